@@ -0,0 +1,37 @@
+package token
+
+import "time"
+
+// Config содержит параметры выпуска и проверки токенов
+type Config struct {
+	Issuer      string        `envconfig:"TOKEN_ISSUER"`                    // Значение iss: подставляется при выпуске и проверяется, если задано
+	Audience    []string      `envconfig:"TOKEN_AUDIENCE"`                  // Значение aud: при проверке достаточно совпадения одного значения
+	TTL         time.Duration `envconfig:"TOKEN_TTL" default:"15m"`         // Время жизни выпускаемых токенов
+	Leeway      time.Duration `envconfig:"TOKEN_LEEWAY" default:"1m"`       // Допустимое расхождение часов при проверке exp, nbf и iat; отрицательное значение отключает допуск
+	JWKSURL     string        `envconfig:"TOKEN_JWKS_URL"`                  // Адрес JWKS внешнего издателя для NewJWKSKeySet
+	JWKSRefresh time.Duration `envconfig:"TOKEN_JWKS_REFRESH" default:"5m"` // Интервал обновления JWKS
+}
+
+// Значения по умолчанию
+const (
+	DefaultTTL         = 15 * time.Minute
+	DefaultLeeway      = time.Minute
+	DefaultJWKSRefresh = 5 * time.Minute
+)
+
+// withDefaults возвращает копию cfg с заполненными значениями по умолчанию
+func withDefaults(cfg Config) Config {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.Leeway == 0 {
+		cfg.Leeway = DefaultLeeway
+	}
+	if cfg.Leeway < 0 {
+		cfg.Leeway = 0
+	}
+	if cfg.JWKSRefresh <= 0 {
+		cfg.JWKSRefresh = DefaultJWKSRefresh
+	}
+	return cfg
+}
@@ -0,0 +1,34 @@
+package token
+
+import (
+	"context"
+	"strings"
+)
+
+// Verifier проверяет токен и возвращает его содержимое; реализуется Service
+type Verifier interface {
+	Verify(ctx context.Context, raw string) (*Claims, error)
+}
+
+type claimsKey struct{}
+
+// ContextWithClaims возвращает контекст с содержимым проверенного токена
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext возвращает содержимое токена, сохранённое Middleware или интерцепторами
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// BearerToken извлекает токен из значения заголовка Authorization вида "Bearer <token>"
+func BearerToken(authorization string) (string, bool) {
+	scheme, raw, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	raw = strings.TrimSpace(raw)
+	return raw, raw != ""
+}
@@ -0,0 +1,39 @@
+// Package token выпускает и проверяет JWT (HS256, RS256, EdDSA).
+//
+// Ключи берутся из [KeySet]: [StaticKeySet] хранит ключи в памяти и поддерживает ротацию,
+// [JWKSKeySet] загружает ключи проверки из JWKS внешнего издателя и кэширует их.
+//
+// Использование:
+//
+//	keys := token.NewStaticKeySet(token.NewEd25519Key("2024-06", privateKey))
+//	svc := token.New(cfg, keys)
+//
+//	raw, err := svc.Issue(ctx, token.Claims{Subject: userID, Extra: map[string]any{"role": "admin"}})
+//	claims, err := svc.Verify(ctx, raw)
+//
+// Ротация ключей:
+//
+//	keys.Rotate(token.NewEd25519Key("2024-07", newPrivateKey)) // новые токены подписываются новым ключом
+//	keys.Remove("2024-06")                                    // после истечения TTL старых токенов
+//
+// Проверка токенов в HTTP и gRPC:
+//
+//	http.Handle("/api/", token.Middleware(svc)(apiHandler))
+//	http.Handle("/.well-known/jwks.json", token.JWKSHandler(keys))
+//
+//	middleware.UnaryAt(middleware.PositionAuth, token.UnaryServerInterceptor(svc))
+//	middleware.StreamAt(middleware.PositionAuth, token.StreamServerInterceptor(svc))
+//
+// Содержимое проверенного токена доступно обработчику через [ClaimsFromContext].
+//
+// Конфигурация через переменные окружения:
+//
+//	TOKEN_ISSUER        — iss выпускаемых токенов; при проверке сверяется, если задан (default: пусто)
+//	TOKEN_AUDIENCE      — aud через запятую; при проверке достаточно одного совпадения (default: пусто)
+//	TOKEN_TTL           — время жизни выпускаемых токенов (default: 15m)
+//	TOKEN_LEEWAY        — допустимое расхождение часов при проверке (default: 1m)
+//	TOKEN_JWKS_URL      — адрес JWKS для [NewJWKSKeySet] (default: пусто)
+//	TOKEN_JWKS_REFRESH  — интервал обновления JWKS (default: 5m)
+//
+// Thread-safe: да.
+package token
@@ -0,0 +1,67 @@
+package token
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor проверяет Bearer токен из метаданных authorization и сохраняет Claims в контексте.
+// Предназначен для слота аутентификации цепочки middleware:
+//
+//	middleware.UnaryAt(middleware.PositionAuth, token.UnaryServerInterceptor(svc))
+func UnaryServerInterceptor(verifier Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		authCtx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+		return handler(authCtx, req)
+	}
+}
+
+// StreamServerInterceptor — потоковый вариант UnaryServerInterceptor
+func StreamServerInterceptor(verifier Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		authCtx, err := authenticate(ss.Context(), verifier)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: authCtx})
+	}
+}
+
+// authenticate проверяет токен из входящих метаданных; ошибки возвращаются как codes.Unauthenticated
+func authenticate(ctx context.Context, verifier Verifier) (context.Context, error) {
+	var (
+		raw string
+		ok  bool
+	)
+	for _, value := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		if raw, ok = BearerToken(value); ok {
+			break
+		}
+	}
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	claims, err := verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return ContextWithClaims(ctx, claims), nil
+}
+
+// authStream подменяет контекст потока
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст с Claims
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
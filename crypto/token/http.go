@@ -0,0 +1,43 @@
+package token
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Middleware проверяет Bearer токен из заголовка Authorization и сохраняет Claims в контексте запроса.
+// Запрос без токена или с непрошедшим проверку токеном получает 401 Unauthorized.
+func Middleware(verifier Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := BearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				unauthorized(w)
+				return
+			}
+			claims, err := verifier.Verify(r.Context(), raw)
+			if err != nil {
+				unauthorized(w)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// unauthorized отвечает 401 с заголовком WWW-Authenticate по RFC 6750
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// JWKSHandler отдаёт публичные ключи набора в формате JWKS, например на /.well-known/jwks.json
+func JWKSHandler(keys *StaticKeySet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(keys.JWKS()); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	})
+}
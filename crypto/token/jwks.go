@@ -0,0 +1,198 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// jwksMinRefreshInterval ограничивает внеочередные загрузки JWKS при неизвестном kid
+	// и повторы первой загрузки, если издатель недоступен
+	jwksMinRefreshInterval = 10 * time.Second
+	// jwksMaxBodySize ограничивает размер ответа JWKS
+	jwksMaxBodySize = 1 << 20
+)
+
+// JWKSKeySet — набор ключей проверки, загружаемый из JWKS внешнего издателя.
+// Ключи кэшируются и обновляются раз в Config.JWKSRefresh; неизвестный kid вызывает
+// внеочередное обновление, чтобы новые ключи издателя подхватывались без ожидания.
+// Если обновление не удалось, используются ранее загруженные ключи.
+// Загрузка выполняется без блокировки набора, одновременные запросы ждут одну загрузку.
+// Подписывать токены набор не может: SigningKey возвращает ErrNoSigningKey.
+type JWKSKeySet struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	loading singleflight.Group
+
+	mu        sync.Mutex
+	keys      []Key
+	fetched   time.Time // время последней успешной загрузки
+	attempted time.Time // время последней попытки загрузки
+	err       error     // ошибка последней попытки загрузки
+}
+
+var _ KeySet = (*JWKSKeySet)(nil)
+
+// NewJWKSKeySet создаёт набор ключей для cfg.JWKSURL; ключи загружаются при первой проверке.
+// Если client равен nil, используется http.Client с таймаутом 10 секунд.
+func NewJWKSKeySet(cfg Config, client *http.Client) *JWKSKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &JWKSKeySet{
+		url:     cfg.JWKSURL,
+		refresh: withDefaults(cfg).JWKSRefresh,
+		client:  client,
+	}
+}
+
+// SigningKey реализует KeySet
+func (s *JWKSKeySet) SigningKey(_ context.Context) (Key, error) {
+	return Key{}, ErrNoSigningKey
+}
+
+// VerificationKeys реализует KeySet
+func (s *JWKSKeySet) VerificationKeys(ctx context.Context, kid string) ([]Key, error) {
+	s.mu.Lock()
+	keys := filterKeys(s.keys, kid)
+	fetched, attempted, lastErr := s.fetched, s.attempted, s.err
+	s.mu.Unlock()
+
+	// Недоступный издатель не должен опрашиваться на каждый запрос: до следующей попытки
+	// возвращается ошибка первой загрузки или остаются прежние ключи
+	retry := time.Since(attempted) >= jwksMinRefreshInterval
+	if fetched.IsZero() {
+		if !retry && lastErr != nil {
+			return nil, lastErr
+		}
+		// Без ошибки недавняя попытка ещё выполняется: fetch дождётся её
+		if err := s.fetch(ctx); err != nil {
+			return nil, err
+		}
+		return s.filter(kid), nil
+	}
+
+	stale := time.Since(fetched) >= s.refresh || (kid != "" && len(keys) == 0)
+	if stale && retry {
+		if err := s.fetch(ctx); err == nil {
+			keys = s.filter(kid)
+		}
+	}
+	return keys, nil
+}
+
+// Refresh принудительно загружает JWKS
+func (s *JWKSKeySet) Refresh(ctx context.Context) error {
+	return s.fetch(ctx)
+}
+
+// filter возвращает загруженные ключи с идентификатором kid
+func (s *JWKSKeySet) filter(kid string) []Key {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filterKeys(s.keys, kid)
+}
+
+// fetch загружает JWKS; одновременные вызовы ждут одну загрузку.
+// Загрузка не отменяется вместе с ctx вызвавшего, чтобы не прерывать её для остальных.
+func (s *JWKSKeySet) fetch(ctx context.Context) error {
+	result := s.loading.DoChan("", func() (any, error) {
+		return nil, s.load(context.WithoutCancel(ctx))
+	})
+	select {
+	case res := <-result:
+		return res.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// load загружает JWKS и заменяет ключи
+func (s *JWKSKeySet) load(ctx context.Context) (err error) {
+	attempted := time.Now()
+	s.mu.Lock()
+	s.attempted = attempted
+	s.mu.Unlock()
+
+	ctx, span := startSpan(ctx, "FetchJWKS")
+	defer func() {
+		recordError(span, err)
+		span.End()
+	}()
+
+	keys, err := s.download(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.fetched = attempted
+	span.SetAttributes(attribute.Int("token.jwks.keys", len(keys)))
+	return nil
+}
+
+// download запрашивает JWKS у издателя
+func (s *JWKSKeySet) download(ctx context.Context) ([]Key, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create jwks request")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch jwks")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxBodySize)).Decode(&set); err != nil {
+		return nil, errors.Wrap(err, "failed to decode jwks")
+	}
+	return keysFromJWKS(set), nil
+}
+
+// keysFromJWKS преобразует JWKS в ключи проверки.
+// Пропускаются ключи шифрования, приватные и симметричные ключи, а также неподдерживаемые алгоритмы.
+func keysFromJWKS(set jose.JSONWebKeySet) []Key {
+	keys := make([]Key, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key Key
+		switch public := jwk.Key.(type) {
+		case *rsa.PublicKey:
+			key = NewRSAPublicKey(jwk.KeyID, public)
+		case ed25519.PublicKey:
+			key = NewEd25519PublicKey(jwk.KeyID, public)
+		default:
+			continue
+		}
+		if jwk.Algorithm != "" && jwk.Algorithm != string(key.Algorithm) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
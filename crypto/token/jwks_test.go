@@ -0,0 +1,179 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer serves the public keys of a StaticKeySet and counts requests.
+type jwksServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	fail     atomic.Bool
+}
+
+func newJWKSServer(t *testing.T, keys *StaticKeySet) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	handler := JWKSHandler(keys)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if s.fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// TestJWKSKeySet tests verification of tokens against keys fetched from a JWKS endpoint.
+func TestJWKSKeySet(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	issuerKeys := NewStaticKeySet(newRSAKey(t, "rsa-1"), NewHMACKey("hmac", []byte("0123456789abcdef0123456789abcdef")))
+	issuer := New(Config{Issuer: "auth"}, issuerKeys)
+	server := newJWKSServer(t, issuerKeys)
+
+	cfg := Config{Issuer: "auth", JWKSURL: server.URL, JWKSRefresh: time.Hour}
+	remote := NewJWKSKeySet(cfg, nil)
+	verifier := New(cfg, remote)
+
+	raw, err := issuer.Issue(ctx, Claims{Subject: "user-1"})
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+
+	// HMAC keys are not published
+	keys, err := remote.VerificationKeys(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "rsa-1", keys[0].ID)
+
+	// cached keys are used until the refresh interval passes
+	_, err = verifier.Verify(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	_, err = remote.SigningKey(ctx)
+	assert.True(t, errors.Is(err, ErrNoSigningKey), err)
+}
+
+// TestJWKSKeySet_UnknownKid tests that an unknown kid triggers a rate-limited refresh.
+func TestJWKSKeySet_UnknownKid(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	issuerKeys := NewStaticKeySet(newEd25519Key(t, "ed-1"))
+	issuer := New(Config{}, issuerKeys)
+	server := newJWKSServer(t, issuerKeys)
+	remote := NewJWKSKeySet(Config{JWKSURL: server.URL, JWKSRefresh: time.Hour}, nil)
+	verifier := New(Config{}, remote)
+
+	require.NoError(t, remote.Refresh(ctx))
+	issuerKeys.Rotate(newEd25519Key(t, "ed-2"))
+	raw, err := issuer.Issue(ctx, Claims{})
+	require.NoError(t, err)
+
+	// the previous attempt is too recent for an out-of-band refresh
+	_, err = verifier.Verify(ctx, raw)
+	assert.True(t, errors.Is(err, ErrUnknownKey), err)
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	remote.mu.Lock()
+	remote.attempted = time.Now().Add(-time.Minute)
+	remote.mu.Unlock()
+
+	_, err = verifier.Verify(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+// TestJWKSKeySet_FetchError tests that cached keys survive a failed refresh.
+func TestJWKSKeySet_FetchError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	issuerKeys := NewStaticKeySet(newEd25519Key(t, "ed-1"))
+	server := newJWKSServer(t, issuerKeys)
+	server.fail.Store(true)
+	remote := NewJWKSKeySet(Config{JWKSURL: server.URL, JWKSRefresh: time.Hour}, nil)
+
+	_, err := remote.VerificationKeys(ctx, "ed-1")
+	assert.ErrorContains(t, err, "unexpected status 503")
+
+	// the first fetch is retried no more often than jwksMinRefreshInterval
+	server.fail.Store(false)
+	_, err = remote.VerificationKeys(ctx, "ed-1")
+	assert.ErrorContains(t, err, "unexpected status 503")
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	remote.mu.Lock()
+	remote.attempted = time.Now().Add(-time.Minute)
+	remote.mu.Unlock()
+
+	keys, err := remote.VerificationKeys(ctx, "ed-1")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	server.fail.Store(true)
+	remote.mu.Lock()
+	remote.fetched = time.Now().Add(-2 * time.Hour)
+	remote.attempted = remote.fetched
+	remote.mu.Unlock()
+
+	keys, err = remote.VerificationKeys(ctx, "ed-1")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, int32(3), server.requests.Load())
+}
+
+// TestJWKSKeySet_ConcurrentFetch tests that concurrent verifications share one fetch.
+func TestJWKSKeySet_ConcurrentFetch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	issuerKeys := NewStaticKeySet(newEd25519Key(t, "ed-1"))
+	release := make(chan struct{})
+	var requests atomic.Int32
+	handler := JWKSHandler(issuerKeys)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	remote := NewJWKSKeySet(Config{JWKSURL: server.URL, JWKSRefresh: time.Hour}, nil)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			keys, err := remote.VerificationKeys(ctx, "ed-1")
+			assert.NoError(t, err)
+			assert.Len(t, keys, 1)
+		})
+	}
+
+	// the set is not locked while the fetch is in flight
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+	remote.mu.Lock()
+	assert.True(t, remote.fetched.IsZero())
+	remote.mu.Unlock()
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,179 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"slices"
+	"sync"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// Algorithm — алгоритм подписи JWT
+type Algorithm string
+
+// Поддерживаемые алгоритмы подписи
+const (
+	HS256 Algorithm = "HS256" // HMAC SHA-256, общий секрет
+	RS256 Algorithm = "RS256" // RSA PKCS#1 v1.5 SHA-256
+	EdDSA Algorithm = "EdDSA" // Ed25519
+)
+
+// Key — ключ подписи или проверки с идентификатором kid.
+// Алгоритм закреплён за ключом: токен с другим alg в заголовке этим ключом не проверяется.
+type Key struct {
+	ID        string    // Идентификатор ключа (kid)
+	Algorithm Algorithm // Алгоритм подписи
+	// Private — ключ подписи: []byte для HS256, *rsa.PrivateKey для RS256, ed25519.PrivateKey для EdDSA.
+	// nil для ключей, предназначенных только для проверки.
+	Private any
+	// Public — ключ проверки: []byte для HS256, *rsa.PublicKey для RS256, ed25519.PublicKey для EdDSA
+	Public any
+}
+
+// NewHMACKey создаёт ключ HS256 с общим секретом
+func NewHMACKey(id string, secret []byte) Key {
+	return Key{ID: id, Algorithm: HS256, Private: secret, Public: secret}
+}
+
+// NewRSAKey создаёт ключ RS256 для подписи и проверки
+func NewRSAKey(id string, key *rsa.PrivateKey) Key {
+	return Key{ID: id, Algorithm: RS256, Private: key, Public: &key.PublicKey}
+}
+
+// NewRSAPublicKey создаёт ключ RS256 только для проверки
+func NewRSAPublicKey(id string, key *rsa.PublicKey) Key {
+	return Key{ID: id, Algorithm: RS256, Public: key}
+}
+
+// NewEd25519Key создаёт ключ EdDSA для подписи и проверки
+func NewEd25519Key(id string, key ed25519.PrivateKey) Key {
+	public, _ := key.Public().(ed25519.PublicKey)
+	return Key{ID: id, Algorithm: EdDSA, Private: key, Public: public}
+}
+
+// NewEd25519PublicKey создаёт ключ EdDSA только для проверки
+func NewEd25519PublicKey(id string, key ed25519.PublicKey) Key {
+	return Key{ID: id, Algorithm: EdDSA, Public: key}
+}
+
+// CanSign сообщает, может ли ключ подписывать токены
+func (k Key) CanSign() bool {
+	return k.Private != nil
+}
+
+// KeySet — источник ключей подписи и проверки.
+// Реализации должны быть безопасны для конкурентного использования.
+type KeySet interface {
+	// SigningKey возвращает активный ключ подписи или ErrNoSigningKey
+	SigningKey(ctx context.Context) (Key, error)
+	// VerificationKeys возвращает ключи проверки с идентификатором kid; пустой kid — все ключи
+	VerificationKeys(ctx context.Context, kid string) ([]Key, error)
+}
+
+// StaticKeySet — набор ключей в памяти с поддержкой ротации.
+// При ротации прежний ключ подписи остаётся ключом проверки, пока не удалён через Remove,
+// поэтому выпущенные им токены действуют до истечения срока.
+type StaticKeySet struct {
+	mu      sync.RWMutex
+	signing string // kid активного ключа подписи; пусто, если подписывать нечем
+	keys    []Key
+}
+
+var _ KeySet = (*StaticKeySet)(nil)
+
+// NewStaticKeySet создаёт набор с активным ключом подписи signing и дополнительными ключами проверки.
+// Для набора только для проверки передайте нулевой Key в качестве signing.
+func NewStaticKeySet(signing Key, verification ...Key) *StaticKeySet {
+	s := &StaticKeySet{}
+	for _, key := range verification {
+		s.add(key)
+	}
+	if signing.Public != nil || signing.Private != nil {
+		s.Rotate(signing)
+	}
+	return s
+}
+
+// Rotate делает key активным ключом подписи; предыдущие ключи остаются для проверки
+func (s *StaticKeySet) Rotate(key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(key)
+	if key.CanSign() {
+		s.signing = key.ID
+	}
+}
+
+// Remove удаляет ключ с идентификатором kid; токены, подписанные им, перестают проходить проверку
+func (s *StaticKeySet) Remove(kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = slices.DeleteFunc(s.keys, func(k Key) bool { return k.ID == kid })
+	if s.signing == kid {
+		s.signing = ""
+	}
+}
+
+// SigningKey реализует KeySet
+func (s *StaticKeySet) SigningKey(_ context.Context) (Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.signing != "" {
+		for _, key := range s.keys {
+			if key.ID == s.signing {
+				return key, nil
+			}
+		}
+	}
+	return Key{}, ErrNoSigningKey
+}
+
+// VerificationKeys реализует KeySet
+func (s *StaticKeySet) VerificationKeys(_ context.Context, kid string) ([]Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterKeys(s.keys, kid), nil
+}
+
+// JWKS возвращает публичные ключи набора в формате JWKS; ключи HS256 не публикуются
+func (s *StaticKeySet) JWKS() jose.JSONWebKeySet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	for _, key := range s.keys {
+		if key.Algorithm == HS256 {
+			continue
+		}
+		set.Keys = append(set.Keys, jose.JSONWebKey{
+			Key:       key.Public,
+			KeyID:     key.ID,
+			Algorithm: string(key.Algorithm),
+			Use:       "sig",
+		})
+	}
+	return set
+}
+
+// add заменяет ключ с тем же kid или добавляет новый; вызывается под s.mu
+func (s *StaticKeySet) add(key Key) {
+	for i := range s.keys {
+		if s.keys[i].ID == key.ID {
+			s.keys[i] = key
+			return
+		}
+	}
+	s.keys = append(s.keys, key)
+}
+
+// filterKeys возвращает копию ключей с идентификатором kid; пустой kid — все ключи
+func filterKeys(keys []Key, kid string) []Key {
+	result := make([]Key, 0, len(keys))
+	for _, key := range keys {
+		if kid == "" || key.ID == kid {
+			result = append(result, key)
+		}
+	}
+	return result
+}
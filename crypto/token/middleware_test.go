@@ -0,0 +1,147 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	return New(Config{Issuer: "auth"}, NewStaticKeySet(newEd25519Key(t, "ed")))
+}
+
+// TestBearerToken tests parsing of the Authorization header.
+func TestBearerToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"Bearer abc", "abc", true},
+		{"bearer  abc ", "abc", true},
+		{"Basic abc", "", false},
+		{"Bearer", "", false},
+		{"Bearer ", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := BearerToken(tt.header)
+		assert.Equal(t, tt.want, got, tt.header)
+		assert.Equal(t, tt.ok, ok, tt.header)
+	}
+}
+
+// TestMiddleware tests that the HTTP middleware rejects requests without a valid token.
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	svc := newTestService(t)
+	raw, err := svc.Issue(context.Background(), Claims{Subject: "user-1"})
+	require.NoError(t, err)
+
+	handler := Middleware(svc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFromContext(r.Context())
+		require.True(t, ok)
+		_, _ = w.Write([]byte(claims.Subject))
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{"valid token", "Bearer " + raw, http.StatusOK, "user-1"},
+		{"missing token", "", http.StatusUnauthorized, ""},
+		{"invalid token", "Bearer " + raw + "x", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			} else {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+// TestUnaryServerInterceptor tests that the gRPC interceptor authenticates incoming metadata.
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+	svc := newTestService(t)
+	raw, err := svc.Issue(context.Background(), Claims{Subject: "user-1"})
+	require.NoError(t, err)
+
+	interceptor := UnaryServerInterceptor(svc)
+	handler := func(ctx context.Context, _ any) (any, error) {
+		claims, ok := ClaimsFromContext(ctx)
+		require.True(t, ok)
+		return claims.Subject, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+raw))
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", resp)
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer invalid"))
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// fakeServerStream is a grpc.ServerStream with a fixed context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// TestStreamServerInterceptor tests that the stream handler receives a context with claims.
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+	svc := newTestService(t)
+	raw, err := svc.Issue(context.Background(), Claims{Subject: "user-1"})
+	require.NoError(t, err)
+
+	interceptor := StreamServerInterceptor(svc)
+	var subject string
+	handler := func(_ any, ss grpc.ServerStream) error {
+		claims, ok := ClaimsFromContext(ss.Context())
+		require.True(t, ok)
+		subject = claims.Subject
+		return nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+raw))
+	require.NoError(t, interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler))
+	assert.Equal(t, "user-1", subject)
+
+	err = interceptor(nil, &fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,248 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// ErrInvalidToken возвращается для некорректного токена: формат, подпись, iss, aud, nbf
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken возвращается для токена с истёкшим сроком действия
+	ErrExpiredToken = errors.New("token expired")
+	// ErrUnknownKey возвращается, если в наборе нет ключа с kid и alg из заголовка токена
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrNoSigningKey возвращается набором ключей, которым нельзя подписывать токены
+	ErrNoSigningKey = errors.New("no signing key")
+)
+
+// supportedAlgorithms — алгоритмы, допустимые в заголовке проверяемого токена
+var supportedAlgorithms = []jose.SignatureAlgorithm{jose.HS256, jose.RS256, jose.EdDSA}
+
+// registeredClaims — имена зарегистрированных claims, которые не попадают в Claims.Extra
+var registeredClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// Claims — содержимое токена
+type Claims struct {
+	Issuer    string    // iss
+	Subject   string    // sub
+	Audience  []string  // aud
+	ID        string    // jti
+	IssuedAt  time.Time // iat
+	NotBefore time.Time // nbf; нулевое значение — не задан
+	ExpiresAt time.Time // exp
+	// Extra — дополнительные claims (роли, tenant и т.п.); зарегистрированные имена игнорируются
+	Extra map[string]any
+}
+
+// Service выпускает и проверяет JWT с ключами из KeySet
+type Service struct {
+	cfg  Config
+	keys KeySet
+	now  func() time.Time
+}
+
+// New создаёт Service. Для проверки токенов внешнего издателя передайте NewJWKSKeySet.
+func New(cfg Config, keys KeySet) *Service {
+	return &Service{
+		cfg:  withDefaults(cfg),
+		keys: keys,
+		now:  time.Now,
+	}
+}
+
+// Issue подписывает токен активным ключом набора.
+// Незаполненные iss и aud берутся из Config, iat — текущее время, exp — iat + TTL,
+// jti генерируется случайно.
+func (s *Service) Issue(ctx context.Context, claims Claims) (raw string, err error) {
+	ctx, span := startSpan(ctx, "Issue")
+	defer func() {
+		recordError(span, err)
+		span.End()
+	}()
+
+	key, err := s.keys.SigningKey(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signing key")
+	}
+	span.SetAttributes(
+		attribute.String("token.kid", key.ID),
+		attribute.String("token.alg", string(key.Algorithm)),
+	)
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(key.Algorithm), Key: key.Private},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", key.ID),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create signer for key %s", key.ID)
+	}
+
+	registered, err := s.registered(claims)
+	if err != nil {
+		return "", err
+	}
+	raw, err = jwt.Signed(signer).Claims(extraClaims(claims.Extra)).Claims(registered).Serialize()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign token")
+	}
+	return raw, nil
+}
+
+// Verify проверяет подпись и срок действия токена, а также iss и aud, если они заданы в Config.
+// Ошибки оборачивают ErrInvalidToken, ErrExpiredToken или ErrUnknownKey и проверяются через errors.Is.
+func (s *Service) Verify(ctx context.Context, raw string) (claims *Claims, err error) {
+	ctx, span := startSpan(ctx, "Verify")
+	defer func() {
+		recordError(span, err)
+		span.End()
+	}()
+
+	tok, err := jwt.ParseSigned(raw, supportedAlgorithms)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+	header := tok.Headers[0]
+	span.SetAttributes(
+		attribute.String("token.kid", header.KeyID),
+		attribute.String("token.alg", header.Algorithm),
+	)
+
+	keys, err := s.keys.VerificationKeys(ctx, header.KeyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get verification keys")
+	}
+
+	var (
+		registered jwt.Claims
+		all        map[string]any
+		found      bool
+	)
+	for _, key := range keys {
+		if string(key.Algorithm) != header.Algorithm {
+			continue
+		}
+		found = true
+		if err = tok.Claims(key.Public, &registered, &all); err == nil {
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Wrapf(ErrUnknownKey, "kid %q, alg %s", header.KeyID, header.Algorithm)
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	if err := s.validate(registered); err != nil {
+		return nil, err
+	}
+	return toClaims(registered, all), nil
+}
+
+// registered заполняет зарегистрированные claims значениями по умолчанию
+func (s *Service) registered(claims Claims) (jwt.Claims, error) {
+	issuedAt := claims.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = s.now()
+	}
+	expiresAt := claims.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = issuedAt.Add(s.cfg.TTL)
+	}
+
+	result := jwt.Claims{
+		Issuer:   claims.Issuer,
+		Subject:  claims.Subject,
+		Audience: claims.Audience,
+		ID:       claims.ID,
+		IssuedAt: jwt.NewNumericDate(issuedAt),
+		Expiry:   jwt.NewNumericDate(expiresAt),
+	}
+	if result.Issuer == "" {
+		result.Issuer = s.cfg.Issuer
+	}
+	if len(result.Audience) == 0 {
+		result.Audience = s.cfg.Audience
+	}
+	if !claims.NotBefore.IsZero() {
+		result.NotBefore = jwt.NewNumericDate(claims.NotBefore)
+	}
+	if result.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return result, err
+		}
+		result.ID = id
+	}
+	return result, nil
+}
+
+// validate проверяет exp, nbf, iat, iss и aud с учётом Config.Leeway
+func (s *Service) validate(registered jwt.Claims) error {
+	if registered.Expiry == nil {
+		return errors.Wrap(ErrInvalidToken, "missing exp claim")
+	}
+	err := registered.ValidateWithLeeway(jwt.Expected{
+		Issuer:      s.cfg.Issuer,
+		AnyAudience: s.cfg.Audience,
+		Time:        s.now(),
+	}, s.cfg.Leeway)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, jwt.ErrExpired):
+		return ErrExpiredToken
+	default:
+		return errors.Wrap(ErrInvalidToken, err.Error())
+	}
+}
+
+// extraClaims возвращает дополнительные claims без зарегистрированных имён
+func extraClaims(extra map[string]any) map[string]any {
+	result := make(map[string]any, len(extra))
+	for name, value := range extra {
+		result[name] = value
+	}
+	for _, name := range registeredClaims {
+		delete(result, name)
+	}
+	return result
+}
+
+// toClaims собирает Claims из зарегистрированных и всех claims токена
+func toClaims(registered jwt.Claims, all map[string]any) *Claims {
+	claims := &Claims{
+		Issuer:   registered.Issuer,
+		Subject:  registered.Subject,
+		Audience: registered.Audience,
+		ID:       registered.ID,
+		Extra:    extraClaims(all),
+	}
+	if registered.IssuedAt != nil {
+		claims.IssuedAt = registered.IssuedAt.Time()
+	}
+	if registered.NotBefore != nil {
+		claims.NotBefore = registered.NotBefore.Time()
+	}
+	if registered.Expiry != nil {
+		claims.ExpiresAt = registered.Expiry.Time()
+	}
+	return claims
+}
+
+// newID генерирует случайный jti
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate token id")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,177 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRSAKey(t *testing.T, id string) Key {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return NewRSAKey(id, key)
+}
+
+func newEd25519Key(t *testing.T, id string) Key {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return NewEd25519Key(id, key)
+}
+
+// TestService_IssueVerify tests a round trip for every supported algorithm.
+func TestService_IssueVerify(t *testing.T) {
+	t.Parallel()
+
+	keys := map[string]Key{
+		"HS256": NewHMACKey("hmac", []byte("0123456789abcdef0123456789abcdef")),
+		"RS256": newRSAKey(t, "rsa"),
+		"EdDSA": newEd25519Key(t, "ed"),
+	}
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			svc := New(Config{Issuer: "auth", Audience: []string{"api"}, TTL: time.Minute}, NewStaticKeySet(key))
+
+			raw, err := svc.Issue(context.Background(), Claims{
+				Subject: "user-1",
+				Extra:   map[string]any{"role": "admin", "sub": "ignored"},
+			})
+			require.NoError(t, err)
+
+			claims, err := svc.Verify(context.Background(), raw)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.Subject)
+			assert.Equal(t, "auth", claims.Issuer)
+			assert.Equal(t, []string{"api"}, claims.Audience)
+			assert.NotEmpty(t, claims.ID)
+			assert.WithinDuration(t, claims.IssuedAt.Add(time.Minute), claims.ExpiresAt, time.Second)
+			assert.Equal(t, map[string]any{"role": "admin"}, claims.Extra)
+		})
+	}
+}
+
+// TestService_Verify_Errors tests that invalid tokens are rejected with the matching error.
+func TestService_Verify_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	key := NewHMACKey("k1", []byte("0123456789abcdef0123456789abcdef"))
+	issuer := New(Config{Issuer: "auth", Audience: []string{"api"}}, NewStaticKeySet(key))
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Parallel()
+		_, err := issuer.Verify(ctx, "not-a-token")
+		assert.True(t, errors.Is(err, ErrInvalidToken), err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+		raw, err := issuer.Issue(ctx, Claims{IssuedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(-time.Minute)})
+		require.NoError(t, err)
+		_, err = New(Config{Issuer: "auth"}, NewStaticKeySet(key)).Verify(ctx, raw)
+		assert.True(t, errors.Is(err, ErrExpiredToken), err)
+	})
+
+	t.Run("leeway", func(t *testing.T) {
+		t.Parallel()
+		raw, err := issuer.Issue(ctx, Claims{IssuedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(-time.Second)})
+		require.NoError(t, err)
+		_, err = New(Config{Leeway: time.Minute}, NewStaticKeySet(key)).Verify(ctx, raw)
+		assert.NoError(t, err)
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		t.Parallel()
+		raw, err := issuer.Issue(ctx, Claims{})
+		require.NoError(t, err)
+		_, err = New(Config{Issuer: "other"}, NewStaticKeySet(key)).Verify(ctx, raw)
+		assert.True(t, errors.Is(err, ErrInvalidToken), err)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		t.Parallel()
+		raw, err := issuer.Issue(ctx, Claims{})
+		require.NoError(t, err)
+		_, err = New(Config{Audience: []string{"billing"}}, NewStaticKeySet(key)).Verify(ctx, raw)
+		assert.True(t, errors.Is(err, ErrInvalidToken), err)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		t.Parallel()
+		raw, err := issuer.Issue(ctx, Claims{})
+		require.NoError(t, err)
+		other := NewHMACKey("k1", []byte("fedcba9876543210fedcba9876543210"))
+		_, err = New(Config{}, NewStaticKeySet(other)).Verify(ctx, raw)
+		assert.True(t, errors.Is(err, ErrInvalidToken), err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		t.Parallel()
+		raw, err := issuer.Issue(ctx, Claims{})
+		require.NoError(t, err)
+		other := NewHMACKey("k2", []byte("0123456789abcdef0123456789abcdef"))
+		_, err = New(Config{}, NewStaticKeySet(other)).Verify(ctx, raw)
+		assert.True(t, errors.Is(err, ErrUnknownKey), err)
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		t.Parallel()
+		// HS256 token signed with the kid of an RSA key must not be verified by that key
+		rsaKey := newRSAKey(t, "k1")
+		raw, err := issuer.Issue(ctx, Claims{})
+		require.NoError(t, err)
+		_, err = New(Config{}, NewStaticKeySet(Key{}, NewRSAPublicKey("k1", rsaKey.Public.(*rsa.PublicKey)))).Verify(ctx, raw)
+		assert.True(t, errors.Is(err, ErrUnknownKey), err)
+	})
+}
+
+// TestService_Issue_NoSigningKey tests that a verification-only key set cannot issue tokens.
+func TestService_Issue_NoSigningKey(t *testing.T) {
+	t.Parallel()
+	key := newEd25519Key(t, "ed")
+	svc := New(Config{}, NewStaticKeySet(Key{}, NewEd25519PublicKey("ed", key.Public.(ed25519.PublicKey))))
+
+	_, err := svc.Issue(context.Background(), Claims{})
+	assert.True(t, errors.Is(err, ErrNoSigningKey), err)
+}
+
+// TestStaticKeySet_Rotate tests that tokens signed with a rotated key stay valid until the key is removed.
+func TestStaticKeySet_Rotate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	keys := NewStaticKeySet(newEd25519Key(t, "old"))
+	svc := New(Config{}, keys)
+
+	oldToken, err := svc.Issue(ctx, Claims{Subject: "a"})
+	require.NoError(t, err)
+
+	keys.Rotate(newRSAKey(t, "new"))
+	signing, err := keys.SigningKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "new", signing.ID)
+
+	newToken, err := svc.Issue(ctx, Claims{Subject: "b"})
+	require.NoError(t, err)
+
+	_, err = svc.Verify(ctx, oldToken)
+	require.NoError(t, err)
+	_, err = svc.Verify(ctx, newToken)
+	require.NoError(t, err)
+
+	keys.Remove("old")
+	_, err = svc.Verify(ctx, oldToken)
+	assert.True(t, errors.Is(err, ErrUnknownKey), err)
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "new", jwks.Keys[0].KeyID)
+	assert.True(t, jwks.Keys[0].IsPublic())
+}
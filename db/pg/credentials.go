@@ -0,0 +1,110 @@
+package pg
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// SQLSTATE ошибок аутентификации
+const (
+	invalidAuthorizationSpecification = "28000"
+	invalidPassword                   = "28P01"
+)
+
+// Credentials — учётные данные подключения к PostgreSQL.
+// Пустой User означает пользователя из конфигурации адаптера.
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// CredentialsProvider возвращает актуальные учётные данные (например, выданные Vault).
+// Адаптеры запрашивают их при создании соединений и повторно — после ошибки аутентификации.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc адаптирует функцию к CredentialsProvider
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials вызывает f
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// IsAuthErrorCode сообщает, что SQLSTATE code — ошибка аутентификации
+// (неверный пароль или недопустимый пользователь)
+func IsAuthErrorCode(code string) bool {
+	return code == invalidPassword || code == invalidAuthorizationSpecification
+}
+
+// CredentialsCache кэширует учётные данные провайдера между подключениями.
+// Используется адаптерами db/pg/pgx и db/pg/sqlx.
+type CredentialsCache struct {
+	provider    CredentialsProvider
+	defaultUser string
+
+	mu     sync.Mutex
+	creds  Credentials
+	loaded bool
+}
+
+// NewCredentialsCache создаёт кэш поверх provider; defaultUser подставляется,
+// если провайдер не вернул пользователя
+func NewCredentialsCache(provider CredentialsProvider, defaultUser string) *CredentialsCache {
+	return &CredentialsCache{provider: provider, defaultUser: defaultUser}
+}
+
+// Get возвращает закэшированные учётные данные; при первом вызове запрашивает провайдер
+func (c *CredentialsCache) Get(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded {
+		return c.creds, nil
+	}
+	if err := c.load(ctx); err != nil {
+		return Credentials{}, err
+	}
+	return c.creds, nil
+}
+
+// Current сообщает, совпадают ли creds с закэшированными.
+// Соединения со старыми учётными данными закрываются после освобождения.
+func (c *CredentialsCache) Current(creds Credentials) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.loaded || c.creds == creds
+}
+
+// Refresh запрашивает учётные данные заново после ошибки аутентификации с failed.
+// Если кэш уже обновлён другим соединением, провайдер не вызывается.
+// Возвращает true, если учётные данные изменились.
+func (c *CredentialsCache) Refresh(ctx context.Context, failed Credentials) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded && c.creds != failed {
+		return true, nil
+	}
+	if err := c.load(ctx); err != nil {
+		return false, err
+	}
+	return c.creds != failed, nil
+}
+
+// load запрашивает провайдер; вызывается под c.mu
+func (c *CredentialsCache) load(ctx context.Context) error {
+	creds, err := c.provider.Credentials(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get database credentials")
+	}
+	if creds.User == "" {
+		creds.User = c.defaultUser
+	}
+	c.creds = creds
+	c.loaded = true
+	return nil
+}
@@ -0,0 +1,66 @@
+package pg
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var calls atomic.Int32
+	password := "p1"
+	cache := NewCredentialsCache(CredentialsFunc(func(context.Context) (Credentials, error) {
+		calls.Add(1)
+		return Credentials{Password: password}, nil
+	}), "app")
+
+	creds, err := cache.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{User: "app", Password: "p1"}, creds)
+	_, err = cache.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.True(t, cache.Current(creds))
+
+	password = "p2"
+	changed, err := cache.Refresh(ctx, creds)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.False(t, cache.Current(creds))
+
+	// a second failure with the old credentials does not call the provider again
+	changed, err = cache.Refresh(ctx, creds)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, int32(2), calls.Load())
+
+	creds, err = cache.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "p2", creds.Password)
+	assert.True(t, cache.Current(creds))
+}
+
+func TestCredentialsCache_Error(t *testing.T) {
+	t.Parallel()
+	cache := NewCredentialsCache(CredentialsFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, errors.New("vault sealed")
+	}), "app")
+
+	_, err := cache.Get(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "vault sealed")
+	assert.True(t, cache.Current(Credentials{User: "app", Password: "any"}))
+}
+
+func TestIsAuthErrorCode(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsAuthErrorCode("28P01"))
+	assert.True(t, IsAuthErrorCode("28000"))
+	assert.False(t, IsAuthErrorCode("23505"))
+}
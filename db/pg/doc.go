@@ -4,10 +4,50 @@
 //   - db/pg/pgx  — нативный pgx драйвер (рекомендуется)
 //   - db/pg/sqlx — sqlx поверх database/sql
 //
+// Для интеграционных тестов: db/pg/pgtest — PostgreSQL в testcontainers
+// с клонированием шаблонной базы для параллельных тестов.
+//
+// Обобщённые CRUD хелперы поверх db/pg/sqlx: db/repo.
+//
 // Обе реализации поддерживают:
 //   - OpenTelemetry tracing
 //   - структурированное логирование через slog
 //   - именованные запросы и транзакции
+//   - повторные попытки подключения (RetryAttempts/RetryBackoff, общий [Retry]) и режим LazyConnect
+//   - параметры сессии: Config.SessionSettings (statement_timeout, application_name, timezone)
+//     устанавливаются на каждом новом соединении пула запросом [SessionSettingsQuery]
+//   - сменяемые учётные данные: Config.Credentials ([CredentialsProvider], например Vault)
+//     запрашиваются заново после ошибки аутентификации, соединения со старым паролем
+//     закрываются после завершения запросов — смена пароля не требует перезапуска
+//   - метрики запросов по отпечаткам: Config.QueryMetrics ([QueryMetrics]) записывает
+//     db.pg.queries_total и db.pg.query_duration_ms с атрибутом db.query.fingerprint —
+//     нормализованным текстом запроса без литералов ([Fingerprint]); число отпечатков
+//     ограничено MaxFingerprints, остальные записываются как "other"
+//   - комментарии sqlcommenter: Config.SQLCommenter ([SQLCommenter]) добавляет к запросам
+//     /*traceparent='…',route='…'*/ из спана OpenTelemetry и тегов контекста ([ContextWithCommentTags]),
+//     чтобы сопоставить pg_stat_activity и журнал медленных запросов с трейсами приложения
+//   - разбор SQL-скриптов: [SplitScript] делит скрипт на инструкции по ";" вне строк,
+//     комментариев и dollar-quoted тел; [ScriptError] указывает инструкцию и позицию ошибки в скрипте;
+//     [Words] — слова запроса вне литералов и комментариев (DevReadOnlyGuard в db/pg/sqlx)
+//   - экранирование динамических имён: [QuoteIdentifier] и [QuoteLiteral] для текста запроса,
+//     [ValidateIdentifier] — строгая проверка имени, [SafeTableName] — проверенное имя таблицы
+//     со схемой (схемы арендаторов, партиции через SafeTableName.Partition); используются db/repo,
+//     Keyset в db/pg/sqlx, queue/inbox и storage/audit
+//
+// Типы для колонок, которые database/sql не поддерживает напрямую
+// (реализуют sql.Scanner и driver.Valuer, работают с обоими адаптерами):
+//   - [JSONB] — json/jsonb колонка в виде произвольной структуры или map
+//   - [TextArray] — text[]
+//   - [UUIDArray] — uuid[]
+//   - [Vector] — vector (pgvector)
+//
+// Пример:
+//
+//	var settings pg.JSONB[map[string]any]
+//	var tags pg.TextArray
+//	err := db.QueryRowContext(ctx, "SELECT settings, tags FROM users WHERE id = $1", id).Scan(&settings, &tags)
+//
+//	_, err = db.ExecContext(ctx, "UPDATE items SET embedding = $1 WHERE id = $2", pg.Vector(embedding), id)
 //
 // Использование (pgx):
 //
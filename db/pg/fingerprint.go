@@ -0,0 +1,373 @@
+package pg
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Заменители в отпечатке запроса
+const (
+	fingerprintPlaceholder = "?"   // литерал или параметр
+	fingerprintList        = "..." // свёрнутый список литералов
+)
+
+// Fingerprint возвращает нормализованный отпечаток запроса: запросы, различающиеся только
+// значениями, получают одинаковый отпечаток.
+//   - строковые, числовые и dollar-quoted литералы и параметры ($1, :name) заменяются на "?"
+//   - списки IN (?, ?, ...) и ARRAY[?, ...] сворачиваются в "(...)" и "[...]"
+//   - повторяющиеся кортежи VALUES (?, ?), (?, ?) сворачиваются в один
+//   - комментарии удаляются, пробелы схлопываются, ключевые слова и идентификаторы
+//     без кавычек приводятся к нижнему регистру
+func Fingerprint(query string) string {
+	tokens := collapseLists(tokenize(query))
+	return render(tokens)
+}
+
+// Words возвращает ключевые слова и идентификаторы без кавычек из query в нижнем регистре.
+// Строковые литералы (включая E'...' с \'), идентификаторы в кавычках, dollar-quoted тела,
+// параметры и комментарии (включая вложенные /* */) пропускаются — используется тот же лексер, что и в [Fingerprint].
+func Words(query string) []string {
+	var words []string
+	for _, tok := range tokenize(query) {
+		if isIdentStart(tok[0]) {
+			words = append(words, tok)
+		}
+	}
+	return words
+}
+
+// tokenize разбивает запрос на токены, заменяя литералы и параметры на fingerprintPlaceholder
+func tokenize(query string) []string {
+	var tokens []string
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			i, _ = skipBlockComment(query, i)
+		case c == '\'':
+			// Префикс вплотную к кавычке (E'...', B'...', X'...', N'...') — часть литерала
+			escapes := false
+			if i > 0 && isStringPrefix(query[i-1]) && (i == 1 || !isIdentPart(query[i-2])) {
+				escapes = query[i-1] == 'e' || query[i-1] == 'E'
+				tokens = tokens[:len(tokens)-1]
+			}
+			i, _ = skipString(query, i, escapes)
+			tokens = append(tokens, fingerprintPlaceholder)
+		case c == '"':
+			end := i + 1
+			for end < len(query) {
+				if query[end] == '"' {
+					if end+1 < len(query) && query[end+1] == '"' {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end+1, len(query))
+			tokens = append(tokens, query[i:end])
+			i = end
+		case c == '$':
+			if end, ok, _ := dollarQuoteEnd(query, i); ok {
+				tokens = append(tokens, fingerprintPlaceholder)
+				i = end
+				continue
+			}
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			if end == i+1 {
+				tokens = append(tokens, "$")
+			} else {
+				tokens = append(tokens, fingerprintPlaceholder)
+			}
+			i = end
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			tokens = append(tokens, "::")
+			i += 2
+		case c == ':' && i+1 < len(query) && isIdentStart(query[i+1]):
+			// Именованный параметр sqlx (:name)
+			end := i + 1
+			for end < len(query) && isIdentPart(query[end]) {
+				end++
+			}
+			tokens = append(tokens, fingerprintPlaceholder)
+			i = end
+		case isDigit(c) || (c == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			i = skipNumber(query, i)
+			tokens = append(tokens, fingerprintPlaceholder)
+		case isIdentStart(c):
+			end := i
+			for end < len(query) && isIdentPart(query[end]) {
+				end++
+			}
+			tokens = append(tokens, strings.ToLower(query[i:end]))
+			i = end
+		default:
+			// Многосимвольные операторы (<=, <>, !=, ||, ->>) сохраняются одним токеном
+			end := i + 1
+			for end < len(query) && isOperator(c) && isOperator(query[end]) &&
+				!strings.HasPrefix(query[end:], "--") && !strings.HasPrefix(query[end:], "/*") {
+				end++
+			}
+			tokens = append(tokens, query[i:end])
+			i = end
+		}
+	}
+	return tokens
+}
+
+// collapseLists сворачивает списки литералов IN (...), ARRAY[...] и повторяющиеся кортежи VALUES
+func collapseLists(tokens []string) []string {
+	result := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case (tok == "(" || tok == "[") && len(result) > 0 && (result[len(result)-1] == "in" || result[len(result)-1] == "array"):
+			closing := ")"
+			if tok == "[" {
+				closing = "]"
+			}
+			if end, ok := placeholderList(tokens, i+1, closing); ok {
+				result = append(result, tok, fingerprintList, closing)
+				i = end
+				continue
+			}
+		case tok == "(" && len(result) > 0 && result[len(result)-1] == "values":
+			end, ok := groupEnd(tokens, i)
+			if !ok {
+				break
+			}
+			group := tokens[i : end+1]
+			result = append(result, group...)
+			// Пропускаем следующие кортежи той же формы
+			for end+1 < len(tokens) && tokens[end+1] == "," {
+				next, ok := groupEnd(tokens, end+2)
+				if !ok || !equalTokens(tokens[end+2:next+1], group) {
+					break
+				}
+				end = next
+			}
+			i = end
+			continue
+		}
+		result = append(result, tok)
+	}
+	return result
+}
+
+// placeholderList проверяет, что с позиции start идёт список "?, ?, ..." до closing,
+// и возвращает позицию closing
+func placeholderList(tokens []string, start int, closing string) (int, bool) {
+	expectValue := true
+	for i := start; i < len(tokens); i++ {
+		switch {
+		case expectValue && tokens[i] == fingerprintPlaceholder:
+			expectValue = false
+		case !expectValue && tokens[i] == ",":
+			expectValue = true
+		case !expectValue && tokens[i] == closing:
+			return i, true
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// groupEnd возвращает позицию скобки, закрывающей "(" на позиции start
+func groupEnd(tokens []string, start int) (int, bool) {
+	if start >= len(tokens) || tokens[start] != "(" {
+		return 0, false
+	}
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func equalTokens(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// render собирает токены в строку: без пробелов внутри скобок, перед запятыми, вокруг "." и "::"
+func render(tokens []string) string {
+	var b strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && needsSpace(tokens[i-1], tok) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tok)
+	}
+	return b.String()
+}
+
+func needsSpace(prev, tok string) bool {
+	switch prev {
+	case "(", "[", ".", "::":
+		return false
+	}
+	switch tok {
+	case ")", "]", ",", ".", "::", ";":
+		return false
+	case "(", "[":
+		// Вызов функции или индекс: count(*), arr[1]
+		return !isWord(prev)
+	}
+	return true
+}
+
+// isWord сообщает, что токен — идентификатор или ключевое слово, после которого "(" означает вызов.
+// Ключевые слова, после которых идёт подзапрос или список, отделяются пробелом.
+func isWord(tok string) bool {
+	switch tok {
+	case "in", "values", "and", "or", "not", "exists", "any", "all", "as", "on", "from", "join", "where", "select", "using":
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(tok)
+	return r == '"' || r == '_' || unicode.IsLetter(r)
+}
+
+// skipBlockComment пропускает комментарий /* */ с учётом вложенности (как в PostgreSQL);
+// terminated == false, если комментарий не закрыт до конца запроса
+func skipBlockComment(query string, i int) (end int, terminated bool) {
+	depth := 0
+	for i < len(query) {
+		switch {
+		case strings.HasPrefix(query[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(query[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i, true
+			}
+		default:
+			i++
+		}
+	}
+	return i, false
+}
+
+// skipString пропускает строковый литерал, начинающийся с кавычки на позиции i.
+// backslashEscapes — литерал E'...', в котором \' не завершает строку.
+// terminated == false, если литерал не закрыт до конца запроса.
+func skipString(query string, i int, backslashEscapes bool) (end int, terminated bool) {
+	i++
+	for i < len(query) {
+		switch query[i] {
+		case '\\':
+			if backslashEscapes {
+				i += 2
+				continue
+			}
+		case '\'':
+			if i+1 < len(query) && query[i+1] == '\'' {
+				i += 2
+				continue
+			}
+			return i + 1, true
+		}
+		i++
+	}
+	return len(query), false
+}
+
+// isStringPrefix сообщает, что c — префикс строкового литерала (E'...', B'...', X'...', N'...')
+func isStringPrefix(c byte) bool {
+	switch c {
+	case 'e', 'E', 'b', 'B', 'x', 'X', 'n', 'N':
+		return true
+	}
+	return false
+}
+
+// dollarQuoteEnd возвращает конец литерала $tag$...$tag$, начинающегося на позиции i;
+// ok == false, если на позиции i нет открывающего тега, terminated == false, если литерал не закрыт
+func dollarQuoteEnd(query string, i int) (end int, ok, terminated bool) {
+	end = i + 1
+	for end < len(query) && query[end] != '$' {
+		if !isIdentPart(query[end]) || (end == i+1 && isDigit(query[end])) {
+			return 0, false, false
+		}
+		end++
+	}
+	if end >= len(query) {
+		return 0, false, false
+	}
+	tag := query[i : end+1]
+	closing := strings.Index(query[end+1:], tag)
+	if closing < 0 {
+		return len(query), true, false
+	}
+	return end + 1 + closing + len(tag), true, true
+}
+
+// skipNumber пропускает числовой литерал (целый, десятичный, с экспонентой)
+func skipNumber(query string, i int) int {
+	for i < len(query) && (isDigit(query[i]) || query[i] == '.' || query[i] == '_') {
+		i++
+	}
+	if i < len(query) && (query[i] == 'e' || query[i] == 'E') {
+		j := i + 1
+		if j < len(query) && (query[j] == '+' || query[j] == '-') {
+			j++
+		}
+		if j < len(query) && isDigit(query[j]) {
+			i = j
+			for i < len(query) && isDigit(query[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
+
+func isOperator(c byte) bool {
+	return strings.IndexByte("+-*/<>=~!@#%^&|`?", c) >= 0
+}
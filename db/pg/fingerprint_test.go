@@ -0,0 +1,95 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "literals and parameters",
+			query: "SELECT id, name FROM users WHERE id = 42 AND email = 'a@b.c' AND age > $1",
+			want:  "select id, name from users where id = ? and email = ? and age > ?",
+		},
+		{
+			name:  "whitespace, case and comments",
+			query: "/* service: api */ SELECT  *\n\tFROM Users -- trailing\nWHERE id=$1",
+			want:  "select * from users where id = ?",
+		},
+		{
+			name:  "in list",
+			query: "SELECT * FROM users WHERE id IN ($1, $2, $3) AND status IN ('a','b')",
+			want:  "select * from users where id in (...) and status in (...)",
+		},
+		{
+			name:  "in subquery is kept",
+			query: "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders WHERE total > 100)",
+			want:  "select * from users where id in (select user_id from orders where total > ?)",
+		},
+		{
+			name:  "array literal",
+			query: "SELECT * FROM items WHERE id = ANY(ARRAY[1, 2, 3])",
+			want:  "select * from items where id = any (array[...])",
+		},
+		{
+			name:  "values tuples",
+			query: "INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'), ($1, $2)",
+			want:  "insert into t(a, b) values (?, ?)",
+		},
+		{
+			name:  "escape and dollar quoted strings",
+			query: `SELECT E'it\'s', $$raw 'text'$$, $tag$x$tag$, X'1F'`,
+			want:  "select ?, ?, ?, ?",
+		},
+		{
+			name:  "named parameters and casts",
+			query: "UPDATE users SET data = :data::jsonb, score = 1.5e3 WHERE id = :id",
+			want:  "update users set data = ?::jsonb, score = ? where id = ?",
+		},
+		{
+			name:  "quoted identifiers and operators",
+			query: `SELECT "User"."Name" FROM "User" WHERE data->>'key' <> 'v' AND count(*) >= 2`,
+			want:  `select "User"."Name" from "User" where data ->> ? <> ? and count(*) >= ?`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Fingerprint(tt.query))
+		})
+	}
+}
+
+func TestFingerprint_SameShape(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t,
+		Fingerprint("SELECT * FROM users WHERE id IN (1, 2) LIMIT 10"),
+		Fingerprint("select * from users where id in ($1,$2,$3,$4) limit $5"),
+	)
+}
+
+// TestWords tests that only unquoted words outside literals and comments are returned.
+func TestWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "SELECT id FROM Users WHERE id = $1", want: []string{"select", "id", "from", "users", "where", "id"}},
+		{query: `SELECT 'delete', "update", $$ insert $$ FROM t`, want: []string{"select", "from", "t"}},
+		{query: `SELECT E'\'' ; DELETE FROM t`, want: []string{"select", "delete", "from", "t"}},
+		{query: "/* outer /* inner */ update */ SELECT 1", want: []string{"select"}},
+		{query: "SELECT :name -- merge\n", want: []string{"select"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Words(tt.query), tt.query)
+	}
+}
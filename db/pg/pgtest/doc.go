@@ -0,0 +1,47 @@
+// Package pgtest запускает PostgreSQL в testcontainers для интеграционных тестов.
+//
+// Контейнер стартует с шаблонной базой: [Options].Setup выполняется для неё один раз
+// (миграции, справочники), после чего основная база и базы для отдельных тестов
+// создаются копированием шаблона (CREATE DATABASE ... TEMPLATE) — быстро и без повторных миграций.
+//
+// Использование в тесте:
+//
+//	func TestRepo(t *testing.T) {
+//	    pg := pgtest.StartPostgres(t, &pgtest.Options{Setup: migrate})
+//
+//	    t.Run("create", func(t *testing.T) {
+//	        t.Parallel()
+//	        db := pg.Connect(t) // отдельная база из шаблона, удаляется после теста
+//	        // ...
+//	    })
+//	}
+//
+// Использование в TestMain (один контейнер на пакет):
+//
+//	func TestMain(m *testing.M) {
+//	    flag.Parse()
+//	    if testing.Short() {
+//	        os.Exit(0)
+//	    }
+//	    pg, err := pgtest.Start(context.Background(), nil)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    testPG = pg
+//	    code := m.Run()
+//	    _ = pg.Terminate(context.Background())
+//	    os.Exit(code)
+//	}
+//
+// Функции и методы:
+//   - [StartPostgres] — контейнер на время теста, пропуск теста в режиме -short
+//   - [Start], [Postgres.Terminate] — ручное управление контейнером
+//   - [Postgres.Config], [Postgres.PgxConfig] — конфигурации адаптеров sqlx и pgx
+//   - [Postgres.NewDatabase] — новая база из шаблона, удаляется по завершении теста
+//   - [Postgres.Connect] — подключение sqlx к новой базе, закрывается по завершении теста
+//
+// Особенности:
+//   - Ожидание готовности по логу и повторные попытки подключения до StartupTimeout
+//   - Базы удаляются через DROP DATABASE ... WITH (FORCE), требуется PostgreSQL 13+
+//   - Клонирование сериализуется: PostgreSQL не копирует один шаблон параллельно
+package pgtest
@@ -0,0 +1,321 @@
+package pgtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/pure-golang/adapters/db/pg/pgx"
+	"github.com/pure-golang/adapters/db/pg/sqlx"
+)
+
+// Значения по умолчанию
+const (
+	DefaultImage          = "postgres:15"
+	DefaultUser           = "test_user"
+	DefaultPassword       = "secret"
+	DefaultDatabase       = "test_db"
+	DefaultStartupTimeout = time.Minute
+)
+
+// templateSuffix — суффикс имени шаблонной базы, из которой клонируются остальные
+const templateSuffix = "_template"
+
+// Options — параметры запуска контейнера PostgreSQL
+type Options struct {
+	Image          string        // Образ (default: postgres:15)
+	User           string        // Пользователь (default: test_user)
+	Password       string        // Пароль (default: secret)
+	Database       string        // Имя основной базы (default: test_db)
+	StartupTimeout time.Duration // Таймаут запуска контейнера и подключения (default: 1m)
+	// Setup вызывается один раз для шаблонной базы (миграции, справочники);
+	// основная база и базы из NewDatabase клонируются из шаблона после Setup
+	Setup func(ctx context.Context, db *sql.DB) error
+}
+
+// Postgres — запущенный контейнер PostgreSQL с шаблонной базой для клонирования
+type Postgres struct {
+	container testcontainers.Container
+	opts      Options
+	host      string
+	port      int
+	admin     *sql.DB // подключение к служебной базе postgres для CREATE/DROP DATABASE
+
+	cloneMu sync.Mutex // CREATE DATABASE ... TEMPLATE не допускает параллельного копирования шаблона
+	seq     atomic.Int64
+}
+
+// StartPostgres запускает контейнер для теста и останавливает его по завершении теста.
+// В режиме -short тест пропускается.
+func StartPostgres(t testing.TB, opts *Options) *Postgres {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	p, err := Start(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("failed to start postgres: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := p.Terminate(context.Background()); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	})
+	return p
+}
+
+// Start запускает контейнер PostgreSQL, дожидается готовности, выполняет Setup
+// для шаблонной базы и создаёт из неё основную базу. Для TestMain, где нет testing.TB;
+// остановка — через Terminate.
+func Start(ctx context.Context, opts *Options) (*Postgres, error) {
+	p := &Postgres{opts: withDefaults(opts)}
+
+	startCtx, cancel := context.WithTimeout(ctx, p.opts.StartupTimeout)
+	defer cancel()
+
+	container, err := testcontainers.GenericContainer(startCtx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        p.opts.Image,
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_USER":     p.opts.User,
+				"POSTGRES_PASSWORD": p.opts.Password,
+				"POSTGRES_DB":       p.templateName(),
+			},
+			// Первое сообщение пишет временный сервер инициализации
+			WaitingFor: wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(p.opts.StartupTimeout),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start postgres container")
+	}
+	p.container = container
+
+	if err := p.init(startCtx); err != nil {
+		if termErr := p.Terminate(context.Background()); termErr != nil {
+			return nil, errors.Wrap(err, termErr.Error())
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// init подключается к контейнеру, готовит шаблонную базу и создаёт основную
+func (p *Postgres) init(ctx context.Context) error {
+	host, err := p.container.Host(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get container host")
+	}
+	mappedPort, err := p.container.MappedPort(ctx, "5432")
+	if err != nil {
+		return errors.Wrap(err, "failed to get container port")
+	}
+	port, err := strconv.Atoi(mappedPort.Port())
+	if err != nil {
+		return errors.Wrap(err, "failed to parse container port")
+	}
+	p.host, p.port = host, port
+
+	p.admin, err = p.open(ctx, "postgres")
+	if err != nil {
+		return err
+	}
+
+	if p.opts.Setup != nil {
+		template, err := p.open(ctx, p.templateName())
+		if err != nil {
+			return err
+		}
+		setupErr := p.opts.Setup(ctx, template)
+		// Соединения с шаблоном не должны оставаться открытыми: иначе клонирование невозможно
+		if err := template.Close(); err != nil && setupErr == nil {
+			setupErr = errors.Wrap(err, "failed to close template database")
+		}
+		if setupErr != nil {
+			return errors.Wrap(setupErr, "failed to set up template database")
+		}
+	}
+
+	return p.clone(ctx, p.opts.Database)
+}
+
+// open подключается к базе с повторными попытками, пока контейнер не начнёт принимать соединения
+func (p *Postgres) open(ctx context.Context, database string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", p.dsn(database))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open database %s", database)
+	}
+
+	for {
+		err = db.PingContext(ctx)
+		if err == nil {
+			return db, nil
+		}
+		select {
+		case <-ctx.Done():
+			_ = db.Close()
+			return nil, errors.Wrapf(err, "failed to ping database %s", database)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// Terminate закрывает служебное подключение и останавливает контейнер
+func (p *Postgres) Terminate(ctx context.Context) error {
+	if p.admin != nil {
+		_ = p.admin.Close()
+	}
+	if p.container == nil {
+		return nil
+	}
+	if err := p.container.Terminate(ctx); err != nil {
+		return errors.Wrap(err, "failed to terminate postgres container")
+	}
+	return nil
+}
+
+// Database возвращает имя основной базы
+func (p *Postgres) Database() string {
+	return p.opts.Database
+}
+
+// Config возвращает конфигурацию sqlx для базы database; пустое имя означает основную базу
+func (p *Postgres) Config(database string) sqlx.Config {
+	return sqlx.Config{
+		Host:           p.host,
+		Port:           p.port,
+		User:           p.opts.User,
+		Password:       p.opts.Password,
+		Database:       p.databaseName(database),
+		SSLMode:        "disable",
+		ConnectTimeout: 5,
+		QueryTimeout:   30 * time.Second,
+		RetryAttempts:  5,
+		RetryBackoff:   200 * time.Millisecond,
+	}
+}
+
+// PgxConfig возвращает конфигурацию pgx для базы database; пустое имя означает основную базу
+func (p *Postgres) PgxConfig(database string) pgx.Config {
+	return pgx.Config{
+		Host:            p.host,
+		Port:            p.port,
+		User:            p.opts.User,
+		Password:        p.opts.Password,
+		Name:            p.databaseName(database),
+		MaxOpenConns:    10,
+		MaxConnLifeTime: 5,
+		MaxConnIdleTime: 5,
+		TraceLogLevel:   "error",
+		RetryAttempts:   5,
+		RetryBackoff:    200 * time.Millisecond,
+	}
+}
+
+// NewDatabase создаёт отдельную базу из шаблона для параллельного теста и удаляет её по завершении теста.
+// Возвращает имя созданной базы.
+func (p *Postgres) NewDatabase(t testing.TB) string {
+	t.Helper()
+	name := fmt.Sprintf("%s_%d", p.opts.Database, p.seq.Add(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.StartupTimeout)
+	defer cancel()
+	if err := p.clone(ctx, name); err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		// WITH (FORCE) закрывает соединения, которые тест забыл закрыть
+		_, err := p.admin.ExecContext(context.Background(), "DROP DATABASE IF EXISTS "+pq.QuoteIdentifier(name)+" WITH (FORCE)")
+		if err != nil {
+			t.Logf("failed to drop database %s: %v", name, err)
+		}
+	})
+	return name
+}
+
+// Connect создаёт базу через NewDatabase и подключается к ней; соединение закрывается по завершении теста
+func (p *Postgres) Connect(t testing.TB) *sqlx.Connection {
+	t.Helper()
+	cfg := p.Config(p.NewDatabase(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.StartupTimeout)
+	defer cancel()
+	conn, err := sqlx.Connect(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to database %s: %v", cfg.Database, err)
+	}
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Logf("failed to close connection: %v", err)
+		}
+	})
+	return conn
+}
+
+// clone создаёт базу name из шаблонной базы
+func (p *Postgres) clone(ctx context.Context, name string) error {
+	p.cloneMu.Lock()
+	defer p.cloneMu.Unlock()
+
+	query := "CREATE DATABASE " + pq.QuoteIdentifier(name) + " TEMPLATE " + pq.QuoteIdentifier(p.templateName())
+	if _, err := p.admin.ExecContext(ctx, query); err != nil {
+		return errors.Wrapf(err, "failed to create database %s", name)
+	}
+	return nil
+}
+
+// databaseName возвращает имя основной базы, если database пусто
+func (p *Postgres) databaseName(database string) string {
+	if database == "" {
+		return p.opts.Database
+	}
+	return database
+}
+
+func (p *Postgres) templateName() string {
+	return p.opts.Database + templateSuffix
+}
+
+func (p *Postgres) dsn(database string) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=5",
+		p.host, p.port, p.opts.User, p.opts.Password, database)
+}
+
+// withDefaults возвращает копию opts с заполненными значениями по умолчанию
+func withDefaults(opts *Options) Options {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Image == "" {
+		o.Image = DefaultImage
+	}
+	if o.User == "" {
+		o.User = DefaultUser
+	}
+	if o.Password == "" {
+		o.Password = DefaultPassword
+	}
+	if o.Database == "" {
+		o.Database = DefaultDatabase
+	}
+	if o.StartupTimeout <= 0 {
+		o.StartupTimeout = DefaultStartupTimeout
+	}
+	return o
+}
@@ -0,0 +1,37 @@
+package pgtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithDefaults tests that empty options are filled with defaults.
+func TestWithDefaults(t *testing.T) {
+	t.Parallel()
+
+	o := withDefaults(nil)
+	assert.Equal(t, DefaultImage, o.Image)
+	assert.Equal(t, DefaultUser, o.User)
+	assert.Equal(t, DefaultPassword, o.Password)
+	assert.Equal(t, DefaultDatabase, o.Database)
+	assert.Equal(t, DefaultStartupTimeout, o.StartupTimeout)
+
+	custom := &Options{Image: "postgres:16", Database: "app"}
+	o = withDefaults(custom)
+	assert.Equal(t, "postgres:16", o.Image)
+	assert.Equal(t, "app", o.Database)
+	assert.Empty(t, custom.User, "options must not be modified")
+}
+
+// TestPostgres_Config tests adapter configurations built for the container.
+func TestPostgres_Config(t *testing.T) {
+	t.Parallel()
+	p := &Postgres{opts: withDefaults(nil), host: "localhost", port: 55432}
+
+	cfg := p.Config("")
+	assert.Equal(t, DefaultDatabase, cfg.Database)
+	assert.Equal(t, 55432, cfg.Port)
+	assert.Equal(t, "other", p.Config("other").Database)
+	assert.Equal(t, DefaultDatabase, p.PgxConfig("").Name)
+}
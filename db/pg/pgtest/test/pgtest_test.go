@@ -0,0 +1,50 @@
+package pgtest_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg/pgtest"
+	"github.com/pure-golang/adapters/db/pg/pgx"
+)
+
+// TestStartPostgres tests that databases are cloned from the prepared template and isolated.
+func TestStartPostgres(t *testing.T) {
+	pg := pgtest.StartPostgres(t, &pgtest.Options{
+		Setup: func(ctx context.Context, db *sql.DB) error {
+			_, err := db.ExecContext(ctx, `CREATE TABLE items (id SERIAL PRIMARY KEY, name TEXT NOT NULL);
+				INSERT INTO items (name) VALUES ('seed')`)
+			return err
+		},
+	})
+
+	// every database is cloned from the template and isolated from the others
+	for _, name := range []string{"a", "b"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			db := pg.Connect(t)
+			ctx := context.Background()
+
+			_, err := db.Exec(ctx, `INSERT INTO items (name) VALUES ($1)`, name)
+			require.NoError(t, err)
+
+			var names []string
+			require.NoError(t, db.Select(ctx, &names, `SELECT name FROM items ORDER BY id`))
+			assert.Equal(t, []string{"seed", name}, names)
+		})
+	}
+
+	t.Run("pgx", func(t *testing.T) {
+		db, err := pgx.New(pg.PgxConfig(pg.NewDatabase(t)), nil)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var count int
+		require.NoError(t, db.QueryRow(context.Background(), `SELECT count(*) FROM items`).Scan(&count))
+		assert.Equal(t, 1, count)
+	})
+}
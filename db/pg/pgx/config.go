@@ -3,11 +3,14 @@ package pgx
 import (
 	"fmt"
 	"net/url"
+	"time"
+
+	"github.com/pure-golang/adapters/db/pg"
 )
 
 type Config struct {
 	User            string `envconfig:"POSTGRES_USER" required:"true"`
-	Password        string `envconfig:"POSTGRES_PASSWORD" required:"true"`
+	Password        string `envconfig:"POSTGRES_PASSWORD"`
 	Host            string `envconfig:"POSTGRES_HOST" required:"true"`
 	Port            int    `envconfig:"POSTGRES_PORT" default:"5432"`
 	Name            string `envconfig:"POSTGRES_DB_NAME" required:"true"`
@@ -18,6 +21,26 @@ type Config struct {
 	// TraceLogLevel  values: trace, debug, info, warn, error, none.
 	// Set "error" or omit empty for production, "debug" for dev.
 	TraceLogLevel string `envconfig:"POSTGRES_TRACE_LOG_LEVEL" default:"error"`
+	// RetryAttempts — number of initial ping attempts; values below 1 mean a single attempt.
+	RetryAttempts int `envconfig:"POSTGRES_RETRY_ATTEMPTS" default:"1"`
+	// RetryBackoff — delay between ping attempts.
+	RetryBackoff time.Duration `envconfig:"POSTGRES_RETRY_BACKOFF" default:"1s"`
+	// LazyConnect skips the initial ping: the pool connects on first use, retrying the first ping
+	// RetryAttempts times.
+	LazyConnect bool `envconfig:"POSTGRES_LAZY_CONNECT" default:"false"`
+	// SessionSettings are run-time parameters (GUC) set on every new pooled connection,
+	// e.g. "statement_timeout:5s,lock_timeout:1s,application_name:orders" (see pg.SessionSettingsQuery).
+	// A parameter PostgreSQL rejects fails the connection.
+	SessionSettings map[string]string `envconfig:"POSTGRES_SESSION_SETTINGS"`
+	// QueryMetrics records per-fingerprint query counters and latency histograms
+	// (see pg.NewQueryMetrics); nil disables query metrics.
+	QueryMetrics *pg.QueryMetrics `ignored:"true"`
+	// Credentials supplies rotated credentials (e.g. from Vault) instead of Password;
+	// User is used when the provider returns no user.
+	Credentials pg.CredentialsProvider `ignored:"true"`
+	// SQLCommenter appends a sqlcommenter comment with traceparent and context tags to queries
+	// run through DB.Exec, DB.Query and DB.QueryRow (see pg.SQLCommenter); nil leaves queries unchanged.
+	SQLCommenter *pg.SQLCommenter `ignored:"true"`
 }
 
 // URL returns database config in URL presentation
@@ -0,0 +1,92 @@
+package pgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+// credentialsKey — ключ контекста подключения с использованными учётными данными
+type credentialsKey struct{}
+
+// credentialsRotation подставляет учётные данные pg.CredentialsProvider в новые соединения пула.
+// После ошибки аутентификации учётные данные запрашиваются заново, а соединения со старыми
+// закрываются: свободные — перед выдачей, занятые — после освобождения, не прерывая запросы.
+type credentialsRotation struct {
+	cache *pg.CredentialsCache
+}
+
+func newCredentialsRotation(provider pg.CredentialsProvider, defaultUser string) *credentialsRotation {
+	return &credentialsRotation{cache: pg.NewCredentialsCache(provider, defaultUser)}
+}
+
+// configure устанавливает хуки пула
+func (r *credentialsRotation) configure(poolCfg *pgxpool.Config) {
+	poolCfg.BeforeConnect = r.beforeConnect
+	poolCfg.PrepareConn = r.prepareConn
+	poolCfg.AfterRelease = r.current
+}
+
+func (r *credentialsRotation) beforeConnect(ctx context.Context, cc *pgx.ConnConfig) error {
+	creds, err := r.cache.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cc.User = creds.User
+	cc.Password = creds.Password
+	return nil
+}
+
+// prepareConn отбрасывает свободное соединение со старыми учётными данными;
+// пул повторяет выдачу на новом соединении
+func (r *credentialsRotation) prepareConn(_ context.Context, conn *pgx.Conn) (bool, error) {
+	return r.current(conn), nil
+}
+
+// current сообщает, что соединение использует актуальные учётные данные
+func (r *credentialsRotation) current(conn *pgx.Conn) bool {
+	cfg := conn.Config()
+	return r.cache.Current(pg.Credentials{User: cfg.User, Password: cfg.Password})
+}
+
+// TraceConnectStart реализует pgx.ConnectTracer
+func (r *credentialsRotation) TraceConnectStart(ctx context.Context, data pgx.TraceConnectStartData) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, pg.Credentials{
+		User:     data.ConnConfig.User,
+		Password: data.ConnConfig.Password,
+	})
+}
+
+// TraceConnectEnd реализует pgx.ConnectTracer: после ошибки аутентификации запрашивает
+// учётные данные заново для следующих подключений
+func (r *credentialsRotation) TraceConnectEnd(ctx context.Context, data pgx.TraceConnectEndData) {
+	var pgErr *pgconn.PgError
+	if data.Err == nil || !errors.As(data.Err, &pgErr) || !pg.IsAuthErrorCode(pgErr.Code) {
+		return
+	}
+	failed, _ := ctx.Value(credentialsKey{}).(pg.Credentials)
+
+	changed, err := r.cache.Refresh(ctx, failed)
+	log := correlate.FromContext(ctx).WithGroup("postgres")
+	if err != nil {
+		log.With("error", err).ErrorContext(ctx, "failed to refresh database credentials")
+		return
+	}
+	if changed {
+		log.InfoContext(ctx, "database credentials refreshed after authentication failure")
+	}
+}
+
+// TraceQueryStart реализует pgx.QueryTracer, обязательный для pgx.ConnConfig.Tracer
+func (r *credentialsRotation) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+// TraceQueryEnd реализует pgx.QueryTracer
+func (r *credentialsRotation) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
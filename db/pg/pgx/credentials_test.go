@@ -0,0 +1,59 @@
+package pgx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+func TestCredentialsRotation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var password atomic.Value
+	password.Store("p1")
+	rotation := newCredentialsRotation(pg.CredentialsFunc(func(context.Context) (pg.Credentials, error) {
+		return pg.Credentials{Password: password.Load().(string)}, nil
+	}), "app")
+
+	cc := &pgx.ConnConfig{}
+	require.NoError(t, rotation.beforeConnect(ctx, cc))
+	assert.Equal(t, "app", cc.User)
+	assert.Equal(t, "p1", cc.Password)
+
+	// authentication failure refreshes the credentials for the next connection
+	password.Store("p2")
+	connectCtx := rotation.TraceConnectStart(ctx, pgx.TraceConnectStartData{ConnConfig: cc})
+	rotation.TraceConnectEnd(connectCtx, pgx.TraceConnectEndData{
+		Err: errors.Wrap(&pgconn.PgError{Code: "28P01"}, "failed to connect"),
+	})
+
+	next := &pgx.ConnConfig{}
+	require.NoError(t, rotation.beforeConnect(ctx, next))
+	assert.Equal(t, "p2", next.Password)
+}
+
+func TestCredentialsRotation_OtherErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var calls atomic.Int32
+	rotation := newCredentialsRotation(pg.CredentialsFunc(func(context.Context) (pg.Credentials, error) {
+		calls.Add(1)
+		return pg.Credentials{User: "app", Password: "p1"}, nil
+	}), "")
+
+	cc := &pgx.ConnConfig{}
+	require.NoError(t, rotation.beforeConnect(ctx, cc))
+	connectCtx := rotation.TraceConnectStart(ctx, pgx.TraceConnectStartData{ConnConfig: cc})
+	rotation.TraceConnectEnd(connectCtx, pgx.TraceConnectEndData{Err: &pgconn.PgError{Code: "3D000"}})
+	rotation.TraceConnectEnd(connectCtx, pgx.TraceConnectEndData{})
+
+	assert.Equal(t, int32(1), calls.Load())
+}
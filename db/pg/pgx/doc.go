@@ -20,6 +20,19 @@
 //	}
 //	defer db.Close()
 //
+// LISTEN/NOTIFY:
+//
+//	go db.Listen(ctx, "jobs", func(ctx context.Context, n pgxadapter.Notification) error {
+//	    return process(ctx, n.Payload)
+//	})
+//
+//	// С декодированием JSON payload
+//	go pgxadapter.ListenJSON(ctx, db, "user_events", func(ctx context.Context, e UserEvent) error {
+//	    return handle(ctx, e)
+//	}, pgxadapter.WithOnReconnect(resync))
+//
+//	err := db.NotifyJSON(ctx, "user_events", UserEvent{ID: 1})
+//
 // Конфигурация через переменные окружения:
 //
 //	PG_HOST              — хост сервера (default: localhost)
@@ -32,10 +45,27 @@
 //	PG_MAX_CONN_LIFETIME — время жизни соединения в секундах
 //	PG_MAX_CONN_IDLE_TIME — время простоя соединения в секундах
 //	PG_TRACE_LOG_LEVEL   — уровень логирования (debug, info, warn, error)
+//	POSTGRES_RETRY_ATTEMPTS — число попыток начального ping (default: 1)
+//	POSTGRES_RETRY_BACKOFF  — пауза между попытками (default: 1s)
+//	POSTGRES_LAZY_CONNECT   — проверять подключение при первом запросе, а не в New (default: false)
+//	POSTGRES_SESSION_SETTINGS — параметры сессии на каждом соединении (AfterConnect): "statement_timeout:5s,timezone:UTC"
 //
 // Особенности:
 //   - Использует pgxpool для управления пулом соединений
 //   - Поддерживает OpenTelemetry tracing через otelpgx
-//   - Автоматическое логирование запросов через tracelog
+//   - Автоматическое логирование запросов через tracelog с trace_id, span_id и request_id
+//     из контекста (observability/correlate)
+//   - Listen использует отдельное соединение вне пула, переподключается и повторяет подписку
+//     при разрыве; уведомления, отправленные во время разрыва, теряются (см. WithOnReconnect)
+//   - Config.Credentials (pg.CredentialsProvider) подставляет учётные данные в каждое новое
+//     соединение; после ошибки аутентификации они запрашиваются заново, свободные соединения
+//     со старыми учётными данными закрываются перед выдачей, занятые — после освобождения.
+//     Запрос, при котором новое соединение получило ошибку аутентификации, возвращает её;
+//     следующие подключаются с новыми учётными данными
+//   - Config.QueryMetrics (pg.QueryMetrics): счётчики и гистограммы длительности запросов
+//     по нормализованным отпечаткам, записываются через QueryTracer пула
+//   - Config.SQLCommenter (pg.SQLCommenter): комментарий sqlcommenter с traceparent и тегами
+//     из контекста добавляется к запросам DB.Exec, DB.Query и DB.QueryRow; кэш подготовленных
+//     выражений отключается (QueryExecModeDescribeExec), т.к. текст запросов становится уникальным
 //   - Рекомендуется для новых проектов
 package pgx
@@ -0,0 +1,200 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+// Параметры переподключения Listen по умолчанию
+const (
+	DefaultListenMinBackoff = 500 * time.Millisecond
+	DefaultListenMaxBackoff = 30 * time.Second
+)
+
+const listenCloseTimeout = 5 * time.Second
+
+// Notification — уведомление, отправленное NOTIFY или pg_notify
+type Notification struct {
+	Channel string
+	Payload string
+	PID     uint32 // PID серверного процесса, отправившего уведомление
+}
+
+// NotificationHandler обрабатывает уведомление. Ошибка логируется, прослушивание продолжается.
+type NotificationHandler func(ctx context.Context, n Notification) error
+
+// ListenOption настраивает Listen
+type ListenOption func(*listenConfig)
+
+// WithListenBackoff задаёт паузу перед переподключением: от minBackoff с удвоением до maxBackoff
+func WithListenBackoff(minBackoff, maxBackoff time.Duration) ListenOption {
+	return func(c *listenConfig) {
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithOnReconnect задаёт функцию, вызываемую после повторной подписки.
+// Уведомления, отправленные во время разрыва, не доставляются — fn может перечитать состояние из БД.
+func WithOnReconnect(fn func(ctx context.Context)) ListenOption {
+	return func(c *listenConfig) {
+		c.onReconnect = fn
+	}
+}
+
+type listenConfig struct {
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	onReconnect func(ctx context.Context)
+}
+
+// listenConn — соединение, на котором выполняется LISTEN; реализуется *pgx.Conn
+type listenConn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+}
+
+// Listen подписывается на канал channel и вызывает handler для каждого уведомления.
+// Блокируется до отмены ctx и возвращает nil; ошибку возвращает только при неверных аргументах.
+//
+// Для подписки используется отдельное соединение, изъятое из пула (Hijack): оно не занимает
+// место в пуле и закрывается при выходе. При разрыве соединение восстанавливается с паузой
+// (WithListenBackoff) и подписка повторяется; уведомления, отправленные во время разрыва, теряются.
+// Уведомления обрабатываются последовательно в порядке получения.
+func (db *DB) Listen(ctx context.Context, channel string, handler NotificationHandler, opts ...ListenOption) error {
+	return listen(ctx, channel, handler, db.connectListener, opts...)
+}
+
+// ListenJSON — Listen с декодированием payload из JSON в T.
+// Уведомления, которые не удалось декодировать, логируются и пропускаются.
+func ListenJSON[T any](ctx context.Context, db *DB, channel string, handler func(ctx context.Context, event T) error, opts ...ListenOption) error {
+	if handler == nil {
+		return errors.New("listen handler is nil")
+	}
+	return db.Listen(ctx, channel, JSONHandler(handler), opts...)
+}
+
+// JSONHandler возвращает NotificationHandler, декодирующий payload из JSON в T
+func JSONHandler[T any](handler func(ctx context.Context, event T) error) NotificationHandler {
+	return func(ctx context.Context, n Notification) error {
+		var event T
+		if err := json.Unmarshal([]byte(n.Payload), &event); err != nil {
+			return errors.Wrapf(err, "failed to decode notification payload of channel %q", n.Channel)
+		}
+		return handler(ctx, event)
+	}
+}
+
+// Notify отправляет уведомление в канал channel (pg_notify).
+// В транзакции уведомление доставляется после её фиксации.
+func (db *DB) Notify(ctx context.Context, channel, payload string) error {
+	if _, err := db.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return errors.Wrapf(err, "failed to notify channel %q", channel)
+	}
+	return nil
+}
+
+// NotifyJSON отправляет уведомление с payload, закодированным в JSON.
+// Размер payload ограничен PostgreSQL 8000 байтами.
+func (db *DB) NotifyJSON(ctx context.Context, channel string, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode notification payload")
+	}
+	return db.Notify(ctx, channel, string(payload))
+}
+
+// connectListener изымает соединение из пула для LISTEN
+func (db *DB) connectListener(ctx context.Context) (listenConn, error) {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to acquire listen connection")
+	}
+	return conn.Hijack(), nil
+}
+
+// listen подписывается на канал через соединения от connect, переподключаясь при ошибках
+func listen(ctx context.Context, channel string, handler NotificationHandler, connect func(ctx context.Context) (listenConn, error), opts ...ListenOption) error {
+	if channel == "" {
+		return errors.New("listen channel is empty")
+	}
+	if handler == nil {
+		return errors.New("listen handler is nil")
+	}
+
+	cfg := &listenConfig{minBackoff: DefaultListenMinBackoff, maxBackoff: DefaultListenMaxBackoff}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.maxBackoff = max(cfg.maxBackoff, cfg.minBackoff)
+
+	log := correlate.FromContext(ctx).With("channel", channel)
+	backoff := cfg.minBackoff
+	subscribed := false
+
+	for {
+		err := listenOnce(ctx, channel, handler, connect, func() {
+			if subscribed {
+				log.Info("listen connection restored")
+				if cfg.onReconnect != nil {
+					cfg.onReconnect(ctx)
+				}
+			}
+			subscribed = true
+			backoff = cfg.minBackoff
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.With("error", err).Warn("listen connection lost, reconnecting", "backoff", backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, cfg.maxBackoff)
+	}
+}
+
+// listenOnce подписывается на канал на одном соединении и обрабатывает уведомления до ошибки.
+// onSubscribed вызывается после успешного LISTEN.
+func listenOnce(ctx context.Context, channel string, handler NotificationHandler, connect func(ctx context.Context) (listenConn, error), onSubscribed func()) error {
+	conn, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), listenCloseTimeout)
+		defer cancel()
+		if err := conn.Close(closeCtx); err != nil {
+			correlate.FromContext(ctx).With("error", err).Warn("failed to close listen connection", "channel", channel)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return errors.Wrapf(err, "failed to listen channel %q", channel)
+	}
+	onSubscribed()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to wait for notification")
+		}
+		notification := Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}
+		if err := handler(ctx, notification); err != nil {
+			correlate.FromContext(ctx).With("error", err).Error("failed to handle notification", "channel", n.Channel)
+		}
+	}
+}
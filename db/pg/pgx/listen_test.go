@@ -0,0 +1,141 @@
+package pgx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeListenConn delivers queued notifications, then fails with err or blocks until ctx is done
+type fakeListenConn struct {
+	mu            sync.Mutex
+	queries       []string
+	notifications []*pgconn.Notification
+	err           error
+	closed        bool
+}
+
+func (c *fakeListenConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, sql)
+	return pgconn.NewCommandTag("LISTEN"), nil
+}
+
+func (c *fakeListenConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	c.mu.Lock()
+	if len(c.notifications) > 0 {
+		n := c.notifications[0]
+		c.notifications = c.notifications[1:]
+		c.mu.Unlock()
+		return n, nil
+	}
+	err := c.err
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *fakeListenConn) Close(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// TestListen_Reconnect tests that the subscription is restored after connection errors.
+func TestListen_Reconnect(t *testing.T) {
+	t.Parallel()
+	first := &fakeListenConn{
+		notifications: []*pgconn.Notification{{Channel: "events", Payload: "1"}, {Channel: "events", Payload: "2"}},
+		err:           errors.New("connection reset"),
+	}
+	second := &fakeListenConn{
+		notifications: []*pgconn.Notification{{Channel: "events", Payload: "3", PID: 42}},
+	}
+
+	var connects int
+	connect := func(context.Context) (listenConn, error) {
+		connects++
+		switch connects {
+		case 1:
+			return first, nil
+		case 2:
+			return nil, errors.New("connection refused")
+		default:
+			return second, nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var received []Notification
+	var reconnects int
+	handler := func(_ context.Context, n Notification) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, n)
+		if len(received) == 3 {
+			cancel()
+		}
+		return errors.New("handler errors are logged")
+	}
+
+	err := listen(ctx, "events", handler, connect,
+		WithListenBackoff(time.Millisecond, 5*time.Millisecond),
+		WithOnReconnect(func(context.Context) { reconnects++ }),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "1", received[0].Payload)
+	assert.Equal(t, Notification{Channel: "events", Payload: "3", PID: 42}, received[2])
+	assert.Equal(t, 3, connects)
+	assert.Equal(t, 1, reconnects)
+	assert.Equal(t, []string{`LISTEN "events"`}, first.queries)
+	assert.Equal(t, []string{`LISTEN "events"`}, second.queries)
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+}
+
+// TestListen_InvalidArguments tests argument validation.
+func TestListen_InvalidArguments(t *testing.T) {
+	t.Parallel()
+	connect := func(context.Context) (listenConn, error) { return &fakeListenConn{}, nil }
+	handler := func(context.Context, Notification) error { return nil }
+
+	require.Error(t, listen(context.Background(), "", handler, connect))
+	require.Error(t, listen(context.Background(), "events", nil, connect))
+}
+
+// TestJSONHandler tests decoding of JSON payloads.
+func TestJSONHandler(t *testing.T) {
+	t.Parallel()
+	type event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	var got event
+	handler := JSONHandler(func(_ context.Context, e event) error {
+		got = e
+		return nil
+	})
+
+	require.NoError(t, handler(context.Background(), Notification{Channel: "users", Payload: `{"id":1,"name":"alice"}`}))
+	assert.Equal(t, event{ID: 1, Name: "alice"}, got)
+
+	err := handler(context.Background(), Notification{Channel: "users", Payload: "not json"})
+	assert.ErrorContains(t, err, `failed to decode notification payload of channel "users"`)
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/tracelog"
 
-	"github.com/pure-golang/adapters/logger"
+	"github.com/pure-golang/adapters/observability/correlate"
 )
 
 type Logger struct {
@@ -37,7 +37,7 @@ func (l *Logger) Log(ctx context.Context, level tracelog.LogLevel, msg string, d
 		attrs = append(attrs, slog.Any(k, v))
 	}
 
-	logger.FromContext(ctx).WithGroup("postgres").LogAttrs(ctx, l.slogLevel(level), msg, attrs...)
+	correlate.FromContext(ctx).WithGroup("postgres").LogAttrs(ctx, l.slogLevel(level), msg, attrs...)
 }
 
 func (l *Logger) slogLevel(level tracelog.LogLevel) slog.Level {
@@ -0,0 +1,38 @@
+package pgx
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// queryStartKey — ключ контекста запроса с временем начала и текстом
+type queryStartKey struct{}
+
+// queryStart — начало запроса для queryMetricsTracer
+type queryStart struct {
+	sql  string
+	time time.Time
+}
+
+// queryMetricsTracer записывает метрики запросов по отпечаткам в pg.QueryMetrics
+type queryMetricsTracer struct {
+	metrics *pg.QueryMetrics
+}
+
+// TraceQueryStart реализует pgx.QueryTracer
+func (t *queryMetricsTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, time: time.Now()})
+}
+
+// TraceQueryEnd реализует pgx.QueryTracer
+func (t *queryMetricsTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	t.metrics.Record(ctx, start.sql, time.Since(start.time), data.Err)
+}
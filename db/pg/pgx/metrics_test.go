@@ -0,0 +1,42 @@
+package pgx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+func TestQueryMetricsTracer(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	tracer := &queryMetricsTracer{metrics: pg.NewQueryMetrics(&pg.QueryMetricsOptions{
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM users WHERE id = $1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	// end without start is ignored
+	tracer.TraceQueryEnd(context.Background(), nil, pgx.TraceQueryEndData{})
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "db.pg.queries_total" {
+			continue
+		}
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+		require.Len(t, sum.DataPoints, 1)
+		fingerprint, _ := sum.DataPoints[0].Attributes.Value("db.query.fingerprint")
+		assert.Equal(t, "select * from users where id = ?", fingerprint.AsString())
+		assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	}
+}
@@ -3,20 +3,35 @@ package pgx
 import (
 	"context"
 	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/exaring/otelpgx"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
 )
 
 // DB extends pgxpool.Pool functionality
 type DB struct {
 	*pgxpool.Pool
 	io.Closer
+
+	commenter *pg.SQLCommenter
+
+	// retryAttempts и retryBackoff — повторные попытки проверки подключения (см. ensureConnected)
+	retryAttempts int
+	retryBackoff  time.Duration
+	// connected и connectMu используются в режиме LazyConnect для проверки подключения при первом запросе
+	connected atomic.Bool
+	connectMu sync.Mutex
 }
 
 type Options struct {
@@ -42,19 +57,87 @@ func New(cfg Config, options *Options) (*DB, error) {
 		options = &Options{}
 	}
 
-	if len(options.Tracers) > 0 {
-		poolCfg.ConnConfig.Tracer = multitracer.New(options.Tracers...)
+	tracers := options.Tracers
+	if cfg.Credentials != nil {
+		rotation := newCredentialsRotation(cfg.Credentials, cfg.User)
+		rotation.configure(poolCfg)
+		tracers = append(slices.Clip(tracers), rotation)
+	}
+	if cfg.QueryMetrics != nil {
+		tracers = append(slices.Clip(tracers), &queryMetricsTracer{metrics: cfg.QueryMetrics})
+	}
+
+	if cfg.SQLCommenter != nil {
+		// Комментарий с traceparent делает текст каждого запроса уникальным: кэш подготовленных
+		// выражений по тексту запроса только рос бы и вытеснял записи, поэтому запросы описываются
+		// безымянным выражением при каждом выполнении
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
+	}
+
+	if len(tracers) > 0 {
+		poolCfg.ConnConfig.Tracer = multitracer.New(tracers...)
+	}
+
+	if len(cfg.SessionSettings) > 0 {
+		poolCfg.AfterConnect = sessionSettingsHook(cfg.SessionSettings)
 	}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init database connections pool")
 	}
-	if err := pool.Ping(context.Background()); err != nil {
-		return nil, errors.Wrap(err, "failed to ping database")
+
+	db := &DB{
+		Pool:          pool,
+		commenter:     cfg.SQLCommenter,
+		retryAttempts: cfg.RetryAttempts,
+		retryBackoff:  cfg.RetryBackoff,
 	}
 
-	return &DB{Pool: pool}, nil
+	// В режиме LazyConnect подключение проверяется при первом запросе
+	if cfg.LazyConnect {
+		return db, nil
+	}
+
+	if err := db.ensureConnected(context.Background()); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// ensureConnected проверяет подключение с повторными попытками (RetryAttempts/RetryBackoff),
+// пока оно не будет установлено. После первой успешной проверки вызов ничего не делает.
+func (db *DB) ensureConnected(ctx context.Context) error {
+	if db.connected.Load() {
+		return nil
+	}
+
+	db.connectMu.Lock()
+	defer db.connectMu.Unlock()
+
+	if db.connected.Load() {
+		return nil
+	}
+
+	if err := pg.Retry(ctx, db.retryAttempts, db.retryBackoff, db.Pool.Ping); err != nil {
+		return errors.Wrap(err, "failed to ping database")
+	}
+
+	db.connected.Store(true)
+	return nil
+}
+
+// sessionSettingsHook возвращает AfterConnect, устанавливающий параметры сессии на новом соединении
+func sessionSettingsHook(settings map[string]string) func(context.Context, *pgx.Conn) error {
+	query, args := pg.SessionSettingsQuery(settings)
+	return func(ctx context.Context, conn *pgx.Conn) error {
+		if _, err := conn.Exec(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "failed to apply session settings")
+		}
+		return nil
+	}
 }
 
 func NewDefault(c Config) (*DB, error) {
@@ -69,6 +152,71 @@ func NewDefault(c Config) (*DB, error) {
 	})
 }
 
+// Ping проверяет подключение; в режиме LazyConnect первая проверка выполняется с повторными попытками
+func (db *DB) Ping(ctx context.Context) error {
+	if !db.connected.Load() {
+		return db.ensureConnected(ctx)
+	}
+	return db.Pool.Ping(ctx)
+}
+
+// Acquire возвращает соединение из пула, см. Ping
+func (db *DB) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	if err := db.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return db.Pool.Acquire(ctx)
+}
+
+// Begin начинает транзакцию, см. Ping
+func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+	if err := db.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return db.Pool.Begin(ctx)
+}
+
+// BeginTx начинает транзакцию с параметрами txOptions, см. Ping
+func (db *DB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	if err := db.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return db.Pool.BeginTx(ctx, txOptions)
+}
+
+// Exec выполняет запрос, добавляя комментарий Config.SQLCommenter
+func (db *DB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if err := db.ensureConnected(ctx); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return db.Pool.Exec(ctx, db.commenter.Apply(ctx, sql), args...)
+}
+
+// Query выполняет запрос, добавляя комментарий Config.SQLCommenter
+func (db *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if err := db.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return db.Pool.Query(ctx, db.commenter.Apply(ctx, sql), args...)
+}
+
+// QueryRow выполняет запрос, добавляя комментарий Config.SQLCommenter
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if err := db.ensureConnected(ctx); err != nil {
+		return errRow{err: err}
+	}
+	return db.Pool.QueryRow(ctx, db.commenter.Apply(ctx, sql), args...)
+}
+
+// errRow — строка результата запроса, не выполненного из-за ошибки подключения
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
 func (db *DB) Close() error {
 	db.Pool.Close()
 	return nil
@@ -786,3 +786,106 @@ func TestNewDefault_ConfigVariations(t *testing.T) {
 		assert.Nil(t, db)
 	})
 }
+
+func TestNew_RetryAttempts(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		User:          "testuser",
+		Password:      "testpass",
+		Host:          "127.0.0.1",
+		Port:          1,
+		Name:          "testdb",
+		MaxOpenConns:  1,
+		RetryAttempts: 2,
+		RetryBackoff:  10 * time.Millisecond,
+	}
+
+	db, err := New(cfg, nil)
+
+	require.Error(t, err)
+	assert.Nil(t, db)
+	assert.Contains(t, err.Error(), "all 2 attempts failed")
+}
+
+func TestNew_LazyConnect(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		User:         "testuser",
+		Password:     "testpass",
+		Host:         "127.0.0.1",
+		Port:         1,
+		Name:         "testdb",
+		MaxOpenConns: 1,
+		LazyConnect:  true,
+	}
+
+	db, err := New(cfg, nil)
+	require.NoError(t, err, "lazy connect must not touch the database")
+	require.NotNil(t, db)
+	t.Cleanup(func() { db.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Error(t, db.Ping(ctx), "connection errors surface on first use")
+}
+
+// TestNew_LazyConnectRetriesFirstUse tests that the first query retries the ping RetryAttempts times
+func TestNew_LazyConnectRetriesFirstUse(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		User:          "testuser",
+		Password:      "testpass",
+		Host:          "127.0.0.1",
+		Port:          1,
+		Name:          "testdb",
+		MaxOpenConns:  1,
+		LazyConnect:   true,
+		RetryAttempts: 3,
+		RetryBackoff:  20 * time.Millisecond,
+	}
+
+	db, err := New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err = db.Exec(ctx, "SELECT 1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all 3 attempts failed")
+	assert.GreaterOrEqual(t, time.Since(start), 2*cfg.RetryBackoff, "attempts are separated by RetryBackoff")
+	assert.False(t, db.connected.Load())
+
+	err = db.QueryRow(ctx, "SELECT 1").Scan()
+	assert.Contains(t, err.Error(), "all 3 attempts failed", "every call retries until the first successful ping")
+
+	_, err = db.Acquire(ctx)
+	assert.Error(t, err)
+	_, err = db.Begin(ctx)
+	assert.Error(t, err)
+}
+
+// TestNew_SessionSettings tests that session settings install an AfterConnect hook
+func TestNew_SessionSettings(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		User:        "testuser",
+		Host:        "127.0.0.1",
+		Port:        1,
+		Name:        "testdb",
+		LazyConnect: true,
+	}
+
+	db, err := New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	assert.Nil(t, db.Config().AfterConnect)
+
+	cfg.SessionSettings = map[string]string{"statement_timeout": "5s"}
+	db, err = New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	assert.NotNil(t, db.Config().AfterConnect)
+}
@@ -0,0 +1,63 @@
+package pgx_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/db/pg/pgtest"
+	"github.com/pure-golang/adapters/db/pg/pgx"
+)
+
+// TestNew_CredentialsRotation tests that the pool recovers after the password is rotated.
+func TestNew_CredentialsRotation(t *testing.T) {
+	postgres := pgtest.StartPostgres(t, nil)
+	cfg := postgres.PgxConfig(postgres.NewDatabase(t))
+	ctx := context.Background()
+
+	admin, err := pgx.New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = admin.Close() })
+	_, err = admin.Exec(ctx, `CREATE ROLE rotated LOGIN PASSWORD 'p1'`)
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = admin.Exec(context.Background(), `DROP ROLE rotated`) })
+
+	var password atomic.Value
+	password.Store("p1")
+	cfg.User = "rotated"
+	cfg.Password = ""
+	cfg.Credentials = pg.CredentialsFunc(func(context.Context) (pg.Credentials, error) {
+		return pg.Credentials{Password: password.Load().(string)}, nil
+	})
+	db, err := pgx.New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	conn, err := db.Acquire(ctx)
+	require.NoError(t, err)
+
+	_, err = admin.Exec(ctx, `ALTER ROLE rotated PASSWORD 'p2'`)
+	require.NoError(t, err)
+	password.Store("p2")
+	db.Reset()
+
+	// the first new connection fails with the old password and refreshes the credentials
+	require.Eventually(t, func() bool {
+		return db.Ping(ctx) == nil
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// the connection in use keeps working and is closed after release
+	var one int
+	require.NoError(t, conn.QueryRow(ctx, `SELECT 1`).Scan(&one))
+	conn.Release()
+
+	conn, err = db.Acquire(ctx)
+	require.NoError(t, err)
+	defer conn.Release()
+	assert.Equal(t, "p2", conn.Conn().Config().Password)
+}
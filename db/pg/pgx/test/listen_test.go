@@ -0,0 +1,74 @@
+package pgx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg/pgtest"
+	"github.com/pure-golang/adapters/db/pg/pgx"
+)
+
+type userEvent struct {
+	ID     int64  `json:"id"`
+	Action string `json:"action"`
+}
+
+// TestListenJSON tests that notifications are decoded and the subscription survives a terminated connection.
+func TestListenJSON(t *testing.T) {
+	pg := pgtest.StartPostgres(t, nil)
+	db, err := pgx.New(pg.PgxConfig(pg.NewDatabase(t)), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan userEvent, 10)
+	reconnected := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- pgx.ListenJSON(ctx, db, "user_events", func(_ context.Context, e userEvent) error {
+			events <- e
+			return nil
+		},
+			pgx.WithListenBackoff(10*time.Millisecond, 100*time.Millisecond),
+			pgx.WithOnReconnect(func(context.Context) { reconnected <- struct{}{} }),
+		)
+	}()
+
+	// notifications sent before LISTEN are not delivered, so retry until the first one arrives
+	require.Eventually(t, func() bool {
+		if err := db.NotifyJSON(ctx, "user_events", userEvent{ID: 1, Action: "created"}); err != nil {
+			return false
+		}
+		select {
+		case e := <-events:
+			return assert.Equal(t, userEvent{ID: 1, Action: "created"}, e)
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 10*time.Second, 10*time.Millisecond)
+
+	_, err = db.Exec(ctx, `SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE query LIKE 'LISTEN %'`)
+	require.NoError(t, err)
+	select {
+	case <-reconnected:
+	case <-time.After(10 * time.Second):
+		t.Fatal("listener did not reconnect")
+	}
+
+	require.NoError(t, db.NotifyJSON(ctx, "user_events", userEvent{ID: 2, Action: "deleted"}))
+	select {
+	case e := <-events:
+		assert.Equal(t, userEvent{ID: 2, Action: "deleted"}, e)
+	case <-time.After(10 * time.Second):
+		t.Fatal("notification after reconnect was not received")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
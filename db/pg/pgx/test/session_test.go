@@ -0,0 +1,33 @@
+package pgx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg/pgtest"
+	"github.com/pure-golang/adapters/db/pg/pgx"
+)
+
+// TestNew_SessionSettings tests that session settings are set on pooled connections.
+func TestNew_SessionSettings(t *testing.T) {
+	postgres := pgtest.StartPostgres(t, nil)
+	cfg := postgres.PgxConfig("")
+	cfg.SessionSettings = map[string]string{"statement_timeout": "1234ms", "timezone": "Europe/Moscow"}
+	ctx := context.Background()
+
+	db, err := pgx.New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	var timeout, timezone string
+	require.NoError(t, db.QueryRow(ctx, "SHOW statement_timeout").Scan(&timeout))
+	require.Equal(t, "1234ms", timeout)
+	require.NoError(t, db.QueryRow(ctx, "SHOW timezone").Scan(&timezone))
+	require.Equal(t, "Europe/Moscow", timezone)
+
+	cfg.SessionSettings = map[string]string{"bogus_setting": "1"}
+	_, err = pgx.New(cfg, nil)
+	require.ErrorContains(t, err, "failed to apply session settings")
+}
@@ -0,0 +1,140 @@
+package pg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	// DefaultMaxFingerprints — число отслеживаемых отпечатков запросов по умолчанию
+	DefaultMaxFingerprints = 500
+	// OtherFingerprint — значение атрибута db.query.fingerprint для запросов сверх MaxFingerprints
+	OtherFingerprint = "other"
+)
+
+// Ограничения кэша отпечатков: запросы в коде обычно константы, поэтому кэш по тексту запроса
+// избавляет от повторного разбора; запросы длиннее maxCachedQueryLen (например, с встроенными
+// значениями) не кэшируются
+const (
+	maxCachedQueries  = 10000
+	maxCachedQueryLen = 4096
+)
+
+// meterName — имя Meter для метрик запросов
+const meterName = "github.com/pure-golang/adapters/db/pg"
+
+// QueryMetricsOptions содержит настройки QueryMetrics
+type QueryMetricsOptions struct {
+	// MaxFingerprints — сколько различных отпечатков записываются под собственным значением;
+	// остальные записываются как OtherFingerprint. По умолчанию DefaultMaxFingerprints.
+	MaxFingerprints int
+	// MeterProvider для инструментов; по умолчанию otel.GetMeterProvider()
+	MeterProvider metric.MeterProvider
+}
+
+// QueryMetrics записывает метрики запросов по нормализованным отпечаткам (см. Fingerprint):
+//   - db.pg.queries_total — число запросов (атрибуты db.query.fingerprint, db.status: ok/error)
+//   - db.pg.query_duration_ms — гистограмма длительности запросов (атрибут db.query.fingerprint)
+//
+// Позволяет найти самые частые и медленные запросы без доступа к pg_stat_statements.
+// Число отпечатков ограничено MaxFingerprints: первые MaxFingerprints отпечатков записываются
+// под собственным значением, остальные — под OtherFingerprint. Один QueryMetrics можно
+// разделить между несколькими подключениями db/pg/pgx и db/pg/sqlx.
+type QueryMetrics struct {
+	maxFingerprints int
+	queries         metric.Int64Counter
+	duration        metric.Float64Histogram
+
+	mu           sync.RWMutex
+	fingerprints map[string]struct{}
+	cache        map[string]string // текст запроса → значение атрибута
+}
+
+// NewQueryMetrics создаёт QueryMetrics. Ошибка создания инструментов передаётся в otel.Handle,
+// и метрики не записываются.
+func NewQueryMetrics(opts *QueryMetricsOptions) *QueryMetrics {
+	if opts == nil {
+		opts = &QueryMetricsOptions{}
+	}
+	provider := opts.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	maxFingerprints := opts.MaxFingerprints
+	if maxFingerprints <= 0 {
+		maxFingerprints = DefaultMaxFingerprints
+	}
+
+	m := &QueryMetrics{
+		maxFingerprints: maxFingerprints,
+		fingerprints:    make(map[string]struct{}),
+		cache:           make(map[string]string),
+	}
+
+	meter := provider.Meter(meterName)
+	var err error
+	m.queries, err = meter.Int64Counter(
+		"db.pg.queries_total",
+		metric.WithDescription("Total number of PostgreSQL queries by fingerprint"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create queries counter"))
+		m.queries = noop.Int64Counter{}
+	}
+	m.duration, err = meter.Float64Histogram(
+		"db.pg.query_duration_ms",
+		metric.WithDescription("PostgreSQL query duration in milliseconds by fingerprint"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create query duration histogram"))
+		m.duration = noop.Float64Histogram{}
+	}
+	return m
+}
+
+// Record записывает выполненный запрос
+func (m *QueryMetrics) Record(ctx context.Context, query string, duration time.Duration, err error) {
+	fingerprint := attribute.String("db.query.fingerprint", m.label(query))
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	m.queries.Add(ctx, 1, metric.WithAttributes(fingerprint, attribute.String("db.status", status)))
+	m.duration.Record(ctx, float64(duration)/float64(time.Millisecond), metric.WithAttributes(fingerprint))
+}
+
+// label возвращает значение атрибута db.query.fingerprint для запроса
+func (m *QueryMetrics) label(query string) string {
+	m.mu.RLock()
+	label, ok := m.cache[query]
+	m.mu.RUnlock()
+	if ok {
+		return label
+	}
+
+	fingerprint := Fingerprint(query)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	label = fingerprint
+	if _, tracked := m.fingerprints[fingerprint]; !tracked {
+		if len(m.fingerprints) < m.maxFingerprints {
+			m.fingerprints[fingerprint] = struct{}{}
+		} else {
+			label = OtherFingerprint
+		}
+	}
+	if len(m.cache) < maxCachedQueries && len(query) <= maxCachedQueryLen {
+		m.cache[query] = label
+	}
+	return label
+}
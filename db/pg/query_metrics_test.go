@@ -0,0 +1,71 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectQueries returns db.pg.queries_total values by fingerprint and status
+func collectQueries(t *testing.T, reader *sdkmetric.ManualReader) map[[2]string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[[2]string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != "db.pg.queries_total" || !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				fingerprint, _ := dp.Attributes.Value("db.query.fingerprint")
+				status, _ := dp.Attributes.Value("db.status")
+				result[[2]string{fingerprint.AsString(), status.AsString()}] = dp.Value
+			}
+		}
+	}
+	return result
+}
+
+func TestQueryMetrics_Record(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	m := NewQueryMetrics(&QueryMetricsOptions{MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))})
+	ctx := context.Background()
+
+	m.Record(ctx, "SELECT * FROM users WHERE id = 1", time.Millisecond, nil)
+	m.Record(ctx, "SELECT * FROM users WHERE id = 2", time.Millisecond, nil)
+	m.Record(ctx, "SELECT * FROM users WHERE id = $1", time.Millisecond, errors.New("timeout"))
+
+	assert.Equal(t, map[[2]string]int64{
+		{"select * from users where id = ?", "ok"}:    2,
+		{"select * from users where id = ?", "error"}: 1,
+	}, collectQueries(t, reader))
+}
+
+func TestQueryMetrics_MaxFingerprints(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	m := NewQueryMetrics(&QueryMetricsOptions{
+		MaxFingerprints: 2,
+		MeterProvider:   sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	ctx := context.Background()
+
+	for _, query := range []string{"SELECT 1 FROM a", "SELECT 1 FROM b", "SELECT 1 FROM c", "SELECT 1 FROM d", "SELECT 2 FROM a"} {
+		m.Record(ctx, query, time.Millisecond, nil)
+	}
+
+	assert.Equal(t, map[[2]string]int64{
+		{"select ? from a", "ok"}: 2,
+		{"select ? from b", "ok"}: 1,
+		{OtherFingerprint, "ok"}:  2,
+	}, collectQueries(t, reader))
+}
@@ -0,0 +1,127 @@
+package pg
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxIdentifierLength — максимальная длина идентификатора PostgreSQL в байтах (NAMEDATALEN - 1);
+// более длинные имена сервер молча обрезает
+const MaxIdentifierLength = 63
+
+// ErrInvalidIdentifier возвращается для имени, не прошедшего [ValidateIdentifier]
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// QuoteIdentifier возвращает name в двойных кавычках с удвоенными кавычками внутри: результат
+// всегда один идентификатор, какие бы символы name ни содержал, и регистр букв сохраняется.
+//
+//	pg.QuoteIdentifier(`orders"; DROP TABLE users; --`) // "orders""; DROP TABLE users; --"
+//
+// Имена из внешнего ввода (схемы арендаторов, партиции) дополнительно проверяйте [ValidateIdentifier]
+// или используйте [SafeTableName].
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteLiteral возвращает строковый литерал s с удвоенными апострофами. Если s содержит обратную
+// косую черту, литерал записывается как E'...' с удвоенными \, поэтому результат не зависит
+// от standard_conforming_strings. Значения запросов передавайте через параметры ($1, $2, ...);
+// QuoteLiteral нужен там, где параметры не поддерживаются: DDL, SET, COMMENT ON.
+func QuoteLiteral(s string) string {
+	quoted := "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	if strings.Contains(s, `\`) {
+		return "E" + strings.ReplaceAll(quoted, `\`, `\\`)
+	}
+	return quoted
+}
+
+// ValidateIdentifier проверяет, что name — простое имя: начинается с латинской буквы или "_",
+// содержит только латинские буквы, цифры, "_" и "$" и не длиннее MaxIdentifierLength байт.
+// Ошибка оборачивает [ErrInvalidIdentifier].
+func ValidateIdentifier(name string) error {
+	switch {
+	case name == "":
+		return errors.Wrap(ErrInvalidIdentifier, "empty name")
+	case len(name) > MaxIdentifierLength:
+		return errors.Wrapf(ErrInvalidIdentifier, "%q is longer than %d bytes", name, MaxIdentifierLength)
+	}
+	for i := range len(name) {
+		ch := name[i]
+		switch {
+		case ch == '_', 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z':
+		case i > 0 && ('0' <= ch && ch <= '9' || ch == '$'):
+		default:
+			return errors.Wrapf(ErrInvalidIdentifier, "%q contains %q at position %d", name, ch, i)
+		}
+	}
+	return nil
+}
+
+// SafeTableName — проверенное имя таблицы с необязательной схемой. Создаётся только через
+// [NewSafeTableName] и [ParseTableName], поэтому значение можно подставлять в текст запроса:
+// String возвращает экранированное имя "schema"."table".
+//
+//	table, err := pg.NewSafeTableName("tenant_"+tenantID, "orders")
+//	query := "SELECT count(*) FROM " + table.String()
+type SafeTableName struct {
+	schema string
+	name   string
+}
+
+// NewSafeTableName проверяет schema и name через [ValidateIdentifier]; пустая schema — без схемы
+func NewSafeTableName(schema, name string) (SafeTableName, error) {
+	if schema != "" {
+		if err := ValidateIdentifier(schema); err != nil {
+			return SafeTableName{}, errors.Wrap(err, "invalid schema name")
+		}
+	}
+	if err := ValidateIdentifier(name); err != nil {
+		return SafeTableName{}, errors.Wrap(err, "invalid table name")
+	}
+	return SafeTableName{schema: schema, name: name}, nil
+}
+
+// ParseTableName разбирает имя вида "table" или "schema.table" без кавычек
+func ParseTableName(s string) (SafeTableName, error) {
+	schema, name, ok := strings.Cut(s, ".")
+	if !ok {
+		return NewSafeTableName("", s)
+	}
+	if schema == "" {
+		return SafeTableName{}, errors.Wrapf(ErrInvalidIdentifier, "empty schema in %q", s)
+	}
+	return NewSafeTableName(schema, name)
+}
+
+// Schema возвращает имя схемы без кавычек; пусто — схема не задана
+func (t SafeTableName) Schema() string {
+	return t.schema
+}
+
+// Name возвращает имя таблицы без кавычек
+func (t SafeTableName) Name() string {
+	return t.name
+}
+
+// IsZero сообщает, что имя не задано
+func (t SafeTableName) IsZero() bool {
+	return t.name == ""
+}
+
+// Partition возвращает имя партиции таблицы в той же схеме: name_suffix, например orders_2024_05.
+// Результат проверяется целиком, в том числе на MaxIdentifierLength.
+func (t SafeTableName) Partition(suffix string) (SafeTableName, error) {
+	if t.IsZero() {
+		return SafeTableName{}, errors.Wrap(ErrInvalidIdentifier, "partition of an empty table name")
+	}
+	return NewSafeTableName(t.schema, t.name+"_"+suffix)
+}
+
+// String возвращает экранированное имя для подстановки в запрос: "schema"."table" или "table"
+func (t SafeTableName) String() string {
+	if t.schema == "" {
+		return QuoteIdentifier(t.name)
+	}
+	return QuoteIdentifier(t.schema) + "." + QuoteIdentifier(t.name)
+}
@@ -0,0 +1,89 @@
+package pg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuoteIdentifier tests that quotes are doubled and the name stays a single identifier
+func TestQuoteIdentifier(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"users"`, QuoteIdentifier("users"))
+	assert.Equal(t, `"Users"`, QuoteIdentifier("Users"))
+	assert.Equal(t, `"a""b"`, QuoteIdentifier(`a"b`))
+	assert.Equal(t, `"x""; DROP TABLE users; --"`, QuoteIdentifier(`x"; DROP TABLE users; --`))
+}
+
+// TestQuoteLiteral tests apostrophe doubling and the escape string form for backslashes
+func TestQuoteLiteral(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `''`, QuoteLiteral(""))
+	assert.Equal(t, `'it''s'`, QuoteLiteral("it's"))
+	assert.Equal(t, `E'C:\\temp\\''x'`, QuoteLiteral(`C:\temp\'x`))
+}
+
+// TestValidateIdentifier tests accepted and rejected names
+func TestValidateIdentifier(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"users", "_tmp", "Orders2024", "tenant_42", "a$b", strings.Repeat("a", MaxIdentifierLength)} {
+		assert.NoError(t, ValidateIdentifier(name), name)
+	}
+	for _, name := range []string{"", "1users", "$a", "users;", "a b", `a"b`, "a.b", "таблица", "a\x00", strings.Repeat("a", MaxIdentifierLength+1)} {
+		assert.ErrorIs(t, ValidateIdentifier(name), ErrInvalidIdentifier, name)
+	}
+	assert.EqualError(t, ValidateIdentifier("users;"), `"users;" contains ';' at position 5: invalid identifier`)
+}
+
+// TestSafeTableName tests construction, parsing and quoting of table names
+func TestSafeTableName(t *testing.T) {
+	t.Parallel()
+
+	table, err := NewSafeTableName("tenant_1", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_1", table.Schema())
+	assert.Equal(t, "orders", table.Name())
+	assert.Equal(t, `"tenant_1"."orders"`, table.String())
+
+	table, err = ParseTableName("users")
+	require.NoError(t, err)
+	assert.Equal(t, `"users"`, table.String())
+
+	table, err = ParseTableName("public.Users")
+	require.NoError(t, err)
+	assert.Equal(t, `"public"."Users"`, table.String())
+
+	for _, s := range []string{"", ".users", "public.", "a.b.c", "users; DROP TABLE users", `"users"`} {
+		_, err := ParseTableName(s)
+		assert.ErrorIs(t, err, ErrInvalidIdentifier, s)
+	}
+	_, err = NewSafeTableName("bad schema", "users")
+	assert.ErrorContains(t, err, "invalid schema name")
+
+	assert.True(t, SafeTableName{}.IsZero())
+	assert.False(t, table.IsZero())
+}
+
+// TestSafeTableName_Partition tests partition names and their validation
+func TestSafeTableName_Partition(t *testing.T) {
+	t.Parallel()
+
+	table, err := ParseTableName("billing.events")
+	require.NoError(t, err)
+
+	partition, err := table.Partition("2024_05")
+	require.NoError(t, err)
+	assert.Equal(t, `"billing"."events_2024_05"`, partition.String())
+
+	_, err = table.Partition("2024-05")
+	assert.ErrorIs(t, err, ErrInvalidIdentifier)
+	_, err = table.Partition(strings.Repeat("x", MaxIdentifierLength))
+	assert.ErrorIs(t, err, ErrInvalidIdentifier)
+	_, err = SafeTableName{}.Partition("2024_05")
+	assert.ErrorIs(t, err, ErrInvalidIdentifier)
+}
@@ -0,0 +1,39 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Retry выполняет fn до attempts раз с паузой backoff между попытками; используется адаптерами
+// для проверки подключения (RetryAttempts/RetryBackoff). Значение attempts меньше 1 означает
+// одну попытку, и тогда ошибка fn возвращается без обёртки. Прерывается при отмене ctx.
+func Retry(ctx context.Context, attempts int, backoff time.Duration, fn func(ctx context.Context) error) error {
+	total := max(attempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= total; attempt++ {
+		err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt == total {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "retry aborted after %d attempts: %v", attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if total == 1 {
+		return err
+	}
+	return errors.Wrapf(err, "all %d attempts failed", total)
+}
@@ -0,0 +1,79 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetry_SucceedsAfterFailures tests that fn is retried until it succeeds
+func TestRetry_SucceedsAfterFailures(t *testing.T) {
+	t.Parallel()
+	calls := 0
+
+	err := Retry(context.Background(), 3, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+// TestRetry_AllAttemptsFail tests that the last error is wrapped after all attempts
+func TestRetry_AllAttemptsFail(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	errRefused := errors.New("connection refused")
+
+	err := Retry(context.Background(), 2, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		return errRefused
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errRefused)
+	assert.Contains(t, err.Error(), "all 2 attempts failed")
+	assert.Equal(t, 2, calls)
+}
+
+// TestRetry_SingleAttemptReturnsOriginalError tests that a single attempt returns the error of fn as is
+func TestRetry_SingleAttemptReturnsOriginalError(t *testing.T) {
+	t.Parallel()
+	errRefused := errors.New("connection refused")
+
+	for _, attempts := range []int{-1, 0, 1} {
+		calls := 0
+		err := Retry(context.Background(), attempts, time.Millisecond, func(ctx context.Context) error {
+			calls++
+			return errRefused
+		})
+
+		assert.Equal(t, errRefused, err)
+		assert.Equal(t, 1, calls)
+	}
+}
+
+// TestRetry_ContextCanceled tests that canceling ctx stops waiting for the next attempt
+func TestRetry_ContextCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	err := Retry(ctx, 10, time.Hour, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errors.New("connection refused")
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry aborted after 1 attempts")
+	assert.Equal(t, 1, calls)
+}
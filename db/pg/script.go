@@ -0,0 +1,208 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Statement — инструкция SQL-скрипта
+type Statement struct {
+	// SQL — текст инструкции без завершающей точки с запятой, начальных пробелов и комментариев
+	SQL string
+	// Offset — байтовое смещение начала инструкции в скрипте
+	Offset int
+	// Line и Column — строка и столбец (в символах) начала инструкции в скрипте, с 1
+	Line   int
+	Column int
+}
+
+// PositionAt переводит позицию в тексте инструкции (с 1, в символах — как Position в ошибке
+// PostgreSQL) в строку и столбец скрипта. Позиция вне инструкции приводится к её границам.
+func (s Statement) PositionAt(pos int) (line, column int) {
+	line, column = s.Line, s.Column
+	for _, r := range s.SQL {
+		if pos <= 1 {
+			break
+		}
+		pos--
+		if r == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+	return line, column
+}
+
+// SplitScript разбивает скрипт из нескольких инструкций по ";". Точка с запятой не разделяет
+// инструкции внутри строковых литералов, идентификаторов в кавычках, комментариев,
+// dollar-quoted тел ($$...$$, $fn$...$fn$ — функции, DO-блоки) и тел BEGIN ATOMIC ... END.
+// Пустые инструкции и инструкции из одних комментариев пропускаются.
+// Незакрытый литерал или комментарий — ошибка с его позицией.
+func SplitScript(script string) ([]Statement, error) {
+	var (
+		stmts    []Statement
+		pos      = scriptCursor{script: script, line: 1, column: 1}
+		start    = -1 // начало текущей инструкции; -1 — значимых токенов ещё не было
+		lastEnd  = 0  // конец последнего значимого токена текущей инструкции
+		prevWord string
+		atomic   int // глубина вложенности BEGIN ATOMIC ... END (CASE ... END внутри тела)
+	)
+	unterminated := func(i int, what string) error {
+		line, column := pos.at(i)
+		return errors.Errorf("unterminated %s at line %d, column %d", what, line, column)
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+				continue
+			}
+			i += end + 1
+			continue
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end, ok := skipBlockComment(script, i)
+			if !ok {
+				return nil, unterminated(i, "block comment")
+			}
+			i = end
+			continue
+		case c == ';' && atomic == 0:
+			if start >= 0 {
+				line, column := pos.at(start)
+				stmts = append(stmts, Statement{SQL: script[start:lastEnd], Offset: start, Line: line, Column: column})
+			}
+			start, prevWord = -1, ""
+			i++
+			continue
+		}
+
+		if start < 0 {
+			start = i
+		}
+		switch {
+		case c == '\'':
+			escapes := i > 0 && (script[i-1] == 'e' || script[i-1] == 'E') && (i == 1 || !isIdentPart(script[i-2]))
+			end, ok := skipString(script, i, escapes)
+			if !ok {
+				return nil, unterminated(i, "string literal")
+			}
+			i = end
+		case c == '"':
+			end, closed := i+1, false
+			for end < len(script) && !closed {
+				switch {
+				case script[end] != '"':
+					end++
+				case end+1 < len(script) && script[end+1] == '"':
+					end += 2
+				default:
+					end++
+					closed = true
+				}
+			}
+			if !closed {
+				return nil, unterminated(i, "quoted identifier")
+			}
+			i = end
+		case c == '$':
+			end, ok, terminated := dollarQuoteEnd(script, i)
+			switch {
+			case ok && !terminated:
+				return nil, unterminated(i, "dollar-quoted string")
+			case ok:
+				i = end
+			default:
+				i++
+			}
+		case isIdentStart(c):
+			end := i
+			for end < len(script) && isIdentPart(script[end]) {
+				end++
+			}
+			word := strings.ToLower(script[i:end])
+			switch {
+			case word == "atomic" && prevWord == "begin":
+				atomic++
+			case word == "case" && atomic > 0:
+				atomic++
+			case word == "end" && atomic > 0:
+				atomic--
+			}
+			prevWord = word
+			i = end
+		default:
+			i++
+		}
+		lastEnd = i
+	}
+
+	if start >= 0 {
+		line, column := pos.at(start)
+		stmts = append(stmts, Statement{SQL: script[start:lastEnd], Offset: start, Line: line, Column: column})
+	}
+	return stmts, nil
+}
+
+// scriptCursor вычисляет строку и столбец для возрастающих смещений в скрипте за линейное время
+type scriptCursor struct {
+	script string
+	offset int
+	line   int
+	column int
+}
+
+// at возвращает строку и столбец смещения offset. Курсор продвигается от предыдущего смещения;
+// меньшее смещение вычисляется заново от начала скрипта.
+func (c *scriptCursor) at(offset int) (line, column int) {
+	if offset < c.offset {
+		c.offset, c.line, c.column = 0, 1, 1
+	}
+	for c.offset < offset {
+		r, size := utf8.DecodeRuneInString(c.script[c.offset:])
+		c.offset += size
+		if r == '\n' {
+			c.line++
+			c.column = 1
+			continue
+		}
+		c.column++
+	}
+	return c.line, c.column
+}
+
+// ScriptError — ошибка выполнения инструкции скрипта
+type ScriptError struct {
+	Index     int // номер инструкции в скрипте, с 0
+	Statement Statement
+	// Line и Column — позиция ошибки в скрипте: позиция, указанная сервером, либо начало инструкции
+	Line   int
+	Column int
+	Err    error
+}
+
+// NewScriptError создаёт ошибку инструкции index; position — позиция ошибки в тексте инструкции
+// из ответа сервера (с 1, в символах), 0 — неизвестна
+func NewScriptError(index int, stmt Statement, err error, position int) *ScriptError {
+	line, column := stmt.PositionAt(position)
+	return &ScriptError{Index: index, Statement: stmt, Line: line, Column: column, Err: err}
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("statement %d at line %d, column %d: %v", e.Index, e.Line, e.Column, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
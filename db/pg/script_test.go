@@ -0,0 +1,149 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitScript(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "simple statements",
+			script: "CREATE TABLE a (id int);\nINSERT INTO a VALUES (1);\n",
+			want:   []string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			name:   "no trailing semicolon",
+			script: "SELECT 1; SELECT 2",
+			want:   []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:   "empty statements and comments",
+			script: ";; -- header\n/* only a comment */;\nSELECT 1; -- trailing\n",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "semicolons in literals and identifiers",
+			script: `INSERT INTO "a;b" VALUES ('x;y', E'it\'s;', 'it''s;'); SELECT 2`,
+			want:   []string{`INSERT INTO "a;b" VALUES ('x;y', E'it\'s;', 'it''s;')`, "SELECT 2"},
+		},
+		{
+			name:   "semicolons in comments",
+			script: "SELECT 1 -- a; b\n/* c; /* nested; */ d; */ + 1; SELECT 2",
+			want:   []string{"SELECT 1 -- a; b\n/* c; /* nested; */ d; */ + 1", "SELECT 2"},
+		},
+		{
+			name: "dollar-quoted function body",
+			script: `CREATE FUNCTION f() RETURNS int AS $$
+BEGIN
+  PERFORM 1;
+  RETURN 1;
+END;
+$$ LANGUAGE plpgsql;
+DO $body$ BEGIN RAISE NOTICE 'a;b'; END $body$;`,
+			want: []string{
+				"CREATE FUNCTION f() RETURNS int AS $$\nBEGIN\n  PERFORM 1;\n  RETURN 1;\nEND;\n$$ LANGUAGE plpgsql",
+				"DO $body$ BEGIN RAISE NOTICE 'a;b'; END $body$",
+			},
+		},
+		{
+			name:   "parameters and identifiers with dollar",
+			script: "SELECT $1, a$b FROM t; SELECT 2",
+			want:   []string{"SELECT $1, a$b FROM t", "SELECT 2"},
+		},
+		{
+			name: "begin atomic body",
+			script: `CREATE FUNCTION f(x int) RETURNS int LANGUAGE sql BEGIN ATOMIC
+  SELECT CASE WHEN x > 0 THEN 1 ELSE 0 END;
+  SELECT 2;
+END; SELECT 3`,
+			want: []string{
+				"CREATE FUNCTION f(x int) RETURNS int LANGUAGE sql BEGIN ATOMIC\n  SELECT CASE WHEN x > 0 THEN 1 ELSE 0 END;\n  SELECT 2;\nEND",
+				"SELECT 3",
+			},
+		},
+		{
+			name:   "empty script",
+			script: "  \n-- nothing\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stmts, err := SplitScript(tt.script)
+			require.NoError(t, err)
+			var got []string
+			for _, s := range stmts {
+				got = append(got, s.SQL)
+				assert.Equal(t, s.SQL, tt.script[s.Offset:s.Offset+len(s.SQL)])
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitScript_Positions(t *testing.T) {
+	t.Parallel()
+	script := "-- seed\nSELECT 1;\n  /* users */ INSERT INTO users\n  VALUES ('ы'); SELECT 3"
+
+	stmts, err := SplitScript(script)
+	require.NoError(t, err)
+	require.Len(t, stmts, 3)
+
+	assert.Equal(t, Statement{SQL: "SELECT 1", Offset: 8, Line: 2, Column: 1}, stmts[0])
+	assert.Equal(t, 3, stmts[1].Line)
+	assert.Equal(t, 15, stmts[1].Column)
+	assert.Equal(t, 4, stmts[2].Line)
+	assert.Equal(t, 17, stmts[2].Column, "column is counted in characters")
+
+	// Позиция 21 в тексте второй инструкции — "VALUES" на следующей строке
+	line, column := stmts[1].PositionAt(21)
+	assert.Equal(t, 4, line)
+	assert.Equal(t, 3, column)
+	line, column = stmts[1].PositionAt(0)
+	assert.Equal(t, 3, line)
+	assert.Equal(t, 15, column)
+}
+
+func TestSplitScript_Unterminated(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{name: "string", script: "SELECT 1;\nSELECT 'abc", want: "unterminated string literal at line 2, column 8"},
+		{name: "dollar quote", script: "DO $$ BEGIN", want: "unterminated dollar-quoted string at line 1, column 4"},
+		{name: "comment", script: "SELECT 1 /* /* */", want: "unterminated block comment at line 1, column 10"},
+		{name: "identifier", script: `SELECT "a`, want: "unterminated quoted identifier at line 1, column 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := SplitScript(tt.script)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestScriptError(t *testing.T) {
+	t.Parallel()
+	cause := errors.New("syntax error")
+	stmt := Statement{SQL: "SELECT\n  foo bar", Line: 5, Column: 3}
+
+	err := NewScriptError(2, stmt, cause, 12)
+
+	assert.Equal(t, 6, err.Line)
+	assert.Equal(t, 5, err.Column)
+	assert.EqualError(t, err, "statement 2 at line 6, column 5: syntax error")
+	assert.ErrorIs(t, err, cause)
+}
@@ -0,0 +1,32 @@
+package pg
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SessionSettingsQuery возвращает запрос, устанавливающий параметры сессии (GUC) на соединении,
+// и его аргументы; для пустых settings возвращается пустой запрос.
+//
+// Параметры задаются через set_config(name, value, false) с аргументами, а не SET: значения не нужно
+// экранировать, и все параметры устанавливаются одним запросом. Имена сортируются, чтобы порядок
+// применения не зависел от обхода map. Неизвестный параметр или недопустимое значение —
+// ошибка PostgreSQL при выполнении запроса.
+//
+//	query, args := pg.SessionSettingsQuery(map[string]string{"statement_timeout": "5s", "timezone": "UTC"})
+//	// SELECT set_config($1, $2, false), set_config($3, $4, false)
+func SessionSettingsQuery(settings map[string]string) (string, []any) {
+	if len(settings) == 0 {
+		return "", nil
+	}
+
+	calls := make([]string, 0, len(settings))
+	args := make([]any, 0, 2*len(settings))
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		calls = append(calls, fmt.Sprintf("set_config($%d, $%d, false)", len(args)+1, len(args)+2))
+		args = append(args, name, settings[name])
+	}
+	return "SELECT " + strings.Join(calls, ", "), args
+}
@@ -0,0 +1,24 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSessionSettingsQuery tests that settings are applied with one set_config call per name in sorted order
+func TestSessionSettingsQuery(t *testing.T) {
+	t.Parallel()
+
+	query, args := SessionSettingsQuery(nil)
+	assert.Empty(t, query)
+	assert.Nil(t, args)
+
+	query, args = SessionSettingsQuery(map[string]string{
+		"timezone":          "Europe/Moscow",
+		"application_name":  "orders",
+		"statement_timeout": "5s",
+	})
+	assert.Equal(t, "SELECT set_config($1, $2, false), set_config($3, $4, false), set_config($5, $6, false)", query)
+	assert.Equal(t, []any{"application_name", "orders", "statement_timeout", "5s", "timezone", "Europe/Moscow"}, args)
+}
@@ -0,0 +1,115 @@
+package pg
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Теги комментария, заполняемые SQLCommenter
+const (
+	CommentTagApplication = "application"
+	CommentTagTraceparent = "traceparent"
+)
+
+// SQLCommenter добавляет к запросам комментарий в формате sqlcommenter (google.github.io/sqlcommenter):
+//
+//	SELECT * FROM users WHERE id = $1 /*application='billing',route='%2Fapi.Users%2FGet',traceparent='00-…-01'*/
+//
+// Комментарий виден в pg_stat_activity.query, журнале медленных запросов (log_min_duration_statement)
+// и auto_explain, что позволяет DBA найти трейс и обработчик запроса. Теги собираются из контекста:
+// traceparent — из спана OpenTelemetry, остальные — из Tags и ContextWithCommentTags.
+// Ключи и значения кодируются как в URL, теги сортируются по ключу.
+//
+// Комментарий не влияет на метрики запросов: [Fingerprint] отбрасывает комментарии.
+type SQLCommenter struct {
+	// Application — значение тега application, например имя сервиса; пусто — тег не добавляется
+	Application string
+	// DisableTraceparent отключает тег traceparent с идентификаторами трейса и спана
+	DisableTraceparent bool
+	// Tags возвращает теги запроса из контекста, например route из метода gRPC или маршрута HTTP;
+	// теги ContextWithCommentTags имеют приоритет
+	Tags func(ctx context.Context) map[string]string
+}
+
+// commentTagsKey — ключ контекста с тегами комментария
+type commentTagsKey struct{}
+
+// ContextWithCommentTags возвращает контекст с тегами комментария запросов,
+// дополняющими теги родительского контекста
+func ContextWithCommentTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := maps.Clone(CommentTagsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(tags))
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, commentTagsKey{}, merged)
+}
+
+// CommentTagsFromContext возвращает теги комментария из контекста; результат нельзя изменять
+func CommentTagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(commentTagsKey{}).(map[string]string)
+	return tags
+}
+
+// Comment возвращает комментарий /*…*/ с тегами для контекста ctx; пустую строку, если тегов нет
+func (c *SQLCommenter) Comment(ctx context.Context) string {
+	tags := make(map[string]string)
+	if c.Tags != nil {
+		maps.Copy(tags, c.Tags(ctx))
+	}
+	maps.Copy(tags, CommentTagsFromContext(ctx))
+	if c.Application != "" {
+		tags[CommentTagApplication] = c.Application
+	}
+	if !c.DisableTraceparent {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			tags[CommentTagTraceparent] = "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-" + sc.TraceFlags().String()
+		}
+	}
+
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		if tags[key] == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(commentEscape(key))
+		b.WriteString("='")
+		b.WriteString(commentEscape(tags[key]))
+		b.WriteByte('\'')
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "/*" + b.String() + "*/"
+}
+
+// Apply добавляет комментарий в конец запроса, перед завершающей ";". Запросы, уже содержащие
+// комментарий /*, не изменяются (как требует спецификация sqlcommenter). Для nil возвращает запрос без изменений.
+func (c *SQLCommenter) Apply(ctx context.Context, query string) string {
+	if c == nil || strings.Contains(query, "/*") {
+		return query
+	}
+	comment := c.Comment(ctx)
+	if comment == "" {
+		return query
+	}
+	body := strings.TrimRight(query, " \t\r\n")
+	if trimmed := strings.TrimSuffix(body, ";"); trimmed != body {
+		return trimmed + " " + comment + ";"
+	}
+	return body + " " + comment
+}
+
+// commentEscape кодирует ключ или значение тега как в URL; пробел кодируется как %20.
+// Результат не содержит кавычек, "*/" и ":", поэтому не ломает комментарий и именованные параметры sqlx.
+func commentEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
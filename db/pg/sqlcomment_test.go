@@ -0,0 +1,99 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestSQLCommenter_Apply tests comment formatting and placement.
+func TestSQLCommenter_Apply(t *testing.T) {
+	t.Parallel()
+	traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	spanCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	const traceparent = "traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'"
+
+	tests := []struct {
+		name      string
+		commenter *SQLCommenter
+		ctx       context.Context
+		query     string
+		want      string
+	}{
+		{
+			name:      "nil commenter",
+			commenter: nil,
+			ctx:       spanCtx,
+			query:     "SELECT 1",
+			want:      "SELECT 1",
+		},
+		{
+			name:      "no tags",
+			commenter: &SQLCommenter{},
+			ctx:       context.Background(),
+			query:     "SELECT 1",
+			want:      "SELECT 1",
+		},
+		{
+			name:      "traceparent",
+			commenter: &SQLCommenter{},
+			ctx:       spanCtx,
+			query:     "SELECT 1",
+			want:      "SELECT 1 /*" + traceparent + "*/",
+		},
+		{
+			name:      "disabled traceparent",
+			commenter: &SQLCommenter{DisableTraceparent: true, Application: "billing"},
+			ctx:       spanCtx,
+			query:     "SELECT 1",
+			want:      "SELECT 1 /*application='billing'*/",
+		},
+		{
+			name:      "sorted and escaped tags before semicolon",
+			commenter: &SQLCommenter{Application: "billing"},
+			ctx:       ContextWithCommentTags(spanCtx, map[string]string{"route": "/api.Users/Get", "user": "O'Brien :id"}),
+			query:     "SELECT 1;\n",
+			want:      "SELECT 1 /*application='billing',route='%2Fapi.Users%2FGet'," + traceparent + ",user='O%27Brien%20%3Aid'*/;",
+		},
+		{
+			name: "context tags override Tags",
+			commenter: &SQLCommenter{Tags: func(context.Context) map[string]string {
+				return map[string]string{"route": "default", "action": "list"}
+			}},
+			ctx:   ContextWithCommentTags(context.Background(), map[string]string{"route": "override", "empty": ""}),
+			query: "SELECT 1",
+			want:  "SELECT 1 /*action='list',route='override'*/",
+		},
+		{
+			name:      "existing comment",
+			commenter: &SQLCommenter{Application: "billing"},
+			ctx:       context.Background(),
+			query:     "SELECT 1 /* report */",
+			want:      "SELECT 1 /* report */",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.commenter.Apply(tt.ctx, tt.query))
+		})
+	}
+}
+
+// TestContextWithCommentTags tests that tags are merged with the parent context.
+func TestContextWithCommentTags(t *testing.T) {
+	t.Parallel()
+	parent := ContextWithCommentTags(context.Background(), map[string]string{"route": "a", "job": "sync"})
+	child := ContextWithCommentTags(parent, map[string]string{"route": "b"})
+
+	assert.Equal(t, map[string]string{"route": "a", "job": "sync"}, CommentTagsFromContext(parent))
+	assert.Equal(t, map[string]string{"route": "b", "job": "sync"}, CommentTagsFromContext(child))
+	assert.Nil(t, CommentTagsFromContext(context.Background()))
+}
@@ -0,0 +1,106 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// CancelReason — причина отмены запроса
+type CancelReason string
+
+const (
+	// CancelReasonDeadline — истёк дедлайн контекста: QueryTimeout или дедлайн вызывающего кода
+	CancelReasonDeadline CancelReason = "deadline"
+	// CancelReasonCanceled — контекст отменён вызывающим кодом (например, клиент прервал запрос)
+	CancelReasonCanceled CancelReason = "canceled"
+	// CancelReasonStatementTimeout — запрос прерван сервером по statement_timeout
+	CancelReasonStatementTimeout CancelReason = "statement_timeout"
+	// CancelReasonCancelRequest — запрос прерван извне, например pg_cancel_backend
+	CancelReasonCancelRequest CancelReason = "cancel_request"
+)
+
+// QueryCancelledError — ошибка отменённого запроса с данными для диагностики вместо
+// "canceling statement due to user request". Возвращается внутри ошибок Get, Select, Exec,
+// Query, NamedExec и NamedQuery; проверяется через errors.As. errors.Is находит исходную
+// ошибку драйвера и ошибку контекста (context.DeadlineExceeded или context.Canceled).
+type QueryCancelledError struct {
+	Operation   string        // операция: Get, Select, Exec, ...
+	Fingerprint string        // нормализованный текст запроса без литералов (pg.Fingerprint)
+	Reason      CancelReason  // причина отмены
+	Elapsed     time.Duration // время выполнения до отмены
+	// Budget — время от начала запроса до дедлайна контекста; 0 — дедлайна не было.
+	// Меньше QueryTimeout, если дедлайн вызывающего кода наступил раньше
+	Budget       time.Duration
+	QueryTimeout time.Duration // Config.QueryTimeout
+	Err          error         // исходная ошибка драйвера
+	ctxErr       error
+}
+
+func (e *QueryCancelledError) Error() string {
+	return fmt.Sprintf("%s query cancelled (%s) after %s, budget %s, query timeout %s: %s: %v",
+		e.Operation, e.Reason, e.Elapsed.Round(time.Millisecond), e.Budget.Round(time.Millisecond),
+		e.QueryTimeout, e.Fingerprint, e.Err)
+}
+
+func (e *QueryCancelledError) Unwrap() []error {
+	if e.ctxErr != nil && !errors.Is(e.Err, e.ctxErr) {
+		return []error{e.Err, e.ctxErr}
+	}
+	return []error{e.Err}
+}
+
+// diagnoseCancellation заменяет ошибку отменённого запроса на *QueryCancelledError
+// и вызывает Config.OnQueryCancelled; остальные ошибки возвращаются без изменений.
+// ctx — контекст выполнения запроса с применённым QueryTimeout.
+func diagnoseCancellation(ctx context.Context, cfg Config, operation, query string, start time.Time, err error) error {
+	if err == nil {
+		return nil
+	}
+	reason, ok := cancelReason(ctx, err)
+	if !ok {
+		return err
+	}
+
+	cancelled := &QueryCancelledError{
+		Operation:    operation,
+		Fingerprint:  pg.Fingerprint(query),
+		Reason:       reason,
+		Elapsed:      time.Since(start),
+		QueryTimeout: cfg.QueryTimeout,
+		Err:          err,
+		ctxErr:       ctx.Err(),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		cancelled.Budget = deadline.Sub(start)
+	}
+	if cfg.OnQueryCancelled != nil {
+		cfg.OnQueryCancelled(ctx, cancelled)
+	}
+	return cancelled
+}
+
+// cancelReason определяет причину отмены по контексту запроса и коду ошибки PostgreSQL
+func cancelReason(ctx context.Context, err error) (CancelReason, bool) {
+	switch ctxErr := ctx.Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return CancelReasonDeadline, true
+	case ctxErr != nil:
+		return CancelReasonCanceled, true
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != QueryCanceledCode {
+		return "", false
+	}
+	if strings.Contains(pqErr.Message, "statement timeout") {
+		return CancelReasonStatementTimeout, true
+	}
+	return CancelReasonCancelRequest, true
+}
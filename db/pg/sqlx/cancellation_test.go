@@ -0,0 +1,89 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiagnoseCancellation tests detection of cancelled queries and the reported diagnostics.
+func TestDiagnoseCancellation(t *testing.T) {
+	t.Parallel()
+
+	userCancel := &pq.Error{Code: QueryCanceledCode, Message: "canceling statement due to user request"}
+	statementTimeout := &pq.Error{Code: QueryCanceledCode, Message: "canceling statement due to statement timeout"}
+
+	deadlineCtx, cancelDeadline := context.WithDeadline(context.Background(), time.Now().Add(-time.Millisecond))
+	defer cancelDeadline()
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		reason CancelReason
+	}{
+		{name: "deadline", ctx: deadlineCtx, err: userCancel, reason: CancelReasonDeadline},
+		{name: "canceled", ctx: canceledCtx, err: context.Canceled, reason: CancelReasonCanceled},
+		{name: "statement timeout", ctx: context.Background(), err: statementTimeout, reason: CancelReasonStatementTimeout},
+		{name: "cancel request", ctx: context.Background(), err: errors.WithStack(userCancel), reason: CancelReasonCancelRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var hooked *QueryCancelledError
+			cfg := Config{
+				QueryTimeout:     time.Second,
+				OnQueryCancelled: func(_ context.Context, err *QueryCancelledError) { hooked = err },
+			}
+
+			err := diagnoseCancellation(tt.ctx, cfg, "Select", "SELECT * FROM users WHERE name = 'bob'", time.Now(), tt.err)
+
+			var cancelled *QueryCancelledError
+			require.ErrorAs(t, err, &cancelled)
+			assert.Same(t, cancelled, hooked)
+			assert.Equal(t, tt.reason, cancelled.Reason)
+			assert.Equal(t, "Select", cancelled.Operation)
+			assert.Equal(t, "select * from users where name = ?", cancelled.Fingerprint)
+			assert.Equal(t, time.Second, cancelled.QueryTimeout)
+			assert.ErrorIs(t, err, tt.err)
+			if ctxErr := tt.ctx.Err(); ctxErr != nil {
+				assert.ErrorIs(t, err, ctxErr)
+			}
+			assert.Contains(t, err.Error(), "Select query cancelled ("+string(tt.reason)+")")
+		})
+	}
+}
+
+// TestDiagnoseCancellation_Passthrough tests that other errors are returned unchanged.
+func TestDiagnoseCancellation_Passthrough(t *testing.T) {
+	t.Parallel()
+	cfg := Config{OnQueryCancelled: func(context.Context, *QueryCancelledError) { t.Fatal("unexpected hook call") }}
+
+	assert.NoError(t, diagnoseCancellation(context.Background(), cfg, "Get", "SELECT 1", time.Now(), nil))
+
+	uniqueErr := &pq.Error{Code: UniqueViolationCode}
+	assert.Same(t, uniqueErr, diagnoseCancellation(context.Background(), cfg, "Exec", "INSERT", time.Now(), uniqueErr))
+}
+
+// TestDiagnoseCancellation_Budget tests that the budget is measured to the context deadline.
+func TestDiagnoseCancellation_Budget(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	ctx, cancel := context.WithDeadline(context.Background(), start.Add(49*time.Millisecond))
+	defer cancel()
+	<-ctx.Done()
+
+	err := diagnoseCancellation(ctx, Config{QueryTimeout: time.Second}, "Get", "SELECT pg_sleep(1)", start, context.DeadlineExceeded)
+
+	var cancelled *QueryCancelledError
+	require.ErrorAs(t, err, &cancelled)
+	assert.Equal(t, 49*time.Millisecond, cancelled.Budget)
+	assert.GreaterOrEqual(t, cancelled.Elapsed, cancelled.Budget)
+}
@@ -1,13 +1,18 @@
 package sqlx
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
 
 // Config содержит параметры подключения к PostgreSQL
 type Config struct {
 	Host            string        `envconfig:"POSTGRES_HOST" required:"true"`
 	Port            int           `envconfig:"POSTGRES_PORT" default:"5432"`
 	User            string        `envconfig:"POSTGRES_USER" required:"true"`
-	Password        string        `envconfig:"POSTGRES_PASSWORD" required:"true"`
+	Password        string        `envconfig:"POSTGRES_PASSWORD"`
 	Database        string        `envconfig:"POSTGRES_DB" required:"true"`
 	SSLMode         string        `envconfig:"POSTGRES_SSLMODE" default:"disable"`
 	ConnectTimeout  int           `envconfig:"POSTGRES_CONNECT_TIMEOUT" default:"5"`
@@ -16,4 +21,52 @@ type Config struct {
 	ConnMaxLifetime time.Duration `envconfig:"POSTGRES_CONN_MAX_LIFETIME" default:"30m"`
 	ConnMaxIdleTime time.Duration `envconfig:"POSTGRES_CONN_MAX_IDLE_TIME" default:"10m"`
 	QueryTimeout    time.Duration `envconfig:"POSTGRES_QUERY_TIMEOUT" default:"10s"`
+	// RetryAttempts — число попыток подключения (ping); значение меньше 1 означает одну попытку
+	RetryAttempts int `envconfig:"POSTGRES_RETRY_ATTEMPTS" default:"1"`
+	// RetryBackoff — пауза между попытками подключения
+	RetryBackoff time.Duration `envconfig:"POSTGRES_RETRY_BACKOFF" default:"1s"`
+	// LazyConnect откладывает подключение до первого запроса: Connect не проверяет доступность БД
+	LazyConnect bool `envconfig:"POSTGRES_LAZY_CONNECT" default:"false"`
+	// MinIdleConns — число соединений, открываемых заранее при подключении (прогрев пула), чтобы первые
+	// запросы после деплоя не ждали установки соединений; ограничено MaxIdleConns и MaxOpenConns
+	MinIdleConns int `envconfig:"POSTGRES_MIN_IDLE_CONNS" default:"0"`
+	// WarmupQueries выполняются на каждом прогретом соединении (например, SET, PREPARE горячих запросов
+	// или чтение справочников в кэш PostgreSQL); ошибка запроса прерывает подключение
+	WarmupQueries []string `ignored:"true"`
+	// SessionSettings — параметры сессии (GUC), устанавливаемые на каждом новом соединении пула, например
+	// "statement_timeout:5s,lock_timeout:1s,application_name:orders" (см. pg.SessionSettingsQuery);
+	// параметр, отклонённый PostgreSQL, — ошибка подключения
+	SessionSettings map[string]string `envconfig:"POSTGRES_SESSION_SETTINGS"`
+	// LogQueries включает логирование всех запросов на уровне Debug
+	LogQueries bool `envconfig:"POSTGRES_LOG_QUERIES" default:"false"`
+	// LogQueryArgs добавляет в лог описание аргументов запроса: тип и длину без значений
+	LogQueryArgs bool `envconfig:"POSTGRES_LOG_QUERY_ARGS" default:"false"`
+	// SlowQueryThreshold — порог длительности, начиная с которого запрос логируется на уровне Warn; 0 — отключено
+	SlowQueryThreshold time.Duration `envconfig:"POSTGRES_SLOW_QUERY_THRESHOLD" default:"0"`
+	// DevExplain повторно выполняет медленные запросы под EXPLAIN (ANALYZE, BUFFERS) в read-only транзакции
+	// и логирует план. Удваивает время медленных запросов — только для разработки
+	DevExplain bool `envconfig:"POSTGRES_DEV_EXPLAIN" default:"false"`
+	// DevReadOnlyGuard отклоняет INSERT/UPDATE/DELETE/MERGE/TRUNCATE в read-only транзакциях (RunReadTx)
+	// до отправки в БД, находя случайные записи в коде для реплик. Разбор запроса упрощённый — только для разработки
+	DevReadOnlyGuard bool `envconfig:"POSTGRES_DEV_READ_ONLY_GUARD" default:"false"`
+	// StrictMapping включает проверку соответствия колонок результата и полей структуры в Get и Select:
+	// колонки без поля и поля без колонки возвращаются в *MappingError вместо частичного заполнения
+	StrictMapping bool `envconfig:"POSTGRES_STRICT_MAPPING" default:"false"`
+	// QueryMetrics записывает счётчики и гистограммы длительности запросов по нормализованным
+	// отпечаткам (см. pg.NewQueryMetrics); nil — метрики запросов не записываются
+	QueryMetrics *pg.QueryMetrics `ignored:"true"`
+	// Credentials — источник сменяемых учётных данных (например, Vault) вместо Password;
+	// User используется, если провайдер не вернул пользователя
+	Credentials pg.CredentialsProvider `ignored:"true"`
+	// SQLCommenter добавляет к запросам комментарий sqlcommenter с traceparent и тегами из контекста
+	// для поиска запросов в pg_stat_activity и журнале медленных запросов (см. pg.SQLCommenter);
+	// nil — запросы отправляются без изменений
+	SQLCommenter *pg.SQLCommenter `ignored:"true"`
+	// OnQueryCancelled вызывается для каждого запроса, отменённого по таймауту, отмене контекста
+	// или на стороне сервера (statement_timeout, pg_cancel_backend), например для записи метрик
+	// и событий; ctx запроса к этому моменту уже отменён. nil — ошибка только дополняется диагностикой (см. QueryCancelledError)
+	OnQueryCancelled func(ctx context.Context, err *QueryCancelledError) `ignored:"true"`
+	// TxHooks — обработчики начала, фиксации и отката транзакций RunTx и RunReadTx
+	// (например, метрики длительности транзакций и доли откатов), см. TxHooks
+	TxHooks TxHooks `ignored:"true"`
 }
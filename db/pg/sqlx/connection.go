@@ -2,21 +2,38 @@ package sqlx
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // Стандартный драйвер PostgreSQL
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/pure-golang/adapters/db/pg"
 )
 
 // Connection представляет соединение с базой данных PostgreSQL через sqlx
 type Connection struct {
 	*sqlx.DB
 	cfg Config
+
+	// connected и connectMu используются в режиме LazyConnect для проверки подключения при первом запросе
+	connected atomic.Bool
+	connectMu sync.Mutex
+
+	// warmedConns — число соединений, прогретых при подключении
+	warmedConns atomic.Int32
 }
 
-// Connect создает новое соединение с базой данных PostgreSQL
+// defaultMaxIdleConns — лимит свободных соединений database/sql по умолчанию
+const defaultMaxIdleConns = 2
+
+// Connect создает новое соединение с базой данных PostgreSQL.
+// Проверка подключения повторяется до cfg.RetryAttempts раз с паузой cfg.RetryBackoff.
+// В режиме cfg.LazyConnect проверка откладывается до первого запроса.
 func Connect(ctx context.Context, cfg Config) (*Connection, error) {
 	ctx, span := tracer.Start(ctx, "sqlx.Connect")
 	defer span.End()
@@ -30,8 +47,8 @@ func Connect(ctx context.Context, cfg Config) (*Connection, error) {
 	)
 
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
+		"host=%s port=%d dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.Database, cfg.SSLMode,
 	)
 
 	if cfg.ConnectTimeout > 0 {
@@ -40,10 +57,25 @@ func Connect(ctx context.Context, cfg Config) (*Connection, error) {
 
 	dsn += " application_name=sqlx"
 
-	db, err := sqlx.ConnectContext(ctx, "postgres", dsn)
-	if err != nil {
-		span.RecordError(err)
-		return nil, errors.Wrap(err, "failed to connect to PostgreSQL")
+	var db *sqlx.DB
+	if cfg.Credentials != nil {
+		// Учётные данные запрашиваются при каждом новом соединении
+		connector := newCredentialsConnector(dsn, cfg.Credentials, cfg.User)
+		db = sqlx.NewDb(sql.OpenDB(withSessionSettings(connector, cfg.SessionSettings)), "postgres")
+	} else if len(cfg.SessionSettings) > 0 {
+		connector, err := pq.NewConnector(fmt.Sprintf("%s user=%s password=%s", dsn, cfg.User, cfg.Password))
+		if err != nil {
+			span.RecordError(err)
+			return nil, errors.Wrap(err, "failed to connect to PostgreSQL")
+		}
+		db = sqlx.NewDb(sql.OpenDB(withSessionSettings(connector, cfg.SessionSettings)), "postgres")
+	} else {
+		var err error
+		db, err = sqlx.Open("postgres", fmt.Sprintf("%s user=%s password=%s", dsn, cfg.User, cfg.Password))
+		if err != nil {
+			span.RecordError(err)
+			return nil, errors.Wrap(err, "failed to connect to PostgreSQL")
+		}
 	}
 
 	if cfg.MaxOpenConns > 0 {
@@ -66,16 +98,143 @@ func Connect(ctx context.Context, cfg Config) (*Connection, error) {
 		span.SetAttributes(attribute.String("db.conn_max_idle_time", cfg.ConnMaxIdleTime.String()))
 	}
 
+	conn := &Connection{
+		DB:  db,
+		cfg: cfg,
+	}
+
+	span.SetAttributes(attribute.Bool("db.lazy_connect", cfg.LazyConnect))
+	if cfg.LazyConnect {
+		return conn, nil
+	}
+
 	// Проверка соединения
-	if err := db.PingContext(ctx); err != nil {
+	if err := conn.ensureConnected(ctx); err != nil {
 		span.RecordError(err)
-		return nil, errors.Wrap(err, "failed to ping PostgreSQL")
+		if closeErr := db.Close(); closeErr != nil {
+			span.RecordError(closeErr)
+		}
+		return nil, err
 	}
 
-	return &Connection{
-		DB:  db,
-		cfg: cfg,
-	}, nil
+	return conn, nil
+}
+
+// ensureConnected проверяет подключение с повторными попытками, пока оно не будет установлено.
+// После первой успешной проверки вызов ничего не делает.
+func (c *Connection) ensureConnected(ctx context.Context) error {
+	if c.connected.Load() {
+		return nil
+	}
+
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
+
+	if c.connected.Load() {
+		return nil
+	}
+
+	err := pg.Retry(ctx, c.cfg.RetryAttempts, c.cfg.RetryBackoff, c.PingContext)
+	if err != nil {
+		return errors.Wrap(err, "failed to ping PostgreSQL")
+	}
+
+	if err := c.warmUp(ctx); err != nil {
+		return err
+	}
+
+	c.connected.Store(true)
+	return nil
+}
+
+// warmUp одновременно открывает MinIdleConns соединений и выполняет на каждом WarmupQueries.
+// После возврата соединения остаются в пуле свободными. Если задан только WarmupQueries,
+// запросы выполняются на одном соединении.
+func (c *Connection) warmUp(ctx context.Context) error {
+	n := c.warmUpConns()
+	if n == 0 {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "sqlx.WarmUp")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("db.warmup_conns", n),
+		attribute.Int("db.warmup_queries", len(c.cfg.WarmupQueries)),
+	)
+
+	// Соединения удерживаются до конца прогрева, иначе пул выдавал бы одно и то же соединение
+	conns := make([]*sqlx.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			if err := conn.Close(); err != nil {
+				span.RecordError(err)
+			}
+		}
+	}()
+
+	for range n {
+		conn, err := c.DB.Connx(ctx)
+		if err != nil {
+			span.RecordError(err)
+			return errors.Wrap(err, "failed to open warm-up connection")
+		}
+		conns = append(conns, conn)
+
+		for _, query := range c.cfg.WarmupQueries {
+			if _, err := conn.ExecContext(ctx, query); err != nil {
+				span.RecordError(err)
+				return errors.Wrapf(err, "failed to execute warm-up query %q", query)
+			}
+		}
+	}
+
+	c.warmedConns.Store(int32(len(conns)))
+	return nil
+}
+
+// warmUpConns возвращает число прогреваемых соединений с учётом лимитов пула
+func (c *Connection) warmUpConns() int {
+	n := c.cfg.MinIdleConns
+	if n <= 0 && len(c.cfg.WarmupQueries) > 0 {
+		n = 1
+	}
+	if n <= 0 {
+		return 0
+	}
+	// database/sql по умолчанию хранит 2 свободных соединения; лишние закрылись бы сразу после прогрева
+	maxIdle := defaultMaxIdleConns
+	if c.cfg.MaxIdleConns > 0 {
+		maxIdle = c.cfg.MaxIdleConns
+	}
+	n = min(n, maxIdle)
+	if c.cfg.MaxOpenConns > 0 {
+		n = min(n, c.cfg.MaxOpenConns)
+	}
+	return n
+}
+
+// PoolStats — состояние пула соединений
+type PoolStats struct {
+	sql.DBStats
+
+	MaxIdleConns int // лимит свободных соединений
+	MinIdleConns int // число соединений, прогреваемых при подключении
+	WarmedConns  int // число соединений, прогретых при подключении
+}
+
+// Stats возвращает состояние пула соединений, например для readiness-проверок и метрик
+func (c *Connection) Stats() PoolStats {
+	maxIdle := defaultMaxIdleConns
+	if c.cfg.MaxIdleConns > 0 {
+		maxIdle = c.cfg.MaxIdleConns
+	}
+	return PoolStats{
+		DBStats:      c.DB.Stats(),
+		MaxIdleConns: maxIdle,
+		MinIdleConns: c.warmUpConns(),
+		WarmedConns:  int(c.warmedConns.Load()),
+	}
 }
 
 // Close закрывает соединение с базой данных
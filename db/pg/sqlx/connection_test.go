@@ -0,0 +1,170 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableConfig returns a config pointing to a closed local port
+func unreachableConfig() Config {
+	return Config{
+		Host:          "127.0.0.1",
+		Port:          1,
+		User:          "user",
+		Password:      "pass",
+		Database:      "db",
+		SSLMode:       "disable",
+		RetryAttempts: 2,
+		RetryBackoff:  10 * time.Millisecond,
+	}
+}
+
+func TestConnect_RetriesAndFails(t *testing.T) {
+	t.Parallel()
+
+	conn, err := Connect(context.Background(), unreachableConfig())
+
+	require.Error(t, err)
+	assert.Nil(t, conn)
+	assert.Contains(t, err.Error(), "failed to ping PostgreSQL")
+	assert.Contains(t, err.Error(), "all 2 attempts failed")
+}
+
+func TestConnect_LazyConnect(t *testing.T) {
+	t.Parallel()
+	cfg := unreachableConfig()
+	cfg.LazyConnect = true
+
+	conn, err := Connect(context.Background(), cfg)
+	require.NoError(t, err, "lazy connect must not touch the database")
+	require.NotNil(t, conn)
+	t.Cleanup(func() { conn.Close() })
+
+	var n int
+	err = conn.Get(context.Background(), &n, "SELECT 1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all 2 attempts failed")
+
+	_, err = conn.BeginTx(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+// warmupDriver is a database/sql driver counting opened connections and executed statements
+type warmupDriver struct {
+	opened  atomic.Int32
+	mu      sync.Mutex
+	execs   []string
+	execErr error
+}
+
+func (d *warmupDriver) Open(string) (driver.Conn, error) {
+	d.opened.Add(1)
+	return &warmupConn{driver: d}, nil
+}
+
+type warmupConn struct {
+	driver *warmupDriver
+}
+
+func (c *warmupConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *warmupConn) Close() error { return nil }
+
+func (c *warmupConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *warmupConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	if c.driver.execErr != nil {
+		return nil, c.driver.execErr
+	}
+	c.driver.execs = append(c.driver.execs, query)
+	return driver.RowsAffected(0), nil
+}
+
+var warmupDriverSeq atomic.Int32
+
+// newWarmupConnection returns a not yet connected Connection backed by a fresh warmupDriver
+func newWarmupConnection(t *testing.T, cfg Config) (*Connection, *warmupDriver) {
+	t.Helper()
+	d := &warmupDriver{}
+	name := "sqlx-warmup-" + string(rune('a'+warmupDriverSeq.Add(1)))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	return &Connection{DB: sqlx.NewDb(db, "postgres"), cfg: cfg}, d
+}
+
+// TestConnection_WarmUp tests that MinIdleConns connections are opened and kept idle
+func TestConnection_WarmUp(t *testing.T) {
+	t.Parallel()
+	conn, d := newWarmupConnection(t, Config{
+		MaxIdleConns:  5,
+		MinIdleConns:  3,
+		WarmupQueries: []string{"SET statement_timeout = '5s'", "SELECT 1"},
+	})
+
+	require.NoError(t, conn.ensureConnected(context.Background()))
+
+	assert.Equal(t, int32(3), d.opened.Load())
+	assert.Len(t, d.execs, 6)
+	stats := conn.Stats()
+	assert.Equal(t, 3, stats.Idle)
+	assert.Equal(t, 3, stats.WarmedConns)
+	assert.Equal(t, 3, stats.MinIdleConns)
+	assert.Equal(t, 5, stats.MaxIdleConns)
+}
+
+// TestConnection_WarmUpLimits tests that warm-up does not exceed pool limits
+func TestConnection_WarmUpLimits(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  Config
+		want int
+	}{
+		{name: "disabled", cfg: Config{}, want: 0},
+		{name: "queries only", cfg: Config{WarmupQueries: []string{"SELECT 1"}}, want: 1},
+		{name: "max idle", cfg: Config{MinIdleConns: 10, MaxIdleConns: 4}, want: 4},
+		{name: "default max idle", cfg: Config{MinIdleConns: 10}, want: defaultMaxIdleConns},
+		{name: "max open", cfg: Config{MinIdleConns: 10, MaxIdleConns: 8, MaxOpenConns: 3}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conn := &Connection{cfg: tt.cfg}
+			assert.Equal(t, tt.want, conn.warmUpConns())
+		})
+	}
+}
+
+// TestConnection_WarmUpError tests that a failed warm-up query fails the connection check
+func TestConnection_WarmUpError(t *testing.T) {
+	t.Parallel()
+	conn, d := newWarmupConnection(t, Config{MinIdleConns: 2, WarmupQueries: []string{"SELECT broken"}})
+	d.execErr = errors.New("syntax error")
+
+	err := conn.ensureConnected(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to execute warm-up query")
+	assert.False(t, conn.connected.Load())
+	assert.Equal(t, 0, conn.Stats().WarmedConns)
+}
@@ -0,0 +1,162 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+var (
+	_ driver.Connector          = (*credentialsConnector)(nil)
+	_ driver.QueryerContext     = (*credentialsConn)(nil)
+	_ driver.ExecerContext      = (*credentialsConn)(nil)
+	_ driver.ConnPrepareContext = (*credentialsConn)(nil)
+	_ driver.ConnBeginTx        = (*credentialsConn)(nil)
+	_ driver.Pinger             = (*credentialsConn)(nil)
+	_ driver.SessionResetter    = (*credentialsConn)(nil)
+	_ driver.Validator          = (*credentialsConn)(nil)
+)
+
+// credentialsConnector создаёт соединения lib/pq с учётными данными pg.CredentialsProvider.
+// При ошибке аутентификации учётные данные запрашиваются заново и подключение повторяется,
+// поэтому смена пароля не видна вызывающему коду.
+type credentialsConnector struct {
+	dsn   string // DSN без user и password
+	cache *pg.CredentialsCache
+}
+
+func newCredentialsConnector(dsn string, provider pg.CredentialsProvider, defaultUser string) *credentialsConnector {
+	return &credentialsConnector{dsn: dsn, cache: pg.NewCredentialsCache(provider, defaultUser)}
+}
+
+// Connect реализует driver.Connector
+func (c *credentialsConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	creds, err := c.cache.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.connect(ctx, creds)
+	if !isAuthError(err) {
+		return conn, err
+	}
+
+	changed, refreshErr := c.cache.Refresh(ctx, creds)
+	if refreshErr != nil {
+		correlate.FromContext(ctx).WithGroup("postgres").With("error", refreshErr).
+			ErrorContext(ctx, "failed to refresh database credentials")
+		return nil, err
+	}
+	if !changed {
+		return nil, err
+	}
+	correlate.FromContext(ctx).WithGroup("postgres").
+		InfoContext(ctx, "database credentials refreshed after authentication failure")
+
+	creds, err = c.cache.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.connect(ctx, creds)
+}
+
+// Driver реализует driver.Connector
+func (c *credentialsConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+func (c *credentialsConnector) connect(ctx context.Context, creds pg.Credentials) (driver.Conn, error) {
+	connector, err := pq.NewConnector(c.dsn + " user=" + dsnValue(creds.User) + " password=" + dsnValue(creds.Password))
+	if err != nil {
+		return nil, err
+	}
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &credentialsConn{Conn: conn, creds: creds, cache: c.cache}, nil
+}
+
+// isAuthError сообщает, что err — ошибка аутентификации PostgreSQL
+func isAuthError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pg.IsAuthErrorCode(string(pqErr.Code))
+}
+
+// dsnValue экранирует значение параметра DSN в формате key=value
+func dsnValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// credentialsConn — соединение lib/pq с учётными данными, с которыми оно создано.
+// После смены учётных данных database/sql закрывает его при возврате в пул (IsValid),
+// не прерывая выполняющийся запрос.
+type credentialsConn struct {
+	driver.Conn
+	creds pg.Credentials
+	cache *pg.CredentialsCache
+}
+
+// IsValid реализует driver.Validator
+func (c *credentialsConn) IsValid() bool {
+	if !c.cache.Current(c.creds) {
+		return false
+	}
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// ResetSession реализует driver.SessionResetter
+func (c *credentialsConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// QueryContext реализует driver.QueryerContext
+func (c *credentialsConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// ExecContext реализует driver.ExecerContext
+func (c *credentialsConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// PrepareContext реализует driver.ConnPrepareContext
+func (c *credentialsConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Prepare(query)
+}
+
+// BeginTx реализует driver.ConnBeginTx
+func (c *credentialsConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+}
+
+// Ping реализует driver.Pinger
+func (c *credentialsConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// fakeConn is a driver.Conn reporting its validity
+type fakeConn struct {
+	driver.Conn
+	valid bool
+}
+
+func (c *fakeConn) IsValid() bool { return c.valid }
+
+func TestCredentialsConn_IsValid(t *testing.T) {
+	t.Parallel()
+	password := "p1"
+	cache := pg.NewCredentialsCache(pg.CredentialsFunc(func(context.Context) (pg.Credentials, error) {
+		return pg.Credentials{Password: password}, nil
+	}), "app")
+	creds, err := cache.Get(context.Background())
+	require.NoError(t, err)
+
+	conn := &credentialsConn{Conn: &fakeConn{valid: true}, creds: creds, cache: cache}
+	assert.True(t, conn.IsValid())
+	assert.False(t, (&credentialsConn{Conn: &fakeConn{}, creds: creds, cache: cache}).IsValid())
+
+	password = "p2"
+	_, err = cache.Refresh(context.Background(), creds)
+	require.NoError(t, err)
+	assert.False(t, conn.IsValid())
+}
+
+func TestIsAuthError(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isAuthError(errors.Wrap(&pq.Error{Code: "28P01"}, "connect")))
+	assert.False(t, isAuthError(&pq.Error{Code: UniqueViolationCode}))
+	assert.False(t, isAuthError(errors.New("connection refused")))
+}
+
+func TestDSNValue(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `'p a\'ss\\'`, dsnValue(`p a'ss\`))
+}
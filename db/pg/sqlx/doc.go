@@ -35,10 +35,61 @@
 //	PG_CONN_MAX_LIFETIME — время жизни соединения
 //	PG_CONN_MAX_IDLE_TIME — время простоя соединения
 //	PG_QUERY_TIMEOUT     — таймаут запросов (default: 10s)
+//	POSTGRES_RETRY_ATTEMPTS       — число попыток подключения (default: 1)
+//	POSTGRES_RETRY_BACKOFF        — пауза между попытками подключения (default: 1s)
+//	POSTGRES_LAZY_CONNECT         — подключаться при первом запросе, а не в Connect (default: false)
+//	POSTGRES_MIN_IDLE_CONNS       — число соединений, открываемых заранее при подключении (default: 0)
+//	POSTGRES_SESSION_SETTINGS     — параметры сессии на каждом соединении: "statement_timeout:5s,timezone:UTC"
+//	POSTGRES_LOG_QUERIES          — логировать все запросы на уровне Debug (default: false)
+//	POSTGRES_LOG_QUERY_ARGS       — добавлять в лог типы и длины аргументов запросов (default: false)
+//	POSTGRES_SLOW_QUERY_THRESHOLD — порог медленного запроса, лог на уровне Warn (default: 0 — отключено)
+//	POSTGRES_DEV_EXPLAIN          — логировать EXPLAIN (ANALYZE, BUFFERS) медленных запросов (default: false)
+//	POSTGRES_DEV_READ_ONLY_GUARD  — отклонять изменяющие запросы в RunReadTx до отправки в БД (default: false)
+//	POSTGRES_STRICT_MAPPING       — проверять соответствие колонок и полей структуры в Get/Select (default: false)
 //
 // Особенности:
 //   - Именованные запросы через NamedExec и NamedQuery
 //   - Транзакции с автоматическим откатом при ошибке (RunTx)
+//   - Транзакция в контексте (WithTx, TxFromContext): Get/Select/Exec/Query/QueryRow
+//     у Connection автоматически выполняются в транзакции из контекста; RunTx сохраняет
+//     транзакцию в контекст и присоединяется к уже открытой
+//   - События транзакций: Config.TxHooks (OnBegin, OnCommit, OnRollback с длительностью и причиной
+//     отката) для метрик; Tx.AfterCommit — действия только после фиксации транзакции
+//   - Read-only транзакции (RunReadTx): BEGIN READ ONLY и default_transaction_read_only;
+//     DevReadOnlyGuard отклоняет INSERT/UPDATE/DELETE/MERGE/TRUNCATE с ErrWriteInReadOnlyTx
+//   - StrictMapping: Get/Select возвращают *MappingError с колонками без поля и полями без колонки
+//     вместо частичного заполнения структуры; VerifyMapping[T] проверяет запрос в тестах
+//   - Enum: строковый тип с методом EnumLabels ([Enum]); ScanEnum/EnumValue и обёртка EnumScanner[T]
+//     отклоняют значения вне EnumLabels с ErrInvalidEnumValue; VerifyEnum сверяет метки enum в БД
+//     с EnumLabels и возвращает *EnumMismatchError
+//   - Типизированные запросы: GetOne[T] и SelectAll[T] возвращают записи без объявления переменных;
+//     T — значение или указатель, отсутствие строк в GetOne — *NotFoundError (IsNotFound)
+//   - Постраничная выборка: Paginate (keyset по колонкам Keyset) и PaginateOffset (LIMIT/OFFSET)
+//     возвращают PageResult с HasNext и непрозрачным токеном NextCursor (EncodeCursor/DecodeCursor)
+//   - Выгрузка: ExportQuery потоково пишет результат в io.Writer в CSV или ND-JSON с форматированием по типу колонки
+//   - ExecScript: SQL-скрипт из нескольких инструкций (сиды, административные скрипты) разбивается
+//     pg.SplitScript с учётом строк, комментариев и dollar-quoted тел и выполняется по одной
+//     инструкции в транзакции; ошибка — *pg.ScriptError с номером инструкции и строкой/столбцом в скрипте
+//   - Прогрев пула: MinIdleConns соединений открываются при подключении, на каждом выполняются
+//     WarmupQueries; Stats возвращает состояние пула (sql.DBStats и число прогретых соединений)
+//   - Повторные попытки подключения; в режиме LazyConnect подключение с повторами
+//     выполняется при первом вызове Get/Select/Exec/Query/NamedExec/NamedQuery/BeginTx
+//   - Config.Credentials (pg.CredentialsProvider) запрашивается при создании соединений; после
+//     ошибки аутентификации учётные данные обновляются и подключение повторяется незаметно для
+//     вызывающего кода, соединения со старыми учётными данными закрываются после освобождения
 //   - OpenTelemetry tracing для всех операций
+//   - Логирование запросов и медленных запросов через slog (logger.FromContext) с trace_id, span_id
+//     и request_id из контекста (observability/correlate);
+//     QueryRow не логируется, т.к. выполняется лениво при Scan
+//   - Config.QueryMetrics (pg.QueryMetrics): счётчики и гистограммы длительности запросов
+//     по нормализованным отпечаткам (тот же набор операций, что и для логирования)
+//   - Config.SQLCommenter (pg.SQLCommenter): комментарий sqlcommenter с traceparent и тегами
+//     из контекста добавляется к запросам Connection и Tx
+//   - Отменённые запросы (QueryTimeout, отмена контекста, statement_timeout, pg_cancel_backend)
+//     возвращают *QueryCancelledError с отпечатком запроса, причиной, временем выполнения
+//     и таймаутом; Config.OnQueryCancelled вызывается для каждого такого запроса
+//   - DevExplain: медленные Get/Select/Exec/NamedExec вне транзакции повторно выполняются
+//     под EXPLAIN (ANALYZE, BUFFERS) в read-only транзакции с откатом, план пишется в лог
+//     ("slow query plan"); изменяющие данные запросы в read-only транзакции завершаются ошибкой
 //   - Хелперы для проверки constraint ошибок (IsUniqueViolation, etc.)
 package sqlx
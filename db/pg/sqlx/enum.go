@@ -0,0 +1,175 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidEnumValue возвращается при чтении или записи значения, не входящего в EnumLabels
+var ErrInvalidEnumValue = errors.New("invalid enum value")
+
+// Enum — строковый тип Go для enum PostgreSQL. EnumLabels возвращает все допустимые значения
+// в порядке объявления enum в БД; вызывается на нулевом значении.
+//
+//	type OrderStatus string
+//
+//	const (
+//	    OrderNew     OrderStatus = "new"
+//	    OrderPaid    OrderStatus = "paid"
+//	    OrderShipped OrderStatus = "shipped"
+//	)
+//
+//	func (OrderStatus) EnumLabels() []OrderStatus { return []OrderStatus{OrderNew, OrderPaid, OrderShipped} }
+type Enum[T any] interface {
+	~string
+	EnumLabels() []T
+}
+
+// EnumScanner читает и записывает колонку enum в V с проверкой значения по EnumLabels.
+// SQL NULL читается как пустая строка, пустая строка записывается как NULL.
+//
+//	var status sqlx.EnumScanner[OrderStatus]
+//	err := db.QueryRow(ctx, "SELECT status FROM orders WHERE id = $1", id).Scan(&status)
+type EnumScanner[T Enum[T]] struct {
+	V T
+}
+
+// Value реализует driver.Valuer
+func (e EnumScanner[T]) Value() (driver.Value, error) {
+	return EnumValue(e.V)
+}
+
+// Scan реализует sql.Scanner
+func (e *EnumScanner[T]) Scan(src any) error {
+	return ScanEnum(&e.V, src)
+}
+
+// ScanEnum читает значение колонки enum в dst и проверяет его по EnumLabels.
+// Используется для реализации sql.Scanner на собственном типе, чтобы применять его в полях структур:
+//
+//	func (s *OrderStatus) Scan(src any) error { return sqlx.ScanEnum(s, src) }
+func ScanEnum[T Enum[T]](dst *T, src any) error {
+	var label string
+	switch v := src.(type) {
+	case nil:
+		*dst = ""
+		return nil
+	case string:
+		label = v
+	case []byte:
+		label = string(v)
+	default:
+		return errors.Errorf("failed to scan %s: unsupported source type %T", enumTypeName[T](), src)
+	}
+
+	value := T(label)
+	if !validEnum(value) {
+		return errors.Wrapf(ErrInvalidEnumValue, "failed to scan %s %q", enumTypeName[T](), label)
+	}
+	*dst = value
+	return nil
+}
+
+// EnumValue проверяет v по EnumLabels и возвращает значение для записи; пустая строка записывается как NULL.
+// Используется для реализации driver.Valuer на собственном типе:
+//
+//	func (s OrderStatus) Value() (driver.Value, error) { return sqlx.EnumValue(s) }
+func EnumValue[T Enum[T]](v T) (driver.Value, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if !validEnum(v) {
+		return nil, errors.Wrapf(ErrInvalidEnumValue, "%s %q", enumTypeName[T](), string(v))
+	}
+	return string(v), nil
+}
+
+// EnumMismatchError описывает расхождение меток enum в БД и значений EnumLabels.
+// Возвращается VerifyEnum.
+type EnumMismatchError struct {
+	Type         string   // имя типа enum в БД
+	GoType       string   // тип Go, например "models.OrderStatus"
+	MissingInGo  []string // метки БД без значения в EnumLabels
+	MissingInDB  []string // значения EnumLabels без метки в БД
+	OrderDiffers bool     // метки совпадают, но порядок отличается
+}
+
+// Error перечисляет метки без соответствия
+func (e *EnumMismatchError) Error() string {
+	var parts []string
+	if len(e.MissingInGo) > 0 {
+		parts = append(parts, "labels missing in Go: "+strings.Join(e.MissingInGo, ", "))
+	}
+	if len(e.MissingInDB) > 0 {
+		parts = append(parts, "labels missing in database: "+strings.Join(e.MissingInDB, ", "))
+	}
+	if e.OrderDiffers {
+		parts = append(parts, "label order differs")
+	}
+	return fmt.Sprintf("enum mismatch between %s and %s: %s", e.Type, e.GoType, strings.Join(parts, "; "))
+}
+
+// enumLabelsQuery возвращает метки enum в порядке объявления; $1 — имя типа, возможно со схемой
+const enumLabelsQuery = `SELECT e.enumlabel FROM pg_enum e WHERE e.enumtypid = $1::regtype ORDER BY e.enumsortorder`
+
+// VerifyEnum сверяет метки enum typeName в БД со значениями EnumLabels типа T, включая порядок:
+// от него зависят сравнения и ORDER BY по колонке. Возвращает *EnumMismatchError при расхождении.
+// Предназначена для тестов: находит значения, добавленные миграцией или в коде только с одной стороны.
+//
+//	err := sqlx.VerifyEnum[OrderStatus](ctx, db, "order_status")
+func VerifyEnum[T Enum[T]](ctx context.Context, q sqlx.QueryerContext, typeName string) error {
+	var dbLabels []string
+	if err := sqlx.SelectContext(ctx, q, &dbLabels, enumLabelsQuery, typeName); err != nil {
+		return errors.Wrapf(err, "failed to query labels of enum %s", typeName)
+	}
+	if len(dbLabels) == 0 {
+		return errors.Errorf("enum %s has no labels or does not exist", typeName)
+	}
+
+	goLabels := make([]string, 0, len(dbLabels))
+	for _, v := range enumLabels[T]() {
+		goLabels = append(goLabels, string(v))
+	}
+
+	mismatch := &EnumMismatchError{Type: typeName, GoType: enumTypeName[T]()}
+	for _, label := range dbLabels {
+		if !slices.Contains(goLabels, label) {
+			mismatch.MissingInGo = append(mismatch.MissingInGo, label)
+		}
+	}
+	for _, label := range goLabels {
+		if !slices.Contains(dbLabels, label) {
+			mismatch.MissingInDB = append(mismatch.MissingInDB, label)
+		}
+	}
+	if len(mismatch.MissingInGo) == 0 && len(mismatch.MissingInDB) == 0 {
+		mismatch.OrderDiffers = !slices.Equal(dbLabels, goLabels)
+		if !mismatch.OrderDiffers {
+			return nil
+		}
+	}
+	return mismatch
+}
+
+// enumLabels возвращает допустимые значения T
+func enumLabels[T Enum[T]]() []T {
+	var zero T
+	return zero.EnumLabels()
+}
+
+// validEnum сообщает, что v входит в EnumLabels
+func validEnum[T Enum[T]](v T) bool {
+	return slices.Contains(enumLabels[T](), v)
+}
+
+// enumTypeName возвращает имя типа T для сообщений об ошибках
+func enumTypeName[T any]() string {
+	var zero T
+	return fmt.Sprintf("%T", zero)
+}
@@ -0,0 +1,54 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderStatus string
+
+const (
+	orderNew  orderStatus = "new"
+	orderPaid orderStatus = "paid"
+)
+
+func (orderStatus) EnumLabels() []orderStatus { return []orderStatus{orderNew, orderPaid} }
+
+// TestEnumScanner tests scanning and writing enum values with validation.
+func TestEnumScanner(t *testing.T) {
+	t.Parallel()
+
+	var s EnumScanner[orderStatus]
+	require.NoError(t, s.Scan("paid"))
+	assert.Equal(t, orderPaid, s.V)
+	require.NoError(t, s.Scan([]byte("new")))
+	assert.Equal(t, orderNew, s.V)
+	require.NoError(t, s.Scan(nil))
+	assert.Equal(t, orderStatus(""), s.V)
+
+	err := s.Scan("refunded")
+	require.ErrorIs(t, err, ErrInvalidEnumValue)
+	assert.EqualError(t, err, `failed to scan sqlx.orderStatus "refunded": invalid enum value`)
+	assert.ErrorContains(t, s.Scan(42), "unsupported source type int")
+
+	v, err := EnumScanner[orderStatus]{V: orderPaid}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "paid", v)
+	v, err = EnumScanner[orderStatus]{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	_, err = EnumValue(orderStatus("Paid"))
+	assert.ErrorIs(t, err, ErrInvalidEnumValue)
+}
+
+// TestEnumMismatchError_Error tests the mismatch description.
+func TestEnumMismatchError_Error(t *testing.T) {
+	t.Parallel()
+	err := &EnumMismatchError{Type: "order_status", GoType: "models.OrderStatus", MissingInGo: []string{"refunded"}, MissingInDB: []string{"cancelled"}}
+	assert.EqualError(t, err, "enum mismatch between order_status and models.OrderStatus: labels missing in Go: refunded; labels missing in database: cancelled")
+
+	err = &EnumMismatchError{Type: "order_status", GoType: "models.OrderStatus", OrderDiffers: true}
+	assert.EqualError(t, err, "enum mismatch between order_status and models.OrderStatus: label order differs")
+}
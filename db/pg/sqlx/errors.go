@@ -13,8 +13,12 @@ const (
 	ForeignKeyViolationCode = pq.ErrorCode("23503")
 	CheckViolationCode      = pq.ErrorCode("23514")
 	NotNullViolationCode    = pq.ErrorCode("23502")
+	QueryCanceledCode       = pq.ErrorCode("57014")
 )
 
+// ErrWriteInReadOnlyTx возвращается DevReadOnlyGuard для изменяющего запроса в read-only транзакции
+var ErrWriteInReadOnlyTx = errors.New("write statement in read-only transaction")
+
 // IsUniqueViolation проверяет, является ли ошибка нарушением ограничения уникальности
 func IsUniqueViolation(err error) bool {
 	var pqErr *pq.Error
@@ -0,0 +1,61 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+// explainSlowQuery повторно выполняет медленный запрос под EXPLAIN (ANALYZE, BUFFERS) и логирует план.
+// Работает только при включённых DevExplain и SlowQueryThreshold и только для успешных запросов.
+// Ошибка EXPLAIN логируется и не влияет на результат исходного запроса.
+func (c *Connection) explainSlowQuery(ctx context.Context, operation, query string, args []any, duration time.Duration, queryErr error) {
+	if !c.cfg.DevExplain || queryErr != nil || c.cfg.SlowQueryThreshold <= 0 || duration < c.cfg.SlowQueryThreshold {
+		return
+	}
+
+	log := correlate.FromContext(ctx).WithGroup("postgres")
+
+	// Исходный контекст мог почти исчерпать таймаут, поэтому EXPLAIN получает собственный
+	explainCtx, cancel := WithTimeout(context.WithoutCancel(ctx), c.cfg.QueryTimeout)
+	defer cancel()
+
+	plan, err := c.explainAnalyze(explainCtx, query, args)
+	if err != nil {
+		log.LogAttrs(ctx, slog.LevelWarn, "failed to explain slow query",
+			slog.String("operation", operation),
+			slog.String("query", query),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	log.LogAttrs(ctx, slog.LevelWarn, "slow query plan",
+		slog.String("operation", operation),
+		slog.String("query", query),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+		slog.String("plan", plan),
+	)
+}
+
+// explainAnalyze выполняет EXPLAIN (ANALYZE, BUFFERS) в read-only транзакции, которая всегда откатывается:
+// запросы, изменяющие данные, завершаются ошибкой и не оставляют последствий.
+func (c *Connection) explainAnalyze(ctx context.Context, query string, args []any) (string, error) {
+	tx, err := c.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to begin read-only transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var lines []string
+	if err := tx.SelectContext(ctx, &lines, "EXPLAIN (ANALYZE, BUFFERS) "+query, args...); err != nil {
+		return "", errors.Wrap(err, "failed to explain query")
+	}
+	return strings.Join(lines, "\n"), nil
+}
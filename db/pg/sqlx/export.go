@@ -0,0 +1,364 @@
+package sqlx
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// ExportFormat — формат выгрузки ExportQuery
+type ExportFormat string
+
+const (
+	// ExportCSV — CSV (RFC 4180) со строкой заголовка из имён колонок
+	ExportCSV ExportFormat = "csv"
+	// ExportNDJSON — по одному JSON объекту на строку результата (newline-delimited JSON)
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// exportFlushRows — число строк, после которого буфер выгрузки сбрасывается в writer
+const exportFlushRows = 1000
+
+// flusher реализуется writer'ами с собственной буферизацией, например http.ResponseWriter
+type flusher interface {
+	Flush()
+}
+
+// ExportQuery выполняет запрос и потоково записывает результат в w в формате format,
+// не загружая его в память: следующая строка читается из БД после записи предыдущей,
+// поэтому медленный клиент замедляет чтение (backpressure). Возвращает число записанных строк.
+// Если в контексте есть транзакция (WithTx), запрос выполняется в ней.
+//
+// Значения форматируются по типу колонки:
+//   - NULL — пустое поле в CSV, null в JSON
+//   - timestamptz — RFC 3339 с часовым поясом, timestamp — без него, date — "2006-01-02", time — "15:04:05.999999"
+//   - numeric — исходная десятичная запись без потери точности (в JSON — число, NaN и Infinity — строкой)
+//   - json и jsonb — вложенный JSON в ND-JSON и исходный текст в CSV
+//   - bytea — base64
+//
+// QueryTimeout не применяется: длительность выгрузки зависит от скорости клиента,
+// ограничивайте её контекстом. Каждые 1000 строк буфер сбрасывается, и вызывается Flush у w,
+// если он его реализует (http.ResponseWriter).
+func (c *Connection) ExportQuery(ctx context.Context, w io.Writer, format ExportFormat, query string, args ...any) (int64, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.ExportQuery(ctx, w, format, query, args...)
+	}
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return 0, err
+	}
+	if err := checkExportFormat(format); err != nil {
+		return 0, err
+	}
+
+	ctx, span := c.WithTracing(ctx, "ExportQuery", query)
+	defer span.End()
+
+	start := time.Now()
+	rows, err := c.QueryxContext(ctx, c.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, c.cfg, "ExportQuery", query, start, err)
+	observeQuery(ctx, c.cfg, "ExportQuery", query, args, rowsUnknown, time.Since(start), err)
+	if err != nil {
+		span.RecordError(err)
+		return 0, errors.Wrap(err, "failed to execute export query")
+	}
+
+	n, err := exportRows(rows, w, format)
+	if err != nil {
+		err = diagnoseCancellation(ctx, c.cfg, "ExportQuery", query, start, err)
+		span.RecordError(err)
+	}
+	return n, err
+}
+
+// ExportQuery выполняет запрос в транзакции и потоково записывает результат в w, см. Connection.ExportQuery
+func (tx *Tx) ExportQuery(ctx context.Context, w io.Writer, format ExportFormat, query string, args ...any) (int64, error) {
+	if err := tx.checkReadOnly(query); err != nil {
+		return 0, err
+	}
+	if err := checkExportFormat(format); err != nil {
+		return 0, err
+	}
+
+	ctx, span := tx.WithTracing(ctx, "ExportQuery", query)
+	defer span.End()
+
+	start := time.Now()
+	rows, err := tx.tx.QueryxContext(ctx, tx.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, tx.cfg, "ExportQuery", query, start, err)
+	observeQuery(ctx, tx.cfg, "ExportQuery", query, args, rowsUnknown, time.Since(start), err)
+	if err != nil {
+		span.RecordError(err)
+		return 0, errors.Wrap(err, "failed to execute export query in transaction")
+	}
+
+	n, err := exportRows(rows, w, format)
+	if err != nil {
+		err = diagnoseCancellation(ctx, tx.cfg, "ExportQuery", query, start, err)
+		span.RecordError(err)
+	}
+	return n, err
+}
+
+// checkExportFormat отклоняет неизвестный формат до выполнения запроса
+func checkExportFormat(format ExportFormat) error {
+	if format != ExportCSV && format != ExportNDJSON {
+		return errors.Errorf("unsupported export format %q", format)
+	}
+	return nil
+}
+
+// exportRows записывает строки результата и закрывает rows
+func exportRows(rows *sqlx.Rows, w io.Writer, format ExportFormat) (int64, error) {
+	defer func() { _ = rows.Close() }()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get export columns")
+	}
+	columns := make([]exportColumn, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = exportColumn{name: ct.Name(), dbType: ct.DatabaseTypeName()}
+	}
+
+	enc, err := newExportEncoder(w, format, columns)
+	if err != nil {
+		return 0, err
+	}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return n, errors.Wrap(err, "failed to scan export row")
+		}
+		if err := enc.writeRow(values); err != nil {
+			return n, err
+		}
+		n++
+		if n%exportFlushRows == 0 {
+			if err := enc.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return n, errors.Wrap(err, "failed to read export rows")
+	}
+	return n, enc.flush()
+}
+
+// exportColumn — имя и тип колонки результата (DatabaseTypeName: "TIMESTAMPTZ", "NUMERIC", ...)
+type exportColumn struct {
+	name   string
+	dbType string
+}
+
+// exportEncoder форматирует строки результата
+type exportEncoder struct {
+	w       io.Writer
+	columns []exportColumn
+	csv     *csv.Writer
+	record  []string
+	buf     bytes.Buffer
+	keys    [][]byte
+}
+
+// newExportEncoder создаёт encoder; для CSV сразу записывает заголовок
+func newExportEncoder(w io.Writer, format ExportFormat, columns []exportColumn) (*exportEncoder, error) {
+	e := &exportEncoder{w: w, columns: columns}
+	switch format {
+	case ExportCSV:
+		e.csv = csv.NewWriter(w)
+		e.record = make([]string, len(columns))
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.name
+		}
+		if err := e.csv.Write(header); err != nil {
+			return nil, errors.Wrap(err, "failed to write export")
+		}
+	case ExportNDJSON:
+		e.keys = make([][]byte, len(columns))
+		for i, column := range columns {
+			key, err := json.Marshal(column.name)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to encode export column")
+			}
+			e.keys[i] = key
+		}
+	default:
+		return nil, checkExportFormat(format)
+	}
+	return e, nil
+}
+
+// writeRow записывает одну строку
+func (e *exportEncoder) writeRow(values []any) error {
+	if e.csv != nil {
+		for i, value := range values {
+			e.record[i] = csvValue(e.columns[i], value)
+		}
+		if err := e.csv.Write(e.record); err != nil {
+			return errors.Wrap(err, "failed to write export")
+		}
+		return nil
+	}
+
+	e.buf.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.buf.Write(e.keys[i])
+		e.buf.WriteByte(':')
+		if err := e.writeJSONValue(e.columns[i], value); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteString("}\n")
+	if e.buf.Len() < 64<<10 {
+		return nil
+	}
+	return e.writeBuffer()
+}
+
+// flush сбрасывает буфер в w и вызывает его Flush
+func (e *exportEncoder) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return errors.Wrap(err, "failed to write export")
+		}
+	} else if err := e.writeBuffer(); err != nil {
+		return err
+	}
+	if f, ok := e.w.(flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// writeBuffer записывает накопленные строки ND-JSON
+func (e *exportEncoder) writeBuffer() error {
+	_, err := e.buf.WriteTo(e.w)
+	return errors.Wrap(err, "failed to write export")
+}
+
+// writeJSONValue добавляет значение в буфер ND-JSON
+func (e *exportEncoder) writeJSONValue(column exportColumn, value any) error {
+	switch v := value.(type) {
+	case nil:
+		e.buf.WriteString("null")
+		return nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return e.writeJSONString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case []byte:
+		switch column.dbType {
+		case "JSON", "JSONB":
+			if json.Valid(v) {
+				e.buf.Write(v)
+				return nil
+			}
+		case "NUMERIC":
+			if !isNonFinite(string(v)) {
+				e.buf.Write(v)
+				return nil
+			}
+		}
+		return e.writeJSONString(textValue(column, v))
+	case time.Time:
+		return e.writeJSONString(formatTime(column, v))
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode export column %s", column.name)
+	}
+	e.buf.Write(data)
+	return nil
+}
+
+// writeJSONString добавляет JSON строку в буфер
+func (e *exportEncoder) writeJSONString(s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode export value")
+	}
+	e.buf.Write(data)
+	return nil
+}
+
+// csvValue форматирует значение для CSV; NULL — пустое поле
+func csvValue(column exportColumn, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return textValue(column, v)
+	case time.Time:
+		return formatTime(column, v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// textValue возвращает текстовое представление значения, полученного драйвером в байтах:
+// bytea кодируется в base64, остальные типы (numeric, uuid, json, массивы) уже в текстовом виде
+func textValue(column exportColumn, v []byte) string {
+	if column.dbType == "BYTEA" {
+		return base64.StdEncoding.EncodeToString(v)
+	}
+	return string(v)
+}
+
+// formatTime форматирует дату и время по типу колонки
+func formatTime(column exportColumn, t time.Time) string {
+	switch column.dbType {
+	case "DATE":
+		return t.Format(time.DateOnly)
+	case "TIME":
+		return t.Format("15:04:05.999999")
+	case "TIMETZ":
+		return t.Format("15:04:05.999999Z07:00")
+	case "TIMESTAMP":
+		return t.Format("2006-01-02T15:04:05.999999")
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
+
+// isNonFinite сообщает, что numeric — NaN или бесконечность, которых нет в JSON
+func isNonFinite(s string) bool {
+	switch s {
+	case "NaN", "Infinity", "-Infinity":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package sqlx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportTestColumns covers the types formatted by column type
+var exportTestColumns = []exportColumn{
+	{name: "id", dbType: "INT8"},
+	{name: "name", dbType: "TEXT"},
+	{name: "amount", dbType: "NUMERIC"},
+	{name: "created_at", dbType: "TIMESTAMPTZ"},
+	{name: "day", dbType: "DATE"},
+	{name: "attrs", dbType: "JSONB"},
+	{name: "data", dbType: "BYTEA"},
+	{name: "note", dbType: "TEXT"},
+}
+
+func exportTestRow() []any {
+	return []any{
+		int64(1),
+		`say "hi", bye`,
+		[]byte("12345678901234567890.01"),
+		time.Date(2026, 1, 2, 3, 4, 5, 600000000, time.FixedZone("MSK", 3*3600)),
+		time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		[]byte(`{"a": [1, 2]}`),
+		[]byte{0xde, 0xad},
+		nil,
+	}
+}
+
+// TestExportEncoder_CSV tests CSV header, quoting and type-aware formatting.
+func TestExportEncoder_CSV(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	enc, err := newExportEncoder(&buf, ExportCSV, exportTestColumns)
+	require.NoError(t, err)
+	require.NoError(t, enc.writeRow(exportTestRow()))
+	require.NoError(t, enc.flush())
+
+	assert.Equal(t, "id,name,amount,created_at,day,attrs,data,note\n"+
+		`1,"say ""hi"", bye",12345678901234567890.01,2026-01-02T03:04:05.6+03:00,2026-01-02,"{""a"": [1, 2]}",3q0=,`+"\n",
+		buf.String())
+}
+
+// TestExportEncoder_NDJSON tests ND-JSON objects with precise numerics, nested JSON and nulls.
+func TestExportEncoder_NDJSON(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	enc, err := newExportEncoder(&buf, ExportNDJSON, exportTestColumns)
+	require.NoError(t, err)
+	require.NoError(t, enc.writeRow(exportTestRow()))
+	require.NoError(t, enc.writeRow([]any{int64(2), "", []byte("NaN"), nil, nil, nil, nil, "x"}))
+	assert.Zero(t, buf.Len(), "rows are buffered until flush")
+	require.NoError(t, enc.flush())
+
+	assert.Equal(t,
+		`{"id":1,"name":"say \"hi\", bye","amount":12345678901234567890.01,"created_at":"2026-01-02T03:04:05.6+03:00",`+
+			`"day":"2026-01-02","attrs":{"a": [1, 2]},"data":"3q0=","note":null}`+"\n"+
+			`{"id":2,"name":"","amount":"NaN","created_at":null,"day":null,"attrs":null,"data":null,"note":"x"}`+"\n",
+		buf.String())
+}
+
+// TestNewExportEncoder_UnsupportedFormat tests that an unknown format is rejected.
+func TestNewExportEncoder_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+	_, err := newExportEncoder(&bytes.Buffer{}, "xml", nil)
+	assert.EqualError(t, err, `unsupported export format "xml"`)
+}
@@ -0,0 +1,113 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+// rowsUnknown — число строк запроса неизвестно: ошибка или строки ещё не прочитаны (Query, ExportQuery)
+const rowsUnknown = -1
+
+// logQuery логирует выполненный запрос согласно настройкам cfg:
+//   - при превышении SlowQueryThreshold — на уровне Warn с сообщением "slow query"
+//   - при включённом LogQueries — на уровне Debug
+//
+// Вместо текста запроса логируется его отпечаток (pg.Fingerprint), поэтому значения литералов
+// в лог не попадают. rows — число затронутых или прочитанных строк, rowsUnknown — не логируется.
+// При включённом LogQueryArgs аргументы логируются без значений: тип и длина (см. describeArg).
+func logQuery(ctx context.Context, cfg Config, operation, query string, args []any, rows int64, duration time.Duration, err error) {
+	slow := cfg.SlowQueryThreshold > 0 && duration >= cfg.SlowQueryThreshold
+	if !slow && !cfg.LogQueries {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", operation),
+		slog.String("fingerprint", pg.Fingerprint(query)),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	}
+	if rows != rowsUnknown {
+		attrs = append(attrs, slog.Int64("rows", rows))
+	}
+	if cfg.LogQueryArgs && len(args) > 0 {
+		described := make([]string, len(args))
+		for i, arg := range args {
+			described[i] = describeArg(arg)
+		}
+		attrs = append(attrs, slog.Any("args", described))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	log := correlate.FromContext(ctx).WithGroup("postgres")
+	if slow {
+		attrs = append(attrs, slog.Int64("threshold_ms", cfg.SlowQueryThreshold.Milliseconds()))
+		log.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+		return
+	}
+	log.LogAttrs(ctx, slog.LevelDebug, "query executed", attrs...)
+}
+
+// describeArg описывает аргумент запроса без значения: тип, а для строк, срезов и map — ещё и длину,
+// например "string(12)", "[]uint8(5)", "int64", "NULL"
+func describeArg(arg any) string {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "NULL"
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return "NULL"
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("%s(%d)", v.Type(), v.Len())
+	default:
+		return v.Type().String()
+	}
+}
+
+// gotRows возвращает число строк, прочитанных Get
+func gotRows(err error) int64 {
+	switch {
+	case err == nil:
+		return 1
+	case err == sql.ErrNoRows:
+		return 0
+	default:
+		return rowsUnknown
+	}
+}
+
+// selectedRows возвращает число строк, прочитанных Select в срез dst
+func selectedRows(dst any, err error) int64 {
+	if err != nil {
+		return rowsUnknown
+	}
+	v := reflect.Indirect(reflect.ValueOf(dst))
+	if v.Kind() != reflect.Slice {
+		return rowsUnknown
+	}
+	return int64(v.Len())
+}
+
+// affectedRows возвращает число строк, затронутых Exec
+func affectedRows(result sql.Result, err error) int64 {
+	if err != nil || result == nil {
+		return rowsUnknown
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return rowsUnknown
+	}
+	return n
+}
@@ -0,0 +1,208 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// recordingHandler collects log records for assertions
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
+
+func newLoggingContext() (context.Context, *recordingHandler) {
+	h := &recordingHandler{}
+	return logger.NewContext(context.Background(), slog.New(h)), h
+}
+
+func TestLogQuery_Disabled(t *testing.T) {
+	t.Parallel()
+	ctx, h := newLoggingContext()
+
+	logQuery(ctx, Config{}, "Get", "SELECT 1", nil, 1, time.Second, nil)
+
+	assert.Empty(t, h.records)
+}
+
+func TestLogQuery_SlowQuery(t *testing.T) {
+	t.Parallel()
+	ctx, h := newLoggingContext()
+	cfg := Config{SlowQueryThreshold: 100 * time.Millisecond}
+
+	logQuery(ctx, cfg, "Select", "SELECT * FROM users WHERE age > 30", []any{42}, 3, 50*time.Millisecond, nil)
+	logQuery(ctx, cfg, "Select", "SELECT * FROM users WHERE age > 30", []any{42}, 3, 150*time.Millisecond, nil)
+
+	require.Len(t, h.records, 1)
+	r := h.records[0]
+	assert.Equal(t, slog.LevelWarn, r.Level)
+	assert.Equal(t, "slow query", r.Message)
+
+	attrs := recordAttrs(r)
+	assert.Equal(t, "Select", attrs["operation"].String())
+	assert.Equal(t, "select * from users where age > ?", attrs["fingerprint"].String())
+	assert.NotContains(t, attrs, "query", "raw query must not be logged")
+	assert.Equal(t, int64(3), attrs["rows"].Int64())
+	assert.Equal(t, int64(150), attrs["duration_ms"].Int64())
+	assert.Equal(t, int64(100), attrs["threshold_ms"].Int64())
+	assert.NotContains(t, attrs, "args", "args must not be logged unless enabled")
+}
+
+func TestLogQuery_AllQueries(t *testing.T) {
+	t.Parallel()
+	ctx, h := newLoggingContext()
+	cfg := Config{LogQueries: true, LogQueryArgs: true}
+
+	secret := "4111 1111 1111 1111"
+	logQuery(ctx, cfg, "Exec", "UPDATE cards SET number = $1 WHERE id = $2 AND token = 'abc'",
+		[]any{secret, int64(7), nil, []byte("raw"), &secret}, 1, time.Millisecond, nil)
+
+	require.Len(t, h.records, 1)
+	r := h.records[0]
+	assert.Equal(t, slog.LevelDebug, r.Level)
+	assert.Equal(t, "query executed", r.Message)
+	attrs := recordAttrs(r)
+	assert.Equal(t, "update cards set number = ? where id = ? and token = ?", attrs["fingerprint"].String())
+	assert.Equal(t, int64(1), attrs["rows"].Int64())
+	assert.Equal(t, []string{"string(19)", "int64", "NULL", "[]uint8(3)", "string(19)"}, attrs["args"].Any())
+	for _, a := range []slog.Value{attrs["fingerprint"], attrs["args"]} {
+		assert.NotContains(t, a.String(), "4111")
+		assert.NotContains(t, a.String(), "abc")
+	}
+}
+
+func TestLogQuery_UnknownRows(t *testing.T) {
+	t.Parallel()
+	ctx, h := newLoggingContext()
+
+	logQuery(ctx, Config{LogQueries: true}, "Query", "SELECT 1", []any{1}, rowsUnknown, time.Millisecond, nil)
+
+	require.Len(t, h.records, 1)
+	attrs := recordAttrs(h.records[0])
+	assert.NotContains(t, attrs, "rows")
+	assert.NotContains(t, attrs, "args", "args must not be logged unless enabled")
+}
+
+func TestDescribeArg(t *testing.T) {
+	t.Parallel()
+	var nilPtr *string
+	testCases := []struct {
+		arg  any
+		want string
+	}{
+		{arg: nil, want: "NULL"},
+		{arg: nilPtr, want: "NULL"},
+		{arg: "secret", want: "string(6)"},
+		{arg: 42, want: "int"},
+		{arg: true, want: "bool"},
+		{arg: []int64{1, 2}, want: "[]int64(2)"},
+		{arg: map[string]any{"a": 1}, want: "map[string]interface {}(1)"},
+		{arg: time.Time{}, want: "time.Time"},
+		{arg: sql.NullString{String: "secret", Valid: true}, want: "sql.NullString"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, describeArg(tc.arg))
+	}
+}
+
+// fakeResult is a sql.Result with a fixed number of affected rows
+type fakeResult struct {
+	rows int64
+	err  error
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+
+func (r fakeResult) RowsAffected() (int64, error) { return r.rows, r.err }
+
+func TestQueryRows(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+
+	assert.Equal(t, int64(1), gotRows(nil))
+	assert.Equal(t, int64(0), gotRows(sql.ErrNoRows))
+	assert.Equal(t, int64(rowsUnknown), gotRows(boom))
+
+	users := []struct{ ID int }{{1}, {2}, {3}}
+	assert.Equal(t, int64(3), selectedRows(&users, nil))
+	assert.Equal(t, int64(rowsUnknown), selectedRows(&users, boom))
+	assert.Equal(t, int64(rowsUnknown), selectedRows(new(int), nil))
+
+	assert.Equal(t, int64(5), affectedRows(fakeResult{rows: 5}, nil))
+	assert.Equal(t, int64(rowsUnknown), affectedRows(fakeResult{err: boom}, nil))
+	assert.Equal(t, int64(rowsUnknown), affectedRows(nil, boom))
+}
+
+func TestLogQuery_SlowQueryWithError(t *testing.T) {
+	t.Parallel()
+	ctx, h := newLoggingContext()
+	cfg := Config{LogQueries: true, SlowQueryThreshold: time.Millisecond}
+
+	logQuery(ctx, cfg, "Exec", "UPDATE users SET name = $1", nil, rowsUnknown, 5*time.Millisecond, errors.New("timeout"))
+
+	require.Len(t, h.records, 1, "slow query is logged once at warn level")
+	r := h.records[0]
+	assert.Equal(t, slog.LevelWarn, r.Level)
+	assert.Contains(t, recordAttrs(r), "error")
+}
+
+func TestExplainSlowQuery_Gating(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		cfg      Config
+		duration time.Duration
+		err      error
+	}{
+		{name: "disabled", cfg: Config{SlowQueryThreshold: time.Millisecond}, duration: time.Second},
+		{name: "no threshold", cfg: Config{DevExplain: true}, duration: time.Second},
+		{name: "fast query", cfg: Config{DevExplain: true, SlowQueryThreshold: time.Second}, duration: time.Millisecond},
+		{name: "failed query", cfg: Config{DevExplain: true, SlowQueryThreshold: time.Millisecond}, duration: time.Second, err: errors.New("boom")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, h := newLoggingContext()
+			// connection without a database: EXPLAIN must not run
+			c := &Connection{cfg: tc.cfg}
+
+			c.explainSlowQuery(ctx, "Select", "SELECT 1", nil, tc.duration, tc.err)
+
+			assert.Empty(t, h.records)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/pkg/errors"
+)
+
+// scannerType — тип sql.Scanner: такие структуры сканируются целиком, а не по полям
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// fieldsMapper определяет, есть ли у структуры поля для заполнения; как и в sqlx,
+// для этого не важно, каким Mapper выполняется запрос
+var fieldsMapper = reflectx.NewMapperFunc("db", strings.ToLower)
+
+// MappingError описывает расхождение колонок результата запроса и полей структуры.
+// Возвращается Get и Select в режиме StrictMapping и VerifyMapping.
+type MappingError struct {
+	Type            string   // тип структуры назначения, например "models.User"
+	UnmappedColumns []string // колонки результата без поля в структуре
+	UnmappedFields  []string // поля структуры (имена db) без колонки в результате
+}
+
+// Error перечисляет колонки и поля без соответствия
+func (e *MappingError) Error() string {
+	var parts []string
+	if len(e.UnmappedColumns) > 0 {
+		parts = append(parts, "unmapped columns: "+strings.Join(e.UnmappedColumns, ", "))
+	}
+	if len(e.UnmappedFields) > 0 {
+		parts = append(parts, "unmapped fields: "+strings.Join(e.UnmappedFields, ", "))
+	}
+	return fmt.Sprintf("struct mapping mismatch for %s: %s", e.Type, strings.Join(parts, "; "))
+}
+
+// VerifyMapping выполняет запрос и сверяет колонки результата с полями структуры T
+// по тегам db. Возвращает *MappingError, если у колонки нет поля или у поля нет колонки.
+// Предназначена для тестов: находит расхождение схемы и структуры до выполнения кода.
+//
+//	err := sqlx.VerifyMapping[User](ctx, db, "SELECT * FROM users WHERE id = $1", 0)
+func VerifyMapping[T any](ctx context.Context, q sqlx.QueryerContext, query string, args ...any) error {
+	t := mappedStruct(reflect.TypeOf((*T)(nil)))
+	if t == nil {
+		return errors.Errorf("failed to verify mapping: %T is not a struct with db fields", *new(T))
+	}
+
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute query")
+	}
+	defer func() { _ = rows.Close() }()
+
+	if err := verifyColumns(rows, t); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// getContext выполняет запрос и заполняет одну запись, как sqlx.GetContext.
+// В режиме strict колонки результата предварительно сверяются с полями структуры.
+func getContext(ctx context.Context, q sqlx.QueryerContext, strict bool, dst any, query string, args ...any) error {
+	t := mappedStruct(reflect.TypeOf(dst))
+	if !strict || t == nil {
+		return sqlx.GetContext(ctx, q, dst, query, args...)
+	}
+
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if err := verifyColumns(rows, t); err != nil {
+		return err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rows.StructScan(dst); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// selectContext выполняет запрос и заполняет срез записей, как sqlx.SelectContext.
+// В режиме strict колонки результата предварительно сверяются с полями структуры.
+func selectContext(ctx context.Context, q sqlx.QueryerContext, strict bool, dst any, query string, args ...any) error {
+	t := mappedStruct(reflect.TypeOf(dst))
+	if !strict || t == nil {
+		return sqlx.SelectContext(ctx, q, dst, query, args...)
+	}
+
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if err := verifyColumns(rows, t); err != nil {
+		return err
+	}
+	return sqlx.StructScan(rows, dst)
+}
+
+// mappedStruct возвращает структуру, которая заполняется по полям, для назначения типа t
+// (указатель на структуру или срез структур); nil — назначение сканируется целиком
+// (скаляр, sql.Scanner или структура без экспортируемых полей, например time.Time)
+func mappedStruct(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(scannerType) {
+		return nil
+	}
+	if len(fieldsMapper.TypeMap(t).Index) == 0 {
+		return nil
+	}
+	return t
+}
+
+// verifyColumns сверяет колонки rows с полями структуры t
+func verifyColumns(rows *sqlx.Rows, t reflect.Type) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return errors.Wrap(err, "failed to get result columns")
+	}
+	if err := checkMapping(rows.Mapper, t, columns); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkMapping возвращает *MappingError, если у колонки нет поля или у поля структуры t нет колонки.
+// Поле считается заполненным, если колонка соответствует ему, вложенному в него полю
+// или содержащей его структуре; встроенные структуры проверяются по их полям.
+func checkMapping(mapper *reflectx.Mapper, t reflect.Type, columns []string) error {
+	tm := mapper.TypeMap(t)
+	direct := make(map[*reflectx.FieldInfo]bool, len(columns))
+	partial := make(map[*reflectx.FieldInfo]bool)
+	mappingErr := &MappingError{Type: t.String()}
+	for _, column := range columns {
+		fi := tm.GetByPath(column)
+		if fi == nil {
+			mappingErr.UnmappedColumns = append(mappingErr.UnmappedColumns, column)
+			continue
+		}
+		direct[fi] = true
+		for parent := fi.Parent; parent != nil; parent = parent.Parent {
+			partial[parent] = true
+		}
+	}
+	mappingErr.UnmappedFields = unmappedFields(tm.Tree, direct, partial)
+
+	if len(mappingErr.UnmappedColumns) == 0 && len(mappingErr.UnmappedFields) == 0 {
+		return nil
+	}
+	return mappingErr
+}
+
+// unmappedFields возвращает пути полей node без колонки. direct содержит поля с колонкой,
+// partial — структуры, часть вложенных полей которых имеет колонку: в них проверяются
+// вложенные поля, остальные поля без колонки сообщаются целиком.
+func unmappedFields(node *reflectx.FieldInfo, direct, partial map[*reflectx.FieldInfo]bool) []string {
+	var fields []string
+	for _, child := range node.Children {
+		switch {
+		case child == nil || direct[child]:
+		case child.Embedded || partial[child]:
+			fields = append(fields, unmappedFields(child, direct, partial)...)
+		default:
+			fields = append(fields, child.Path)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,100 @@
+package sqlx
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mappingAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type mappingAddress struct {
+	City   string `db:"city"`
+	Street string `db:"street"`
+}
+
+type mappingUser struct {
+	mappingAudit
+	ID       int64          `db:"id"`
+	Name     sql.NullString `db:"name"`
+	Address  mappingAddress `db:"address"`
+	Internal string         `db:"-"`
+}
+
+// TestCheckMapping tests detection of unmapped columns and struct fields.
+func TestCheckMapping(t *testing.T) {
+	t.Parallel()
+	mapper := reflectx.NewMapperFunc("db", strings.ToLower)
+	userType := reflect.TypeOf(mappingUser{})
+
+	tests := []struct {
+		name            string
+		columns         []string
+		unmappedColumns []string
+		unmappedFields  []string
+	}{
+		{
+			name:    "all mapped",
+			columns: []string{"id", "name", "created_at", "updated_at", "address.city", "address.street"},
+		},
+		{
+			name:            "extra columns",
+			columns:         []string{"id", "name", "created_at", "updated_at", "address.city", "address.street", "email", "deleted_at"},
+			unmappedColumns: []string{"email", "deleted_at"},
+		},
+		{
+			name:           "missing fields",
+			columns:        []string{"id", "created_at", "address.city"},
+			unmappedFields: []string{"updated_at", "name", "address.street"},
+		},
+		{
+			name:            "both",
+			columns:         []string{"id", "email"},
+			unmappedColumns: []string{"email"},
+			unmappedFields:  []string{"created_at", "updated_at", "name", "address"},
+		},
+	}
+	for _, tt := range tests {
+		err := checkMapping(mapper, userType, tt.columns)
+		if tt.unmappedColumns == nil && tt.unmappedFields == nil {
+			assert.NoError(t, err, tt.name)
+			continue
+		}
+		var mappingErr *MappingError
+		require.ErrorAs(t, err, &mappingErr, tt.name)
+		assert.Equal(t, "sqlx.mappingUser", mappingErr.Type, tt.name)
+		assert.Equal(t, tt.unmappedColumns, mappingErr.UnmappedColumns, tt.name)
+		assert.Equal(t, tt.unmappedFields, mappingErr.UnmappedFields, tt.name)
+	}
+}
+
+// TestMappingError_Error tests the error message listing unmapped columns and fields.
+func TestMappingError_Error(t *testing.T) {
+	t.Parallel()
+	err := &MappingError{Type: "models.User", UnmappedColumns: []string{"email", "phone"}, UnmappedFields: []string{"name"}}
+	assert.EqualError(t, err, "struct mapping mismatch for models.User: unmapped columns: email, phone; unmapped fields: name")
+}
+
+// TestMappedStruct tests which destinations are checked field by field.
+func TestMappedStruct(t *testing.T) {
+	t.Parallel()
+	userType := reflect.TypeOf(mappingUser{})
+
+	assert.Equal(t, userType, mappedStruct(reflect.TypeOf(&mappingUser{})))
+	assert.Equal(t, userType, mappedStruct(reflect.TypeOf(&[]mappingUser{})))
+	assert.Equal(t, userType, mappedStruct(reflect.TypeOf(&[]*mappingUser{})))
+	assert.Nil(t, mappedStruct(reflect.TypeOf(new(int))))
+	assert.Nil(t, mappedStruct(reflect.TypeOf(&[]string{})))
+	assert.Nil(t, mappedStruct(reflect.TypeOf(new([]byte))))
+	assert.Nil(t, mappedStruct(reflect.TypeOf(new(time.Time))))
+	assert.Nil(t, mappedStruct(reflect.TypeOf(new(sql.NullString))))
+}
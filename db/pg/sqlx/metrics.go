@@ -0,0 +1,14 @@
+package sqlx
+
+import (
+	"context"
+	"time"
+)
+
+// observeQuery записывает метрики запроса по отпечатку (Config.QueryMetrics) и логирует его (см. logQuery)
+func observeQuery(ctx context.Context, cfg Config, operation, query string, args []any, rows int64, duration time.Duration, err error) {
+	if cfg.QueryMetrics != nil {
+		cfg.QueryMetrics.Record(ctx, query, duration, err)
+	}
+	logQuery(ctx, cfg, operation, query, args, rows, duration, err)
+}
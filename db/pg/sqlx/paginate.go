@@ -0,0 +1,232 @@
+package sqlx
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// Ограничения размера страницы
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 1000
+)
+
+// ErrInvalidCursor возвращается для повреждённого или чужого токена курсора
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// Selecter выполняет запрос и заполняет срез записей; реализуется Connection и Tx
+type Selecter interface {
+	Select(ctx context.Context, dst any, query string, args ...any) error
+}
+
+var (
+	_ Selecter = (*Connection)(nil)
+	_ Selecter = (*Tx)(nil)
+)
+
+// Keyset — порядок записей для постраничной выборки: колонки результата запроса
+// и общее направление сортировки. Последняя колонка должна быть уникальной (обычно id),
+// иначе записи с одинаковыми значениями на границе страниц пропускаются.
+type Keyset struct {
+	Columns []string
+	Desc    bool
+}
+
+// Validate проверяет имена колонок через pg.ValidateIdentifier
+func (k Keyset) Validate() error {
+	for _, column := range k.Columns {
+		if err := pg.ValidateIdentifier(column); err != nil {
+			return errors.Wrap(err, "invalid keyset column")
+		}
+	}
+	return nil
+}
+
+// OrderBy возвращает фрагмент ORDER BY без ключевых слов, например `"created_at" DESC, "id" DESC`
+func (k Keyset) OrderBy() string {
+	direction := " ASC"
+	if k.Desc {
+		direction = " DESC"
+	}
+	parts := make([]string, len(k.Columns))
+	for i, column := range k.Columns {
+		parts[i] = pg.QuoteIdentifier(column) + direction
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Where возвращает условие выборки записей после курсора, например `("created_at", "id") < ($3, $4)`,
+// и его аргументы. Плейсхолдеры нумеруются с firstArg.
+func (k Keyset) Where(cursor Cursor, firstArg int) (string, []any) {
+	columns := make([]string, len(k.Columns))
+	placeholders := make([]string, len(k.Columns))
+	for i, column := range k.Columns {
+		columns[i] = pg.QuoteIdentifier(column)
+		placeholders[i] = fmt.Sprintf("$%d", firstArg+i)
+	}
+	op := ">"
+	if k.Desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, strings.Join(placeholders, ", ")), cursor.Values
+}
+
+// Cursor — позиция в выборке: значения колонок Keyset последней записи страницы
+// или смещение для PaginateOffset
+type Cursor struct {
+	Values []any `json:"v,omitempty"`
+	Offset int   `json:"o,omitempty"`
+}
+
+// EncodeCursor кодирует курсор в непрозрачный токен для передачи клиенту.
+// Значения кодируются в JSON: time.Time передаётся строкой RFC 3339, числа сохраняют точность.
+func EncodeCursor(cursor Cursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode page cursor")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor декодирует токен EncodeCursor. Ошибка оборачивает ErrInvalidCursor.
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // bigint за пределами 2^53 не теряет точность
+	if err := decoder.Decode(&cursor); err != nil {
+		return cursor, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	if cursor.Offset < 0 {
+		return cursor, errors.Wrap(ErrInvalidCursor, "negative offset")
+	}
+	return cursor, nil
+}
+
+// PageRequest — параметры запроса страницы
+type PageRequest struct {
+	Limit  int    // Размер страницы; 0 — DefaultPageLimit, больше MaxPageLimit — MaxPageLimit
+	Cursor string // Токен PageResult.NextCursor; пусто — первая страница
+}
+
+// PageResult — страница записей
+type PageResult[T any] struct {
+	Items      []T
+	NextCursor string // Токен следующей страницы; пусто, если HasNext == false
+	HasNext    bool
+}
+
+// Paginate возвращает страницу результата query по ключам (keyset pagination):
+// следующая страница выбирается условием по значениям колонок Keyset последней записи,
+// поэтому стоимость не растёт с номером страницы и вставки не сдвигают страницы.
+// query — запрос без ORDER BY и LIMIT с плейсхолдерами $1, $2, ... для args; он оборачивается
+// в подзапрос, поэтому колонки Keyset — имена колонок результата.
+// cursorOf возвращает значения колонок Keyset записи в том же порядке.
+func Paginate[T any](ctx context.Context, q Selecter, req PageRequest, keyset Keyset, cursorOf func(T) []any, query string, args ...any) (PageResult[T], error) {
+	result := PageResult[T]{Items: []T{}}
+	if len(keyset.Columns) == 0 {
+		return result, errors.New("keyset has no columns")
+	}
+	if err := keyset.Validate(); err != nil {
+		return result, err
+	}
+
+	cursor, err := decodePageCursor(req.Cursor)
+	if err != nil {
+		return result, err
+	}
+
+	pageQuery := "SELECT * FROM (" + query + ") AS page"
+	queryArgs := args
+	if len(cursor.Values) > 0 {
+		if len(cursor.Values) != len(keyset.Columns) {
+			return result, errors.Wrapf(ErrInvalidCursor, "cursor has %d values for %d keyset columns",
+				len(cursor.Values), len(keyset.Columns))
+		}
+		where, whereArgs := keyset.Where(cursor, len(args)+1)
+		pageQuery += " WHERE " + where
+		queryArgs = append(append([]any{}, args...), whereArgs...)
+	}
+	limit := normalizePageLimit(req.Limit)
+	pageQuery += fmt.Sprintf(" ORDER BY %s LIMIT %d", keyset.OrderBy(), limit+1)
+
+	if err := q.Select(ctx, &result.Items, pageQuery, queryArgs...); err != nil {
+		return result, errors.Wrap(err, "failed to select page")
+	}
+	if len(result.Items) <= limit {
+		return result, nil
+	}
+
+	result.Items = result.Items[:limit]
+	result.HasNext = true
+	values := cursorOf(result.Items[limit-1])
+	if len(values) != len(keyset.Columns) {
+		return result, errors.Errorf("cursorOf returned %d values for %d keyset columns", len(values), len(keyset.Columns))
+	}
+	result.NextCursor, err = EncodeCursor(Cursor{Values: values})
+	return result, err
+}
+
+// PaginateOffset возвращает страницу результата query по смещению (LIMIT/OFFSET) с тем же
+// токеном курсора, что и Paginate. Подходит для небольших выборок и сортировок,
+// которые нельзя выразить через Keyset; глубокие страницы обходятся дороже.
+func PaginateOffset[T any](ctx context.Context, q Selecter, req PageRequest, keyset Keyset, query string, args ...any) (PageResult[T], error) {
+	result := PageResult[T]{Items: []T{}}
+	if err := keyset.Validate(); err != nil {
+		return result, err
+	}
+	cursor, err := decodePageCursor(req.Cursor)
+	if err != nil {
+		return result, err
+	}
+
+	pageQuery := "SELECT * FROM (" + query + ") AS page"
+	if len(keyset.Columns) > 0 {
+		pageQuery += " ORDER BY " + keyset.OrderBy()
+	}
+	limit := normalizePageLimit(req.Limit)
+	pageQuery += fmt.Sprintf(" LIMIT %d OFFSET %d", limit+1, cursor.Offset)
+
+	if err := q.Select(ctx, &result.Items, pageQuery, args...); err != nil {
+		return result, errors.Wrap(err, "failed to select page")
+	}
+	if len(result.Items) <= limit {
+		return result, nil
+	}
+
+	result.Items = result.Items[:limit]
+	result.HasNext = true
+	result.NextCursor, err = EncodeCursor(Cursor{Offset: cursor.Offset + limit})
+	return result, err
+}
+
+// decodePageCursor декодирует токен; пустой токен — начало выборки
+func decodePageCursor(token string) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+	return DecodeCursor(token)
+}
+
+// normalizePageLimit приводит размер страницы к диапазону (0, MaxPageLimit]
+func normalizePageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageLimit
+	case limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return limit
+	}
+}
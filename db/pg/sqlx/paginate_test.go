@@ -0,0 +1,135 @@
+package sqlx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+type pageRow struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+// fakeSelecter records the query and returns up to the requested rows.
+type fakeSelecter struct {
+	rows  []pageRow
+	query string
+	args  []any
+}
+
+func (f *fakeSelecter) Select(_ context.Context, dst any, query string, args ...any) error {
+	f.query = query
+	f.args = args
+	*dst.(*[]pageRow) = append([]pageRow{}, f.rows...)
+	return nil
+}
+
+// TestKeyset tests ORDER BY and WHERE fragments.
+func TestKeyset(t *testing.T) {
+	t.Parallel()
+	keyset := Keyset{Columns: []string{"created_at", "id"}, Desc: true}
+	assert.Equal(t, `"created_at" DESC, "id" DESC`, keyset.OrderBy())
+
+	where, args := keyset.Where(Cursor{Values: []any{"2024-01-01", 7}}, 3)
+	assert.Equal(t, `("created_at", "id") < ($3, $4)`, where)
+	assert.Equal(t, []any{"2024-01-01", 7}, args)
+
+	where, _ = Keyset{Columns: []string{"id"}}.Where(Cursor{Values: []any{1}}, 1)
+	assert.Equal(t, `("id") > ($1)`, where)
+
+	require.NoError(t, keyset.Validate())
+	assert.ErrorIs(t, Keyset{Columns: []string{"id", "name DESC"}}.Validate(), pg.ErrInvalidIdentifier)
+}
+
+// TestPaginate_InvalidKeyset tests that a keyset with an invalid column is rejected before querying.
+func TestPaginate_InvalidKeyset(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	q := &fakeSelecter{}
+	keyset := Keyset{Columns: []string{"id); DROP TABLE users; --"}}
+
+	_, err := Paginate(ctx, q, PageRequest{}, keyset, func(r pageRow) []any { return []any{r.ID} }, "SELECT id FROM users")
+	assert.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	_, err = PaginateOffset[pageRow](ctx, q, PageRequest{}, keyset, "SELECT id FROM users")
+	assert.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	assert.Empty(t, q.query)
+}
+
+// TestCursor_EncodeDecode tests round-trip of cursor tokens and rejection of invalid ones.
+func TestCursor_EncodeDecode(t *testing.T) {
+	t.Parallel()
+	token, err := EncodeCursor(Cursor{Values: []any{"a", int64(9007199254740993)}})
+	require.NoError(t, err)
+
+	cursor, err := DecodeCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a", json.Number("9007199254740993")}, cursor.Values)
+
+	_, err = DecodeCursor("not base64!")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = DecodeCursor("bm90IGpzb24")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	negative, err := EncodeCursor(Cursor{Offset: -1})
+	require.NoError(t, err)
+	_, err = DecodeCursor(negative)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+// TestPaginate tests keyset pagination over consecutive pages.
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	keyset := Keyset{Columns: []string{"id"}}
+	cursorOf := func(r pageRow) []any { return []any{r.ID} }
+	q := &fakeSelecter{rows: []pageRow{{ID: 1}, {ID: 2}, {ID: 3}}}
+
+	page, err := Paginate(ctx, q, PageRequest{Limit: 2}, keyset, cursorOf,
+		"SELECT id, name FROM users WHERE org_id = $1", 10)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM (SELECT id, name FROM users WHERE org_id = $1) AS page ORDER BY "id" ASC LIMIT 3`, q.query)
+	assert.Equal(t, []any{10}, q.args)
+	assert.Equal(t, []pageRow{{ID: 1}, {ID: 2}}, page.Items)
+	require.True(t, page.HasNext)
+
+	q.rows = []pageRow{{ID: 3}}
+	page, err = Paginate(ctx, q, PageRequest{Limit: 2, Cursor: page.NextCursor}, keyset, cursorOf,
+		"SELECT id, name FROM users WHERE org_id = $1", 10)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM (SELECT id, name FROM users WHERE org_id = $1) AS page WHERE ("id") > ($2) ORDER BY "id" ASC LIMIT 3`, q.query)
+	assert.Equal(t, []any{10, json.Number("2")}, q.args)
+	assert.Equal(t, []pageRow{{ID: 3}}, page.Items)
+	assert.False(t, page.HasNext)
+	assert.Empty(t, page.NextCursor)
+
+	// cursor from a different keyset
+	other, err := EncodeCursor(Cursor{Values: []any{1, 2}})
+	require.NoError(t, err)
+	_, err = Paginate(ctx, q, PageRequest{Cursor: other}, keyset, cursorOf, "SELECT id FROM users")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+// TestPaginateOffset tests offset pagination and page limit bounds.
+func TestPaginateOffset(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	q := &fakeSelecter{rows: make([]pageRow, DefaultPageLimit+1)}
+
+	page, err := PaginateOffset[pageRow](ctx, q, PageRequest{}, Keyset{Columns: []string{"name", "id"}}, "SELECT id, name FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM (SELECT id, name FROM users) AS page ORDER BY "name" ASC, "id" ASC LIMIT 51 OFFSET 0`, q.query)
+	assert.Len(t, page.Items, DefaultPageLimit)
+	require.True(t, page.HasNext)
+
+	q.rows = nil
+	page, err = PaginateOffset[pageRow](ctx, q, PageRequest{Limit: 2 * MaxPageLimit, Cursor: page.NextCursor}, Keyset{}, "SELECT id, name FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM (SELECT id, name FROM users) AS page LIMIT 1001 OFFSET 50`, q.query)
+	assert.Empty(t, page.Items)
+	assert.False(t, page.HasNext)
+}
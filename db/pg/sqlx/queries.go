@@ -22,15 +22,29 @@ type Querier interface {
 	NamedQuery(ctx context.Context, query string, arg any) (*sqlx.Rows, error)
 }
 
-// Get выполняет запрос и заполняет одну запись
+// Get выполняет запрос и заполняет одну запись.
+// Если в контексте есть транзакция (WithTx), запрос выполняется в ней.
 func (c *Connection) Get(ctx context.Context, dst any, query string, args ...any) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Get(ctx, dst, query, args...)
+	}
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+
 	ctx, cancel := WithTimeout(ctx, c.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := c.WithTracing(ctx, "Get", query)
 	defer span.End()
 
-	err := c.GetContext(ctx, dst, query, args...)
+	start := time.Now()
+	err := getContext(ctx, c.DB, c.cfg.StrictMapping, dst, c.cfg.SQLCommenter.Apply(ctx, query), args...)
+	elapsed := time.Since(start)
+	err = diagnoseCancellation(ctx, c.cfg, "Get", query, start, err)
+	observeQuery(ctx, c.cfg, "Get", query, args, gotRows(err), elapsed, err)
+	c.explainSlowQuery(ctx, "Get", query, args, elapsed, err)
 	if err != nil {
 		span.RecordError(err)
 		if err == sql.ErrNoRows {
@@ -41,15 +55,29 @@ func (c *Connection) Get(ctx context.Context, dst any, query string, args ...any
 	return nil
 }
 
-// Select выполняет запрос и заполняет срез записей
+// Select выполняет запрос и заполняет срез записей.
+// Если в контексте есть транзакция (WithTx), запрос выполняется в ней.
 func (c *Connection) Select(ctx context.Context, dst any, query string, args ...any) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Select(ctx, dst, query, args...)
+	}
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+
 	ctx, cancel := WithTimeout(ctx, c.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := c.WithTracing(ctx, "Select", query)
 	defer span.End()
 
-	err := c.SelectContext(ctx, dst, query, args...)
+	start := time.Now()
+	err := selectContext(ctx, c.DB, c.cfg.StrictMapping, dst, c.cfg.SQLCommenter.Apply(ctx, query), args...)
+	elapsed := time.Since(start)
+	err = diagnoseCancellation(ctx, c.cfg, "Select", query, start, err)
+	observeQuery(ctx, c.cfg, "Select", query, args, selectedRows(dst, err), elapsed, err)
+	c.explainSlowQuery(ctx, "Select", query, args, elapsed, err)
 	if err != nil {
 		span.RecordError(err)
 		return errors.Wrap(err, "failed to execute select query")
@@ -57,15 +85,29 @@ func (c *Connection) Select(ctx context.Context, dst any, query string, args ...
 	return nil
 }
 
-// Exec выполняет запрос и возвращает результат
+// Exec выполняет запрос и возвращает результат.
+// Если в контексте есть транзакция (WithTx), запрос выполняется в ней.
 func (c *Connection) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Exec(ctx, query, args...)
+	}
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := WithTimeout(ctx, c.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := c.WithTracing(ctx, "Exec", query)
 	defer span.End()
 
-	result, err := c.ExecContext(ctx, query, args...)
+	start := time.Now()
+	result, err := c.ExecContext(ctx, c.cfg.SQLCommenter.Apply(ctx, query), args...)
+	elapsed := time.Since(start)
+	err = diagnoseCancellation(ctx, c.cfg, "Exec", query, start, err)
+	observeQuery(ctx, c.cfg, "Exec", query, args, affectedRows(result, err), elapsed, err)
+	c.explainSlowQuery(ctx, "Exec", query, args, elapsed, err)
 	if err != nil {
 		span.RecordError(err)
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -73,15 +115,27 @@ func (c *Connection) Exec(ctx context.Context, query string, args ...any) (sql.R
 	return result, nil
 }
 
-// Query выполняет запрос и возвращает строки результата
+// Query выполняет запрос и возвращает строки результата.
+// Если в контексте есть транзакция (WithTx), запрос выполняется в ней.
 func (c *Connection) Query(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Query(ctx, query, args...)
+	}
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := WithTimeout(ctx, c.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := c.WithTracing(ctx, "Query", query)
 	defer span.End()
 
-	rows, err := c.QueryxContext(ctx, query, args...)
+	start := time.Now()
+	rows, err := c.QueryxContext(ctx, c.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, c.cfg, "Query", query, start, err)
+	observeQuery(ctx, c.cfg, "Query", query, args, rowsUnknown, time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		return nil, errors.Wrap(err, "failed to execute query")
@@ -89,26 +143,44 @@ func (c *Connection) Query(ctx context.Context, query string, args ...any) (*sql
 	return rows, nil
 }
 
-// QueryRow выполняет запрос и возвращает одну строку результата
+// QueryRow выполняет запрос и возвращает одну строку результата.
+// Если в контексте есть транзакция (WithTx), запрос выполняется в ней.
 func (c *Connection) QueryRow(ctx context.Context, query string, args ...any) *sqlx.Row {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.QueryRow(ctx, query, args...)
+	}
+
 	ctx, span := c.WithTracing(ctx, "QueryRow", query)
 	defer span.End()
 
 	// Note: We don't apply QueryTimeout here because sqlx.Row is lazy-evaluated.
 	// The query is executed when Scan() is called, so canceling the context here
 	// would cause "context canceled" errors. The caller should manage context lifetime.
-	return c.QueryRowxContext(ctx, query, args...)
+	return c.QueryRowxContext(ctx, c.cfg.SQLCommenter.Apply(ctx, query), args...)
 }
 
 // NamedExec выполняет именованный запрос
 func (c *Connection) NamedExec(ctx context.Context, query string, arg any) (sql.Result, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := WithTimeout(ctx, c.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := c.WithTracing(ctx, "NamedExec", query)
 	defer span.End()
 
-	result, err := c.NamedExecContext(ctx, query, arg)
+	start := time.Now()
+	result, err := c.NamedExecContext(ctx, c.cfg.SQLCommenter.Apply(ctx, query), arg)
+	elapsed := time.Since(start)
+	err = diagnoseCancellation(ctx, c.cfg, "NamedExec", query, start, err)
+	observeQuery(ctx, c.cfg, "NamedExec", query, []any{arg}, affectedRows(result, err), elapsed, err)
+	if c.cfg.DevExplain {
+		if bound, args, bindErr := c.BindNamed(query, arg); bindErr == nil {
+			c.explainSlowQuery(ctx, "NamedExec", bound, args, elapsed, err)
+		}
+	}
 	if err != nil {
 		span.RecordError(err)
 		return nil, errors.Wrap(err, "failed to execute named query")
@@ -118,13 +190,20 @@ func (c *Connection) NamedExec(ctx context.Context, query string, arg any) (sql.
 
 // NamedQuery выполняет именованный запрос и возвращает строки результата
 func (c *Connection) NamedQuery(ctx context.Context, query string, arg any) (*sqlx.Rows, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
 	// Не отменяем контекст пока rows не будут закрыты
 	// Вызывающий должен закрыть rows через defer rows.Close()
 	ctx, cancel := WithTimeout(ctx, c.cfg.QueryTimeout)
 
 	ctx, span := c.WithTracing(ctx, "NamedQuery", query)
 
-	rows, err := c.NamedQueryContext(ctx, query, arg)
+	start := time.Now()
+	rows, err := c.NamedQueryContext(ctx, c.cfg.SQLCommenter.Apply(ctx, query), arg)
+	err = diagnoseCancellation(ctx, c.cfg, "NamedQuery", query, start, err)
+	observeQuery(ctx, c.cfg, "NamedQuery", query, []any{arg}, rowsUnknown, time.Since(start), err)
 	if err != nil {
 		cancel()
 		span.RecordError(err)
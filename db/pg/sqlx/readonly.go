@@ -0,0 +1,68 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// writeKeywords — ключевые слова изменяющих данные запросов, которые отклоняет DevReadOnlyGuard
+var writeKeywords = map[string]bool{
+	"INSERT":   true,
+	"UPDATE":   true,
+	"DELETE":   true,
+	"MERGE":    true,
+	"TRUNCATE": true,
+}
+
+// RunReadTx выполняет fn в read-only транзакции: BEGIN READ ONLY и
+// SET LOCAL default_transaction_read_only, так что изменяющие запросы завершаются ошибкой PostgreSQL.
+// Предназначен для кода, который должен работать с репликой.
+//
+// При включённом DevReadOnlyGuard изменяющие запросы (INSERT, UPDATE, DELETE, MERGE, TRUNCATE)
+// отклоняются до отправки в БД с ErrWriteInReadOnlyTx. QueryRow не проверяется.
+//
+// Если в ctx уже есть транзакция, fn выполняется в ней: read-only режим PostgreSQL не включается,
+// но DevReadOnlyGuard проверяет запросы fn.
+func (c *Connection) RunReadTx(ctx context.Context, fn TxFunc) error {
+	if outer, ok := TxFromContext(ctx); ok {
+		readTx := &Tx{tx: outer.tx, cfg: outer.cfg, readOnly: true, callbacks: outer.callbacks}
+		return fn(WithTx(ctx, readTx), readTx)
+	}
+
+	return c.RunTx(ctx, &TxOptions{ReadOnly: true}, func(ctx context.Context, tx *Tx) error {
+		if _, err := tx.tx.ExecContext(ctx, "SET LOCAL default_transaction_read_only = on"); err != nil {
+			return errors.Wrap(err, "failed to set read-only transaction")
+		}
+		return fn(ctx, tx)
+	})
+}
+
+// checkReadOnly отклоняет изменяющий запрос в read-only транзакции при включённом DevReadOnlyGuard
+func (tx *Tx) checkReadOnly(query string) error {
+	if !tx.readOnly || !tx.cfg.DevReadOnlyGuard {
+		return nil
+	}
+	if keyword := writeKeyword(query); keyword != "" {
+		return errors.WithMessagef(ErrWriteInReadOnlyTx, "%s statement", keyword)
+	}
+	return nil
+}
+
+// writeKeyword возвращает первое ключевое слово изменяющего запроса в query или пустую строку.
+// Строковые литералы, идентификаторы в кавычках и комментарии пропускаются лексером [pg.Words],
+// UPDATE в блокировках FOR UPDATE и FOR NO KEY UPDATE не считается записью.
+func writeKeyword(query string) string {
+	var prev string
+	for _, word := range pg.Words(query) {
+		word = strings.ToUpper(word)
+		if writeKeywords[word] && !(word == "UPDATE" && (prev == "FOR" || prev == "KEY")) {
+			return word
+		}
+		prev = word
+	}
+	return ""
+}
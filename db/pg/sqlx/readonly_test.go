@@ -0,0 +1,77 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteKeyword tests detection of data-modifying statements.
+func TestWriteKeyword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "SELECT * FROM users WHERE id = $1", want: ""},
+		{query: "insert into users (name) values ($1)", want: "INSERT"},
+		{query: "  UPDATE users SET name = $1", want: "UPDATE"},
+		{query: "DELETE FROM users", want: "DELETE"},
+		{query: "TRUNCATE users", want: "TRUNCATE"},
+		{query: "WITH moved AS (DELETE FROM a RETURNING *) SELECT * FROM moved", want: "DELETE"},
+		{query: "SELECT * FROM users FOR UPDATE", want: ""},
+		{query: "SELECT * FROM users FOR NO KEY UPDATE SKIP LOCKED", want: ""},
+		{query: "SELECT 'insert into users' AS text", want: ""},
+		{query: `SELECT "update" FROM audit`, want: ""},
+		{query: "SELECT deleted_at, updated_at FROM users", want: ""},
+		{query: "SELECT 1 -- delete later\n", want: ""},
+		{query: "/* UPDATE */ SELECT 1", want: ""},
+		{query: "SELECT $$ DELETE $$, $tag$ INSERT $tag$", want: ""},
+		{query: "SELECT $1, $2; DELETE FROM users", want: "DELETE"},
+		{query: "SELECT 'it''s'; INSERT INTO t VALUES (1)", want: "INSERT"},
+		{query: `SELECT E'\''; DELETE FROM users`, want: "DELETE"},
+		{query: "/* outer /* nested */ still comment */ DELETE FROM users", want: "DELETE"},
+		{query: "/* outer /* nested */ DELETE */ SELECT 1", want: ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, writeKeyword(tt.query), tt.query)
+	}
+}
+
+// TestTx_CheckReadOnly tests that the guard applies only to read-only transactions with DevReadOnlyGuard.
+func TestTx_CheckReadOnly(t *testing.T) {
+	t.Parallel()
+	const insert = "INSERT INTO users (name) VALUES ($1)"
+
+	assert.NoError(t, (&Tx{readOnly: true}).checkReadOnly(insert))
+	assert.NoError(t, (&Tx{cfg: Config{DevReadOnlyGuard: true}}).checkReadOnly(insert))
+
+	guarded := &Tx{cfg: Config{DevReadOnlyGuard: true}, readOnly: true}
+	assert.NoError(t, guarded.checkReadOnly("SELECT 1"))
+	err := guarded.checkReadOnly(insert)
+	require.ErrorIs(t, err, ErrWriteInReadOnlyTx)
+	assert.EqualError(t, err, "INSERT statement: write statement in read-only transaction")
+}
+
+// TestRunReadTx_JoinsTxFromContext tests that a read transaction inside an outer one is guarded.
+func TestRunReadTx_JoinsTxFromContext(t *testing.T) {
+	t.Parallel()
+	outer := &Tx{cfg: Config{DevReadOnlyGuard: true}}
+	ctx := WithTx(context.Background(), outer)
+
+	// Connection without DB: joining the outer transaction must not begin a new one
+	c := &Connection{}
+	err := c.RunReadTx(ctx, func(ctx context.Context, tx *Tx) error {
+		inner, ok := TxFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, tx, inner)
+
+		_, err := c.Exec(ctx, "DELETE FROM users")
+		return err
+	})
+	require.ErrorIs(t, err, ErrWriteInReadOnlyTx)
+	assert.False(t, outer.readOnly)
+}
@@ -0,0 +1,83 @@
+package sqlx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// ErrScriptTransactionControl возвращается ExecScript для скрипта с BEGIN, COMMIT, ROLLBACK и т.п.:
+// скрипт уже выполняется в транзакции
+var ErrScriptTransactionControl = errors.New("transaction control statement in script")
+
+// ExecScript выполняет SQL-скрипт из нескольких инструкций в одной транзакции (см. pg.SplitScript):
+// точки с запятой внутри строк, комментариев и dollar-quoted тел функций не разделяют инструкции.
+// Инструкции выполняются по одной через Tx.Exec, QueryTimeout действует на каждую.
+// Если в ctx уже есть транзакция (см. WithTx), скрипт выполняется в ней.
+//
+// Ошибка инструкции откатывает транзакцию и возвращается как *pg.ScriptError с номером инструкции
+// и позицией ошибки в скрипте (по Position из ответа PostgreSQL, иначе — начало инструкции).
+// Скрипт с инструкциями управления транзакцией отклоняется до выполнения (ErrScriptTransactionControl).
+func (c *Connection) ExecScript(ctx context.Context, script string) error {
+	stmts, err := pg.SplitScript(script)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse script")
+	}
+	for i, stmt := range stmts {
+		if isTransactionControl(stmt.SQL) {
+			return pg.NewScriptError(i, stmt, ErrScriptTransactionControl, 0)
+		}
+	}
+
+	ctx, span := c.WithTracing(ctx, "ExecScript", "")
+	defer span.End()
+	span.SetAttributes(attribute.Int("db.script.statements", len(stmts)))
+
+	err = c.RunTx(ctx, nil, func(ctx context.Context, tx *Tx) error {
+		for i, stmt := range stmts {
+			if _, err := tx.Exec(ctx, stmt.SQL); err != nil {
+				return pg.NewScriptError(i, stmt, err, errorPosition(err))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// isTransactionControl сообщает, что инструкция управляет транзакцией (ROLLBACK TO SAVEPOINT допустим)
+func isTransactionControl(stmt string) bool {
+	fields := strings.Fields(strings.ToLower(stmt))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "begin", "start", "commit", "end", "abort":
+		return true
+	case "rollback":
+		return len(fields) < 2 || fields[1] != "to"
+	}
+	return false
+}
+
+// errorPosition возвращает позицию ошибки в тексте запроса из ответа PostgreSQL; 0 — неизвестна
+func errorPosition(err error) int {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return 0
+	}
+	pos, err := strconv.Atoi(pqErr.Position)
+	if err != nil {
+		return 0
+	}
+	return pos
+}
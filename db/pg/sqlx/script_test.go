@@ -0,0 +1,175 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// scriptDriver is a database/sql driver recording executed statements and transaction outcomes
+type scriptDriver struct {
+	mu        sync.Mutex
+	execs     []string
+	commits   int
+	rollbacks int
+	// failOn makes statements containing the substring fail with failErr
+	failOn  string
+	failErr error
+}
+
+func (d *scriptDriver) Open(string) (driver.Conn, error) {
+	return &scriptConn{driver: d}, nil
+}
+
+type scriptConn struct {
+	driver *scriptDriver
+}
+
+func (c *scriptConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *scriptConn) Close() error { return nil }
+
+func (c *scriptConn) Begin() (driver.Tx, error) {
+	return &scriptTx{driver: c.driver}, nil
+}
+
+func (c *scriptConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	if c.driver.failOn != "" && strings.Contains(query, c.driver.failOn) {
+		return nil, c.driver.failErr
+	}
+	c.driver.execs = append(c.driver.execs, query)
+	return driver.RowsAffected(1), nil
+}
+
+type scriptTx struct {
+	driver *scriptDriver
+}
+
+func (tx *scriptTx) Commit() error {
+	tx.driver.mu.Lock()
+	defer tx.driver.mu.Unlock()
+	tx.driver.commits++
+	return nil
+}
+
+func (tx *scriptTx) Rollback() error {
+	tx.driver.mu.Lock()
+	defer tx.driver.mu.Unlock()
+	tx.driver.rollbacks++
+	return nil
+}
+
+var scriptDriverSeq atomic.Int32
+
+func newScriptConnection(t *testing.T, d *scriptDriver) *Connection {
+	t.Helper()
+	name := "sqlx-script-" + string(rune('a'+scriptDriverSeq.Add(1)))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return &Connection{DB: sqlx.NewDb(db, "postgres")}
+}
+
+// TestConnection_ExecScript tests that statements are executed one by one in a single transaction
+func TestConnection_ExecScript(t *testing.T) {
+	t.Parallel()
+	d := &scriptDriver{}
+	conn := newScriptConnection(t, d)
+
+	err := conn.ExecScript(context.Background(), `
+CREATE TABLE users (id int, name text);
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.name := lower(NEW.name);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+INSERT INTO users VALUES (1, 'a;b');
+`)
+
+	require.NoError(t, err)
+	require.Len(t, d.execs, 3)
+	assert.Equal(t, "CREATE TABLE users (id int, name text)", d.execs[0])
+	assert.True(t, strings.HasPrefix(d.execs[1], "CREATE FUNCTION touch()"))
+	assert.Equal(t, "INSERT INTO users VALUES (1, 'a;b')", d.execs[2])
+	assert.Equal(t, 1, d.commits)
+	assert.Zero(t, d.rollbacks)
+}
+
+// TestConnection_ExecScriptError tests that a failing statement rolls back the script and reports its position
+func TestConnection_ExecScriptError(t *testing.T) {
+	t.Parallel()
+	d := &scriptDriver{
+		failOn:  "usres",
+		failErr: &pq.Error{Code: "42P01", Message: `relation "usres" does not exist`, Position: "15"},
+	}
+	conn := newScriptConnection(t, d)
+
+	err := conn.ExecScript(context.Background(), "CREATE TABLE users (id int);\n\nINSERT INTO\n  usres VALUES (1);\nSELECT 1;")
+
+	var scriptErr *pg.ScriptError
+	require.ErrorAs(t, err, &scriptErr)
+	assert.Equal(t, 1, scriptErr.Index)
+	assert.Equal(t, 3, scriptErr.Statement.Line)
+	assert.Equal(t, 4, scriptErr.Line)
+	assert.Equal(t, 3, scriptErr.Column)
+	var pqErr *pq.Error
+	assert.ErrorAs(t, err, &pqErr)
+	assert.Equal(t, []string{"CREATE TABLE users (id int)"}, d.execs)
+	assert.Zero(t, d.commits)
+	assert.Equal(t, 1, d.rollbacks)
+}
+
+// TestConnection_ExecScriptRejected tests scripts rejected before execution
+func TestConnection_ExecScriptRejected(t *testing.T) {
+	t.Parallel()
+	d := &scriptDriver{}
+	conn := newScriptConnection(t, d)
+
+	err := conn.ExecScript(context.Background(), "BEGIN;\nCREATE TABLE a (id int);\nCOMMIT;")
+	var scriptErr *pg.ScriptError
+	require.ErrorAs(t, err, &scriptErr)
+	assert.Equal(t, 0, scriptErr.Index)
+	assert.ErrorIs(t, err, ErrScriptTransactionControl)
+
+	err = conn.ExecScript(context.Background(), "SELECT 'unterminated")
+	assert.ErrorContains(t, err, "unterminated string literal")
+
+	assert.Empty(t, d.execs)
+	assert.Zero(t, d.commits+d.rollbacks)
+}
+
+func TestIsTransactionControl(t *testing.T) {
+	t.Parallel()
+	for stmt, want := range map[string]bool{
+		"BEGIN":                       true,
+		"start transaction":           true,
+		"COMMIT":                      true,
+		"END":                         true,
+		"ROLLBACK":                    true,
+		"rollback work":               true,
+		"ROLLBACK TO SAVEPOINT s1":    false,
+		"SAVEPOINT s1":                false,
+		"SELECT 1":                    false,
+		"CREATE TABLE begin (id int)": false,
+	} {
+		assert.Equal(t, want, isTransactionControl(stmt), stmt)
+	}
+}
@@ -0,0 +1,51 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+var _ driver.Connector = (*sessionConnector)(nil)
+
+// sessionConnector устанавливает Config.SessionSettings на каждом новом соединении пула
+type sessionConnector struct {
+	driver.Connector
+	query string
+	args  []driver.NamedValue
+}
+
+// withSessionSettings оборачивает connector; без settings connector возвращается как есть
+func withSessionSettings(connector driver.Connector, settings map[string]string) driver.Connector {
+	query, args := pg.SessionSettingsQuery(settings)
+	if query == "" {
+		return connector
+	}
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return &sessionConnector{Connector: connector, query: query, args: named}
+}
+
+// Connect реализует driver.Connector. Соединение, на котором не удалось установить параметры, закрывается
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		_ = conn.Close()
+		return nil, errors.New("driver connection does not support ExecContext")
+	}
+	if _, err := execer.ExecContext(ctx, c.query, c.args); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "failed to apply session settings")
+	}
+	return conn, nil
+}
@@ -0,0 +1,80 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// execConn is a driver.Conn recording ExecContext calls
+type execConn struct {
+	driver.Conn
+	query   string
+	args    []driver.NamedValue
+	execErr error
+	closed  bool
+}
+
+func (c *execConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.query, c.args = query, args
+	return driver.RowsAffected(0), c.execErr
+}
+
+func (c *execConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// connConnector is a driver.Connector returning conn
+type connConnector struct {
+	driver.Connector
+	conn driver.Conn
+}
+
+func (c *connConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+
+// TestWithSessionSettings tests that session settings are applied on every new connection
+func TestWithSessionSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no settings", func(t *testing.T) {
+		t.Parallel()
+		connector := &connConnector{conn: &execConn{}}
+		assert.Same(t, connector, withSessionSettings(connector, nil))
+	})
+
+	t.Run("settings are applied", func(t *testing.T) {
+		t.Parallel()
+		conn := &execConn{}
+		connector := withSessionSettings(&connConnector{conn: conn}, map[string]string{
+			"statement_timeout": "5s",
+			"application_name":  "orders",
+		})
+
+		got, err := connector.Connect(context.Background())
+		require.NoError(t, err)
+		assert.Same(t, conn, got)
+		assert.Equal(t, "SELECT set_config($1, $2, false), set_config($3, $4, false)", conn.query)
+		assert.Equal(t, []driver.NamedValue{
+			{Ordinal: 1, Value: "application_name"},
+			{Ordinal: 2, Value: "orders"},
+			{Ordinal: 3, Value: "statement_timeout"},
+			{Ordinal: 4, Value: "5s"},
+		}, conn.args)
+		assert.False(t, conn.closed)
+	})
+
+	t.Run("rejected setting closes the connection", func(t *testing.T) {
+		t.Parallel()
+		conn := &execConn{execErr: errors.New(`unrecognized configuration parameter "bogus"`)}
+		connector := withSessionSettings(&connConnector{conn: conn}, map[string]string{"bogus": "1"})
+
+		_, err := connector.Connect(context.Background())
+		require.ErrorContains(t, err, "failed to apply session settings")
+		assert.True(t, conn.closed)
+	})
+}
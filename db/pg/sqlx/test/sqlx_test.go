@@ -1,23 +1,25 @@
 package sqlx_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
-	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/db/pg/pgtest"
 	"github.com/pure-golang/adapters/db/pg/sqlx"
+	"github.com/pure-golang/adapters/logger"
 )
 
 var testDB *sqlx.Connection
@@ -37,84 +39,28 @@ func TestMain(m *testing.M) {
 func runTests(m *testing.M) int {
 	ctx := context.Background()
 
-	req := testcontainers.ContainerRequest{
-		Image:        "postgres:15",
-		ExposedPorts: []string{"5432/tcp"},
-		Env: map[string]string{
-			"POSTGRES_PASSWORD": "secret",
-			"POSTGRES_USER":     "test_user",
-			"POSTGRES_DB":       "test_db",
-		},
-		WaitingFor: wait.ForLog("database system is ready to accept connections"),
-	}
-
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
+	postgres, err := pgtest.Start(ctx, nil)
 	if err != nil {
-		log.Printf("Could not start container: %s", err)
+		log.Printf("Could not start postgres: %s", err)
 		return 1
 	}
-
 	defer func() {
-		if err := container.Terminate(ctx); err != nil {
+		if err := postgres.Terminate(ctx); err != nil {
 			fmt.Printf("Warning: could not terminate container: %s\n", err)
 		}
 	}()
 
-	host, err := container.Host(ctx)
+	testCfg = postgres.Config("")
+	testDB, err = sqlx.Connect(ctx, testCfg)
 	if err != nil {
-		log.Printf("Could not get container host: %s", err)
-		return 1
-	}
-
-	mappedPort, err := container.MappedPort(ctx, "5432")
-	if err != nil {
-		log.Printf("Could not get container port: %s", err)
-		return 1
-	}
-
-	port, err := strconv.Atoi(mappedPort.Port())
-	if err != nil {
-		log.Printf("Could not parse port: %s", err)
-		return 1
-	}
-
-	testCfg = sqlx.Config{
-		Host:           host,
-		Port:           port,
-		User:           "test_user",
-		Password:       "secret",
-		Database:       "test_db",
-		SSLMode:        "disable",
-		ConnectTimeout: 5,
-		QueryTimeout:   30 * time.Second,
-	}
-
-	maxRetries := 30
-	retryInterval := 500 * time.Millisecond
-
-	for i := range maxRetries {
-		testDB, err = sqlx.Connect(ctx, testCfg)
-		if err == nil {
-			break
-		}
-		if i < maxRetries-1 {
-			time.Sleep(retryInterval)
-		}
-	}
-	if err != nil {
-		log.Printf("Could not connect to database after %d retries: %s", maxRetries, err)
+		log.Printf("Could not connect to database: %s", err)
 		return 1
 	}
 
 	code := m.Run()
 
-	if testDB != nil {
-		if err := testDB.Close(); err != nil {
-			fmt.Printf("Warning: failed to close test DB: %s\n", err)
-		}
+	if err := testDB.Close(); err != nil {
+		fmt.Printf("Warning: failed to close test DB: %s\n", err)
 	}
 
 	return code
@@ -300,6 +246,8 @@ func TestConnection_QueryTimeout(t *testing.T) {
 
 	shortTimeoutCfg := testCfg
 	shortTimeoutCfg.QueryTimeout = 100 * time.Millisecond
+	var hooked *sqlx.QueryCancelledError
+	shortTimeoutCfg.OnQueryCancelled = func(_ context.Context, err *sqlx.QueryCancelledError) { hooked = err }
 	shortTimeoutDB, err := sqlx.Connect(ctx, shortTimeoutCfg)
 	require.NoError(t, err)
 	t.Cleanup(func() { shortTimeoutDB.Close() })
@@ -307,6 +255,27 @@ func TestConnection_QueryTimeout(t *testing.T) {
 	_, err = shortTimeoutDB.Exec(ctx, "SELECT test_delay()")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "canceling statement due to user request")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var cancelled *sqlx.QueryCancelledError
+	require.ErrorAs(t, err, &cancelled)
+	require.Same(t, cancelled, hooked)
+	require.Equal(t, "Exec", cancelled.Operation)
+	require.Equal(t, "select test_delay()", cancelled.Fingerprint)
+	require.Equal(t, sqlx.CancelReasonDeadline, cancelled.Reason)
+	require.Equal(t, 100*time.Millisecond, cancelled.QueryTimeout)
+	require.GreaterOrEqual(t, cancelled.Elapsed, 100*time.Millisecond)
+	require.InDelta(t, 100*time.Millisecond, cancelled.Budget, float64(10*time.Millisecond))
+
+	// statement_timeout is reported by the server without a context deadline
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	_, err = tx.Exec(ctx, "SET LOCAL statement_timeout = 50")
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "SELECT test_delay()")
+	require.ErrorAs(t, err, &cancelled)
+	require.Equal(t, sqlx.CancelReasonStatementTimeout, cancelled.Reason)
 }
 
 func TestConnection_ErrorHandling(t *testing.T) {
@@ -1009,3 +978,462 @@ func TestGetConstraintName(t *testing.T) {
 	name = sqlx.GetConstraintName(err)
 	require.NotEmpty(t, name)
 }
+
+func TestConnection_TxFromContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS test_ctx_tx (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL
+		)
+	`)
+	require.NoError(t, err)
+
+	// Repository-style function that is unaware of transactions
+	insert := func(ctx context.Context, name string) error {
+		_, err := testDB.Exec(ctx, "INSERT INTO test_ctx_tx (name) VALUES ($1)", name)
+		return err
+	}
+
+	errAbort := errors.New("abort")
+	err = testDB.RunTx(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := insert(ctx, "rolled back"); err != nil {
+			return err
+		}
+
+		var count int
+		if err := testDB.Get(ctx, &count, "SELECT COUNT(*) FROM test_ctx_tx WHERE name = $1", "rolled back"); err != nil {
+			return err
+		}
+		require.Equal(t, 1, count, "row must be visible inside the transaction")
+
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	var count int
+	err = testDB.Get(ctx, &count, "SELECT COUNT(*) FROM test_ctx_tx WHERE name = $1", "rolled back")
+	require.NoError(t, err)
+	require.Equal(t, 0, count, "insert through context transaction must be rolled back")
+
+	// Nested RunTx joins the outer transaction
+	err = testDB.RunTx(ctx, nil, func(ctx context.Context, outer *sqlx.Tx) error {
+		return testDB.RunTx(ctx, nil, func(ctx context.Context, inner *sqlx.Tx) error {
+			require.Same(t, outer, inner)
+			return insert(ctx, "committed")
+		})
+	})
+	require.NoError(t, err)
+
+	err = testDB.Get(ctx, &count, "SELECT COUNT(*) FROM test_ctx_tx WHERE name = $1", "committed")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestConnection_DevExplain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	var buf bytes.Buffer
+	ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	cfg := testCfg
+	cfg.SlowQueryThreshold = time.Nanosecond
+	cfg.DevExplain = true
+	db, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(ctx, `CREATE TABLE IF NOT EXISTS test_explain (id SERIAL PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	var ids []int
+	err = db.Select(ctx, &ids, `SELECT id FROM test_explain WHERE name = $1`, "explain")
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "slow query plan")
+	require.Contains(t, buf.String(), "Seq Scan on test_explain")
+
+	// the INSERT is not repeated: EXPLAIN ANALYZE fails in the read-only transaction
+	_, err = db.Exec(ctx, `INSERT INTO test_explain (name) VALUES ($1)`, "explain")
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "failed to explain slow query")
+
+	var count int
+	require.NoError(t, testDB.Get(context.Background(), &count, `SELECT count(*) FROM test_explain`))
+	require.Equal(t, 1, count)
+}
+
+func TestConnection_SQLCommenter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := pg.ContextWithCommentTags(context.Background(), map[string]string{"route": "/api.Users/Get"})
+
+	cfg := testCfg
+	cfg.SQLCommenter = &pg.SQLCommenter{Application: "sqlx-test"}
+	db, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// pg_stat_activity shows the text of the running query, including the comment
+	var query string
+	err = db.Get(ctx, &query, `SELECT query FROM pg_stat_activity WHERE pid = pg_backend_pid()`)
+	require.NoError(t, err)
+	require.Contains(t, query, "/*application='sqlx-test',route='%2Fapi.Users%2FGet'")
+}
+
+func TestConnection_RunReadTx(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `CREATE TABLE IF NOT EXISTS test_read_tx (id SERIAL PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	err = testDB.RunReadTx(ctx, func(ctx context.Context, _ *sqlx.Tx) error {
+		var readOnly string
+		if err := testDB.Get(ctx, &readOnly, `SHOW default_transaction_read_only`); err != nil {
+			return err
+		}
+		require.Equal(t, "on", readOnly)
+
+		var count int
+		return testDB.Get(ctx, &count, `SELECT count(*) FROM test_read_tx`)
+	})
+	require.NoError(t, err)
+
+	// PostgreSQL rejects writes in a read-only transaction
+	err = testDB.RunReadTx(ctx, func(ctx context.Context, _ *sqlx.Tx) error {
+		_, err := testDB.Exec(ctx, `INSERT INTO test_read_tx (name) VALUES ($1)`, "read")
+		return err
+	})
+	require.ErrorContains(t, err, "read-only transaction")
+
+	// the dev guard rejects writes before they reach the database
+	cfg := testCfg
+	cfg.DevReadOnlyGuard = true
+	db, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = db.RunReadTx(ctx, func(ctx context.Context, _ *sqlx.Tx) error {
+		_, err := db.Exec(ctx, `UPDATE test_read_tx SET name = $1`, "read")
+		return err
+	})
+	require.ErrorIs(t, err, sqlx.ErrWriteInReadOnlyTx)
+}
+
+func TestConnection_TxHooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	var events []string
+	cfg := testCfg
+	cfg.TxHooks = sqlx.TxHooks{
+		OnBegin:    func(context.Context, sqlx.TxEvent) { events = append(events, "begin") },
+		OnCommit:   func(context.Context, sqlx.TxEvent) { events = append(events, "commit") },
+		OnRollback: func(context.Context, sqlx.TxEvent) { events = append(events, "rollback") },
+	}
+	db, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(ctx, `CREATE TABLE IF NOT EXISTS test_tx_hooks (id SERIAL PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	// the after-commit function sees the committed row
+	var count int
+	err = db.RunTx(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		tx.AfterCommit(func() {
+			require.NoError(t, db.Get(context.Background(), &count, `SELECT count(*) FROM test_tx_hooks WHERE name = $1`, "hooks"))
+		})
+		_, err := db.Exec(ctx, `INSERT INTO test_tx_hooks (name) VALUES ($1)`, "hooks")
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	err = db.RunTx(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		tx.AfterCommit(func() { t.Error("after-commit function must not run on rollback") })
+		_, err := db.Exec(ctx, `INSERT INTO test_tx_hooks (missing) VALUES ($1)`, "hooks")
+		return err
+	})
+	require.Error(t, err)
+	require.Equal(t, []string{"begin", "commit", "begin", "rollback"}, events)
+}
+
+func TestConnection_StrictMapping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `CREATE TABLE IF NOT EXISTS test_strict_mapping (id SERIAL PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL DEFAULT '')`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(ctx, `INSERT INTO test_strict_mapping (name) VALUES ($1)`, "strict")
+	require.NoError(t, err)
+
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	require.NoError(t, sqlx.VerifyMapping[user](ctx, testDB, `SELECT id, name FROM test_strict_mapping`))
+
+	err = sqlx.VerifyMapping[user](ctx, testDB, `SELECT * FROM test_strict_mapping`)
+	var mappingErr *sqlx.MappingError
+	require.ErrorAs(t, err, &mappingErr)
+	require.Equal(t, []string{"email"}, mappingErr.UnmappedColumns)
+
+	cfg := testCfg
+	cfg.StrictMapping = true
+	db, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var users []user
+	require.NoError(t, db.Select(ctx, &users, `SELECT id, name FROM test_strict_mapping`))
+	require.NotEmpty(t, users)
+
+	var u user
+	err = db.Get(ctx, &u, `SELECT id FROM test_strict_mapping LIMIT 1`)
+	require.ErrorAs(t, err, &mappingErr)
+	require.Equal(t, []string{"name"}, mappingErr.UnmappedFields)
+
+	err = db.Get(ctx, &u, `SELECT id, name FROM test_strict_mapping WHERE id < 0`)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = db.RunTx(ctx, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		return tx.Select(ctx, &users, `SELECT * FROM test_strict_mapping`)
+	})
+	require.ErrorAs(t, err, &mappingErr)
+}
+
+type testOrderStatus string
+
+func (testOrderStatus) EnumLabels() []testOrderStatus {
+	return []testOrderStatus{"new", "paid", "shipped"}
+}
+
+func TestVerifyEnum(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `DROP TYPE IF EXISTS test_order_status CASCADE`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(ctx, `CREATE TYPE test_order_status AS ENUM ('new', 'paid', 'shipped')`)
+	require.NoError(t, err)
+	require.NoError(t, sqlx.VerifyEnum[testOrderStatus](ctx, testDB, "test_order_status"))
+
+	var status sqlx.EnumScanner[testOrderStatus]
+	require.NoError(t, testDB.QueryRow(ctx, `SELECT 'paid'::test_order_status`).Scan(&status))
+	require.Equal(t, testOrderStatus("paid"), status.V)
+
+	_, err = testDB.Exec(ctx, `ALTER TYPE test_order_status ADD VALUE 'refunded'`)
+	require.NoError(t, err)
+	err = sqlx.VerifyEnum[testOrderStatus](ctx, testDB, "test_order_status")
+	var mismatch *sqlx.EnumMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, []string{"refunded"}, mismatch.MissingInGo)
+
+	err = testDB.QueryRow(ctx, `SELECT 'refunded'::test_order_status`).Scan(&status)
+	require.ErrorIs(t, err, sqlx.ErrInvalidEnumValue)
+
+	err = sqlx.VerifyEnum[testOrderStatus](ctx, testDB, "test_missing_enum")
+	require.Error(t, err)
+}
+
+func TestPaginate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `CREATE TABLE IF NOT EXISTS test_paginate (id SERIAL PRIMARY KEY, score INT NOT NULL)`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(ctx, `TRUNCATE test_paginate RESTART IDENTITY`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(ctx, `INSERT INTO test_paginate (score) SELECT g % 3 FROM generate_series(1, 7) AS g`)
+	require.NoError(t, err)
+
+	type row struct {
+		ID    int64 `db:"id"`
+		Score int   `db:"score"`
+	}
+	keyset := sqlx.Keyset{Columns: []string{"score", "id"}, Desc: true}
+	cursorOf := func(r row) []any { return []any{r.Score, r.ID} }
+
+	var ids []int64
+	req := sqlx.PageRequest{Limit: 3}
+	for {
+		page, err := sqlx.Paginate(ctx, testDB, req, keyset, cursorOf,
+			`SELECT id, score FROM test_paginate WHERE id > $1`, 0)
+		require.NoError(t, err)
+		for _, r := range page.Items {
+			ids = append(ids, r.ID)
+		}
+		if !page.HasNext {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	require.Equal(t, []int64{5, 2, 7, 4, 1, 6, 3}, ids)
+
+	page, err := sqlx.PaginateOffset[row](ctx, testDB, sqlx.PageRequest{Limit: 5}, keyset, `SELECT id, score FROM test_paginate`)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 5)
+	require.True(t, page.HasNext)
+
+	page, err = sqlx.PaginateOffset[row](ctx, testDB, sqlx.PageRequest{Limit: 5, Cursor: page.NextCursor}, keyset, `SELECT id, score FROM test_paginate`)
+	require.NoError(t, err)
+	require.Equal(t, []row{{ID: 6, Score: 0}, {ID: 3, Score: 0}}, page.Items)
+	require.False(t, page.HasNext)
+}
+
+func TestGetOne_SelectAll(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `CREATE TABLE IF NOT EXISTS test_typed (id SERIAL PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(ctx, `TRUNCATE test_typed RESTART IDENTITY`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(ctx, `INSERT INTO test_typed (name) VALUES ('a'), ('b')`)
+	require.NoError(t, err)
+
+	type row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	value, err := sqlx.GetOne[row](ctx, testDB, `SELECT id, name FROM test_typed WHERE id = $1`, 1)
+	require.NoError(t, err)
+	require.Equal(t, row{ID: 1, Name: "a"}, value)
+
+	ptr, err := sqlx.GetOne[*row](ctx, testDB, `SELECT id, name FROM test_typed WHERE id = $1`, 2)
+	require.NoError(t, err)
+	require.Equal(t, &row{ID: 2, Name: "b"}, ptr)
+
+	count, err := sqlx.GetOne[int](ctx, testDB, `SELECT count(*) FROM test_typed`)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	ptr, err = sqlx.GetOne[*row](ctx, testDB, `SELECT id, name FROM test_typed WHERE id = $1`, 999)
+	require.Nil(t, ptr)
+	require.True(t, sqlx.IsNotFound(err))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	rows, err := sqlx.SelectAll[*row](ctx, testDB, `SELECT id, name FROM test_typed ORDER BY id`)
+	require.NoError(t, err)
+	require.Equal(t, []*row{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, rows)
+
+	names, err := sqlx.SelectAll[string](ctx, testDB, `SELECT name FROM test_typed WHERE id > $1`, 5)
+	require.NoError(t, err)
+	require.Empty(t, names)
+	require.NotNil(t, names)
+}
+
+func TestConnect_CredentialsRotation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	_, err := testDB.Exec(ctx, `CREATE ROLE rotated LOGIN PASSWORD 'p1'`)
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = testDB.Exec(context.Background(), `DROP ROLE rotated`) })
+
+	var mu sync.Mutex
+	password := "p1"
+	cfg := testCfg
+	cfg.User = "rotated"
+	cfg.Password = ""
+	cfg.Credentials = pg.CredentialsFunc(func(context.Context) (pg.Credentials, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return pg.Credentials{Password: password}, nil
+	})
+	db, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+
+	_, err = testDB.Exec(ctx, `ALTER ROLE rotated PASSWORD 'p2'`)
+	require.NoError(t, err)
+	mu.Lock()
+	password = "p2"
+	mu.Unlock()
+
+	// a new connection fails with the cached password, refreshes it and reconnects transparently
+	other, err := db.Conn(ctx)
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	// the connection in use keeps working and is discarded after release
+	require.NoError(t, conn.PingContext(ctx))
+	require.NoError(t, conn.Close())
+	require.NoError(t, db.PingContext(ctx))
+}
+
+func TestConnection_ExportQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	query := `SELECT 1::bigint AS id, 'a,b'::text AS name, 10.50::numeric AS amount,
+		'2026-01-02 03:04:05+00'::timestamptz AS created_at, '{"k": 1}'::jsonb AS attrs, NULL::text AS note`
+
+	var buf bytes.Buffer
+	n, err := testDB.ExportQuery(ctx, &buf, sqlx.ExportCSV, query)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	require.Equal(t, "id,name,amount,created_at,attrs,note\n"+
+		`1,"a,b",10.50,2026-01-02T03:04:05Z,"{""k"": 1}",`+"\n", buf.String())
+
+	buf.Reset()
+	n, err = testDB.ExportQuery(ctx, &buf, sqlx.ExportNDJSON, `SELECT g AS id FROM generate_series(1, 3) g`)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, n)
+	require.Equal(t, "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n", buf.String())
+
+	_, err = testDB.ExportQuery(ctx, &buf, sqlx.ExportCSV, "SELECT * FROM nonexistent_table")
+	require.ErrorContains(t, err, "failed to execute export query")
+}
+
+func TestConnect_SessionSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	cfg := testCfg
+	cfg.SessionSettings = map[string]string{"statement_timeout": "1234ms", "application_name": "orders"}
+	conn, err := sqlx.Connect(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	var timeout, application string
+	require.NoError(t, conn.Get(ctx, &timeout, "SHOW statement_timeout"))
+	require.Equal(t, "1234ms", timeout)
+	require.NoError(t, conn.Get(ctx, &application, "SHOW application_name"))
+	require.Equal(t, "orders", application)
+
+	cfg.SessionSettings = map[string]string{"bogus_setting": "1"}
+	_, err = sqlx.Connect(ctx, cfg)
+	require.ErrorContains(t, err, "failed to apply session settings")
+}
@@ -3,6 +3,7 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
@@ -10,8 +11,10 @@ import (
 
 // Tx представляет транзакцию в базе данных
 type Tx struct {
-	tx  *sqlx.Tx
-	cfg Config
+	tx        *sqlx.Tx
+	cfg       Config
+	readOnly  bool
+	callbacks *txCallbacks
 }
 
 // TxFunc определяет функцию, которая будет выполняться в рамках транзакции
@@ -34,6 +37,10 @@ func DefaultTxOptions() *TxOptions {
 
 // BeginTx начинает новую транзакцию с заданными опциями
 func (c *Connection) BeginTx(ctx context.Context, opts *TxOptions) (*Tx, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
 	var txOpts *sql.TxOptions
 	if opts != nil {
 		txOpts = &sql.TxOptions{
@@ -48,34 +55,52 @@ func (c *Connection) BeginTx(ctx context.Context, opts *TxOptions) (*Tx, error)
 	}
 
 	return &Tx{
-		tx:  tx,
-		cfg: c.cfg,
+		tx:        tx,
+		cfg:       c.cfg,
+		readOnly:  opts != nil && opts.ReadOnly,
+		callbacks: &txCallbacks{},
 	}, nil
 }
 
-// RunTx выполняет функцию в рамках транзакции
+// RunTx выполняет функцию в рамках транзакции.
+// Транзакция сохраняется в контексте, передаваемом в fn (см. WithTx), поэтому методы Connection,
+// вызванные с этим контекстом, выполняются в ней. Если в ctx уже есть транзакция,
+// fn выполняется в ней без открытия новой, а фиксация остаётся за внешним RunTx; opts при этом игнорируются.
+// О начале, фиксации и откате транзакции сообщают обработчики Config.TxHooks.
 func (c *Connection) RunTx(ctx context.Context, opts *TxOptions, fn TxFunc) (err error) {
+	if outer, ok := TxFromContext(ctx); ok {
+		return fn(ctx, outer)
+	}
+
 	tx, err := c.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
+	start, event := time.Now(), txEvent(opts)
 
 	ctx, span := c.WithTracing(ctx, "RunTx", "")
 	defer span.End()
 
+	hookCtx := ctx
+	c.cfg.TxHooks.begin(hookCtx, event)
+
+	ctx = WithTx(ctx, tx)
+
 	// Автоматический Rollback при панике или ошибке
 	defer func() {
 		if p := recover(); p != nil {
 			rbErr := tx.Rollback()
 			span.RecordError(rbErr)
 			err = errors.Wrap(rbErr, "panic during transaction") // Сохраняем ошибку отката
-			panic(p)                                             // Перебрасываем панику дальше
+			c.cfg.TxHooks.rollback(hookCtx, event, start, errors.Errorf("panic during transaction: %v", p))
+			panic(p) // Перебрасываем панику дальше
 		} else if err != nil {
 			rbErr := tx.Rollback()
 			if rbErr != nil {
 				span.RecordError(rbErr)
 				err = errors.Wrap(err, rbErr.Error()) // Объединяем ошибки
 			}
+			c.cfg.TxHooks.rollback(hookCtx, event, start, err)
 		}
 	}()
 
@@ -88,26 +113,30 @@ func (c *Connection) RunTx(ctx context.Context, opts *TxOptions, fn TxFunc) (err
 		span.RecordError(err)
 		return errors.Wrap(err, "failed to commit transaction")
 	}
+	c.cfg.TxHooks.commit(hookCtx, event, start)
 
 	return nil
 }
 
-// Commit фиксирует транзакцию
+// Commit фиксирует транзакцию и выполняет функции, зарегистрированные AfterCommit
 func (tx *Tx) Commit() error {
 	_, span := tx.WithTracing(context.Background(), "Commit", "")
 	defer span.End()
 
 	if err := tx.tx.Commit(); err != nil {
 		span.RecordError(err)
+		tx.discardAfterCommit()
 		return errors.Wrap(err, "failed to commit transaction")
 	}
+	tx.runAfterCommit()
 	return nil
 }
 
-// Rollback откатывает транзакцию
+// Rollback откатывает транзакцию; функции, зарегистрированные AfterCommit, не выполняются
 func (tx *Tx) Rollback() error {
 	_, span := tx.WithTracing(context.Background(), "Rollback", "")
 	defer span.End()
+	tx.discardAfterCommit()
 
 	if err := tx.tx.Rollback(); err != nil && err != sql.ErrTxDone {
 		span.RecordError(err)
@@ -118,13 +147,20 @@ func (tx *Tx) Rollback() error {
 
 // Get выполняет запрос в транзакции и заполняет одну запись
 func (tx *Tx) Get(ctx context.Context, dst any, query string, args ...any) error {
+	if err := tx.checkReadOnly(query); err != nil {
+		return err
+	}
+
 	ctx, cancel := WithTimeout(ctx, tx.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := tx.WithTracing(ctx, "Get", query)
 	defer span.End()
 
-	err := tx.tx.GetContext(ctx, dst, query, args...)
+	start := time.Now()
+	err := getContext(ctx, tx.tx, tx.cfg.StrictMapping, dst, tx.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, tx.cfg, "Get", query, start, err)
+	observeQuery(ctx, tx.cfg, "Get", query, args, gotRows(err), time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		if err == sql.ErrNoRows {
@@ -137,13 +173,20 @@ func (tx *Tx) Get(ctx context.Context, dst any, query string, args ...any) error
 
 // Select выполняет запрос в транзакции и заполняет срез записей
 func (tx *Tx) Select(ctx context.Context, dst any, query string, args ...any) error {
+	if err := tx.checkReadOnly(query); err != nil {
+		return err
+	}
+
 	ctx, cancel := WithTimeout(ctx, tx.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := tx.WithTracing(ctx, "Select", query)
 	defer span.End()
 
-	err := tx.tx.SelectContext(ctx, dst, query, args...)
+	start := time.Now()
+	err := selectContext(ctx, tx.tx, tx.cfg.StrictMapping, dst, tx.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, tx.cfg, "Select", query, start, err)
+	observeQuery(ctx, tx.cfg, "Select", query, args, selectedRows(dst, err), time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		return errors.Wrap(err, "failed to execute select query in transaction")
@@ -153,13 +196,20 @@ func (tx *Tx) Select(ctx context.Context, dst any, query string, args ...any) er
 
 // Exec выполняет запрос в транзакции и возвращает результат
 func (tx *Tx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if err := tx.checkReadOnly(query); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := WithTimeout(ctx, tx.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := tx.WithTracing(ctx, "Exec", query)
 	defer span.End()
 
-	result, err := tx.tx.ExecContext(ctx, query, args...)
+	start := time.Now()
+	result, err := tx.tx.ExecContext(ctx, tx.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, tx.cfg, "Exec", query, start, err)
+	observeQuery(ctx, tx.cfg, "Exec", query, args, affectedRows(result, err), time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		return nil, errors.Wrap(err, "failed to execute query in transaction")
@@ -169,13 +219,20 @@ func (tx *Tx) Exec(ctx context.Context, query string, args ...any) (sql.Result,
 
 // Query выполняет запрос в транзакции и возвращает строки результата
 func (tx *Tx) Query(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	if err := tx.checkReadOnly(query); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := WithTimeout(ctx, tx.cfg.QueryTimeout)
 	defer cancel()
 
 	ctx, span := tx.WithTracing(ctx, "Query", query)
 	defer span.End()
 
-	rows, err := tx.tx.QueryxContext(ctx, query, args...)
+	start := time.Now()
+	rows, err := tx.tx.QueryxContext(ctx, tx.cfg.SQLCommenter.Apply(ctx, query), args...)
+	err = diagnoseCancellation(ctx, tx.cfg, "Query", query, start, err)
+	observeQuery(ctx, tx.cfg, "Query", query, args, rowsUnknown, time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		return nil, errors.Wrap(err, "failed to execute query in transaction")
@@ -196,5 +253,5 @@ func (tx *Tx) QueryRow(ctx context.Context, query string, args ...any) *sqlx.Row
 	// Note: We don't apply QueryTimeout here because sqlx.Row is lazy-evaluated.
 	// The query is executed when Scan() is called, so canceling the context here
 	// would cause "context canceled" errors. The caller should manage context lifetime.
-	return tx.tx.QueryRowxContext(ctx, query, args...)
+	return tx.tx.QueryRowxContext(ctx, tx.cfg.SQLCommenter.Apply(ctx, query), args...)
 }
@@ -0,0 +1,20 @@
+package sqlx
+
+import "context"
+
+type txContextKeyT struct{}
+
+var txContextKey = txContextKeyT{}
+
+// WithTx возвращает контекст с сохранённой транзакцией.
+// Методы Connection (Get, Select, Exec, Query, QueryRow), вызванные с этим контекстом,
+// выполняются в рамках транзакции.
+func WithTx(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txContextKey, tx)
+}
+
+// TxFromContext извлекает транзакцию из контекста
+func TxFromContext(ctx context.Context) (*Tx, bool) {
+	tx, ok := ctx.Value(txContextKey).(*Tx)
+	return tx, ok && tx != nil
+}
@@ -0,0 +1,52 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxFromContext_Empty(t *testing.T) {
+	t.Parallel()
+
+	tx, ok := TxFromContext(context.Background())
+
+	assert.False(t, ok)
+	assert.Nil(t, tx)
+}
+
+func TestWithTx_RoundTrip(t *testing.T) {
+	t.Parallel()
+	tx := &Tx{}
+
+	got, ok := TxFromContext(WithTx(context.Background(), tx))
+
+	assert.True(t, ok)
+	assert.Same(t, tx, got)
+}
+
+func TestWithTx_NilTx(t *testing.T) {
+	t.Parallel()
+
+	_, ok := TxFromContext(WithTx(context.Background(), nil))
+
+	assert.False(t, ok, "nil transaction must not be reported as present")
+}
+
+func TestRunTx_JoinsTxFromContext(t *testing.T) {
+	t.Parallel()
+	outer := &Tx{}
+	ctx := WithTx(context.Background(), outer)
+
+	// Connection without DB: joining the outer transaction must not begin a new one
+	c := &Connection{}
+	var got *Tx
+	err := c.RunTx(ctx, nil, func(ctx context.Context, tx *Tx) error {
+		got = tx
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Same(t, outer, got)
+}
@@ -0,0 +1,109 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// TxEvent описывает транзакцию RunTx в обработчиках TxHooks
+type TxEvent struct {
+	// Isolation и ReadOnly — уровень изоляции и режим транзакции из TxOptions
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// Duration — время от начала транзакции до фиксации или отката; 0 в OnBegin
+	Duration time.Duration
+	// Err — причина отката: ошибка fn, паника или ошибка фиксации; nil в OnBegin и OnCommit
+	Err error
+}
+
+// TxHooks — обработчики событий транзакций, открываемых RunTx и RunReadTx, например для метрик
+// длительности и доли откатов. Обработчики вызываются синхронно; nil — событие не обрабатывается.
+// Транзакции, присоединённые к внешней (см. RunTx), событий не порождают.
+//
+// Контекст обработчиков — контекст RunTx без транзакции: запросы в нём выполняются вне транзакции.
+// Для действий только после фиксации конкретной транзакции (доменные события, пробуждение outbox relay)
+// используйте Tx.AfterCommit.
+type TxHooks struct {
+	// OnBegin вызывается после BEGIN
+	OnBegin func(ctx context.Context, event TxEvent)
+	// OnCommit вызывается после успешной фиксации
+	OnCommit func(ctx context.Context, event TxEvent)
+	// OnRollback вызывается после отката из-за ошибки fn, паники или неудачной фиксации
+	OnRollback func(ctx context.Context, event TxEvent)
+}
+
+// txEvent возвращает событие транзакции с опциями opts
+func txEvent(opts *TxOptions) TxEvent {
+	if opts == nil {
+		return TxEvent{}
+	}
+	return TxEvent{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}
+}
+
+func (h TxHooks) begin(ctx context.Context, event TxEvent) {
+	if h.OnBegin != nil {
+		h.OnBegin(ctx, event)
+	}
+}
+
+func (h TxHooks) commit(ctx context.Context, event TxEvent, start time.Time) {
+	if h.OnCommit != nil {
+		event.Duration = time.Since(start)
+		h.OnCommit(ctx, event)
+	}
+}
+
+func (h TxHooks) rollback(ctx context.Context, event TxEvent, start time.Time, err error) {
+	if h.OnRollback != nil {
+		event.Duration = time.Since(start)
+		event.Err = err
+		h.OnRollback(ctx, event)
+	}
+}
+
+// txCallbacks — функции, выполняемые после фиксации транзакции; общие для Tx,
+// присоединённых к одной транзакции базы данных
+type txCallbacks struct {
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+// AfterCommit регистрирует fn, выполняемую после успешной фиксации транзакции, например публикацию
+// доменного события или пробуждение outbox relay. При откате fn не выполняется.
+// Во вложенном RunTx fn выполняется после фиксации внешней транзакции.
+// Функции выполняются в порядке регистрации; паника в fn не перехватывается.
+func (tx *Tx) AfterCommit(fn func()) {
+	if tx.callbacks == nil {
+		return
+	}
+	tx.callbacks.mu.Lock()
+	defer tx.callbacks.mu.Unlock()
+	tx.callbacks.afterCommit = append(tx.callbacks.afterCommit, fn)
+}
+
+// runAfterCommit выполняет функции AfterCommit после фиксации
+func (tx *Tx) runAfterCommit() {
+	if tx.callbacks == nil {
+		return
+	}
+	tx.callbacks.mu.Lock()
+	fns := tx.callbacks.afterCommit
+	tx.callbacks.afterCommit = nil
+	tx.callbacks.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// discardAfterCommit отбрасывает функции AfterCommit после отката
+func (tx *Tx) discardAfterCommit() {
+	if tx.callbacks == nil {
+		return
+	}
+	tx.callbacks.mu.Lock()
+	tx.callbacks.afterCommit = nil
+	tx.callbacks.mu.Unlock()
+}
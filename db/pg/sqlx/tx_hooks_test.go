@@ -0,0 +1,161 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txConn is a driver.Conn recording transaction outcomes
+type txConn struct {
+	driver.Conn
+	commitErr  error
+	committed  int
+	rolledBack int
+}
+
+func (c *txConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) { return c, nil }
+func (c *txConn) Close() error                                                 { return nil }
+
+func (c *txConn) Commit() error {
+	c.committed++
+	return c.commitErr
+}
+
+func (c *txConn) Rollback() error {
+	c.rolledBack++
+	return nil
+}
+
+// newTxConnection returns a Connection whose pool serves conn
+func newTxConnection(conn *txConn, hooks TxHooks) *Connection {
+	c := &Connection{
+		DB:  sqlx.NewDb(sql.OpenDB(&connConnector{conn: conn}), "postgres"),
+		cfg: Config{TxHooks: hooks},
+	}
+	c.connected.Store(true)
+	return c
+}
+
+// txHookRecorder records TxHooks events
+type txHookRecorder struct {
+	events []string
+	last   TxEvent
+}
+
+func (r *txHookRecorder) hooks() TxHooks {
+	record := func(name string) func(context.Context, TxEvent) {
+		return func(ctx context.Context, event TxEvent) {
+			_, inTx := TxFromContext(ctx)
+			if inTx {
+				name += " in tx"
+			}
+			r.events = append(r.events, name)
+			r.last = event
+		}
+	}
+	return TxHooks{OnBegin: record("begin"), OnCommit: record("commit"), OnRollback: record("rollback")}
+}
+
+// TestRunTx_Hooks tests the transaction hooks and AfterCommit functions of RunTx.
+func TestRunTx_Hooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("commit", func(t *testing.T) {
+		t.Parallel()
+		rec := &txHookRecorder{}
+		conn := &txConn{}
+		c := newTxConnection(conn, rec.hooks())
+
+		err := c.RunTx(context.Background(), &TxOptions{ReadOnly: true, Isolation: sql.LevelSerializable}, func(ctx context.Context, tx *Tx) error {
+			tx.AfterCommit(func() {
+				assert.Equal(t, 1, conn.committed, "after-commit function must run after the commit")
+				rec.events = append(rec.events, "after commit")
+			})
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin", "after commit", "commit"}, rec.events)
+		assert.True(t, rec.last.ReadOnly)
+		assert.Equal(t, sql.LevelSerializable, rec.last.Isolation)
+		assert.Positive(t, rec.last.Duration)
+		assert.NoError(t, rec.last.Err)
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		t.Parallel()
+		rec := &txHookRecorder{}
+		conn := &txConn{}
+		c := newTxConnection(conn, rec.hooks())
+		fnErr := errors.New("insufficient funds")
+
+		err := c.RunTx(context.Background(), nil, func(ctx context.Context, tx *Tx) error {
+			tx.AfterCommit(func() { t.Error("after-commit function must not run on rollback") })
+			return fnErr
+		})
+		require.ErrorIs(t, err, fnErr)
+		assert.Equal(t, []string{"begin", "rollback"}, rec.events)
+		assert.ErrorIs(t, rec.last.Err, fnErr)
+		assert.Equal(t, 1, conn.rolledBack)
+	})
+
+	t.Run("failed commit", func(t *testing.T) {
+		t.Parallel()
+		rec := &txHookRecorder{}
+		conn := &txConn{commitErr: errors.New("serialization failure")}
+		c := newTxConnection(conn, rec.hooks())
+
+		err := c.RunTx(context.Background(), nil, func(ctx context.Context, tx *Tx) error {
+			tx.AfterCommit(func() { t.Error("after-commit function must not run when the commit fails") })
+			return nil
+		})
+		require.ErrorContains(t, err, "failed to commit transaction")
+		assert.Equal(t, []string{"begin", "rollback"}, rec.events)
+		assert.ErrorContains(t, rec.last.Err, "serialization failure")
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+		rec := &txHookRecorder{}
+		c := newTxConnection(&txConn{}, rec.hooks())
+
+		assert.Panics(t, func() {
+			_ = c.RunTx(context.Background(), nil, func(context.Context, *Tx) error {
+				panic("boom")
+			})
+		})
+		assert.Equal(t, []string{"begin", "rollback"}, rec.events)
+		assert.EqualError(t, rec.last.Err, "panic during transaction: boom")
+	})
+
+	t.Run("nested transactions", func(t *testing.T) {
+		t.Parallel()
+		rec := &txHookRecorder{}
+		conn := &txConn{}
+		c := newTxConnection(conn, rec.hooks())
+
+		var order []string
+		err := c.RunTx(context.Background(), nil, func(ctx context.Context, _ *Tx) error {
+			err := c.RunReadTx(ctx, func(_ context.Context, tx *Tx) error {
+				tx.AfterCommit(func() { order = append(order, "inner") })
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Empty(t, order, "inner after-commit function must wait for the outer commit")
+
+			tx, _ := TxFromContext(ctx)
+			tx.AfterCommit(func() { order = append(order, "outer") })
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"inner", "outer"}, order)
+		assert.Equal(t, []string{"begin", "commit"}, rec.events)
+		assert.Equal(t, 1, conn.committed)
+	})
+}
@@ -0,0 +1,88 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Getter выполняет запрос и заполняет одну запись; реализуется Connection и Tx
+type Getter interface {
+	Get(ctx context.Context, dst any, query string, args ...any) error
+}
+
+var (
+	_ Getter = (*Connection)(nil)
+	_ Getter = (*Tx)(nil)
+)
+
+// NotFoundError возвращается GetOne, если запрос не вернул строк.
+// Оборачивает sql.ErrNoRows, поэтому errors.Is(err, sql.ErrNoRows) продолжает работать.
+type NotFoundError struct {
+	Type string // тип записи, например "models.User"
+}
+
+// Error сообщает, запись какого типа не найдена
+func (e *NotFoundError) Error() string {
+	return e.Type + " not found"
+}
+
+// Unwrap возвращает sql.ErrNoRows
+func (e *NotFoundError) Unwrap() error {
+	return sql.ErrNoRows
+}
+
+// IsNotFound проверяет, что запись не найдена: *NotFoundError или sql.ErrNoRows
+func IsNotFound(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// GetOne выполняет запрос через Get и возвращает одну запись типа T.
+// T — структура, скаляр или указатель на них; для указателя запись создаётся заново
+// при каждом вызове. Если строк нет, возвращает нулевое значение T (nil для указателя)
+// и *NotFoundError.
+//
+//	user, err := sqlx.GetOne[User](ctx, db, "SELECT * FROM users WHERE id = $1", id)
+//	if sqlx.IsNotFound(err) { ... }
+func GetOne[T any](ctx context.Context, q Getter, query string, args ...any) (T, error) {
+	var result T
+	t := reflect.TypeFor[T]()
+
+	dst := any(&result)
+	if t.Kind() == reflect.Pointer {
+		ptr := reflect.New(t.Elem())
+		dst = ptr.Interface()
+		result = ptr.Interface().(T)
+	}
+
+	if err := q.Get(ctx, dst, query, args...); err != nil {
+		var zero T
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero, &NotFoundError{Type: recordType(t).String()}
+		}
+		return zero, err
+	}
+	return result, nil
+}
+
+// SelectAll выполняет запрос через Select и возвращает записи типа T.
+// T — структура, скаляр или указатель на них. Если строк нет, возвращает пустой срез, а не nil.
+//
+//	users, err := sqlx.SelectAll[User](ctx, db, "SELECT * FROM users WHERE active")
+func SelectAll[T any](ctx context.Context, q Selecter, query string, args ...any) ([]T, error) {
+	result := []T{}
+	if err := q.Select(ctx, &result, query, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// recordType возвращает тип записи без указателей
+func recordType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
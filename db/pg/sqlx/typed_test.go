@@ -0,0 +1,86 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGetter fills the destination with row and records its type.
+type fakeGetter struct {
+	row any
+	err error
+	dst reflect.Type
+}
+
+func (f *fakeGetter) Get(_ context.Context, dst any, _ string, _ ...any) error {
+	f.dst = reflect.TypeOf(dst)
+	if f.err != nil {
+		return f.err
+	}
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(f.row))
+	return nil
+}
+
+// TestGetOne tests value and pointer results and the destination passed to Get.
+func TestGetOne(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	row := pageRow{ID: 1, Name: "a"}
+
+	q := &fakeGetter{row: row}
+	value, err := GetOne[pageRow](ctx, q, "SELECT 1")
+	require.NoError(t, err)
+	assert.Equal(t, row, value)
+	assert.Equal(t, reflect.TypeOf(&pageRow{}), q.dst)
+
+	q = &fakeGetter{row: row}
+	ptr, err := GetOne[*pageRow](ctx, q, "SELECT 1")
+	require.NoError(t, err)
+	assert.Equal(t, &row, ptr)
+	assert.Equal(t, reflect.TypeOf(&pageRow{}), q.dst)
+
+	count, err := GetOne[int64](ctx, &fakeGetter{row: int64(3)}, "SELECT count(*) FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+// TestGetOne_NotFound tests mapping of sql.ErrNoRows to NotFoundError.
+func TestGetOne_NotFound(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ptr, err := GetOne[*pageRow](ctx, &fakeGetter{err: sql.ErrNoRows}, "SELECT 1")
+	assert.Nil(t, ptr)
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "sqlx.pageRow", notFound.Type)
+	assert.EqualError(t, err, "sqlx.pageRow not found")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.True(t, IsNotFound(err))
+
+	value, err := GetOne[pageRow](ctx, &fakeGetter{err: errors.New("connection refused")}, "SELECT 1")
+	assert.Zero(t, value)
+	assert.EqualError(t, err, "connection refused")
+	assert.False(t, IsNotFound(err))
+}
+
+// TestSelectAll tests that records are returned and an empty result is not nil.
+func TestSelectAll(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	rows, err := SelectAll[pageRow](ctx, &fakeSelecter{rows: []pageRow{{ID: 1}, {ID: 2}}}, "SELECT id FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, []pageRow{{ID: 1}, {ID: 2}}, rows)
+
+	rows, err = SelectAll[pageRow](ctx, &fakeSelecter{}, "SELECT id FROM users")
+	require.NoError(t, err)
+	assert.NotNil(t, rows)
+	assert.Empty(t, rows)
+}
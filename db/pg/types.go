@@ -0,0 +1,284 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// JSONB — обёртка для колонок json/jsonb: значение сериализуется в JSON при записи
+// и десериализуется в V при чтении. SQL NULL читается как нулевое значение T;
+// для различения NULL используйте sql.Null[pg.JSONB[T]].
+//
+//	var settings pg.JSONB[map[string]any]
+//	err := db.QueryRowContext(ctx, "SELECT settings FROM users WHERE id = $1", id).Scan(&settings)
+type JSONB[T any] struct {
+	V T
+}
+
+// NewJSONB создаёт JSONB со значением v
+func NewJSONB[T any](v T) JSONB[T] {
+	return JSONB[T]{V: v}
+}
+
+// Value реализует driver.Valuer
+func (j JSONB[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal jsonb")
+	}
+	return string(data), nil
+}
+
+// Scan реализует sql.Scanner
+func (j *JSONB[T]) Scan(src any) error {
+	var zero T
+	j.V = zero
+
+	data, ok, err := scanBytes(src, "JSONB")
+	if err != nil || !ok {
+		return err
+	}
+	if err := json.Unmarshal(data, &j.V); err != nil {
+		return errors.Wrap(err, "failed to unmarshal jsonb")
+	}
+	return nil
+}
+
+// TextArray — колонка text[] (одномерный массив). NULL элементы не поддерживаются.
+type TextArray []string
+
+// Value реализует driver.Valuer
+func (a TextArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return formatArray(a), nil
+}
+
+// Scan реализует sql.Scanner
+func (a *TextArray) Scan(src any) error {
+	data, ok, err := scanBytes(src, "TextArray")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*a = nil
+		return nil
+	}
+
+	elems, err := parseArray(string(data))
+	if err != nil {
+		return err
+	}
+	*a = elems
+	return nil
+}
+
+// UUIDArray — колонка uuid[] (одномерный массив). NULL элементы не поддерживаются.
+type UUIDArray []uuid.UUID
+
+// Value реализует driver.Valuer
+func (a UUIDArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elems := make([]string, len(a))
+	for i, id := range a {
+		elems[i] = id.String()
+	}
+	return formatArray(elems), nil
+}
+
+// Scan реализует sql.Scanner
+func (a *UUIDArray) Scan(src any) error {
+	data, ok, err := scanBytes(src, "UUIDArray")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*a = nil
+		return nil
+	}
+
+	elems, err := parseArray(string(data))
+	if err != nil {
+		return err
+	}
+	ids := make(UUIDArray, len(elems))
+	for i, elem := range elems {
+		ids[i], err = uuid.Parse(elem)
+		if err != nil {
+			return errors.Wrapf(err, "invalid uuid array element %q", elem)
+		}
+	}
+	*a = ids
+	return nil
+}
+
+// Vector — колонка vector расширения pgvector в текстовом формате "[1,2,3]"
+type Vector []float32
+
+// Value реализует driver.Valuer
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String(), nil
+}
+
+// Scan реализует sql.Scanner
+func (v *Vector) Scan(src any) error {
+	data, ok, err := scanBytes(src, "Vector")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*v = nil
+		return nil
+	}
+
+	s := strings.TrimSpace(string(data))
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return errors.Errorf("invalid vector %q", s)
+	}
+	s = s[1 : len(s)-1]
+	if strings.TrimSpace(s) == "" {
+		*v = Vector{}
+		return nil
+	}
+
+	parts := strings.Split(s, ",")
+	vec := make(Vector, len(parts))
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return errors.Wrapf(err, "invalid vector element %q", part)
+		}
+		vec[i] = float32(f)
+	}
+	*v = vec
+	return nil
+}
+
+// scanBytes приводит исходное значение драйвера к []byte; ok == false для NULL
+func scanBytes(src any, typeName string) ([]byte, bool, error) {
+	switch s := src.(type) {
+	case nil:
+		return nil, false, nil
+	case []byte:
+		return s, true, nil
+	case string:
+		return []byte(s), true, nil
+	default:
+		return nil, false, errors.Errorf("cannot scan %T into %s", src, typeName)
+	}
+}
+
+// formatArray кодирует элементы в литерал одномерного массива PostgreSQL: {"a","b"}
+func formatArray(elems []string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, elem := range elems {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('"')
+		for _, r := range elem {
+			if r == '"' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// parseArray разбирает литерал одномерного массива PostgreSQL
+func parseArray(src string) ([]string, error) {
+	s := strings.TrimSpace(src)
+	// литерал может начинаться с границ размерностей: [1:2]={a,b}
+	if strings.HasPrefix(s, "[") {
+		if i := strings.Index(s, "="); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, errors.Errorf("invalid array literal %q", src)
+	}
+	body := s[1 : len(s)-1]
+
+	elems := []string{}
+	if strings.TrimSpace(body) == "" {
+		return elems, nil
+	}
+
+	for i := 0; i <= len(body); {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		if i < len(body) && body[i] == '{' {
+			return nil, errors.New("multidimensional arrays are not supported")
+		}
+
+		var elem strings.Builder
+		quoted := i < len(body) && body[i] == '"'
+		if quoted {
+			i++
+			closed := false
+			for i < len(body) {
+				c := body[i]
+				i++
+				if c == '\\' && i < len(body) {
+					elem.WriteByte(body[i])
+					i++
+					continue
+				}
+				if c == '"' {
+					closed = true
+					break
+				}
+				elem.WriteByte(c)
+			}
+			if !closed {
+				return nil, errors.Errorf("unterminated quoted element in array literal %q", src)
+			}
+			for i < len(body) && body[i] == ' ' {
+				i++
+			}
+		} else {
+			start := i
+			for i < len(body) && body[i] != ',' {
+				i++
+			}
+			raw := strings.TrimSpace(body[start:i])
+			if strings.EqualFold(raw, "NULL") {
+				return nil, errors.New("NULL array elements are not supported")
+			}
+			elem.WriteString(raw)
+		}
+		elems = append(elems, elem.String())
+
+		if i < len(body) && body[i] != ',' {
+			return nil, errors.Errorf("invalid array literal %q", src)
+		}
+		i++
+	}
+
+	return elems, nil
+}
@@ -0,0 +1,170 @@
+package pg
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profile struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func TestJSONB_RoundTrip(t *testing.T) {
+	t.Parallel()
+	value, err := NewJSONB(profile{Name: "alice", Tags: []string{"a"}}).Value()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"alice","tags":["a"]}`, value.(string))
+
+	var scanned JSONB[profile]
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, profile{Name: "alice", Tags: []string{"a"}}, scanned.V)
+}
+
+func TestJSONB_Scan(t *testing.T) {
+	t.Parallel()
+	var m JSONB[map[string]any]
+	require.NoError(t, m.Scan(`{"a":1}`))
+	assert.Equal(t, map[string]any{"a": float64(1)}, m.V)
+
+	require.NoError(t, m.Scan(nil))
+	assert.Nil(t, m.V, "NULL resets the value")
+
+	assert.Error(t, m.Scan(42))
+	assert.Error(t, m.Scan([]byte("{invalid")))
+}
+
+func TestJSONB_SQLNull(t *testing.T) {
+	t.Parallel()
+	var n sql.Null[JSONB[profile]]
+	require.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+
+	require.NoError(t, n.Scan([]byte(`{"name":"bob"}`)))
+	assert.True(t, n.Valid)
+	assert.Equal(t, "bob", n.V.V.Name)
+}
+
+func TestTextArray_Value(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		array    TextArray
+		expected any
+	}{
+		{name: "nil", array: nil, expected: nil},
+		{name: "empty", array: TextArray{}, expected: "{}"},
+		{name: "plain", array: TextArray{"a", "b"}, expected: `{"a","b"}`},
+		{name: "special characters", array: TextArray{`say "hi"`, `back\slash`, "a,b", ""}, expected: `{"say \"hi\"","back\\slash","a,b",""}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			value, err := tc.array.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestTextArray_Scan(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		src      any
+		expected TextArray
+	}{
+		{name: "nil", src: nil, expected: nil},
+		{name: "empty", src: "{}", expected: TextArray{}},
+		{name: "unquoted", src: []byte("{a,b,c}"), expected: TextArray{"a", "b", "c"}},
+		{name: "quoted", src: `{"hello world","a,b","say \"hi\"","back\\slash",""}`, expected: TextArray{"hello world", "a,b", `say "hi"`, `back\slash`, ""}},
+		{name: "dimensions prefix", src: "[0:1]={x,y}", expected: TextArray{"x", "y"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var a TextArray
+			require.NoError(t, a.Scan(tc.src))
+			assert.Equal(t, tc.expected, a)
+		})
+	}
+}
+
+func TestTextArray_ScanErrors(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		src  any
+	}{
+		{name: "unsupported type", src: 42},
+		{name: "not an array", src: "abc"},
+		{name: "null element", src: "{a,NULL}"},
+		{name: "multidimensional", src: "{{a,b},{c,d}}"},
+		{name: "unterminated quote", src: `{"abc}`},
+		{name: "garbage after quote", src: `{"a"b}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var a TextArray
+			assert.Error(t, a.Scan(tc.src))
+		})
+	}
+}
+
+func TestTextArray_RoundTrip(t *testing.T) {
+	t.Parallel()
+	original := TextArray{"plain", `quo"te`, `back\slash`, "comma,inside", " spaces ", "NULL"}
+	value, err := original.Value()
+	require.NoError(t, err)
+
+	var scanned TextArray
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, original, scanned)
+}
+
+func TestUUIDArray(t *testing.T) {
+	t.Parallel()
+	ids := UUIDArray{uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")}
+
+	value, err := ids.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"6ba7b810-9dad-11d1-80b4-00c04fd430c8","6ba7b811-9dad-11d1-80b4-00c04fd430c8"}`, value)
+
+	var scanned UUIDArray
+	require.NoError(t, scanned.Scan([]byte("{6ba7b810-9dad-11d1-80b4-00c04fd430c8,6ba7b811-9dad-11d1-80b4-00c04fd430c8}")))
+	assert.Equal(t, ids, scanned)
+
+	assert.Error(t, scanned.Scan("{not-a-uuid}"))
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
+
+func TestVector(t *testing.T) {
+	t.Parallel()
+	value, err := Vector{1, -0.5, 3.25}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "[1,-0.5,3.25]", value)
+
+	var v Vector
+	require.NoError(t, v.Scan([]byte("[1, -0.5, 3.25]")))
+	assert.Equal(t, Vector{1, -0.5, 3.25}, v)
+
+	require.NoError(t, v.Scan("[]"))
+	assert.Equal(t, Vector{}, v)
+
+	require.NoError(t, v.Scan(nil))
+	assert.Nil(t, v)
+
+	assert.Error(t, v.Scan("1,2,3"))
+	assert.Error(t, v.Scan("[1,x]"))
+	assert.Error(t, v.Scan(1.5))
+}
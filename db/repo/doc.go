@@ -0,0 +1,33 @@
+// Package repo содержит обобщённые CRUD хелперы для PostgreSQL поверх [Querier]
+// (sqlx.Connection или sqlx.Tx из db/pg/sqlx).
+//
+// Колонки определяются тегами db полей структуры, включая поля встроенных структур;
+// поля без тега db и с тегом db:"-" пропускаются. Тег repo задаёт роль колонки:
+//   - repo:"pk" — первичный ключ; без тега первичным ключом считается колонка id
+//   - repo:"readonly" — значение заполняет база (bigserial, DEFAULT now()): колонка
+//     не участвует в INSERT и UPDATE, но возвращается через RETURNING
+//
+// Использование:
+//
+//	type User struct {
+//	    ID        int64     `db:"id" repo:"readonly"`
+//	    Email     string    `db:"email"`
+//	    CreatedAt time.Time `db:"created_at" repo:"readonly"`
+//	}
+//
+//	u, err := repo.InsertReturning(ctx, db, "users", User{Email: "a@example.com"})
+//	u, err = repo.FindByID[User](ctx, db, "users", u.ID)
+//	u, err = repo.UpdateByID(ctx, db, "users", u)
+//	err = repo.DeleteByID[User](ctx, db, "users", u.ID)
+//
+//	page, err := repo.FindPage[User](ctx, db, "users",
+//	    repo.PageRequest{Limit: 20, OrderBy: "created_at", Desc: true}, "email LIKE $1", "%@example.com")
+//
+// FindByID, UpdateByID и DeleteByID возвращают ошибку, оборачивающую [ErrNotFound], если записи нет.
+// Имена таблиц ("table" или "schema.table") и колонок проверяются pg.ValidateIdentifier и экранируются,
+// недопустимое имя — ошибка, оборачивающая pg.ErrInvalidIdentifier; OrderBy проверяется по колонкам структуры.
+// Условие where в FindPage подставляется в запрос как есть и не должно содержать пользовательский ввод —
+// значения передаются через args.
+//
+// Хелперы, вызванные через sqlx.Connection с контекстом из RunTx или WithTx, выполняются в транзакции.
+package repo
@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+// defaultPK — колонка первичного ключа, если ни одно поле не помечено repo:"pk"
+const defaultPK = "id"
+
+// column — колонка таблицы, связанная с полем структуры
+type column struct {
+	name     string
+	index    []int // путь к полю для reflect.Value.FieldByIndex
+	pk       bool
+	readonly bool // заполняется базой (serial, DEFAULT now()); не пишется в INSERT и UPDATE
+}
+
+// meta — описание колонок структуры
+type meta struct {
+	columns []column
+	pk      int // индекс колонки первичного ключа в columns
+}
+
+// metaCache кэширует meta по типу структуры
+var metaCache sync.Map // reflect.Type -> *meta
+
+// metaOf возвращает описание колонок типа T
+func metaOf[T any]() (*meta, error) {
+	t := reflect.TypeFor[T]()
+	if cached, ok := metaCache.Load(t); ok {
+		return cached.(*meta), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("repo: %s is not a struct", t)
+	}
+
+	m := &meta{pk: -1}
+	collectColumns(t, nil, m)
+	if len(m.columns) == 0 {
+		return nil, errors.Errorf("repo: %s has no fields with db tags", t)
+	}
+	for _, c := range m.columns {
+		if err := pg.ValidateIdentifier(c.name); err != nil {
+			return nil, errors.Wrapf(err, "repo: %s has an invalid column name", t)
+		}
+	}
+	if m.pk < 0 {
+		for i, c := range m.columns {
+			if c.name == defaultPK {
+				m.pk = i
+				m.columns[i].pk = true
+				break
+			}
+		}
+	}
+	if m.pk < 0 {
+		return nil, errors.Errorf(`repo: %s has no primary key: add a %q column or tag a field with repo:"pk"`, t, defaultPK)
+	}
+
+	cached, _ := metaCache.LoadOrStore(t, m)
+	return cached.(*meta), nil
+}
+
+// collectColumns добавляет в m поля с тегом db, включая поля встроенных структур
+func collectColumns(t reflect.Type, parent []int, m *meta) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				collectColumns(field.Type, index, m)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		c := column{name: name, index: index}
+		for _, opt := range strings.Split(field.Tag.Get("repo"), ",") {
+			switch strings.TrimSpace(opt) {
+			case "pk":
+				c.pk = true
+			case "readonly":
+				c.readonly = true
+			}
+		}
+		if c.pk && m.pk < 0 {
+			m.pk = len(m.columns)
+		} else {
+			c.pk = false
+		}
+		m.columns = append(m.columns, c)
+	}
+}
+
+// pkColumn возвращает колонку первичного ключа
+func (m *meta) pkColumn() column {
+	return m.columns[m.pk]
+}
+
+// column возвращает колонку по имени
+func (m *meta) column(name string) (column, bool) {
+	for _, c := range m.columns {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// selectList возвращает список всех колонок для SELECT и RETURNING
+func (m *meta) selectList() string {
+	names := make([]string, len(m.columns))
+	for i, c := range m.columns {
+		names[i] = quotedName(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// writable возвращает колонки для INSERT (withPK) или SET в UPDATE (без первичного ключа)
+func (m *meta) writable(withPK bool) []column {
+	result := make([]column, 0, len(m.columns))
+	for _, c := range m.columns {
+		if c.readonly || (c.pk && !withPK) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// value возвращает значение поля колонки c в v
+func (c column) value(v reflect.Value) any {
+	return v.FieldByIndex(c.index).Interface()
+}
+
+// quoteTable проверяет имя таблицы, в том числе со схемой, и экранирует его: "public"."users"
+func quoteTable(table string) (string, error) {
+	name, err := pg.ParseTableName(table)
+	if err != nil {
+		return "", errors.Wrap(err, "repo")
+	}
+	return name.String(), nil
+}
+
+// quotedName возвращает экранированное имя колонки
+func quotedName(c column) string {
+	return pg.QuoteIdentifier(c.name)
+}
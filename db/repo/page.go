@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Ограничения размера страницы
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 1000
+)
+
+// PageRequest — параметры постраничной выборки
+type PageRequest struct {
+	Limit   int    // Размер страницы; 0 — DefaultPageLimit, больше MaxPageLimit — MaxPageLimit
+	Offset  int    // Число пропускаемых записей
+	OrderBy string // Колонка сортировки (имя из тега db); пусто — первичный ключ
+	Desc    bool   // Сортировка по убыванию
+}
+
+// Page — страница записей и общее число записей, подходящих под условие
+type Page[T any] struct {
+	Items  []T
+	Total  int64
+	Limit  int
+	Offset int
+}
+
+// HasNext сообщает, есть ли записи после этой страницы
+func (p Page[T]) HasNext() bool {
+	return int64(p.Offset+len(p.Items)) < p.Total
+}
+
+// NextOffset возвращает смещение следующей страницы
+func (p Page[T]) NextOffset() int {
+	return p.Offset + len(p.Items)
+}
+
+// FindPage возвращает страницу записей таблицы table.
+// where — необязательное условие с плейсхолдерами $1, $2, ... для args, например "status = $1".
+// Для стабильного порядка при неуникальной OrderBy записи дополнительно сортируются по первичному ключу.
+func FindPage[T any](ctx context.Context, q Querier, table string, req PageRequest, where string, args ...any) (Page[T], error) {
+	page := Page[T]{Items: []T{}, Limit: normalizeLimit(req.Limit), Offset: max(req.Offset, 0)}
+	m, err := metaOf[T]()
+	if err != nil {
+		return page, err
+	}
+	quoted, err := quoteTable(table)
+	if err != nil {
+		return page, err
+	}
+
+	pk := m.pkColumn()
+	order := pk
+	if req.OrderBy != "" {
+		c, ok := m.column(req.OrderBy)
+		if !ok {
+			return page, errors.Errorf("repo: unknown order column %q", req.OrderBy)
+		}
+		order = c
+	}
+	direction := "ASC"
+	if req.Desc {
+		direction = "DESC"
+	}
+	orderBy := quotedName(order) + " " + direction
+	if !order.pk {
+		orderBy += ", " + quotedName(pk) + " " + direction
+	}
+
+	whereClause := ""
+	if where != "" {
+		whereClause = " WHERE " + where
+	}
+
+	countQuery := fmt.Sprintf("SELECT count(*) FROM %s%s", quoted, whereClause)
+	if err := q.Get(ctx, &page.Total, countQuery, args...); err != nil {
+		return page, errors.Wrapf(err, "failed to count %s", table)
+	}
+	if page.Total == 0 || int64(page.Offset) >= page.Total {
+		return page, nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT %d OFFSET %d",
+		m.selectList(), quoted, whereClause, orderBy, page.Limit, page.Offset)
+	if err := q.Select(ctx, &page.Items, query, args...); err != nil {
+		return page, errors.Wrapf(err, "failed to select %s", table)
+	}
+	return page, nil
+}
+
+// normalizeLimit приводит размер страницы к диапазону (0, MaxPageLimit]
+func normalizeLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageLimit
+	case limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return limit
+	}
+}
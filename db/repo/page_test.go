@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindPage tests the count and page queries.
+func TestFindPage(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeQuerier{
+		get:      []any{int64(3)},
+		selected: []user{{ID: 2}, {ID: 3}},
+	}
+	page, err := FindPage[user](context.Background(), q, "users",
+		PageRequest{Limit: 2, Offset: 1, OrderBy: "name", Desc: true}, "email LIKE $1", "%@example.com")
+	require.NoError(t, err)
+
+	require.Len(t, q.calls, 2)
+	assert.Equal(t, `SELECT count(*) FROM "users" WHERE email LIKE $1`, q.calls[0].query)
+	assert.Equal(t, `SELECT "id", "email", "name", "created_at" FROM "users" WHERE email LIKE $1 ORDER BY "name" DESC, "id" DESC LIMIT 2 OFFSET 1`, q.calls[1].query)
+	assert.Equal(t, []any{"%@example.com"}, q.calls[1].args)
+
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.Items, 2)
+	assert.False(t, page.HasNext())
+	assert.Equal(t, 3, page.NextOffset())
+}
+
+// TestFindPage_Defaults tests default ordering and limits and the empty result.
+func TestFindPage_Defaults(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	q := &fakeQuerier{get: []any{int64(100)}, selected: []user{{ID: 1}}}
+	page, err := FindPage[user](ctx, q, "users", PageRequest{Limit: 5000}, "")
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "id", "email", "name", "created_at" FROM "users" ORDER BY "id" ASC LIMIT 1000 OFFSET 0`, q.calls[1].query)
+	assert.Equal(t, MaxPageLimit, page.Limit)
+	assert.True(t, page.HasNext())
+
+	q = &fakeQuerier{get: []any{int64(0)}}
+	page, err = FindPage[user](ctx, q, "users", PageRequest{}, "")
+	require.NoError(t, err)
+	assert.Len(t, q.calls, 1, "no select for an empty table")
+	assert.Empty(t, page.Items)
+	assert.NotNil(t, page.Items)
+	assert.Equal(t, DefaultPageLimit, page.Limit)
+
+	_, err = FindPage[user](ctx, &fakeQuerier{}, "users", PageRequest{OrderBy: "name; DROP TABLE users"}, "")
+	assert.ErrorContains(t, err, "unknown order column")
+}
@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg/sqlx"
+)
+
+// ErrNotFound возвращается, если запись с указанным первичным ключом не найдена
+var ErrNotFound = errors.New("record not found")
+
+// Querier — общая часть sqlx.Connection и sqlx.Tx, на которой построены хелперы.
+// Вызовы через sqlx.Connection с контекстом WithTx выполняются в транзакции из контекста.
+type Querier interface {
+	Get(ctx context.Context, dst any, query string, args ...any) error
+	Select(ctx context.Context, dst any, query string, args ...any) error
+	Exec(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+var (
+	_ Querier = (*sqlx.Connection)(nil)
+	_ Querier = (*sqlx.Tx)(nil)
+)
+
+// FindByID возвращает запись таблицы table с первичным ключом id
+func FindByID[T any](ctx context.Context, q Querier, table string, id any) (T, error) {
+	var result T
+	m, err := metaOf[T]()
+	if err != nil {
+		return result, err
+	}
+	quoted, err := quoteTable(table)
+	if err != nil {
+		return result, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1",
+		m.selectList(), quoted, quotedName(m.pkColumn()))
+	if err := q.Get(ctx, &result, query, id); err != nil {
+		return result, notFound(err, table, id)
+	}
+	return result, nil
+}
+
+// InsertReturning вставляет v в таблицу table и возвращает запись в том виде, в каком её сохранила база:
+// с заполненными serial ключами и значениями по умолчанию. Поля с тегом repo:"readonly" не вставляются.
+func InsertReturning[T any](ctx context.Context, q Querier, table string, v T) (T, error) {
+	var result T
+	m, err := metaOf[T]()
+	if err != nil {
+		return result, err
+	}
+	quoted, err := quoteTable(table)
+	if err != nil {
+		return result, err
+	}
+
+	columns := m.writable(true)
+	value := reflect.ValueOf(v)
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, c := range columns {
+		names[i] = quotedName(c)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = c.value(value)
+	}
+
+	var query string
+	if len(columns) == 0 {
+		query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", quoted, m.selectList())
+	} else {
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
+			quoted, strings.Join(names, ", "), strings.Join(placeholders, ", "), m.selectList())
+	}
+	if err := q.Get(ctx, &result, query, args...); err != nil {
+		return result, errors.Wrapf(err, "failed to insert into %s", table)
+	}
+	return result, nil
+}
+
+// UpdateByID обновляет все изменяемые колонки записи с первичным ключом из v и возвращает обновлённую запись.
+// Первичный ключ и поля с тегом repo:"readonly" не изменяются.
+func UpdateByID[T any](ctx context.Context, q Querier, table string, v T) (T, error) {
+	var result T
+	m, err := metaOf[T]()
+	if err != nil {
+		return result, err
+	}
+	quoted, err := quoteTable(table)
+	if err != nil {
+		return result, err
+	}
+
+	columns := m.writable(false)
+	if len(columns) == 0 {
+		return result, errors.Errorf("repo: %T has no updatable columns", v)
+	}
+	value := reflect.ValueOf(v)
+	sets := make([]string, len(columns))
+	args := make([]any, 0, len(columns)+1)
+	for i, c := range columns {
+		sets[i] = fmt.Sprintf("%s = $%d", quotedName(c), i+1)
+		args = append(args, c.value(value))
+	}
+	id := m.pkColumn().value(value)
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d RETURNING %s",
+		quoted, strings.Join(sets, ", "), quotedName(m.pkColumn()), len(args), m.selectList())
+	if err := q.Get(ctx, &result, query, args...); err != nil {
+		return result, notFound(err, table, id)
+	}
+	return result, nil
+}
+
+// DeleteByID удаляет запись с первичным ключом id; T задаёт колонку первичного ключа
+func DeleteByID[T any](ctx context.Context, q Querier, table string, id any) error {
+	m, err := metaOf[T]()
+	if err != nil {
+		return err
+	}
+	quoted, err := quoteTable(table)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", quoted, quotedName(m.pkColumn()))
+	res, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete from %s", table)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get affected rows")
+	}
+	if affected == 0 {
+		return errors.Wrapf(ErrNotFound, "%s %v", table, id)
+	}
+	return nil
+}
+
+// notFound заменяет sql.ErrNoRows на ErrNotFound
+func notFound(err error, table string, id any) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrapf(ErrNotFound, "%s %v", table, id)
+	}
+	return errors.Wrapf(err, "failed to query %s", table)
+}
@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+)
+
+type timestamps struct {
+	CreatedAt time.Time `db:"created_at" repo:"readonly"`
+}
+
+type user struct {
+	ID    int64  `db:"id" repo:"readonly"`
+	Email string `db:"email"`
+	Name  string `db:"name"`
+	Note  string `db:"-"`
+	timestamps
+}
+
+type account struct {
+	Code    string `db:"code" repo:"pk"`
+	Balance int64  `db:"balance"`
+}
+
+// call is a query received by fakeQuerier.
+type call struct {
+	query string
+	args  []any
+}
+
+// fakeQuerier records queries and fills destinations with the configured values.
+type fakeQuerier struct {
+	calls    []call
+	get      []any // values assigned to Get destinations in order
+	selected any   // value assigned to the Select destination
+	err      error
+	affected int64
+}
+
+func (f *fakeQuerier) Get(_ context.Context, dst any, query string, args ...any) error {
+	f.calls = append(f.calls, call{query, args})
+	if f.err != nil {
+		return f.err
+	}
+	if len(f.get) > 0 {
+		reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(f.get[0]))
+		f.get = f.get[1:]
+	}
+	return nil
+}
+
+func (f *fakeQuerier) Select(_ context.Context, dst any, query string, args ...any) error {
+	f.calls = append(f.calls, call{query, args})
+	if f.selected != nil {
+		reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(f.selected))
+	}
+	return f.err
+}
+
+func (f *fakeQuerier) Exec(_ context.Context, query string, args ...any) (sql.Result, error) {
+	f.calls = append(f.calls, call{query, args})
+	return driverResult(f.affected), f.err
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+// TestMetaOf tests column discovery from db and repo tags.
+func TestMetaOf(t *testing.T) {
+	t.Parallel()
+
+	m, err := metaOf[user]()
+	require.NoError(t, err)
+	assert.Equal(t, `"id", "email", "name", "created_at"`, m.selectList())
+	assert.Equal(t, "id", m.pkColumn().name)
+
+	var writable []string
+	for _, c := range m.writable(true) {
+		writable = append(writable, c.name)
+	}
+	assert.Equal(t, []string{"email", "name"}, writable)
+
+	m, err = metaOf[account]()
+	require.NoError(t, err)
+	assert.Equal(t, "code", m.pkColumn().name)
+
+	_, err = metaOf[struct {
+		Name string `db:"name"`
+	}]()
+	assert.ErrorContains(t, err, "no primary key")
+
+	_, err = metaOf[int]()
+	assert.ErrorContains(t, err, "not a struct")
+
+	_, err = metaOf[struct {
+		ID int64 `db:"id; DROP TABLE users"`
+	}]()
+	assert.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+}
+
+// TestFindByID tests the query and the not found error.
+func TestFindByID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	q := &fakeQuerier{get: []any{user{ID: 1, Email: "a@example.com"}}}
+	u, err := FindByID[user](ctx, q, "public.users", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "a@example.com", u.Email)
+	assert.Equal(t, `SELECT "id", "email", "name", "created_at" FROM "public"."users" WHERE "id" = $1`, q.calls[0].query)
+	assert.Equal(t, []any{1}, q.calls[0].args)
+
+	_, err = FindByID[user](ctx, &fakeQuerier{err: sql.ErrNoRows}, "users", 2)
+	assert.True(t, errors.Is(err, ErrNotFound), err)
+
+	_, err = FindByID[user](ctx, &fakeQuerier{err: errors.New("boom")}, "users", 2)
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.ErrorContains(t, err, "boom")
+
+	q = &fakeQuerier{}
+	_, err = FindByID[user](ctx, q, `users"; DROP TABLE users; --`, 1)
+	assert.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	assert.Empty(t, q.calls)
+}
+
+// TestInsertReturning tests that readonly columns are skipped and all columns are returned.
+func TestInsertReturning(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeQuerier{get: []any{user{ID: 7, Email: "a@example.com", Name: "Alice"}}}
+	u, err := InsertReturning(context.Background(), q, "users", user{ID: 100, Email: "a@example.com", Name: "Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), u.ID)
+	assert.Equal(t, `INSERT INTO "users" ("email", "name") VALUES ($1, $2) RETURNING "id", "email", "name", "created_at"`, q.calls[0].query)
+	assert.Equal(t, []any{"a@example.com", "Alice"}, q.calls[0].args)
+
+	q = &fakeQuerier{}
+	_, err = InsertReturning(context.Background(), q, "accounts", account{Code: "acc-1", Balance: 10})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "accounts" ("code", "balance") VALUES ($1, $2) RETURNING "code", "balance"`, q.calls[0].query)
+}
+
+// TestUpdateByID tests that the primary key goes to WHERE and readonly columns are not updated.
+func TestUpdateByID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	q := &fakeQuerier{}
+	_, err := UpdateByID(ctx, q, "users", user{ID: 7, Email: "b@example.com", Name: "Bob"})
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "users" SET "email" = $1, "name" = $2 WHERE "id" = $3 RETURNING "id", "email", "name", "created_at"`, q.calls[0].query)
+	assert.Equal(t, []any{"b@example.com", "Bob", int64(7)}, q.calls[0].args)
+
+	_, err = UpdateByID(ctx, &fakeQuerier{err: sql.ErrNoRows}, "users", user{ID: 8})
+	assert.True(t, errors.Is(err, ErrNotFound), err)
+}
+
+// TestDeleteByID tests that deleting a missing record returns ErrNotFound.
+func TestDeleteByID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	q := &fakeQuerier{affected: 1}
+	require.NoError(t, DeleteByID[account](ctx, q, "accounts", "acc-1"))
+	assert.Equal(t, `DELETE FROM "accounts" WHERE "code" = $1`, q.calls[0].query)
+
+	err := DeleteByID[account](ctx, &fakeQuerier{}, "accounts", "acc-2")
+	assert.True(t, errors.Is(err, ErrNotFound), err)
+}
@@ -0,0 +1,89 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg/pgtest"
+	"github.com/pure-golang/adapters/db/pg/sqlx"
+	"github.com/pure-golang/adapters/db/repo"
+)
+
+type user struct {
+	ID        int64     `db:"id" repo:"readonly"`
+	Email     string    `db:"email"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at" repo:"readonly"`
+}
+
+const schema = `CREATE TABLE users (
+	id         bigserial PRIMARY KEY,
+	email      text NOT NULL UNIQUE,
+	name       text NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// TestRepo tests the CRUD and paging helpers against PostgreSQL.
+func TestRepo(t *testing.T) {
+	pg := pgtest.StartPostgres(t, &pgtest.Options{
+		Setup: func(ctx context.Context, db *sql.DB) error {
+			_, err := db.ExecContext(ctx, schema)
+			return err
+		},
+	})
+	conn := pg.Connect(t)
+	ctx := context.Background()
+
+	alice, err := repo.InsertReturning(ctx, conn, "users", user{Email: "alice@example.com", Name: "Alice"})
+	require.NoError(t, err)
+	assert.NotZero(t, alice.ID)
+	assert.False(t, alice.CreatedAt.IsZero())
+
+	_, err = repo.InsertReturning(ctx, conn, "users", user{Email: "alice@example.com", Name: "Duplicate"})
+	assert.True(t, sqlx.IsUniqueViolation(err), err)
+
+	found, err := repo.FindByID[user](ctx, conn, "users", alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, alice.Email, found.Email)
+
+	found.Name = "Alice Smith"
+	updated, err := repo.UpdateByID(ctx, conn, "users", found)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Smith", updated.Name)
+	assert.True(t, alice.CreatedAt.Equal(updated.CreatedAt))
+
+	for _, name := range []string{"Bob", "Carol", "Dave"} {
+		_, err := repo.InsertReturning(ctx, conn, "users", user{Email: name + "@example.com", Name: name})
+		require.NoError(t, err)
+	}
+
+	page, err := repo.FindPage[user](ctx, conn, "users", repo.PageRequest{Limit: 2, OrderBy: "name", Desc: true}, "name <> $1", "Bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "Dave", page.Items[0].Name)
+	assert.Equal(t, "Carol", page.Items[1].Name)
+	assert.True(t, page.HasNext())
+
+	// helpers run in the transaction stored in the context
+	err = conn.RunTx(ctx, nil, func(ctx context.Context, _ *sqlx.Tx) error {
+		if err := repo.DeleteByID[user](ctx, conn, "users", alice.ID); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+	_, err = repo.FindByID[user](ctx, conn, "users", alice.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteByID[user](ctx, conn, "users", alice.ID))
+	_, err = repo.FindByID[user](ctx, conn, "users", alice.ID)
+	assert.True(t, errors.Is(err, repo.ErrNotFound), err)
+	assert.True(t, errors.Is(repo.DeleteByID[user](ctx, conn, "users", alice.ID), repo.ErrNotFound))
+}
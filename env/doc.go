@@ -23,6 +23,9 @@
 //   - Поддерживает теги envconfig для маппинга переменных
 //   - Поддерживает значения по умолчанию через тег default
 //   - Поддерживает обязательные поля через тег required
+//   - Проверяет значения конфигураций, реализующих [Validatable], включая вложенные
+//     (например, std.Config внутри конфигурации приложения); ошибки всех полей
+//     возвращаются разом как [*ValidationError]
 //
 // Теги конфигурации:
 //
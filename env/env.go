@@ -16,6 +16,9 @@ func InitConfig(config any) error {
 	if err := envconfig.Process("", config); err != nil {
 		return errors.Wrap(err, "failed to envconfig.Process")
 	}
+	if err := Validate(config); err != nil {
+		return errors.Wrap(err, "invalid config")
+	}
 
 	return nil
 }
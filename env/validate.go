@@ -0,0 +1,119 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Validatable реализуется конфигурациями адаптеров с семантической проверкой значений
+// (диапазоны портов, существование файлов, синтаксис адресов). InitConfig вызывает Validate
+// у загруженной конфигурации и у всех вложенных конфигураций.
+type Validatable interface {
+	Validate() error
+}
+
+// FieldError — некорректное значение поля конфигурации
+type FieldError struct {
+	Field  string // имя переменной окружения или поля, например GRPC_PORT
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationError собирает ошибки всех полей конфигурации, чтобы сообщить о них разом
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add добавляет ошибку поля
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Err возвращает e, если есть ошибки, иначе nil
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		problems[i] = f.Error()
+	}
+	return strings.Join(problems, "; ")
+}
+
+// Validate проверяет config и вложенные в него конфигурации, реализующие Validatable.
+// Ошибки всех конфигураций объединяются в *ValidationError; ошибка, не являющаяся
+// *ValidationError, записывается под именем поля структуры.
+func Validate(config any) error {
+	verr := &ValidationError{}
+	validateValue(reflect.ValueOf(config), "", verr)
+	return verr.Err()
+}
+
+var validatableType = reflect.TypeFor[Validatable]()
+
+func validateValue(v reflect.Value, field string, verr *ValidationError) {
+	if !v.IsValid() {
+		return
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		if v.Type().Implements(validatableType) {
+			break
+		}
+		v = v.Elem()
+	}
+
+	if v.Type().Implements(validatableType) || (v.CanAddr() && v.Addr().Type().Implements(validatableType)) {
+		if v.Kind() != reflect.Pointer && v.CanAddr() {
+			v = v.Addr()
+		}
+		if !v.CanInterface() {
+			return
+		}
+		addValidationError(verr, field, v.Interface().(Validatable).Validate())
+		return
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if field != "" {
+			name = field + "." + name
+		}
+		validateValue(v.Field(i), name, verr)
+	}
+}
+
+func addValidationError(verr *ValidationError, field string, err error) {
+	if err == nil {
+		return
+	}
+	var nested *ValidationError
+	if errors.As(err, &nested) {
+		verr.Fields = append(verr.Fields, nested.Fields...)
+		return
+	}
+	if field == "" {
+		field = "config"
+	}
+	verr.Fields = append(verr.Fields, FieldError{Field: field, Reason: err.Error()})
+}
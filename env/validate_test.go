@@ -0,0 +1,83 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// portConfig validates its port with a value receiver
+type portConfig struct {
+	Port int `envconfig:"PORT" default:"8080"`
+}
+
+func (c portConfig) Validate() error {
+	verr := &ValidationError{}
+	if c.Port < 1 || c.Port > 65535 {
+		verr.Add("PORT", "port %d out of range [1, 65535]", c.Port)
+	}
+	return verr.Err()
+}
+
+// dsnConfig validates with a pointer receiver and returns a plain error
+type dsnConfig struct {
+	DSN string
+}
+
+func (c *dsnConfig) Validate() error {
+	if c.DSN == "" {
+		return errors.New("dsn is required")
+	}
+	return nil
+}
+
+// appConfig nests adapter configs
+type appConfig struct {
+	HTTP     portConfig
+	GRPC     *portConfig
+	Database dsnConfig
+	Name     string
+	internal portConfig
+}
+
+func TestValidate_Nested(t *testing.T) {
+	t.Parallel()
+	cfg := appConfig{
+		HTTP:     portConfig{Port: 0},
+		GRPC:     &portConfig{Port: 70000},
+		internal: portConfig{Port: -1},
+	}
+
+	err := Validate(&cfg)
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []FieldError{
+		{Field: "PORT", Reason: "port 0 out of range [1, 65535]"},
+		{Field: "PORT", Reason: "port 70000 out of range [1, 65535]"},
+		{Field: "Database", Reason: "dsn is required"},
+	}, verr.Fields)
+	assert.EqualError(t, err, "PORT: port 0 out of range [1, 65535]; PORT: port 70000 out of range [1, 65535]; Database: dsn is required")
+}
+
+func TestValidate_Valid(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, Validate(&appConfig{HTTP: portConfig{Port: 80}, Database: dsnConfig{DSN: "postgres://"}}))
+	assert.NoError(t, Validate(portConfig{Port: 80}))
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate(&TestConfig{}), "configs without Validate are not checked")
+}
+
+func TestInitConfig_Validate(t *testing.T) {
+	t.Setenv("PORT", "99999")
+
+	var cfg portConfig
+	err := InitConfig(&cfg)
+
+	require.Error(t, err)
+	assert.EqualError(t, err, "invalid config: PORT: port 99999 out of range [1, 65535]")
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
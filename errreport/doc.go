@@ -0,0 +1,22 @@
+// Package errreport определяет интерфейс [Reporter] для отправки ошибок во внешнюю
+// систему отслеживания (Sentry и совместимые).
+//
+// Реализации находятся в дочерних пакетах:
+//   - [errreport/noop] — заглушка для unit-тестов и окружений без отслеживания ошибок
+//   - [errreport/sentry] — реализация на базе sentry-go
+//
+// Пользователь и теги события берутся из контекста ([ContextWithUser], [ContextWithTags])
+// и дополняются опциями ([WithLevel], [WithTag], [WithExtra], [WithFingerprint]):
+//
+//	ctx = errreport.ContextWithUser(ctx, errreport.User{ID: userID})
+//	reporter.CaptureException(ctx, err, errreport.WithTag("order_id", orderID))
+//
+// Интеграции:
+//   - gRPC Recovery: middleware.WithRecoveryReporter или MonitoringOptions.ErrorReporter
+//   - паники и фатальные ошибки из logger.HandlePanic и logger.Fatal: [NewNotifier]
+//
+// Использование:
+//
+//	var reporter errreport.Reporter = noop.New() // тесты
+//	reporter := sentry.New(cfg)                  // продакшн, затем reporter.Start()
+package errreport
@@ -0,0 +1,147 @@
+package errreport
+
+import (
+	"context"
+	"maps"
+)
+
+// Level — уровень события
+type Level string
+
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+)
+
+// Reporter отправляет ошибки и сообщения во внешнюю систему отслеживания ошибок (Sentry и совместимые).
+// Методы Capture* не блокируются на отправке и не возвращают ошибок: сбой доставки не должен
+// влиять на обработку запроса.
+type Reporter interface {
+	// CaptureException отправляет ошибку; стек берётся из ошибки pkg/errors, если он есть
+	CaptureException(ctx context.Context, err error, opts ...Option)
+	// CaptureMessage отправляет текстовое сообщение (по умолчанию с уровнем LevelInfo)
+	CaptureMessage(ctx context.Context, msg string, opts ...Option)
+	// Flush ожидает отправки накопленных событий до отмены ctx
+	Flush(ctx context.Context) error
+}
+
+// User — пользователь, в контексте которого произошло событие
+type User struct {
+	ID        string
+	Email     string
+	Username  string
+	IPAddress string
+}
+
+// Event — атрибуты события, собранные из контекста и опций
+type Event struct {
+	Level       Level
+	User        *User
+	Tags        map[string]string // индексируемые атрибуты для поиска и группировки
+	Extra       map[string]any    // дополнительные данные без индексации
+	Fingerprint []string          // правило группировки событий; nil — группировка по умолчанию
+}
+
+// Option изменяет атрибуты события
+type Option func(*Event)
+
+// WithLevel задаёт уровень события
+func WithLevel(level Level) Option {
+	return func(e *Event) {
+		e.Level = level
+	}
+}
+
+// WithUser задаёт пользователя события вместо пользователя из контекста
+func WithUser(user User) Option {
+	return func(e *Event) {
+		e.User = &user
+	}
+}
+
+// WithTag добавляет тег события
+func WithTag(key, value string) Option {
+	return func(e *Event) {
+		if e.Tags == nil {
+			e.Tags = make(map[string]string)
+		}
+		e.Tags[key] = value
+	}
+}
+
+// WithTags добавляет теги события
+func WithTags(tags map[string]string) Option {
+	return func(e *Event) {
+		if e.Tags == nil {
+			e.Tags = make(map[string]string, len(tags))
+		}
+		maps.Copy(e.Tags, tags)
+	}
+}
+
+// WithExtra добавляет дополнительные данные события
+func WithExtra(key string, value any) Option {
+	return func(e *Event) {
+		if e.Extra == nil {
+			e.Extra = make(map[string]any)
+		}
+		e.Extra[key] = value
+	}
+}
+
+// WithFingerprint задаёт правило группировки событий
+func WithFingerprint(parts ...string) Option {
+	return func(e *Event) {
+		e.Fingerprint = parts
+	}
+}
+
+// userKey и tagsKey — ключи контекста для пользователя и тегов
+type (
+	userKey struct{}
+	tagsKey struct{}
+)
+
+// ContextWithUser возвращает контекст с пользователем, который попадёт во все события,
+// отправленные с этим контекстом
+func ContextWithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext возвращает пользователя из контекста
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey{}).(User)
+	return user, ok
+}
+
+// ContextWithTags возвращает контекст с тегами, дополняющими теги родительского контекста
+func ContextWithTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := maps.Clone(TagsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(tags))
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// TagsFromContext возвращает теги из контекста; результат нельзя изменять
+func TagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
+
+// NewEvent собирает атрибуты события: уровень level, пользователь и теги из контекста, затем опции.
+// Используется реализациями Reporter.
+func NewEvent(ctx context.Context, level Level, opts ...Option) Event {
+	e := Event{Level: level, Tags: maps.Clone(TagsFromContext(ctx))}
+	if user, ok := UserFromContext(ctx); ok {
+		e.User = &user
+	}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
@@ -0,0 +1,52 @@
+package errreport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent_MergesContextAndOptions(t *testing.T) {
+	t.Parallel()
+	ctx := ContextWithUser(context.Background(), User{ID: "42"})
+	ctx = ContextWithTags(ctx, map[string]string{"service": "orders", "region": "eu"})
+	ctx = ContextWithTags(ctx, map[string]string{"region": "us"})
+
+	e := NewEvent(ctx, LevelError,
+		WithTag("order_id", "7"),
+		WithExtra("attempt", 3),
+		WithFingerprint("orders", "timeout"),
+	)
+
+	assert.Equal(t, LevelError, e.Level)
+	require.NotNil(t, e.User)
+	assert.Equal(t, "42", e.User.ID)
+	assert.Equal(t, map[string]string{"service": "orders", "region": "us", "order_id": "7"}, e.Tags)
+	assert.Equal(t, map[string]any{"attempt": 3}, e.Extra)
+	assert.Equal(t, []string{"orders", "timeout"}, e.Fingerprint)
+
+	// Опции события не изменяют теги контекста
+	assert.Equal(t, map[string]string{"service": "orders", "region": "us"}, TagsFromContext(ctx))
+}
+
+func TestNewEvent_OptionsOverrideContext(t *testing.T) {
+	t.Parallel()
+	ctx := ContextWithUser(context.Background(), User{ID: "42"})
+
+	e := NewEvent(ctx, LevelInfo, WithLevel(LevelWarning), WithUser(User{ID: "7", Email: "a@example.com"}))
+
+	assert.Equal(t, LevelWarning, e.Level)
+	assert.Equal(t, &User{ID: "7", Email: "a@example.com"}, e.User)
+}
+
+func TestNewEvent_EmptyContext(t *testing.T) {
+	t.Parallel()
+	e := NewEvent(context.Background(), LevelInfo)
+
+	assert.Nil(t, e.User)
+	assert.Nil(t, e.Tags)
+	_, ok := UserFromContext(context.Background())
+	assert.False(t, ok)
+}
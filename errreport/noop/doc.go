@@ -0,0 +1,13 @@
+// Package noop реализует [errreport.Reporter] как no-op заглушку для unit-тестов
+// и окружений без системы отслеживания ошибок.
+//
+// Все методы ничего не отправляют и не возвращают ошибок.
+//
+// Использование:
+//
+//	reporter := noop.New()
+//
+// Переменные окружения: отсутствуют.
+//
+// Thread-safe: да.
+package noop
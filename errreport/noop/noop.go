@@ -0,0 +1,28 @@
+package noop
+
+import (
+	"context"
+
+	"github.com/pure-golang/adapters/errreport"
+)
+
+var _ errreport.Reporter = (*Reporter)(nil)
+
+// Reporter представляет no-op реализацию errreport.Reporter
+type Reporter struct{}
+
+// New создаёт новый no-op Reporter
+func New() *Reporter {
+	return &Reporter{}
+}
+
+// CaptureException не выполняет операций
+func (r *Reporter) CaptureException(ctx context.Context, err error, opts ...errreport.Option) {}
+
+// CaptureMessage не выполняет операций
+func (r *Reporter) CaptureMessage(ctx context.Context, msg string, opts ...errreport.Option) {}
+
+// Flush не выполняет операций
+func (r *Reporter) Flush(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,18 @@
+package noop
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReporterMethods(t *testing.T) {
+	t.Parallel()
+	r := New()
+
+	r.CaptureException(context.Background(), errors.New("test"))
+	r.CaptureMessage(context.Background(), "test")
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+}
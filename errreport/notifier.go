@@ -0,0 +1,36 @@
+package errreport
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// NewNotifier возвращает logger.Notifier, отправляющий в r паники из logger.HandlePanic
+// и фатальные ошибки из logger.Fatal. Перед завершением процесса по logger.Fatal
+// накопленные события отправляются (Flush).
+//
+//	defer logger.AddNotifier(errreport.NewNotifier(reporter))()
+//
+// gRPC Recovery вызывает logger.HandlePanic: при зарегистрированном нотификаторе
+// не передавайте тот же Reporter в middleware.WithRecoveryReporter, иначе паника будет отправлена дважды.
+func NewNotifier(r Reporter) logger.Notifier {
+	return logger.NotifierFunc(func(ctx context.Context, e *logger.Event) error {
+		level := LevelError
+		if e.Kind == logger.EventFatal {
+			level = LevelFatal
+		}
+		r.CaptureException(ctx, errors.Wrap(e.Err(), string(e.Kind)),
+			WithLevel(level),
+			WithTag("event.kind", string(e.Kind)),
+			WithExtra("message", e.Message),
+			WithExtra("stack", e.Stack),
+		)
+		if e.Kind != logger.EventFatal {
+			return nil
+		}
+		return errors.Wrap(r.Flush(ctx), "failed to flush error reports")
+	})
+}
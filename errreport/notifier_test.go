@@ -0,0 +1,58 @@
+package errreport
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+type recordingReporter struct {
+	errs    []error
+	events  []Event
+	flushes int
+}
+
+func (r *recordingReporter) CaptureException(ctx context.Context, err error, opts ...Option) {
+	r.errs = append(r.errs, err)
+	r.events = append(r.events, NewEvent(ctx, LevelError, opts...))
+}
+
+func (r *recordingReporter) CaptureMessage(context.Context, string, ...Option) {}
+
+func (r *recordingReporter) Flush(context.Context) error {
+	r.flushes++
+	return nil
+}
+
+func TestNewNotifier_ReportsPanics(t *testing.T) {
+	reporter := &recordingReporter{}
+	defer logger.AddNotifier(NewNotifier(reporter))()
+
+	logger.HandlePanic(context.Background(), slog.New(slog.DiscardHandler), "boom", "panic recovered")
+
+	require.Len(t, reporter.errs, 1)
+	assert.EqualError(t, reporter.errs[0], "panic: boom")
+	assert.Equal(t, LevelError, reporter.events[0].Level)
+	assert.Equal(t, "panic", reporter.events[0].Tags["event.kind"])
+	assert.Equal(t, "panic recovered", reporter.events[0].Extra["message"])
+	assert.NotEmpty(t, reporter.events[0].Extra["stack"])
+	assert.Zero(t, reporter.flushes)
+}
+
+func TestNewNotifier_FlushesOnFatal(t *testing.T) {
+	reporter := &recordingReporter{}
+	n := NewNotifier(reporter)
+
+	err := n.Notify(context.Background(), &logger.Event{Kind: logger.EventFatal, Value: assert.AnError})
+
+	require.NoError(t, err)
+	require.Len(t, reporter.events, 1)
+	assert.Equal(t, LevelFatal, reporter.events[0].Level)
+	assert.ErrorIs(t, reporter.errs[0], assert.AnError)
+	assert.Equal(t, 1, reporter.flushes)
+}
@@ -0,0 +1,32 @@
+// Package sentry реализует [errreport.Reporter] на базе github.com/getsentry/sentry-go.
+//
+// Клиент создаётся в Start; до вызова Start и после Close события отбрасываются.
+// Каждое событие получает собственный scope: уровень, пользователь, теги и extra
+// из контекста и опций errreport, а также теги trace_id и span_id текущего span'а.
+// Стек ошибок pkg/errors передаётся в Sentry.
+//
+// Использование:
+//
+//	import "github.com/pure-golang/adapters/errreport/sentry"
+//
+//	reporter := sentry.New(cfg)
+//	if err := reporter.Start(); err != nil {
+//	    return err
+//	}
+//	defer reporter.Close() // отправка накопленных событий, не дольше SENTRY_FLUSH_TIMEOUT
+//
+//	reporter.CaptureException(ctx, err, errreport.WithTag("order_id", orderID))
+//
+// Конфигурация через переменные окружения:
+//
+//	SENTRY_DSN           — DSN проекта (required)
+//	SENTRY_ENVIRONMENT   — окружение
+//	SENTRY_RELEASE       — версия приложения
+//	SENTRY_SERVER_NAME   — имя сервера (по умолчанию имя хоста)
+//	SENTRY_SAMPLE_RATE   — доля отправляемых событий (default: 1)
+//	SENTRY_DEBUG         — отладочный вывод SDK (default: false)
+//	SENTRY_FLUSH_TIMEOUT — таймаут отправки при Close (default: 2s)
+//	SENTRY_TAGS          — теги всех событий: key1:value1,key2:value2
+//
+// Thread-safe: да.
+package sentry
@@ -0,0 +1,153 @@
+package sentry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pure-golang/adapters/errreport"
+)
+
+var _ errreport.Reporter = (*Reporter)(nil)
+
+// Config содержит конфигурацию Sentry
+type Config struct {
+	DSN          string            `envconfig:"SENTRY_DSN" required:"true"`        // DSN проекта Sentry
+	Environment  string            `envconfig:"SENTRY_ENVIRONMENT"`                // Окружение (production, staging)
+	Release      string            `envconfig:"SENTRY_RELEASE"`                    // Версия приложения
+	ServerName   string            `envconfig:"SENTRY_SERVER_NAME"`                // Имя сервера; пусто — имя хоста
+	SampleRate   float64           `envconfig:"SENTRY_SAMPLE_RATE" default:"1"`    // Доля отправляемых событий (0..1]
+	Debug        bool              `envconfig:"SENTRY_DEBUG" default:"false"`      // Отладочный вывод SDK
+	FlushTimeout time.Duration     `envconfig:"SENTRY_FLUSH_TIMEOUT" default:"2s"` // Таймаут отправки событий при Close
+	Tags         map[string]string `envconfig:"SENTRY_TAGS"`                       // Теги всех событий (key1:value1,key2:value2)
+
+	// Transport — транспорт событий вместо HTTP (например, sentry.MockTransport в тестах)
+	Transport sentrygo.Transport `ignored:"true"`
+}
+
+// Reporter реализует errreport.Reporter поверх sentry-go.
+// Клиент создаётся в Start; до вызова Start события отбрасываются.
+type Reporter struct {
+	cfg Config
+
+	mu     sync.RWMutex
+	client *sentrygo.Client
+}
+
+// New создаёт Reporter; подключение выполняется в Start
+func New(cfg Config) *Reporter {
+	return &Reporter{cfg: cfg}
+}
+
+// Start создаёт клиент Sentry
+func (r *Reporter) Start() error {
+	client, err := sentrygo.NewClient(sentrygo.ClientOptions{
+		Dsn:         r.cfg.DSN,
+		Environment: r.cfg.Environment,
+		Release:     r.cfg.Release,
+		ServerName:  r.cfg.ServerName,
+		SampleRate:  r.cfg.SampleRate,
+		Debug:       r.cfg.Debug,
+		Transport:   r.cfg.Transport,
+		Tags:        r.cfg.Tags,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create sentry client")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.client = client
+	return nil
+}
+
+// Close отправляет накопленные события (не дольше FlushTimeout) и закрывает клиент
+func (r *Reporter) Close() error {
+	r.mu.Lock()
+	client := r.client
+	r.client = nil
+	r.mu.Unlock()
+	if client == nil {
+		return nil
+	}
+
+	flushed := client.Flush(r.flushTimeout())
+	client.Close()
+	if !flushed {
+		return errors.New("failed to flush sentry events: timeout")
+	}
+	return nil
+}
+
+// CaptureException отправляет ошибку; стек берётся из ошибки pkg/errors
+func (r *Reporter) CaptureException(ctx context.Context, err error, opts ...errreport.Option) {
+	client := r.getClient()
+	if client == nil || err == nil {
+		return
+	}
+	e := errreport.NewEvent(ctx, errreport.LevelError, opts...)
+	client.CaptureException(err, &sentrygo.EventHint{Context: ctx, OriginalException: err}, newScope(ctx, e))
+}
+
+// CaptureMessage отправляет текстовое сообщение
+func (r *Reporter) CaptureMessage(ctx context.Context, msg string, opts ...errreport.Option) {
+	client := r.getClient()
+	if client == nil {
+		return
+	}
+	e := errreport.NewEvent(ctx, errreport.LevelInfo, opts...)
+	client.CaptureMessage(msg, &sentrygo.EventHint{Context: ctx}, newScope(ctx, e))
+}
+
+// Flush ожидает отправки накопленных событий до отмены ctx
+func (r *Reporter) Flush(ctx context.Context) error {
+	client := r.getClient()
+	if client == nil {
+		return nil
+	}
+	if !client.FlushWithContext(ctx) {
+		return errors.Wrap(ctx.Err(), "failed to flush sentry events")
+	}
+	return nil
+}
+
+func (r *Reporter) getClient() *sentrygo.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.client
+}
+
+func (r *Reporter) flushTimeout() time.Duration {
+	if r.cfg.FlushTimeout > 0 {
+		return r.cfg.FlushTimeout
+	}
+	return 2 * time.Second
+}
+
+// newScope переносит атрибуты события в scope Sentry и связывает событие с трейсом запроса
+func newScope(ctx context.Context, e errreport.Event) *sentrygo.Scope {
+	scope := sentrygo.NewScope()
+	scope.SetLevel(sentrygo.Level(e.Level))
+	if e.User != nil {
+		scope.SetUser(sentrygo.User{
+			ID:        e.User.ID,
+			Email:     e.User.Email,
+			Username:  e.User.Username,
+			IPAddress: e.User.IPAddress,
+		})
+	}
+	scope.SetTags(e.Tags)
+	scope.SetExtras(e.Extra)
+	if len(e.Fingerprint) > 0 {
+		scope.SetFingerprint(e.Fingerprint)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		scope.SetTag("trace_id", sc.TraceID().String())
+		scope.SetTag("span_id", sc.SpanID().String())
+	}
+	return scope
+}
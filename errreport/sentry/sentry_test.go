@@ -0,0 +1,98 @@
+package sentry
+
+import (
+	"context"
+	"testing"
+
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/pure-golang/adapters/errreport"
+)
+
+func newTestReporter(t *testing.T) (*Reporter, *sentrygo.MockTransport) {
+	t.Helper()
+	transport := &sentrygo.MockTransport{}
+	r := New(Config{
+		DSN:         "https://public@sentry.example.com/1",
+		Environment: "test",
+		Release:     "1.0.0",
+		SampleRate:  1,
+		Tags:        map[string]string{"service": "orders"},
+		Transport:   transport,
+	})
+	require.NoError(t, r.Start())
+	t.Cleanup(func() { assert.NoError(t, r.Close()) })
+	return r, transport
+}
+
+func TestReporter_CaptureException(t *testing.T) {
+	t.Parallel()
+	r, transport := newTestReporter(t)
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	ctx = errreport.ContextWithUser(ctx, errreport.User{ID: "42", Email: "user@example.com"})
+	ctx = errreport.ContextWithTags(ctx, map[string]string{"tenant": "acme"})
+
+	r.CaptureException(ctx, errors.New("payment failed"),
+		errreport.WithTag("order_id", "7"),
+		errreport.WithExtra("attempt", 3),
+		errreport.WithFingerprint("payments"),
+	)
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	e := events[0]
+	assert.Equal(t, sentrygo.LevelError, e.Level)
+	assert.Equal(t, "test", e.Environment)
+	assert.Equal(t, "1.0.0", e.Release)
+	require.NotEmpty(t, e.Exception)
+	assert.Equal(t, "payment failed", e.Exception[len(e.Exception)-1].Value)
+	assert.NotNil(t, e.Exception[len(e.Exception)-1].Stacktrace)
+	assert.Equal(t, "42", e.User.ID)
+	assert.Equal(t, "user@example.com", e.User.Email)
+	assert.Equal(t, "acme", e.Tags["tenant"])
+	assert.Equal(t, "7", e.Tags["order_id"])
+	assert.Equal(t, "orders", e.Tags["service"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), e.Tags["trace_id"])
+	assert.Equal(t, 3, e.Extra["attempt"])
+	assert.Equal(t, []string{"payments"}, e.Fingerprint)
+}
+
+func TestReporter_CaptureMessage(t *testing.T) {
+	t.Parallel()
+	r, transport := newTestReporter(t)
+
+	r.CaptureMessage(context.Background(), "quota almost exceeded", errreport.WithLevel(errreport.LevelWarning))
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "quota almost exceeded", events[0].Message)
+	assert.Equal(t, sentrygo.LevelWarning, events[0].Level)
+	assert.NoError(t, r.Flush(context.Background()))
+}
+
+func TestReporter_NotStarted(t *testing.T) {
+	t.Parallel()
+	transport := &sentrygo.MockTransport{}
+	r := New(Config{DSN: "https://public@sentry.example.com/1", Transport: transport})
+
+	r.CaptureException(context.Background(), errors.New("ignored"))
+	r.CaptureMessage(context.Background(), "ignored")
+
+	assert.Empty(t, transport.Events())
+	assert.NoError(t, r.Flush(context.Background()))
+	assert.NoError(t, r.Close())
+}
+
+func TestReporter_StartInvalidDSN(t *testing.T) {
+	t.Parallel()
+	r := New(Config{DSN: "not a dsn"})
+
+	assert.Error(t, r.Start())
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // клиентская проверка здоровья каналов (healthCheckConfig)
+	"google.golang.org/grpc/resolver"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Политики балансировки нагрузки
+const (
+	PickFirst  = "pick_first"
+	RoundRobin = "round_robin"
+)
+
+var _ grpc.ClientConnInterface = (*Pool)(nil)
+
+type Config struct {
+	// Target — адрес сервера в синтаксисе gRPC: "host:port", "dns:///orders.prod.svc:50051", "unix:///run/orders.sock"
+	Target string `envconfig:"GRPC_CLIENT_TARGET" required:"true"`
+	// PoolSize — число ClientConn в пуле. Каждое ClientConn открывает свои HTTP/2 соединения,
+	// поэтому пул обходит лимит одновременных стримов на соединение (MaxConcurrentStreams)
+	PoolSize int `envconfig:"GRPC_CLIENT_POOL_SIZE" default:"1"`
+	// LoadBalancing — политика балансировки между адресами target: "round_robin" или "pick_first"
+	LoadBalancing string `envconfig:"GRPC_CLIENT_LOAD_BALANCING" default:"round_robin"`
+	// DNSResolveInterval — период повторного разрешения DNS имени target: новые реплики сервиса
+	// получают запросы без разрыва соединений. 0 — только при потере соединения (поведение gRPC).
+	// DNS resolver gRPC не разрешает имя чаще раза в 30 секунд
+	DNSResolveInterval time.Duration `envconfig:"GRPC_CLIENT_DNS_RESOLVE_INTERVAL" default:"30s"`
+	// HealthCheck включает клиентскую проверку здоровья (grpc.health.v1.Health/Watch): round_robin
+	// не отправляет запросы на адреса в статусе NOT_SERVING. pick_first проверку не выполняет
+	HealthCheck bool `envconfig:"GRPC_CLIENT_HEALTH_CHECK" default:"false"`
+	// HealthCheckService — имя сервиса в запросе проверки здоровья; пусто — состояние сервера целиком
+	HealthCheckService string `envconfig:"GRPC_CLIENT_HEALTH_CHECK_SERVICE"`
+	// IdleTimeout — время без запросов, после которого ClientConn закрывает соединения и resolver;
+	// следующий запрос подключается заново. 0 — соединения не закрываются
+	IdleTimeout time.Duration `envconfig:"GRPC_CLIENT_IDLE_TIMEOUT" default:"30m"`
+	// TLSCAPath — PEM файл с CA сервера: включает TLS; пусто — соединения без шифрования
+	TLSCAPath string `envconfig:"GRPC_CLIENT_TLS_CA_PATH"`
+	// TLSServerName переопределяет имя сервера при проверке сертификата; пусто — хост из Target
+	TLSServerName string `envconfig:"GRPC_CLIENT_TLS_SERVER_NAME"`
+}
+
+type Option func(*Pool)
+
+// WithDialOption добавляет опцию ClientConn; опции применяются после опций из Config и имеют приоритет
+func WithDialOption(opt grpc.DialOption) Option {
+	return func(p *Pool) {
+		p.dialOpts = append(p.dialOpts, opt)
+	}
+}
+
+// WithUnaryInterceptor добавляет интерцептор унарных вызовов, например middleware.TracingUnaryClientInterceptor
+func WithUnaryInterceptor(interceptor grpc.UnaryClientInterceptor) Option {
+	return func(p *Pool) {
+		p.unaryInterceptors = append(p.unaryInterceptors, interceptor)
+	}
+}
+
+// WithStreamInterceptor добавляет интерцептор потоковых вызовов
+func WithStreamInterceptor(interceptor grpc.StreamClientInterceptor) Option {
+	return func(p *Pool) {
+		p.streamInterceptors = append(p.streamInterceptors, interceptor)
+	}
+}
+
+// Pool — пул ClientConn к одному target. Реализует grpc.ClientConnInterface: сгенерированные
+// клиенты создаются прямо из пула (pb.NewOrdersClient(pool)), вызовы распределяются по ClientConn по кругу
+type Pool struct {
+	logger             *slog.Logger
+	config             Config
+	conns              []*grpc.ClientConn
+	next               atomic.Uint64
+	dialOpts           []grpc.DialOption
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+}
+
+// New создаёт пул из PoolSize ClientConn. Соединения устанавливаются при первом вызове
+func New(c Config, opts ...Option) (*Pool, error) {
+	if err := c.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid gRPC client config")
+	}
+
+	p := &Pool{
+		logger: logger.FromContext(context.Background()).WithGroup("grpcclient"),
+		config: c,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	dialOpts, err := p.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	poolSize := max(c.PoolSize, 1)
+	for range poolSize {
+		conn, err := grpc.NewClient(c.Target, dialOpts...)
+		if err != nil {
+			_ = p.Close()
+			return nil, errors.Wrapf(err, "failed to create gRPC client for %s", c.Target)
+		}
+		p.conns = append(p.conns, conn)
+	}
+
+	p.logger.With("target", c.Target, "pool_size", poolSize, "load_balancing", c.LoadBalancing).Info("gRPC client pool created")
+	return p, nil
+}
+
+// dialOptions собирает опции ClientConn из конфигурации и опций пула
+func (p *Pool) dialOptions() ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if p.config.TLSCAPath != "" {
+		var err error
+		creds, err = credentials.NewClientTLSFromFile(p.config.TLSCAPath, p.config.TLSServerName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load TLS CA file")
+		}
+	}
+
+	serviceConfig, err := p.config.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithIdleTimeout(p.config.IdleTimeout),
+	}
+	if p.config.DNSResolveInterval > 0 {
+		opts = append(opts, grpc.WithResolvers(newReresolveBuilder(resolver.Get("dns"), p.config.DNSResolveInterval)))
+	}
+	if len(p.unaryInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(p.unaryInterceptors...))
+	}
+	if len(p.streamInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainStreamInterceptor(p.streamInterceptors...))
+	}
+	return append(opts, p.dialOpts...), nil
+}
+
+// serviceConfig возвращает service config с политикой балансировки и проверкой здоровья
+func (c Config) serviceConfig() (string, error) {
+	type healthCheckConfig struct {
+		ServiceName string `json:"serviceName"`
+	}
+	policy := c.LoadBalancing
+	if policy == "" {
+		policy = RoundRobin
+	}
+	sc := struct {
+		LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig"`
+		HealthCheckConfig   *healthCheckConfig    `json:"healthCheckConfig,omitempty"`
+	}{
+		LoadBalancingConfig: []map[string]struct{}{{policy: {}}},
+	}
+	if c.HealthCheck {
+		sc.HealthCheckConfig = &healthCheckConfig{ServiceName: c.HealthCheckService}
+	}
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal service config")
+	}
+	return string(data), nil
+}
+
+// Conn возвращает следующий ClientConn пула по кругу
+func (p *Pool) Conn() *grpc.ClientConn {
+	return p.conns[(p.next.Add(1)-1)%uint64(len(p.conns))]
+}
+
+// Conns возвращает все ClientConn пула, например для проверки состояния через GetState
+func (p *Pool) Conns() []*grpc.ClientConn {
+	return p.conns
+}
+
+// Invoke выполняет унарный вызов на следующем ClientConn пула
+func (p *Pool) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return p.Conn().Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream открывает поток на следующем ClientConn пула
+func (p *Pool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.Conn().NewStream(ctx, desc, method, opts...)
+}
+
+// Close закрывает все ClientConn пула; незавершённые вызовы получают ошибку Canceled
+func (p *Pool) Close() error {
+	var firstErr error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(err, "failed to close gRPC client")
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/pure-golang/adapters/env"
+)
+
+// startHealthServer serves the health service on a random local port.
+func startHealthServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+// TestServiceConfig tests the service config built from the load balancing and health check settings.
+func TestServiceConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"default policy", Config{}, `{"loadBalancingConfig":[{"round_robin":{}}]}`},
+		{"pick first", Config{LoadBalancing: PickFirst}, `{"loadBalancingConfig":[{"pick_first":{}}]}`},
+		{
+			"health check",
+			Config{LoadBalancing: RoundRobin, HealthCheck: true, HealthCheckService: "orders.v1.Orders"},
+			`{"loadBalancingConfig":[{"round_robin":{}}],"healthCheckConfig":{"serviceName":"orders.v1.Orders"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.cfg.serviceConfig()
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
+
+// TestConfig_Validate tests validation of the client config.
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, Config{Target: "localhost:50051", PoolSize: 2, LoadBalancing: RoundRobin}.Validate())
+
+	err := Config{
+		PoolSize:           -1,
+		LoadBalancing:      "least_request",
+		DNSResolveInterval: -time.Second,
+		IdleTimeout:        -time.Second,
+		TLSCAPath:          "/nonexistent/ca.pem",
+	}.Validate()
+	var verr *env.ValidationError
+	require.ErrorAs(t, err, &verr)
+	fields := make([]string, 0, len(verr.Fields))
+	for _, fe := range verr.Fields {
+		fields = append(fields, fe.Field)
+	}
+	assert.Equal(t, []string{
+		"GRPC_CLIENT_TARGET",
+		"GRPC_CLIENT_POOL_SIZE",
+		"GRPC_CLIENT_LOAD_BALANCING",
+		"GRPC_CLIENT_DNS_RESOLVE_INTERVAL",
+		"GRPC_CLIENT_IDLE_TIMEOUT",
+		"GRPC_CLIENT_TLS_CA_PATH",
+	}, fields)
+
+	_, err = New(Config{})
+	assert.ErrorContains(t, err, "invalid gRPC client config")
+}
+
+// TestPool tests calls through the pool and rotation of its connections.
+func TestPool(t *testing.T) {
+	t.Parallel()
+	addr := startHealthServer(t)
+
+	var calls int
+	pool, err := New(Config{
+		Target:             addr,
+		PoolSize:           3,
+		LoadBalancing:      RoundRobin,
+		DNSResolveInterval: time.Second,
+		HealthCheck:        true,
+		IdleTimeout:        time.Minute,
+	}, WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		calls++
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}))
+	require.NoError(t, err)
+	require.Len(t, pool.Conns(), 3)
+
+	used := map[*grpc.ClientConn]bool{}
+	for range 3 {
+		used[pool.Conn()] = true
+	}
+	assert.Len(t, used, 3, "Conn must rotate over all connections")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := healthpb.NewHealthClient(pool)
+	for range 3 {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+	assert.Equal(t, 3, calls)
+
+	require.NoError(t, pool.Close())
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Error(t, err, "calls after Close must fail")
+}
@@ -0,0 +1,52 @@
+// Package client создаёт пул gRPC соединений к одному target с балансировкой нагрузки.
+//
+// Поддерживает:
+//   - пул ClientConn ([Pool]) для обхода лимита одновременных стримов на HTTP/2 соединение
+//   - балансировку round_robin и pick_first между адресами target
+//   - периодическое повторное разрешение DNS: новые реплики сервиса получают запросы
+//   - клиентскую проверку здоровья (grpc.health.v1.Health): round_robin исключает адреса в статусе NOT_SERVING
+//   - закрытие соединений, простаивающих дольше IdleTimeout
+//   - TLS с CA сервера
+//
+// Использование:
+//
+//	import grpcclient "github.com/pure-golang/adapters/grpc/client"
+//
+//	pool, err := grpcclient.New(grpcclient.Config{
+//	    Target:      "dns:///orders.prod.svc.cluster.local:50051",
+//	    PoolSize:    4,
+//	    HealthCheck: true,
+//	},
+//	    grpcclient.WithUnaryInterceptor(middleware.TracingUnaryClientInterceptor()),
+//	    grpcclient.WithUnaryInterceptor(middleware.MetricsUnaryClientInterceptor()),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	defer pool.Close()
+//
+//	orders := pb.NewOrdersClient(pool) // Pool реализует grpc.ClientConnInterface
+//
+// Конфигурация через переменные окружения:
+//
+//	GRPC_CLIENT_TARGET          — адрес сервера в синтаксисе gRPC (required)
+//	GRPC_CLIENT_POOL_SIZE       — число ClientConn в пуле (default: 1)
+//	GRPC_CLIENT_LOAD_BALANCING  — политика балансировки: round_robin или pick_first (default: round_robin)
+//	GRPC_CLIENT_DNS_RESOLVE_INTERVAL — период повторного разрешения DNS; 0 — только при потере соединения (default: 30s)
+//	GRPC_CLIENT_HEALTH_CHECK    — клиентская проверка здоровья адресов (default: false)
+//	GRPC_CLIENT_HEALTH_CHECK_SERVICE — имя сервиса в запросе проверки здоровья; пусто — сервер целиком
+//	GRPC_CLIENT_IDLE_TIMEOUT    — закрытие соединений после простоя; 0 — не закрываются (default: 30m)
+//	GRPC_CLIENT_TLS_CA_PATH     — PEM файл с CA сервера: включает TLS
+//	GRPC_CLIENT_TLS_SERVER_NAME — имя сервера при проверке сертификата
+//
+// Особенности:
+//   - Соединения устанавливаются при первом вызове; New проверяет только конфигурацию (Config реализует env.Validatable)
+//   - Вызовы распределяются по ClientConn пула по кругу; внутри ClientConn запросы балансирует политика LoadBalancing
+//   - Повторное разрешение применяется к target со схемой dns (в том числе без схемы, "host:port");
+//     DNS resolver gRPC не разрешает имя чаще раза в 30 секунд
+//   - Проверка здоровья требует сервиса grpc.health.v1.Health на сервере (health.NewServer из
+//     google.golang.org/grpc/health) и не выполняется политикой pick_first
+//   - После IdleTimeout без вызовов ClientConn переходит в IDLE: соединения и resolver закрываются,
+//     следующий вызов подключается заново
+//   - Опции из WithDialOption применяются последними и переопределяют настройки Config
+package client
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// reresolveBuilder оборачивает resolver, периодически запрашивая повторное разрешение имени.
+// DNS resolver gRPC разрешает имя только при создании и потере соединения, поэтому без
+// повторного разрешения round_robin не узнаёт о новых репликах сервиса
+type reresolveBuilder struct {
+	resolver.Builder
+	interval time.Duration
+}
+
+func newReresolveBuilder(builder resolver.Builder, interval time.Duration) *reresolveBuilder {
+	return &reresolveBuilder{Builder: builder, interval: interval}
+}
+
+func (b *reresolveBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	r, err := b.Builder.Build(target, cc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &reresolver{Resolver: r, done: make(chan struct{})}
+	rr.wg.Add(1)
+	go rr.run(b.interval)
+	return rr, nil
+}
+
+type reresolver struct {
+	resolver.Resolver
+	done chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func (r *reresolver) run(interval time.Duration) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.Resolver.ResolveNow(resolver.ResolveNowOptions{})
+		}
+	}
+}
+
+// Close останавливает повторное разрешение и закрывает исходный resolver
+func (r *reresolver) Close() {
+	r.once.Do(func() {
+		close(r.done)
+		r.wg.Wait()
+		r.Resolver.Close()
+	})
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/resolver"
+)
+
+// countingBuilder builds resolvers counting re-resolution requests.
+type countingBuilder struct {
+	resolveNow atomic.Int32
+	closed     atomic.Bool
+}
+
+func (b *countingBuilder) Build(resolver.Target, resolver.ClientConn, resolver.BuildOptions) (resolver.Resolver, error) {
+	return &countingResolver{b: b}, nil
+}
+
+func (b *countingBuilder) Scheme() string { return "dns" }
+
+type countingResolver struct{ b *countingBuilder }
+
+func (r *countingResolver) ResolveNow(resolver.ResolveNowOptions) { r.b.resolveNow.Add(1) }
+func (r *countingResolver) Close()                                { r.b.closed.Store(true) }
+
+// TestReresolveBuilder tests periodic re-resolution and its stop on Close.
+func TestReresolveBuilder(t *testing.T) {
+	t.Parallel()
+	inner := &countingBuilder{}
+	b := newReresolveBuilder(inner, 10*time.Millisecond)
+	assert.Equal(t, "dns", b.Scheme())
+
+	r, err := b.Build(resolver.Target{}, nil, resolver.BuildOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return inner.resolveNow.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	r.Close()
+	r.Close()
+	assert.True(t, inner.closed.Load())
+	count := inner.resolveNow.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, count, inner.resolveNow.Load(), "re-resolution must stop after Close")
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"os"
+
+	"github.com/pure-golang/adapters/env"
+)
+
+var _ env.Validatable = Config{}
+
+// Validate проверяет значения конфигурации: наличие target, размер пула, политику балансировки,
+// неотрицательность интервалов и наличие CA файла. Ошибки всех полей возвращаются разом как *env.ValidationError.
+func (c Config) Validate() error {
+	verr := &env.ValidationError{}
+
+	if c.Target == "" {
+		verr.Add("GRPC_CLIENT_TARGET", "required")
+	}
+	if c.PoolSize < 0 {
+		verr.Add("GRPC_CLIENT_POOL_SIZE", "must not be negative")
+	}
+	if c.LoadBalancing != "" && c.LoadBalancing != RoundRobin && c.LoadBalancing != PickFirst {
+		verr.Add("GRPC_CLIENT_LOAD_BALANCING", "unsupported policy %q: expected %q or %q", c.LoadBalancing, RoundRobin, PickFirst)
+	}
+	if c.DNSResolveInterval < 0 {
+		verr.Add("GRPC_CLIENT_DNS_RESOLVE_INTERVAL", "must not be negative")
+	}
+	if c.IdleTimeout < 0 {
+		verr.Add("GRPC_CLIENT_IDLE_TIMEOUT", "must not be negative")
+	}
+	if c.TLSCAPath != "" {
+		if _, err := os.Stat(c.TLSCAPath); err != nil {
+			verr.Add("GRPC_CLIENT_TLS_CA_PATH", "%v", err)
+		}
+	}
+
+	return verr.Err()
+}
@@ -3,6 +3,7 @@
 // Пакет предоставляет базовые интерфейсы для gRPC компонентов.
 // Реализации находятся в дочерних пакетах:
 //   - [grpc/std] — стандартная реализация gRPC сервера
+//   - [grpc/client] — пул клиентских соединений с балансировкой нагрузки
 //   - [grpc/middleware] — интерцепторы для мониторинга
 //   - [grpc/errors] — утилиты для обработки ошибок
 //
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// WithRetryDelay добавляет к gRPC статусу err деталь google.rpc.RetryInfo: клиенту следует повторить
+// запрос не раньше, чем через delay. Используется с RESOURCE_EXHAUSTED и UNAVAILABLE при перегрузке.
+// Ошибка, не являющаяся статусом, преобразуется в codes.Unknown; nil возвращается как nil
+func WithRetryDelay(err error, delay time.Duration) error {
+	return withDetails(err, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+}
+
+// WithQuotaFailure добавляет к gRPC статусу err деталь google.rpc.QuotaFailure с нарушенной квотой:
+// subject — что ограничено (например, "concurrency:/pkg.Service/Method"), description — почему
+func WithQuotaFailure(err error, subject, description string) error {
+	return withDetails(err, &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{Subject: subject, Description: description}},
+	})
+}
+
+// RetryDelay возвращает задержку из детали google.rpc.RetryInfo gRPC статуса err;
+// false — статус без RetryInfo
+func RetryDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// QuotaViolations возвращает нарушения квот из детали google.rpc.QuotaFailure gRPC статуса err
+func QuotaViolations(err error) []*errdetails.QuotaFailure_Violation {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	var violations []*errdetails.QuotaFailure_Violation
+	for _, detail := range st.Details() {
+		if failure, ok := detail.(*errdetails.QuotaFailure); ok {
+			violations = append(violations, failure.GetViolations()...)
+		}
+	}
+	return violations
+}
+
+// withDetails добавляет деталь к статусу err; если деталь не сериализуется, err возвращается без изменений
+func withDetails(err error, detail protoadapt.MessageV1) error {
+	if err == nil {
+		return nil
+	}
+	st, detailErr := status.Convert(err).WithDetails(detail)
+	if detailErr != nil {
+		return err
+	}
+	return st.Err()
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRetryDetails tests attaching and reading RetryInfo and QuotaFailure details.
+func TestRetryDetails(t *testing.T) {
+	t.Parallel()
+	err := status.Error(codes.Unavailable, "shedding load")
+	err = WithQuotaFailure(err, "requests:tenant-1", "100 requests per second")
+	err = WithRetryDelay(err, 2*time.Second)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "shedding load", st.Message())
+	assert.Len(t, st.Details(), 2, "details are accumulated")
+
+	delay, ok := RetryDelay(err)
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+	violations := QuotaViolations(err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "requests:tenant-1", violations[0].GetSubject())
+
+	_, ok = RetryDelay(status.Error(codes.Unavailable, "no details"))
+	assert.False(t, ok)
+	_, ok = RetryDelay(errors.New("plain error"))
+	assert.False(t, ok)
+	assert.Nil(t, QuotaViolations(errors.New("plain error")))
+	assert.NoError(t, WithRetryDelay(nil, time.Second))
+	assert.Equal(t, codes.Unknown, status.Code(WithRetryDelay(errors.New("plain error"), time.Second)))
+}
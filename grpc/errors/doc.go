@@ -20,6 +20,42 @@
 //   - [FromError] — преобразует error в gRPC статус
 //   - [WrapError] — оборачивает ошибку с gRPC кодом
 //   - [NewError] — создаёт новую ошибку с gRPC кодом
+//   - [Mapper] — преобразует доменные ошибки в gRPC статусы по зарегистрированным правилам
+//   - [WithRetryDelay], [WithQuotaFailure] — добавляют к статусу детали google.rpc.RetryInfo и QuotaFailure
+//   - [RetryDelay], [QuotaViolations] — читают эти детали на клиенте
+//
+// Подсказки повтора при перегрузке (RESOURCE_EXHAUSTED, UNAVAILABLE):
+//
+//	// сервер
+//	err := status.Error(codes.ResourceExhausted, "rate limit exceeded")
+//	err = grpcerrors.WithQuotaFailure(err, "requests:"+tenantID, "100 requests per second")
+//	err = grpcerrors.WithRetryDelay(err, 500*time.Millisecond)
+//
+//	// клиент
+//	if delay, ok := grpcerrors.RetryDelay(err); ok {
+//	    time.Sleep(delay)
+//	}
+//
+// Маппинг доменных ошибок:
+//
+//	mapper := grpcerrors.NewDefaultMapper() // PostgreSQL + storage
+//	mapper.Register(grpcerrors.Mapping{
+//	    Match:   grpcerrors.Is(domain.ErrUserNotFound),
+//	    Code:    codes.NotFound,
+//	    Message: "user not found",
+//	})
+//	err := mapper.Map(err)
+//
+// Matcher'ы: [Is], [As], [SQLState] (lib/pq и pgx), [StorageCode].
+// Встроенные правила ([DefaultMappings]):
+//   - 23505 unique_violation → codes.AlreadyExists
+//   - 23503 foreign_key_violation → codes.FailedPrecondition
+//   - 23514 check_violation, 23502 not_null_violation → codes.InvalidArgument
+//   - storage NotFound, BucketNotFound → codes.NotFound
+//   - storage AccessDenied → codes.PermissionDenied
+//
+// Неизвестные ошибки Mapper преобразует в codes.Internal с сообщением "internal error",
+// не раскрывая текст исходной ошибки клиенту.
 //
 // Маппинг стандартных ошибок:
 //   - context.Canceled → codes.Canceled
@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// Коды ошибок PostgreSQL (SQLSTATE) для нарушений ограничений
+const (
+	SQLStateUniqueViolation     = "23505"
+	SQLStateForeignKeyViolation = "23503"
+	SQLStateCheckViolation      = "23514"
+	SQLStateNotNullViolation    = "23502"
+)
+
+// internalErrorMessage — безопасное сообщение для неизвестных ошибок
+const internalErrorMessage = "internal error"
+
+// Matcher проверяет, подходит ли ошибка под правило маппинга
+type Matcher func(err error) bool
+
+// Mapping описывает правило преобразования доменной ошибки в gRPC статус.
+// Message — безопасное сообщение, которое уходит клиенту вместо текста исходной ошибки;
+// если не задано, используется строковое представление кода.
+type Mapping struct {
+	Match   Matcher
+	Code    codes.Code
+	Message string
+}
+
+// Mapper преобразует ошибки в gRPC статусы по зарегистрированным правилам.
+// Правила проверяются в порядке регистрации, срабатывает первое подходящее.
+// Потокобезопасен.
+type Mapper struct {
+	mu       sync.RWMutex
+	mappings []Mapping
+}
+
+// NewMapper создаёт Mapper с заданными правилами
+func NewMapper(mappings ...Mapping) *Mapper {
+	return &Mapper{
+		mappings: slices.Clone(mappings),
+	}
+}
+
+// NewDefaultMapper создаёт Mapper со встроенными правилами из DefaultMappings
+func NewDefaultMapper() *Mapper {
+	return NewMapper(DefaultMappings()...)
+}
+
+// Register добавляет правила в конец списка
+func (m *Mapper) Register(mappings ...Mapping) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mappings = append(m.mappings, mappings...)
+}
+
+// Map преобразует ошибку в gRPC статус:
+//   - nil возвращается как nil
+//   - ошибки, уже являющиеся gRPC статусом, возвращаются как есть
+//   - context.Canceled и context.DeadlineExceeded → Canceled и DeadlineExceeded
+//   - первая подходящая зарегистрированная ошибка → её код и безопасное сообщение
+//   - прочие → codes.Internal без раскрытия текста исходной ошибки
+func (m *Mapper) Map(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, mapping := range m.mappings {
+		if mapping.Match == nil || !mapping.Match(err) {
+			continue
+		}
+		msg := mapping.Message
+		if msg == "" {
+			msg = mapping.Code.String()
+		}
+		return status.Error(mapping.Code, msg)
+	}
+
+	return status.Error(codes.Internal, internalErrorMessage)
+}
+
+// Is создаёт Matcher, срабатывающий при errors.Is(err, target)
+func Is(target error) Matcher {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// As создаёт Matcher, срабатывающий, если в цепочке ошибок есть ошибка типа T
+func As[T error]() Matcher {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+// sqlStateError реализуется ошибками драйверов PostgreSQL (*pq.Error, *pgconn.PgError)
+type sqlStateError interface {
+	SQLState() string
+}
+
+// SQLState создаёт Matcher для ошибок PostgreSQL с одним из заданных кодов SQLSTATE.
+// Работает с ошибками lib/pq и pgx без зависимости от конкретного драйвера.
+func SQLState(states ...string) Matcher {
+	return func(err error) bool {
+		var pgErr sqlStateError
+		if !errors.As(err, &pgErr) {
+			return false
+		}
+		return slices.Contains(states, pgErr.SQLState())
+	}
+}
+
+// StorageCode создаёт Matcher для *storage.StorageError с одним из заданных кодов
+func StorageCode(storageCodes ...storage.ErrorCode) Matcher {
+	return func(err error) bool {
+		var storageErr *storage.StorageError
+		if !errors.As(err, &storageErr) {
+			return false
+		}
+		return slices.Contains(storageCodes, storageErr.Code)
+	}
+}
+
+// PostgresMappings возвращает правила для нарушений ограничений PostgreSQL
+func PostgresMappings() []Mapping {
+	return []Mapping{
+		{Match: SQLState(SQLStateUniqueViolation), Code: codes.AlreadyExists, Message: "resource already exists"},
+		{Match: SQLState(SQLStateForeignKeyViolation), Code: codes.FailedPrecondition, Message: "related resource constraint violated"},
+		{Match: SQLState(SQLStateCheckViolation), Code: codes.InvalidArgument, Message: "invalid argument"},
+		{Match: SQLState(SQLStateNotNullViolation), Code: codes.InvalidArgument, Message: "required field is missing"},
+	}
+}
+
+// StorageMappings возвращает правила для ошибок пакета storage
+func StorageMappings() []Mapping {
+	return []Mapping{
+		{Match: storage.IsNotFound, Code: codes.NotFound, Message: "object not found"},
+		{Match: storage.IsBucketNotFound, Code: codes.NotFound, Message: "bucket not found"},
+		{Match: storage.IsAccessDenied, Code: codes.PermissionDenied, Message: "access denied"},
+		{Match: storage.IsQuotaExceeded, Code: codes.ResourceExhausted, Message: "quota exceeded"},
+		{Match: storage.IsObjectLockNotEnabled, Code: codes.FailedPrecondition, Message: "object lock not enabled"},
+		{Match: StorageCode(storage.CodeInternalError), Code: codes.Internal, Message: internalErrorMessage},
+	}
+}
+
+// DefaultMappings возвращает встроенные правила: PostgresMappings и StorageMappings
+func DefaultMappings() []Mapping {
+	return append(PostgresMappings(), StorageMappings()...)
+}
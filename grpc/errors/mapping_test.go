@@ -0,0 +1,173 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var errDomainNotFound = errors.New("user not found")
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid field " + e.field
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, msg string) {
+	t.Helper()
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok, "error should be a gRPC status")
+	assert.Equal(t, code, st.Code())
+	assert.Equal(t, msg, st.Message())
+}
+
+func TestMapper_Nil(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, NewDefaultMapper().Map(nil))
+}
+
+func TestMapper_StatusPassthrough(t *testing.T) {
+	t.Parallel()
+	original := status.Error(codes.Unauthenticated, "no token")
+
+	err := NewDefaultMapper().Map(original)
+
+	assert.Equal(t, original, err)
+}
+
+func TestMapper_ContextErrors(t *testing.T) {
+	t.Parallel()
+	m := NewMapper()
+
+	requireStatus(t, m.Map(fmt.Errorf("query: %w", context.Canceled)), codes.Canceled, "request canceled")
+	requireStatus(t, m.Map(fmt.Errorf("query: %w", context.DeadlineExceeded)), codes.DeadlineExceeded, "deadline exceeded")
+}
+
+func TestMapper_UnknownErrorDoesNotLeak(t *testing.T) {
+	t.Parallel()
+
+	err := NewMapper().Map(errors.New("pq: password authentication failed for user admin"))
+
+	requireStatus(t, err, codes.Internal, "internal error")
+}
+
+func TestMapper_IsMatcher(t *testing.T) {
+	t.Parallel()
+	m := NewMapper(Mapping{Match: Is(errDomainNotFound), Code: codes.NotFound, Message: "user not found"})
+
+	err := m.Map(fmt.Errorf("get user 42: %w", errDomainNotFound))
+
+	requireStatus(t, err, codes.NotFound, "user not found")
+}
+
+func TestMapper_AsMatcher(t *testing.T) {
+	t.Parallel()
+	m := NewMapper(Mapping{Match: As[*validationError](), Code: codes.InvalidArgument})
+
+	err := m.Map(fmt.Errorf("create: %w", &validationError{field: "email"}))
+
+	requireStatus(t, err, codes.InvalidArgument, codes.InvalidArgument.String())
+}
+
+func TestMapper_FirstMatchWins(t *testing.T) {
+	t.Parallel()
+	m := NewMapper(
+		Mapping{Match: Is(errDomainNotFound), Code: codes.NotFound, Message: "first"},
+		Mapping{Match: Is(errDomainNotFound), Code: codes.Internal, Message: "second"},
+	)
+
+	requireStatus(t, m.Map(errDomainNotFound), codes.NotFound, "first")
+}
+
+func TestMapper_Register(t *testing.T) {
+	t.Parallel()
+	m := NewMapper()
+	requireStatus(t, m.Map(errDomainNotFound), codes.Internal, "internal error")
+
+	m.Register(Mapping{Match: Is(errDomainNotFound), Code: codes.NotFound, Message: "user not found"})
+
+	requireStatus(t, m.Map(errDomainNotFound), codes.NotFound, "user not found")
+}
+
+func TestMapper_NilMatcherSkipped(t *testing.T) {
+	t.Parallel()
+	m := NewMapper(Mapping{Code: codes.NotFound})
+
+	requireStatus(t, m.Map(errDomainNotFound), codes.Internal, "internal error")
+}
+
+func TestMapper_PostgresMappings(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"pq unique", &pq.Error{Code: SQLStateUniqueViolation}, codes.AlreadyExists, "resource already exists"},
+		{"pgx unique", &pgconn.PgError{Code: SQLStateUniqueViolation}, codes.AlreadyExists, "resource already exists"},
+		{"pgx foreign key", &pgconn.PgError{Code: SQLStateForeignKeyViolation}, codes.FailedPrecondition, "related resource constraint violated"},
+		{"pq check", &pq.Error{Code: SQLStateCheckViolation}, codes.InvalidArgument, "invalid argument"},
+		{"pq not null", &pq.Error{Code: SQLStateNotNullViolation}, codes.InvalidArgument, "required field is missing"},
+		{"wrapped", fmt.Errorf("insert: %w", &pgconn.PgError{Code: SQLStateUniqueViolation}), codes.AlreadyExists, "resource already exists"},
+		{"other sqlstate", &pgconn.PgError{Code: "42P01", Message: "relation \"users\" does not exist"}, codes.Internal, "internal error"},
+	}
+
+	m := NewDefaultMapper()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			requireStatus(t, m.Map(tc.err), tc.code, tc.msg)
+		})
+	}
+}
+
+func TestMapper_StorageMappings(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"not found", &storage.StorageError{Code: storage.CodeNotFound, Bucket: "b", Key: "k"}, codes.NotFound, "object not found"},
+		{"sentinel not found", fmt.Errorf("get: %w", storage.ErrNotFound), codes.NotFound, "object not found"},
+		{"bucket not found", &storage.StorageError{Code: storage.CodeBucketNotFound}, codes.NotFound, "bucket not found"},
+		{"access denied", &storage.StorageError{Code: storage.CodeAccessDenied}, codes.PermissionDenied, "access denied"},
+		{"quota exceeded", &storage.StorageError{Code: storage.CodeQuotaExceeded, Bucket: "b", Key: "tenant-1/a.txt"}, codes.ResourceExhausted, "quota exceeded"},
+		{"object lock not enabled", &storage.StorageError{Code: storage.CodeObjectLockNotEnabled}, codes.FailedPrecondition, "object lock not enabled"},
+		{"internal", &storage.StorageError{Code: storage.CodeInternalError, Message: "secret details"}, codes.Internal, "internal error"},
+	}
+
+	m := NewDefaultMapper()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			requireStatus(t, m.Map(tc.err), tc.code, tc.msg)
+		})
+	}
+}
+
+func TestStorageCode_NonStorageError(t *testing.T) {
+	t.Parallel()
+	assert.False(t, StorageCode(storage.CodeNotFound)(errors.New("plain")))
+}
+
+func TestSQLState_NonPostgresError(t *testing.T) {
+	t.Parallel()
+	assert.False(t, SQLState(SQLStateUniqueViolation)(errors.New("plain")))
+}
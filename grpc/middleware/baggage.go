@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Ключи W3C Baggage для бизнес-идентификаторов
+const (
+	BaggageTenantID = "tenant-id"
+	BaggageUserID   = "user-id"
+)
+
+// baggageAttributePrefix — префикс атрибутов span'а, скопированных из baggage
+const baggageAttributePrefix = "baggage."
+
+// TracingOption настраивает TracingUnaryInterceptor и TracingStreamInterceptor
+type TracingOption func(*tracingConfig)
+
+type tracingConfig struct {
+	spanAttributes []string
+	logFields      []string
+}
+
+func newTracingConfig(opts []TracingOption) *tracingConfig {
+	cfg := &tracingConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithBaggageSpanAttributes копирует перечисленные элементы baggage в атрибуты span'а
+// с префиксом "baggage." (например, baggage.tenant-id). Остальные элементы не копируются:
+// baggage приходит от клиента и может содержать персональные данные.
+func WithBaggageSpanAttributes(keys ...string) TracingOption {
+	return func(c *tracingConfig) {
+		c.spanAttributes = append(c.spanAttributes, keys...)
+	}
+}
+
+// WithBaggageLogFields добавляет перечисленные элементы baggage в поля логгера контекста
+// (logger.FromContext) и в записи LoggingInterceptor и LoggingStreamInterceptor
+func WithBaggageLogFields(keys ...string) TracingOption {
+	return func(c *tracingConfig) {
+		c.logFields = append(c.logFields, keys...)
+	}
+}
+
+// BaggageValue возвращает значение элемента baggage из контекста; пустая строка — элемент отсутствует
+func BaggageValue(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// ContextWithBaggage добавляет элемент baggage в контекст.
+// TracingUnaryClientInterceptor и TracingStreamClientInterceptor передают его в исходящих вызовах.
+func ContextWithBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, errors.Wrapf(err, "invalid baggage member %q", key)
+	}
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, errors.Wrapf(err, "failed to set baggage member %q", key)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// extractPropagation извлекает контекст трассировки и baggage из входящих метаданных.
+// Baggage извлекается, даже если глобальный пропагатор его не поддерживает.
+func extractPropagation(ctx context.Context, md metadata.MD) context.Context {
+	supplier := metadataSupplier{metadata: &md}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, supplier)
+	if baggage.FromContext(ctx).Len() == 0 {
+		ctx = propagation.Baggage{}.Extract(ctx, supplier)
+	}
+	return ctx
+}
+
+// baggageLogFieldsKey — ключ контекста для полей лога из baggage
+type baggageLogFieldsKey struct{}
+
+// enrich копирует разрешённые элементы baggage в атрибуты span'а и поля лога
+func (c *tracingConfig) enrich(ctx context.Context, span trace.Span) context.Context {
+	if len(c.spanAttributes) == 0 && len(c.logFields) == 0 {
+		return ctx
+	}
+	b := baggage.FromContext(ctx)
+
+	for _, key := range c.spanAttributes {
+		if member := b.Member(key); member.Key() != "" {
+			span.SetAttributes(attribute.String(baggageAttributePrefix+key, member.Value()))
+		}
+	}
+
+	var fields []any
+	for _, key := range c.logFields {
+		if member := b.Member(key); member.Key() != "" {
+			fields = append(fields, slog.String(key, member.Value()))
+		}
+	}
+	if len(fields) == 0 {
+		return ctx
+	}
+	ctx = logger.NewContext(ctx, logger.FromContext(ctx).With(fields...))
+	return context.WithValue(ctx, baggageLogFieldsKey{}, fields)
+}
+
+// baggageLogFields возвращает поля лога, добавленные WithBaggageLogFields
+func baggageLogFields(ctx context.Context) []any {
+	fields, _ := ctx.Value(baggageLogFieldsKey{}).([]any)
+	return fields
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// fakeServerStream is a grpc.ServerStream with a fixed context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// TestTracingUnaryInterceptor_Baggage tests that allowlisted baggage members reach the context, span and logs.
+func TestTracingUnaryInterceptor_Baggage(t *testing.T) {
+	exporter := &testSpanExporter{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	// baggage is extracted even if the global propagator does not support it
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(MetadataTextMapPropagator())
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	tracing := TracingUnaryInterceptor(
+		WithBaggageSpanAttributes(BaggageTenantID),
+		WithBaggageLogFields(BaggageTenantID, BaggageUserID),
+	)
+	logging := LoggingInterceptor(log)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"baggage", "tenant-id=acme,user-id=42,email=a%40b.c",
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	_, err := tracing(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		assert.Equal(t, "acme", BaggageValue(ctx, BaggageTenantID))
+		assert.Equal(t, "42", BaggageValue(ctx, BaggageUserID))
+		logger.FromContext(ctx).InfoContext(ctx, "handled")
+		return logging(ctx, req, info, func(context.Context, any) (any, error) { return "ok", nil })
+	})
+	require.NoError(t, err)
+
+	require.Len(t, exporter.spans, 1)
+	attrs := exporter.spans[0].attributes
+	assert.Equal(t, "acme", attrs["baggage.tenant-id"])
+	assert.NotContains(t, attrs, "baggage.user-id")
+	assert.NotContains(t, attrs, "baggage.email")
+
+	output := buf.String()
+	assert.Contains(t, output, "msg=\"gRPC request processed\"")
+	assert.Contains(t, output, "tenant-id=acme user-id=42")
+	assert.NotContains(t, output, "email")
+}
+
+// TestTracingStreamInterceptor_Baggage tests that baggage members reach the stream context.
+func TestTracingStreamInterceptor_Baggage(t *testing.T) {
+	interceptor := TracingStreamInterceptor(WithBaggageLogFields(BaggageTenantID))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("baggage", "tenant-id=acme"))
+
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(_ any, ss grpc.ServerStream) error {
+			assert.Equal(t, "acme", BaggageValue(ss.Context(), BaggageTenantID))
+			assert.Len(t, baggageLogFields(ss.Context()), 1)
+			return nil
+		})
+	require.NoError(t, err)
+}
+
+// TestContextWithBaggage tests that baggage members set by the caller are injected into outgoing metadata.
+func TestContextWithBaggage(t *testing.T) {
+	t.Parallel()
+	ctx, err := ContextWithBaggage(context.Background(), BaggageTenantID, "acme")
+	require.NoError(t, err)
+	ctx, err = ContextWithBaggage(ctx, BaggageUserID, "42")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", BaggageValue(ctx, BaggageTenantID))
+
+	ctx, span := startClientSpan(ctx, "/test.Service/Method", nil)
+	defer span.End()
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	require.Len(t, md.Get("baggage"), 1)
+	assert.ElementsMatch(t, []string{"tenant-id=acme", "user-id=42"}, strings.Split(md.Get("baggage")[0], ","))
+
+	_, err = ContextWithBaggage(context.Background(), "", "v")
+	assert.Error(t, err)
+}
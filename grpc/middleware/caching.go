@@ -0,0 +1,293 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/pure-golang/adapters/kv"
+	"github.com/pure-golang/adapters/logger"
+)
+
+const (
+	// CachedResponseHeader — заголовок ответа, выставляемый при возврате ответа из кэша
+	CachedResponseHeader = "cached-response"
+
+	cacheKeyPrefix = "grpc:cache:"
+)
+
+// CacheStore хранит сериализованные ответы для CachingUnaryInterceptor.
+// Интерфейс совпадает с IdempotencyStore: MemoryIdempotencyStore подходит как кэш в памяти процесса.
+type CacheStore interface {
+	// Get возвращает сохранённое значение; ok == false, если ключа нет или он истёк
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set сохраняет значение на ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheMethod задаёт кэширование ответов метода
+type CacheMethod struct {
+	// TTL — время жизни ответа в кэше; 0 — ответы метода не кэшируются
+	TTL time.Duration
+	// MetadataKeys — ключи входящих метаданных, от которых зависит ответ (например, accept-language);
+	// их значения входят в ключ кэша вместе с телом запроса
+	MetadataKeys []string
+}
+
+// CachingOption настраивает CachingUnaryInterceptor
+type CachingOption func(*cachingConfig)
+
+// WithCachedMethod включает кэширование ответов метода (полное имя "/pkg.Service/Method").
+// Кэшировать можно только методы, не изменяющие данные.
+func WithCachedMethod(method string, cfg CacheMethod) CachingOption {
+	return func(c *cachingConfig) {
+		c.methods[method] = cfg
+	}
+}
+
+// WithCacheScope задаёт функцию, возвращающую область действия кэша (например, ID пользователя),
+// чтобы ответы, зависящие от вызывающего, не попадали другим клиентам
+func WithCacheScope(scope func(ctx context.Context) string) CachingOption {
+	return func(c *cachingConfig) {
+		c.scope = scope
+	}
+}
+
+// WithCacheMeterProvider задаёт MeterProvider для метрик кэша
+// вместо глобального otel.GetMeterProvider()
+func WithCacheMeterProvider(provider metric.MeterProvider) CachingOption {
+	return func(c *cachingConfig) {
+		c.meterProvider = provider
+	}
+}
+
+type cachingConfig struct {
+	methods       map[string]CacheMethod
+	scope         func(ctx context.Context) string
+	meterProvider metric.MeterProvider
+}
+
+// CachingUnaryInterceptor создает интерцептор, возвращающий сохранённые ответы методов,
+// перечисленных через WithCachedMethod, без вызова обработчика.
+//
+// Ключ кэша — метод, область (WithCacheScope), значения CacheMethod.MetadataKeys и SHA-256
+// детерминированной сериализации запроса. Успешный ответ сохраняется в store на CacheMethod.TTL;
+// ответ из кэша получает заголовок cached-response: true. Ошибки обработчика не кэшируются.
+// Одновременные промахи по одному ключу в пределах процесса вызывают обработчик один раз.
+// Ошибка store не прерывает запрос: он обрабатывается как промах.
+// Остальные методы и запросы с не-protobuf сообщениями обрабатываются как обычно.
+//
+// Метрики:
+//   - grpc.server.cache.hits_total — ответы из кэша (атрибут grpc.method)
+//   - grpc.server.cache.misses_total — вызовы обработчика для кэшируемых методов (атрибут grpc.method)
+func CachingUnaryInterceptor(store CacheStore, opts ...CachingOption) grpc.UnaryServerInterceptor {
+	cfg := &cachingConfig{methods: make(map[string]CacheMethod)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	m := meter
+	if cfg.meterProvider != nil {
+		m = cfg.meterProvider.Meter("github.com/pure-golang/adapters/grpc")
+	}
+	hits, misses := newCacheInstruments(m)
+
+	var inflight keyedMutex
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method, ok := cfg.methods[info.FullMethod]
+		if !ok || method.TTL <= 0 {
+			return handler(ctx, req)
+		}
+		reqMsg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		key, err := cacheKey(ctx, info.FullMethod, cfg.scope, method.MetadataKeys, reqMsg)
+		if err != nil {
+			return handler(ctx, req)
+		}
+		attrs := metric.WithAttributes(attribute.String("grpc.method", info.FullMethod))
+
+		if resp, ok := lookupCachedResponse(ctx, store, key); ok {
+			hits.Add(ctx, 1, attrs)
+			return resp, nil
+		}
+
+		unlock, err := inflight.Lock(ctx, key)
+		if err != nil {
+			return handler(ctx, req)
+		}
+		defer unlock()
+
+		// Ответ мог сохранить запрос, которого ждали на блокировке
+		if resp, ok := lookupCachedResponse(ctx, store, key); ok {
+			hits.Add(ctx, 1, attrs)
+			return resp, nil
+		}
+
+		misses.Add(ctx, 1, attrs)
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		if respMsg, ok := resp.(proto.Message); ok {
+			saveCachedResponse(ctx, store, key, info.FullMethod, respMsg, method.TTL)
+		}
+
+		return resp, nil
+	}
+}
+
+// saveCachedResponse сохраняет ответ в кэш. Ошибка не прерывает уже выполненный запрос,
+// но логируется и записывается в span.
+func saveCachedResponse(ctx context.Context, store CacheStore, key, method string, resp proto.Message, ttl time.Duration) {
+	record, err := encodeCachedResponse(resp)
+	if err == nil {
+		err = store.Set(ctx, key, record, ttl)
+	}
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		logger.FromContext(ctx).Warn("failed to store cached response",
+			slog.String("method", method),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// newCacheInstruments создаёт счётчики попаданий и промахов.
+// Ошибка создания передаётся в otel.Handle, интерцептор работает без метрик.
+func newCacheInstruments(m metric.Meter) (hits, misses metric.Int64Counter) {
+	var err error
+	hits, err = m.Int64Counter(
+		"grpc.server.cache.hits_total",
+		metric.WithDescription("Total number of gRPC responses served from the cache"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create cache hits counter"))
+		hits = noop.Int64Counter{}
+	}
+	misses, err = m.Int64Counter(
+		"grpc.server.cache.misses_total",
+		metric.WithDescription("Total number of cacheable gRPC requests passed to the handler"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create cache misses counter"))
+		misses = noop.Int64Counter{}
+	}
+	return hits, misses
+}
+
+// lookupCachedResponse возвращает ответ из кэша; ошибки store и повреждённые записи считаются промахом
+func lookupCachedResponse(ctx context.Context, store CacheStore, key string) (any, bool) {
+	stored, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false
+	}
+	resp, err := decodeCachedResponse(stored)
+	if err != nil {
+		return nil, false
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(CachedResponseHeader, "true"))
+	return resp, true
+}
+
+// cacheKey возвращает ключ кэша: префикс, метод и SHA-256 области, метаданных и тела запроса
+func cacheKey(ctx context.Context, method string, scope func(ctx context.Context) string, mdKeys []string, req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if scope != nil {
+		writeCacheKeyPart(h, scope(ctx))
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, k := range mdKeys {
+		values := md.Get(k)
+		writeCacheKeyPart(h, k)
+		writeCacheKeyPart(h, strconv.Itoa(len(values)))
+		for _, v := range values {
+			writeCacheKeyPart(h, v)
+		}
+	}
+	writeCacheKeyPart(h, string(data))
+
+	return cacheKeyPrefix + method + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeCacheKeyPart пишет в хэш значение с префиксом длины, чтобы границы частей не смешивались
+func writeCacheKeyPart(h hash.Hash, s string) {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(s)))
+	_, _ = h.Write(size[:])
+	_, _ = h.Write([]byte(s))
+}
+
+// encodeCachedResponse сериализует ответ как Any
+func encodeCachedResponse(resp proto.Message) ([]byte, error) {
+	packed, err := anypb.New(resp)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(packed)
+}
+
+// decodeCachedResponse разбирает запись, созданную encodeCachedResponse
+func decodeCachedResponse(record []byte) (proto.Message, error) {
+	var packed anypb.Any
+	if err := proto.Unmarshal(record, &packed); err != nil {
+		return nil, err
+	}
+	return packed.UnmarshalNew()
+}
+
+// kvCacheStore адаптирует kv.Store к CacheStore
+type kvCacheStore struct {
+	store kv.Store
+}
+
+// NewKVCacheStore создаёт CacheStore поверх kv.Store (например, kv/redis)
+func NewKVCacheStore(store kv.Store) CacheStore {
+	return &kvCacheStore{store: store}
+}
+
+func (s *kvCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.store.Get(ctx, key)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get cached response")
+	}
+	if value == "" {
+		return nil, false, nil
+	}
+	return []byte(value), true, nil
+}
+
+func (s *kvCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.store.Set(ctx, key, value, ttl); err != nil {
+		return errors.Wrap(err, "failed to set cached response")
+	}
+	return nil
+}
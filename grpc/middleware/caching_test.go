@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/pure-golang/adapters/kv"
+	kvnoop "github.com/pure-golang/adapters/kv/noop"
+	"github.com/pure-golang/adapters/logger"
+)
+
+var cachingInfo = &grpc.UnaryServerInfo{FullMethod: "/test.Catalog/GetItem"}
+
+// cacheCounters returns grpc.server.cache.* counter values by metric name
+func cacheCounters(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				result[m.Name] += dp.Value
+			}
+		}
+	}
+	return result
+}
+
+// TestCachingUnaryInterceptor_ServesCachedResponse tests that repeated requests are served from the cache
+func TestCachingUnaryInterceptor_ServesCachedResponse(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	interceptor := CachingUnaryInterceptor(NewMemoryIdempotencyStore(),
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute}),
+		WithCacheMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+
+	first, err := interceptor(context.Background(), wrapperspb.String("item"), cachingInfo, handler)
+	require.NoError(t, err)
+	second, err := interceptor(context.Background(), wrapperspb.String("item"), cachingInfo, handler)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.True(t, proto.Equal(first.(proto.Message), second.(proto.Message)))
+
+	_, err = interceptor(context.Background(), wrapperspb.String("other"), cachingInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "a different request must reach the handler")
+
+	assert.Equal(t, map[string]int64{
+		"grpc.server.cache.hits_total":   1,
+		"grpc.server.cache.misses_total": 2,
+	}, cacheCounters(t, reader))
+}
+
+// TestCachingUnaryInterceptor_UncachedMethod tests that methods without configuration are not cached
+func TestCachingUnaryInterceptor_UncachedMethod(t *testing.T) {
+	t.Parallel()
+	interceptor := CachingUnaryInterceptor(NewMemoryIdempotencyStore(),
+		WithCachedMethod("/test.Catalog/ListItems", CacheMethod{TTL: time.Minute}))
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+
+	for range 2 {
+		_, err := interceptor(context.Background(), wrapperspb.String("item"), cachingInfo, handler)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestCachingUnaryInterceptor_MetadataKeys tests that selected metadata is part of the cache key
+func TestCachingUnaryInterceptor_MetadataKeys(t *testing.T) {
+	t.Parallel()
+	interceptor := CachingUnaryInterceptor(NewMemoryIdempotencyStore(),
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute, MetadataKeys: []string{"accept-language"}}))
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+	withLanguage := func(lang, requestID string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", lang, "x-request-id", requestID))
+	}
+
+	_, err := interceptor(withLanguage("en", "1"), wrapperspb.String("item"), cachingInfo, handler)
+	require.NoError(t, err)
+	_, err = interceptor(withLanguage("en", "2"), wrapperspb.String("item"), cachingInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "unselected metadata must not affect the key")
+
+	_, err = interceptor(withLanguage("ru", "3"), wrapperspb.String("item"), cachingInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestCachingUnaryInterceptor_Scope tests that scopes isolate equal requests
+func TestCachingUnaryInterceptor_Scope(t *testing.T) {
+	t.Parallel()
+	type userKey struct{}
+	interceptor := CachingUnaryInterceptor(NewMemoryIdempotencyStore(),
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute}),
+		WithCacheScope(func(ctx context.Context) string {
+			user, _ := ctx.Value(userKey{}).(string)
+			return user
+		}))
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+
+	for _, user := range []string{"alice", "bob", "alice"} {
+		ctx := context.WithValue(context.Background(), userKey{}, user)
+		_, err := interceptor(ctx, wrapperspb.String("item"), cachingInfo, handler)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestCachingUnaryInterceptor_ErrorsNotCached tests that failed calls are not cached
+func TestCachingUnaryInterceptor_ErrorsNotCached(t *testing.T) {
+	t.Parallel()
+	interceptor := CachingUnaryInterceptor(NewMemoryIdempotencyStore(),
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute}))
+	var calls atomic.Int32
+	handler := func(context.Context, any) (any, error) {
+		calls.Add(1)
+		return nil, errors.New("backend unavailable")
+	}
+
+	for range 2 {
+		_, err := interceptor(context.Background(), wrapperspb.String("item"), cachingInfo, handler)
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestCachingUnaryInterceptor_StoreError tests that store failures fall back to the handler
+func TestCachingUnaryInterceptor_StoreError(t *testing.T) {
+	t.Parallel()
+	interceptor := CachingUnaryInterceptor(failingIdempotencyStore{},
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute}))
+	var calls atomic.Int32
+
+	resp, err := interceptor(context.Background(), wrapperspb.String("item"), cachingInfo, countingHandler(&calls))
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// TestCachingUnaryInterceptor_SetError tests that a failed cache write keeps the response and is logged
+func TestCachingUnaryInterceptor_SetError(t *testing.T) {
+	t.Parallel()
+	interceptor := CachingUnaryInterceptor(unwritableIdempotencyStore{},
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute}))
+	var buf bytes.Buffer
+	ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	var calls atomic.Int32
+
+	resp, err := interceptor(ctx, wrapperspb.String("item"), cachingInfo, countingHandler(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, "item-1", resp.(*wrapperspb.StringValue).GetValue())
+	assert.Contains(t, buf.String(), `level=WARN msg="failed to store cached response" method=/test.Catalog/GetItem error="connection refused"`)
+}
+
+// TestCachingUnaryInterceptor_ConcurrentMisses tests that concurrent misses run the handler once
+func TestCachingUnaryInterceptor_ConcurrentMisses(t *testing.T) {
+	t.Parallel()
+	interceptor := CachingUnaryInterceptor(NewMemoryIdempotencyStore(),
+		WithCachedMethod(cachingInfo.FullMethod, CacheMethod{TTL: time.Minute}))
+	var calls atomic.Int32
+	handler := func(ctx context.Context, req any) (any, error) {
+		time.Sleep(20 * time.Millisecond)
+		return countingHandler(&calls)(ctx, req)
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := interceptor(context.Background(), wrapperspb.String("item"), cachingInfo, handler)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// TestKVCacheStore_Miss tests that missing keys are reported as a miss
+func TestKVCacheStore_Miss(t *testing.T) {
+	t.Parallel()
+	for _, store := range []kv.Store{kvnoop.New(), &notFoundKVStore{}} {
+		_, ok, err := NewKVCacheStore(store).Get(context.Background(), "k")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+}
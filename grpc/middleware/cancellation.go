@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Значения атрибута grpc.outcome метрик grpc.server.requests_total
+const (
+	OutcomeOK        = "ok"
+	OutcomeCancelled = "cancelled"
+	OutcomeFailed    = "failed"
+)
+
+// CancelledSpanAttribute — атрибут span'а запроса, отменённого клиентом
+const CancelledSpanAttribute = "rpc.cancelled"
+
+// IsClientCancellation сообщает, что запрос завершился из-за отмены клиентом: ошибка — context.Canceled
+// или codes.Canceled, либо контекст запроса отменён (клиент отменил вызов или отключился), даже если
+// обработчик вернул другую ошибку, например Internal от прерванного запроса к БД.
+// Истечение deadline отменой не считается.
+func IsClientCancellation(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled {
+		return true
+	}
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// requestStatusCode возвращает код статуса запроса; отменённые клиентом запросы получают codes.Canceled
+func requestStatusCode(ctx context.Context, err error) codes.Code {
+	if IsClientCancellation(ctx, err) {
+		return codes.Canceled
+	}
+	return status.Code(err)
+}
+
+// requestOutcome возвращает значение атрибута grpc.outcome
+func requestOutcome(ctx context.Context, err error) string {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case IsClientCancellation(ctx, err):
+		return OutcomeCancelled
+	default:
+		return OutcomeFailed
+	}
+}
+
+// statusAttributes возвращает атрибуты счётчика запросов: attrs, grpc.status и grpc.outcome
+func statusAttributes(ctx context.Context, err error, attrs []attribute.KeyValue) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attrs)+2)
+	result = append(result, attrs...)
+	return append(result,
+		attribute.String("grpc.status", requestStatusCode(ctx, err).String()),
+		attribute.String("grpc.outcome", requestOutcome(ctx, err)),
+	)
+}
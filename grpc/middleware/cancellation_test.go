@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// levelHandler records levels and messages of log records
+type levelHandler struct {
+	records *[]slog.Record
+}
+
+func (h *levelHandler) Handle(_ context.Context, r slog.Record) error {
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h *levelHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *levelHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *levelHandler) WithGroup(string) slog.Handler            { return h }
+
+// cancelledContext returns a context cancelled as if the client went away
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+// TestIsClientCancellation tests classification of cancellation errors.
+func TestIsClientCancellation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	deadlineCtx, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+
+	assert.True(t, IsClientCancellation(ctx, context.Canceled))
+	assert.True(t, IsClientCancellation(ctx, status.Error(codes.Canceled, "canceled")))
+	assert.True(t, IsClientCancellation(cancelledContext(), status.Error(codes.Internal, "query interrupted")),
+		"errors of requests whose client went away")
+
+	assert.False(t, IsClientCancellation(cancelledContext(), nil))
+	assert.False(t, IsClientCancellation(ctx, status.Error(codes.Internal, "boom")))
+	assert.False(t, IsClientCancellation(deadlineCtx, context.DeadlineExceeded))
+}
+
+// TestMetricsUnaryInterceptor_Cancellation tests that cancellations are counted separately from failures.
+func TestMetricsUnaryInterceptor_Cancellation(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	interceptor := MetricsUnaryInterceptor(WithMetricsMeterProvider(mp))
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	calls := []struct {
+		ctx context.Context
+		err error
+	}{
+		{context.Background(), nil},
+		{context.Background(), status.Error(codes.Internal, "boom")},
+		{cancelledContext(), status.Error(codes.Internal, "query interrupted")},
+		{context.Background(), context.Canceled},
+	}
+	for _, c := range calls {
+		_, _ = interceptor(c.ctx, nil, info, func(context.Context, any) (any, error) { return nil, c.err })
+	}
+
+	m, ok := collectMetric(t, reader, "grpc.server.requests_total")
+	require.True(t, ok)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	counts := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		statusCode, _ := dp.Attributes.Value(attribute.Key("grpc.status"))
+		outcome, _ := dp.Attributes.Value(attribute.Key("grpc.outcome"))
+		counts[statusCode.AsString()+"/"+outcome.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{
+		"OK/ok":              1,
+		"Internal/failed":    1,
+		"Canceled/cancelled": 2,
+	}, counts)
+}
+
+// TestLoggingInterceptor_Cancellation tests that cancellations are logged at Info.
+func TestLoggingInterceptor_Cancellation(t *testing.T) {
+	t.Parallel()
+	var records []slog.Record
+	log := slog.New(&levelHandler{records: &records})
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	_, err := LoggingInterceptor(log)(cancelledContext(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.Internal, "query interrupted")
+	})
+	require.Error(t, err)
+
+	ss := &mockServerStreamForMetrics{ctx: cancelledContext()}
+	err = LoggingStreamInterceptor(log)(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch"}, func(any, grpc.ServerStream) error {
+		return context.Canceled
+	})
+	require.Error(t, err)
+
+	require.Len(t, records, 2)
+	assert.Equal(t, slog.LevelInfo, records[0].Level)
+	assert.Equal(t, "gRPC request cancelled by client", records[0].Message)
+	assert.Equal(t, slog.LevelInfo, records[1].Level)
+	assert.Equal(t, "gRPC stream cancelled by client", records[1].Message)
+	records[0].Attrs(func(a slog.Attr) bool {
+		if a.Key == "status_code" {
+			assert.Equal(t, "Canceled", a.Value.String())
+		}
+		return true
+	})
+}
+
+// TestSetSpanStatus_Cancellation tests that cancelled requests are marked with an attribute, not an error status.
+func TestSetSpanStatus_Cancellation(t *testing.T) {
+	t.Parallel()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, cancelled := tp.Tracer("test").Start(context.Background(), "cancelled")
+	setSpanStatus(cancelledContext(), cancelled, context.Canceled)
+	cancelled.End()
+
+	_, failed := tp.Tracer("test").Start(context.Background(), "failed")
+	setSpanStatus(context.Background(), failed, status.Error(codes.Internal, "boom"))
+	failed.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, otelcodes.Unset, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.Bool(CancelledSpanAttribute, true))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("rpc.status_code", "Canceled"))
+	assert.Empty(t, spans[0].Events(), "cancellation is not recorded as an exception")
+
+	assert.Equal(t, otelcodes.Error, spans[1].Status().Code)
+	assert.NotContains(t, spans[1].Attributes(), attribute.Bool(CancelledSpanAttribute, true))
+}
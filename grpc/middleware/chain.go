@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"google.golang.org/grpc"
+)
+
+// ChainPosition определяет именованную позицию в цепочке интерцепторов.
+// Встроенные интерцепторы располагаются в порядке: Tracing, Metrics, Recovery, Logging;
+// интерцепторы арендатора (MonitoringOptions.TenantExtractor), RequestInfo (MonitoringOptions.EnableRequestInfo),
+// PeerInfo (MonitoringOptions.EnablePeerInfo) и IP клиента (MonitoringOptions.PeerIPExtractor) — сразу после Tracing.
+type ChainPosition int
+
+const (
+	// PositionFirst — в самом начале цепочки, до трассировки
+	PositionFirst ChainPosition = iota
+	// PositionAfterTracing — после трассировки, до метрик
+	PositionAfterTracing
+	// PositionAfterMetrics — после метрик, до recovery
+	PositionAfterMetrics
+	// PositionAfterRecovery — после recovery, до логирования
+	PositionAfterRecovery
+	// PositionAfterLogging — после всех встроенных интерцепторов, до аутентификации
+	PositionAfterLogging
+	// PositionAuth — слот для интерцепторов аутентификации и авторизации
+	PositionAuth
+	// PositionLast — в самом конце цепочки, непосредственно перед обработчиком
+	PositionLast
+)
+
+// PositionBeforeAuth — синоним PositionAfterLogging
+const PositionBeforeAuth = PositionAfterLogging
+
+// ChainInterceptor описывает пользовательский интерцептор и его позицию в цепочке.
+// Unary и Stream могут быть заданы независимо; nil значения пропускаются.
+type ChainInterceptor struct {
+	Position ChainPosition
+	Unary    grpc.UnaryServerInterceptor
+	Stream   grpc.StreamServerInterceptor
+}
+
+// UnaryAt создаёт ChainInterceptor для унарного интерцептора в заданной позиции
+func UnaryAt(position ChainPosition, interceptor grpc.UnaryServerInterceptor) ChainInterceptor {
+	return ChainInterceptor{Position: position, Unary: interceptor}
+}
+
+// StreamAt создаёт ChainInterceptor для потокового интерцептора в заданной позиции
+func StreamAt(position ChainPosition, interceptor grpc.StreamServerInterceptor) ChainInterceptor {
+	return ChainInterceptor{Position: position, Stream: interceptor}
+}
+
+// BuildChain возвращает упорядоченные цепочки унарных и потоковых интерцепторов
+// с учётом включённых встроенных компонентов и пользовательских интерцепторов из options.Interceptors.
+// Интерцепторы с одинаковой позицией сохраняют порядок добавления. Методы из options.SkipMethods
+// проходят мимо трассировки, метрик и логирования.
+//
+// В отличие от SetupMonitoring, BuildChain не изменяет глобальное состояние OpenTelemetry
+// и не возвращает опции сервера.
+func BuildChain(options *MonitoringOptions) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	unaryInterceptors := []grpc.UnaryServerInterceptor{}
+	streamInterceptors := []grpc.StreamServerInterceptor{}
+
+	appendAt := func(position ChainPosition) {
+		for _, ci := range options.Interceptors {
+			if ci.Position != position {
+				continue
+			}
+			if ci.Unary != nil {
+				unaryInterceptors = append(unaryInterceptors, ci.Unary)
+			}
+			if ci.Stream != nil {
+				streamInterceptors = append(streamInterceptors, ci.Stream)
+			}
+		}
+	}
+
+	appendAt(PositionFirst)
+
+	skipper := methodSkipper(options.SkipMethods)
+
+	if options.EnableTracing {
+		unaryInterceptors = append(unaryInterceptors, skipper.unary(TracingUnaryInterceptor(options.TracingOptions...)))
+		streamInterceptors = append(streamInterceptors, skipper.stream(TracingStreamInterceptor(options.TracingOptions...)))
+	}
+	if options.TenantExtractor != nil {
+		unaryInterceptors = append(unaryInterceptors, TenantUnaryInterceptor(options.TenantExtractor))
+		streamInterceptors = append(streamInterceptors, TenantStreamInterceptor(options.TenantExtractor))
+	}
+	if options.EnableRequestInfo {
+		unaryInterceptors = append(unaryInterceptors, RequestInfoUnaryInterceptor(options.RequestInfoOptions...))
+		streamInterceptors = append(streamInterceptors, RequestInfoStreamInterceptor(options.RequestInfoOptions...))
+	}
+	if options.EnablePeerInfo {
+		unaryInterceptors = append(unaryInterceptors, PeerInfoUnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, PeerInfoStreamInterceptor())
+	}
+	if options.PeerIPExtractor != nil {
+		unaryInterceptors = append(unaryInterceptors, ClientIPUnaryInterceptor(options.PeerIPExtractor))
+		streamInterceptors = append(streamInterceptors, ClientIPStreamInterceptor(options.PeerIPExtractor))
+	}
+	appendAt(PositionAfterTracing)
+
+	if options.EnableMetrics {
+		metrics := options.ServerMetrics
+		if metrics == nil {
+			metrics = newServerMetrics(options.MetricsOptions)
+		}
+		unaryInterceptors = append(unaryInterceptors, skipper.unary(metrics.UnaryServerInterceptor()))
+		streamInterceptors = append(streamInterceptors, skipper.stream(metrics.StreamServerInterceptor()))
+	}
+	appendAt(PositionAfterMetrics)
+
+	if options.EnableLogging {
+		var recoveryOpts []RecoveryOption
+		if options.ErrorReporter != nil {
+			recoveryOpts = append(recoveryOpts, WithRecoveryReporter(options.ErrorReporter))
+		}
+		unaryInterceptors = append(unaryInterceptors, RecoveryInterceptor(options.Logger, recoveryOpts...))
+		streamInterceptors = append(streamInterceptors, RecoveryStreamInterceptor(options.Logger, recoveryOpts...))
+	}
+	appendAt(PositionAfterRecovery)
+
+	if options.EnableLogging {
+		unaryInterceptors = append(unaryInterceptors, skipper.unary(LoggingInterceptor(options.Logger)))
+		streamInterceptors = append(streamInterceptors, skipper.stream(LoggingStreamInterceptor(options.Logger)))
+	}
+	appendAt(PositionAfterLogging)
+	appendAt(PositionAuth)
+	appendAt(PositionLast)
+
+	return unaryInterceptors, streamInterceptors
+}
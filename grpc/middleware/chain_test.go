@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/pure-golang/adapters/logger/noop"
+)
+
+// recordingUnary returns a unary interceptor that appends name to calls
+func recordingUnary(calls *[]string, name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		*calls = append(*calls, name)
+		return handler(ctx, req)
+	}
+}
+
+// runUnaryChain executes interceptors in order the same way grpc.ChainUnaryInterceptor does
+func runUnaryChain(chain []grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) (any, error) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	next := handler
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, h := chain[i], next
+		next = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, h)
+		}
+	}
+	return next(context.Background(), "request")
+}
+
+// TestBuildChain_CustomPositionsOrder tests that custom interceptors run in position order
+func TestBuildChain_CustomPositionsOrder(t *testing.T) {
+	t.Parallel()
+	var calls []string
+
+	opts := &MonitoringOptions{
+		Logger: noop.NewNoop(),
+		Interceptors: []ChainInterceptor{
+			UnaryAt(PositionLast, recordingUnary(&calls, "last")),
+			UnaryAt(PositionAuth, recordingUnary(&calls, "auth")),
+			UnaryAt(PositionFirst, recordingUnary(&calls, "first-1")),
+			UnaryAt(PositionAfterLogging, recordingUnary(&calls, "after-logging")),
+			UnaryAt(PositionFirst, recordingUnary(&calls, "first-2")),
+		},
+	}
+
+	unary, stream := BuildChain(opts)
+	require.Len(t, unary, 5)
+	assert.Empty(t, stream)
+
+	_, err := runUnaryChain(unary, func(ctx context.Context, req any) (any, error) {
+		calls = append(calls, "handler")
+		return "response", nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first-1", "first-2", "after-logging", "auth", "last", "handler"}, calls)
+}
+
+// TestBuildChain_BuiltinsPlacement tests custom interceptors placement relative to built-ins
+func TestBuildChain_BuiltinsPlacement(t *testing.T) {
+	t.Parallel()
+	var calls []string
+	first := recordingUnary(&calls, "first")
+	afterTracing := recordingUnary(&calls, "after-tracing")
+	afterRecovery := recordingUnary(&calls, "after-recovery")
+	last := recordingUnary(&calls, "last")
+
+	opts := DefaultMonitoringOptions(noop.NewNoop())
+	opts.Interceptors = []ChainInterceptor{
+		UnaryAt(PositionLast, last),
+		UnaryAt(PositionAfterRecovery, afterRecovery),
+		UnaryAt(PositionAfterTracing, afterTracing),
+		UnaryAt(PositionFirst, first),
+	}
+
+	unary, _ := BuildChain(opts)
+
+	// first, tracing, after-tracing, metrics, recovery, after-recovery, logging, last
+	require.Len(t, unary, 8)
+	assert.Equal(t, reflect.ValueOf(first).Pointer(), reflect.ValueOf(unary[0]).Pointer())
+	assert.Equal(t, reflect.ValueOf(afterTracing).Pointer(), reflect.ValueOf(unary[2]).Pointer())
+	assert.Equal(t, reflect.ValueOf(afterRecovery).Pointer(), reflect.ValueOf(unary[5]).Pointer())
+	assert.Equal(t, reflect.ValueOf(last).Pointer(), reflect.ValueOf(unary[7]).Pointer())
+
+	resp, err := runUnaryChain(unary, func(ctx context.Context, req any) (any, error) {
+		return "response", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "response", resp)
+	assert.Equal(t, []string{"first", "after-tracing", "after-recovery", "last"}, calls)
+}
+
+// TestBuildChain_StreamInterceptors tests that unary and stream interceptors are placed independently
+func TestBuildChain_StreamInterceptors(t *testing.T) {
+	t.Parallel()
+	streamInterceptor := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, ss)
+	}
+
+	opts := &MonitoringOptions{
+		Logger:        noop.NewNoop(),
+		EnableLogging: true,
+		Interceptors: []ChainInterceptor{
+			StreamAt(PositionFirst, streamInterceptor),
+		},
+	}
+
+	unary, stream := BuildChain(opts)
+
+	assert.Len(t, unary, 2, "recovery + logging")
+	require.Len(t, stream, 3, "custom + recovery + logging")
+	assert.Equal(t, reflect.ValueOf(streamInterceptor).Pointer(), reflect.ValueOf(stream[0]).Pointer())
+}
+
+// TestBuildChain_NoInterceptors tests that BuildChain matches SetupMonitoring defaults
+func TestBuildChain_NoInterceptors(t *testing.T) {
+	t.Parallel()
+	opts := &MonitoringOptions{
+		Logger:        noop.NewNoop(),
+		EnableTracing: true,
+		EnableMetrics: true,
+		EnableLogging: true,
+	}
+
+	unary, stream := BuildChain(opts)
+
+	assert.Len(t, unary, 4)
+	assert.Len(t, stream, 4)
+}
+
+// TestBuildChain_AllDisabled tests BuildChain with everything disabled
+func TestBuildChain_AllDisabled(t *testing.T) {
+	t.Parallel()
+	unary, stream := BuildChain(&MonitoringOptions{})
+
+	assert.Empty(t, unary)
+	assert.Empty(t, stream)
+}
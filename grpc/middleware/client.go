@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+// clientPropagator внедряет контекст трассировки в исходящие метаданные
+var clientPropagator = MetadataTextMapPropagator()
+
+// TracingUnaryClientInterceptor создает клиентский интерцептор для трассировки унарных RPC.
+// Контекст трассировки передается серверу через метаданные (MetadataTextMapPropagator).
+func TracingUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := startClientSpan(ctx, method, cc)
+		defer span.End()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		finishClientSpan(span, err)
+
+		return err
+	}
+}
+
+// TracingStreamClientInterceptor создает клиентский интерцептор для трассировки потоковых RPC.
+// Span завершается, когда RecvMsg возвращает ошибку или io.EOF.
+func TracingStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startClientSpan(ctx, method, cc)
+		span.SetAttributes(attribute.String("stream.type", streamType(desc.ClientStreams, desc.ServerStreams)))
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			finishClientSpan(span, err)
+			span.End()
+			return nil, err
+		}
+
+		return newFinishingClientStream(cs, func(err error) {
+			finishClientSpan(span, err)
+			span.End()
+		}), nil
+	}
+}
+
+// MetricsUnaryClientInterceptor создает клиентский интерцептор для метрик унарных RPC:
+// количество запросов и длительность по методу и статусу
+func MetricsUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		startTime := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordClientMetrics(ctx, method, time.Since(startTime), err)
+		return err
+	}
+}
+
+// MetricsStreamClientInterceptor создает клиентский интерцептор для метрик потоковых RPC
+func MetricsStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		startTime := time.Now()
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			recordClientMetrics(ctx, method, time.Since(startTime), err)
+			return nil, err
+		}
+
+		return newFinishingClientStream(cs, func(err error) {
+			recordClientMetrics(ctx, method, time.Since(startTime), err)
+		}), nil
+	}
+}
+
+// LoggingUnaryClientInterceptor создает клиентский интерцептор для логирования унарных RPC
+func LoggingUnaryClientInterceptor(logger *slog.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logClientCall(ctx, logger, "gRPC call", method, cc, time.Since(start), err)
+		return err
+	}
+}
+
+// LoggingStreamClientInterceptor создает клиентский интерцептор для логирования потоковых RPC
+func LoggingStreamClientInterceptor(logger *slog.Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logClientCall(ctx, logger, "gRPC stream", method, cc, time.Since(start), err)
+			return nil, err
+		}
+
+		return newFinishingClientStream(cs, func(err error) {
+			logClientCall(ctx, logger, "gRPC stream", method, cc, time.Since(start), err)
+		}), nil
+	}
+}
+
+// startClientSpan начинает клиентский span и внедряет контекст трассировки в исходящие метаданные
+func startClientSpan(ctx context.Context, fullMethod string, cc *grpc.ClientConn) (context.Context, trace.Span) {
+	service, method := splitMethodName(fullMethod)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+	}
+	if cc != nil {
+		attrs = append(attrs, attribute.String("server.address", cc.Target()))
+	}
+
+	ctx, span := tracer.Start(
+		ctx,
+		path.Join(service, method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.New(nil)
+	}
+	clientPropagator.Inject(ctx, metadataSupplier{metadata: &md})
+
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// finishClientSpan записывает статус вызова в span
+func finishClientSpan(span trace.Span, err error) {
+	if err != nil {
+		s, _ := status.FromError(err)
+		span.SetStatus(codes.Error, s.Message())
+		span.SetAttributes(attribute.String("rpc.status_code", s.Code().String()))
+		span.RecordError(err)
+		return
+	}
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.String("rpc.status_code", "OK"))
+}
+
+// recordClientMetrics записывает метрики исходящего вызова
+func recordClientMetrics(ctx context.Context, method string, duration time.Duration, err error) {
+	attrs := metric.WithAttributes(
+		attribute.String("grpc.method", method),
+		attribute.String("grpc.status", status.Code(err).String()),
+	)
+	clientRequestDuration.Record(ctx, duration.Milliseconds(), attrs)
+	clientRequestsCount.Add(ctx, 1, attrs)
+}
+
+// logClientCall логирует результат исходящего вызова
+func logClientCall(ctx context.Context, logger *slog.Logger, msg, method string, cc *grpc.ClientConn, duration time.Duration, err error) {
+	logAttrs := []any{
+		slog.String("method", method),
+		slog.Duration("duration", duration),
+	}
+	if cc != nil {
+		logAttrs = append(logAttrs, slog.String("target", cc.Target()))
+	}
+	logAttrs = append(logAttrs, correlate.Args(ctx)...)
+
+	if err != nil {
+		s := status.Convert(err)
+		logAttrs = append(logAttrs,
+			slog.String("status_code", s.Code().String()),
+			slog.Any("error", err),
+		)
+		logger.ErrorContext(ctx, msg+" failed", logAttrs...)
+		return
+	}
+	logAttrs = append(logAttrs, slog.String("status_code", "OK"))
+	logger.InfoContext(ctx, msg+" completed", logAttrs...)
+}
+
+// streamType возвращает тип потока для атрибутов
+func streamType(clientStream, serverStream bool) string {
+	switch {
+	case clientStream && serverStream:
+		return "bidi_streaming"
+	case clientStream:
+		return "client_streaming"
+	default:
+		return "server_streaming"
+	}
+}
+
+// finishingClientStream оборачивает grpc.ClientStream и вызывает onFinish один раз,
+// когда поток завершается: RecvMsg вернул ошибку (io.EOF считается успешным завершением)
+// или не удалось получить заголовки
+type finishingClientStream struct {
+	grpc.ClientStream
+	once     sync.Once
+	onFinish func(err error)
+}
+
+func newFinishingClientStream(cs grpc.ClientStream, onFinish func(err error)) *finishingClientStream {
+	return &finishingClientStream{
+		ClientStream: cs,
+		onFinish:     onFinish,
+	}
+}
+
+func (s *finishingClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *finishingClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.finish(err)
+	}
+	return md, err
+}
+
+func (s *finishingClientStream) finish(err error) {
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	s.once.Do(func() {
+		s.onFinish(err)
+	})
+}
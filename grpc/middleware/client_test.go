@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mockClientStream is a grpc.ClientStream returning preset RecvMsg errors
+type mockClientStream struct {
+	grpc.ClientStream
+	recvErrs []error
+}
+
+func (m *mockClientStream) RecvMsg(any) error {
+	if len(m.recvErrs) == 0 {
+		return io.EOF
+	}
+	err := m.recvErrs[0]
+	m.recvErrs = m.recvErrs[1:]
+	return err
+}
+
+func parentContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc), sc
+}
+
+// TestTracingUnaryClientInterceptor_InjectsTraceContext tests that the trace context is sent in metadata
+func TestTracingUnaryClientInterceptor_InjectsTraceContext(t *testing.T) {
+	t.Parallel()
+	ctx, parent := parentContext(t)
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", "42")
+	interceptor := TracingUnaryClientInterceptor()
+
+	var md metadata.MD
+	err := interceptor(ctx, "/test.Service/Get", "req", nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, md.Get("traceparent"), 1)
+	assert.Contains(t, md.Get("traceparent")[0], parent.TraceID().String())
+	assert.Equal(t, []string{"42"}, md.Get("x-request-id"), "existing metadata must be preserved")
+}
+
+// TestTracingUnaryClientInterceptor_ReturnsError tests that invoker errors are returned unchanged
+func TestTracingUnaryClientInterceptor_ReturnsError(t *testing.T) {
+	t.Parallel()
+	interceptor := TracingUnaryClientInterceptor()
+	expectedErr := status.Error(codes.Unavailable, "unavailable")
+
+	err := interceptor(context.Background(), "/test.Service/Get", "req", nil, nil, func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return expectedErr
+	})
+
+	assert.Equal(t, expectedErr, err)
+}
+
+// TestTracingStreamClientInterceptor_InjectsTraceContext tests trace propagation for streams
+func TestTracingStreamClientInterceptor_InjectsTraceContext(t *testing.T) {
+	t.Parallel()
+	ctx, parent := parentContext(t)
+	interceptor := TracingStreamClientInterceptor()
+	desc := &grpc.StreamDesc{ServerStreams: true}
+
+	var md metadata.MD
+	cs, err := interceptor(ctx, desc, nil, "/test.Service/Watch", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return &mockClientStream{}, nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, md.Get("traceparent"), 1)
+	assert.Contains(t, md.Get("traceparent")[0], parent.TraceID().String())
+	assert.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+}
+
+// TestTracingStreamClientInterceptor_StreamerError tests that streamer errors are returned
+func TestTracingStreamClientInterceptor_StreamerError(t *testing.T) {
+	t.Parallel()
+	interceptor := TracingStreamClientInterceptor()
+	expectedErr := status.Error(codes.Unavailable, "unavailable")
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Watch", func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, expectedErr
+	})
+
+	assert.Nil(t, cs)
+	assert.Equal(t, expectedErr, err)
+}
+
+// TestFinishingClientStream_FinishesOnce tests that onFinish is called once with io.EOF mapped to nil
+func TestFinishingClientStream_FinishesOnce(t *testing.T) {
+	t.Parallel()
+	var calls []error
+	cs := newFinishingClientStream(&mockClientStream{recvErrs: []error{nil, io.EOF, io.EOF}}, func(err error) {
+		calls = append(calls, err)
+	})
+
+	require.NoError(t, cs.RecvMsg(nil))
+	assert.Empty(t, calls)
+
+	assert.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+	assert.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+	assert.Equal(t, []error{nil}, calls)
+}
+
+// TestFinishingClientStream_Error tests that stream errors are passed to onFinish
+func TestFinishingClientStream_Error(t *testing.T) {
+	t.Parallel()
+	streamErr := status.Error(codes.Internal, "broken")
+	var finishErr error
+	cs := newFinishingClientStream(&mockClientStream{recvErrs: []error{streamErr}}, func(err error) {
+		finishErr = err
+	})
+
+	assert.Equal(t, streamErr, cs.RecvMsg(nil))
+	assert.Equal(t, streamErr, finishErr)
+}
+
+// TestMetricsUnaryClientInterceptor tests that metrics interceptor passes results through
+func TestMetricsUnaryClientInterceptor(t *testing.T) {
+	t.Parallel()
+	interceptor := MetricsUnaryClientInterceptor()
+	expectedErr := status.Error(codes.NotFound, "not found")
+
+	err := interceptor(context.Background(), "/test.Service/Get", "req", nil, nil, func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return expectedErr
+	})
+
+	assert.Equal(t, expectedErr, err)
+}
+
+// TestMetricsStreamClientInterceptor tests that metrics stream interceptor wraps the stream
+func TestMetricsStreamClientInterceptor(t *testing.T) {
+	t.Parallel()
+	interceptor := MetricsStreamClientInterceptor()
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, "/test.Service/Watch", func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &mockClientStream{}, nil
+	})
+
+	require.NoError(t, err)
+	assert.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+}
+
+// TestLoggingUnaryClientInterceptor tests logging of successful and failed calls
+func TestLoggingUnaryClientInterceptor(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	interceptor := LoggingUnaryClientInterceptor(logger)
+
+	err := interceptor(context.Background(), "/test.Service/Get", "req", nil, nil, func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `msg="gRPC call completed"`)
+	assert.Contains(t, buf.String(), "method=/test.Service/Get")
+
+	buf.Reset()
+	err = interceptor(context.Background(), "/test.Service/Get", "req", nil, nil, func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.PermissionDenied, "denied")
+	})
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), `msg="gRPC call failed"`)
+	assert.Contains(t, buf.String(), "status_code=PermissionDenied")
+}
+
+// TestLoggingStreamClientInterceptor tests logging when the stream ends with an error
+func TestLoggingStreamClientInterceptor(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	interceptor := LoggingStreamClientInterceptor(logger)
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, "/test.Service/Watch", func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &mockClientStream{recvErrs: []error{errors.New("connection reset")}}, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, buf.String(), "nothing is logged until the stream ends")
+
+	require.Error(t, cs.RecvMsg(nil))
+	assert.Contains(t, buf.String(), `msg="gRPC stream failed"`)
+	assert.Contains(t, buf.String(), "status_code=Unknown")
+}
+
+// TestStreamType tests stream type detection
+func TestStreamType(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "server_streaming", streamType(false, true))
+	assert.Equal(t, "client_streaming", streamType(true, false))
+	assert.Equal(t, "bidi_streaming", streamType(true, true))
+}
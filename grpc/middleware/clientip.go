@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Заголовки метаданных, в которых L7 балансировщики и прокси передают адрес клиента
+const (
+	ForwardedForHeader = "x-forwarded-for"
+	RealIPHeader       = "x-real-ip"
+)
+
+// ClientIPLogField — имя поля лога с IP клиента
+const ClientIPLogField = "client_ip"
+
+// PeerIPExtractor определяет IP клиента запроса по контексту и входящим метаданным.
+// Невалидный netip.Addr — адрес не определён.
+type PeerIPExtractor func(ctx context.Context, md metadata.MD) netip.Addr
+
+// ParseTrustedProxies разбирает список доверенных прокси: подсети CIDR ("10.0.0.0/8")
+// или отдельные адреса ("192.168.1.10")
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, errors.Errorf("invalid trusted proxy %q: expected an IP address or a CIDR", proxy)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, errors.Errorf("invalid trusted proxy %q: expected an IP address or a CIDR", proxy)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ForwardedPeerIPExtractor возвращает IP клиента с учётом прокси из trusted.
+//
+// Если соединение пришло не от доверенного прокси, IP клиента — адрес соединения: заголовки
+// подделывает любой клиент, поэтому они не читаются. Иначе x-forwarded-for просматривается справа
+// налево, доверенные адреса пропускаются, и IP клиента — первый недоверенный адрес; если все адреса
+// доверенные — самый левый. Без x-forwarded-for используется x-real-ip. Соединение через unix сокет
+// считается соединением от локального доверенного прокси (sidecar).
+func ForwardedPeerIPExtractor(trusted ...netip.Prefix) PeerIPExtractor {
+	isTrusted := func(addr netip.Addr) bool {
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(ctx context.Context, md metadata.MD) netip.Addr {
+		addr, isPeerIP := peerIP(ctx)
+		if isPeerIP && !isTrusted(addr) {
+			return addr
+		}
+
+		hops := forwardedHops(md.Get(ForwardedForHeader))
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(hops[i])
+			if err != nil {
+				// Цепочку дальше не разобрать: клиент — ближайший известный адрес
+				return addr
+			}
+			addr = hop.Unmap()
+			if !isTrusted(addr) {
+				return addr
+			}
+		}
+		if len(hops) > 0 {
+			return addr
+		}
+
+		if values := md.Get(RealIPHeader); len(values) > 0 {
+			if realIP, err := netip.ParseAddr(strings.TrimSpace(values[0])); err == nil {
+				return realIP.Unmap()
+			}
+		}
+		return addr
+	}
+}
+
+// forwardedHops возвращает адреса x-forwarded-for по порядку; значения нескольких заголовков объединяются
+func forwardedHops(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for hop := range strings.SplitSeq(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// peerIP возвращает IP адрес соединения; false — соединение не по IP (unix сокет) или адрес неизвестен
+func peerIP(ctx context.Context) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}, false
+	}
+	if tcp, ok := p.Addr.(*net.TCPAddr); ok {
+		addr, ok := netip.AddrFromSlice(tcp.IP)
+		return addr.Unmap(), ok
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addrPort.Addr().Unmap(), true
+}
+
+// clientIPKey — ключ контекста с IP клиента
+type clientIPKey struct{}
+
+// ClientIPFromContext возвращает IP клиента, определённый ClientIPUnaryInterceptor или ClientIPStreamInterceptor,
+// например как ключ ограничителя частоты запросов. Без интерцептора возвращается адрес соединения;
+// false — адрес не определён.
+func ClientIPFromContext(ctx context.Context) (netip.Addr, bool) {
+	if addr, ok := ctx.Value(clientIPKey{}).(netip.Addr); ok {
+		return addr, true
+	}
+	return peerIP(ctx)
+}
+
+// ClientIPUnaryInterceptor определяет IP клиента через extractor и сохраняет его в контексте
+// (см. ClientIPFromContext). IP заменяет адрес соединения в атрибуте client.address текущего span'а
+// и добавляется в поля логгера контекста (logger.FromContext), записи интерцепторов recovery
+// и логирования (client_ip) и, с WithClientIPLabeler, в атрибуты метрик интерцепторов, стоящих после него.
+func ClientIPUnaryInterceptor(extractor PeerIPExtractor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withClientIP(ctx, extractor), req)
+	}
+}
+
+// ClientIPStreamInterceptor сохраняет IP клиента в контексте потока, как ClientIPUnaryInterceptor
+func ClientIPStreamInterceptor(extractor PeerIPExtractor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withClientIP(ss.Context(), extractor)
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withClientIP сохраняет IP клиента в контексте, span'е и логгере контекста
+func withClientIP(ctx context.Context, extractor PeerIPExtractor) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.New(nil)
+	}
+	addr := extractor(ctx, md)
+	if !addr.IsValid() {
+		return ctx
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("client.address", addr.String()))
+	ctx = logger.NewContext(ctx, logger.FromContext(ctx).With(slog.String(ClientIPLogField, addr.String())))
+	return context.WithValue(ctx, clientIPKey{}, addr)
+}
+
+// clientIPLogFields возвращает поле лога с IP клиента, если он определён интерцептором
+func clientIPLogFields(ctx context.Context) []any {
+	if addr, ok := ctx.Value(clientIPKey{}).(netip.Addr); ok {
+		return []any{slog.String(ClientIPLogField, addr.String())}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// proxiedContext returns a context of a request from peerAddr with incoming metadata pairs.
+func proxiedContext(peerAddr net.Addr, kv ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: peerAddr})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+}
+
+func tcpAddr(ip string) net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(ip), Port: 52344}
+}
+
+// TestParseTrustedProxies tests parsing of CIDRs and single addresses.
+func TestParseTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	prefixes, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.10 ", "fd00::/8", "172.16.5.4/12"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.10/32"),
+		netip.MustParsePrefix("fd00::/8"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+	}, prefixes)
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, `invalid trusted proxy "10.0.0.0/33": expected an IP address or a CIDR`)
+	_, err = ParseTrustedProxies([]string{"proxy.internal"})
+	assert.Error(t, err)
+}
+
+// TestForwardedPeerIPExtractor tests client IP resolution through trusted and untrusted hops.
+func TestForwardedPeerIPExtractor(t *testing.T) {
+	t.Parallel()
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	extract := ForwardedPeerIPExtractor(trusted...)
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		want string
+	}{
+		"untrusted peer ignores headers": {
+			ctx:  proxiedContext(tcpAddr("203.0.113.9"), ForwardedForHeader, "198.51.100.1"),
+			want: "203.0.113.9",
+		},
+		"trusted peer without headers": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1")),
+			want: "10.0.0.1",
+		},
+		"rightmost untrusted hop": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "1.2.3.4, 198.51.100.1, 10.0.0.2"),
+			want: "198.51.100.1",
+		},
+		"several headers": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "198.51.100.1", ForwardedForHeader, "10.0.0.2"),
+			want: "198.51.100.1",
+		},
+		"all hops trusted": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "10.0.0.3, 10.0.0.2"),
+			want: "10.0.0.3",
+		},
+		"invalid hop": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "198.51.100.1, unknown, 10.0.0.2"),
+			want: "10.0.0.2",
+		},
+		"real ip": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1"), RealIPHeader, "198.51.100.7"),
+			want: "198.51.100.7",
+		},
+		"ipv4-mapped hop": {
+			ctx:  proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "::ffff:198.51.100.1"),
+			want: "198.51.100.1",
+		},
+		"unix socket sidecar": {
+			ctx:  proxiedContext(&net.UnixAddr{Name: "/run/envoy.sock", Net: "unix"}, ForwardedForHeader, "198.51.100.1"),
+			want: "198.51.100.1",
+		},
+	} {
+		md, _ := metadata.FromIncomingContext(tc.ctx)
+		assert.Equal(t, tc.want, extract(tc.ctx, md).String(), name)
+	}
+
+	ctx := proxiedContext(&net.UnixAddr{Name: "/run/envoy.sock", Net: "unix"})
+	md, _ := metadata.FromIncomingContext(ctx)
+	assert.False(t, extract(ctx, md).IsValid())
+}
+
+// TestClientIPFromContext tests the fallback to the connection address.
+func TestClientIPFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := ClientIPFromContext(context.Background())
+	assert.False(t, ok)
+
+	addr, ok := ClientIPFromContext(proxiedContext(tcpAddr("10.0.0.1")))
+	require.True(t, ok)
+	assert.Equal(t, "10.0.0.1", addr.String())
+}
+
+// TestClientIPUnaryInterceptor tests that the client IP reaches the handler, the span and the context logger.
+func TestClientIPUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var buf bytes.Buffer
+	ctx := proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "198.51.100.1")
+	ctx = logger.NewContext(ctx, slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, span := tp.Tracer("test").Start(ctx, "request")
+
+	extractor := ForwardedPeerIPExtractor(netip.MustParsePrefix("10.0.0.0/8"))
+	_, err := ClientIPUnaryInterceptor(extractor)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		addr, ok := ClientIPFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "198.51.100.1", addr.String())
+		logger.FromContext(ctx).InfoContext(ctx, "handled")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	span.End()
+
+	assert.Contains(t, buf.String(), "client_ip=198.51.100.1")
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("client.address", "198.51.100.1"))
+}
+
+// TestClientIPStreamInterceptor tests that the client IP reaches the stream context.
+func TestClientIPStreamInterceptor(t *testing.T) {
+	t.Parallel()
+	extractor := ForwardedPeerIPExtractor(netip.MustParsePrefix("10.0.0.0/8"))
+	ctx := proxiedContext(tcpAddr("10.0.0.1"), RealIPHeader, "198.51.100.7")
+
+	err := ClientIPStreamInterceptor(extractor)(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{},
+		func(_ any, ss grpc.ServerStream) error {
+			addr, ok := ss.Context().Value(clientIPKey{}).(netip.Addr)
+			assert.True(t, ok)
+			assert.Equal(t, "198.51.100.7", addr.String())
+			return nil
+		})
+	require.NoError(t, err)
+
+	// Без адреса клиента поток передаётся без изменений
+	ss := &fakeServerStream{ctx: context.Background()}
+	err = ClientIPStreamInterceptor(extractor)(nil, ss, &grpc.StreamServerInfo{}, func(_ any, got grpc.ServerStream) error {
+		assert.Same(t, ss, got)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// TestBuildChain_PeerIPExtractor tests that the client IP overrides the proxy address in the request log record.
+func TestBuildChain_PeerIPExtractor(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	unary, _ := BuildChain(&MonitoringOptions{
+		Logger:          slog.New(slog.NewTextHandler(&buf, nil)),
+		EnableLogging:   true,
+		EnablePeerInfo:  true,
+		PeerIPExtractor: ForwardedPeerIPExtractor(netip.MustParsePrefix("10.0.0.0/8")),
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	next := grpc.UnaryHandler(func(context.Context, any) (any, error) { return "response", nil })
+	for i := len(unary) - 1; i >= 0; i-- {
+		interceptor, h := unary[i], next
+		next = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, h)
+		}
+	}
+	_, err := next(proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "198.51.100.1"), "request")
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "peer_address=10.0.0.1:52344 client_ip=198.51.100.1")
+}
+
+// TestMetricsUnaryInterceptor_ClientIPLabeler tests the client.network attribute.
+func TestMetricsUnaryInterceptor_ClientIPLabeler(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	interceptor := MetricsUnaryInterceptor(
+		WithMetricsMeterProvider(mp),
+		WithClientIPLabeler(ClientIPPrefixLabeler(16, 48)),
+	)
+
+	ctx := proxiedContext(tcpAddr("10.0.0.1"), ForwardedForHeader, "203.0.113.7")
+	ctx = context.WithValue(ctx, clientIPKey{}, netip.MustParseAddr("203.0.113.7"))
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	points := durationPoints(t, reader)
+	require.Len(t, points, 1)
+	network, ok := points[0].Attributes.Value(ClientNetworkAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "203.0.0.0/16", network.AsString())
+}
+
+// TestClientIPPrefixLabeler tests masking of IPv4 and IPv6 addresses.
+func TestClientIPPrefixLabeler(t *testing.T) {
+	t.Parallel()
+	labeler := ClientIPPrefixLabeler(24, 48)
+
+	assert.Equal(t, "198.51.100.0/24", labeler(netip.MustParseAddr("198.51.100.7")))
+	assert.Equal(t, "2001:db8:1::/48", labeler(netip.MustParseAddr("2001:db8:1:2::1")))
+	assert.Empty(t, ClientIPPrefixLabeler(33, 48)(netip.MustParseAddr("198.51.100.7")))
+}
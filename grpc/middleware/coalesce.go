@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CoalescedHeader — заголовок ответа, выставляемый запросам, получившим результат чужого выполнения
+const CoalescedHeader = "request-coalesced"
+
+// CoalescingOption настраивает CoalescingUnaryInterceptor
+type CoalescingOption func(*coalescingConfig)
+
+// WithCoalescingHeader задаёт ключ метаданных, из которого читается ключ запроса
+// (по умолчанию IdempotencyKeyHeader)
+func WithCoalescingHeader(header string) CoalescingOption {
+	return func(c *coalescingConfig) {
+		c.header = header
+	}
+}
+
+// WithCoalescingScope задаёт функцию, возвращающую область действия ключа
+// (например, ID пользователя), чтобы одинаковые ключи разных клиентов не объединялись
+func WithCoalescingScope(scope func(ctx context.Context) string) CoalescingOption {
+	return func(c *coalescingConfig) {
+		c.scope = scope
+	}
+}
+
+type coalescingConfig struct {
+	header string
+	scope  func(ctx context.Context) string
+}
+
+// coalescedCall — выполнение обработчика, результат которого ждут одинаковые запросы
+type coalescedCall struct {
+	done        chan struct{}
+	fingerprint []byte
+	resp        any
+	err         error
+	// retry — выполнение прервано отменой или дедлайном запроса-исполнителя:
+	// ожидающие запросы выполняют обработчик заново
+	retry bool
+}
+
+// CoalescingUnaryInterceptor создает интерцептор, объединяющий одновременные запросы
+// с одинаковым ключом (метаданные idempotency-key): обработчик выполняется один раз,
+// остальные запросы ждут его завершения и получают тот же ответ или ошибку
+// с заголовком request-coalesced: true — singleflight для RPC. Экспериментальный: API может измениться.
+//
+// Защищает от повторов и hedging'а клиента (gRPC hedging policy отправляет одинаковые запросы
+// параллельно), пока первый запрос ещё выполняется. В отличие от IdempotencyUnaryInterceptor
+// результат не сохраняется: запрос после завершения выполнения снова вызывает обработчик.
+// Для семантики at-most-once после завершения добавьте IdempotencyUnaryInterceptor после этого интерцептора.
+//
+// Запрос с тем же ключом, но другим телом, отклоняется с codes.FailedPrecondition.
+// Если запрос-исполнитель отменён клиентом или истёк его дедлайн, ожидающие запросы выполняют
+// обработчик заново. Ожидание прерывается отменой и дедлайном собственного контекста запроса.
+// Объединяются только запросы одного процесса; запросы без ключа обрабатываются как обычно.
+func CoalescingUnaryInterceptor(opts ...CoalescingOption) grpc.UnaryServerInterceptor {
+	cfg := &coalescingConfig{header: IdempotencyKeyHeader}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var mu sync.Mutex
+	calls := make(map[string]*coalescedCall)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(cfg.header)
+		if len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+
+		key := info.FullMethod + ":"
+		if cfg.scope != nil {
+			key += cfg.scope(ctx) + ":"
+		}
+		key += values[0]
+
+		var fingerprint []byte
+		if reqMsg, ok := req.(proto.Message); ok {
+			var err error
+			if fingerprint, err = requestFingerprint(reqMsg); err != nil {
+				return nil, status.Error(codes.Internal, "failed to fingerprint request")
+			}
+		}
+
+		for {
+			mu.Lock()
+			call, running := calls[key]
+			if !running {
+				call = &coalescedCall{done: make(chan struct{}), fingerprint: fingerprint}
+				calls[key] = call
+				mu.Unlock()
+				return runCoalescedCall(ctx, req, handler, call, func() {
+					mu.Lock()
+					delete(calls, key)
+					mu.Unlock()
+				})
+			}
+			mu.Unlock()
+
+			if !bytes.Equal(call.fingerprint, fingerprint) {
+				return nil, status.Error(codes.FailedPrecondition, "idempotency key reused with a different request")
+			}
+
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+			if call.retry {
+				continue
+			}
+
+			_ = grpc.SetHeader(ctx, metadata.Pairs(CoalescedHeader, "true"))
+			return cloneResponse(call.resp), call.err
+		}
+	}
+}
+
+// runCoalescedCall выполняет обработчик и публикует результат ожидающим запросам.
+// Паника обработчика передаётся дальше по цепочке, ожидающие получают codes.Internal.
+func runCoalescedCall(ctx context.Context, req any, handler grpc.UnaryHandler, call *coalescedCall, remove func()) (any, error) {
+	panicked := true
+	defer func() {
+		remove()
+		if panicked {
+			call.resp, call.err = nil, status.Error(codes.Internal, "internal server error")
+		}
+		close(call.done)
+	}()
+
+	resp, err := handler(ctx, req)
+	panicked = false
+
+	call.resp, call.err = resp, err
+	call.retry = err != nil && ctx.Err() != nil
+	return resp, err
+}
+
+// cloneResponse копирует protobuf ответ, чтобы запросы не сериализовали одно сообщение одновременно
+func cloneResponse(resp any) any {
+	if msg, ok := resp.(proto.Message); ok && msg != nil {
+		return proto.Clone(msg)
+	}
+	return resp
+}
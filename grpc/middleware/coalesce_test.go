@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// blockingHandler counts calls, signals entry and blocks until release is closed
+func blockingHandler(calls *atomic.Int32, entered chan<- struct{}, release <-chan struct{}) grpc.UnaryHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		n := calls.Add(1)
+		entered <- struct{}{}
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return wrapperspb.String(req.(*wrapperspb.StringValue).GetValue() + "-" + string(rune('0'+n))), nil
+	}
+}
+
+// TestCoalescingUnaryInterceptor_SharesResult tests that concurrent duplicates execute the handler once
+func TestCoalescingUnaryInterceptor_SharesResult(t *testing.T) {
+	t.Parallel()
+	interceptor := CoalescingUnaryInterceptor()
+	var calls atomic.Int32
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	handler := blockingHandler(&calls, entered, release)
+
+	const requests = 5
+	responses := make([]any, requests)
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[0], errs[0] = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+	}()
+	<-entered
+	for i := 1; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], errs[i] = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // followers are waiting for the first execution
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for i := range requests {
+		require.NoError(t, errs[i])
+		assert.True(t, proto.Equal(wrapperspb.String("charge-1"), responses[i].(proto.Message)))
+	}
+	assert.NotSame(t, responses[0], responses[1], "followers get a copy of the response")
+
+	_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, func(context.Context, any) (any, error) {
+		return wrapperspb.String("again"), nil
+	})
+	require.NoError(t, err, "completed executions are not stored")
+}
+
+// TestCoalescingUnaryInterceptor_DifferentBody tests that a key reused with another body is rejected
+func TestCoalescingUnaryInterceptor_DifferentBody(t *testing.T) {
+	t.Parallel()
+	interceptor := CoalescingUnaryInterceptor()
+	var calls atomic.Int32
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		_, _ = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, blockingHandler(&calls, entered, release))
+	}()
+	<-entered
+
+	_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("refund"), idempotencyInfo, countingHandler(&calls))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+// TestCoalescingUnaryInterceptor_LeaderCancelled tests that followers take over after the executing request is cancelled
+func TestCoalescingUnaryInterceptor_LeaderCancelled(t *testing.T) {
+	t.Parallel()
+	interceptor := CoalescingUnaryInterceptor()
+	var calls atomic.Int32
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	handler := blockingHandler(&calls, entered, release)
+
+	leaderCtx, cancel := context.WithCancel(withIdempotencyKey("k1"))
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := interceptor(leaderCtx, wrapperspb.String("charge"), idempotencyInfo, handler)
+		leaderDone <- err
+	}()
+	<-entered
+
+	followerDone := make(chan error, 1)
+	var followerResp any
+	go func() {
+		var err error
+		followerResp, err = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+		followerDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-leaderDone))
+	<-entered // the follower executes the handler itself
+	close(release)
+	require.NoError(t, <-followerDone)
+	assert.True(t, proto.Equal(wrapperspb.String("charge-2"), followerResp.(proto.Message)))
+}
+
+// TestCoalescingUnaryInterceptor_Panic tests that a panic reaches the executing request and followers get Internal
+func TestCoalescingUnaryInterceptor_Panic(t *testing.T) {
+	t.Parallel()
+	interceptor := CoalescingUnaryInterceptor()
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(context.Context, any) (any, error) {
+		close(entered)
+		<-release
+		panic("boom")
+	}
+
+	panicked := make(chan any, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_, _ = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+	}()
+	<-entered
+
+	followerDone := make(chan error, 1)
+	go func() {
+		_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+		followerDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, "boom", <-panicked)
+	assert.Equal(t, codes.Internal, status.Code(<-followerDone))
+}
@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Имена поддерживаемых компрессоров
+const (
+	CompressorGzip = gzip.Name
+	CompressorZstd = "zstd"
+)
+
+// Компрессоры регистрируются при инициализации: encoding.RegisterCompressor
+// нельзя вызывать конкурентно с созданием серверов и соединений.
+// gzip регистрируется импортом google.golang.org/grpc/encoding/gzip.
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// zstdCompressor реализует encoding.Compressor на klauspost/compress/zstd
+// с переиспользованием энкодеров и декодеров
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+// Name возвращает имя компрессора для заголовка grpc-encoding
+func (c *zstdCompressor) Name() string {
+	return CompressorZstd
+}
+
+// Compress возвращает writer, сжимающий данные в w
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	enc, ok := c.encoders.Get().(*zstd.Encoder)
+	if !ok {
+		var err error
+		enc, err = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		enc.Reset(w)
+	}
+	return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+// Decompress возвращает reader, распаковывающий данные из r.
+// Размер распакованного сообщения ограничивает gRPC (MaxRecvMsgSize).
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	dec, ok := c.decoders.Get().(*zstd.Decoder)
+	if !ok {
+		var err error
+		dec, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+	} else if err := dec.Reset(r); err != nil {
+		c.decoders.Put(dec)
+		return nil, err
+	}
+	return &zstdReader{dec: dec, pool: &c.decoders}, nil
+}
+
+// zstdWriter возвращает энкодер в пул при закрытии
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w.Encoder)
+	return err
+}
+
+// zstdReader возвращает декодер в пул, дочитав поток до конца
+type zstdReader struct {
+	dec  *zstd.Decoder
+	pool *sync.Pool
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	if r.dec == nil {
+		return 0, io.EOF
+	}
+	n, err := r.dec.Read(p)
+	if errors.Is(err, io.EOF) {
+		r.pool.Put(r.dec)
+		r.dec = nil
+	}
+	return n, err
+}
+
+// SendCompressorUnaryInterceptor создает интерцептор, сжимающий ответы компрессором name,
+// если клиент поддерживает его (заголовок grpc-accept-encoding). Иначе ответ отправляется
+// так же, как без интерцептора: сжатым компрессором запроса или без сжатия.
+func SendCompressorUnaryInterceptor(name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		setSendCompressor(ctx, name)
+		return handler(ctx, req)
+	}
+}
+
+// SendCompressorStreamInterceptor — потоковый вариант SendCompressorUnaryInterceptor
+func SendCompressorStreamInterceptor(name string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		setSendCompressor(ss.Context(), name)
+		return handler(srv, ss)
+	}
+}
+
+// setSendCompressor выбирает компрессор ответа, если клиент его поддерживает
+func setSendCompressor(ctx context.Context, name string) {
+	supported, err := grpc.ClientSupportedCompressors(ctx)
+	if err != nil || !slices.Contains(supported, name) {
+		return
+	}
+	// Ошибка возможна только вне серверного RPC или для незарегистрированного компрессора
+	_ = grpc.SetSendCompressor(ctx, name)
+}
+
+// compressionInfoKey — ключ контекста для сведений о сжатии входящих сообщений
+type compressionInfoKey struct{}
+
+// compressionInfo заполняется CompressionStatsHandler до вызова интерцепторов
+type compressionInfo struct {
+	compression atomic.Pointer[string]
+	lastSize    atomic.Int64 // размер последнего полученного сообщения без сжатия
+}
+
+// CompressionStatsHandler возвращает stats.Handler, сохраняющий в контексте RPC
+// алгоритм сжатия запроса и размер полученных сообщений.
+// Необходим для RequireCompressionUnaryInterceptor и RequestCompression:
+// заголовок grpc-encoding не попадает в метаданные запроса.
+//
+//	grpc.NewServer(grpc.StatsHandler(middleware.CompressionStatsHandler()), ...)
+func CompressionStatsHandler() stats.Handler {
+	return compressionStatsHandler{}
+}
+
+type compressionStatsHandler struct{}
+
+func (compressionStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, compressionInfoKey{}, &compressionInfo{})
+}
+
+func (compressionStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	info, ok := ctx.Value(compressionInfoKey{}).(*compressionInfo)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.InHeader:
+		compression := s.Compression
+		info.compression.Store(&compression)
+	case *stats.InPayload:
+		info.lastSize.Store(int64(s.Length))
+	}
+}
+
+func (compressionStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (compressionStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// RequestCompression возвращает алгоритм сжатия входящего запроса ("" — без сжатия).
+// ok == false, если сервер не использует CompressionStatsHandler.
+func RequestCompression(ctx context.Context) (compression string, ok bool) {
+	info, found := ctx.Value(compressionInfoKey{}).(*compressionInfo)
+	if !found {
+		return "", false
+	}
+	if c := info.compression.Load(); c != nil {
+		return *c, true
+	}
+	return "", true
+}
+
+// RequireCompressionUnaryInterceptor создает интерцептор, отклоняющий несжатые запросы
+// больше threshold байт с codes.ResourceExhausted.
+// Требует CompressionStatsHandler; без него запросы пропускаются без проверки.
+// threshold <= 0 отключает проверку.
+func RequireCompressionUnaryInterceptor(threshold int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkCompression(ctx, threshold); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RequireCompressionStreamInterceptor — потоковый вариант RequireCompressionUnaryInterceptor:
+// проверяется каждое полученное сообщение
+func RequireCompressionStreamInterceptor(threshold int) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if threshold <= 0 {
+			return handler(srv, ss)
+		}
+		return handler(srv, &compressionCheckingStream{ServerStream: ss, threshold: threshold})
+	}
+}
+
+// compressionCheckingStream проверяет сжатие после каждого RecvMsg
+type compressionCheckingStream struct {
+	grpc.ServerStream
+	threshold int
+}
+
+func (s *compressionCheckingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkCompression(s.Context(), s.threshold)
+}
+
+// checkCompression возвращает ошибку, если последнее полученное сообщение не сжато и больше threshold
+func checkCompression(ctx context.Context, threshold int) error {
+	if threshold <= 0 {
+		return nil
+	}
+	info, ok := ctx.Value(compressionInfoKey{}).(*compressionInfo)
+	if !ok {
+		return nil
+	}
+	if c := info.compression.Load(); c != nil && *c != "" && *c != "identity" {
+		return nil
+	}
+	if size := info.lastSize.Load(); size > int64(threshold) {
+		return status.Errorf(codes.ResourceExhausted,
+			"uncompressed message of %d bytes exceeds %d bytes, use grpc-encoding gzip or zstd", size, threshold)
+	}
+	return nil
+}
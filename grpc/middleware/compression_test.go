@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// responseCompression records the compression of responses received by a client.
+type responseCompression struct {
+	mu          sync.Mutex
+	compression string
+}
+
+func (r *responseCompression) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (r *responseCompression) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if h, ok := s.(*stats.InHeader); ok && h.Client {
+		r.mu.Lock()
+		r.compression = h.Compression
+		r.mu.Unlock()
+	}
+}
+
+func (r *responseCompression) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (r *responseCompression) HandleConn(context.Context, stats.ConnStats) {}
+
+func (r *responseCompression) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.compression
+}
+
+// startCompressionServer serves the health service over bufconn with the given server options.
+func startCompressionServer(t *testing.T, client stats.Handler, opts ...grpc.ServerOption) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(client),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+// TestZstdCompressor tests that the registered zstd compressor round-trips data and reuses coders.
+func TestZstdCompressor(t *testing.T) {
+	t.Parallel()
+	c := encoding.GetCompressor(CompressorZstd)
+	require.NotNil(t, c)
+	require.NotNil(t, encoding.GetCompressor(CompressorGzip))
+
+	for range 3 {
+		data := bytes.Repeat([]byte("compressible payload "), 1000)
+		var buf bytes.Buffer
+		w, err := c.Compress(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		assert.Less(t, buf.Len(), len(data))
+
+		r, err := c.Decompress(&buf)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	}
+}
+
+// TestRequireCompressionUnaryInterceptor tests that large uncompressed requests are rejected.
+func TestRequireCompressionUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+	client := startCompressionServer(t, &responseCompression{},
+		grpc.StatsHandler(CompressionStatsHandler()),
+		grpc.UnaryInterceptor(RequireCompressionUnaryInterceptor(1024)),
+	)
+	ctx := context.Background()
+	large := &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 2048)}
+
+	// small uncompressed request reaches the handler: unknown service
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "small"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Check(ctx, large)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "uncompressed message")
+
+	for _, name := range []string{CompressorGzip, CompressorZstd} {
+		_, err = client.Check(ctx, large, grpc.UseCompressor(name))
+		assert.Equal(t, codes.NotFound, status.Code(err), name)
+	}
+}
+
+// TestRequireCompressionStreamInterceptor tests that every received stream message is checked.
+func TestRequireCompressionStreamInterceptor(t *testing.T) {
+	t.Parallel()
+	client := startCompressionServer(t, &responseCompression{},
+		grpc.StatsHandler(CompressionStatsHandler()),
+		grpc.StreamInterceptor(RequireCompressionStreamInterceptor(1024)),
+	)
+	ctx := context.Background()
+	large := &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 2048)}
+
+	stream, err := client.Watch(ctx, large)
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	stream, err = client.Watch(ctx, large, grpc.UseCompressor(CompressorZstd))
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.GetStatus())
+}
+
+// TestRequireCompression_WithoutStatsHandler tests that requests pass when compression is unknown.
+func TestRequireCompression_WithoutStatsHandler(t *testing.T) {
+	t.Parallel()
+	client := startCompressionServer(t, &responseCompression{},
+		grpc.UnaryInterceptor(RequireCompressionUnaryInterceptor(1024)),
+	)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 2048)})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestSendCompressorUnaryInterceptor tests that responses use the preferred compressor.
+func TestSendCompressorUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+	recorder := &responseCompression{}
+	client := startCompressionServer(t, recorder,
+		grpc.UnaryInterceptor(SendCompressorUnaryInterceptor(CompressorZstd)),
+	)
+	ctx := context.Background()
+
+	// clients advertise registered compressors in grpc-accept-encoding
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, CompressorZstd, recorder.get())
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.UseCompressor(CompressorGzip))
+	require.NoError(t, err)
+	assert.Equal(t, CompressorZstd, recorder.get())
+}
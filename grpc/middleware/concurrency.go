@@ -0,0 +1,329 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	grpcerrors "github.com/pure-golang/adapters/grpc/errors"
+)
+
+// concurrencyScopeGlobal — значение атрибута limit для общего лимита
+const concurrencyScopeGlobal = "global"
+
+// DefaultConcurrencyRetryDelay — задержка повтора в RetryInfo отклонённых запросов по умолчанию
+const DefaultConcurrencyRetryDelay = time.Second
+
+// retryPushbackHeader — трейлер, по которому встроенные повторы gRPC (retryPolicy) откладывают следующую попытку
+const retryPushbackHeader = "grpc-retry-pushback-ms"
+
+// ConcurrencyOption настраивает ConcurrencyLimiter
+type ConcurrencyOption func(*concurrencyConfig)
+
+// WithMethodConcurrencyLimit ограничивает число одновременных запросов метода (полное имя "/pkg.Service/Method").
+// Лимит метода действует вместе с общим: запрос занимает место в обоих.
+func WithMethodConcurrencyLimit(method string, limit int) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.methodLimits[method] = limit
+	}
+}
+
+// WithConcurrencyQueueTimeout задаёт время ожидания свободного места при насыщении.
+// 0 (по умолчанию) — запрос отклоняется сразу.
+func WithConcurrencyQueueTimeout(timeout time.Duration) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.queueTimeout = timeout
+	}
+}
+
+// WithConcurrencyExemptMethods задаёт методы без ограничения, заменяя значения по умолчанию
+// (Check и Watch сервиса grpc.health.v1.Health: проверки здоровья не должны отклоняться под нагрузкой,
+// а долгоживущий Watch — занимать место)
+func WithConcurrencyExemptMethods(methods ...string) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.exempt = make(map[string]bool, len(methods))
+		for _, method := range methods {
+			c.exempt[method] = true
+		}
+	}
+}
+
+// WithConcurrencyRetryDelay задаёт задержку повтора, которую отклонённый запрос получает в детали
+// google.rpc.RetryInfo и трейлере grpc-retry-pushback-ms (DefaultConcurrencyRetryDelay по умолчанию).
+// 0 — без RetryInfo и трейлера
+func WithConcurrencyRetryDelay(delay time.Duration) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.retryDelay = delay
+	}
+}
+
+// WithConcurrencyMeterProvider задаёт MeterProvider для метрик ограничителя
+// вместо глобального otel.GetMeterProvider()
+func WithConcurrencyMeterProvider(provider metric.MeterProvider) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.meterProvider = provider
+	}
+}
+
+type concurrencyConfig struct {
+	methodLimits  map[string]int
+	queueTimeout  time.Duration
+	retryDelay    time.Duration
+	exempt        map[string]bool
+	meterProvider metric.MeterProvider
+}
+
+// semaphore ограничивает число одновременных владельцев
+type semaphore chan struct{}
+
+// acquire занимает место, ожидая не дольше timeout; false — место не освободилось
+func (s semaphore) acquire(ctx context.Context, timeout time.Duration) (bool, error) {
+	select {
+	case s <- struct{}{}:
+		return true, nil
+	default:
+	}
+	if timeout <= 0 {
+		return false, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case s <- struct{}{}:
+		return true, nil
+	case <-timer.C:
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
+
+// ConcurrencyLimiter ограничивает число одновременно обрабатываемых запросов (load shedding),
+// защищая ресурсы за сервером, например пул соединений с БД.
+// При насыщении запрос ждёт свободного места не дольше QueueTimeout и отклоняется
+// с codes.ResourceExhausted. Потоковый запрос занимает место до завершения потока.
+//
+// Ошибка отказа содержит детали google.rpc.QuotaFailure (subject "concurrency:global" или
+// "concurrency:<метод>") и google.rpc.RetryInfo с задержкой повтора (см. WithConcurrencyRetryDelay),
+// а ответ — трейлер grpc-retry-pushback-ms: клиенты со встроенными повторами gRPC и клиенты,
+// читающие RetryInfo (grpcerrors.RetryDelay), откладывают повтор, а не усиливают перегрузку.
+//
+// Метрики:
+//   - grpc.server.concurrency.in_flight — текущее число запросов (атрибут limit: "global" или метод)
+//   - grpc.server.concurrency.rejected_total — отклонённые запросы (атрибуты grpc.method, limit)
+//
+// Время ожидания места записывается в grpc.server.queue_duration_ms интерцептором метрик
+// ([ServerMetrics]), если он стоит в цепочке раньше ограничителя, как в BuildChain.
+type ConcurrencyLimiter struct {
+	global       semaphore
+	methods      map[string]semaphore
+	limits       map[string]int
+	queueTimeout time.Duration
+	retryDelay   time.Duration
+	exempt       map[string]bool
+	rejected     metric.Int64Counter
+}
+
+// NewConcurrencyLimiter создаёт ограничитель с общим лимитом limit; limit <= 0 — без общего лимита,
+// действуют только лимиты методов.
+// Ошибка создания метрик передаётся в otel.Handle, ограничитель работает без них.
+func NewConcurrencyLimiter(limit int, opts ...ConcurrencyOption) *ConcurrencyLimiter {
+	cfg := &concurrencyConfig{
+		methodLimits: make(map[string]int),
+		retryDelay:   DefaultConcurrencyRetryDelay,
+		exempt: map[string]bool{
+			grpc_health_v1.Health_Check_FullMethodName: true,
+			grpc_health_v1.Health_Watch_FullMethodName: true,
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	l := &ConcurrencyLimiter{
+		methods:      make(map[string]semaphore, len(cfg.methodLimits)),
+		limits:       make(map[string]int, len(cfg.methodLimits)+1),
+		queueTimeout: cfg.queueTimeout,
+		retryDelay:   cfg.retryDelay,
+		exempt:       cfg.exempt,
+	}
+	if limit > 0 {
+		l.global = make(semaphore, limit)
+		l.limits[concurrencyScopeGlobal] = limit
+	}
+	for method, methodLimit := range cfg.methodLimits {
+		if methodLimit > 0 {
+			l.methods[method] = make(semaphore, methodLimit)
+			l.limits[method] = methodLimit
+		}
+	}
+
+	m := meter
+	if cfg.meterProvider != nil {
+		m = cfg.meterProvider.Meter("github.com/pure-golang/adapters/grpc")
+	}
+	if err := l.initMetrics(m); err != nil {
+		otel.Handle(err)
+	}
+	return l
+}
+
+// initMetrics создаёт счётчик отказов и наблюдаемый gauge текущих запросов
+func (l *ConcurrencyLimiter) initMetrics(m metric.Meter) error {
+	rejected, err := m.Int64Counter(
+		"grpc.server.concurrency.rejected_total",
+		metric.WithDescription("Total number of gRPC requests rejected by the concurrency limiter"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create rejected requests counter")
+	}
+	l.rejected = rejected
+
+	_, err = m.Int64ObservableGauge(
+		"grpc.server.concurrency.in_flight",
+		metric.WithDescription("Number of gRPC requests currently holding a concurrency limit slot"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			if l.global != nil {
+				o.Observe(int64(len(l.global)), metric.WithAttributes(attribute.String("limit", concurrencyScopeGlobal)))
+			}
+			for method, s := range l.methods {
+				o.Observe(int64(len(s)), metric.WithAttributes(attribute.String("limit", method)))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create in-flight requests gauge")
+	}
+	return nil
+}
+
+// InFlight возвращает число запросов, занимающих место в общем лимите
+func (l *ConcurrencyLimiter) InFlight() int {
+	return len(l.global)
+}
+
+// UnaryServerInterceptor возвращает интерцептор, ограничивающий унарные запросы
+func (l *ConcurrencyLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := l.acquire(ctx, info.FullMethod)
+		if err != nil {
+			setRetryPushback(ctx, err)
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor возвращает интерцептор, ограничивающий потоковые запросы
+func (l *ConcurrencyLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := l.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			setRetryPushback(ss.Context(), err)
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// acquire занимает место в лимите метода, затем в общем.
+// Лимит метода занимается первым, чтобы запросы перегруженного метода не держали общий лимит в очереди.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, method string) (func(), error) {
+	if l.exempt[method] {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	var deadline time.Time
+	if l.queueTimeout > 0 {
+		deadline = start.Add(l.queueTimeout)
+	}
+
+	methodSem := l.methods[method]
+	if methodSem != nil {
+		if err := l.wait(ctx, methodSem, deadline, method, method); err != nil {
+			recordQueueTime(ctx, time.Since(start), false)
+			return nil, err
+		}
+	}
+	if l.global != nil {
+		if err := l.wait(ctx, l.global, deadline, method, concurrencyScopeGlobal); err != nil {
+			if methodSem != nil {
+				methodSem.release()
+			}
+			recordQueueTime(ctx, time.Since(start), false)
+			return nil, err
+		}
+	}
+	recordQueueTime(ctx, time.Since(start), true)
+
+	return func() {
+		if l.global != nil {
+			l.global.release()
+		}
+		if methodSem != nil {
+			methodSem.release()
+		}
+	}, nil
+}
+
+// wait занимает место в s до deadline и возвращает статус отказа
+func (l *ConcurrencyLimiter) wait(ctx context.Context, s semaphore, deadline time.Time, method, scope string) error {
+	var timeout time.Duration
+	if !deadline.IsZero() {
+		timeout = time.Until(deadline)
+	}
+	ok, err := s.acquire(ctx, timeout)
+	if err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if ok {
+		return nil
+	}
+
+	if l.rejected != nil {
+		l.rejected.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("grpc.method", method),
+			attribute.String("limit", scope),
+		))
+	}
+	return l.rejection(scope)
+}
+
+// rejection возвращает статус отказа с QuotaFailure и RetryInfo
+func (l *ConcurrencyLimiter) rejection(scope string) error {
+	err := status.Errorf(codes.ResourceExhausted, "server is overloaded: %s concurrency limit reached", scope)
+	err = grpcerrors.WithQuotaFailure(err, "concurrency:"+scope,
+		fmt.Sprintf("more than %d concurrent requests", l.limits[scope]))
+	if l.retryDelay > 0 {
+		err = grpcerrors.WithRetryDelay(err, l.retryDelay)
+	}
+	return err
+}
+
+// setRetryPushback передаёт задержку повтора из RetryInfo ошибки в трейлере grpc-retry-pushback-ms.
+// Вне gRPC сервера (контекст без транспортного потока) трейлер не устанавливается
+func setRetryPushback(ctx context.Context, err error) {
+	if delay, ok := grpcerrors.RetryDelay(err); ok {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(retryPushbackHeader, strconv.FormatInt(delay.Milliseconds(), 10)))
+	}
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	grpcerrors "github.com/pure-golang/adapters/grpc/errors"
+)
+
+// blockingCalls starts n unary calls of method that block until release is closed.
+func blockingCalls(t *testing.T, interceptor grpc.UnaryServerInterceptor, method string, n int) (release func(), wait func()) {
+	t.Helper()
+	block := make(chan struct{})
+	started := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+				func(context.Context, any) (any, error) {
+					started <- struct{}{}
+					<-block
+					return "ok", nil
+				})
+			assert.NoError(t, err)
+		}()
+	}
+	for range n {
+		<-started
+	}
+	return func() { close(block) }, wg.Wait
+}
+
+func callLimited(interceptor grpc.UnaryServerInterceptor, method string) error {
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	return err
+}
+
+// TestConcurrencyLimiter_Global tests that requests above the global limit are rejected.
+func TestConcurrencyLimiter_Global(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	limiter := NewConcurrencyLimiter(2, WithConcurrencyMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	interceptor := limiter.UnaryServerInterceptor()
+
+	release, wait := blockingCalls(t, interceptor, "/svc/Slow", 2)
+	assert.Equal(t, 2, limiter.InFlight())
+
+	err := callLimited(interceptor, "/svc/Fast")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "global concurrency limit")
+
+	// health checks are exempt
+	require.NoError(t, callLimited(interceptor, grpc_health_v1.Health_Check_FullMethodName))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	inFlight := metrics["grpc.server.concurrency.in_flight"].Data.(metricdata.Gauge[int64])
+	require.Len(t, inFlight.DataPoints, 1)
+	assert.Equal(t, int64(2), inFlight.DataPoints[0].Value)
+	rejected := metrics["grpc.server.concurrency.rejected_total"].Data.(metricdata.Sum[int64])
+	require.Len(t, rejected.DataPoints, 1)
+	assert.Equal(t, int64(1), rejected.DataPoints[0].Value)
+
+	release()
+	wait()
+	assert.Equal(t, 0, limiter.InFlight())
+	require.NoError(t, callLimited(interceptor, "/svc/Fast"))
+}
+
+// TestConcurrencyLimiter_Method tests per-method limits.
+func TestConcurrencyLimiter_Method(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(0, WithMethodConcurrencyLimit("/svc/Report", 1))
+	interceptor := limiter.UnaryServerInterceptor()
+
+	release, wait := blockingCalls(t, interceptor, "/svc/Report", 1)
+	defer wait()
+	defer release()
+
+	err := callLimited(interceptor, "/svc/Report")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "/svc/Report concurrency limit")
+
+	// other methods are not limited
+	require.NoError(t, callLimited(interceptor, "/svc/Other"))
+}
+
+// TestConcurrencyLimiter_Queue tests waiting for a free slot.
+func TestConcurrencyLimiter_Queue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("slot is freed before timeout", func(t *testing.T) {
+		t.Parallel()
+		limiter := NewConcurrencyLimiter(1, WithConcurrencyQueueTimeout(time.Second))
+		interceptor := limiter.UnaryServerInterceptor()
+
+		release, wait := blockingCalls(t, interceptor, "/svc/Slow", 1)
+		time.AfterFunc(20*time.Millisecond, release)
+		require.NoError(t, callLimited(interceptor, "/svc/Fast"))
+		wait()
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		limiter := NewConcurrencyLimiter(1, WithConcurrencyQueueTimeout(20*time.Millisecond))
+		interceptor := limiter.UnaryServerInterceptor()
+
+		release, wait := blockingCalls(t, interceptor, "/svc/Slow", 1)
+		defer wait()
+		defer release()
+
+		start := time.Now()
+		err := callLimited(interceptor, "/svc/Fast")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("context canceled while queued", func(t *testing.T) {
+		t.Parallel()
+		limiter := NewConcurrencyLimiter(1, WithConcurrencyQueueTimeout(time.Minute))
+		interceptor := limiter.UnaryServerInterceptor()
+
+		release, wait := blockingCalls(t, interceptor, "/svc/Slow", 1)
+		defer wait()
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Fast"},
+			func(context.Context, any) (any, error) { return "ok", nil })
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+}
+
+// TestConcurrencyLimiter_MethodReleasedOnGlobalRejection tests that a method slot is returned
+// when the global limit rejects the request.
+func TestConcurrencyLimiter_MethodReleasedOnGlobalRejection(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(1, WithMethodConcurrencyLimit("/svc/Report", 1))
+	interceptor := limiter.UnaryServerInterceptor()
+
+	release, wait := blockingCalls(t, interceptor, "/svc/Other", 1)
+	err := callLimited(interceptor, "/svc/Report")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	release()
+	wait()
+
+	require.NoError(t, callLimited(interceptor, "/svc/Report"))
+}
+
+// TestConcurrencyLimiter_Stream tests that a stream holds its slot until it finishes.
+func TestConcurrencyLimiter_Stream(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(1)
+	interceptor := limiter.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}
+	stream := &mockServerStream{ctx: context.Background()}
+
+	err := interceptor(nil, stream, info, func(any, grpc.ServerStream) error {
+		assert.Equal(t, 1, limiter.InFlight())
+		err := interceptor(nil, stream, info, func(any, grpc.ServerStream) error { return nil })
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, limiter.InFlight())
+}
+
+// trailerStream records trailers set through grpc.SetTrailer.
+type trailerStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string { return "/svc/Report" }
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// TestConcurrencyLimiter_RetryInfo tests the RetryInfo and QuotaFailure details and the retry pushback trailer of rejections.
+func TestConcurrencyLimiter_RetryInfo(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(0, WithMethodConcurrencyLimit("/svc/Report", 1), WithConcurrencyRetryDelay(250*time.Millisecond))
+	interceptor := limiter.UnaryServerInterceptor()
+
+	release, wait := blockingCalls(t, interceptor, "/svc/Report", 1)
+	defer wait()
+	defer release()
+
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Report"},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	delay, ok := grpcerrors.RetryDelay(err)
+	require.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, delay)
+	violations := grpcerrors.QuotaViolations(err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "concurrency:/svc/Report", violations[0].GetSubject())
+	assert.Equal(t, "more than 1 concurrent requests", violations[0].GetDescription())
+	assert.Equal(t, []string{"250"}, stream.trailer.Get("grpc-retry-pushback-ms"))
+
+	// without retry delay only QuotaFailure is attached
+	limiter = NewConcurrencyLimiter(0, WithMethodConcurrencyLimit("/svc/Report", 1), WithConcurrencyRetryDelay(0))
+	interceptor = limiter.UnaryServerInterceptor()
+	releaseSecond, waitSecond := blockingCalls(t, interceptor, "/svc/Report", 1)
+	defer waitSecond()
+	defer releaseSecond()
+
+	stream = &trailerStream{}
+	ctx = grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Report"},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	_, ok = grpcerrors.RetryDelay(err)
+	assert.False(t, ok)
+	assert.Len(t, grpcerrors.QuotaViolations(err), 1)
+	assert.Empty(t, stream.trailer)
+}
@@ -0,0 +1,308 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc/stats"
+)
+
+// ConnAttribute — атрибут соединения в логах и метриках ConnectionStatsHandler
+type ConnAttribute string
+
+const (
+	// ConnAttributeRemoteAddr — адрес клиента (host:port); только в логах
+	ConnAttributeRemoteAddr ConnAttribute = "remote_addr"
+	// ConnAttributeRemoteIP — IP клиента без порта; только в логах
+	ConnAttributeRemoteIP ConnAttribute = "remote_ip"
+	// ConnAttributeLocalAddr — адрес, на котором сервер принял соединение
+	ConnAttributeLocalAddr ConnAttribute = "local_addr"
+	// ConnAttributeNetwork — тип сети (tcp, unix)
+	ConnAttributeNetwork ConnAttribute = "network"
+)
+
+// ConnStatsOption настраивает ConnectionStatsHandler
+type ConnStatsOption func(*connStatsConfig)
+
+// WithConnStatsLogger задаёт логгер событий соединений; без логгера записываются только метрики
+func WithConnStatsLogger(logger *slog.Logger) ConnStatsOption {
+	return func(c *connStatsConfig) {
+		c.logger = logger
+	}
+}
+
+// WithConnStatsSampling задаёт долю соединений (0..1), открытие и закрытие которых логируется.
+// Решение принимается при открытии соединения, поэтому пара записей не разрывается.
+// Закрытие с транспортными ошибками логируется всегда; метрики записываются для всех соединений.
+func WithConnStatsSampling(rate float64) ConnStatsOption {
+	return func(c *connStatsConfig) {
+		c.sampling = min(max(rate, 0), 1)
+	}
+}
+
+// WithConnStatsAttributes задаёт атрибуты соединения, заменяя набор по умолчанию
+// (ConnAttributeRemoteAddr, ConnAttributeLocalAddr). В метрики попадают только
+// ConnAttributeLocalAddr и ConnAttributeNetwork: адреса клиентов неограниченны по кардинальности.
+func WithConnStatsAttributes(attrs ...ConnAttribute) ConnStatsOption {
+	return func(c *connStatsConfig) {
+		c.attributes = attrs
+	}
+}
+
+// WithConnStatsMeterProvider задаёт MeterProvider для метрик соединений
+// вместо глобального otel.GetMeterProvider()
+func WithConnStatsMeterProvider(provider metric.MeterProvider) ConnStatsOption {
+	return func(c *connStatsConfig) {
+		c.meterProvider = provider
+	}
+}
+
+type connStatsConfig struct {
+	logger        *slog.Logger
+	sampling      float64
+	attributes    []ConnAttribute
+	meterProvider metric.MeterProvider
+}
+
+// connStatsKey — ключ контекста для состояния соединения
+type connStatsKey struct{}
+
+// connState — состояние соединения, общее для его RPC
+type connState struct {
+	start   time.Time
+	sampled bool
+	logArgs []any
+	attrs   metric.MeasurementOption
+
+	rpcs   atomic.Int64
+	active atomic.Int64
+}
+
+// connStatsHandler реализует stats.Handler для событий соединений
+type connStatsHandler struct {
+	cfg *connStatsConfig
+
+	opened          metric.Int64Counter
+	closed          metric.Int64Counter
+	active          metric.Int64UpDownCounter
+	duration        metric.Float64Histogram
+	rpcsPerConn     metric.Int64Histogram
+	transportErrors metric.Int64Counter
+}
+
+// ConnectionStatsHandler возвращает stats.Handler, записывающий события жизненного цикла соединений.
+//
+// Метрики:
+//   - grpc.server.connections.opened_total и grpc.server.connections.closed_total — открытые и закрытые соединения
+//   - grpc.server.connections.active — текущее число соединений
+//   - grpc.server.connection.duration_s — время жизни соединения
+//   - grpc.server.connection.rpcs — число RPC за время жизни соединения
+//   - grpc.server.connection.transport_errors_total — RPC, прерванные закрытием соединения
+//     (разрыв связи, GOAWAY с незавершёнными запросами)
+//
+// Открытие соединения логируется на уровне Debug, закрытие — на уровне Info с числом RPC
+// и временем жизни, закрытие с транспортными ошибками — на уровне Warn.
+// Ошибка создания метрик передаётся в otel.Handle, обработчик работает без них.
+//
+//	grpc.NewServer(grpc.StatsHandler(middleware.ConnectionStatsHandler(
+//	    middleware.WithConnStatsLogger(logger),
+//	    middleware.WithConnStatsSampling(0.1),
+//	)))
+func ConnectionStatsHandler(opts ...ConnStatsOption) stats.Handler {
+	cfg := &connStatsConfig{
+		sampling:   1,
+		attributes: []ConnAttribute{ConnAttributeRemoteAddr, ConnAttributeLocalAddr},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	m := meter
+	if cfg.meterProvider != nil {
+		m = cfg.meterProvider.Meter("github.com/pure-golang/adapters/grpc")
+	}
+	h := &connStatsHandler{cfg: cfg}
+	if err := h.initMetrics(m); err != nil {
+		otel.Handle(err)
+		h.noopMetrics()
+	}
+	return h
+}
+
+// initMetrics создаёт инструменты метрик соединений
+func (h *connStatsHandler) initMetrics(m metric.Meter) error {
+	var err error
+	h.opened, err = m.Int64Counter(
+		"grpc.server.connections.opened_total",
+		metric.WithDescription("Total number of accepted gRPC connections"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create opened connections counter")
+	}
+	h.closed, err = m.Int64Counter(
+		"grpc.server.connections.closed_total",
+		metric.WithDescription("Total number of closed gRPC connections"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create closed connections counter")
+	}
+	h.active, err = m.Int64UpDownCounter(
+		"grpc.server.connections.active",
+		metric.WithDescription("Number of open gRPC connections"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create active connections counter")
+	}
+	h.duration, err = m.Float64Histogram(
+		"grpc.server.connection.duration_s",
+		metric.WithDescription("gRPC connection lifetime in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create connection duration histogram")
+	}
+	h.rpcsPerConn, err = m.Int64Histogram(
+		"grpc.server.connection.rpcs",
+		metric.WithDescription("Number of RPCs served over a gRPC connection"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create RPCs per connection histogram")
+	}
+	h.transportErrors, err = m.Int64Counter(
+		"grpc.server.connection.transport_errors_total",
+		metric.WithDescription("Total number of RPCs aborted by gRPC connection close"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create transport errors counter")
+	}
+	return nil
+}
+
+// noopMetrics заменяет инструменты заглушками
+func (h *connStatsHandler) noopMetrics() {
+	h.opened = noop.Int64Counter{}
+	h.closed = noop.Int64Counter{}
+	h.active = noop.Int64UpDownCounter{}
+	h.duration = noop.Float64Histogram{}
+	h.rpcsPerConn = noop.Int64Histogram{}
+	h.transportErrors = noop.Int64Counter{}
+}
+
+func (h *connStatsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	state := &connState{
+		start:   time.Now(),
+		sampled: h.cfg.sampling >= 1 || (h.cfg.sampling > 0 && rand.Float64() < h.cfg.sampling),
+	}
+
+	var metricAttrs []attribute.KeyValue
+	for _, attr := range h.cfg.attributes {
+		value := connAttributeValue(attr, info)
+		if value == "" {
+			continue
+		}
+		state.logArgs = append(state.logArgs, slog.String(string(attr), value))
+		if attr == ConnAttributeLocalAddr || attr == ConnAttributeNetwork {
+			metricAttrs = append(metricAttrs, attribute.String(string(attr), value))
+		}
+	}
+	state.attrs = metric.WithAttributes(metricAttrs...)
+
+	return context.WithValue(ctx, connStatsKey{}, state)
+}
+
+func (h *connStatsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	state, ok := ctx.Value(connStatsKey{}).(*connState)
+	if !ok {
+		return
+	}
+
+	switch s.(type) {
+	case *stats.ConnBegin:
+		h.opened.Add(ctx, 1, state.attrs)
+		h.active.Add(ctx, 1, state.attrs)
+		if h.cfg.logger != nil && state.sampled {
+			h.cfg.logger.DebugContext(ctx, "gRPC connection opened", state.logArgs...)
+		}
+	case *stats.ConnEnd:
+		duration := time.Since(state.start)
+		rpcs := state.rpcs.Load()
+		// RPC, не завершившиеся к закрытию соединения, прерваны его потерей
+		transportErrors := state.active.Load()
+
+		h.closed.Add(ctx, 1, state.attrs)
+		h.active.Add(ctx, -1, state.attrs)
+		h.duration.Record(ctx, duration.Seconds(), state.attrs)
+		h.rpcsPerConn.Record(ctx, rpcs, state.attrs)
+		if transportErrors > 0 {
+			h.transportErrors.Add(ctx, transportErrors, state.attrs)
+		}
+
+		if h.cfg.logger == nil || (!state.sampled && transportErrors == 0) {
+			return
+		}
+		logArgs := append([]any{
+			slog.Duration("duration", duration),
+			slog.Int64("rpcs", rpcs),
+		}, state.logArgs...)
+		if transportErrors > 0 {
+			logArgs = append(logArgs, slog.Int64("transport_errors", transportErrors))
+			h.cfg.logger.WarnContext(ctx, "gRPC connection closed with transport errors", logArgs...)
+			return
+		}
+		h.cfg.logger.InfoContext(ctx, "gRPC connection closed", logArgs...)
+	}
+}
+
+func (h *connStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *connStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	state, ok := ctx.Value(connStatsKey{}).(*connState)
+	if !ok {
+		return
+	}
+
+	switch s.(type) {
+	case *stats.Begin:
+		state.rpcs.Add(1)
+		state.active.Add(1)
+	case *stats.End:
+		state.active.Add(-1)
+	}
+}
+
+// connAttributeValue возвращает значение атрибута соединения
+func connAttributeValue(attr ConnAttribute, info *stats.ConnTagInfo) string {
+	switch attr {
+	case ConnAttributeRemoteAddr:
+		if info.RemoteAddr != nil {
+			return info.RemoteAddr.String()
+		}
+	case ConnAttributeRemoteIP:
+		if info.RemoteAddr != nil {
+			if host, _, err := net.SplitHostPort(info.RemoteAddr.String()); err == nil {
+				return host
+			}
+			return info.RemoteAddr.String()
+		}
+	case ConnAttributeLocalAddr:
+		if info.LocalAddr != nil {
+			return info.LocalAddr.String()
+		}
+	case ConnAttributeNetwork:
+		if info.LocalAddr != nil {
+			return info.LocalAddr.Network()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc/stats"
+)
+
+// connStatsInfo returns the tag info of a TCP connection
+func connStatsInfo() *stats.ConnTagInfo {
+	return &stats.ConnTagInfo{
+		RemoteAddr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 51234},
+		LocalAddr:  &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 9090},
+	}
+}
+
+// simulateConn drives h through a connection serving completed RPCs and inFlight unfinished ones
+func simulateConn(h stats.Handler, completed, inFlight int) {
+	ctx := h.TagConn(context.Background(), connStatsInfo())
+	h.HandleConn(ctx, &stats.ConnBegin{})
+	for range completed {
+		rpcCtx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/svc/Method"})
+		h.HandleRPC(rpcCtx, &stats.Begin{})
+		h.HandleRPC(rpcCtx, &stats.End{})
+	}
+	for range inFlight {
+		rpcCtx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/svc/Stream"})
+		h.HandleRPC(rpcCtx, &stats.Begin{})
+	}
+	h.HandleConn(ctx, &stats.ConnEnd{})
+}
+
+// connMetrics returns sums of counters and counts of histograms by metric name
+func connMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					result[m.Name] += dp.Value
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					result[m.Name] += dp.Sum
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					result[m.Name] += int64(dp.Count)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// TestConnectionStatsHandler_Metrics tests connection lifecycle metrics
+func TestConnectionStatsHandler_Metrics(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	h := ConnectionStatsHandler(WithConnStatsMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	simulateConn(h, 3, 0)
+	simulateConn(h, 1, 2)
+
+	assert.Equal(t, map[string]int64{
+		"grpc.server.connections.opened_total":          2,
+		"grpc.server.connections.closed_total":          2,
+		"grpc.server.connections.active":                0,
+		"grpc.server.connection.duration_s":             2,
+		"grpc.server.connection.rpcs":                   6,
+		"grpc.server.connection.transport_errors_total": 2,
+	}, connMetrics(t, reader))
+}
+
+// TestConnectionStatsHandler_Logs tests connection logs and the attribute set
+func TestConnectionStatsHandler_Logs(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	h := ConnectionStatsHandler(
+		WithConnStatsLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithConnStatsAttributes(ConnAttributeRemoteIP, ConnAttributeNetwork),
+	)
+
+	simulateConn(h, 2, 0)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `level=DEBUG msg="gRPC connection opened" remote_ip=10.0.0.7 network=tcp`)
+	assert.Contains(t, logs, `level=INFO msg="gRPC connection closed"`)
+	assert.Contains(t, logs, "rpcs=2")
+	assert.NotContains(t, logs, "remote_addr")
+	assert.NotContains(t, logs, "local_addr")
+}
+
+// TestConnectionStatsHandler_Sampling tests that unsampled connections log only transport errors
+func TestConnectionStatsHandler_Sampling(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	h := ConnectionStatsHandler(
+		WithConnStatsLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithConnStatsSampling(0),
+	)
+
+	simulateConn(h, 1, 0)
+	assert.Empty(t, buf.String())
+
+	simulateConn(h, 0, 1)
+	assert.Contains(t, buf.String(), `level=WARN msg="gRPC connection closed with transport errors"`)
+	assert.Contains(t, buf.String(), "transport_errors=1")
+	assert.NotContains(t, buf.String(), "connection opened")
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeadlineBudgetOption настраивает интерцепторы бюджета дедлайна
+type DeadlineBudgetOption func(*deadlineBudgetConfig)
+
+// WithDefaultDeadline задаёт таймаут для запросов без дедлайна.
+// По умолчанию такие запросы выполняются без дедлайна и без бюджета.
+func WithDefaultDeadline(timeout time.Duration) DeadlineBudgetOption {
+	return func(c *deadlineBudgetConfig) {
+		c.defaultTimeout = timeout
+	}
+}
+
+// WithMinDeadlineBudget задаёт минимальный бюджет: если после вычета запаса остаётся меньше,
+// запрос сразу отклоняется с codes.DeadlineExceeded. По умолчанию отклоняются запросы без остатка.
+func WithMinDeadlineBudget(minBudget time.Duration) DeadlineBudgetOption {
+	return func(c *deadlineBudgetConfig) {
+		c.minBudget = minBudget
+	}
+}
+
+type deadlineBudgetConfig struct {
+	defaultTimeout time.Duration
+	minBudget      time.Duration
+}
+
+func newDeadlineBudgetConfig(opts []DeadlineBudgetOption) deadlineBudgetConfig {
+	var cfg deadlineBudgetConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+type deadlineBudgetKey struct{}
+
+// DeadlineBudget возвращает время, оставшееся на обработку запроса и исходящие вызовы,
+// если бюджет установлен интерцептором DeadlineBudget*
+func DeadlineBudget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Value(deadlineBudgetKey{}).(time.Time)
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// DeadlineBudgetUnaryServerInterceptor сокращает дедлайн входящего запроса на margin — запас на
+// формирование и отправку ответа — и сохраняет оставшийся бюджет в контексте (DeadlineBudget).
+// Исходящие вызовы с этим контекстом получают сокращённый дедлайн и не переживают вызывающую сторону.
+// Запрос, бюджет которого уже исчерпан, отклоняется с codes.DeadlineExceeded без вызова обработчика.
+func DeadlineBudgetUnaryServerInterceptor(margin time.Duration, opts ...DeadlineBudgetOption) grpc.UnaryServerInterceptor {
+	cfg := newDeadlineBudgetConfig(opts)
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		budgetCtx, cancel, err := withDeadlineBudget(ctx, margin, cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+		return handler(budgetCtx, req)
+	}
+}
+
+// DeadlineBudgetStreamServerInterceptor — потоковый вариант DeadlineBudgetUnaryServerInterceptor
+func DeadlineBudgetStreamServerInterceptor(margin time.Duration, opts ...DeadlineBudgetOption) grpc.StreamServerInterceptor {
+	cfg := newDeadlineBudgetConfig(opts)
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		budgetCtx, cancel, err := withDeadlineBudget(ss.Context(), margin, cfg)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: budgetCtx})
+	}
+}
+
+// DeadlineBudgetUnaryClientInterceptor сокращает дедлайн исходящего вызова на margin — запас на сеть
+// и обработку ответа вызывающей стороной. Вызов, бюджет которого исчерпан, завершается
+// с codes.DeadlineExceeded без отправки запроса.
+func DeadlineBudgetUnaryClientInterceptor(margin time.Duration, opts ...DeadlineBudgetOption) grpc.UnaryClientInterceptor {
+	cfg := newDeadlineBudgetConfig(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		budgetCtx, cancel, err := withDeadlineBudget(ctx, margin, cfg)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+		return invoker(budgetCtx, method, req, reply, cc, callOpts...)
+	}
+}
+
+// DeadlineBudgetStreamClientInterceptor — потоковый вариант DeadlineBudgetUnaryClientInterceptor.
+// Контекст потока освобождается, когда RecvMsg возвращает ошибку или io.EOF, либо по дедлайну.
+func DeadlineBudgetStreamClientInterceptor(margin time.Duration, opts ...DeadlineBudgetOption) grpc.StreamClientInterceptor {
+	cfg := newDeadlineBudgetConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		budgetCtx, cancel, err := withDeadlineBudget(ctx, margin, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		cs, err := streamer(budgetCtx, desc, cc, method, callOpts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return newFinishingClientStream(cs, func(error) { cancel() }), nil
+	}
+}
+
+// withDeadlineBudget возвращает контекст с дедлайном, сокращённым на margin, и бюджетом в значении контекста.
+// Без дедлайна и таймаута по умолчанию контекст возвращается без изменений.
+func withDeadlineBudget(ctx context.Context, margin time.Duration, cfg deadlineBudgetConfig) (context.Context, context.CancelFunc, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		if cfg.defaultTimeout <= 0 {
+			return ctx, func() {}, nil
+		}
+		deadline = time.Now().Add(cfg.defaultTimeout)
+	}
+
+	budgetDeadline := deadline.Add(-margin)
+	if remaining := time.Until(budgetDeadline); remaining <= cfg.minBudget {
+		return nil, nil, status.Errorf(codes.DeadlineExceeded,
+			"deadline budget exhausted: %s left after %s margin", max(remaining, 0), margin)
+	}
+
+	budgetCtx, cancel := context.WithDeadline(ctx, budgetDeadline)
+	return context.WithValue(budgetCtx, deadlineBudgetKey{}, budgetDeadline), cancel, nil
+}
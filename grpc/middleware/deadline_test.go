@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestDeadlineBudgetUnaryServerInterceptor tests that the handler deadline is shrunk by the margin.
+func TestDeadlineBudgetUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+	interceptor := DeadlineBudgetUnaryServerInterceptor(100 * time.Millisecond)
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+
+	t.Run("shrinks deadline", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		callerDeadline, _ := ctx.Deadline()
+
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.Equal(t, callerDeadline.Add(-100*time.Millisecond), deadline)
+
+			budget, ok := DeadlineBudget(ctx)
+			require.True(t, ok)
+			assert.InDelta(t, 900*time.Millisecond, budget, float64(50*time.Millisecond))
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("without deadline", func(t *testing.T) {
+		t.Parallel()
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			_, ok = DeadlineBudget(ctx)
+			assert.False(t, ok)
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("exhausted budget", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		var called bool
+		_, err := interceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+			called = true
+			return nil, nil
+		})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.False(t, called)
+	})
+}
+
+// TestDeadlineBudget_Options tests the default deadline and minimum budget.
+func TestDeadlineBudget_Options(t *testing.T) {
+	t.Parallel()
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+
+	interceptor := DeadlineBudgetUnaryServerInterceptor(10*time.Millisecond, WithDefaultDeadline(time.Second))
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(990*time.Millisecond), deadline, 50*time.Millisecond)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	interceptor = DeadlineBudgetUnaryServerInterceptor(10*time.Millisecond, WithMinDeadlineBudget(time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err = interceptor(ctx, nil, info, func(context.Context, any) (any, error) { return nil, nil })
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+// TestDeadlineBudget_Cascade tests that client calls made from a handler get a shorter deadline.
+func TestDeadlineBudget_Cascade(t *testing.T) {
+	t.Parallel()
+	server := DeadlineBudgetUnaryServerInterceptor(100 * time.Millisecond)
+	client := DeadlineBudgetUnaryClientInterceptor(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	callerDeadline, _ := ctx.Deadline()
+
+	var downstreamDeadline time.Time
+	_, err := server(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}, func(ctx context.Context, _ any) (any, error) {
+		return nil, client(ctx, "/downstream/Method", nil, nil, nil,
+			func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				downstreamDeadline, _ = ctx.Deadline()
+				return nil
+			})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, callerDeadline.Add(-150*time.Millisecond), downstreamDeadline)
+}
+
+// TestDeadlineBudgetStreamInterceptors tests the stream variants.
+func TestDeadlineBudgetStreamInterceptors(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	callerDeadline, _ := ctx.Deadline()
+
+	server := DeadlineBudgetStreamServerInterceptor(100 * time.Millisecond)
+	err := server(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}, func(_ any, ss grpc.ServerStream) error {
+		deadline, _ := ss.Context().Deadline()
+		assert.Equal(t, callerDeadline.Add(-100*time.Millisecond), deadline)
+		return nil
+	})
+	require.NoError(t, err)
+
+	client := DeadlineBudgetStreamClientInterceptor(time.Second)
+	_, err = client(ctx, &grpc.StreamDesc{}, nil, "/svc/Stream",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			t.Fatal("streamer must not be called with an exhausted budget")
+			return nil, nil
+		})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
@@ -1,4 +1,4 @@
-// Package middleware предоставляет интерцепторы для gRPC серверов.
+// Package middleware предоставляет интерцепторы для gRPC серверов и клиентов.
 //
 // Поддерживает:
 //   - OpenTelemetry tracing (распределённая трассировка)
@@ -23,21 +23,158 @@
 //	unary := middleware.TracingUnaryInterceptor()
 //	stream := middleware.TracingStreamInterceptor()
 //
+//	// Tracing с копированием baggage (tenant-id, user-id) в атрибуты span'а и поля лога
+//	unary := middleware.TracingUnaryInterceptor(
+//	    middleware.WithBaggageSpanAttributes(middleware.BaggageTenantID),
+//	    middleware.WithBaggageLogFields(middleware.BaggageTenantID, middleware.BaggageUserID),
+//	)
+//	tenant := middleware.BaggageValue(ctx, middleware.BaggageTenantID)
+//
 //	// Metrics
 //	unary := middleware.MetricsUnaryInterceptor()
 //	stream := middleware.MetricsStreamInterceptor()
 //
-//	// Logging
+//	// Metrics с ограничением кардинальности и своими границами гистограмм
+//	unary := middleware.MetricsUnaryInterceptor(
+//	    middleware.WithDroppedMethods("/grpc.health.v1.Health/Check"),
+//	    middleware.WithMethodLabeler(middleware.ServiceMethodLabeler),
+//	    middleware.WithDurationBuckets(5, 25, 100, 500, 2500),
+//	)
+//
+//	// Metrics с общими инструментами для нескольких серверов (MonitoringOptions.ServerMetrics)
+//	metrics, err := middleware.NewServerMetrics(meterProvider.Meter("svc"))
+//	unary := metrics.UnaryServerInterceptor()
+//	stream := metrics.StreamServerInterceptor()
+//
+//	// Tenant: атрибут tenant в метриках, span'е и логах (MonitoringOptions.TenantExtractor)
+//	extractor := middleware.LimitTenants(middleware.MetadataTenantExtractor("x-tenant-id"), "acme", "globex")
+//	unary := middleware.TenantUnaryInterceptor(extractor) // после Tracing, до Metrics
+//	tenant := middleware.TenantFromContext(ctx)
+//
+//	// RequestInfo: язык, версия клиента и платформа из метаданных (MonitoringOptions.EnableRequestInfo)
+//	unary := middleware.RequestInfoUnaryInterceptor(middleware.WithSupportedLanguages("ru", "en"))
+//	info, _ := middleware.RequestInfoFromContext(ctx)
+//	if info.ClientVersionAtLeast("2.3") { ... }
+//
+//	// PeerInfo: адрес клиента, ALPN и сертификат клиента при mTLS (MonitoringOptions.EnablePeerInfo)
+//	unary := middleware.PeerInfoUnaryInterceptor()
+//	peer, _ := middleware.PeerInfoFromContext(ctx)
+//	if peer.HasClientCert() && slices.Contains(peer.URIs, "spiffe://acme.internal/billing") { ... }
+//
+//	// ClientIP: IP клиента за доверенными прокси из x-forwarded-for/x-real-ip (MonitoringOptions.PeerIPExtractor)
+//	trusted, err := middleware.ParseTrustedProxies([]string{"10.0.0.0/8"})
+//	unary := middleware.ClientIPUnaryInterceptor(middleware.ForwardedPeerIPExtractor(trusted...))
+//	ip, _ := middleware.ClientIPFromContext(ctx) // ключ rate limiter'а, поле аудита
+//
+//	// Logging: trace_id, span_id и request_id (метаданные x-request-id) в записях — observability/correlate;
+//	// request_id сохраняется в контекст обработчика и попадает в логи storage, db и mail
 //	unary := middleware.LoggingInterceptor(logger)
 //	stream := middleware.LoggingStreamInterceptor(logger)
 //
-//	// Recovery
+//	// Recovery (паника передаётся в logger.HandlePanic: стек, хуки logger.OnPanic, нотификаторы)
 //	unary := middleware.RecoveryInterceptor(logger)
-//	stream := middleware.RecoveryStreamInterceptor(logger)
+//	stream := middleware.RecoveryStreamInterceptor(logger, middleware.WithRecoveryReporter(reporter)) // + Sentry
+//
+//	// Error mapping (доменные ошибки → gRPC статусы, см. grpc/errors.Mapper)
+//	unary := middleware.ErrorMappingUnaryInterceptor(grpcerrors.NewDefaultMapper())
+//	stream := middleware.ErrorMappingStreamInterceptor(nil) // nil — правила по умолчанию
+//
+//	// Idempotency (повторы с метаданными idempotency-key получают сохранённый ответ)
+//	unary := middleware.IdempotencyUnaryInterceptor(middleware.NewKVIdempotencyStore(redisClient), 24*time.Hour)
+//
+//	// Coalescing (экспериментально): одновременные запросы с одним idempotency-key выполняют обработчик один раз
+//	unary := middleware.CoalescingUnaryInterceptor()
+//
+//	// Caching (ответы методов из WithCachedMethod возвращаются из кэша до истечения TTL)
+//	unary := middleware.CachingUnaryInterceptor(middleware.NewKVCacheStore(redisClient),
+//	    middleware.WithCachedMethod("/catalog.Catalog/GetItem", middleware.CacheMethod{TTL: 5 * time.Minute}),
+//	)
+//
+//	// Compression (gzip и zstd регистрируются импортом пакета)
+//	serverOpt := grpc.StatsHandler(middleware.CompressionStatsHandler())
+//	unary := middleware.SendCompressorUnaryInterceptor(middleware.CompressorZstd)
+//	unary := middleware.RequireCompressionUnaryInterceptor(64 << 10) // несжатые > 64 KiB → RESOURCE_EXHAUSTED
+//
+//	// Concurrency limit (load shedding): сверх лимита — RESOURCE_EXHAUSTED с QuotaFailure и RetryInfo
+//	limiter := middleware.NewConcurrencyLimiter(100,
+//	    middleware.WithMethodConcurrencyLimit("/svc.Reports/Build", 4),
+//	    middleware.WithConcurrencyQueueTimeout(50*time.Millisecond),
+//	    middleware.WithConcurrencyRetryDelay(500*time.Millisecond), // клиент повторит не раньше
+//	)
+//	unary := limiter.UnaryServerInterceptor() // после метрик: ожидание — в grpc.server.queue_duration_ms
+//
+//	// Message size: запрос сверх лимита — INVALID_ARGUMENT с errdetails.ErrorInfo (MESSAGE_TOO_LARGE)
+//	limiter := middleware.NewMessageSizeLimiter(1<<20, 4<<20)
+//	serverOpts := []grpc.ServerOption{
+//	    grpc.MaxRecvMsgSize(limiter.TransportRecvLimit()),
+//	    grpc.StatsHandler(limiter.StatsHandler()),
+//	}
+//	unary := limiter.UnaryServerInterceptor()
 //
-// Порядок интерцепторов (важно):
-//  1. Recovery — перехват паник
-//  2. Tracing — создание span'ов
-//  3. Metrics — сбор метрик
+//	// Connection stats: метрики и логи открытия/закрытия соединений (ставится SetupMonitoring при EnableStatsHandler)
+//	serverOpt := grpc.StatsHandler(middleware.ConnectionStatsHandler(
+//	    middleware.WithConnStatsLogger(logger),
+//	    middleware.WithConnStatsSampling(0.1),
+//	))
+//
+//	// Deadline budget: дедлайн обработчика и исходящих вызовов сокращается на запас
+//	unary := middleware.DeadlineBudgetUnaryServerInterceptor(50*time.Millisecond)
+//	client := middleware.DeadlineBudgetUnaryClientInterceptor(20*time.Millisecond)
+//	budget, ok := middleware.DeadlineBudget(ctx)
+//
+//	// Slow requests: запросы дольше бюджета метода — Warn лог и метрика; CPU профиль сохраняется в storage
+//	detector := middleware.NewSlowRequestDetector(logger, 500*time.Millisecond,
+//	    middleware.WithMethodLatencyBudget("/svc.Reports/Build", 5*time.Second),
+//	    middleware.WithSlowRequestProfiling(minioStorage, "diagnostics"),
+//	)
+//	unary := detector.UnaryServerInterceptor()
+//
+//	// Skip methods: служебные методы без span'ов, метрик и логов запросов
+//	opts.SkipMethods = middleware.InternalMethods
+//	matched, err := middleware.MatchMethod(opts.SkipMethods, "/grpc.health.v1.Health/Check")
+//
+//	// Feature gate: методы с выключенным флагом отклоняются с UNIMPLEMENTED (dark launch, аварийный выключатель)
+//	gate := middleware.NewFeatureGate(flags, map[string]string{"/svc.Reports/BuildV2": "reports-build-v2"})
+//	unary := gate.UnaryServerInterceptor()
+//
+// Отмены клиентом (context.Canceled, codes.Canceled или отменённый контекст запроса, см. [IsClientCancellation])
+// не считаются ошибками сервера: в метриках получают grpc.status=Canceled и grpc.outcome=cancelled,
+// логируются на уровне Info, span не помечается ошибкой и получает атрибут rpc.cancelled.
+//
+// Использование (клиентские интерцепторы):
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithChainUnaryInterceptor(
+//	        middleware.TracingUnaryClientInterceptor(), // передаёт контекст трассировки в метаданных
+//	        middleware.MetricsUnaryClientInterceptor(),
+//	        middleware.LoggingUnaryClientInterceptor(logger),
+//	    ),
+//	    grpc.WithChainStreamInterceptor(
+//	        middleware.TracingStreamClientInterceptor(),
+//	        middleware.MetricsStreamClientInterceptor(),
+//	        middleware.LoggingStreamClientInterceptor(logger),
+//	    ),
+//	)
+//
+// Использование (BuildChain с пользовательскими интерцепторами):
+//
+//	opts := middleware.DefaultMonitoringOptions(logger)
+//	opts.Interceptors = []middleware.ChainInterceptor{
+//	    middleware.UnaryAt(middleware.PositionFirst, requestIDInterceptor),
+//	    middleware.UnaryAt(middleware.PositionAuth, authInterceptor),
+//	}
+//	unary, stream := middleware.BuildChain(opts)
+//
+// Порядок встроенных интерцепторов:
+//  1. Tracing — создание span'ов (и Tenant, RequestInfo, PeerInfo, ClientIP, если включены)
+//  2. Metrics — сбор метрик
+//  3. Recovery — перехват паник
 //  4. Logging — логирование запросов
+//
+// Именованные позиции для пользовательских интерцепторов ([ChainPosition]):
+// PositionFirst, PositionAfterTracing, PositionAfterMetrics, PositionAfterRecovery,
+// PositionAfterLogging (PositionBeforeAuth), PositionAuth, PositionLast.
+// Интерцепторы с одинаковой позицией выполняются в порядке добавления.
+//
+// Пакет middlewaretest проверяет цепочку на сервере в памяти с перехватом span'ов, метрик и логов.
 package middleware
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	grpcerrors "github.com/pure-golang/adapters/grpc/errors"
+)
+
+// ErrorMappingUnaryInterceptor создает интерцептор, преобразующий доменные ошибки обработчика
+// в gRPC статусы по правилам mapper. Если mapper равен nil, используется grpcerrors.NewDefaultMapper().
+// Исходная ошибка записывается в текущий span, клиент получает только безопасное сообщение.
+//
+// Рекомендуемая позиция в цепочке — PositionAfterRecovery: метрики и логи увидят итоговый код статуса.
+func ErrorMappingUnaryInterceptor(mapper *grpcerrors.Mapper) grpc.UnaryServerInterceptor {
+	m := mapper
+	if m == nil {
+		m = grpcerrors.NewDefaultMapper()
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return resp, mapError(ctx, m, err)
+	}
+}
+
+// ErrorMappingStreamInterceptor создает интерцептор преобразования ошибок для потоковых RPC
+func ErrorMappingStreamInterceptor(mapper *grpcerrors.Mapper) grpc.StreamServerInterceptor {
+	m := mapper
+	if m == nil {
+		m = grpcerrors.NewDefaultMapper()
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil {
+			return nil
+		}
+		return mapError(ss.Context(), m, err)
+	}
+}
+
+// mapError преобразует ошибку и сохраняет исходную в span, если она была заменена
+func mapError(ctx context.Context, mapper *grpcerrors.Mapper, err error) error {
+	mapped := mapper.Map(err)
+	if mapped != err {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+	return mapped
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcerrors "github.com/pure-golang/adapters/grpc/errors"
+	"github.com/pure-golang/adapters/storage"
+)
+
+// TestErrorMappingUnaryInterceptor_MapsDomainError tests that domain errors are converted to statuses
+func TestErrorMappingUnaryInterceptor_MapsDomainError(t *testing.T) {
+	t.Parallel()
+	interceptor := ErrorMappingUnaryInterceptor(nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	_, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return nil, &storage.StorageError{Code: storage.CodeNotFound, Bucket: "private", Key: "secret.txt"}
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.NotContains(t, st.Message(), "secret.txt")
+}
+
+// TestErrorMappingUnaryInterceptor_Success tests that successful responses pass through
+func TestErrorMappingUnaryInterceptor_Success(t *testing.T) {
+	t.Parallel()
+	interceptor := ErrorMappingUnaryInterceptor(nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	resp, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return "response", nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "response", resp)
+}
+
+// TestErrorMappingUnaryInterceptor_CustomMapper tests interceptor with a custom mapper
+func TestErrorMappingUnaryInterceptor_CustomMapper(t *testing.T) {
+	t.Parallel()
+	errQuota := errors.New("quota exceeded")
+	mapper := grpcerrors.NewMapper(grpcerrors.Mapping{
+		Match:   grpcerrors.Is(errQuota),
+		Code:    codes.ResourceExhausted,
+		Message: "quota exceeded",
+	})
+	interceptor := ErrorMappingUnaryInterceptor(mapper)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Upload"}
+
+	_, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return nil, errQuota
+	})
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// TestErrorMappingStreamInterceptor_MapsDomainError tests stream error mapping
+func TestErrorMappingStreamInterceptor_MapsDomainError(t *testing.T) {
+	t.Parallel()
+	interceptor := ErrorMappingStreamInterceptor(nil)
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch", IsServerStream: true}
+	stream := &wrappedServerStream{ctx: context.Background()}
+
+	err := interceptor(nil, stream, info, func(srv any, ss grpc.ServerStream) error {
+		return errors.New("connection to 10.0.0.1 refused")
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal error", st.Message())
+}
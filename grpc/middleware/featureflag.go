@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Причины отказа FeatureGate — значения атрибута reason метрики
+const (
+	featureGateReasonDisabled = "disabled"
+	featureGateReasonError    = "error"
+)
+
+// FeatureFlagProvider возвращает значение логического feature-флага по ключу.
+// Подходит обёртка над любым источником флагов: OpenFeature, Unleash, конфигурация из env.
+type FeatureFlagProvider interface {
+	Enabled(ctx context.Context, key string) (bool, error)
+}
+
+// FeatureFlagProviderFunc — адаптер функции к FeatureFlagProvider
+type FeatureFlagProviderFunc func(ctx context.Context, key string) (bool, error)
+
+// Enabled вызывает f(ctx, key)
+func (f FeatureFlagProviderFunc) Enabled(ctx context.Context, key string) (bool, error) {
+	return f(ctx, key)
+}
+
+// FeatureGateOption настраивает FeatureGate
+type FeatureGateOption func(*featureGateConfig)
+
+// WithFeatureGateDisabledCode задаёт код ответа для выключенных методов (по умолчанию codes.Unimplemented:
+// клиент видит метод так же, как ещё не развёрнутый)
+func WithFeatureGateDisabledCode(code codes.Code) FeatureGateOption {
+	return func(c *featureGateConfig) {
+		c.disabledCode = code
+	}
+}
+
+// WithMethodFeatureGateCode задаёт код ответа для выключенного метода (или сервиса "/pkg.Service/*"),
+// например codes.FailedPrecondition для аварийно выключенного метода, о котором клиент знает
+func WithMethodFeatureGateCode(method string, code codes.Code) FeatureGateOption {
+	return func(c *featureGateConfig) {
+		c.methodCodes[method] = code
+	}
+}
+
+// WithFeatureGateFailOpen пропускает запрос, если провайдер вернул ошибку.
+// По умолчанию такой запрос отклоняется с codes.Unavailable: неготовый метод не открывается при сбое провайдера.
+// Для аварийных выключателей уже работающих методов обычно удобнее пропускать.
+func WithFeatureGateFailOpen() FeatureGateOption {
+	return func(c *featureGateConfig) {
+		c.failOpen = true
+	}
+}
+
+// WithFeatureGateMeterProvider задаёт MeterProvider для метрик FeatureGate
+// вместо глобального otel.GetMeterProvider()
+func WithFeatureGateMeterProvider(provider metric.MeterProvider) FeatureGateOption {
+	return func(c *featureGateConfig) {
+		c.meterProvider = provider
+	}
+}
+
+type featureGateConfig struct {
+	disabledCode  codes.Code
+	methodCodes   map[string]codes.Code
+	failOpen      bool
+	meterProvider metric.MeterProvider
+}
+
+// FeatureGate открывает методы в зависимости от feature-флагов: перед вызовом обработчика
+// флаг метода запрашивается у провайдера, и выключенный метод отклоняется без вызова обработчика.
+// Так методы выпускаются скрыто (dark launch) и выключаются без повторного развёртывания.
+// Методы без флага не проверяются.
+//
+// Метрика grpc.server.feature_gate.rejected_total — отклонённые запросы
+// (атрибуты grpc.method, flag, reason: "disabled" или "error").
+type FeatureGate struct {
+	provider     FeatureFlagProvider
+	flags        map[string]string
+	disabledCode codes.Code
+	methodCodes  map[string]codes.Code
+	failOpen     bool
+	rejected     metric.Int64Counter
+}
+
+// NewFeatureGate создаёт FeatureGate. flags сопоставляет метод ("/pkg.Service/Method")
+// или все методы сервиса ("/pkg.Service/*") с ключом флага; флаг метода важнее флага сервиса.
+// Ошибка создания метрик передаётся в otel.Handle, FeatureGate работает без них.
+func NewFeatureGate(provider FeatureFlagProvider, flags map[string]string, opts ...FeatureGateOption) *FeatureGate {
+	cfg := &featureGateConfig{
+		disabledCode: codes.Unimplemented,
+		methodCodes:  make(map[string]codes.Code),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g := &FeatureGate{
+		provider:     provider,
+		flags:        make(map[string]string, len(flags)),
+		disabledCode: cfg.disabledCode,
+		methodCodes:  cfg.methodCodes,
+		failOpen:     cfg.failOpen,
+	}
+	for method, key := range flags {
+		g.flags[method] = key
+	}
+
+	m := meter
+	if cfg.meterProvider != nil {
+		m = cfg.meterProvider.Meter("github.com/pure-golang/adapters/grpc")
+	}
+	rejected, err := m.Int64Counter(
+		"grpc.server.feature_gate.rejected_total",
+		metric.WithDescription("Total number of gRPC requests rejected by feature flags"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create feature gate rejected counter"))
+		rejected = noop.Int64Counter{}
+	}
+	g.rejected = rejected
+	return g
+}
+
+// UnaryServerInterceptor возвращает интерцептор, проверяющий флаги унарных методов
+func (g *FeatureGate) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := g.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor возвращает интерцептор, проверяющий флаги потоковых методов
+func (g *FeatureGate) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := g.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check возвращает ошибку статуса, если флаг метода выключен или не получен
+func (g *FeatureGate) check(ctx context.Context, method string) error {
+	key, ok := g.flags[method]
+	if !ok {
+		key, ok = g.flags[servicePattern(method)]
+	}
+	if !ok {
+		return nil
+	}
+
+	enabled, err := g.provider.Enabled(ctx, key)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to evaluate feature flag",
+			slog.String("method", method),
+			slog.String("flag", key),
+			slog.Bool("fail_open", g.failOpen),
+			slog.Any("error", err),
+		)
+		if g.failOpen {
+			return nil
+		}
+		g.reject(ctx, method, key, featureGateReasonError)
+		return status.Errorf(codes.Unavailable, "method %s is temporarily unavailable", method)
+	}
+	if enabled {
+		return nil
+	}
+
+	g.reject(ctx, method, key, featureGateReasonDisabled)
+	code, ok := g.methodCodes[method]
+	if !ok {
+		code, ok = g.methodCodes[servicePattern(method)]
+	}
+	if !ok {
+		code = g.disabledCode
+	}
+	return status.Errorf(code, "method %s is not available", method)
+}
+
+// servicePattern возвращает запись "/pkg.Service/*" для всех методов сервиса метода
+func servicePattern(method string) string {
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		return method[:i+1] + "*"
+	}
+	return ""
+}
+
+// reject учитывает отклонённый запрос в метрике
+func (g *FeatureGate) reject(ctx context.Context, method, key, reason string) {
+	g.rejected.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("grpc.method", method),
+		attribute.String("flag", key),
+		attribute.String("reason", reason),
+	))
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// staticFlags returns a provider with fixed flag values; unknown keys fail.
+func staticFlags(values map[string]bool) FeatureFlagProvider {
+	return FeatureFlagProviderFunc(func(_ context.Context, key string) (bool, error) {
+		enabled, ok := values[key]
+		if !ok {
+			return false, errors.New("flag service unavailable")
+		}
+		return enabled, nil
+	})
+}
+
+// TestFeatureGate tests that disabled methods are rejected with the configured codes.
+func TestFeatureGate(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	gate := NewFeatureGate(
+		staticFlags(map[string]bool{"reports-v2": false, "billing": false, "billing-get": true, "search": true}),
+		map[string]string{
+			"/svc.Reports/BuildV2": "reports-v2",
+			"/svc.Billing/*":       "billing",
+			"/svc.Billing/Get":     "billing-get",
+			"/svc.Search/Find":     "search",
+			"/svc.Broken/Call":     "unknown",
+		},
+		WithMethodFeatureGateCode("/svc.Billing/*", codes.FailedPrecondition),
+		WithFeatureGateMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	interceptor := gate.UnaryServerInterceptor()
+
+	tests := []struct {
+		method string
+		code   codes.Code
+	}{
+		{"/svc.Reports/BuildV2", codes.Unimplemented},
+		{"/svc.Billing/Charge", codes.FailedPrecondition},
+		{"/svc.Billing/Get", codes.OK},
+		{"/svc.Search/Find", codes.OK},
+		{"/svc.Search/Suggest", codes.OK},
+		{"/svc.Broken/Call", codes.Unavailable},
+	}
+	for _, tt := range tests {
+		called := false
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+			func(context.Context, any) (any, error) {
+				called = true
+				return "ok", nil
+			})
+		assert.Equal(t, tt.code, status.Code(err), tt.method)
+		assert.Equal(t, tt.code == codes.OK, called, tt.method)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	rejected := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	reasons := make(map[string]int64)
+	for _, dp := range rejected.DataPoints {
+		reason, _ := dp.Attributes.Value("reason")
+		reasons[reason.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{"disabled": 2, "error": 1}, reasons)
+}
+
+// TestFeatureGate_FailOpen tests that provider errors pass requests through with WithFeatureGateFailOpen.
+func TestFeatureGate_FailOpen(t *testing.T) {
+	t.Parallel()
+	gate := NewFeatureGate(staticFlags(nil), map[string]string{"/svc/Watch": "watch"},
+		WithFeatureGateFailOpen(),
+		WithFeatureGateDisabledCode(codes.FailedPrecondition),
+	)
+
+	called := false
+	err := gate.StreamServerInterceptor()(nil, &mockServerStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/svc/Watch"}, func(any, grpc.ServerStream) error {
+			called = true
+			return nil
+		})
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	gate = NewFeatureGate(staticFlags(map[string]bool{"watch": false}), map[string]string{"/svc/Watch": "watch"},
+		WithFeatureGateDisabledCode(codes.FailedPrecondition),
+	)
+	err = gate.StreamServerInterceptor()(nil, &mockServerStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/svc/Watch"}, func(any, grpc.ServerStream) error {
+			t.Fatal("handler must not be called for a disabled method")
+			return nil
+		})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,317 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/pure-golang/adapters/kv"
+	"github.com/pure-golang/adapters/logger"
+)
+
+const (
+	// IdempotencyKeyHeader — ключ метаданных с ключом идемпотентности по умолчанию
+	IdempotencyKeyHeader = "idempotency-key"
+	// IdempotentReplayHeader — заголовок ответа, выставляемый при возврате сохранённого ответа
+	IdempotentReplayHeader = "idempotent-replayed"
+
+	idempotencyKeyPrefix = "grpc:idempotency:"
+)
+
+// IdempotencyStore хранит сериализованные ответы по ключу идемпотентности
+type IdempotencyStore interface {
+	// Get возвращает сохранённое значение; ok == false, если ключа нет или он истёк
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set сохраняет значение на ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// IdempotencyOption настраивает IdempotencyUnaryInterceptor
+type IdempotencyOption func(*idempotencyConfig)
+
+// WithIdempotencyHeader задаёт ключ метаданных, из которого читается ключ идемпотентности
+func WithIdempotencyHeader(header string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.header = header
+	}
+}
+
+// WithIdempotencyScope задаёт функцию, возвращающую область действия ключа
+// (например, ID пользователя), чтобы одинаковые ключи разных клиентов не пересекались
+func WithIdempotencyScope(scope func(ctx context.Context) string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.scope = scope
+	}
+}
+
+type idempotencyConfig struct {
+	header string
+	scope  func(ctx context.Context) string
+}
+
+// IdempotencyUnaryInterceptor создает интерцептор, дедуплицирующий повторные запросы
+// с одинаковым ключом идемпотентности (метаданные idempotency-key).
+//
+// Успешный ответ сохраняется в store на ttl; повтор с тем же ключом и тем же телом запроса
+// получает сохранённый ответ без вызова обработчика и заголовок idempotent-replayed: true.
+// Повтор с тем же ключом, но другим телом, отклоняется с codes.FailedPrecondition.
+// Ошибки обработчика не сохраняются — клиент может повторить запрос. Ошибка сохранения ответа
+// логируется и записывается в span, ответ клиенту не меняется.
+// Одновременные запросы с одним ключом в пределах процесса ждут завершения первого.
+// Запросы без ключа и с не-protobuf сообщениями обрабатываются как обычно.
+func IdempotencyUnaryInterceptor(store IdempotencyStore, ttl time.Duration, opts ...IdempotencyOption) grpc.UnaryServerInterceptor {
+	cfg := &idempotencyConfig{header: IdempotencyKeyHeader}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var inflight keyedMutex
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		reqMsg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(cfg.header)
+		if len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+
+		key := idempotencyKeyPrefix + info.FullMethod + ":"
+		if cfg.scope != nil {
+			key += cfg.scope(ctx) + ":"
+		}
+		key += values[0]
+
+		fingerprint, err := requestFingerprint(reqMsg)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to fingerprint request")
+		}
+
+		unlock, err := inflight.Lock(ctx, key)
+		if err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		defer unlock()
+
+		stored, ok, err := store.Get(ctx, key)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, "idempotency store is unavailable")
+		}
+		if ok {
+			return replayResponse(ctx, stored, fingerprint)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		if respMsg, ok := resp.(proto.Message); ok {
+			saveIdempotencyRecord(ctx, store, key, info.FullMethod, fingerprint, respMsg, ttl)
+		}
+
+		return resp, nil
+	}
+}
+
+// saveIdempotencyRecord сохраняет ответ по ключу идемпотентности. Ошибка не возвращается клиенту,
+// так как запрос уже выполнен, но логируется и записывается в span: без записи повтор
+// с тем же ключом снова вызовет обработчик.
+func saveIdempotencyRecord(ctx context.Context, store IdempotencyStore, key, method string, fingerprint []byte, resp proto.Message, ttl time.Duration) {
+	record, err := encodeIdempotencyRecord(fingerprint, resp)
+	if err == nil {
+		err = store.Set(ctx, key, record, ttl)
+	}
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		logger.FromContext(ctx).Error("failed to store idempotency record",
+			slog.String("method", method),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// replayResponse возвращает сохранённый ответ, если отпечаток запроса совпадает
+func replayResponse(ctx context.Context, stored, fingerprint []byte) (any, error) {
+	storedFingerprint, resp, err := decodeIdempotencyRecord(stored)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to decode stored response")
+	}
+	if !bytes.Equal(storedFingerprint, fingerprint) {
+		return nil, status.Error(codes.FailedPrecondition, "idempotency key reused with a different request")
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(IdempotentReplayHeader, "true"))
+	return resp, nil
+}
+
+// requestFingerprint возвращает SHA-256 детерминированной сериализации запроса
+func requestFingerprint(req proto.Message) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// encodeIdempotencyRecord сериализует отпечаток запроса и ответ: sha256 || Any(resp)
+func encodeIdempotencyRecord(fingerprint []byte, resp proto.Message) ([]byte, error) {
+	packed, err := anypb.New(resp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(packed)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, fingerprint...), data...), nil
+}
+
+// decodeIdempotencyRecord разбирает запись, созданную encodeIdempotencyRecord
+func decodeIdempotencyRecord(record []byte) ([]byte, proto.Message, error) {
+	if len(record) < sha256.Size {
+		return nil, nil, errors.New("idempotency record is too short")
+	}
+	var packed anypb.Any
+	if err := proto.Unmarshal(record[sha256.Size:], &packed); err != nil {
+		return nil, nil, err
+	}
+	resp, err := packed.UnmarshalNew()
+	if err != nil {
+		return nil, nil, err
+	}
+	return record[:sha256.Size], resp, nil
+}
+
+// keyedMutex — набор мьютексов по ключу с ожиданием, прерываемым контекстом
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// Lock захватывает ключ и возвращает функцию освобождения
+func (m *keyedMutex) Lock(ctx context.Context, key string) (func(), error) {
+	for {
+		m.mu.Lock()
+		if m.locks == nil {
+			m.locks = make(map[string]chan struct{})
+		}
+		wait, busy := m.locks[key]
+		if !busy {
+			done := make(chan struct{})
+			m.locks[key] = done
+			m.mu.Unlock()
+			return func() {
+				m.mu.Lock()
+				delete(m.locks, key)
+				m.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// MemoryIdempotencyStore — IdempotencyStore в памяти процесса
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+	now     func() time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore создаёт IdempotencyStore в памяти процесса.
+// Истёкшие записи удаляются при обращении и при каждой записи.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]memoryIdempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Get реализует IdempotencyStore
+func (s *MemoryIdempotencyStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set реализует IdempotencyStore
+func (s *MemoryIdempotencyStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryIdempotencyEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// kvIdempotencyStore адаптирует kv.Store к IdempotencyStore
+type kvIdempotencyStore struct {
+	store kv.Store
+}
+
+// NewKVIdempotencyStore создаёт IdempotencyStore поверх kv.Store (например, kv/redis)
+func NewKVIdempotencyStore(store kv.Store) IdempotencyStore {
+	return &kvIdempotencyStore{store: store}
+}
+
+func (s *kvIdempotencyStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.store.Get(ctx, key)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get idempotency record")
+	}
+	if value == "" {
+		return nil, false, nil
+	}
+	return []byte(value), true, nil
+}
+
+func (s *kvIdempotencyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.store.Set(ctx, key, value, ttl); err != nil {
+		return errors.Wrap(err, "failed to set idempotency record")
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/pure-golang/adapters/kv"
+	kvnoop "github.com/pure-golang/adapters/kv/noop"
+	"github.com/pure-golang/adapters/logger"
+)
+
+var idempotencyInfo = &grpc.UnaryServerInfo{FullMethod: "/test.Payments/Charge"}
+
+func withIdempotencyKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyHeader, key))
+}
+
+// countingHandler returns a handler producing a unique response per call
+func countingHandler(calls *atomic.Int32) grpc.UnaryHandler {
+	return func(_ context.Context, req any) (any, error) {
+		n := calls.Add(1)
+		return wrapperspb.String(req.(*wrapperspb.StringValue).GetValue() + "-" + string(rune('0'+n))), nil
+	}
+}
+
+// TestIdempotencyUnaryInterceptor_ReplaysResponse tests that retries get the stored response
+func TestIdempotencyUnaryInterceptor_ReplaysResponse(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+
+	first, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+	require.NoError(t, err)
+	second, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, handler)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.True(t, proto.Equal(first.(proto.Message), second.(proto.Message)))
+
+	_, err = interceptor(withIdempotencyKey("k2"), wrapperspb.String("charge"), idempotencyInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "a different key must reach the handler")
+}
+
+// TestIdempotencyUnaryInterceptor_WithoutKey tests that requests without a key are not deduplicated
+func TestIdempotencyUnaryInterceptor_WithoutKey(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	var calls atomic.Int32
+
+	for range 2 {
+		_, err := interceptor(context.Background(), wrapperspb.String("charge"), idempotencyInfo, countingHandler(&calls))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestIdempotencyUnaryInterceptor_DifferentRequest tests that key reuse with another payload is rejected
+func TestIdempotencyUnaryInterceptor_DifferentRequest(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	var calls atomic.Int32
+
+	_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge 10"), idempotencyInfo, countingHandler(&calls))
+	require.NoError(t, err)
+	_, err = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge 20"), idempotencyInfo, countingHandler(&calls))
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// TestIdempotencyUnaryInterceptor_ErrorsNotCached tests that failed calls can be retried
+func TestIdempotencyUnaryInterceptor_ErrorsNotCached(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	var calls atomic.Int32
+	failing := func(context.Context, any) (any, error) {
+		calls.Add(1)
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+
+	_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, failing)
+	require.Error(t, err)
+	_, err = interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, countingHandler(&calls))
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestIdempotencyUnaryInterceptor_Scope tests that scopes isolate equal keys
+func TestIdempotencyUnaryInterceptor_Scope(t *testing.T) {
+	t.Parallel()
+	type userKey struct{}
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute,
+		WithIdempotencyScope(func(ctx context.Context) string {
+			user, _ := ctx.Value(userKey{}).(string)
+			return user
+		}),
+	)
+	var calls atomic.Int32
+
+	for _, user := range []string{"alice", "bob"} {
+		ctx := context.WithValue(withIdempotencyKey("k1"), userKey{}, user)
+		_, err := interceptor(ctx, wrapperspb.String("charge"), idempotencyInfo, countingHandler(&calls))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestIdempotencyUnaryInterceptor_ConcurrentDuplicates tests that concurrent duplicates run the handler once
+func TestIdempotencyUnaryInterceptor_ConcurrentDuplicates(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	var calls atomic.Int32
+	slow := func(ctx context.Context, req any) (any, error) {
+		time.Sleep(20 * time.Millisecond)
+		return countingHandler(&calls)(ctx, req)
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, slow)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// failingIdempotencyStore is an IdempotencyStore that always fails
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingIdempotencyStore) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+// TestIdempotencyUnaryInterceptor_StoreError tests that store failures do not run the handler
+func TestIdempotencyUnaryInterceptor_StoreError(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(failingIdempotencyStore{}, time.Minute)
+	var calls atomic.Int32
+
+	_, err := interceptor(withIdempotencyKey("k1"), wrapperspb.String("charge"), idempotencyInfo, countingHandler(&calls))
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Zero(t, calls.Load())
+}
+
+// unwritableIdempotencyStore is an IdempotencyStore that has no records and fails to save them
+type unwritableIdempotencyStore struct{}
+
+func (unwritableIdempotencyStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (unwritableIdempotencyStore) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+// TestIdempotencyUnaryInterceptor_SetError tests that a failed save keeps the response and is logged and traced
+func TestIdempotencyUnaryInterceptor_SetError(t *testing.T) {
+	t.Parallel()
+	interceptor := IdempotencyUnaryInterceptor(unwritableIdempotencyStore{}, time.Minute)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var buf bytes.Buffer
+	ctx := logger.NewContext(withIdempotencyKey("k1"), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, span := tp.Tracer("test").Start(ctx, "request")
+	var calls atomic.Int32
+
+	resp, err := interceptor(ctx, wrapperspb.String("charge"), idempotencyInfo, countingHandler(&calls))
+	span.End()
+
+	require.NoError(t, err)
+	assert.Equal(t, "charge-1", resp.(*wrapperspb.StringValue).GetValue())
+	assert.Contains(t, buf.String(), `level=ERROR msg="failed to store idempotency record" method=/test.Payments/Charge error="connection refused"`)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+// TestMemoryIdempotencyStore_Expiry tests that entries expire after ttl
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryIdempotencyStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Set(context.Background(), "k", []byte("v"), time.Minute))
+	value, ok, err := store.Get(context.Background(), "k")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v"), value)
+
+	now = now.Add(time.Minute)
+	_, ok, err = store.Get(context.Background(), "k")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// notFoundKVStore is a kv.Store reporting missing keys
+type notFoundKVStore struct {
+	kvnoop.Store
+}
+
+func (*notFoundKVStore) Get(context.Context, string) (string, error) {
+	return "", kv.ErrKeyNotFound
+}
+
+// TestKVIdempotencyStore_Miss tests that missing keys are reported as a miss
+func TestKVIdempotencyStore_Miss(t *testing.T) {
+	t.Parallel()
+	for _, store := range []kv.Store{kvnoop.New(), &notFoundKVStore{}} {
+		_, ok, err := NewKVIdempotencyStore(store).Get(context.Background(), "k")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+}
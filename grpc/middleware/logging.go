@@ -5,13 +5,26 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/errreport"
+	"github.com/pure-golang/adapters/logger"
+	"github.com/pure-golang/adapters/observability/correlate"
 )
 
-// LoggingInterceptor создает интерцептор для логирования gRPC запросов
+// RequestIDHeader — заголовок метаданных с идентификатором запроса, который LoggingInterceptor
+// и LoggingStreamInterceptor сохраняют в контекст (correlate.WithRequestID)
+const RequestIDHeader = "x-request-id"
+
+// LoggingInterceptor создает интерцептор для логирования gRPC запросов.
+// Записи содержат trace_id, span_id и request_id (observability/correlate).
 func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = requestIDContext(ctx)
 		start := time.Now()
 		resp, err := handler(ctx, req)
 		duration := time.Since(start)
@@ -20,16 +33,29 @@ func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 			slog.String("method", info.FullMethod),
 			slog.Duration("duration", duration),
 		}
+		logAttrs = append(logAttrs, correlate.Args(ctx)...)
+		logAttrs = append(logAttrs, baggageLogFields(ctx)...)
+		logAttrs = append(logAttrs, tenantLogFields(ctx)...)
+		logAttrs = append(logAttrs, requestInfoLogFields(ctx)...)
+		logAttrs = append(logAttrs, peerLogFields(ctx)...)
+		logAttrs = append(logAttrs, clientIPLogFields(ctx)...)
 
-		// Добавляем информацию о статусе
-		if err != nil {
+		// Добавляем информацию о статусе; отмена клиентом не является ошибкой сервера
+		switch {
+		case IsClientCancellation(ctx, err):
+			logAttrs = append(logAttrs,
+				slog.String("status_code", codes.Canceled.String()),
+				slog.Any("error", err),
+			)
+			logger.InfoContext(ctx, "gRPC request cancelled by client", logAttrs...)
+		case err != nil:
 			s := status.Convert(err)
 			logAttrs = append(logAttrs,
 				slog.String("status_code", s.Code().String()),
 				slog.Any("error", err),
 			)
 			logger.ErrorContext(ctx, "gRPC request failed", logAttrs...)
-		} else {
+		default:
 			logAttrs = append(logAttrs, slog.String("status_code", "OK"))
 			logger.InfoContext(ctx, "gRPC request processed", logAttrs...)
 		}
@@ -38,15 +64,36 @@ func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
-// RecoveryInterceptor создает интерцептор для восстановления после паники
-func RecoveryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+// RecoveryOption настраивает RecoveryInterceptor и RecoveryStreamInterceptor
+type RecoveryOption func(*recoveryConfig)
+
+// WithRecoveryReporter отправляет восстановленные паники в систему отслеживания ошибок
+// с тегами grpc.method и tenant (если определён)
+func WithRecoveryReporter(reporter errreport.Reporter) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.reporter = reporter
+	}
+}
+
+type recoveryConfig struct {
+	reporter errreport.Reporter
+}
+
+// RecoveryInterceptor создает интерцептор для восстановления после паники.
+// Паника передаётся в logger.HandlePanic: запись получает стек, вызываются хуки logger.OnPanic и нотификаторы.
+func RecoveryInterceptor(log *slog.Logger, opts ...RecoveryOption) grpc.UnaryServerInterceptor {
+	cfg := newRecoveryConfig(opts)
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.ErrorContext(ctx, "Recovered from panic in gRPC handler",
-					slog.Any("panic", r),
-					slog.String("method", info.FullMethod),
-				)
+				logAttrs := []any{slog.String("method", info.FullMethod)}
+				logAttrs = append(logAttrs, correlate.Args(ctx)...)
+				logAttrs = append(logAttrs, tenantLogFields(ctx)...)
+				logAttrs = append(logAttrs, requestInfoLogFields(ctx)...)
+				logAttrs = append(logAttrs, peerLogFields(ctx)...)
+				logAttrs = append(logAttrs, clientIPLogFields(ctx)...)
+				logger.HandlePanic(ctx, log, r, "Recovered from panic in gRPC handler", logAttrs...)
+				cfg.report(ctx, info.FullMethod, r)
 				err = status.Error(14, "internal server error") // UNAVAILABLE
 			}
 		}()
@@ -54,9 +101,13 @@ func RecoveryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
-// LoggingStreamInterceptor создает интерцептор для логирования потоковых gRPC запросов
+// LoggingStreamInterceptor создает интерцептор для логирования потоковых gRPC запросов.
+// Записи содержат trace_id, span_id и request_id (observability/correlate).
 func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if ctx := requestIDContext(ss.Context()); ctx != ss.Context() {
+			ss = &wrappedServerStream{ServerStream: ss, ctx: ctx}
+		}
 		start := time.Now()
 		err := handler(srv, ss)
 		duration := time.Since(start)
@@ -67,15 +118,28 @@ func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor
 			slog.Bool("client_stream", info.IsClientStream),
 			slog.Bool("server_stream", info.IsServerStream),
 		}
+		logAttrs = append(logAttrs, correlate.Args(ss.Context())...)
+		logAttrs = append(logAttrs, baggageLogFields(ss.Context())...)
+		logAttrs = append(logAttrs, tenantLogFields(ss.Context())...)
+		logAttrs = append(logAttrs, requestInfoLogFields(ss.Context())...)
+		logAttrs = append(logAttrs, peerLogFields(ss.Context())...)
+		logAttrs = append(logAttrs, clientIPLogFields(ss.Context())...)
 
-		if err != nil {
+		switch {
+		case IsClientCancellation(ss.Context(), err):
+			logAttrs = append(logAttrs,
+				slog.String("status_code", codes.Canceled.String()),
+				slog.Any("error", err),
+			)
+			logger.InfoContext(ss.Context(), "gRPC stream cancelled by client", logAttrs...)
+		case err != nil:
 			s := status.Convert(err)
 			logAttrs = append(logAttrs,
 				slog.String("status_code", s.Code().String()),
 				slog.Any("error", err),
 			)
 			logger.ErrorContext(ss.Context(), "gRPC stream failed", logAttrs...)
-		} else {
+		default:
 			logAttrs = append(logAttrs, slog.String("status_code", "OK"))
 			logger.InfoContext(ss.Context(), "gRPC stream processed", logAttrs...)
 		}
@@ -84,18 +148,64 @@ func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor
 	}
 }
 
-// RecoveryStreamInterceptor создает интерцептор для восстановления в потоковых запросах
-func RecoveryStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+// RecoveryStreamInterceptor создает интерцептор для восстановления в потоковых запросах.
+// Паника передаётся в logger.HandlePanic, как в RecoveryInterceptor.
+func RecoveryStreamInterceptor(log *slog.Logger, opts ...RecoveryOption) grpc.StreamServerInterceptor {
+	cfg := newRecoveryConfig(opts)
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.ErrorContext(ss.Context(), "Recovered from panic in gRPC stream handler",
-					slog.Any("panic", r),
-					slog.String("method", info.FullMethod),
-				)
+				logAttrs := []any{slog.String("method", info.FullMethod)}
+				logAttrs = append(logAttrs, tenantLogFields(ss.Context())...)
+				logAttrs = append(logAttrs, requestInfoLogFields(ss.Context())...)
+				logAttrs = append(logAttrs, peerLogFields(ss.Context())...)
+				logAttrs = append(logAttrs, clientIPLogFields(ss.Context())...)
+				logger.HandlePanic(ss.Context(), log, r, "Recovered from panic in gRPC stream handler", logAttrs...)
+				cfg.report(ss.Context(), info.FullMethod, r)
 				err = status.Error(14, "internal server error") // UNAVAILABLE
 			}
 		}()
 		return handler(srv, ss)
 	}
 }
+
+func newRecoveryConfig(opts []RecoveryOption) *recoveryConfig {
+	cfg := &recoveryConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// report отправляет восстановленную панику в reporter, если он задан
+func (c *recoveryConfig) report(ctx context.Context, method string, recovered any) {
+	if c.reporter == nil {
+		return
+	}
+	var err error
+	if e, ok := recovered.(error); ok {
+		err = errors.Wrap(e, "panic")
+	} else {
+		err = errors.Errorf("panic: %v", recovered)
+	}
+	opts := []errreport.Option{
+		errreport.WithLevel(errreport.LevelFatal),
+		errreport.WithTag("grpc.method", method),
+	}
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		opts = append(opts, errreport.WithTag("tenant", tenant))
+	}
+	c.reporter.CaptureException(ctx, err, opts...)
+}
+
+// requestIDContext сохраняет в контекст идентификатор запроса из метаданных RequestIDHeader,
+// если он ещё не задан
+func requestIDContext(ctx context.Context) context.Context {
+	if correlate.RequestID(ctx) != "" {
+		return ctx
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDHeader); len(values) > 0 {
+		return correlate.WithRequestID(ctx, values[0])
+	}
+	return ctx
+}
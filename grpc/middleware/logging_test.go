@@ -10,11 +10,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
+	"github.com/pure-golang/adapters/errreport"
 	"github.com/pure-golang/adapters/logger"
 	"github.com/pure-golang/adapters/logger/noop"
+	"github.com/pure-golang/adapters/observability/correlate"
 )
 
 func init() {
@@ -449,6 +452,48 @@ func TestRecoveryStreamInterceptor_CatchesPanic(t *testing.T) {
 	})
 }
 
+// recordingReporter collects events captured via errreport.Reporter
+type recordingReporter struct {
+	errs   []error
+	events []errreport.Event
+}
+
+func (r *recordingReporter) CaptureException(ctx context.Context, err error, opts ...errreport.Option) {
+	r.errs = append(r.errs, err)
+	r.events = append(r.events, errreport.NewEvent(ctx, errreport.LevelError, opts...))
+}
+
+func (r *recordingReporter) CaptureMessage(context.Context, string, ...errreport.Option) {}
+
+func (r *recordingReporter) Flush(context.Context) error { return nil }
+
+// TestRecoveryInterceptor_Reporter tests that recovered panics are sent to the error reporter
+func TestRecoveryInterceptor_Reporter(t *testing.T) {
+	reporter := &recordingReporter{}
+	unary := RecoveryInterceptor(noop.NewNoop(), WithRecoveryReporter(reporter))
+	stream := RecoveryStreamInterceptor(noop.NewNoop(), WithRecoveryReporter(reporter))
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	_, err := unary(ctx, "request", &grpc.UnaryServerInfo{FullMethod: "/test.service/Panic"},
+		func(context.Context, any) (any, error) { panic("unary panic") })
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	err = stream(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/test.service/PanicStream"},
+		func(any, grpc.ServerStream) error { panic(errors.New("stream panic")) })
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = unary(ctx, "request", &grpc.UnaryServerInfo{FullMethod: "/test.service/OK"},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	require.NoError(t, err)
+
+	require.Len(t, reporter.errs, 2)
+	assert.EqualError(t, reporter.errs[0], "panic: unary panic")
+	assert.Equal(t, errreport.LevelFatal, reporter.events[0].Level)
+	assert.Equal(t, map[string]string{"grpc.method": "/test.service/Panic", "tenant": "acme"}, reporter.events[0].Tags)
+	assert.EqualError(t, reporter.errs[1], "panic: stream panic")
+	assert.Equal(t, map[string]string{"grpc.method": "/test.service/PanicStream"}, reporter.events[1].Tags)
+}
+
 // TestLoggingInterceptor_Context tests that context is properly passed through
 func TestLoggingInterceptor_Context(t *testing.T) {
 	t.Parallel()
@@ -474,6 +519,44 @@ func TestLoggingInterceptor_Context(t *testing.T) {
 	assert.Equal(t, "test_value", capturedCtx.Value(loggingCtxKey("test_key")))
 }
 
+// TestLoggingInterceptor_RequestID tests that the request ID from metadata reaches the handler and the log record
+func TestLoggingInterceptor_RequestID(t *testing.T) {
+	t.Parallel()
+	var logAttrs []slog.Attr
+	interceptor := LoggingInterceptor(slog.New(&attrHandler{attrs: &logAttrs}))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "req-1"))
+
+	var requestID string
+	_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: "/test.service/TestMethod"},
+		func(ctx context.Context, req any) (any, error) {
+			requestID = correlate.RequestID(ctx)
+			return "success", nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", requestID)
+	assert.Contains(t, logAttrs, slog.String(correlate.RequestIDKey, "req-1"))
+}
+
+// TestLoggingStreamInterceptor_RequestID tests that the request ID from metadata reaches the stream context
+func TestLoggingStreamInterceptor_RequestID(t *testing.T) {
+	t.Parallel()
+	var logAttrs []slog.Attr
+	interceptor := LoggingStreamInterceptor(slog.New(&attrHandler{attrs: &logAttrs}))
+	ss := &mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "req-2"))}
+
+	var requestID string
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.service/TestStream"},
+		func(srv any, stream grpc.ServerStream) error {
+			requestID = correlate.RequestID(stream.Context())
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, "req-2", requestID)
+	assert.Contains(t, logAttrs, slog.String(correlate.RequestIDKey, "req-2"))
+}
+
 // TestLoggingStreamInterceptor_Context tests that context is properly passed through streams
 func TestLoggingStreamInterceptor_Context(t *testing.T) {
 	t.Parallel()
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
@@ -9,56 +10,200 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 var (
 	meter = otel.Meter("github.com/pure-golang/adapters/grpc")
 
-	requestsCount       metric.Int64Counter
-	requestDuration     metric.Int64Histogram
-	requestPayloadSize  metric.Int64Histogram
-	responsePayloadSize metric.Int64Histogram
+	// defaultServerInstruments — инструменты глобального MeterProvider для интерцепторов без собственных настроек
+	defaultServerInstruments serverInstruments
+
+	clientRequestsCount   metric.Int64Counter
+	clientRequestDuration metric.Int64Histogram
 )
 
 func init() {
 	var err error
 
-	requestsCount, err = meter.Int64Counter(
+	defaultServerInstruments, err = newServerInstruments(meter, nil, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	clientRequestsCount, err = meter.Int64Counter(
+		"grpc.client.requests_total",
+		metric.WithDescription("Total number of outgoing gRPC requests"),
+	)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create client requests counter"))
+	}
+
+	clientRequestDuration, err = meter.Int64Histogram(
+		"grpc.client.duration_ms",
+		metric.WithDescription("Outgoing gRPC request duration in milliseconds"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create client request duration histogram"))
+	}
+}
+
+// serverInstruments — инструменты серверных метрик
+type serverInstruments struct {
+	requests      metric.Int64Counter
+	duration      metric.Int64Histogram
+	requestSize   metric.Int64Histogram
+	responseSize  metric.Int64Histogram
+	inFlight      metric.Int64UpDownCounter
+	queueDuration metric.Int64Histogram
+}
+
+// newServerInstruments создаёт инструменты серверных метрик на m; пустые границы — границы SDK по умолчанию
+func newServerInstruments(m metric.Meter, durationBounds, sizeBounds []float64) (serverInstruments, error) {
+	var instruments serverInstruments
+	var err error
+
+	instruments.requests, err = m.Int64Counter(
 		"grpc.server.requests_total",
 		metric.WithDescription("Total number of gRPC requests"),
 	)
 	if err != nil {
-		panic(errors.Wrap(err, "failed to create requests counter"))
+		return instruments, errors.Wrap(err, "failed to create requests counter")
 	}
 
-	requestDuration, err = meter.Int64Histogram(
-		"grpc.server.duration_ms",
+	durationOpts := []metric.Int64HistogramOption{
 		metric.WithDescription("gRPC request duration in milliseconds"),
 		metric.WithUnit("ms"),
-	)
+	}
+	if len(durationBounds) > 0 {
+		durationOpts = append(durationOpts, metric.WithExplicitBucketBoundaries(durationBounds...))
+	}
+	instruments.duration, err = m.Int64Histogram("grpc.server.duration_ms", durationOpts...)
 	if err != nil {
-		panic(errors.Wrap(err, "failed to create request duration histogram"))
+		return instruments, errors.Wrap(err, "failed to create request duration histogram")
 	}
 
-	requestPayloadSize, err = meter.Int64Histogram(
-		"grpc.server.request_size_bytes",
-		metric.WithDescription("gRPC request size in bytes"),
-		metric.WithUnit("bytes"),
+	instruments.inFlight, err = m.Int64UpDownCounter(
+		"grpc.server.in_flight",
+		metric.WithDescription("Number of gRPC requests currently being handled"),
 	)
 	if err != nil {
-		panic(errors.Wrap(err, "failed to create request size histogram"))
+		return instruments, errors.Wrap(err, "failed to create in-flight requests counter")
 	}
 
-	responsePayloadSize, err = meter.Int64Histogram(
-		"grpc.server.response_size_bytes",
-		metric.WithDescription("gRPC response size in bytes"),
-		metric.WithUnit("bytes"),
-	)
+	queueOpts := []metric.Int64HistogramOption{
+		metric.WithDescription("Time gRPC requests waited for a concurrency limiter slot in milliseconds"),
+		metric.WithUnit("ms"),
+	}
+	if len(durationBounds) > 0 {
+		queueOpts = append(queueOpts, metric.WithExplicitBucketBoundaries(durationBounds...))
+	}
+	instruments.queueDuration, err = m.Int64Histogram("grpc.server.queue_duration_ms", queueOpts...)
+	if err != nil {
+		return instruments, errors.Wrap(err, "failed to create queue duration histogram")
+	}
+
+	sizeOpts := func(description string) []metric.Int64HistogramOption {
+		opts := []metric.Int64HistogramOption{
+			metric.WithDescription(description),
+			metric.WithUnit("bytes"),
+		}
+		if len(sizeBounds) > 0 {
+			opts = append(opts, metric.WithExplicitBucketBoundaries(sizeBounds...))
+		}
+		return opts
+	}
+	instruments.requestSize, err = m.Int64Histogram("grpc.server.request_size_bytes", sizeOpts("gRPC request size in bytes")...)
+	if err != nil {
+		return instruments, errors.Wrap(err, "failed to create request size histogram")
+	}
+	instruments.responseSize, err = m.Int64Histogram("grpc.server.response_size_bytes", sizeOpts("gRPC response size in bytes")...)
+	if err != nil {
+		return instruments, errors.Wrap(err, "failed to create response size histogram")
+	}
+
+	return instruments, nil
+}
+
+// queueResultAdmitted и queueResultRejected — значения атрибута result метрики grpc.server.queue_duration_ms
+const (
+	queueResultAdmitted = "admitted"
+	queueResultRejected = "rejected"
+)
+
+// queueTimeKey — ключ контекста для queueTimeRecorder
+type queueTimeKey struct{}
+
+// queueTimeRecorder записывает время ожидания места в ConcurrencyLimiter; admitted — запрос допущен к обработке
+type queueTimeRecorder func(wait time.Duration, admitted bool)
+
+// recordQueueTime передаёт время ожидания интерцептору метрик выше по цепочке, если он есть
+func recordQueueTime(ctx context.Context, wait time.Duration, admitted bool) {
+	if record, ok := ctx.Value(queueTimeKey{}).(queueTimeRecorder); ok {
+		record(wait, admitted)
+	}
+}
+
+// withQueueTimeRecorder возвращает контекст, в котором ConcurrencyLimiter записывает время ожидания
+// в grpc.server.queue_duration_ms с атрибутами запроса
+func withQueueTimeRecorder(ctx, recordCtx context.Context, instruments serverInstruments, attrs []attribute.KeyValue) context.Context {
+	return context.WithValue(ctx, queueTimeKey{}, queueTimeRecorder(func(wait time.Duration, admitted bool) {
+		result := queueResultRejected
+		if admitted {
+			result = queueResultAdmitted
+		}
+		queueAttrs := append(slices.Clip(attrs), attribute.String("result", result))
+		instruments.queueDuration.Record(recordCtx, wait.Milliseconds(), metric.WithAttributes(queueAttrs...))
+	}))
+}
+
+// ServerMetrics — инструменты серверных метрик gRPC и интерцепторы, записывающие в них.
+// Инструменты создаются один раз: один ServerMetrics разделяют унарный и потоковый интерцепторы
+// и несколько серверов процесса, что исключает повторную регистрацию инструментов.
+//
+// Кроме длительности и размеров записываются число обрабатываемых запросов (grpc.server.in_flight)
+// и, если ниже по цепочке стоит [ConcurrencyLimiter], время ожидания в его очереди
+// (grpc.server.queue_duration_ms, атрибут result: admitted или rejected): рост обеих метрик
+// показывает насыщение раньше, чем растёт длительность запросов.
+type ServerMetrics struct {
+	cfg         *metricsConfig
+	instruments serverInstruments
+}
+
+// NewServerMetrics создаёт инструменты серверных метрик на meter.
+// Если meter равен nil, используется MeterProvider из WithMetricsMeterProvider или глобальный.
+// Опции задают кардинальность по методам, границы гистограмм и exemplar'ы (см. [MetricsOption]).
+func NewServerMetrics(m metric.Meter, opts ...MetricsOption) (*ServerMetrics, error) {
+	cfg := newMetricsConfig(opts)
+	instrumentsMeter := m
+	if instrumentsMeter == nil {
+		instrumentsMeter = cfg.meter()
+	}
+
+	instruments, err := newServerInstruments(instrumentsMeter, cfg.durationBounds, cfg.sizeBounds)
+	if err != nil {
+		return nil, err
+	}
+	return &ServerMetrics{cfg: cfg, instruments: instruments}, nil
+}
+
+// newServerMetrics возвращает ServerMetrics на глобальных инструментах, если опции не требуют собственных.
+// Ошибка создания инструментов передаётся в otel.Handle, и используются глобальные инструменты.
+func newServerMetrics(opts []MetricsOption) *ServerMetrics {
+	cfg := newMetricsConfig(opts)
+	sm := &ServerMetrics{cfg: cfg, instruments: defaultServerInstruments}
+	if !cfg.custom {
+		return sm
+	}
+
+	instruments, err := newServerInstruments(cfg.meter(), cfg.durationBounds, cfg.sizeBounds)
 	if err != nil {
-		panic(errors.Wrap(err, "failed to create response size histogram"))
+		otel.Handle(err)
+		return sm
 	}
+	sm.instruments = instruments
+	return sm
 }
 
 // getMessageSize возвращает размер protobuf сообщения в байтах
@@ -69,78 +214,98 @@ func getMessageSize(msg any) int64 {
 	return 0
 }
 
-// MetricsUnaryInterceptor создает интерцептор для метрик gRPC запросов
-func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+// MetricsUnaryInterceptor создает интерцептор для метрик gRPC запросов.
+// Опции позволяют ограничить кардинальность по методам, задать границы гистограмм
+// и управлять exemplar'ами (см. [MetricsOption]).
+//
+// Каждый вызов создаёт собственные инструменты, если опции меняют MeterProvider или границы гистограмм;
+// для нескольких интерцепторов и серверов используйте общий [ServerMetrics].
+func MetricsUnaryInterceptor(opts ...MetricsOption) grpc.UnaryServerInterceptor {
+	return newServerMetrics(opts).UnaryServerInterceptor()
+}
+
+// UnaryServerInterceptor возвращает интерцептор метрик унарных gRPC запросов
+func (m *ServerMetrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	cfg, instruments := m.cfg, m.instruments
+
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method, ok := cfg.label(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+
 		startTime := time.Now()
+		recordCtx := cfg.recordContext(ctx)
+
+		// Атрибуты для метрик
+		metricAttrs := cfg.requestAttributes(ctx, []attribute.KeyValue{
+			attribute.String("grpc.method", method),
+		})
 
 		// Измеряем размер запроса
 		requestSize := getMessageSize(req)
-		requestPayloadSize.Record(ctx, requestSize, metric.WithAttributes(
-			attribute.String("grpc.method", info.FullMethod),
-		))
-
-		// Атрибуты для метрик
-		metricAttrs := []attribute.KeyValue{
-			attribute.String("grpc.method", info.FullMethod),
-		}
+		instruments.requestSize.Record(recordCtx, requestSize, metric.WithAttributes(metricAttrs...))
 
 		// Обрабатываем запрос
-		resp, err := handler(ctx, req)
+		instruments.inFlight.Add(recordCtx, 1, metric.WithAttributes(metricAttrs...))
+		defer instruments.inFlight.Add(recordCtx, -1, metric.WithAttributes(metricAttrs...))
+		resp, err := handler(withQueueTimeRecorder(ctx, recordCtx, instruments, metricAttrs), req)
 
 		// Измеряем размер ответа
 		responseSize := getMessageSize(resp)
-		responsePayloadSize.Record(ctx, responseSize, metric.WithAttributes(
-			attribute.String("grpc.method", info.FullMethod),
-		))
+		instruments.responseSize.Record(recordCtx, responseSize, metric.WithAttributes(metricAttrs...))
 
 		// Записываем метрики
 		duration := time.Since(startTime)
-		requestDuration.Record(ctx, duration.Milliseconds(), metric.WithAttributes(metricAttrs...))
+		instruments.duration.Record(recordCtx, duration.Milliseconds(), metric.WithAttributes(metricAttrs...))
 
-		// Добавляем код статуса
-		statusCode := status.Code(err)
-		statusAttrs := make([]attribute.KeyValue, 0, len(metricAttrs)+1)
-		statusAttrs = append(statusAttrs, metricAttrs...)
-		statusAttrs = append(statusAttrs, attribute.String("grpc.status", statusCode.String()))
-		requestsCount.Add(ctx, 1, metric.WithAttributes(statusAttrs...))
+		// Добавляем код статуса и исход; отмены клиентом считаются отдельно от ошибок
+		statusAttrs := statusAttributes(ctx, err, metricAttrs)
+		instruments.requests.Add(recordCtx, 1, metric.WithAttributes(statusAttrs...))
 
 		return resp, err
 	}
 }
 
-// MetricsStreamInterceptor создает интерцептор для метрик потоковых gRPC запросов
-func MetricsStreamInterceptor() grpc.StreamServerInterceptor {
-	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		startTime := time.Now()
+// MetricsStreamInterceptor создает интерцептор для метрик потоковых gRPC запросов.
+// Принимает те же опции, что и MetricsUnaryInterceptor.
+func MetricsStreamInterceptor(opts ...MetricsOption) grpc.StreamServerInterceptor {
+	return newServerMetrics(opts).StreamServerInterceptor()
+}
 
-		// Определяем тип потока
-		streamType := "server_streaming"
-		if info.IsClientStream {
-			streamType = "client_streaming"
-		}
-		if info.IsClientStream && info.IsServerStream {
-			streamType = "bidi_streaming"
-		}
+// StreamServerInterceptor возвращает интерцептор метрик потоковых gRPC запросов
+func (m *ServerMetrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	cfg, instruments := m.cfg, m.instruments
 
-		metricAttrs := []attribute.KeyValue{
-			attribute.String("grpc.method", info.FullMethod),
-			attribute.String("stream.type", streamType),
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method, ok := cfg.label(info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
 		}
 
+		startTime := time.Now()
+
+		metricAttrs := cfg.requestAttributes(ss.Context(), []attribute.KeyValue{
+			attribute.String("grpc.method", method),
+			attribute.String("stream.type", streamType(info.IsClientStream, info.IsServerStream)),
+		})
+
 		// Обрабатываем поток
-		err := handler(srv, ss)
+		recordCtx := cfg.recordContext(ss.Context())
+		instruments.inFlight.Add(recordCtx, 1, metric.WithAttributes(metricAttrs...))
+		defer instruments.inFlight.Add(recordCtx, -1, metric.WithAttributes(metricAttrs...))
+		err := handler(srv, &wrappedServerStream{
+			ServerStream: ss,
+			ctx:          withQueueTimeRecorder(ss.Context(), recordCtx, instruments, metricAttrs),
+		})
 
 		// Записываем метрики
 		duration := time.Since(startTime)
-		requestDuration.Record(ss.Context(), duration.Milliseconds(), metric.WithAttributes(metricAttrs...))
-
-		// Добавляем код статуса
-		statusCode := status.Code(err)
-		statusAttrs := make([]attribute.KeyValue, 0, len(metricAttrs)+1)
-		statusAttrs = append(statusAttrs, metricAttrs...)
-		statusAttrs = append(statusAttrs, attribute.String("grpc.status", statusCode.String()))
-		requestsCount.Add(ss.Context(), 1, metric.WithAttributes(statusAttrs...))
+		instruments.duration.Record(recordCtx, duration.Milliseconds(), metric.WithAttributes(metricAttrs...))
+
+		// Добавляем код статуса и исход; отмены клиентом считаются отдельно от ошибок
+		statusAttrs := statusAttributes(ss.Context(), err, metricAttrs)
+		instruments.requests.Add(recordCtx, 1, metric.WithAttributes(statusAttrs...))
 
 		return err
 	}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OtherMethodLabel — значение атрибута grpc.method для методов, не вошедших в WithAllowedMethods
+const OtherMethodLabel = "other"
+
+// MethodLabeler возвращает значение атрибута grpc.method для полного имени метода.
+// ok == false означает, что метрики для метода не записываются.
+type MethodLabeler func(fullMethod string) (label string, ok bool)
+
+// ServiceMethodLabeler группирует методы по сервису: "/pkg.Service/Method" → "/pkg.Service"
+func ServiceMethodLabeler(fullMethod string) (string, bool) {
+	if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+		return fullMethod[:i], true
+	}
+	return fullMethod, true
+}
+
+// ClientNetworkAttribute — имя атрибута метрик с группой IP клиента (см. WithClientIPLabeler)
+const ClientNetworkAttribute = "client.network"
+
+// ClientIPLabeler возвращает значение атрибута client.network для IP клиента;
+// пустая строка — атрибут не добавляется. Множество значений должно быть ограничено.
+type ClientIPLabeler func(addr netip.Addr) string
+
+// ClientIPPrefixLabeler группирует клиентов по подсетям: IPv4 — по первым ipv4Bits битам,
+// IPv6 — по первым ipv6Bits: 203.0.113.7 при ipv4Bits = 16 → "203.0.0.0/16"
+func ClientIPPrefixLabeler(ipv4Bits, ipv6Bits int) ClientIPLabeler {
+	return func(addr netip.Addr) string {
+		bits := ipv6Bits
+		if addr.Is4() {
+			bits = ipv4Bits
+		}
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			return ""
+		}
+		return prefix.String()
+	}
+}
+
+// MetricsOption настраивает MetricsUnaryInterceptor, MetricsStreamInterceptor и NewServerMetrics
+type MetricsOption func(*metricsConfig)
+
+// WithMethodLabeler задаёт функцию, определяющую атрибут grpc.method.
+// Позволяет группировать методы (например, ServiceMethodLabeler) или отбрасывать их.
+func WithMethodLabeler(labeler MethodLabeler) MetricsOption {
+	return func(c *metricsConfig) {
+		c.labeler = labeler
+	}
+}
+
+// WithDroppedMethods отключает метрики для перечисленных методов (например, health check)
+func WithDroppedMethods(methods ...string) MetricsOption {
+	return func(c *metricsConfig) {
+		for _, m := range methods {
+			c.dropped[m] = struct{}{}
+		}
+	}
+}
+
+// WithAllowedMethods записывает перечисленные методы под собственным именем,
+// а все остальные — под OtherMethodLabel
+func WithAllowedMethods(methods ...string) MetricsOption {
+	return func(c *metricsConfig) {
+		allowed := make(map[string]struct{}, len(methods))
+		for _, m := range methods {
+			allowed[m] = struct{}{}
+		}
+		c.labeler = func(fullMethod string) (string, bool) {
+			if _, ok := allowed[fullMethod]; ok {
+				return fullMethod, true
+			}
+			return OtherMethodLabel, true
+		}
+	}
+}
+
+// WithClientIPLabeler добавляет к метрикам атрибут client.network по IP клиента из ClientIPFromContext
+// (за прокси — определённому ClientIPUnaryInterceptor). Сам IP атрибутом метрик не становится:
+// labeler должен сводить адреса к ограниченному множеству групп, например ClientIPPrefixLabeler.
+func WithClientIPLabeler(labeler ClientIPLabeler) MetricsOption {
+	return func(c *metricsConfig) {
+		c.clientIPLabeler = labeler
+	}
+}
+
+// WithDurationBuckets задаёт границы гистограмм grpc.server.duration_ms
+// и grpc.server.queue_duration_ms в миллисекундах
+func WithDurationBuckets(bounds ...float64) MetricsOption {
+	return func(c *metricsConfig) {
+		c.durationBounds = bounds
+		c.custom = true
+	}
+}
+
+// WithSizeBuckets задаёт границы гистограмм grpc.server.request_size_bytes
+// и grpc.server.response_size_bytes в байтах
+func WithSizeBuckets(bounds ...float64) MetricsOption {
+	return func(c *metricsConfig) {
+		c.sizeBounds = bounds
+		c.custom = true
+	}
+}
+
+// WithMetricsMeterProvider задаёт MeterProvider для инструментов интерцептора
+// вместо глобального otel.GetMeterProvider()
+func WithMetricsMeterProvider(provider metric.MeterProvider) MetricsOption {
+	return func(c *metricsConfig) {
+		c.meterProvider = provider
+		c.custom = true
+	}
+}
+
+// WithExemplars включает или отключает привязку измерений к trace ID текущего span'а.
+// По умолчанию включено: при записи передаётся контекст запроса, и SDK с фильтром
+// trace_based прикрепляет exemplar к семплированным запросам. Интерцептор должен
+// стоять после TracingUnaryInterceptor (см. BuildChain).
+func WithExemplars(enabled bool) MetricsOption {
+	return func(c *metricsConfig) {
+		c.exemplars = enabled
+	}
+}
+
+// metricsConfig — настройки серверных интерцепторов метрик
+type metricsConfig struct {
+	labeler         MethodLabeler
+	clientIPLabeler ClientIPLabeler
+	dropped         map[string]struct{}
+	durationBounds  []float64
+	sizeBounds      []float64
+	meterProvider   metric.MeterProvider
+	exemplars       bool
+	custom          bool // нужны собственные инструменты вместо глобальных
+}
+
+func newMetricsConfig(opts []MetricsOption) *metricsConfig {
+	c := &metricsConfig{
+		dropped:   make(map[string]struct{}),
+		exemplars: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// meter возвращает Meter из WithMetricsMeterProvider или глобального MeterProvider
+func (c *metricsConfig) meter() metric.Meter {
+	provider := c.meterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	return provider.Meter("github.com/pure-golang/adapters/grpc")
+}
+
+// label возвращает значение атрибута grpc.method; ok == false — метрики не записываются
+func (c *metricsConfig) label(fullMethod string) (string, bool) {
+	if _, ok := c.dropped[fullMethod]; ok {
+		return "", false
+	}
+	if c.labeler == nil {
+		return fullMethod, true
+	}
+	return c.labeler(fullMethod)
+}
+
+// requestAttributes возвращает атрибуты метрик запроса: attrs, арендатора и группу IP клиента
+func (c *metricsConfig) requestAttributes(ctx context.Context, attrs []attribute.KeyValue) []attribute.KeyValue {
+	attrs = appendTenantAttribute(ctx, attrs)
+	if c.clientIPLabeler == nil {
+		return attrs
+	}
+	if addr, ok := ClientIPFromContext(ctx); ok {
+		if label := c.clientIPLabeler(addr); label != "" {
+			attrs = append(attrs, attribute.String(ClientNetworkAttribute, label))
+		}
+	}
+	return attrs
+}
+
+// recordContext возвращает контекст для записи измерений.
+// При отключённых exemplar'ах span убирается из контекста, чтобы SDK не связывал измерения с трассой.
+func (c *metricsConfig) recordContext(ctx context.Context) context.Context {
+	if c.exemplars {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+}
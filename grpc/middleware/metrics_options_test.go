@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+func newTestMeterProvider() (*sdkmetric.MeterProvider, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	return sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+func durationPoints(t *testing.T, reader *sdkmetric.ManualReader) []metricdata.HistogramDataPoint[int64] {
+	t.Helper()
+	m, ok := collectMetric(t, reader, "grpc.server.duration_ms")
+	if !ok {
+		return nil
+	}
+	hist, ok := m.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	return hist.DataPoints
+}
+
+func methodLabels(t *testing.T, points []metricdata.HistogramDataPoint[int64]) []string {
+	t.Helper()
+	labels := make([]string, 0, len(points))
+	for _, dp := range points {
+		v, ok := dp.Attributes.Value(attribute.Key("grpc.method"))
+		require.True(t, ok)
+		labels = append(labels, v.AsString())
+	}
+	return labels
+}
+
+func callUnary(t *testing.T, interceptor grpc.UnaryServerInterceptor, method string) {
+	t.Helper()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+}
+
+// TestMetricsUnaryInterceptor_DurationBuckets tests custom histogram boundaries
+func TestMetricsUnaryInterceptor_DurationBuckets(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	interceptor := MetricsUnaryInterceptor(
+		WithMetricsMeterProvider(mp),
+		WithDurationBuckets(5, 50, 500),
+	)
+
+	callUnary(t, interceptor, "/test.Service/Get")
+
+	points := durationPoints(t, reader)
+	require.Len(t, points, 1)
+	assert.Equal(t, []float64{5, 50, 500}, points[0].Bounds)
+}
+
+// TestMetricsUnaryInterceptor_DroppedMethods tests that dropped methods are not recorded
+func TestMetricsUnaryInterceptor_DroppedMethods(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	interceptor := MetricsUnaryInterceptor(
+		WithMetricsMeterProvider(mp),
+		WithDroppedMethods("/grpc.health.v1.Health/Check"),
+	)
+
+	callUnary(t, interceptor, "/grpc.health.v1.Health/Check")
+	callUnary(t, interceptor, "/test.Service/Get")
+
+	assert.Equal(t, []string{"/test.Service/Get"}, methodLabels(t, durationPoints(t, reader)))
+}
+
+// TestMetricsUnaryInterceptor_AllowedMethods tests that other methods are bucketed together
+func TestMetricsUnaryInterceptor_AllowedMethods(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	interceptor := MetricsUnaryInterceptor(
+		WithMetricsMeterProvider(mp),
+		WithAllowedMethods("/test.Service/Get"),
+	)
+
+	callUnary(t, interceptor, "/test.Service/Get")
+	callUnary(t, interceptor, "/test.Service/List")
+	callUnary(t, interceptor, "/test.Service/Delete")
+
+	assert.ElementsMatch(t, []string{"/test.Service/Get", OtherMethodLabel}, methodLabels(t, durationPoints(t, reader)))
+}
+
+// TestMetricsStreamInterceptor_ServiceMethodLabeler tests grouping stream methods by service
+func TestMetricsStreamInterceptor_ServiceMethodLabeler(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	interceptor := MetricsStreamInterceptor(
+		WithMetricsMeterProvider(mp),
+		WithMethodLabeler(ServiceMethodLabeler),
+	)
+	ss := &mockServerStreamForMetrics{ctx: context.Background()}
+
+	for _, method := range []string{"/test.Service/Watch", "/test.Service/Subscribe"} {
+		err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}, func(any, grpc.ServerStream) error {
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	points := durationPoints(t, reader)
+	require.Len(t, points, 1)
+	assert.Equal(t, []string{"/test.Service"}, methodLabels(t, points))
+	assert.Equal(t, uint64(2), points[0].Count)
+}
+
+// TestMetricsUnaryInterceptor_Exemplars tests that samples are linked to the trace ID
+func TestMetricsUnaryInterceptor_Exemplars(t *testing.T) {
+	t.Parallel()
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	testCases := []struct {
+		name      string
+		enabled   bool
+		exemplars int
+	}{
+		{name: "enabled", enabled: true, exemplars: 1},
+		{name: "disabled", enabled: false, exemplars: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mp, reader := newTestMeterProvider()
+			interceptor := MetricsUnaryInterceptor(WithMetricsMeterProvider(mp), WithExemplars(tc.enabled))
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, func(context.Context, any) (any, error) {
+				return "ok", nil
+			})
+			require.NoError(t, err)
+
+			points := durationPoints(t, reader)
+			require.Len(t, points, 1)
+			require.Len(t, points[0].Exemplars, tc.exemplars)
+			if tc.exemplars > 0 {
+				traceID := span.SpanContext().TraceID()
+				assert.Equal(t, traceID[:], points[0].Exemplars[0].TraceID)
+			}
+		})
+	}
+}
+
+// TestServiceMethodLabeler tests service extraction from full method names
+func TestServiceMethodLabeler(t *testing.T) {
+	t.Parallel()
+	label, ok := ServiceMethodLabeler("/pkg.Service/Method")
+	assert.True(t, ok)
+	assert.Equal(t, "/pkg.Service", label)
+
+	label, ok = ServiceMethodLabeler("invalid")
+	assert.True(t, ok)
+	assert.Equal(t, "invalid", label)
+}
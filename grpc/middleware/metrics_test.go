@@ -3,10 +3,13 @@ package middleware
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
@@ -723,3 +726,129 @@ func TestMetricsStreamInterceptor_MeasuresDuration(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+// TestNewServerMetrics tests that unary and stream interceptors share instruments of the given meter
+func TestNewServerMetrics(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	metrics, err := NewServerMetrics(mp.Meter("test"), WithDurationBuckets(10, 100))
+	require.NoError(t, err)
+
+	callUnary(t, metrics.UnaryServerInterceptor(), "/test.Service/Get")
+	ss := &mockServerStreamForMetrics{ctx: context.Background()}
+	err = metrics.StreamServerInterceptor()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch", IsServerStream: true}, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	points := durationPoints(t, reader)
+	require.Len(t, points, 2)
+	assert.ElementsMatch(t, []string{"/test.Service/Get", "/test.Service/Watch"}, methodLabels(t, points))
+	assert.Equal(t, []float64{10, 100}, points[0].Bounds)
+}
+
+// TestNewServerMetrics_MeterProviderOption tests the meter provider option when no meter is given
+func TestNewServerMetrics_MeterProviderOption(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	metrics, err := NewServerMetrics(nil, WithMetricsMeterProvider(mp))
+	require.NoError(t, err)
+
+	callUnary(t, metrics.UnaryServerInterceptor(), "/test.Service/Get")
+
+	_, ok := collectMetric(t, reader, "grpc.server.requests_total")
+	assert.True(t, ok)
+}
+
+// TestBuildChain_ServerMetrics tests that servers built from one MonitoringOptions.ServerMetrics record to it
+func TestBuildChain_ServerMetrics(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	metrics, err := NewServerMetrics(mp.Meter("test"))
+	require.NoError(t, err)
+
+	for range 2 {
+		opts := &MonitoringOptions{EnableMetrics: true, ServerMetrics: metrics}
+		unary, stream := BuildChain(opts)
+		require.Len(t, unary, 1)
+		require.Len(t, stream, 1)
+		callUnary(t, unary[0], "/test.Service/Get")
+	}
+
+	points := durationPoints(t, reader)
+	require.Len(t, points, 1)
+	assert.Equal(t, uint64(2), points[0].Count)
+}
+
+// TestServerMetrics_InFlight tests that in-flight requests are counted while the handler runs
+func TestServerMetrics_InFlight(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	metrics, err := NewServerMetrics(mp.Meter("test"))
+	require.NoError(t, err)
+
+	inFlight := func() int64 {
+		m, ok := collectMetric(t, reader, "grpc.server.in_flight")
+		require.True(t, ok)
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+		assert.False(t, sum.IsMonotonic)
+		var total int64
+		for _, dp := range sum.DataPoints {
+			total += dp.Value
+		}
+		return total
+	}
+
+	_, err = metrics.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"},
+		func(context.Context, any) (any, error) {
+			assert.Equal(t, int64(1), inFlight())
+			return "ok", nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), inFlight())
+
+	ss := &mockServerStreamForMetrics{ctx: context.Background()}
+	err = metrics.StreamServerInterceptor()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Get"}, func(any, grpc.ServerStream) error {
+		return status.Error(codes.Internal, "failed")
+	})
+	require.Error(t, err)
+	assert.Equal(t, int64(0), inFlight())
+}
+
+// TestServerMetrics_QueueDuration tests that the concurrency limiter records queue time to the metrics above it
+func TestServerMetrics_QueueDuration(t *testing.T) {
+	t.Parallel()
+	mp, reader := newTestMeterProvider()
+	metrics, err := NewServerMetrics(mp.Meter("test"))
+	require.NoError(t, err)
+	limiter := NewConcurrencyLimiter(1, WithConcurrencyQueueTimeout(20*time.Millisecond))
+	// The metrics interceptor wraps the limiter, as in BuildChain
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return metrics.UnaryServerInterceptor()(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return limiter.UnaryServerInterceptor()(ctx, req, info, handler)
+		})
+	}
+
+	release, wait := blockingCalls(t, interceptor, "/svc/Slow", 1)
+	err = callLimited(interceptor, "/svc/Fast")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	release()
+	wait()
+
+	m, ok := collectMetric(t, reader, "grpc.server.queue_duration_ms")
+	require.True(t, ok)
+	hist, ok := m.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	results := make(map[string]metricdata.HistogramDataPoint[int64])
+	for _, dp := range hist.DataPoints {
+		method, _ := dp.Attributes.Value("grpc.method")
+		result, _ := dp.Attributes.Value("result")
+		results[method.AsString()+" "+result.AsString()] = dp
+	}
+	require.Len(t, results, 2)
+	assert.Equal(t, uint64(1), results["/svc/Slow admitted"].Count)
+	rejected := results["/svc/Fast rejected"]
+	assert.Equal(t, uint64(1), rejected.Count)
+	assert.GreaterOrEqual(t, rejected.Sum, int64(20))
+}
@@ -0,0 +1,63 @@
+package middlewaretest
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// RequireSpan возвращает span с именем name ("middlewaretest.TestService/Echo") или завершает тест
+func (h *Harness) RequireSpan(t testing.TB, name string) tracetest.SpanStub {
+	t.Helper()
+	spans := h.Spans()
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	names := make([]string, 0, len(spans))
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	t.Fatalf("span %q not found, recorded spans: %v", name, names)
+	return tracetest.SpanStub{}
+}
+
+// RequireMetric возвращает метрику с именем name ("grpc.server.requests_total") или завершает тест
+func (h *Harness) RequireMetric(t testing.TB, name string) metricdata.Metrics {
+	t.Helper()
+	rm, err := h.Metrics(context.Background())
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+	var names []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m
+			}
+			names = append(names, m.Name)
+		}
+	}
+	t.Fatalf("metric %q not found, recorded metrics: %v", name, names)
+	return metricdata.Metrics{}
+}
+
+// RequireLog возвращает первую запись с сообщением message или завершает тест
+func (h *Harness) RequireLog(t testing.TB, message string) LogEntry {
+	t.Helper()
+	entries := h.Logs()
+	for _, e := range entries {
+		if e.Message == message {
+			return e
+		}
+	}
+	messages := make([]string, 0, len(entries))
+	for _, e := range entries {
+		messages = append(messages, e.Message)
+	}
+	t.Fatalf("log %q not found, recorded logs: %v", message, messages)
+	return LogEntry{}
+}
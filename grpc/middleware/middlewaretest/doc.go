@@ -0,0 +1,29 @@
+// Package middlewaretest запускает gRPC сервер в памяти (bufconn) с цепочкой мониторинга
+// из пакета middleware и тестовым сервисом, перехватывая span'ы, метрики и логи.
+//
+// Использование:
+//
+//	rec := &middlewaretest.OrderRecorder{}
+//	opts := middleware.DefaultMonitoringOptions(nil)
+//	opts.Interceptors = []middleware.ChainInterceptor{
+//	    middleware.UnaryAt(middleware.PositionAuth, rec.Unary("auth")),
+//	}
+//	h := middlewaretest.Start(t, &middlewaretest.Options{Monitoring: opts})
+//
+//	_, err := h.Echo(ctx, "hello")
+//	h.RequireSpan(t, "middlewaretest.TestService/Echo")
+//	h.RequireMetric(t, "grpc.server.requests_total")
+//	h.RequireLog(t, "gRPC request processed")
+//
+// Тестовый сервис ([ServiceName]):
+//   - Echo — возвращает сообщение; [PanicMessage] вызывает панику, [ErrorPrefix]+"NotFound" — ошибку с кодом
+//   - Stream — серверный поток из count копий сообщения
+//
+// Перехват:
+//   - Span'ы — общий TracerProvider устанавливается глобально при первом [Start]
+//     (трассер middleware привязывается к первому провайдеру), span'ы распределяются по харнессам
+//   - Метрики — собственный MeterProvider харнесса через middleware.WithMetricsMeterProvider
+//   - Логи — логгер харнесса заменяет MonitoringOptions.Logger, см. [LogEntry]
+//
+// Харнессы изолированы друг от друга и могут использоваться в параллельных тестах.
+package middlewaretest
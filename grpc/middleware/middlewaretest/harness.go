@@ -0,0 +1,185 @@
+package middlewaretest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+const bufSize = 1 << 20
+
+// Options — параметры харнесса
+type Options struct {
+	// Monitoring — настройки цепочки; nil — middleware.DefaultMonitoringOptions.
+	// Logger заменяется логгером харнесса, к MetricsOptions добавляется MeterProvider харнесса,
+	// ServerMetrics сбрасывается, чтобы метрики записывались в MeterProvider харнесса.
+	Monitoring *middleware.MonitoringOptions
+	// Register регистрирует дополнительные сервисы рядом с тестовым
+	Register func(*grpc.Server)
+	// ServerOptions и DialOptions добавляются к опциям сервера и клиента
+	ServerOptions []grpc.ServerOption
+	DialOptions   []grpc.DialOption
+}
+
+// Harness — gRPC сервер в памяти (bufconn) с цепочкой мониторинга и перехватом span'ов, метрик и логов
+type Harness struct {
+	conn    *grpc.ClientConn
+	spans   *spanRecorder
+	metrics *sdkmetric.ManualReader
+	logs    *logRecorder
+}
+
+// Start запускает сервер с тестовым сервисом и подключённого к нему клиента.
+// Сервер и клиент останавливаются по завершении теста.
+func Start(t testing.TB, opts *Options) *Harness {
+	t.Helper()
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	h := &Harness{
+		spans:   &spanRecorder{},
+		metrics: sdkmetric.NewManualReader(),
+		logs:    newLogRecorder(),
+	}
+	tp := tracerProvider()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(h.metrics))
+	t.Cleanup(func() {
+		router.forget(h.spans)
+		_ = mp.Shutdown(context.Background())
+	})
+
+	monitoring := monitoringOptions(opts.Monitoring, slog.New(h.logs), mp)
+	// Маркер харнесса должен попасть в контекст раньше пользовательских интерцепторов
+	monitoring.Interceptors = append([]middleware.ChainInterceptor{
+		middleware.UnaryAt(middleware.PositionFirst, markUnary(h.spans)),
+		middleware.StreamAt(middleware.PositionFirst, markStream(h.spans)),
+	}, monitoring.Interceptors...)
+	unary, stream := middleware.BuildChain(monitoring)
+
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if monitoring.EnableTracing && monitoring.EnableStatsHandler {
+		serverOpts = append(serverOpts, grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp))))
+	}
+	if monitoring.EnableStatsHandler {
+		connStatsOpts := append([]middleware.ConnStatsOption{middleware.WithConnStatsLogger(monitoring.Logger)}, monitoring.ConnStatsOptions...)
+		connStatsOpts = append(connStatsOpts, middleware.WithConnStatsMeterProvider(mp))
+		serverOpts = append(serverOpts, grpc.StatsHandler(middleware.ConnectionStatsHandler(connStatsOpts...)))
+	}
+	server := grpc.NewServer(append(serverOpts, opts.ServerOptions...)...)
+	server.RegisterService(&serviceDesc, testService{})
+	if opts.Register != nil {
+		opts.Register(server)
+	}
+
+	lis := bufconn.Listen(bufSize)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts.DialOptions...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	h.conn = conn
+
+	return h
+}
+
+// monitoringOptions возвращает копию настроек с логгером и MeterProvider харнесса
+func monitoringOptions(options *middleware.MonitoringOptions, logger *slog.Logger, mp *sdkmetric.MeterProvider) *middleware.MonitoringOptions {
+	var result middleware.MonitoringOptions
+	if options != nil {
+		result = *options
+	} else {
+		result = *middleware.DefaultMonitoringOptions(nil)
+	}
+	result.Logger = logger
+	result.ServerMetrics = nil
+	result.Interceptors = slices.Clone(result.Interceptors)
+	result.MetricsOptions = append(slices.Clone(result.MetricsOptions), middleware.WithMetricsMeterProvider(mp))
+	return &result
+}
+
+// Conn возвращает клиентское подключение к серверу харнесса
+func (h *Harness) Conn() *grpc.ClientConn {
+	return h.conn
+}
+
+// Echo вызывает унарный метод тестового сервиса.
+// PanicMessage вызывает панику, ErrorPrefix+"NotFound" — ошибку с кодом NotFound.
+func (h *Harness) Echo(ctx context.Context, message string, opts ...grpc.CallOption) (string, error) {
+	resp := new(wrapperspb.StringValue)
+	if err := h.conn.Invoke(ctx, EchoMethod, wrapperspb.String(message), resp, opts...); err != nil {
+		return "", err
+	}
+	return resp.GetValue(), nil
+}
+
+// Stream вызывает серверный поток тестового сервиса и возвращает count полученных сообщений
+func (h *Harness) Stream(ctx context.Context, message string, count int, opts ...grpc.CallOption) ([]string, error) {
+	stream, err := h.conn.NewStream(ctx, &serviceDesc.Streams[0], StreamMethod, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(wrapperspb.String(strconv.Itoa(count) + ":" + message)); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for {
+		resp := new(wrapperspb.StringValue)
+		if err := stream.RecvMsg(resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				return messages, nil
+			}
+			return messages, err
+		}
+		messages = append(messages, resp.GetValue())
+	}
+}
+
+// Spans возвращает завершённые span'ы запросов к харнессу
+func (h *Harness) Spans() tracetest.SpanStubs {
+	return h.spans.snapshot()
+}
+
+// Metrics возвращает накопленные метрики интерцепторов
+func (h *Harness) Metrics(ctx context.Context) (metricdata.ResourceMetrics, error) {
+	var rm metricdata.ResourceMetrics
+	err := h.metrics.Collect(ctx, &rm)
+	return rm, err
+}
+
+// Logs возвращает записи логов интерцепторов
+func (h *Harness) Logs() []LogEntry {
+	return h.logs.entries()
+}
@@ -0,0 +1,171 @@
+package middlewaretest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// TestHarness_Echo tests that a unary call is traced, measured and logged
+func TestHarness_Echo(t *testing.T) {
+	t.Parallel()
+	h := Start(t, nil)
+
+	resp, err := h.Echo(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", resp)
+
+	span := h.RequireSpan(t, ServiceName+"/Echo")
+	assert.Equal(t, codes.Ok, span.Status.Code)
+
+	requests := h.RequireMetric(t, "grpc.server.requests_total")
+	sum, ok := requests.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+	entry := h.RequireLog(t, "gRPC request processed")
+	assert.Equal(t, slog.LevelInfo, entry.Level)
+	assert.Equal(t, EchoMethod, entry.Attrs["method"])
+}
+
+// TestHarness_Errors tests error and panic handling of the chain
+func TestHarness_Errors(t *testing.T) {
+	t.Parallel()
+	h := Start(t, nil)
+
+	_, err := h.Echo(context.Background(), ErrorPrefix+"NotFound")
+	assert.Equal(t, grpccodes.NotFound, status.Code(err))
+	assert.Equal(t, "NotFound", h.RequireLog(t, "gRPC request failed").Attrs["status_code"])
+
+	_, err = h.Echo(context.Background(), PanicMessage)
+	assert.Equal(t, grpccodes.Unavailable, status.Code(err))
+	h.RequireLog(t, "Recovered from panic in gRPC handler")
+}
+
+// TestHarness_Stream tests server streaming through the chain
+func TestHarness_Stream(t *testing.T) {
+	t.Parallel()
+	h := Start(t, nil)
+
+	messages, err := h.Stream(context.Background(), "tick", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tick", "tick", "tick"}, messages)
+	h.RequireSpan(t, ServiceName+"/Stream")
+}
+
+// TestHarness_Isolation tests that parallel harnesses do not see each other's spans
+func TestHarness_Isolation(t *testing.T) {
+	t.Parallel()
+	first := Start(t, nil)
+	second := Start(t, nil)
+
+	_, err := first.Echo(context.Background(), "one")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, first.Spans())
+	assert.Empty(t, second.Spans())
+	assert.Empty(t, second.Logs())
+}
+
+// TestHarness_InterceptorOrder tests custom interceptor positions with OrderRecorder
+func TestHarness_InterceptorOrder(t *testing.T) {
+	t.Parallel()
+	rec := &OrderRecorder{}
+	opts := middleware.DefaultMonitoringOptions(nil)
+	opts.Interceptors = []middleware.ChainInterceptor{
+		middleware.UnaryAt(middleware.PositionLast, rec.Unary("last")),
+		middleware.UnaryAt(middleware.PositionAuth, rec.Unary("auth")),
+		middleware.UnaryAt(middleware.PositionFirst, rec.Unary("first")),
+		middleware.StreamAt(middleware.PositionAuth, rec.Stream("stream-auth")),
+	}
+	h := Start(t, &Options{Monitoring: opts})
+
+	_, err := h.Echo(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "auth", "last"}, rec.Calls())
+
+	rec.Reset()
+	_, err = h.Stream(context.Background(), "hello", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stream-auth"}, rec.Calls())
+}
+
+// TestHarness_Tenant tests that the tenant reaches metrics, spans and logs of the chain
+func TestHarness_Tenant(t *testing.T) {
+	t.Parallel()
+	opts := middleware.DefaultMonitoringOptions(nil)
+	opts.TenantExtractor = middleware.LimitTenants(middleware.MetadataTenantExtractor("x-tenant-id"), "acme")
+	h := Start(t, &Options{Monitoring: opts})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "acme")
+	_, err := h.Echo(ctx, "hello")
+	require.NoError(t, err)
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "globex")
+	_, err = h.Echo(ctx, "hello")
+	require.NoError(t, err)
+
+	span := h.RequireSpan(t, ServiceName+"/Echo")
+	assert.Contains(t, span.Attributes, attribute.String(middleware.TenantAttribute, "acme"))
+
+	requests := h.RequireMetric(t, "grpc.server.requests_total")
+	sum, ok := requests.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	var tenants []string
+	for _, dp := range sum.DataPoints {
+		tenant, _ := dp.Attributes.Value(middleware.TenantAttribute)
+		tenants = append(tenants, tenant.AsString())
+	}
+	assert.ElementsMatch(t, []string{"acme", middleware.OtherTenantLabel}, tenants)
+
+	assert.Equal(t, "acme", h.RequireLog(t, "gRPC request processed").Attrs[middleware.TenantAttribute])
+}
+
+// TestParseCode tests status code lookup by name
+func TestParseCode(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, grpccodes.NotFound, parseCode("NotFound"))
+	assert.Equal(t, grpccodes.OK, parseCode("OK"))
+	assert.Equal(t, grpccodes.Unknown, parseCode("Bogus"))
+}
+
+// TestHarness_SkipMethods tests that skipped methods produce no spans, metrics or logs
+func TestHarness_SkipMethods(t *testing.T) {
+	t.Parallel()
+	opts := middleware.DefaultMonitoringOptions(nil)
+	opts.SkipMethods = []string{"/" + ServiceName + "/Echo"}
+	h := Start(t, &Options{Monitoring: opts})
+
+	_, err := h.Echo(context.Background(), "hello")
+	require.NoError(t, err)
+	_, err = h.Stream(context.Background(), "hello", 1)
+	require.NoError(t, err)
+
+	for _, span := range h.Spans() {
+		assert.NotEqual(t, ServiceName+"/Echo", span.Name)
+	}
+	h.RequireSpan(t, ServiceName+"/Stream")
+
+	requests := h.RequireMetric(t, "grpc.server.requests_total")
+	sum, ok := requests.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	for _, dp := range sum.DataPoints {
+		method, _ := dp.Attributes.Value("grpc.method")
+		assert.NotEqual(t, "/"+ServiceName+"/Echo", method.AsString())
+	}
+
+	for _, entry := range h.Logs() {
+		assert.NotEqual(t, "/"+ServiceName+"/Echo", entry.Attrs["method"], entry.Message)
+	}
+}
@@ -0,0 +1,52 @@
+package middlewaretest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// OrderRecorder создаёт интерцепторы, записывающие порядок своего вызова.
+// Позволяет проверить, где в цепочке оказались пользовательские интерцепторы.
+type OrderRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+// Unary возвращает унарный интерцептор, записывающий name при вызове
+func (r *OrderRecorder) Unary(name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		r.record(name)
+		return handler(ctx, req)
+	}
+}
+
+// Stream возвращает потоковый интерцептор, записывающий name при вызове
+func (r *OrderRecorder) Stream(name string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		r.record(name)
+		return handler(srv, ss)
+	}
+}
+
+func (r *OrderRecorder) record(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, name)
+}
+
+// Calls возвращает имена интерцепторов в порядке вызова
+func (r *OrderRecorder) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// Reset очищает записанные вызовы
+func (r *OrderRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
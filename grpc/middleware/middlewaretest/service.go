@@ -0,0 +1,110 @@
+package middlewaretest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Тестовый сервис: запросы и ответы — wrapperspb.StringValue, кодогенерация не нужна
+const (
+	ServiceName  = "middlewaretest.TestService"
+	EchoMethod   = "/" + ServiceName + "/Echo"
+	StreamMethod = "/" + ServiceName + "/Stream"
+)
+
+// Управляющие сообщения тестового сервиса
+const (
+	// PanicMessage вызывает панику в обработчике
+	PanicMessage = "panic"
+	// ErrorPrefix возвращает ошибку с кодом из сообщения: "error:NotFound"
+	ErrorPrefix = "error:"
+)
+
+// testService отвечает эхом на сообщения, кроме управляющих
+type testService struct{}
+
+func (testService) handle(message string) (string, error) {
+	if message == PanicMessage {
+		panic("middlewaretest: requested panic")
+	}
+	if name, ok := strings.CutPrefix(message, ErrorPrefix); ok {
+		return "", status.Error(parseCode(name), "middlewaretest: requested error")
+	}
+	return message, nil
+}
+
+// parseCode возвращает код по имени (codes.Code.String()); неизвестное имя — codes.Unknown
+func parseCode(name string) codes.Code {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == name {
+			return c
+		}
+	}
+	return codes.Unknown
+}
+
+func echoHandler(ctx context.Context, srv any, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := new(wrapperspb.StringValue)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	handler := func(_ context.Context, r any) (any, error) {
+		message, err := srv.(testService).handle(r.(*wrapperspb.StringValue).GetValue())
+		if err != nil {
+			return nil, err
+		}
+		return wrapperspb.String(message), nil
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: EchoMethod}, handler)
+}
+
+// streamHandler отправляет сообщение count раз; count передаётся в сообщении как "<count>:<message>"
+func streamHandler(srv any, stream grpc.ServerStream) error {
+	req := new(wrapperspb.StringValue)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+
+	var count int
+	var message string
+	if _, err := fmt.Sscanf(req.GetValue(), "%d:", &count); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid stream request %q", req.GetValue())
+	}
+	_, message, _ = strings.Cut(req.GetValue(), ":")
+
+	reply, err := srv.(testService).handle(message)
+	if err != nil {
+		return err
+	}
+	for range count {
+		if err := stream.SendMsg(wrapperspb.String(reply)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Echo",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				return echoHandler(ctx, srv, dec, interceptor)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Stream", Handler: streamHandler, ServerStreams: true},
+	},
+}
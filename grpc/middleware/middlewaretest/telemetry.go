@@ -0,0 +1,199 @@
+package middlewaretest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Трассер middleware получает глобальный TracerProvider один раз при первой установке,
+// поэтому пакет устанавливает общий провайдер и распределяет span'ы по харнессам:
+// маркер харнесса кладётся в контекст первым интерцептором, span'ы привязываются по trace ID.
+var (
+	routerOnce sync.Once
+	router     = &spanRouter{traces: make(map[trace.TraceID]*spanRecorder)}
+	routerTP   *sdktrace.TracerProvider
+)
+
+// tracerProvider возвращает общий провайдер, при первом вызове устанавливая его глобально
+func tracerProvider() *sdktrace.TracerProvider {
+	routerOnce.Do(func() {
+		routerTP = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(router))
+		otel.SetTracerProvider(routerTP)
+	})
+	return routerTP
+}
+
+// recorderKey — ключ контекста с харнессом, к которому относится запрос
+type recorderKey struct{}
+
+// markUnary и markStream помечают контекст запроса получателем span'ов
+func markUnary(rec *spanRecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(context.WithValue(ctx, recorderKey{}, rec), req)
+	}
+}
+
+func markStream(rec *spanRecorder) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &markedStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), recorderKey{}, rec)})
+	}
+}
+
+type markedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *markedStream) Context() context.Context {
+	return s.ctx
+}
+
+// spanRouter — SpanProcessor, передающий завершённые span'ы харнессу их трассы
+type spanRouter struct {
+	mu     sync.Mutex
+	traces map[trace.TraceID]*spanRecorder
+}
+
+func (r *spanRouter) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	rec, ok := parent.Value(recorderKey{}).(*spanRecorder)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	r.traces[s.SpanContext().TraceID()] = rec
+	r.mu.Unlock()
+}
+
+func (r *spanRouter) OnEnd(s sdktrace.ReadOnlySpan) {
+	r.mu.Lock()
+	rec, ok := r.traces[s.SpanContext().TraceID()]
+	r.mu.Unlock()
+	if ok {
+		rec.add(s)
+	}
+}
+
+func (r *spanRouter) Shutdown(context.Context) error   { return nil }
+func (r *spanRouter) ForceFlush(context.Context) error { return nil }
+
+// forget удаляет привязки трасс харнесса
+func (r *spanRouter) forget(rec *spanRecorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, owner := range r.traces {
+		if owner == rec {
+			delete(r.traces, id)
+		}
+	}
+}
+
+// spanRecorder хранит завершённые span'ы одного харнесса
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans tracetest.SpanStubs
+}
+
+func (r *spanRecorder) add(s sdktrace.ReadOnlySpan) {
+	r.mu.Lock()
+	r.spans = append(r.spans, tracetest.SpanStubFromReadOnlySpan(s))
+	r.mu.Unlock()
+}
+
+func (r *spanRecorder) snapshot() tracetest.SpanStubs {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.spans)
+}
+
+// LogEntry — запись лога, перехваченная харнессом.
+// Атрибуты групп хранятся с ключами через точку: "group.key".
+type LogEntry struct {
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// logRecorder — slog.Handler, сохраняющий записи в память
+type logRecorder struct {
+	store  *logStore
+	attrs  []slog.Attr
+	prefix string
+}
+
+type logStore struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+func newLogRecorder() *logRecorder {
+	return &logRecorder{store: &logStore{}}
+}
+
+func (h *logRecorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *logRecorder) Handle(_ context.Context, record slog.Record) error {
+	entry := LogEntry{Level: record.Level, Message: record.Message, Attrs: make(map[string]any)}
+	for _, a := range h.attrs {
+		addAttr(entry.Attrs, "", a)
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(entry.Attrs, h.prefix, a)
+		return true
+	})
+
+	h.store.mu.Lock()
+	h.store.entries = append(h.store.entries, entry)
+	h.store.mu.Unlock()
+	return nil
+}
+
+func (h *logRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+func (h *logRecorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+func (h *logRecorder) entries() []LogEntry {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return slices.Clone(h.store.entries)
+}
+
+// addAttr добавляет атрибут, раскрывая группы в ключи через точку
+func addAttr(attrs map[string]any, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range value.Group() {
+			addAttr(attrs, groupPrefix, ga)
+		}
+		return
+	}
+	attrs[prefix+a.Key] = value.Any()
+}
@@ -7,6 +7,9 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+
+	"github.com/pure-golang/adapters/errreport"
 )
 
 // MonitoringOptions содержит настройки мониторинга
@@ -16,6 +19,39 @@ type MonitoringOptions struct {
 	EnableMetrics      bool
 	EnableLogging      bool
 	EnableStatsHandler bool
+	// Interceptors — пользовательские интерцепторы, встраиваемые в цепочку по именованным позициям
+	Interceptors []ChainInterceptor
+	// TracingOptions — опции интерцепторов трассировки: копирование baggage в атрибуты span'а и поля лога
+	TracingOptions []TracingOption
+	// MetricsOptions — опции интерцепторов метрик: кардинальность методов, границы гистограмм, exemplar'ы
+	MetricsOptions []MetricsOption
+	// ServerMetrics — общие инструменты метрик для нескольких серверов; если задан, MetricsOptions не используются
+	ServerMetrics *ServerMetrics
+	// TenantExtractor определяет арендатора запроса; результат добавляется атрибутом tenant в метрики,
+	// span и записи лога цепочки (см. TenantUnaryInterceptor). Должен возвращать ограниченное
+	// множество значений, см. LimitTenants.
+	TenantExtractor TenantExtractor
+	// EnableRequestInfo включает извлечение языка, версии клиента и платформы из метаданных
+	// в RequestInfo контекста и поля лога (см. RequestInfoUnaryInterceptor)
+	EnableRequestInfo bool
+	// RequestInfoOptions — опции RequestInfo: язык по умолчанию, поддерживаемые языки, платформы, строгая проверка
+	RequestInfoOptions []RequestInfoOption
+	// EnablePeerInfo включает извлечение адреса клиента, ALPN и сертификата клиента (mTLS)
+	// в PeerInfo контекста, атрибуты span'а и поля лога (см. PeerInfoUnaryInterceptor)
+	EnablePeerInfo bool
+	// PeerIPExtractor определяет IP клиента за прокси (см. ForwardedPeerIPExtractor); результат доступен
+	// через ClientIPFromContext и добавляется в span и записи лога цепочки (см. ClientIPUnaryInterceptor)
+	PeerIPExtractor PeerIPExtractor
+	// ConnStatsOptions — опции ConnectionStatsHandler, устанавливаемого при EnableStatsHandler:
+	// выборка логов соединений, набор атрибутов, MeterProvider. Логгер по умолчанию — Logger.
+	ConnStatsOptions []ConnStatsOption
+	// ErrorReporter — система отслеживания ошибок (errreport/sentry), в которую Recovery
+	// отправляет восстановленные паники; nil — паники только логируются
+	ErrorReporter errreport.Reporter
+	// SkipMethods — glob-шаблоны методов (см. MatchMethod), которые не трассируются, не учитываются
+	// в метриках и не логируются, например InternalMethods. Recovery и пользовательские интерцепторы
+	// применяются ко всем методам.
+	SkipMethods []string
 }
 
 // DefaultMonitoringOptions возвращает настройки по умолчанию
@@ -36,8 +72,6 @@ func SetupMonitoring(
 	options *MonitoringOptions,
 ) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor, []grpc.ServerOption) {
 
-	unaryInterceptors := []grpc.UnaryServerInterceptor{}
-	streamInterceptors := []grpc.StreamServerInterceptor{}
 	serverOptions := []grpc.ServerOption{}
 
 	// Настраиваем трассировку OpenTelemetry
@@ -45,33 +79,34 @@ func SetupMonitoring(
 		// Устанавливаем пропагатор контекста для трассировки
 		otel.SetTextMapPropagator(MetadataTextMapPropagator())
 
-		// Добавляем интерцепторы трассировки
-		unaryInterceptors = append(unaryInterceptors, TracingUnaryInterceptor())
-		streamInterceptors = append(streamInterceptors, TracingStreamInterceptor())
-
 		// Добавляем StatsHandler для дополнительных метрик трассировки
 		if options.EnableStatsHandler {
-			serverOptions = append(serverOptions, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+			var handlerOpts []otelgrpc.Option
+			if skipper := methodSkipper(options.SkipMethods); len(skipper) > 0 {
+				handlerOpts = append(handlerOpts, otelgrpc.WithFilter(func(info *stats.RPCTagInfo) bool {
+					return !skipper.skip(info.FullMethodName)
+				}))
+			}
+			serverOptions = append(serverOptions, grpc.StatsHandler(otelgrpc.NewServerHandler(handlerOpts...)))
 		}
 	}
 
-	// Добавляем метрики Prometheus
-	if options.EnableMetrics {
-		unaryInterceptors = append(unaryInterceptors, MetricsUnaryInterceptor())
-		streamInterceptors = append(streamInterceptors, MetricsStreamInterceptor())
+	// Метрики и логи жизненного цикла соединений
+	if options.EnableStatsHandler {
+		serverOptions = append(serverOptions, grpc.StatsHandler(ConnectionStatsHandler(connStatsOptions(options)...)))
 	}
 
-	// Добавляем логирование и восстановление после паники
-	if options.EnableLogging {
-		unaryInterceptors = append(unaryInterceptors,
-			RecoveryInterceptor(options.Logger),
-			LoggingInterceptor(options.Logger),
-		)
-		streamInterceptors = append(streamInterceptors,
-			RecoveryStreamInterceptor(options.Logger),
-			LoggingStreamInterceptor(options.Logger),
-		)
-	}
+	// Собираем цепочку интерцепторов: Tracing, Metrics, Recovery, Logging и пользовательские
+	unaryInterceptors, streamInterceptors := BuildChain(options)
 
 	return unaryInterceptors, streamInterceptors, serverOptions
 }
+
+// connStatsOptions возвращает опции ConnectionStatsHandler с логгером из options
+func connStatsOptions(options *MonitoringOptions) []ConnStatsOption {
+	opts := make([]ConnStatsOption, 0, len(options.ConnStatsOptions)+1)
+	if options.Logger != nil {
+		opts = append(opts, WithConnStatsLogger(options.Logger))
+	}
+	return append(opts, options.ConnStatsOptions...)
+}
@@ -76,13 +76,13 @@ func TestSetupMonitoring_TracingDisabled(t *testing.T) {
 	// Should have interceptors for:
 	// - Metrics (1 unary, 1 stream)
 	// - Logging + Recovery (2 unary, 2 stream)
-	// No tracing interceptors or stats handler
+	// No tracing interceptors or otelgrpc stats handler
 	expectedUnaryCount := 3  // metrics + recovery + logging
 	expectedStreamCount := 3 // metrics + recovery + logging
 
 	assert.Equal(t, expectedUnaryCount, len(unaryInterceptors))
 	assert.Equal(t, expectedStreamCount, len(streamInterceptors))
-	assert.Equal(t, 0, len(serverOptions), "No server options when tracing disabled")
+	assert.Equal(t, 1, len(serverOptions), "Only the connection stats handler when tracing disabled")
 }
 
 // TestSetupMonitoring_MetricsDisabled tests SetupMonitoring with metrics disabled
@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MessageTooLargeReason — ErrorInfo.Reason в деталях ошибки превышения размера сообщения.
+// Metadata содержит method, direction ("request" или "response"), size и limit в байтах.
+const MessageTooLargeReason = "MESSAGE_TOO_LARGE"
+
+// Направления сообщения в деталях ошибки и атрибуте direction метрики
+const (
+	MessageDirectionRequest  = "request"
+	MessageDirectionResponse = "response"
+)
+
+// messageSizeErrorDomain — ErrorInfo.Domain ошибок размера сообщения
+const messageSizeErrorDomain = "grpc.io"
+
+// recvTransportFactor — во сколько раз транспортный лимит gRPC больше лимита запроса:
+// унарный запрос сверх транспортного лимита отклоняется до вызова интерцепторов
+const recvTransportFactor = 2
+
+// MessageSizeOption настраивает MessageSizeLimiter
+type MessageSizeOption func(*messageSizeConfig)
+
+// WithMessageSizeMeterProvider задаёт MeterProvider для метрики ограничителя
+// вместо глобального otel.GetMeterProvider()
+func WithMessageSizeMeterProvider(provider metric.MeterProvider) MessageSizeOption {
+	return func(c *messageSizeConfig) {
+		c.meterProvider = provider
+	}
+}
+
+type messageSizeConfig struct {
+	meterProvider metric.MeterProvider
+}
+
+// MessageSizeLimiter ограничивает размер запросов и ответов с понятными ошибками:
+// слишком большой запрос отклоняется с codes.InvalidArgument, ответ — с codes.ResourceExhausted;
+// детали ошибки содержат errdetails.ErrorInfo с причиной MessageTooLargeReason и лимитом.
+//
+// gRPC сам отклоняет сообщения больше MaxRecvMsgSize и отправляет клиенту статус
+// "received message larger than max" до того, как ошибку увидят интерцепторы. Поэтому транспортный
+// лимит (TransportRecvLimit) выставляется с запасом, а точный лимит проверяет интерцептор
+// по размеру protobuf сообщения. Запросы сверх транспортного лимита получают исходную ошибку
+// gRPC RESOURCE_EXHAUSTED и учитываются в метрике через StatsHandler.
+//
+// Метрика grpc.server.message_size.oversized_total — отклонённые сообщения
+// (атрибуты grpc.method, direction).
+type MessageSizeLimiter struct {
+	maxRecv   int
+	maxSend   int
+	oversized metric.Int64Counter
+}
+
+// NewMessageSizeLimiter создаёт ограничитель с лимитами запроса maxRecv и ответа maxSend в байтах;
+// значение <= 0 отключает соответствующую проверку.
+// Ошибка создания метрики передаётся в otel.Handle, ограничитель работает без неё.
+func NewMessageSizeLimiter(maxRecv, maxSend int, opts ...MessageSizeOption) *MessageSizeLimiter {
+	cfg := &messageSizeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	l := &MessageSizeLimiter{maxRecv: maxRecv, maxSend: maxSend}
+
+	m := meter
+	if cfg.meterProvider != nil {
+		m = cfg.meterProvider.Meter("github.com/pure-golang/adapters/grpc")
+	}
+	oversized, err := m.Int64Counter(
+		"grpc.server.message_size.oversized_total",
+		metric.WithDescription("Total number of gRPC messages rejected for exceeding the size limit"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create oversized messages counter"))
+	} else {
+		l.oversized = oversized
+	}
+	return l
+}
+
+// TransportRecvLimit возвращает значение для grpc.MaxRecvMsgSize или 0, если лимит запроса не задан
+func (l *MessageSizeLimiter) TransportRecvLimit() int {
+	if l.maxRecv <= 0 {
+		return 0
+	}
+	return l.maxRecv * recvTransportFactor
+}
+
+// UnaryServerInterceptor возвращает интерцептор, проверяющий размер запроса и ответа
+func (l *MessageSizeLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.check(ctx, info.FullMethod, MessageDirectionRequest, req); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		if err := l.check(ctx, info.FullMethod, MessageDirectionResponse, resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor возвращает интерцептор, проверяющий размер каждого сообщения потока
+func (l *MessageSizeLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &sizeLimitedStream{ServerStream: ss, limiter: l, method: info.FullMethod})
+	}
+}
+
+// StatsHandler возвращает stats.Handler, учитывающий в метрике запросы, которые gRPC отклонил
+// до вызова интерцепторов.
+//
+//	grpc.NewServer(grpc.StatsHandler(limiter.StatsHandler()), ...)
+func (l *MessageSizeLimiter) StatsHandler() stats.Handler {
+	return messageSizeStatsHandler{limiter: l}
+}
+
+// check возвращает ошибку, если protobuf сообщение m больше лимита направления
+func (l *MessageSizeLimiter) check(ctx context.Context, method, direction string, m any) error {
+	limit := l.limit(direction)
+	if limit <= 0 {
+		return nil
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if size := proto.Size(msg); size > limit {
+		l.record(ctx, method, direction)
+		return messageTooLargeError(method, direction, size, limit)
+	}
+	return nil
+}
+
+func (l *MessageSizeLimiter) limit(direction string) int {
+	if direction == MessageDirectionRequest {
+		return l.maxRecv
+	}
+	return l.maxSend
+}
+
+func (l *MessageSizeLimiter) record(ctx context.Context, method, direction string) {
+	if l.oversized == nil {
+		return
+	}
+	l.oversized.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("grpc.method", method),
+		attribute.String("direction", direction),
+	))
+}
+
+// rawMessageSizeDirection распознаёт исходную ошибку gRPC о превышении MaxRecvMsgSize или MaxSendMsgSize
+// и возвращает направление сообщения
+func rawMessageSizeDirection(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted || !strings.Contains(st.Message(), "larger than max") {
+		return "", false
+	}
+	if strings.Contains(st.Message(), "send") {
+		return MessageDirectionResponse, true
+	}
+	return MessageDirectionRequest, true
+}
+
+// messageTooLargeError возвращает статус с errdetails.ErrorInfo
+func messageTooLargeError(method, direction string, size, limit int) error {
+	code := codes.InvalidArgument
+	if direction == MessageDirectionResponse {
+		code = codes.ResourceExhausted
+	}
+	msg := fmt.Sprintf("%s message of %d bytes exceeds the limit of %d bytes", direction, size, limit)
+
+	st, err := status.New(code, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason: MessageTooLargeReason,
+		Domain: messageSizeErrorDomain,
+		Metadata: map[string]string{
+			"method":    method,
+			"direction": direction,
+			"size":      strconv.Itoa(size),
+			"limit":     strconv.Itoa(limit),
+		},
+	})
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+// sizeLimitedStream проверяет размер сообщений потока
+type sizeLimitedStream struct {
+	grpc.ServerStream
+	limiter *MessageSizeLimiter
+	method  string
+}
+
+func (s *sizeLimitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.limiter.check(s.Context(), s.method, MessageDirectionRequest, m)
+}
+
+func (s *sizeLimitedStream) SendMsg(m any) error {
+	if err := s.limiter.check(s.Context(), s.method, MessageDirectionResponse, m); err != nil {
+		return err
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// messageSizeMethodKey — ключ контекста для имени метода в messageSizeStatsHandler
+type messageSizeMethodKey struct{}
+
+// messageSizeStatsHandler учитывает исходные ошибки gRPC о превышении размера;
+// отказы интерцептора имеют другой код или сообщение и учтены при отказе
+type messageSizeStatsHandler struct {
+	limiter *MessageSizeLimiter
+}
+
+func (h messageSizeStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, messageSizeMethodKey{}, info.FullMethodName)
+}
+
+func (h messageSizeStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	end, ok := s.(*stats.End)
+	if !ok || end.Error == nil {
+		return
+	}
+	direction, ok := rawMessageSizeDirection(end.Error)
+	if !ok {
+		return
+	}
+	method, _ := ctx.Value(messageSizeMethodKey{}).(string)
+	h.limiter.record(ctx, method, direction)
+}
+
+func (h messageSizeStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h messageSizeStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// messageTooLargeInfo returns the ErrorInfo detail of a message size error.
+func messageTooLargeInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			require.Equal(t, MessageTooLargeReason, info.GetReason())
+			return info
+		}
+	}
+	require.Fail(t, "ErrorInfo detail not found", err)
+	return nil
+}
+
+// oversizedCount returns the oversized messages counter value for direction.
+func oversizedCount(t *testing.T, reader *sdkmetric.ManualReader, direction string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "grpc.server.message_size.oversized_total" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, ok := dp.Attributes.Value("direction"); ok && v.AsString() == direction {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
+
+// TestMessageSizeLimiter_Unary tests rejection of oversized unary requests with details.
+func TestMessageSizeLimiter_Unary(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	limiter := NewMessageSizeLimiter(1024, 0, WithMessageSizeMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	assert.Equal(t, 2048, limiter.TransportRecvLimit())
+
+	client := startCompressionServer(t, &responseCompression{},
+		grpc.MaxRecvMsgSize(limiter.TransportRecvLimit()),
+		grpc.StatsHandler(limiter.StatsHandler()),
+		grpc.UnaryInterceptor(limiter.UnaryServerInterceptor()),
+	)
+	ctx := context.Background()
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "small"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 1500)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "request message of 1503 bytes exceeds the limit of 1024 bytes", status.Convert(err).Message())
+	info := messageTooLargeInfo(t, err)
+	assert.Equal(t, map[string]string{
+		"method":    healthpb.Health_Check_FullMethodName,
+		"direction": MessageDirectionRequest,
+		"size":      "1503",
+		"limit":     "1024",
+	}, info.GetMetadata())
+
+	// above the transport limit gRPC rejects the request before interceptors
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 4096)})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	assert.Equal(t, int64(2), oversizedCount(t, reader, MessageDirectionRequest))
+}
+
+// TestMessageSizeLimiter_Stream tests that stream messages are checked on receive.
+func TestMessageSizeLimiter_Stream(t *testing.T) {
+	t.Parallel()
+	limiter := NewMessageSizeLimiter(1024, 0)
+	client := startCompressionServer(t, &responseCompression{},
+		grpc.MaxRecvMsgSize(limiter.TransportRecvLimit()),
+		grpc.StreamInterceptor(limiter.StreamServerInterceptor()),
+	)
+	ctx := context.Background()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 1500)})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// above the transport limit gRPC rejects the message before interceptors
+	stream, err = client.Watch(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 4096)})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// TestMessageSizeLimiter_Response tests that oversized responses are rejected.
+func TestMessageSizeLimiter_Response(t *testing.T) {
+	t.Parallel()
+	interceptor := NewMessageSizeLimiter(0, 1).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: healthpb.Health_Check_FullMethodName}
+
+	_, err := interceptor(context.Background(), &healthpb.HealthCheckRequest{}, info, func(context.Context, any) (any, error) {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, MessageDirectionResponse, messageTooLargeInfo(t, err).GetMetadata()["direction"])
+}
+
+// TestRawMessageSizeDirection tests recognition of gRPC message size errors.
+func TestRawMessageSizeDirection(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		message   string
+		direction string
+		ok        bool
+	}{
+		{message: "grpc: received message larger than max (5000 vs. 4096)", direction: MessageDirectionRequest, ok: true},
+		{message: "grpc: received message after decompression larger than max 4096", direction: MessageDirectionRequest, ok: true},
+		{message: "grpc: trying to send message larger than max (10 vs. 5)", direction: MessageDirectionResponse, ok: true},
+		{message: "quota exceeded"},
+	}
+	for _, tt := range tests {
+		direction, ok := rawMessageSizeDirection(status.Error(codes.ResourceExhausted, tt.message))
+		assert.Equal(t, tt.ok, ok, tt.message)
+		assert.Equal(t, tt.direction, direction, tt.message)
+	}
+	_, ok := rawMessageSizeDirection(status.Error(codes.InvalidArgument, "request message of 5000 bytes exceeds the limit of 4096 bytes"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+	"log/slog"
+	"net"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// PeerInfo — сведения о соединении клиента: адрес и, для TLS, согласованный протокол
+// и сертификат клиента (mTLS)
+type PeerInfo struct {
+	// Addr — адрес клиента ("10.0.0.1:52344"); это адрес соединения, за прокси — адрес прокси
+	Addr string
+	// TLS — соединение защищено TLS
+	TLS bool
+	// ALPN — протокол, согласованный через ALPN ("h2"); пусто без TLS
+	ALPN string
+	// ServerName — имя сервера из SNI
+	ServerName string
+
+	// Certificate — сертификат клиента; nil, если клиент его не предъявил
+	Certificate *x509.Certificate
+	// Verified — сертификат проверен по доверенным CA сервера (tls.RequireAndVerifyClientCert
+	// или tls.VerifyClientCertIfGiven); непроверенному сертификату нельзя доверять для авторизации
+	Verified bool
+	// Subject — Subject сертификата клиента в формате RFC 2253 ("CN=orders,O=Acme")
+	Subject string
+	// CommonName — CN сертификата клиента
+	CommonName string
+	// DNSNames, URIs, IPAddresses и EmailAddresses — Subject Alternative Names сертификата клиента;
+	// URIs содержит, например, SPIFFE ID ("spiffe://acme.internal/orders")
+	DNSNames       []string
+	URIs           []string
+	IPAddresses    []string
+	EmailAddresses []string
+}
+
+// HasClientCert сообщает, что клиент предъявил проверенный сертификат
+func (p PeerInfo) HasClientCert() bool {
+	return p.Certificate != nil && p.Verified
+}
+
+// peerInfoKey — ключ контекста с PeerInfo
+type peerInfoKey struct{}
+
+// PeerInfoFromContext возвращает PeerInfo, сохранённую PeerInfoUnaryInterceptor или PeerInfoStreamInterceptor.
+// Без интерцептора сведения извлекаются из peer.FromContext при каждом вызове;
+// false — в контексте нет сведений о соединении.
+func PeerInfoFromContext(ctx context.Context) (PeerInfo, bool) {
+	if info, ok := ctx.Value(peerInfoKey{}).(PeerInfo); ok {
+		return info, true
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return PeerInfo{}, false
+	}
+	return newPeerInfo(p), true
+}
+
+// PeerInfoUnaryInterceptor сохраняет сведения о соединении клиента в PeerInfo контекста
+// (см. PeerInfoFromContext), чтобы слои авторизации и аудита не разбирали peer.FromContext каждый сам.
+// Адрес клиента и сертификат добавляются в атрибуты текущего span'а (client.address, tls.next_protocol,
+// tls.client.subject, tls.client.server_name), в поля логгера контекста (logger.FromContext)
+// и в записи интерцепторов recovery и логирования, стоящих после него (peer_address, peer_subject).
+func PeerInfoUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withPeerInfo(ctx), req)
+	}
+}
+
+// PeerInfoStreamInterceptor сохраняет PeerInfo в контексте потока, как PeerInfoUnaryInterceptor
+func PeerInfoStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withPeerInfo(ss.Context())
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withPeerInfo сохраняет PeerInfo в контексте, span'е и логгере контекста
+func withPeerInfo(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info := newPeerInfo(p)
+
+	trace.SpanFromContext(ctx).SetAttributes(peerSpanAttributes(info)...)
+	ctx = context.WithValue(ctx, peerInfoKey{}, info)
+	return logger.NewContext(ctx, logger.FromContext(ctx).With(peerLogFields(ctx)...))
+}
+
+// newPeerInfo извлекает сведения о соединении из peer
+func newPeerInfo(p *peer.Peer) PeerInfo {
+	var info PeerInfo
+	if p.Addr != nil {
+		info.Addr = p.Addr.String()
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return info
+	}
+	state := tlsInfo.State
+	info.TLS = true
+	info.ALPN = state.NegotiatedProtocol
+	info.ServerName = state.ServerName
+	if len(state.PeerCertificates) == 0 {
+		return info
+	}
+
+	cert := state.PeerCertificates[0]
+	info.Certificate = cert
+	info.Verified = len(state.VerifiedChains) > 0
+	info.Subject = cert.Subject.String()
+	info.CommonName = cert.Subject.CommonName
+	info.DNSNames = cert.DNSNames
+	info.EmailAddresses = cert.EmailAddresses
+	for _, uri := range cert.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	return info
+}
+
+// peerSpanAttributes возвращает атрибуты span'а по соглашениям OpenTelemetry
+func peerSpanAttributes(info PeerInfo) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if host, _, err := net.SplitHostPort(info.Addr); err == nil {
+		attrs = append(attrs, attribute.String("client.address", host))
+	} else if info.Addr != "" {
+		attrs = append(attrs, attribute.String("client.address", info.Addr))
+	}
+	if info.ALPN != "" {
+		attrs = append(attrs, attribute.String("tls.next_protocol", info.ALPN))
+	}
+	if info.ServerName != "" {
+		attrs = append(attrs, attribute.String("tls.client.server_name", info.ServerName))
+	}
+	if info.Subject != "" {
+		attrs = append(attrs, attribute.String("tls.client.subject", info.Subject))
+	}
+	return attrs
+}
+
+// peerLogFields возвращает поля лога с адресом клиента и Subject его сертификата, если PeerInfo сохранена
+func peerLogFields(ctx context.Context) []any {
+	info, ok := ctx.Value(peerInfoKey{}).(PeerInfo)
+	if !ok {
+		return nil
+	}
+	fields := []any{slog.String("peer_address", info.Addr)}
+	if info.Subject != "" {
+		fields = append(fields, slog.String("peer_subject", info.Subject))
+	}
+	return fields
+}
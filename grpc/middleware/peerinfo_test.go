@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log/slog"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// mtlsPeerContext returns a context with a peer that connected over mTLS with a verified client certificate.
+func mtlsPeerContext(ctx context.Context) context.Context {
+	spiffe, _ := url.Parse("spiffe://acme.internal/orders")
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "orders", Organization: []string{"Acme"}},
+		DNSNames:       []string{"orders.acme.internal"},
+		URIs:           []*url.URL{spiffe},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.7")},
+		EmailAddresses: []string{"orders@acme.internal"},
+	}
+	return peer.NewContext(ctx, &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 52344},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			NegotiatedProtocol: "h2",
+			ServerName:         "api.acme.internal",
+			PeerCertificates:   []*x509.Certificate{cert},
+			VerifiedChains:     [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+// TestPeerInfoFromContext tests extraction of the peer address and TLS identity.
+func TestPeerInfoFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := PeerInfoFromContext(context.Background())
+	assert.False(t, ok)
+
+	info, ok := PeerInfoFromContext(peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 52344},
+	}))
+	require.True(t, ok)
+	assert.Equal(t, PeerInfo{Addr: "10.0.0.1:52344"}, info)
+	assert.False(t, info.HasClientCert())
+
+	info, ok = PeerInfoFromContext(mtlsPeerContext(context.Background()))
+	require.True(t, ok)
+	assert.True(t, info.TLS)
+	assert.Equal(t, "h2", info.ALPN)
+	assert.Equal(t, "api.acme.internal", info.ServerName)
+	assert.True(t, info.HasClientCert())
+	assert.Equal(t, "CN=orders,O=Acme", info.Subject)
+	assert.Equal(t, "orders", info.CommonName)
+	assert.Equal(t, []string{"orders.acme.internal"}, info.DNSNames)
+	assert.Equal(t, []string{"spiffe://acme.internal/orders"}, info.URIs)
+	assert.Equal(t, []string{"10.0.0.7"}, info.IPAddresses)
+	assert.Equal(t, []string{"orders@acme.internal"}, info.EmailAddresses)
+}
+
+// TestPeerInfo_UnverifiedCertificate tests that a certificate without a verified chain is not trusted.
+func TestPeerInfo_UnverifiedCertificate(t *testing.T) {
+	t.Parallel()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 52344},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "mallory"}}},
+		}},
+	})
+
+	info, ok := PeerInfoFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "mallory", info.CommonName)
+	assert.False(t, info.Verified)
+	assert.False(t, info.HasClientCert())
+}
+
+// TestPeerInfoUnaryInterceptor tests that the peer info reaches the span, the context logger and the handler.
+func TestPeerInfoUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var buf bytes.Buffer
+	ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, span := tp.Tracer("test").Start(mtlsPeerContext(ctx), "request")
+
+	_, err := PeerInfoUnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		info, ok := PeerInfoFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "orders", info.CommonName)
+		logger.FromContext(ctx).InfoContext(ctx, "handled")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	span.End()
+
+	assert.Contains(t, buf.String(), `peer_address=10.0.0.1:52344 peer_subject="CN=orders,O=Acme"`)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Subset(t, spans[0].Attributes(), []attribute.KeyValue{
+		attribute.String("client.address", "10.0.0.1"),
+		attribute.String("tls.next_protocol", "h2"),
+		attribute.String("tls.client.server_name", "api.acme.internal"),
+		attribute.String("tls.client.subject", "CN=orders,O=Acme"),
+	})
+}
+
+// TestPeerInfoStreamInterceptor tests that the peer info reaches the stream context.
+func TestPeerInfoStreamInterceptor(t *testing.T) {
+	t.Parallel()
+	ctx := mtlsPeerContext(context.Background())
+
+	err := PeerInfoStreamInterceptor()(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(_ any, ss grpc.ServerStream) error {
+			info, ok := ss.Context().Value(peerInfoKey{}).(PeerInfo)
+			assert.True(t, ok)
+			assert.Equal(t, []string{"spiffe://acme.internal/orders"}, info.URIs)
+			return nil
+		})
+	require.NoError(t, err)
+
+	// Без peer в контексте поток передаётся без изменений
+	ss := &fakeServerStream{ctx: context.Background()}
+	err = PeerInfoStreamInterceptor()(nil, ss, &grpc.StreamServerInfo{}, func(_ any, got grpc.ServerStream) error {
+		assert.Same(t, ss, got)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// TestBuildChain_PeerInfo tests that BuildChain adds the peer info to the request log record.
+func TestBuildChain_PeerInfo(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	unary, _ := BuildChain(&MonitoringOptions{
+		Logger:         slog.New(slog.NewTextHandler(&buf, nil)),
+		EnableLogging:  true,
+		EnablePeerInfo: true,
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	next := grpc.UnaryHandler(func(context.Context, any) (any, error) { return "response", nil })
+	for i := len(unary) - 1; i >= 0; i-- {
+		interceptor, h := unary[i], next
+		next = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, h)
+		}
+	}
+	_, err := next(mtlsPeerContext(context.Background()), "request")
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `peer_address=10.0.0.1:52344 peer_subject="CN=orders,O=Acme"`)
+}
@@ -0,0 +1,299 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Заголовки метаданных, из которых RequestInfoUnaryInterceptor извлекает RequestInfo по умолчанию
+const (
+	AcceptLanguageHeader = "accept-language"
+	ClientVersionHeader  = "x-client-version"
+	PlatformHeader       = "x-platform"
+)
+
+// Platform — платформа клиента
+type Platform string
+
+const (
+	PlatformUnknown Platform = "unknown" // заголовок не передан или значение не из списка допустимых
+	PlatformIOS     Platform = "ios"
+	PlatformAndroid Platform = "android"
+	PlatformWeb     Platform = "web"
+	PlatformDesktop Platform = "desktop"
+)
+
+// RequestInfo — сведения о клиенте из метаданных запроса
+type RequestInfo struct {
+	// Language — язык ответа в каноническом виде BCP 47 (ru, en-US): первый язык из accept-language
+	// или, если задан WithSupportedLanguages, наиболее подходящий из поддерживаемых;
+	// язык по умолчанию, если заголовка нет, он некорректен или подходящего языка нет
+	Language string
+	// Languages — языки из accept-language в порядке предпочтения
+	Languages []string
+	// ClientVersion — версия клиента без префикса "v" (1.2.3, 2.0.0-beta.1); пусто — не передана или некорректна
+	ClientVersion string
+	// Platform — платформа клиента в нижнем регистре; PlatformUnknown — не передана или не из списка допустимых
+	Platform Platform
+}
+
+// ClientVersionAtLeast сообщает, что версия клиента не ниже minVersion; сравниваются числовые компоненты,
+// суффиксы пре-релиза не учитываются. Для неизвестной версии возвращает false.
+func (ri RequestInfo) ClientVersionAtLeast(minVersion string) bool {
+	if ri.ClientVersion == "" {
+		return false
+	}
+	have, want := versionNumbers(ri.ClientVersion), versionNumbers(strings.TrimPrefix(minVersion, "v"))
+	for i := range max(len(have), len(want)) {
+		var h, w int
+		if i < len(have) {
+			h = have[i]
+		}
+		if i < len(want) {
+			w = want[i]
+		}
+		if h != w {
+			return h > w
+		}
+	}
+	return true
+}
+
+// RequestInfoOption настраивает RequestInfoUnaryInterceptor и RequestInfoStreamInterceptor
+type RequestInfoOption func(*requestInfoConfig)
+
+// WithDefaultLanguage задаёт язык по умолчанию (по умолчанию "en")
+func WithDefaultLanguage(lang string) RequestInfoOption {
+	return func(c *requestInfoConfig) {
+		c.defaultLanguage = lang
+	}
+}
+
+// WithSupportedLanguages ограничивает RequestInfo.Language поддерживаемыми языками: выбирается
+// наиболее подходящий по accept-language с учётом региона (en-GB → en), иначе язык по умолчанию
+func WithSupportedLanguages(langs ...string) RequestInfoOption {
+	return func(c *requestInfoConfig) {
+		c.supported = langs
+	}
+}
+
+// WithAllowedPlatforms задаёт допустимые платформы вместо набора по умолчанию
+// (PlatformIOS, PlatformAndroid, PlatformWeb, PlatformDesktop)
+func WithAllowedPlatforms(platforms ...Platform) RequestInfoOption {
+	return func(c *requestInfoConfig) {
+		c.platforms = platforms
+	}
+}
+
+// WithRequestInfoHeaders задаёт заголовки метаданных вместо AcceptLanguageHeader, ClientVersionHeader
+// и PlatformHeader; пустое значение оставляет заголовок по умолчанию
+func WithRequestInfoHeaders(languageHeader, clientVersionHeader, platformHeader string) RequestInfoOption {
+	return func(c *requestInfoConfig) {
+		if languageHeader != "" {
+			c.languageHeader = languageHeader
+		}
+		if clientVersionHeader != "" {
+			c.versionHeader = clientVersionHeader
+		}
+		if platformHeader != "" {
+			c.platformHeader = platformHeader
+		}
+	}
+}
+
+// WithStrictRequestInfo отклоняет запросы с некорректными значениями заголовков с кодом InvalidArgument
+// вместо подстановки значений по умолчанию. Отсутствующие заголовки допустимы.
+func WithStrictRequestInfo() RequestInfoOption {
+	return func(c *requestInfoConfig) {
+		c.strict = true
+	}
+}
+
+type requestInfoConfig struct {
+	defaultLanguage string
+	supported       []string
+	platforms       []Platform
+	languageHeader  string
+	versionHeader   string
+	platformHeader  string
+	strict          bool
+
+	matcher     language.Matcher
+	platformSet map[Platform]struct{}
+}
+
+// requestInfoKey — ключ контекста с RequestInfo
+type requestInfoKey struct{}
+
+// RequestInfoFromContext возвращает RequestInfo, извлечённую RequestInfoUnaryInterceptor
+// или RequestInfoStreamInterceptor
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	ri, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return ri, ok
+}
+
+// RequestInfoUnaryInterceptor извлекает язык (accept-language), версию клиента (x-client-version)
+// и платформу (x-platform) из метаданных в RequestInfo контекста (см. RequestInfoFromContext).
+// Некорректные значения заменяются значениями по умолчанию (см. WithStrictRequestInfo).
+// Поля language, client_version и platform добавляются в логгер контекста (logger.FromContext)
+// и в записи интерцепторов recovery и логирования, стоящих после него.
+func RequestInfoUnaryInterceptor(opts ...RequestInfoOption) grpc.UnaryServerInterceptor {
+	cfg := newRequestInfoConfig(opts)
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := cfg.withRequestInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RequestInfoStreamInterceptor извлекает RequestInfo для потока, как RequestInfoUnaryInterceptor
+func RequestInfoStreamInterceptor(opts ...RequestInfoOption) grpc.StreamServerInterceptor {
+	cfg := newRequestInfoConfig(opts)
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := cfg.withRequestInfo(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func newRequestInfoConfig(opts []RequestInfoOption) *requestInfoConfig {
+	cfg := &requestInfoConfig{
+		defaultLanguage: "en",
+		platforms:       []Platform{PlatformIOS, PlatformAndroid, PlatformWeb, PlatformDesktop},
+		languageHeader:  AcceptLanguageHeader,
+		versionHeader:   ClientVersionHeader,
+		platformHeader:  PlatformHeader,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if len(cfg.supported) > 0 {
+		tags := make([]language.Tag, 0, len(cfg.supported))
+		for _, lang := range cfg.supported {
+			tags = append(tags, language.Make(lang))
+		}
+		cfg.matcher = language.NewMatcher(tags)
+	}
+	cfg.platformSet = make(map[Platform]struct{}, len(cfg.platforms))
+	for _, p := range cfg.platforms {
+		cfg.platformSet[Platform(strings.ToLower(string(p)))] = struct{}{}
+	}
+	return cfg
+}
+
+// withRequestInfo сохраняет RequestInfo в контексте и поля лога в логгере контекста
+func (c *requestInfoConfig) withRequestInfo(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ri := RequestInfo{Language: c.defaultLanguage, Platform: PlatformUnknown}
+
+	if value := firstMetadataValue(md, c.languageHeader); value != "" {
+		if err := c.parseLanguage(&ri, value); err != nil && c.strict {
+			return ctx, status.Errorf(codes.InvalidArgument, "invalid %s metadata", c.languageHeader)
+		}
+	}
+	if value := firstMetadataValue(md, c.versionHeader); value != "" {
+		version := strings.TrimPrefix(strings.TrimSpace(value), "v")
+		switch {
+		case len(version) <= maxClientVersionLength && clientVersionPattern.MatchString(version):
+			ri.ClientVersion = version
+		case c.strict:
+			return ctx, status.Errorf(codes.InvalidArgument, "invalid %s metadata", c.versionHeader)
+		}
+	}
+	if value := firstMetadataValue(md, c.platformHeader); value != "" {
+		platform := Platform(strings.ToLower(strings.TrimSpace(value)))
+		_, ok := c.platformSet[platform]
+		switch {
+		case ok:
+			ri.Platform = platform
+		case c.strict:
+			return ctx, status.Errorf(codes.InvalidArgument, "invalid %s metadata", c.platformHeader)
+		}
+	}
+
+	ctx = context.WithValue(ctx, requestInfoKey{}, ri)
+	return logger.NewContext(ctx, logger.FromContext(ctx).With(requestInfoLogFields(ctx)...)), nil
+}
+
+// parseLanguage заполняет Language и Languages по значению accept-language
+func (c *requestInfoConfig) parseLanguage(ri *RequestInfo, value string) error {
+	tags, _, err := language.ParseAcceptLanguage(value)
+	if err != nil {
+		return err
+	}
+	ri.Languages = make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ri.Languages = append(ri.Languages, tag.String())
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if c.matcher == nil {
+		ri.Language = ri.Languages[0]
+		return nil
+	}
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence != language.No {
+		ri.Language = c.supported[index]
+	}
+	return nil
+}
+
+// maxClientVersionLength ограничивает длину версии клиента, чтобы в логи не попадали произвольные строки
+const maxClientVersionLength = 64
+
+// clientVersionPattern — версия из 1–4 числовых компонентов с необязательными суффиксами пре-релиза и сборки
+var clientVersionPattern = regexp.MustCompile(`^\d+(\.\d+){0,3}(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// versionNumbers возвращает числовые компоненты версии до суффиксов
+func versionNumbers(version string) []int {
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	var numbers []int
+	for part := range strings.SplitSeq(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requestInfoLogFields возвращает поля лога с языком, версией клиента и платформой, если RequestInfo извлечена
+func requestInfoLogFields(ctx context.Context) []any {
+	ri, ok := RequestInfoFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	fields := []any{slog.String("language", ri.Language), slog.String("platform", string(ri.Platform))}
+	if ri.ClientVersion != "" {
+		fields = append(fields, slog.String("client_version", ri.ClientVersion))
+	}
+	return fields
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// extractRequestInfo runs the unary interceptor with the given metadata and returns the extracted RequestInfo.
+func extractRequestInfo(t *testing.T, md metadata.MD, opts ...RequestInfoOption) (RequestInfo, error) {
+	t.Helper()
+	var info RequestInfo
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := RequestInfoUnaryInterceptor(opts...)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		var ok bool
+		info, ok = RequestInfoFromContext(ctx)
+		assert.True(t, ok)
+		return nil, nil
+	})
+	return info, err
+}
+
+// TestRequestInfoUnaryInterceptor tests extraction and normalization of the request info headers.
+func TestRequestInfoUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		md   metadata.MD
+		opts []RequestInfoOption
+		want RequestInfo
+	}{
+		{
+			name: "no metadata",
+			md:   metadata.MD{},
+			want: RequestInfo{Language: "en", Platform: PlatformUnknown},
+		},
+		{
+			name: "all headers",
+			md:   metadata.Pairs(AcceptLanguageHeader, "ru-RU,ru;q=0.9,en;q=0.8", ClientVersionHeader, "v2.3.1", PlatformHeader, "iOS"),
+			want: RequestInfo{Language: "ru-RU", Languages: []string{"ru-RU", "ru", "en"}, ClientVersion: "2.3.1", Platform: PlatformIOS},
+		},
+		{
+			name: "supported languages",
+			md:   metadata.Pairs(AcceptLanguageHeader, "de;q=0.9,en-GB;q=0.8"),
+			opts: []RequestInfoOption{WithSupportedLanguages("ru", "en")},
+			want: RequestInfo{Language: "en", Languages: []string{"de", "en-GB"}, Platform: PlatformUnknown},
+		},
+		{
+			name: "no supported language",
+			md:   metadata.Pairs(AcceptLanguageHeader, "de"),
+			opts: []RequestInfoOption{WithSupportedLanguages("ru", "en"), WithDefaultLanguage("ru")},
+			want: RequestInfo{Language: "ru", Languages: []string{"de"}, Platform: PlatformUnknown},
+		},
+		{
+			name: "invalid values fall back to defaults",
+			md:   metadata.Pairs(AcceptLanguageHeader, "not a language!!", ClientVersionHeader, "latest", PlatformHeader, "symbian"),
+			want: RequestInfo{Language: "en", Platform: PlatformUnknown},
+		},
+		{
+			name: "custom headers and platforms",
+			md:   metadata.Pairs("x-lang", "fr", "x-app-version", "1.0.0-beta.2+45", "x-os", "tv"),
+			opts: []RequestInfoOption{WithRequestInfoHeaders("x-lang", "x-app-version", "x-os"), WithAllowedPlatforms("tv")},
+			want: RequestInfo{Language: "fr", Languages: []string{"fr"}, ClientVersion: "1.0.0-beta.2+45", Platform: "tv"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			info, err := extractRequestInfo(t, tt.md, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, info)
+		})
+	}
+}
+
+// TestRequestInfoUnaryInterceptor_Strict tests that invalid headers are rejected in strict mode.
+func TestRequestInfoUnaryInterceptor_Strict(t *testing.T) {
+	t.Parallel()
+	for _, md := range []metadata.MD{
+		metadata.Pairs(AcceptLanguageHeader, "not a language!!"),
+		metadata.Pairs(ClientVersionHeader, "latest"),
+		metadata.Pairs(PlatformHeader, "symbian"),
+	} {
+		_, err := extractRequestInfo(t, md, WithStrictRequestInfo())
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), md)
+	}
+
+	info, err := extractRequestInfo(t, metadata.MD{}, WithStrictRequestInfo())
+	require.NoError(t, err)
+	assert.Equal(t, "en", info.Language)
+}
+
+// TestRequestInfoStreamInterceptor tests that the request info reaches the stream context and the context logger.
+func TestRequestInfoStreamInterceptor(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(AcceptLanguageHeader, "ru", ClientVersionHeader, "3.1", PlatformHeader, "android"))
+
+	err := RequestInfoStreamInterceptor()(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(_ any, ss grpc.ServerStream) error {
+			info, ok := RequestInfoFromContext(ss.Context())
+			assert.True(t, ok)
+			assert.Equal(t, PlatformAndroid, info.Platform)
+			logger.FromContext(ss.Context()).InfoContext(ss.Context(), "handled")
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "language=ru platform=android client_version=3.1")
+}
+
+func TestRequestInfo_ClientVersionAtLeast(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		version string
+		min     string
+		want    bool
+	}{
+		{"2.3.1", "2.3", true},
+		{"2.3", "2.3.0", true},
+		{"2.10.0", "2.9.9", true},
+		{"2.2.9", "2.3", false},
+		{"3.0.0-beta.1", "v3", true},
+		{"", "1.0", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RequestInfo{ClientVersion: tt.version}.ClientVersionAtLeast(tt.min), tt.version+" >= "+tt.min)
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"path"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// InternalMethods — шаблоны служебных методов для MonitoringOptions.SkipMethods:
+// проверки здоровья и reflection
+var InternalMethods = []string{
+	"/grpc.health.v1.Health/*",
+	"/grpc.reflection.v1.ServerReflection/*",
+	"/grpc.reflection.v1alpha.ServerReflection/*",
+}
+
+// MatchMethod сообщает, подходит ли полное имя метода ("/pkg.Service/Method") под один из glob-шаблонов.
+// Синтаксис шаблонов — path.Match: "*" не захватывает "/", поэтому все методы сервиса —
+// "/pkg.Service/*", а методы всех сервисов пакета — "/pkg.*/*".
+// Некорректный шаблон возвращает ошибку, даже если метод подошёл под другой шаблон:
+// функцией удобно проверять шаблоны в тестах и при старте.
+func MatchMethod(patterns []string, method string) (bool, error) {
+	matched := false
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, method)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid method pattern %q", pattern)
+		}
+		matched = matched || ok
+	}
+	return matched, nil
+}
+
+// methodSkipper пропускает методы, подходящие под шаблоны MonitoringOptions.SkipMethods,
+// мимо интерцепторов; некорректные шаблоны ни с чем не совпадают
+type methodSkipper []string
+
+// skip сообщает, что метод не нужно обрабатывать
+func (s methodSkipper) skip(method string) bool {
+	for _, pattern := range s {
+		if ok, _ := path.Match(pattern, method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// unary возвращает интерцептор, вызывающий обработчик напрямую для пропускаемых методов
+func (s methodSkipper) unary(interceptor grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	if len(s) == 0 {
+		return interceptor
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if s.skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+// stream — потоковый вариант unary
+func (s methodSkipper) stream(interceptor grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	if len(s) == 0 {
+		return interceptor
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if s.skip(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return interceptor(srv, ss, info, handler)
+	}
+}
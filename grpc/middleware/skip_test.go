@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// TestMatchMethod tests glob matching of full method names and invalid pattern errors
+func TestMatchMethod(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"/grpc.health.v1.Health/Check", true},
+		{"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", true},
+		{"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", true},
+		{"/svc.Orders/Get", false},
+	}
+	for _, tt := range tests {
+		got, err := MatchMethod(InternalMethods, tt.method)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.method)
+	}
+
+	got, err := MatchMethod([]string{"/svc.*/*"}, "/svc.Orders/Get")
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	_, err = MatchMethod([]string{"/svc.Orders/*", "/svc.[/*"}, "/svc.Orders/Get")
+	assert.ErrorContains(t, err, `invalid method pattern "/svc.[/*"`)
+}
+
+// TestBuildChain_SkipMethods tests that skipped methods bypass tracing, metrics and logging but not recovery
+func TestBuildChain_SkipMethods(t *testing.T) {
+	t.Parallel()
+	opts := DefaultMonitoringOptions(nil)
+	opts.SkipMethods = InternalMethods
+	unary, _ := BuildChain(opts)
+
+	handler := func(context.Context, any) (any, error) { panic("boom") }
+	var err error
+	assert.NotPanics(t, func() {
+		_, err = callChain(unary, "/grpc.health.v1.Health/Check", handler)
+	})
+	assert.Error(t, err)
+}
+
+// callChain calls handler through interceptors in order
+func callChain(interceptors []grpc.UnaryServerInterceptor, method string, handler grpc.UnaryHandler) (any, error) {
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next, interceptor := handler, interceptors[i]
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler(context.Background(), nil)
+}
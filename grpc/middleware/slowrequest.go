@@ -0,0 +1,302 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/observability/correlate"
+	"github.com/pure-golang/adapters/storage"
+)
+
+// Параметры захвата профиля по умолчанию
+const (
+	defaultSlowRequestProfileDuration = 10 * time.Second
+	defaultSlowRequestProfileInterval = 10 * time.Minute
+	defaultSlowRequestProfilePrefix   = "profiles/slow-requests/"
+)
+
+// SlowRequestOption настраивает SlowRequestDetector
+type SlowRequestOption func(*slowRequestConfig)
+
+// WithMethodLatencyBudget задаёт бюджет задержки метода (полное имя "/pkg.Service/Method")
+// вместо общего; 0 — запросы метода не проверяются
+func WithMethodLatencyBudget(method string, budget time.Duration) SlowRequestOption {
+	return func(c *slowRequestConfig) {
+		c.methodBudgets[method] = budget
+	}
+}
+
+// WithSlowRequestProfiling включает захват CPU профиля, когда запрос превышает бюджет:
+// профиль пишется в storage в bucket с ключом
+// "<prefix><метод>/<время UTC>.pprof" (prefix по умолчанию "profiles/slow-requests/")
+func WithSlowRequestProfiling(store storage.Storage, bucket string) SlowRequestOption {
+	return func(c *slowRequestConfig) {
+		c.store = store
+		c.bucket = bucket
+	}
+}
+
+// WithSlowRequestProfileDuration задаёт длительность захвата CPU профиля (по умолчанию 10s)
+func WithSlowRequestProfileDuration(d time.Duration) SlowRequestOption {
+	return func(c *slowRequestConfig) {
+		c.profileDuration = d
+	}
+}
+
+// WithSlowRequestProfileInterval задаёт минимальный интервал между захватами профиля
+// (по умолчанию 10m): профилирование не должно нагружать сервер, который и так тормозит
+func WithSlowRequestProfileInterval(d time.Duration) SlowRequestOption {
+	return func(c *slowRequestConfig) {
+		c.profileInterval = d
+	}
+}
+
+// WithSlowRequestProfilePrefix задаёт префикс ключей профилей в storage
+func WithSlowRequestProfilePrefix(prefix string) SlowRequestOption {
+	return func(c *slowRequestConfig) {
+		c.profilePrefix = prefix
+	}
+}
+
+// WithSlowRequestMeterProvider задаёт MeterProvider для метрик детектора
+// вместо глобального otel.GetMeterProvider()
+func WithSlowRequestMeterProvider(provider metric.MeterProvider) SlowRequestOption {
+	return func(c *slowRequestConfig) {
+		c.meterProvider = provider
+	}
+}
+
+type slowRequestConfig struct {
+	methodBudgets   map[string]time.Duration
+	store           storage.Storage
+	bucket          string
+	profileDuration time.Duration
+	profileInterval time.Duration
+	profilePrefix   string
+	meterProvider   metric.MeterProvider
+}
+
+// SlowRequestDetector отмечает запросы, превысившие бюджет задержки метода, и по возможности
+// снимает CPU профиль сервера, пока медленный запрос ещё выполняется, — ручное профилирование
+// медленных путей в production не требуется.
+//
+// Запрос, завершившийся дольше бюджета, логируется на уровне Warn ("slow gRPC request")
+// с методом, длительностью, бюджетом и кодом статуса.
+//
+// С WithSlowRequestProfiling при превышении бюджета запускается захват CPU профиля
+// (runtime/pprof) на ProfileDuration; профиль сохраняется в storage с метаданными method и budget.
+// Захваты ограничены: не чаще одного за ProfileInterval и не более одного одновременно.
+// CPU профиль в процессе может быть только один, поэтому захват пропускается, если профиль
+// уже снимается (например, через /debug/pprof/profile).
+//
+// Метрики:
+//   - grpc.server.slow_requests_total — запросы, превысившие бюджет (атрибуты grpc.method, grpc.code)
+//   - grpc.server.slow_request.profiles_total — попытки захвата профиля (атрибут result:
+//     saved, failed, busy)
+type SlowRequestDetector struct {
+	logger  *slog.Logger
+	budget  time.Duration
+	cfg     *slowRequestConfig
+	slow    metric.Int64Counter
+	profile metric.Int64Counter
+
+	mu          sync.Mutex
+	profiling   bool
+	lastProfile time.Time
+}
+
+// NewSlowRequestDetector создаёт детектор с общим бюджетом задержки budget; budget <= 0 — проверяются
+// только методы с WithMethodLatencyBudget.
+// Ошибка создания метрик передаётся в otel.Handle, детектор работает без них.
+func NewSlowRequestDetector(logger *slog.Logger, budget time.Duration, opts ...SlowRequestOption) *SlowRequestDetector {
+	cfg := &slowRequestConfig{
+		methodBudgets:   make(map[string]time.Duration),
+		profileDuration: defaultSlowRequestProfileDuration,
+		profileInterval: defaultSlowRequestProfileInterval,
+		profilePrefix:   defaultSlowRequestProfilePrefix,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	d := &SlowRequestDetector{logger: logger, budget: budget, cfg: cfg}
+	m := meter
+	if cfg.meterProvider != nil {
+		m = cfg.meterProvider.Meter("github.com/pure-golang/adapters/grpc")
+	}
+	if err := d.initMetrics(m); err != nil {
+		otel.Handle(err)
+		d.slow = noop.Int64Counter{}
+		d.profile = noop.Int64Counter{}
+	}
+	return d
+}
+
+// initMetrics создаёт счётчики медленных запросов и захватов профиля
+func (d *SlowRequestDetector) initMetrics(m metric.Meter) error {
+	var err error
+	d.slow, err = m.Int64Counter(
+		"grpc.server.slow_requests_total",
+		metric.WithDescription("Total number of gRPC requests exceeding the method latency budget"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create slow requests counter")
+	}
+	d.profile, err = m.Int64Counter(
+		"grpc.server.slow_request.profiles_total",
+		metric.WithDescription("Total number of CPU profile captures triggered by slow gRPC requests"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create slow request profiles counter")
+	}
+	return nil
+}
+
+// UnaryServerInterceptor возвращает интерцептор, отмечающий медленные унарные запросы
+func (d *SlowRequestDetector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		budget := d.methodBudget(info.FullMethod)
+		if budget <= 0 {
+			return handler(ctx, req)
+		}
+
+		stop := d.watch(info.FullMethod, budget)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		stop()
+		d.observe(ctx, info.FullMethod, budget, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor возвращает интерцептор, отмечающий медленные потоки.
+// Бюджет применяется ко всему времени жизни потока, поэтому долгоживущим потокам
+// задайте WithMethodLatencyBudget(method, 0).
+func (d *SlowRequestDetector) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		budget := d.methodBudget(info.FullMethod)
+		if budget <= 0 {
+			return handler(srv, ss)
+		}
+
+		stop := d.watch(info.FullMethod, budget)
+		start := time.Now()
+		err := handler(srv, ss)
+		stop()
+		d.observe(ss.Context(), info.FullMethod, budget, time.Since(start), err)
+		return err
+	}
+}
+
+// methodBudget возвращает бюджет задержки метода
+func (d *SlowRequestDetector) methodBudget(method string) time.Duration {
+	if budget, ok := d.cfg.methodBudgets[method]; ok {
+		return budget
+	}
+	return d.budget
+}
+
+// watch запускает захват профиля, если запрос не завершится за budget; возвращает функцию отмены
+func (d *SlowRequestDetector) watch(method string, budget time.Duration) func() {
+	if d.cfg.store == nil {
+		return func() {}
+	}
+	timer := time.AfterFunc(budget, func() { d.captureProfile(method, budget) })
+	return func() { timer.Stop() }
+}
+
+// observe записывает метрику и лог запроса, превысившего бюджет
+func (d *SlowRequestDetector) observe(ctx context.Context, method string, budget, duration time.Duration, err error) {
+	if duration <= budget {
+		return
+	}
+
+	code := status.Code(err)
+	d.slow.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("grpc.method", method),
+		attribute.String("grpc.code", code.String()),
+	))
+	logAttrs := []any{
+		slog.String("method", method),
+		slog.Duration("duration", duration),
+		slog.Duration("budget", budget),
+		slog.String("status_code", code.String()),
+	}
+	logAttrs = append(logAttrs, correlate.Args(ctx)...)
+	d.logger.WarnContext(ctx, "slow gRPC request", logAttrs...)
+}
+
+// captureProfile снимает CPU профиль, если не превышен лимит захватов, и сохраняет его в storage
+func (d *SlowRequestDetector) captureProfile(method string, budget time.Duration) {
+	now := time.Now()
+	d.mu.Lock()
+	if d.profiling || (!d.lastProfile.IsZero() && now.Sub(d.lastProfile) < d.cfg.profileInterval) {
+		d.mu.Unlock()
+		return
+	}
+	d.profiling = true
+	d.lastProfile = now
+	d.mu.Unlock()
+
+	go func() {
+		defer func() {
+			d.mu.Lock()
+			d.profiling = false
+			d.mu.Unlock()
+		}()
+
+		ctx := context.Background()
+		var buf bytes.Buffer
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			// профиль уже снимается другим потребителем
+			d.profile.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "busy")))
+			d.logger.Debug("slow request profile skipped", slog.String("method", method), slog.Any("error", err))
+			return
+		}
+		time.Sleep(d.cfg.profileDuration)
+		pprof.StopCPUProfile()
+
+		key := slowRequestProfileKey(d.cfg.profilePrefix, method, now)
+		err := d.cfg.store.Put(ctx, d.cfg.bucket, key, &buf, &storage.PutOptions{
+			ContentType: "application/octet-stream",
+			Metadata: map[string]string{
+				"method": method,
+				"budget": budget.String(),
+			},
+		})
+		if err != nil {
+			d.profile.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "failed")))
+			d.logger.Error("failed to save slow request profile",
+				slog.String("method", method),
+				slog.String("key", key),
+				slog.Any("error", err),
+			)
+			return
+		}
+		d.profile.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "saved")))
+		d.logger.Info("slow request profile saved",
+			slog.String("method", method),
+			slog.String("bucket", d.cfg.bucket),
+			slog.String("key", key),
+		)
+	}()
+}
+
+// slowRequestProfileKey возвращает ключ профиля: "/pkg.Service/Method" превращается в "pkg.Service/Method"
+func slowRequestProfileKey(prefix, method string, at time.Time) string {
+	return fmt.Sprintf("%s%s/%s.pprof", prefix, strings.TrimPrefix(method, "/"), at.UTC().Format("20060102T150405.000Z"))
+}
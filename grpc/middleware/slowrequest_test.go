@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// profileStorage records objects written by Put.
+type profileStorage struct {
+	storage.Storage
+	puts chan profileObject
+}
+
+type profileObject struct {
+	bucket, key string
+	data        []byte
+	opts        *storage.PutOptions
+}
+
+func (s *profileStorage) Put(_ context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.puts <- profileObject{bucket: bucket, key: key, data: data, opts: opts}
+	return nil
+}
+
+// sleepingHandler returns a unary handler that takes d and returns err.
+func sleepingHandler(d time.Duration, err error) grpc.UnaryHandler {
+	return func(context.Context, any) (any, error) {
+		time.Sleep(d)
+		return "ok", err
+	}
+}
+
+// TestSlowRequestDetector tests that requests over the method budget are logged and counted.
+func TestSlowRequestDetector(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	reader := sdkmetric.NewManualReader()
+	detector := NewSlowRequestDetector(slog.New(slog.NewTextHandler(&buf, nil)), 20*time.Millisecond,
+		WithMethodLatencyBudget("/svc/Report", time.Second),
+		WithMethodLatencyBudget("/svc/Watch", 0),
+		WithSlowRequestMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	interceptor := detector.UnaryServerInterceptor()
+	call := func(method string, handler grpc.UnaryHandler) {
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	call("/svc/Get", sleepingHandler(0, nil))
+	call("/svc/Get", sleepingHandler(40*time.Millisecond, status.Error(codes.Unavailable, "db down")))
+	call("/svc/Report", sleepingHandler(40*time.Millisecond, nil))
+	call("/svc/Watch", sleepingHandler(40*time.Millisecond, nil))
+
+	out := buf.String()
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("slow gRPC request")), out)
+	assert.Contains(t, out, "method=/svc/Get")
+	assert.Contains(t, out, "budget=20ms")
+	assert.Contains(t, out, "status_code=Unavailable")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	var slow metricdata.Sum[int64]
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == "grpc.server.slow_requests_total" {
+			slow = m.Data.(metricdata.Sum[int64])
+		}
+	}
+	require.Len(t, slow.DataPoints, 1)
+	assert.EqualValues(t, 1, slow.DataPoints[0].Value)
+	method, _ := slow.DataPoints[0].Attributes.Value("grpc.method")
+	assert.Equal(t, "/svc/Get", method.AsString())
+}
+
+// TestSlowRequestDetector_Profile tests that a slow request triggers one rate-limited CPU profile capture.
+func TestSlowRequestDetector_Profile(t *testing.T) {
+	t.Parallel()
+	store := &profileStorage{puts: make(chan profileObject, 2)}
+	detector := NewSlowRequestDetector(slog.New(slog.DiscardHandler), 10*time.Millisecond,
+		WithSlowRequestProfiling(store, "diagnostics"),
+		WithSlowRequestProfileDuration(50*time.Millisecond),
+	)
+	interceptor := detector.UnaryServerInterceptor()
+
+	for range 2 {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Svc/Slow"},
+			sleepingHandler(30*time.Millisecond, nil))
+		require.NoError(t, err)
+	}
+	// fast requests never start a capture
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Svc/Fast"}, sleepingHandler(0, nil))
+	require.NoError(t, err)
+
+	select {
+	case obj := <-store.puts:
+		assert.Equal(t, "diagnostics", obj.bucket)
+		assert.Regexp(t, `^profiles/slow-requests/pkg\.Svc/Slow/\d{8}T\d{6}\.\d{3}Z\.pprof$`, obj.key)
+		assert.NotEmpty(t, obj.data)
+		assert.Equal(t, "/pkg.Svc/Slow", obj.opts.Metadata["method"])
+		assert.Equal(t, "10ms", obj.opts.Metadata["budget"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("profile was not saved")
+	}
+
+	// the second slow request falls within the profile interval
+	select {
+	case obj := <-store.puts:
+		t.Fatalf("unexpected second profile %s", obj.key)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// TenantAttribute — имя атрибута метрик и span'ов и поля лога с арендатором
+const TenantAttribute = "tenant"
+
+// OtherTenantLabel — значение арендатора, не вошедшего в LimitTenants
+const OtherTenantLabel = "other"
+
+// TenantExtractor возвращает арендатора запроса по контексту и входящим метаданным.
+// Пустая строка — арендатор не определён, атрибут не добавляется.
+//
+// Значение становится атрибутом метрик, поэтому множество значений должно быть ограничено:
+// для произвольных идентификаторов используйте LimitTenants.
+type TenantExtractor func(ctx context.Context, md metadata.MD) string
+
+// MetadataTenantExtractor возвращает арендатора из заголовка метаданных key (например, "x-tenant-id")
+func MetadataTenantExtractor(key string) TenantExtractor {
+	return func(_ context.Context, md metadata.MD) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+}
+
+// BaggageTenantExtractor возвращает арендатора из элемента baggage BaggageTenantID.
+// Baggage извлекается интерцептором трассировки, поэтому он должен стоять раньше в цепочке (см. BuildChain).
+func BaggageTenantExtractor() TenantExtractor {
+	return func(ctx context.Context, _ metadata.MD) string {
+		return BaggageValue(ctx, BaggageTenantID)
+	}
+}
+
+// LimitTenants оставляет перечисленных арендаторов под собственным именем,
+// а остальных непустых заменяет на OtherTenantLabel
+func LimitTenants(extractor TenantExtractor, tenants ...string) TenantExtractor {
+	allowed := make(map[string]struct{}, len(tenants))
+	for _, t := range tenants {
+		allowed[t] = struct{}{}
+	}
+	return func(ctx context.Context, md metadata.MD) string {
+		tenant := extractor(ctx, md)
+		if tenant == "" {
+			return ""
+		}
+		if _, ok := allowed[tenant]; ok {
+			return tenant
+		}
+		return OtherTenantLabel
+	}
+}
+
+// tenantKey — ключ контекста с арендатором
+type tenantKey struct{}
+
+// TenantFromContext возвращает арендатора, определённого TenantUnaryInterceptor или TenantStreamInterceptor
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// TenantUnaryInterceptor определяет арендатора запроса и сохраняет его в контексте.
+// Арендатор добавляется в атрибуты текущего span'а, поля логгера контекста (logger.FromContext),
+// а также в метрики и записи интерцепторов метрик, recovery и логирования, стоящих после него.
+func TenantUnaryInterceptor(extractor TenantExtractor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withTenant(ctx, extractor), req)
+	}
+}
+
+// TenantStreamInterceptor определяет арендатора потока и сохраняет его в контексте потока
+func TenantStreamInterceptor(extractor TenantExtractor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withTenant(ss.Context(), extractor)
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withTenant сохраняет арендатора в контексте, span'е и логгере контекста
+func withTenant(ctx context.Context, extractor TenantExtractor) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.New(nil)
+	}
+	tenant := extractor(ctx, md)
+	if tenant == "" {
+		return ctx
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(TenantAttribute, tenant))
+	ctx = logger.NewContext(ctx, logger.FromContext(ctx).With(slog.String(TenantAttribute, tenant)))
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// tenantLogFields возвращает поле лога с арендатором, если он определён
+func tenantLogFields(ctx context.Context) []any {
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		return []any{slog.String(TenantAttribute, tenant)}
+	}
+	return nil
+}
+
+// appendTenantAttribute добавляет атрибут арендатора к атрибутам метрик, если он определён
+func appendTenantAttribute(ctx context.Context, attrs []attribute.KeyValue) []attribute.KeyValue {
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		return append(attrs, attribute.String(TenantAttribute, tenant))
+	}
+	return attrs
+}
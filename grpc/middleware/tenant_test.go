@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// TestTenantExtractors tests metadata and baggage extractors and LimitTenants.
+func TestTenantExtractors(t *testing.T) {
+	t.Parallel()
+	md := metadata.Pairs("x-tenant-id", "acme")
+
+	assert.Equal(t, "acme", MetadataTenantExtractor("x-tenant-id")(context.Background(), md))
+	assert.Empty(t, MetadataTenantExtractor("x-other")(context.Background(), md))
+
+	ctx, err := ContextWithBaggage(context.Background(), BaggageTenantID, "globex")
+	require.NoError(t, err)
+	assert.Equal(t, "globex", BaggageTenantExtractor()(ctx, nil))
+
+	limited := LimitTenants(MetadataTenantExtractor("x-tenant-id"), "acme")
+	assert.Equal(t, "acme", limited(context.Background(), md))
+	assert.Equal(t, OtherTenantLabel, limited(context.Background(), metadata.Pairs("x-tenant-id", "initech")))
+	assert.Empty(t, limited(context.Background(), metadata.MD{}))
+}
+
+// TestTenantUnaryInterceptor tests that the tenant reaches the context and the context logger.
+func TestTenantUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant-id", "acme"))
+	interceptor := TenantUnaryInterceptor(MetadataTenantExtractor("x-tenant-id"))
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, func(ctx context.Context, _ any) (any, error) {
+		assert.Equal(t, "acme", TenantFromContext(ctx))
+		logger.FromContext(ctx).InfoContext(ctx, "handled")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "tenant=acme")
+
+	// without a tenant the context is passed unchanged
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		assert.Empty(t, TenantFromContext(ctx))
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+// TestTenantStreamInterceptor tests that the tenant reaches the stream context.
+func TestTenantStreamInterceptor(t *testing.T) {
+	t.Parallel()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "acme"))
+	interceptor := TenantStreamInterceptor(MetadataTenantExtractor("x-tenant-id"))
+
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(_ any, ss grpc.ServerStream) error {
+			assert.Equal(t, "acme", TenantFromContext(ss.Context()))
+			return nil
+		})
+	require.NoError(t, err)
+}
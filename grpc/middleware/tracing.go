@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
@@ -42,8 +43,10 @@ func (s metadataSupplier) Keys() []string {
 	return keys
 }
 
-// TracingUnaryInterceptor создает интерцептор для трассировки унарных RPC
-func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+// TracingUnaryInterceptor создает интерцептор для трассировки унарных RPC.
+// Baggage из метаданных сохраняется в контексте (см. BaggageValue, WithBaggageSpanAttributes).
+func TracingUnaryInterceptor(opts ...TracingOption) grpc.UnaryServerInterceptor {
+	cfg := newTracingConfig(opts)
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		// Извлекаем метаданные
 		md, ok := metadata.FromIncomingContext(ctx)
@@ -56,7 +59,7 @@ func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
 
 		// Создаем контекст с пропагацией трассировки
 		var span trace.Span
-		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataSupplier{metadata: &md})
+		ctx = extractPropagation(ctx, md)
 
 		// Начинаем новый спан
 		ctx, span = tracer.Start(
@@ -70,6 +73,7 @@ func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
 			),
 		)
 		defer span.End()
+		ctx = cfg.enrich(ctx, span)
 
 		startTime := time.Now()
 
@@ -86,18 +90,7 @@ func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
 		span.SetAttributes(attribute.Int64("request.duration_ms", duration.Milliseconds()))
 
 		// Обработка ошибок
-		if err != nil {
-			s, _ := status.FromError(err)
-			span.SetStatus(codes.Error, s.Message())
-			span.SetAttributes(
-				attribute.String("rpc.status_code", s.Code().String()),
-				attribute.String("error.message", err.Error()),
-			)
-			span.RecordError(err)
-		} else {
-			span.SetStatus(codes.Ok, "")
-			span.SetAttributes(attribute.String("rpc.status_code", "OK"))
-		}
+		setSpanStatus(ctx, span, err)
 
 		// Записываем ответ как событие
 		span.AddEvent("response_sent", trace.WithAttributes(
@@ -108,8 +101,10 @@ func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
-// TracingStreamInterceptor создает интерцептор для трассировки потоковых RPC
-func TracingStreamInterceptor() grpc.StreamServerInterceptor {
+// TracingStreamInterceptor создает интерцептор для трассировки потоковых RPC.
+// Baggage из метаданных сохраняется в контексте потока.
+func TracingStreamInterceptor(opts ...TracingOption) grpc.StreamServerInterceptor {
+	cfg := newTracingConfig(opts)
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		// Извлекаем метаданные
 		ctx := ss.Context()
@@ -123,7 +118,7 @@ func TracingStreamInterceptor() grpc.StreamServerInterceptor {
 
 		// Создаем контекст с пропагацией трассировки
 		var span trace.Span
-		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataSupplier{metadata: &md})
+		ctx = extractPropagation(ctx, md)
 
 		// Начинаем новый спан
 		streamType := "server_streaming"
@@ -146,6 +141,7 @@ func TracingStreamInterceptor() grpc.StreamServerInterceptor {
 			),
 		)
 		defer span.End()
+		ctx = cfg.enrich(ctx, span)
 
 		startTime := time.Now()
 
@@ -166,18 +162,7 @@ func TracingStreamInterceptor() grpc.StreamServerInterceptor {
 		span.SetAttributes(attribute.Int64("stream.duration_ms", duration.Milliseconds()))
 
 		// Обработка ошибок
-		if err != nil {
-			s, _ := status.FromError(err)
-			span.SetStatus(codes.Error, s.Message())
-			span.SetAttributes(
-				attribute.String("rpc.status_code", s.Code().String()),
-				attribute.String("error.message", err.Error()),
-			)
-			span.RecordError(err)
-		} else {
-			span.SetStatus(codes.Ok, "")
-			span.SetAttributes(attribute.String("rpc.status_code", "OK"))
-		}
+		setSpanStatus(ctx, span, err)
 
 		// Записываем завершение потока
 		span.AddEvent("stream_ended")
@@ -186,6 +171,30 @@ func TracingStreamInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
+// setSpanStatus записывает в span код статуса и ошибку запроса.
+// Span отменённого клиентом запроса не помечается ошибкой и получает атрибут CancelledSpanAttribute.
+func setSpanStatus(ctx context.Context, span trace.Span, err error) {
+	switch {
+	case IsClientCancellation(ctx, err):
+		span.SetAttributes(
+			attribute.Bool(CancelledSpanAttribute, true),
+			attribute.String("rpc.status_code", grpccodes.Canceled.String()),
+			attribute.String("error.message", err.Error()),
+		)
+	case err != nil:
+		s, _ := status.FromError(err)
+		span.SetStatus(codes.Error, s.Message())
+		span.SetAttributes(
+			attribute.String("rpc.status_code", s.Code().String()),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+	default:
+		span.SetStatus(codes.Ok, "")
+		span.SetAttributes(attribute.String("rpc.status_code", "OK"))
+	}
+}
+
 // wrappedServerStream оборачивает grpc.ServerStream для использования контекста с трассировкой
 type wrappedServerStream struct {
 	grpc.ServerStream
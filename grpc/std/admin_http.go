@@ -0,0 +1,209 @@
+package std
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	channelzgrpc "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	channelzservice "google.golang.org/grpc/channelz/service"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// adminReadHeaderTimeout защищает admin HTTP сервер от Slowloris атак
+const adminReadHeaderTimeout = 10 * time.Second
+
+// channelzRegistrar перехватывает реализацию channelz сервиса, чтобы вызывать её из HTTP обработчиков
+type channelzRegistrar struct {
+	server channelzgrpc.ChannelzServer
+}
+
+func (r *channelzRegistrar) RegisterService(_ *grpc.ServiceDesc, impl any) {
+	r.server, _ = impl.(channelzgrpc.ChannelzServer)
+}
+
+// newAdminMux создаёт обработчики admin HTTP сервера:
+//   - /debug/pprof/ — профилирование net/http/pprof
+//   - /debug/grpc/channelz/channels, /debug/grpc/channelz/servers — состояние gRPC (channelz)
+//   - /debug/runtime — метрики runtime/metrics
+//   - /debug/buildinfo — информация о сборке
+//   - /debug/grpc/descriptors, /debug/grpc/openapi.json — схемы сервисов, если schema не nil
+func newAdminMux(schema *schemaExport) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	registrar := &channelzRegistrar{}
+	channelzservice.RegisterChannelzServiceToServer(registrar)
+	cz := registrar.server
+
+	mux.HandleFunc("GET /debug/grpc/channelz/channels", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := cz.GetTopChannels(r.Context(), &channelzgrpc.GetTopChannelsRequest{})
+		writeProto(w, resp, err)
+	})
+	mux.HandleFunc("GET /debug/grpc/channelz/servers", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := cz.GetServers(r.Context(), &channelzgrpc.GetServersRequest{})
+		writeProto(w, resp, err)
+	})
+	mux.HandleFunc("GET /debug/runtime", handleRuntimeMetrics)
+	mux.HandleFunc("GET /debug/buildinfo", handleBuildInfo)
+	if schema != nil {
+		schema.register(mux)
+	}
+
+	return mux
+}
+
+// handleRuntimeMetrics отдаёт текущие значения runtime/metrics;
+// для гистограмм отдаётся только количество наблюдений
+func handleRuntimeMetrics(w http.ResponseWriter, _ *http.Request) {
+	descs := metrics.All()
+	samples := make([]metrics.Sample, len(descs))
+	for i, desc := range descs {
+		samples[i].Name = desc.Name
+	}
+	metrics.Read(samples)
+
+	result := make(map[string]any, len(samples))
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			result[sample.Name] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			result[sample.Name] = sample.Value.Float64()
+		case metrics.KindFloat64Histogram:
+			var count uint64
+			for _, c := range sample.Value.Float64Histogram().Counts {
+				count += c
+			}
+			result[sample.Name] = map[string]uint64{"count": count}
+		case metrics.KindBad:
+			// метрика не поддерживается текущей версией runtime
+		}
+	}
+
+	writeJSON(w, result)
+}
+
+// buildInfo — ответ /debug/buildinfo
+type buildInfo struct {
+	GoVersion string            `json:"go_version"`
+	Path      string            `json:"path"`
+	Version   string            `json:"version"`
+	Settings  map[string]string `json:"settings"`
+	Deps      []string          `json:"deps"`
+}
+
+func handleBuildInfo(w http.ResponseWriter, _ *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		http.Error(w, "build info is not available", http.StatusNotFound)
+		return
+	}
+
+	result := buildInfo{
+		GoVersion: info.GoVersion,
+		Path:      info.Main.Path,
+		Version:   info.Main.Version,
+		Settings:  make(map[string]string, len(info.Settings)),
+		Deps:      make([]string, 0, len(info.Deps)),
+	}
+	for _, setting := range info.Settings {
+		result.Settings[setting.Key] = setting.Value
+	}
+	for _, dep := range info.Deps {
+		result.Deps = append(result.Deps, dep.Path+"@"+dep.Version)
+	}
+
+	writeJSON(w, result)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeProto(w http.ResponseWriter, msg proto.Message, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// startAdminHTTP запускает admin HTTP сервер на Config.AdminPort
+func (s *Server) startAdminHTTP() error {
+	lis, err := inheritedListener(fdNameAdminHTTP)
+	if err != nil {
+		return err
+	}
+	if lis == nil {
+		lis, err = listen(NetworkTCP, fmt.Sprintf("%s:%d", s.config.Host, s.config.AdminPort))
+		if err != nil {
+			return errors.Wrap(err, "failed to start admin http server")
+		}
+	}
+
+	srv := &http.Server{
+		Handler:           newAdminMux(newSchemaExport(s.server, s.config)),
+		ReadHeaderTimeout: adminReadHeaderTimeout,
+	}
+
+	s.listenerMu.Lock()
+	s.adminHTTPListener = lis
+	s.adminHTTPServer = srv
+	s.listenerMu.Unlock()
+
+	s.logger.Info("admin http server starting", "addr", lis.Addr().String())
+
+	go func() {
+		err := srv.Serve(lis)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.With("error", err).Error("admin http server crashed")
+		}
+	}()
+
+	return nil
+}
+
+// closeAdminHTTP останавливает admin HTTP сервер, если он запущен
+func (s *Server) closeAdminHTTP(ctx context.Context) error {
+	s.listenerMu.RLock()
+	srv := s.adminHTTPServer
+	s.listenerMu.RUnlock()
+
+	if srv == nil {
+		return nil
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return errors.Wrap(err, "failed to shutdown admin http server")
+	}
+	return nil
+}
+
+// GetAdminHTTPListener возвращает listener admin HTTP сервера или nil, если он не запущен
+func (s *Server) GetAdminHTTPListener() net.Listener {
+	s.listenerMu.RLock()
+	defer s.listenerMu.RUnlock()
+	return s.adminHTTPListener
+}
@@ -0,0 +1,93 @@
+package std
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func serveAdmin(t *testing.T, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	newAdminMux(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestAdminMux_Pprof(t *testing.T) {
+	t.Parallel()
+
+	rec := serveAdmin(t, "/debug/pprof/")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine")
+}
+
+func TestAdminMux_RuntimeMetrics(t *testing.T) {
+	t.Parallel()
+
+	rec := serveAdmin(t, "/debug/runtime")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Contains(t, result, "/sched/goroutines:goroutines")
+	assert.Contains(t, result, "/gc/pauses:seconds")
+}
+
+func TestAdminMux_BuildInfo(t *testing.T) {
+	t.Parallel()
+
+	rec := serveAdmin(t, "/debug/buildinfo")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result buildInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.NotEmpty(t, result.GoVersion)
+}
+
+func TestAdminMux_Channelz(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"/debug/grpc/channelz/channels", "/debug/grpc/channelz/servers"} {
+		rec := serveAdmin(t, path)
+
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), path)
+	}
+}
+
+func TestServer_Start_AdminPortInUse(t *testing.T) {
+	t.Parallel()
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	c := Config{
+		Network:    NetworkUnix,
+		SocketPath: filepath.Join(t.TempDir(), "main.sock"),
+		Host:       "127.0.0.1",
+		AdminPort:  busy.Addr().(*net.TCPAddr).Port,
+	}
+	s := New(c, func(srv *grpc.Server) {})
+
+	err = s.Start()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start admin http server")
+	assert.NoFileExists(t, c.SocketPath, "main listener must be closed")
+}
+
+func TestServer_CloseAdminHTTP_NotStarted(t *testing.T) {
+	t.Parallel()
+	s := New(Config{Port: 9134}, func(srv *grpc.Server) {})
+
+	assert.Nil(t, s.GetAdminHTTPListener())
+	assert.NoError(t, s.closeAdminHTTP(t.Context()))
+}
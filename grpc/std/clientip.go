@@ -0,0 +1,18 @@
+package std
+
+import (
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// trustedProxiesExtractor возвращает извлечение IP клиента за доверенными прокси по конфигурации
+// или nil, если прокси не заданы; некорректный список отклоняет Validate
+func trustedProxiesExtractor(c Config) middleware.PeerIPExtractor {
+	if len(c.TrustedProxies) == 0 {
+		return nil
+	}
+	trusted, err := middleware.ParseTrustedProxies(c.TrustedProxies)
+	if err != nil {
+		return nil
+	}
+	return middleware.ForwardedPeerIPExtractor(trusted...)
+}
@@ -0,0 +1,49 @@
+package std
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// TestServer_TrustedProxies tests that the client IP from x-forwarded-for reaches handlers.
+func TestServer_TrustedProxies(t *testing.T) {
+	t.Parallel()
+	clientIPs := make(chan netip.Addr, 1)
+	lis := bufconn.Listen(1 << 20)
+	s := NewWithListener(lis, Config{TrustedProxies: []string{"10.0.0.0/8"}}, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	}, WithUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		addr, _ := middleware.ClientIPFromContext(ctx)
+		clientIPs <- addr
+		return handler(ctx, req)
+	}))
+	go func() { _ = s.Start() }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), middleware.ForwardedForHeader, "198.51.100.1, 10.0.0.2")
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("198.51.100.1"), <-clientIPs)
+}
@@ -0,0 +1,40 @@
+package std
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// compressionOptions возвращает интерцепторы и опции сервера для настроек сжатия из конфигурации.
+// Проверка сжатия выполняется после логирования, чтобы отклонённые запросы попадали в логи и метрики.
+func compressionOptions(c Config, logger *slog.Logger) ([]middleware.ChainInterceptor, []grpc.ServerOption) {
+	var interceptors []middleware.ChainInterceptor
+	var serverOpts []grpc.ServerOption
+
+	if c.Compression != "" {
+		if encoding.GetCompressor(c.Compression) == nil {
+			logger.Error("unknown gRPC compressor, response compression disabled", "compression", c.Compression)
+		} else {
+			interceptors = append(interceptors, middleware.ChainInterceptor{
+				Position: middleware.PositionFirst,
+				Unary:    middleware.SendCompressorUnaryInterceptor(c.Compression),
+				Stream:   middleware.SendCompressorStreamInterceptor(c.Compression),
+			})
+		}
+	}
+
+	if c.CompressionThreshold > 0 {
+		serverOpts = append(serverOpts, grpc.StatsHandler(middleware.CompressionStatsHandler()))
+		interceptors = append(interceptors, middleware.ChainInterceptor{
+			Position: middleware.PositionAfterLogging,
+			Unary:    middleware.RequireCompressionUnaryInterceptor(c.CompressionThreshold),
+			Stream:   middleware.RequireCompressionStreamInterceptor(c.CompressionThreshold),
+		})
+	}
+
+	return interceptors, serverOpts
+}
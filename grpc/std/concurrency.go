@@ -0,0 +1,22 @@
+package std
+
+import (
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// concurrencyLimiter возвращает ограничитель одновременных запросов по конфигурации
+// или nil, если лимиты не заданы
+func concurrencyLimiter(c Config) *middleware.ConcurrencyLimiter {
+	if c.MaxConcurrentRequests <= 0 && len(c.MethodConcurrencyLimits) == 0 {
+		return nil
+	}
+
+	opts := []middleware.ConcurrencyOption{
+		middleware.WithConcurrencyQueueTimeout(c.ConcurrencyQueueTimeout),
+		middleware.WithConcurrencyRetryDelay(c.ConcurrencyRetryDelay),
+	}
+	for method, limit := range c.MethodConcurrencyLimits {
+		opts = append(opts, middleware.WithMethodConcurrencyLimit(method, limit))
+	}
+	return middleware.NewConcurrencyLimiter(c.MaxConcurrentRequests, opts...)
+}
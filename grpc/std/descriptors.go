@@ -0,0 +1,145 @@
+package std
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// schemaExport отдаёт схемы зарегистрированных сервисов через admin HTTP сервер
+type schemaExport struct {
+	services    reflection.ServiceInfoProvider
+	resolver    protodesc.Resolver
+	allowed     serviceAllowlist // nil — все сервисы
+	descriptors bool             // /debug/grpc/descriptors
+	openAPI     bool             // /debug/grpc/openapi.json
+}
+
+// newSchemaExport возвращает экспорт схем по конфигурации или nil, если он выключен
+func newSchemaExport(services reflection.ServiceInfoProvider, c Config) *schemaExport {
+	if !c.DescriptorExport && !c.OpenAPIExport {
+		return nil
+	}
+	export := &schemaExport{
+		services:    services,
+		resolver:    protoregistry.GlobalFiles,
+		descriptors: c.DescriptorExport,
+		openAPI:     c.OpenAPIExport,
+	}
+	if len(c.ReflectionServices) > 0 {
+		export.allowed = newServiceAllowlist(c.ReflectionServices)
+	}
+	return export
+}
+
+// register добавляет обработчики экспорта в mux
+func (e *schemaExport) register(mux *http.ServeMux) {
+	if e.descriptors {
+		mux.HandleFunc("GET /debug/grpc/descriptors", e.handleDescriptors)
+	}
+	if e.openAPI {
+		mux.HandleFunc("GET /debug/grpc/openapi.json", e.handleOpenAPI)
+	}
+}
+
+// handleDescriptors отдаёт FileDescriptorSet сервисов в бинарном виде или, с ?format=json, в protojson
+func (e *schemaExport) handleDescriptors(w http.ResponseWriter, r *http.Request) {
+	files, err := e.files()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range files {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		writeProto(w, set, nil)
+		return
+	}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Disposition", `attachment; filename="descriptors.binpb"`)
+	_, _ = w.Write(data)
+}
+
+// handleOpenAPI отдаёт OpenAPI 3 спецификацию методов с аннотациями google.api.http
+func (e *schemaExport) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	services, err := e.serviceDescriptors()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newOpenAPIBuilder().build(services))
+}
+
+// serviceDescriptors возвращает дескрипторы зарегистрированных сервисов в порядке имён.
+// Сервисы reflection и не разрешённые ReflectionServices пропускаются.
+func (e *schemaExport) serviceDescriptors() ([]protoreflect.ServiceDescriptor, error) {
+	info := e.services.GetServiceInfo()
+	names := make([]string, 0, len(info))
+	for name := range info {
+		if name == reflectionServiceV1 || name == reflectionServiceV1Alpha {
+			continue
+		}
+		if e.allowed != nil && !e.allowed.has(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	services := make([]protoreflect.ServiceDescriptor, 0, len(names))
+	for _, name := range names {
+		d, err := e.resolver.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find descriptor of %s", name)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, errors.Errorf("%s is not a service", name)
+		}
+		services = append(services, sd)
+	}
+	return services, nil
+}
+
+// files возвращает файлы сервисов со всеми зависимостями; зависимости идут раньше
+// зависящих от них файлов, как требует FileDescriptorSet
+func (e *schemaExport) files() ([]protoreflect.FileDescriptor, error) {
+	services, err := e.serviceDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []protoreflect.FileDescriptor
+	seen := make(map[string]bool)
+	var visit func(fd protoreflect.FileDescriptor)
+	visit = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := range imports.Len() {
+			visit(imports.Get(i).FileDescriptor)
+		}
+		files = append(files, fd)
+	}
+	for _, sd := range services {
+		visit(sd.ParentFile())
+	}
+	return files, nil
+}
@@ -0,0 +1,268 @@
+package std
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeServiceInfo returns a fixed set of registered services.
+type fakeServiceInfo []string
+
+func (f fakeServiceInfo) GetServiceInfo() map[string]grpc.ServiceInfo {
+	info := make(map[string]grpc.ServiceInfo, len(f))
+	for _, name := range f {
+		info[name] = grpc.ServiceInfo{}
+	}
+	return info
+}
+
+// libraryFiles builds a registry with test.library.Library annotated with google.api.http.
+func libraryFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   kind.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	repeated := func(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return f
+	}
+	method := func(name, input string, rule *annotations.HttpRule) *descriptorpb.MethodDescriptorProto {
+		opts := &descriptorpb.MethodOptions{}
+		proto.SetExtension(opts, annotations.E_Http, rule)
+		return &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".test.library." + input),
+			OutputType: proto.String(".test.library.Book"),
+			Options:    opts,
+		}
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/library.proto"),
+		Package:    proto.String("test.library"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Book"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("page_count", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+					repeated(field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")),
+					field("state", 4, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.library.Book.State"),
+					field("create_time", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+					repeated(field("labels", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.library.Book.LabelsEntry")),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+				EnumType: []*descriptorpb.EnumDescriptorProto{{
+					Name: proto.String("State"),
+					Value: []*descriptorpb.EnumValueDescriptorProto{
+						{Name: proto.String("STATE_UNSPECIFIED"), Number: proto.Int32(0)},
+						{Name: proto.String("PUBLISHED"), Number: proto.Int32(1)},
+					},
+				}},
+			},
+			{
+				Name: proto.String("GetBookRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("read_mask", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{
+				Name: proto.String("CreateBookRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("parent", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("book", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.library.Book"),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Library"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("GetBook", "GetBookRequest", &annotations.HttpRule{
+					Pattern: &annotations.HttpRule_Get{Get: "/v1/{name=books/*}"},
+				}),
+				method("CreateBook", "CreateBookRequest", &annotations.HttpRule{
+					Pattern: &annotations.HttpRule_Post{Post: "/v1/{parent=shelves/*}/books"},
+					Body:    "book",
+					AdditionalBindings: []*annotations.HttpRule{{
+						Pattern: &annotations.HttpRule_Put{Put: "/v1/books"},
+						Body:    "*",
+					}},
+				}),
+				method("Ping", "GetBookRequest", nil),
+			},
+		}},
+	}
+
+	files := &protoregistry.Files{}
+	require.NoError(t, files.RegisterFile(timestamppb.File_google_protobuf_timestamp_proto))
+	fd, err := protodesc.NewFile(file, files)
+	require.NoError(t, err)
+	require.NoError(t, files.RegisterFile(fd))
+	return files
+}
+
+func serveSchema(t *testing.T, e *schemaExport, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	newAdminMux(e).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+// TestNewSchemaExport tests that the export is created only when enabled.
+func TestNewSchemaExport(t *testing.T) {
+	t.Parallel()
+	services := fakeServiceInfo{"test.library.Library"}
+
+	assert.Nil(t, newSchemaExport(services, Config{}))
+
+	e := newSchemaExport(services, Config{DescriptorExport: true, ReflectionServices: []string{"test.library.Library"}})
+	require.NotNil(t, e)
+	assert.True(t, e.descriptors)
+	assert.False(t, e.openAPI)
+	assert.True(t, e.allowed.has("test.library.Library"))
+
+	rec := serveSchema(t, e, "/debug/grpc/openapi.json")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestSchemaExport_Descriptors tests the descriptor set with dependencies first and skipped services.
+func TestSchemaExport_Descriptors(t *testing.T) {
+	t.Parallel()
+	e := &schemaExport{
+		services:    fakeServiceInfo{"test.library.Library", reflectionServiceV1, "test.hidden.Hidden"},
+		resolver:    libraryFiles(t),
+		allowed:     newServiceAllowlist([]string{"test.library.Library"}),
+		descriptors: true,
+	}
+
+	rec := serveSchema(t, e, "/debug/grpc/descriptors")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/x-protobuf", rec.Header().Get("Content-Type"))
+	set := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), set))
+	var names []string
+	for _, file := range set.GetFile() {
+		names = append(names, file.GetName())
+	}
+	assert.Equal(t, []string{"google/protobuf/timestamp.proto", "test/library.proto"}, names)
+
+	rec = serveSchema(t, e, "/debug/grpc/descriptors?format=json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	fromJSON := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protojson.Unmarshal(rec.Body.Bytes(), fromJSON))
+	assert.True(t, proto.Equal(set, fromJSON))
+}
+
+// TestSchemaExport_UnknownService tests the error for a service missing from the resolver.
+func TestSchemaExport_UnknownService(t *testing.T) {
+	t.Parallel()
+	e := &schemaExport{
+		services:    fakeServiceInfo{"test.missing.Missing"},
+		resolver:    libraryFiles(t),
+		descriptors: true,
+	}
+
+	rec := serveSchema(t, e, "/debug/grpc/descriptors")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "failed to find descriptor of test.missing.Missing")
+}
+
+// TestSchemaExport_OpenAPI tests paths, parameters and schemas generated from google.api.http.
+func TestSchemaExport_OpenAPI(t *testing.T) {
+	t.Parallel()
+	e := &schemaExport{
+		services: fakeServiceInfo{"test.library.Library"},
+		resolver: libraryFiles(t),
+		openAPI:  true,
+	}
+
+	rec := serveSchema(t, e, "/debug/grpc/openapi.json")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, openAPIVersion, spec["openapi"])
+
+	paths := spec["paths"].(map[string]any)
+	assert.Len(t, paths, 3)
+
+	get := paths["/v1/{name}"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "Library_GetBook", get["operationId"])
+	assert.Equal(t, []any{
+		map[string]any{"name": "name", "in": "path", "required": true, "schema": map[string]any{"type": "string"}},
+		map[string]any{"name": "readMask", "in": "query", "schema": map[string]any{"type": "string"}},
+	}, get["parameters"])
+	assert.Nil(t, get["requestBody"])
+
+	post := paths["/v1/{parent}/books"].(map[string]any)["post"].(map[string]any)
+	body := post["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/test.library.Book"}, body["schema"])
+
+	put := paths["/v1/books"].(map[string]any)["put"].(map[string]any)
+	body = put["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/test.library.CreateBookRequest"}, body["schema"])
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	book := schemas["test.library.Book"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"name":       map[string]any{"type": "string"},
+		"pageCount":  map[string]any{"type": "string", "format": "int64"},
+		"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		"state":      map[string]any{"type": "string", "enum": []any{"STATE_UNSPECIFIED", "PUBLISHED"}},
+		"createTime": map[string]any{"type": "string", "format": "date-time"},
+		"labels":     map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+	}, book)
+	assert.NotContains(t, schemas, "test.library.Book.LabelsEntry")
+}
+
+// TestOpenAPIPath tests conversion of google.api.http path templates.
+func TestOpenAPIPath(t *testing.T) {
+	t.Parallel()
+
+	for template, want := range map[string]struct {
+		path   string
+		params []string
+	}{
+		"/v1/books":                            {"/v1/books", nil},
+		"/v1/{name=shelves/*/books/*}":         {"/v1/{name}", []string{"name"}},
+		"/v1/{shelf}/books/{book.id}:publish":  {"/v1/{shelf}/books/{book.id}:publish", []string{"shelf", "book.id"}},
+		"/v1/{parent=projects/*}/items/{item}": {"/v1/{parent}/items/{item}", []string{"parent", "item"}},
+	} {
+		path, params := openAPIPath(template)
+		assert.Equal(t, want.path, path, template)
+		assert.Equal(t, want.params, params, template)
+	}
+}
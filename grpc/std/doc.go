@@ -3,8 +3,12 @@
 // Поддерживает:
 //   - автоматическое подключение мониторинга (tracing, metrics, logging)
 //   - TLS шифрование
+//   - listener'ы tcp и unix, дополнительный admin listener (например, unix сокет для sidecar)
+//   - admin HTTP сервер с pprof, channelz, runtime метриками и информацией о сборке
 //   - gracefull shutdown
-//   - gRPC reflection
+//   - перезапуск без потери соединений: Handoff и наследование listener'ов (LISTEN_FDS)
+//   - gRPC reflection с ограничением по сервисам, окружениям и токену
+//   - gRPC-Web для браузерных клиентов без отдельного Envoy, с настройкой CORS
 //
 // Использование:
 //
@@ -23,17 +27,104 @@
 //	server.Run()
 //	defer server.Close()
 //
+//	// Перезапуск по SIGHUP после обновления бинарника
+//	sighup := make(chan os.Signal, 1)
+//	signal.Notify(sighup, syscall.SIGHUP)
+//	<-sighup
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := server.Handoff(ctx); err != nil {
+//	    log.Error("handoff failed, still serving", "error", err)
+//	}
+//
 // Конфигурация через переменные окружения:
 //
+//	GRPC_NETWORK           — протокол основного listener'а: tcp или unix (default: tcp)
 //	GRPC_HOST              — хост сервера (default: "")
-//	GRPC_PORT              — порт сервера (required)
+//	GRPC_PORT              — порт сервера (required для tcp)
+//	GRPC_SOCKET_PATH       — путь к unix сокету (required для unix)
+//	GRPC_ADMIN_NETWORK     — протокол дополнительного listener'а: tcp или unix (default: unix)
+//	GRPC_ADMIN_ADDRESS     — адрес дополнительного listener'а; пусто — не создаётся
+//	GRPC_ADMIN_PORT        — порт admin HTTP сервера (pprof, channelz и др.); 0 — не запускается
 //	GRPC_TLS_CERT_PATH     — путь к TLS сертификату
 //	GRPC_TLS_KEY_PATH      — путь к TLS ключу
+//	GRPC_TLS_CLIENT_CA_PATH — PEM файл с CA клиентов: включает mTLS, клиенты без подписанного сертификата отклоняются
+//	GRPC_PEER_INFO         — сохранять адрес клиента, ALPN и сертификат клиента в PeerInfo контекста (default: false)
+//	GRPC_TRUSTED_PROXIES   — CIDR или адреса прокси через запятую, от которых принимаются x-forwarded-for и x-real-ip
 //	GRPC_ENABLE_REFLECTION — включить reflection API (default: true)
+//	GRPC_REFLECTION_SERVICES — сервисы через запятую, доступные через reflection; пусто — все
+//	GRPC_REFLECTION_TOKEN  — токен, требуемый в метаданных "authorization: Bearer <token>" для reflection
+//	APP_ENV                — имя окружения; если задано, reflection включается только в GRPC_REFLECTION_ENVIRONMENTS
+//	GRPC_REFLECTION_ENVIRONMENTS — окружения, где разрешён reflection (default: local,dev,development,test)
+//	GRPC_DESCRIPTOR_EXPORT — отдавать FileDescriptorSet на /debug/grpc/descriptors admin HTTP сервера (default: false)
+//	GRPC_OPENAPI_EXPORT    — отдавать OpenAPI по google.api.http на /debug/grpc/openapi.json (default: false)
+//	GRPC_COMPRESSION       — предпочтительный компрессор ответов: gzip или zstd; пусто — как у запроса
+//	GRPC_COMPRESSION_THRESHOLD — несжатые сообщения больше N байт отклоняются с RESOURCE_EXHAUSTED; 0 — без ограничения
+//	GRPC_MAX_CONCURRENT_REQUESTS — максимум одновременных запросов, сверх — RESOURCE_EXHAUSTED; 0 — без лимита
+//	GRPC_METHOD_CONCURRENCY_LIMITS — лимиты методов: "/pkg.Service/Method:10,/pkg.Service/Other:5"
+//	GRPC_CONCURRENCY_QUEUE_TIMEOUT — ожидание свободного места при насыщении; 0 — отказ сразу
+//	GRPC_CONCURRENCY_RETRY_DELAY — задержка повтора в RetryInfo и grpc-retry-pushback-ms отказа (default: 1s); 0 — без подсказки
+//	GRPC_DEADLINE_MARGIN — запас на ответ: дедлайн обработчика сокращается на это время; 0 — отключено
+//	GRPC_DEFAULT_DEADLINE — дедлайн запросов без дедлайна (вместе с GRPC_DEADLINE_MARGIN)
+//	GRPC_MAX_RECV_MSG_SIZE — максимальный размер запроса в байтах, сверх — INVALID_ARGUMENT с деталями; 0 — 4 МБ gRPC
+//	GRPC_MAX_SEND_MSG_SIZE — максимальный размер ответа в байтах, сверх — RESOURCE_EXHAUSTED; 0 — без ограничения
+//	GRPC_ENABLE_WEB        — запустить сервер gRPC-Web на GRPC_WEB_PORT (default: false)
+//	GRPC_WEB_PORT          — порт сервера gRPC-Web (required при GRPC_ENABLE_WEB)
+//	GRPC_WEB_ALLOWED_ORIGINS — origins через запятую, которым разрешены запросы gRPC-Web; "*" — любые (default: *)
+//	GRPC_WEB_ALLOWED_HEADERS — дополнительные заголовки запроса (метаданные) для CORS; "*" — любые запрошенные
+//	GRPC_WEB_EXPOSED_HEADERS — заголовки ответа (метаданные), доступные браузерному клиенту
+//	GRPC_WEB_ALLOW_CREDENTIALS — разрешить запросы с cookie (default: false)
+//	GRPC_WEB_CORS_MAX_AGE  — время кэширования preflight в браузере (default: 10m)
+//	GRPC_SKIP_METHODS      — glob-шаблоны методов без трассировки, метрик и логов: "/grpc.health.v1.Health/*"
+//
+// Проверка конфигурации:
+//
+// Config реализует env.Validatable: env.InitConfig проверяет сети, диапазоны портов, синтаксис хоста,
+// наличие TLS сертификата и ключа и их соответствие, компрессор и лимиты — и возвращает ошибки всех
+// полей разом (env.ValidationError). New выполняет ту же проверку; так как New не возвращает ошибку,
+// некорректная конфигурация логируется, а Start и Serve возвращают её, не открывая listener'ы:
+//
+//	invalid gRPC server config: GRPC_PORT: port 70000 out of range [1, 65535]; GRPC_TLS_KEY_PATH: certificate and key do not match or are malformed: ...
 //
 // Особенности:
-//   - По умолчанию включает tracing, metrics и logging через SetupMonitoring
+//   - По умолчанию включает tracing, metrics и logging через SetupMonitoring, а также
+//     метрики и логи соединений (middleware.ConnectionStatsHandler, MonitoringOptions.ConnStatsOptions)
 //   - Graceful shutdown с таймаутом 15 секунд
 //   - Поддержка кастомных интерцепторов через WithUnaryInterceptor
-//   - Потокобезопасное управление listener'ом
+//   - Встраивание интерцепторов в именованные позиции цепочки через WithChainInterceptor,
+//     например дедупликация повторов по idempotency-key (middleware.IdempotencyUnaryInterceptor)
+//   - Потокобезопасное управление listener'ами: GetListener, GetAdminListener
+//   - Обслуживание готового listener'а вместо Host:Port: NewWithListener или Serve(lis)
+//     (bufconn в тестах, systemd socket activation)
+//   - Дополнительный listener обслуживает тот же набор сервисов и закрывается
+//     независимо через CloseAdmin; Close закрывает оба
+//   - Admin HTTP сервер (Host:AdminPort) запускается в Start и останавливается в Close:
+//     /debug/pprof/, /debug/grpc/channelz/channels, /debug/grpc/channelz/servers,
+//     /debug/runtime (runtime/metrics), /debug/buildinfo
+//   - Экспорт схем для сервисов без reflection (DescriptorExport, OpenAPIExport) на admin HTTP сервере:
+//     /debug/grpc/descriptors — FileDescriptorSet сервисов с зависимостями (бинарный, ?format=json — protojson),
+//     /debug/grpc/openapi.json — OpenAPI 3 по аннотациям google.api.http; сервисы ограничиваются ReflectionServices
+//   - Файл unix сокета, оставшийся от предыдущего запуска, удаляется перед listen
+//   - Перезапуск бинарника на VM без потери соединений: Handoff запускает новый процесс
+//     (по умолчанию тот же исполняемый файл с теми же аргументами, см. WithHandoffCommand)
+//     с унаследованными listener'ами, ждёт, пока он начнёт их обслуживать, и завершает текущий
+//     сервер как Close — активные запросы и стримы дорабатывают. Start использует унаследованные
+//     дескрипторы (LISTEN_FDS, LISTEN_FDNAMES: grpc, grpc-admin, grpc-admin-http, grpc-web) вместо
+//     адресов из конфигурации; так же подхватывается systemd socket activation
+//   - gRPC-Web (EnableGRPCWeb): HTTP сервер на Host:GRPCWebPort принимает запросы
+//     application/grpc-web и application/grpc-web-text по HTTP/1.1 (и HTTP/2 с TLS), передаёт их
+//     тому же gRPC серверу через ServeHTTP — работают все интерцепторы — и возвращает статус
+//     кадром трейлеров в теле ответа. Поддерживаются unary и server streaming вызовы (client
+//     streaming gRPC-Web не поддерживает). Отвечает на CORS preflight; запросы с Origin не из
+//     GRPCWebAllowedOrigins отклоняются с 403. Использует TLS сертификат и CA клиентов основного сервера,
+//     запускается в Start и останавливается в Close первым, дожидаясь текущих запросов
+//   - mTLS (TLSClientCAPath): клиент обязан предъявить сертификат, подписанный одним из CA файла.
+//     С PeerInfo (GRPC_PEER_INFO) адрес клиента, согласованный ALPN, Subject и SAN сертификата доступны
+//     обработчикам и слоям авторизации через middleware.PeerInfoFromContext, добавляются в span
+//     и поля лога запроса (peer_address, peer_subject)
+//   - За L7 балансировщиком (TrustedProxies) IP клиента из x-forwarded-for/x-real-ip доступен через
+//     middleware.ClientIPFromContext, записывается в атрибут span'а client.address и поле лога client_ip
+//   - Reflection в production: при APP_ENV вне GRPC_REFLECTION_ENVIRONMENTS reflection не регистрируется.
+//     GRPC_REFLECTION_SERVICES скрывает остальные сервисы из списка и их дескрипторы; файл с разрешённым
+//     сервисом отдаётся целиком, включая объявленные в нём запрещённые сервисы
 package std
@@ -0,0 +1,330 @@
+package std
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Типы содержимого запросов gRPC-Web: двоичный и base64 (для клиентов без поддержки двоичных тел)
+const (
+	grpcContentType        = "application/grpc"
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+)
+
+// grpcWebTrailerFlag — флаг кадра тела ответа, содержащего трейлеры: браузеры не дают
+// доступа к HTTP трейлерам, поэтому gRPC-Web передаёт их последним кадром тела
+const grpcWebTrailerFlag = 0x80
+
+// Заголовки CORS, нужные клиентам gRPC-Web (grpc-web, Connect) всегда
+var (
+	grpcWebAllowedHeaders = []string{"Content-Type", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", "Authorization"}
+	grpcWebExposedHeaders = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+)
+
+// grpcWebHandler принимает запросы gRPC-Web по HTTP/1.1 или HTTP/2, преобразует их в gRPC
+// и передаёт grpc.Server.ServeHTTP: запросы проходят те же интерцепторы, что и обычные.
+// Отвечает на CORS preflight и отклоняет запросы с Origin не из списка разрешённых.
+type grpcWebHandler struct {
+	grpc             http.Handler
+	allowedOrigins   []string
+	allowAnyOrigin   bool
+	allowedHeaders   []string
+	allowAnyHeader   bool
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+func newGRPCWebHandler(grpcHandler http.Handler, c Config) *grpcWebHandler {
+	h := &grpcWebHandler{
+		grpc:             grpcHandler,
+		allowedOrigins:   c.GRPCWebAllowedOrigins,
+		allowAnyOrigin:   slices.Contains(c.GRPCWebAllowedOrigins, "*"),
+		allowedHeaders:   append(slices.Clone(grpcWebAllowedHeaders), c.GRPCWebAllowedHeaders...),
+		allowAnyHeader:   slices.Contains(c.GRPCWebAllowedHeaders, "*"),
+		exposedHeaders:   strings.Join(append(slices.Clone(grpcWebExposedHeaders), c.GRPCWebExposedHeaders...), ", "),
+		allowCredentials: c.GRPCWebAllowCredentials,
+	}
+	if c.GRPCWebCORSMaxAge > 0 {
+		h.maxAge = strconv.Itoa(int(c.GRPCWebCORSMaxAge.Seconds()))
+	}
+	return h
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		if !h.originAllowed(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		h.setAllowOrigin(w.Header(), origin)
+	}
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		h.preflight(w, r)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if r.Method != http.MethodPost || !strings.HasPrefix(contentType, grpcWebContentType) {
+		http.Error(w, "not a gRPC-Web request", http.StatusUnsupportedMediaType)
+		return
+	}
+	if origin != "" {
+		w.Header().Set("Access-Control-Expose-Headers", h.exposedHeaders)
+	}
+
+	text := strings.HasPrefix(contentType, grpcWebTextContentType)
+	subtype := strings.TrimPrefix(strings.TrimPrefix(contentType, grpcWebTextContentType), grpcWebContentType)
+
+	// grpc.Server.ServeHTTP принимает только HTTP/2 с application/grpc: тело и заголовки
+	// gRPC-Web совпадают с gRPC, кроме типа содержимого и трейлеров
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2"
+	req.Header.Set("Content-Type", grpcContentType+subtype)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	rw := &grpcWebResponseWriter{
+		w:           w,
+		header:      make(http.Header),
+		contentType: contentType,
+		text:        text,
+		sent:        make(map[string]bool),
+	}
+	h.grpc.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+// originAllowed сообщает, что origin входит в GRPCWebAllowedOrigins
+func (h *grpcWebHandler) originAllowed(origin string) bool {
+	return h.allowAnyOrigin || slices.Contains(h.allowedOrigins, origin)
+}
+
+// setAllowOrigin разрешает ответ для origin; с учётными данными "*" не допускается, поэтому origin возвращается как есть
+func (h *grpcWebHandler) setAllowOrigin(header http.Header, origin string) {
+	if h.allowAnyOrigin && !h.allowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+	}
+	if h.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// preflight отвечает на CORS preflight запрос
+func (h *grpcWebHandler) preflight(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Access-Control-Request-Method") != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusForbidden)
+		return
+	}
+
+	allowed := h.allowedHeaders
+	if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		for name := range strings.SplitSeq(requested, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if h.allowAnyHeader {
+				allowed = append(slices.Clip(allowed), name)
+			} else if !slices.ContainsFunc(h.allowedHeaders, func(header string) bool { return strings.EqualFold(header, name) }) {
+				http.Error(w, fmt.Sprintf("header %q not allowed", name), http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	header := w.Header()
+	header.Set("Access-Control-Allow-Methods", http.MethodPost)
+	header.Set("Access-Control-Allow-Headers", strings.Join(allowed, ", "))
+	if h.maxAge != "" {
+		header.Set("Access-Control-Max-Age", h.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// grpcWebResponseWriter преобразует ответ grpc.Server.ServeHTTP в gRPC-Web: заменяет тип содержимого,
+// кодирует тело в base64 для grpc-web-text и передаёт трейлеры последним кадром тела (finish)
+type grpcWebResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	text        bool
+	wroteHeader bool
+	// sent — заголовки, отправленные до тела; заданные позже становятся трейлерами
+	sent map[string]bool
+}
+
+func (rw *grpcWebResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *grpcWebResponseWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+
+	dst := rw.w.Header()
+	for k, vv := range rw.header {
+		if k == "Trailer" || strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+		dst[k] = slices.Clone(vv)
+		rw.sent[k] = true
+	}
+	if strings.HasPrefix(dst.Get("Content-Type"), grpcContentType) {
+		dst.Set("Content-Type", rw.contentType)
+	}
+	rw.w.WriteHeader(code)
+}
+
+func (rw *grpcWebResponseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if !rw.text {
+		return rw.w.Write(b)
+	}
+	// Каждая запись кодируется отдельно с выравниванием: клиенты декодируют тело по группам из 4 символов
+	if _, err := io.WriteString(rw.w, base64.StdEncoding.EncodeToString(b)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (rw *grpcWebResponseWriter) Flush() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish записывает кадр трейлеров: заголовки с префиксом http.TrailerPrefix
+// и заданные после отправки заголовков (grpc-status, grpc-message)
+func (rw *grpcWebResponseWriter) finish() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	var trailer bytes.Buffer
+	for k, vv := range rw.header {
+		name, isTrailer := strings.CutPrefix(k, http.TrailerPrefix)
+		if !isTrailer && (k == "Trailer" || rw.sent[k]) {
+			continue
+		}
+		for _, v := range vv {
+			trailer.WriteString(strings.ToLower(name) + ": " + v + "\r\n")
+		}
+	}
+	if trailer.Len() == 0 {
+		return
+	}
+
+	frame := make([]byte, 5, 5+trailer.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailer.Len()))
+	frame = append(frame, trailer.Bytes()...)
+	if _, err := rw.Write(frame); err != nil {
+		return
+	}
+	rw.Flush()
+}
+
+// startGRPCWeb запускает HTTP сервер gRPC-Web на Config.GRPCWebPort
+func (s *Server) startGRPCWeb() error {
+	lis, err := inheritedListener(fdNameGRPCWeb)
+	if err != nil {
+		return err
+	}
+	if lis == nil {
+		lis, err = listen(NetworkTCP, fmt.Sprintf("%s:%d", s.config.Host, s.config.GRPCWebPort))
+		if err != nil {
+			return errors.Wrap(err, "failed to start grpc-web server")
+		}
+	}
+
+	srv := &http.Server{
+		Handler:           newGRPCWebHandler(s.server, s.config),
+		ReadHeaderTimeout: adminReadHeaderTimeout,
+	}
+	if s.config.TLSClientCAPath != "" {
+		// Клиенты gRPC-Web проходят ту же проверку сертификата, что и клиенты основного сервера
+		clientCAs, err := loadCertPool(s.config.TLSClientCAPath)
+		if err != nil {
+			_ = lis.Close()
+			return errors.Wrap(err, "failed to start grpc-web server")
+		}
+		srv.TLSConfig = &tls.Config{
+			ClientCAs:  clientCAs,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	s.listenerMu.Lock()
+	s.grpcWebListener = lis
+	s.grpcWebServer = srv
+	s.listenerMu.Unlock()
+
+	s.logger.Info("grpc-web server starting", "addr", lis.Addr().String())
+
+	go func() {
+		var err error
+		if s.config.TLSCertPath != "" && s.config.TLSKeyPath != "" {
+			err = srv.ServeTLS(lis, s.config.TLSCertPath, s.config.TLSKeyPath)
+		} else {
+			err = srv.Serve(lis)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.With("error", err).Error("grpc-web server crashed")
+		}
+	}()
+
+	return nil
+}
+
+// closeGRPCWeb останавливает HTTP сервер gRPC-Web, дожидаясь завершения запросов
+func (s *Server) closeGRPCWeb(ctx context.Context) error {
+	s.listenerMu.RLock()
+	srv := s.grpcWebServer
+	s.listenerMu.RUnlock()
+
+	if srv == nil {
+		return nil
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return errors.Wrap(err, "failed to shutdown grpc-web server")
+	}
+	return nil
+}
+
+// GetGRPCWebListener возвращает listener сервера gRPC-Web или nil, если он не запущен
+func (s *Server) GetGRPCWebListener() net.Listener {
+	s.listenerMu.RLock()
+	defer s.listenerMu.RUnlock()
+	return s.grpcWebListener
+}
@@ -0,0 +1,214 @@
+package std
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const testOrigin = "https://app.example.com"
+
+// newGRPCWebTestServer serves a gRPC server with the health service through the gRPC-Web handler.
+func newGRPCWebTestServer(t *testing.T, c Config) *httptest.Server {
+	t.Helper()
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	t.Cleanup(srv.Stop)
+
+	web := httptest.NewServer(newGRPCWebHandler(srv, c))
+	t.Cleanup(web.Close)
+	return web
+}
+
+// grpcWebRequest frames msg as a gRPC message and posts it to method.
+func grpcWebRequest(t *testing.T, url, method, contentType string, msg proto.Message) *http.Response {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	body := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(data)))
+	body = append(body, data...)
+	if strings.HasPrefix(contentType, grpcWebTextContentType) {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url+method, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Grpc-Web", "1")
+	req.Header.Set("Origin", testOrigin)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+// readGRPCWebFrames splits a gRPC-Web response body into message payloads and trailers.
+func readGRPCWebFrames(t *testing.T, body []byte, text bool) ([][]byte, string) {
+	t.Helper()
+	if text {
+		// every write is padded separately, so the body is decoded in groups of 4 characters
+		var decoded []byte
+		for i := 0; i+4 <= len(body); i += 4 {
+			group, err := base64.StdEncoding.DecodeString(string(body[i : i+4]))
+			require.NoError(t, err)
+			decoded = append(decoded, group...)
+		}
+		body = decoded
+	}
+
+	var messages [][]byte
+	var trailers string
+	for len(body) > 0 {
+		require.GreaterOrEqual(t, len(body), 5)
+		n := binary.BigEndian.Uint32(body[1:5])
+		payload := body[5 : 5+n]
+		if body[0]&grpcWebTrailerFlag != 0 {
+			trailers = string(payload)
+		} else {
+			messages = append(messages, payload)
+		}
+		body = body[5+n:]
+	}
+	return messages, trailers
+}
+
+// TestGRPCWebHandler_Unary tests binary and text gRPC-Web calls with trailers in the body.
+func TestGRPCWebHandler_Unary(t *testing.T) {
+	t.Parallel()
+	web := newGRPCWebTestServer(t, Config{GRPCWebAllowedOrigins: []string{testOrigin}})
+
+	for _, contentType := range []string{"application/grpc-web+proto", "application/grpc-web-text+proto"} {
+		t.Run(contentType, func(t *testing.T) {
+			t.Parallel()
+			resp := grpcWebRequest(t, web.URL, "/grpc.health.v1.Health/Check", contentType, &healthpb.HealthCheckRequest{})
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, contentType, resp.Header.Get("Content-Type"))
+			assert.Equal(t, testOrigin, resp.Header.Get("Access-Control-Allow-Origin"))
+			assert.Contains(t, resp.Header.Get("Access-Control-Expose-Headers"), "Grpc-Status")
+			assert.Empty(t, resp.Header.Get("Grpc-Status"), "status must be sent in the body")
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			messages, trailers := readGRPCWebFrames(t, body, strings.HasPrefix(contentType, grpcWebTextContentType))
+			require.Len(t, messages, 1)
+			var check healthpb.HealthCheckResponse
+			require.NoError(t, proto.Unmarshal(messages[0], &check))
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check.GetStatus())
+			assert.Contains(t, trailers, "grpc-status: 0\r\n")
+		})
+	}
+}
+
+// TestGRPCWebHandler_Error tests that a trailers-only error response carries the status in the body.
+func TestGRPCWebHandler_Error(t *testing.T) {
+	t.Parallel()
+	web := newGRPCWebTestServer(t, Config{GRPCWebAllowedOrigins: []string{"*"}})
+
+	resp := grpcWebRequest(t, web.URL, "/unknown.Service/Method", "application/grpc-web+proto", &healthpb.HealthCheckRequest{})
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	messages, trailers := readGRPCWebFrames(t, body, false)
+	assert.Empty(t, messages)
+	assert.Contains(t, trailers, "grpc-status: 12\r\n")
+	assert.Contains(t, trailers, "grpc-message: unknown service unknown.Service\r\n")
+}
+
+// TestGRPCWebHandler_CORS tests preflight responses and origin checks.
+func TestGRPCWebHandler_CORS(t *testing.T) {
+	t.Parallel()
+	handler := newGRPCWebHandler(http.NotFoundHandler(), Config{
+		GRPCWebAllowedOrigins: []string{testOrigin},
+		GRPCWebAllowedHeaders: []string{"X-Request-Id"},
+		GRPCWebCORSMaxAge:     10 * time.Minute,
+	})
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/grpc.health.v1.Health/Check", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := preflight(testOrigin, http.MethodPost, "content-type, x-grpc-web, x-request-id")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, testOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+	assert.Equal(t, http.MethodPost, rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "X-Request-Id")
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+
+	assert.Equal(t, http.StatusForbidden, preflight("https://evil.example.com", http.MethodPost, "content-type").Code)
+	assert.Equal(t, http.StatusForbidden, preflight(testOrigin, http.MethodPost, "x-secret").Code)
+	assert.Equal(t, http.StatusForbidden, preflight(testOrigin, http.MethodGet, "").Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+// TestServer_GRPCWeb tests that the gRPC-Web server starts and stops with the gRPC server.
+func TestServer_GRPCWeb(t *testing.T) {
+	t.Parallel()
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	webPort := free.Addr().(*net.TCPAddr).Port
+	require.NoError(t, free.Close())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := NewWithListener(lis, Config{
+		Host:                  "127.0.0.1",
+		EnableGRPCWeb:         true,
+		GRPCWebPort:           webPort,
+		GRPCWebAllowedOrigins: []string{testOrigin},
+	}, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+	go func() { _ = s.Start() }()
+	require.Eventually(t, func() bool { return s.GetGRPCWebListener() != nil }, 5*time.Second, 10*time.Millisecond)
+
+	url := "http://" + s.GetGRPCWebListener().Addr().String()
+	resp := grpcWebRequest(t, url, "/grpc.health.v1.Health/Check", "application/grpc-web+proto", &healthpb.HealthCheckRequest{})
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_, trailers := readGRPCWebFrames(t, body, false)
+	assert.Contains(t, trailers, "grpc-status: 0\r\n")
+
+	require.NoError(t, s.Close())
+	_, err = net.Dial("tcp", s.GetGRPCWebListener().Addr().String())
+	assert.Error(t, err, "grpc-web listener must be closed")
+}
+
+// TestConfig_Validate_GRPCWeb tests gRPC-Web port checks.
+func TestConfig_Validate_GRPCWeb(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, Config{Port: 9000, EnableGRPCWeb: true, GRPCWebPort: 8080}.Validate())
+
+	assert.EqualError(t, Config{Port: 9000, EnableGRPCWeb: true}.Validate(),
+		"GRPC_WEB_PORT: required when GRPC_ENABLE_WEB is set")
+	assert.EqualError(t, Config{Port: 9000, EnableGRPCWeb: true, GRPCWebPort: 9000}.Validate(),
+		"GRPC_WEB_PORT: must differ from GRPC_PORT 9000")
+	assert.EqualError(t, Config{Port: 9000, AdminPort: 9001, EnableGRPCWeb: true, GRPCWebPort: 9001}.Validate(),
+		"GRPC_WEB_PORT: must differ from GRPC_ADMIN_PORT 9001")
+}
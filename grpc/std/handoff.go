@@ -0,0 +1,338 @@
+package std
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Переменные окружения передачи listener'ов (соглашение systemd socket activation)
+const (
+	envListenFDs     = "LISTEN_FDS"
+	envListenPID     = "LISTEN_PID"
+	envListenFDNames = "LISTEN_FDNAMES"
+	// envHandoffReadyFD — дескриптор, в который новый процесс сообщает о готовности (Handoff)
+	envHandoffReadyFD = "GRPC_HANDOFF_READY_FD"
+)
+
+// listenFDsStart — первый унаследованный дескриптор (SD_LISTEN_FDS_START)
+const listenFDsStart = 3
+
+// Имена listener'ов в LISTEN_FDNAMES
+const (
+	fdNameMain      = "grpc"
+	fdNameAdmin     = "grpc-admin"
+	fdNameAdminHTTP = "grpc-admin-http"
+	fdNameGRPCWeb   = "grpc-web"
+)
+
+// WithHandoffCommand задаёт команду нового процесса для Handoff; по умолчанию — текущий
+// исполняемый файл с теми же аргументами. Используется, например, если новая версия
+// установлена по другому пути. Команда вызывается при каждом Handoff; Env, равный nil,
+// означает окружение текущего процесса.
+func WithHandoffCommand(command func() *exec.Cmd) ServerOption {
+	return func(s *Server) {
+		s.handoffCommand = command
+	}
+}
+
+// Handoff передаёт открытые listener'ы новому процессу и плавно останавливает текущий сервер.
+//
+// Новый процесс запускается с унаследованными дескрипторами (LISTEN_FDS, LISTEN_FDNAMES)
+// и обслуживает их в Start вместо открытия адресов из конфигурации. После того как он начал
+// обслуживать основной listener, текущий сервер перестаёт принимать соединения и дожидается
+// завершения активных запросов и стримов, как Close. Соединения, ожидающие accept, остаются
+// в общем сокете и принимаются новым процессом.
+//
+// Если новый процесс завершился или ctx истёк до готовности, процесс останавливается,
+// а текущий сервер продолжает работу и Handoff возвращает ошибку.
+func (s *Server) Handoff(ctx context.Context) error {
+	if runtime.GOOS == "windows" {
+		return errors.New("listener handoff is not supported on windows")
+	}
+
+	listeners, names := s.handoffListeners()
+	if len(listeners) == 0 {
+		return errors.New("failed to hand off listeners: server is not started")
+	}
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for i, lis := range listeners {
+		filer, ok := lis.(interface{ File() (*os.File, error) })
+		if !ok {
+			return errors.Errorf("failed to hand off listener %q: %T does not expose its file", names[i], lis)
+		}
+		f, err := filer.File()
+		if err != nil {
+			return errors.Wrapf(err, "failed to get file of listener %q", names[i])
+		}
+		files = append(files, f)
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return errors.Wrap(err, "failed to create handoff pipe")
+	}
+	defer func() { _ = readyReader.Close() }()
+	files = append(files, readyWriter)
+
+	cmd, err := s.newHandoffCommand()
+	if err != nil {
+		return err
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(withoutHandoffEnv(env),
+		envListenFDs+"="+strconv.Itoa(len(listeners)),
+		envListenFDNames+"="+strings.Join(names, ":"),
+		envHandoffReadyFD+"="+strconv.Itoa(listenFDsStart+len(listeners)),
+	)
+	cmd.ExtraFiles = files
+
+	startErr := cmd.Start()
+	// StartProcess переводит переданные дескрипторы в блокирующий режим, а флаг общий
+	// с listener'ами сервера: Accept заблокировал бы поток и не прервался бы при Close
+	for i, f := range files[:len(listeners)] {
+		if err := restoreNonblock(f); err != nil {
+			s.logger.With("error", err).Warn("failed to restore non-blocking mode of listener", "listener", names[i])
+		}
+	}
+	if startErr != nil {
+		return errors.Wrap(startErr, "failed to start new process")
+	}
+	// Копия дескриптора готовности у текущего процесса закрывается, чтобы EOF означал выход нового
+	if err := readyWriter.Close(); err != nil {
+		s.logger.With("error", err).Warn("failed to close handoff pipe")
+	}
+	files = files[:len(files)-1]
+
+	if err := waitReady(ctx, readyReader); err != nil {
+		if killErr := cmd.Process.Kill(); killErr != nil && !errors.Is(killErr, os.ErrProcessDone) {
+			s.logger.With("error", killErr).Warn("failed to kill new process")
+		}
+		waitErr := cmd.Wait()
+		return errors.Wrapf(err, "new process failed to take over listeners (exit: %v)", waitErr)
+	}
+
+	s.logger.Info("gRPC listeners handed off, draining", "pid", cmd.Process.Pid)
+	if err := cmd.Process.Release(); err != nil {
+		s.logger.With("error", err).Warn("failed to release new process")
+	}
+
+	// Файл unix сокета теперь принадлежит новому процессу
+	for _, lis := range listeners {
+		if unixLis, ok := lis.(*net.UnixListener); ok {
+			unixLis.SetUnlinkOnClose(false)
+		}
+	}
+	return s.Close()
+}
+
+// handoffListeners возвращает запущенные listener'ы и их имена для LISTEN_FDNAMES
+func (s *Server) handoffListeners() ([]net.Listener, []string) {
+	s.listenerMu.RLock()
+	defer s.listenerMu.RUnlock()
+
+	if s.listener == nil {
+		return nil, nil
+	}
+	listeners := []net.Listener{s.listener}
+	names := []string{fdNameMain}
+	if s.adminListener != nil {
+		listeners = append(listeners, s.adminListener)
+		names = append(names, fdNameAdmin)
+	}
+	if s.adminHTTPListener != nil {
+		listeners = append(listeners, s.adminHTTPListener)
+		names = append(names, fdNameAdminHTTP)
+	}
+	if s.grpcWebListener != nil {
+		listeners = append(listeners, s.grpcWebListener)
+		names = append(names, fdNameGRPCWeb)
+	}
+	return listeners, names
+}
+
+// newHandoffCommand создаёт команду нового процесса
+func (s *Server) newHandoffCommand() (*exec.Cmd, error) {
+	if s.handoffCommand != nil {
+		return s.handoffCommand(), nil
+	}
+	path, err := os.Executable()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get executable path")
+	}
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
+
+// restoreNonblock возвращает неблокирующий режим сокету f: net.FileListener включает его
+// на своей копии дескриптора, а флаг общий для всех копий
+func restoreNonblock(f *os.File) error {
+	lis, err := net.FileListener(f)
+	if err != nil {
+		return err
+	}
+	return lis.Close()
+}
+
+// waitReady ждёт сообщения о готовности нового процесса
+func waitReady(ctx context.Context, ready *os.File) error {
+	result := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		_, err := ready.Read(buf)
+		if errors.Is(err, io.EOF) {
+			err = errors.New("new process exited before it was ready")
+		}
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "new process was not ready in time")
+	}
+}
+
+// withoutHandoffEnv удаляет переменные передачи listener'ов из окружения
+func withoutHandoffEnv(env []string) []string {
+	result := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		switch name {
+		case envListenFDs, envListenPID, envListenFDNames, envHandoffReadyFD:
+			continue
+		}
+		result = append(result, kv)
+	}
+	return result
+}
+
+// inherited — listener'ы, унаследованные процессом; окружение разбирается один раз
+var inherited struct {
+	once      sync.Once
+	mu        sync.Mutex
+	listeners map[string]net.Listener
+	ready     *os.File
+	err       error
+}
+
+// inheritedListener возвращает унаследованный listener с именем name или nil.
+// Каждый listener выдаётся один раз.
+func inheritedListener(name string) (net.Listener, error) {
+	inherited.once.Do(func() {
+		inherited.listeners, inherited.ready, inherited.err = listenersFromEnv(os.Getenv, os.Getpid(), listenFDsStart)
+		for _, name := range []string{envListenFDs, envListenPID, envListenFDNames, envHandoffReadyFD} {
+			if err := os.Unsetenv(name); err != nil && inherited.err == nil {
+				inherited.err = errors.Wrapf(err, "failed to unset %s", name)
+			}
+		}
+	})
+
+	inherited.mu.Lock()
+	defer inherited.mu.Unlock()
+	if inherited.err != nil {
+		return nil, errors.Wrap(inherited.err, "failed to inherit listeners")
+	}
+	lis := inherited.listeners[name]
+	delete(inherited.listeners, name)
+	return lis, nil
+}
+
+// notifyHandoffReady сообщает процессу, вызвавшему Handoff, что унаследованный listener обслуживается
+func notifyHandoffReady() error {
+	inherited.mu.Lock()
+	ready := inherited.ready
+	inherited.ready = nil
+	inherited.mu.Unlock()
+
+	if ready == nil {
+		return nil
+	}
+	defer func() { _ = ready.Close() }()
+	if _, err := ready.Write([]byte{1}); err != nil {
+		return errors.Wrap(err, "failed to notify handoff readiness")
+	}
+	return nil
+}
+
+// listenersFromEnv создаёт listener'ы из дескрипторов, начиная с startFD, по LISTEN_FDS и LISTEN_FDNAMES.
+// Переменные, предназначенные другому процессу (LISTEN_PID не совпадает с pid), игнорируются.
+// Первый дескриптор без имени (или с именем systemd по умолчанию "unknown") считается основным listener'ом.
+func listenersFromEnv(getenv func(string) string, pid, startFD int) (map[string]net.Listener, *os.File, error) {
+	value := getenv(envListenFDs)
+	if value == "" {
+		return nil, nil, nil
+	}
+	if listenPID := getenv(envListenPID); listenPID != "" && listenPID != strconv.Itoa(pid) {
+		return nil, nil, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return nil, nil, errors.Errorf("invalid %s %q", envListenFDs, value)
+	}
+
+	var names []string
+	if value := getenv(envListenFDNames); value != "" {
+		names = strings.Split(value, ":")
+	}
+
+	listeners := make(map[string]net.Listener, count)
+	for i := range count {
+		name := ""
+		if i < len(names) && names[i] != "unknown" {
+			name = names[i]
+		}
+		if name == "" && i == 0 {
+			// systemd без FileDescriptorName
+			name = fdNameMain
+		}
+
+		f := os.NewFile(uintptr(startFD+i), name)
+		if name == "" || listeners[name] != nil {
+			if err := f.Close(); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to close inherited descriptor %d", startFD+i)
+			}
+			continue
+		}
+		lis, err := net.FileListener(f)
+		closeErr := f.Close()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to create listener %q from descriptor %d", name, startFD+i)
+		}
+		if closeErr != nil {
+			return nil, nil, errors.Wrapf(closeErr, "failed to close inherited descriptor %d", startFD+i)
+		}
+		listeners[name] = lis
+	}
+
+	var ready *os.File
+	if value := getenv(envHandoffReadyFD); value != "" {
+		fd, err := strconv.Atoi(value)
+		if err != nil || fd < 0 {
+			return nil, nil, errors.Errorf("invalid %s %q", envHandoffReadyFD, value)
+		}
+		ready = os.NewFile(uintptr(fd), "handoff-ready")
+	}
+	return listeners, ready, nil
+}
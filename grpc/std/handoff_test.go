@@ -0,0 +1,151 @@
+package std
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// handoffChildEnv switches TestServer_Handoff to the new process role
+const handoffChildEnv = "GRPC_HANDOFF_TEST_CHILD"
+
+// TestServer_Handoff tests that a new process takes over the listener while the old one drains in-flight requests.
+func TestServer_Handoff(t *testing.T) {
+	if os.Getenv(handoffChildEnv) != "" {
+		runHandoffChild()
+		return
+	}
+
+	// the child runs until stdin is closed
+	stdinReader, stdinWriter, err := os.Pipe()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stdinWriter.Close()
+		_ = stdinReader.Close()
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	slow := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if r, ok := req.(*healthpb.HealthCheckRequest); ok && r.GetService() == "slow" {
+			time.Sleep(300 * time.Millisecond)
+		}
+		return handler(ctx, req)
+	}
+	s := NewWithListener(lis, Config{}, func(srv *grpc.Server) {
+		h := health.NewServer()
+		h.SetServingStatus("old", healthpb.HealthCheckResponse_SERVING)
+		h.SetServingStatus("slow", healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(srv, h)
+	}, WithUnaryInterceptor(slow), WithHandoffCommand(func() *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestServer_Handoff$")
+		cmd.Env = append(os.Environ(), handoffChildEnv+"=1")
+		cmd.Stdin = stdinReader
+		return cmd
+	}))
+	go func() { _ = s.Start() }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	client := healthpb.NewHealthClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "old"}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	inFlight := make(chan error, 1)
+	go func() {
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "slow"})
+		inFlight <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, s.Handoff(ctx))
+	assert.NoError(t, <-inFlight)
+
+	// the client reconnects to the same address, now served by the new process
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "new"}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+// runHandoffChild serves the inherited listener until stdin is closed
+func runHandoffChild() {
+	s := New(Config{}, func(srv *grpc.Server) {
+		h := health.NewServer()
+		h.SetServingStatus("new", healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(srv, h)
+	})
+	go func() { _ = s.Start() }()
+	_, _ = io.Copy(io.Discard, os.Stdin)
+	_ = s.Close()
+}
+
+// TestServer_Handoff_NotStarted tests that Handoff requires a running server.
+func TestServer_Handoff_NotStarted(t *testing.T) {
+	t.Parallel()
+	s := New(Config{}, func(*grpc.Server) {})
+	assert.ErrorContains(t, s.Handoff(context.Background()), "server is not started")
+}
+
+// TestServer_Handoff_ChildFails tests that the server keeps serving when the new process exits before it is ready.
+func TestServer_Handoff_ChildFails(t *testing.T) {
+	t.Parallel()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := NewWithListener(lis, Config{}, func(*grpc.Server) {}, WithHandoffCommand(func() *exec.Cmd {
+		return exec.Command("false")
+	}))
+	go func() { _ = s.Start() }()
+	defer func() { _ = s.Close() }()
+	require.Eventually(t, func() bool { return s.GetListener() != nil }, time.Second, 10*time.Millisecond)
+
+	err = s.Handoff(context.Background())
+	assert.ErrorContains(t, err, "exited before it was ready")
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	_ = conn.Close()
+}
+
+// TestListenersFromEnv tests which environments are treated as inherited listeners.
+func TestListenersFromEnv(t *testing.T) {
+	t.Parallel()
+	env := func(values map[string]string) func(string) string {
+		return func(name string) string { return values[name] }
+	}
+
+	listeners, ready, err := listenersFromEnv(env(nil), 100, listenFDsStart)
+	require.NoError(t, err)
+	assert.Nil(t, listeners)
+	assert.Nil(t, ready)
+
+	// variables addressed to another process are ignored
+	listeners, _, err = listenersFromEnv(env(map[string]string{envListenFDs: "1", envListenPID: "42"}), 100, listenFDsStart)
+	require.NoError(t, err)
+	assert.Nil(t, listeners)
+
+	_, _, err = listenersFromEnv(env(map[string]string{envListenFDs: "x"}), 100, listenFDsStart)
+	assert.ErrorContains(t, err, "invalid LISTEN_FDS")
+}
+
+// TestWithoutHandoffEnv tests that handoff variables of the current process are not passed on.
+func TestWithoutHandoffEnv(t *testing.T) {
+	t.Parallel()
+	env := withoutHandoffEnv([]string{"PATH=/bin", "LISTEN_FDS=2", "LISTEN_PID=1", "LISTEN_FDNAMES=grpc", "GRPC_HANDOFF_READY_FD=5", "LISTEN_FDS_X=1"})
+	assert.Equal(t, []string{"PATH=/bin", "LISTEN_FDS_X=1"}, env)
+}
@@ -0,0 +1,31 @@
+package std
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// messageSizeOptions возвращает интерцепторы и опции сервера для лимитов размера сообщений из конфигурации.
+// Проверка выполняется после логирования, чтобы отклонённые запросы попадали в логи и метрики.
+func messageSizeOptions(c Config) ([]middleware.ChainInterceptor, []grpc.ServerOption) {
+	if c.MaxRecvMsgSize <= 0 && c.MaxSendMsgSize <= 0 {
+		return nil, nil
+	}
+
+	limiter := middleware.NewMessageSizeLimiter(c.MaxRecvMsgSize, c.MaxSendMsgSize)
+	serverOpts := []grpc.ServerOption{grpc.StatsHandler(limiter.StatsHandler())}
+	if c.MaxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(limiter.TransportRecvLimit()))
+	}
+	if c.MaxSendMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxSendMsgSize(c.MaxSendMsgSize))
+	}
+
+	interceptors := []middleware.ChainInterceptor{{
+		Position: middleware.PositionAfterLogging,
+		Unary:    limiter.UnaryServerInterceptor(),
+		Stream:   limiter.StreamServerInterceptor(),
+	}}
+	return interceptors, serverOpts
+}
@@ -0,0 +1,288 @@
+package std
+
+import (
+	"runtime/debug"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPIVersion — версия спецификации OpenAPI, которую формирует openAPIBuilder
+const openAPIVersion = "3.0.3"
+
+// wellKnownSchemas — схемы well-known типов в JSON представлении protojson
+var wellKnownSchemas = map[protoreflect.FullName]map[string]any{
+	"google.protobuf.Timestamp":   {"type": "string", "format": "date-time"},
+	"google.protobuf.Duration":    {"type": "string", "example": "1.5s"},
+	"google.protobuf.FieldMask":   {"type": "string"},
+	"google.protobuf.Empty":       {"type": "object"},
+	"google.protobuf.Struct":      {"type": "object"},
+	"google.protobuf.Value":       {},
+	"google.protobuf.ListValue":   {"type": "array", "items": map[string]any{}},
+	"google.protobuf.Any":         {"type": "object", "properties": map[string]any{"@type": map[string]any{"type": "string"}}},
+	"google.protobuf.BoolValue":   {"type": "boolean"},
+	"google.protobuf.StringValue": {"type": "string"},
+	"google.protobuf.BytesValue":  {"type": "string", "format": "byte"},
+	"google.protobuf.Int32Value":  {"type": "integer", "format": "int32"},
+	"google.protobuf.UInt32Value": {"type": "integer", "format": "uint32"},
+	"google.protobuf.Int64Value":  {"type": "string", "format": "int64"},
+	"google.protobuf.UInt64Value": {"type": "string", "format": "uint64"},
+	"google.protobuf.FloatValue":  {"type": "number", "format": "float"},
+	"google.protobuf.DoubleValue": {"type": "number", "format": "double"},
+}
+
+// httpBinding — HTTP привязка метода из аннотации google.api.http
+type httpBinding struct {
+	verb         string
+	path         string
+	body         string
+	responseBody string
+}
+
+// openAPIBuilder формирует OpenAPI спецификацию по аннотациям google.api.http.
+// Сообщения описываются в components.schemas под полными именами, поля — по JSON именам protojson.
+type openAPIBuilder struct {
+	schemas map[string]any
+}
+
+func newOpenAPIBuilder() *openAPIBuilder {
+	return &openAPIBuilder{schemas: make(map[string]any)}
+}
+
+// build возвращает спецификацию методов services; методы без аннотации google.api.http пропускаются
+func (b *openAPIBuilder) build(services []protoreflect.ServiceDescriptor) map[string]any {
+	paths := make(map[string]map[string]any)
+	for _, sd := range services {
+		methods := sd.Methods()
+		for i := range methods.Len() {
+			md := methods.Get(i)
+			for _, binding := range httpBindings(md) {
+				path, params := openAPIPath(binding.path)
+				if paths[path] == nil {
+					paths[path] = make(map[string]any)
+				}
+				paths[path][binding.verb] = b.operation(sd, md, binding, params)
+			}
+		}
+	}
+
+	version := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		version = info.Main.Version
+	}
+	return map[string]any{
+		"openapi":    openAPIVersion,
+		"info":       map[string]any{"title": "gRPC API", "version": version},
+		"paths":      paths,
+		"components": map[string]any{"schemas": b.schemas},
+	}
+}
+
+// operation описывает вызов метода md через binding
+func (b *openAPIBuilder) operation(sd protoreflect.ServiceDescriptor, md protoreflect.MethodDescriptor, binding httpBinding, params []string) map[string]any {
+	input := md.Input()
+	parameters := make([]any, 0, len(params))
+	bound := make(map[string]bool, len(params))
+	for _, param := range params {
+		bound[param] = true
+		schema := map[string]any{"type": "string"}
+		if fd := fieldByPath(input, param); fd != nil {
+			schema = b.fieldSchema(fd)
+		}
+		parameters = append(parameters, map[string]any{"name": param, "in": "path", "required": true, "schema": schema})
+	}
+
+	op := map[string]any{
+		"operationId": string(sd.Name()) + "_" + string(md.Name()),
+		"tags":        []string{string(sd.FullName())},
+	}
+
+	switch binding.body {
+	case "":
+		// Скалярные поля запроса, не связанные с путём, передаются в query string
+		fields := input.Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			if bound[string(fd.Name())] || fd.IsMap() {
+				continue
+			}
+			if msg := fd.Message(); msg != nil && wellKnownSchemas[msg.FullName()]["type"] != "string" {
+				continue
+			}
+			parameters = append(parameters, map[string]any{"name": fd.JSONName(), "in": "query", "schema": b.fieldSchema(fd)})
+		}
+	case "*":
+		op["requestBody"] = jsonContent(b.messageRef(input), true)
+	default:
+		schema := map[string]any{"type": "object"}
+		if fd := input.Fields().ByName(protoreflect.Name(binding.body)); fd != nil {
+			schema = b.fieldSchema(fd)
+		}
+		op["requestBody"] = jsonContent(schema, true)
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+
+	response := b.messageRef(md.Output())
+	if binding.responseBody != "" {
+		if fd := md.Output().Fields().ByName(protoreflect.Name(binding.responseBody)); fd != nil {
+			response = b.fieldSchema(fd)
+		}
+	}
+	ok := jsonContent(response, false)
+	ok["description"] = "OK"
+	op["responses"] = map[string]any{"200": ok}
+	return op
+}
+
+// messageRef возвращает ссылку на схему сообщения, добавляя её в components.schemas
+func (b *openAPIBuilder) messageRef(md protoreflect.MessageDescriptor) map[string]any {
+	if schema, ok := wellKnownSchemas[md.FullName()]; ok {
+		return schema
+	}
+	name := string(md.FullName())
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := b.schemas[name]; ok {
+		return ref
+	}
+
+	properties := make(map[string]any)
+	b.schemas[name] = map[string]any{"type": "object", "properties": properties}
+	fields := md.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		properties[fd.JSONName()] = b.fieldSchema(fd)
+	}
+	return ref
+}
+
+// fieldSchema возвращает схему поля с учётом repeated и map
+func (b *openAPIBuilder) fieldSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{"type": "object", "additionalProperties": b.singularSchema(fd.MapValue())}
+	case fd.IsList():
+		return map[string]any{"type": "array", "items": b.singularSchema(fd)}
+	default:
+		return b.singularSchema(fd)
+	}
+}
+
+// singularSchema возвращает схему одного значения поля; 64-битные целые protojson передаёт строками
+func (b *openAPIBuilder) singularSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := range values.Len() {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.messageRef(fd.Message())
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// httpBindings возвращает привязки метода из аннотации google.api.http, включая additional_bindings
+func httpBindings(md protoreflect.MethodDescriptor) []httpBinding {
+	rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+
+	var bindings []httpBinding
+	var collect func(rule *annotations.HttpRule)
+	collect = func(rule *annotations.HttpRule) {
+		binding := httpBinding{body: rule.GetBody(), responseBody: rule.GetResponseBody()}
+		switch pattern := rule.GetPattern().(type) {
+		case *annotations.HttpRule_Get:
+			binding.verb, binding.path = "get", pattern.Get
+		case *annotations.HttpRule_Put:
+			binding.verb, binding.path = "put", pattern.Put
+		case *annotations.HttpRule_Post:
+			binding.verb, binding.path = "post", pattern.Post
+		case *annotations.HttpRule_Delete:
+			binding.verb, binding.path = "delete", pattern.Delete
+		case *annotations.HttpRule_Patch:
+			binding.verb, binding.path = "patch", pattern.Patch
+		case *annotations.HttpRule_Custom:
+			binding.verb, binding.path = strings.ToLower(pattern.Custom.GetKind()), pattern.Custom.GetPath()
+		}
+		if binding.path != "" {
+			bindings = append(bindings, binding)
+		}
+		for _, additional := range rule.GetAdditionalBindings() {
+			collect(additional)
+		}
+	}
+	collect(rule)
+	return bindings
+}
+
+// openAPIPath преобразует шаблон пути google.api.http в путь OpenAPI и возвращает имена переменных:
+// "/v1/{name=shelves/*/books/*}" → "/v1/{name}", ["name"]
+func openAPIPath(template string) (string, []string) {
+	var path strings.Builder
+	var params []string
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			path.WriteString(template)
+			return path.String(), params
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			path.WriteString(template)
+			return path.String(), params
+		}
+		name, _, _ := strings.Cut(template[start+1:start+end], "=")
+		params = append(params, name)
+		path.WriteString(template[:start] + "{" + name + "}")
+		template = template[start+end+1:]
+	}
+}
+
+// fieldByPath возвращает поле по пути вида "book.name" или nil
+func fieldByPath(md protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+	for name := range strings.SplitSeq(path, ".") {
+		if md == nil {
+			return nil
+		}
+		if fd = md.Fields().ByName(protoreflect.Name(name)); fd == nil {
+			return nil
+		}
+		md = fd.Message()
+	}
+	return fd
+}
+
+// jsonContent описывает тело запроса или ответа в application/json
+func jsonContent(schema map[string]any, required bool) map[string]any {
+	content := map[string]any{"content": map[string]any{"application/json": map[string]any{"schema": schema}}}
+	if required {
+		content["required"] = true
+	}
+	return content
+}
@@ -0,0 +1,199 @@
+package std
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Имена сервисов reflection API; всегда видны в списке сервисов, если reflection включён
+const (
+	reflectionServiceV1      = "grpc.reflection.v1.ServerReflection"
+	reflectionServiceV1Alpha = "grpc.reflection.v1alpha.ServerReflection"
+)
+
+// DefaultReflectionEnvironments — окружения, в которых reflection включается, если ReflectionEnvironments пуст
+var DefaultReflectionEnvironments = []string{"local", "dev", "development", "test"}
+
+// reflectionEnabled сообщает, нужно ли регистрировать reflection API.
+// Если задано Environment, reflection включается только в окружениях из ReflectionEnvironments.
+func reflectionEnabled(c Config) bool {
+	if !c.EnableReflect {
+		return false
+	}
+	if c.Environment == "" {
+		return true
+	}
+	environments := c.ReflectionEnvironments
+	if len(environments) == 0 {
+		environments = DefaultReflectionEnvironments
+	}
+	return slices.ContainsFunc(environments, func(env string) bool {
+		return strings.EqualFold(strings.TrimSpace(env), c.Environment)
+	})
+}
+
+// registerReflection регистрирует reflection API v1 и v1alpha с учётом ReflectionServices и ReflectionToken
+func registerReflection(s *grpc.Server, c Config) {
+	opts := reflection.ServerOptions{Services: s}
+	if len(c.ReflectionServices) > 0 {
+		allowed := newServiceAllowlist(c.ReflectionServices)
+		opts.Services = &filteredServices{base: s, allowed: allowed}
+		opts.DescriptorResolver = &filteredResolver{base: protoregistry.GlobalFiles, allowed: allowed}
+	}
+
+	v1 := reflection.NewServerV1(opts)
+	v1alpha := reflection.NewServer(opts)
+	if c.ReflectionToken != "" {
+		v1 = &tokenReflectionV1{ServerReflectionServer: v1, token: c.ReflectionToken}
+		v1alpha = &tokenReflectionV1Alpha{ServerReflectionServer: v1alpha, token: c.ReflectionToken}
+	}
+	reflectionv1.RegisterServerReflectionServer(s, v1)
+	reflectionv1alpha.RegisterServerReflectionServer(s, v1alpha)
+}
+
+// serviceAllowlist — множество полных имён сервисов, доступных через reflection
+type serviceAllowlist map[string]struct{}
+
+func newServiceAllowlist(services []string) serviceAllowlist {
+	allowed := serviceAllowlist{
+		reflectionServiceV1:      {},
+		reflectionServiceV1Alpha: {},
+	}
+	for _, name := range services {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = struct{}{}
+		}
+	}
+	return allowed
+}
+
+func (a serviceAllowlist) has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// fileVisible сообщает, можно ли отдать файл: в нём нет сервисов или есть хотя бы один разрешённый.
+// Файл отдаётся целиком, поэтому запрещённый сервис, объявленный в одном файле с разрешённым, остаётся виден.
+func (a serviceAllowlist) fileVisible(fd protoreflect.FileDescriptor) bool {
+	services := fd.Services()
+	if services.Len() == 0 {
+		return true
+	}
+	for i := range services.Len() {
+		if a.has(string(services.Get(i).FullName())) {
+			return true
+		}
+	}
+	return false
+}
+
+// filteredServices ограничивает список сервисов reflection разрешёнными
+type filteredServices struct {
+	base    reflection.ServiceInfoProvider
+	allowed serviceAllowlist
+}
+
+// GetServiceInfo реализует reflection.ServiceInfoProvider
+func (f *filteredServices) GetServiceInfo() map[string]grpc.ServiceInfo {
+	all := f.base.GetServiceInfo()
+	result := make(map[string]grpc.ServiceInfo, len(all))
+	for name, info := range all {
+		if f.allowed.has(name) {
+			result[name] = info
+		}
+	}
+	return result
+}
+
+// filteredResolver скрывает дескрипторы запрещённых сервисов и файлов, содержащих только их
+type filteredResolver struct {
+	base    protodesc.Resolver
+	allowed serviceAllowlist
+}
+
+// FindFileByPath реализует protodesc.Resolver
+func (f *filteredResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	fd, err := f.base.FindFileByPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if !f.allowed.fileVisible(fd) {
+		return nil, protoregistry.NotFound
+	}
+	return fd, nil
+}
+
+// FindDescriptorByName реализует protodesc.Resolver
+func (f *filteredResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	d, err := f.base.FindDescriptorByName(name)
+	if err != nil {
+		return nil, err
+	}
+	var service protoreflect.Descriptor
+	switch desc := d.(type) {
+	case protoreflect.ServiceDescriptor:
+		service = desc
+	case protoreflect.MethodDescriptor:
+		service = desc.Parent()
+	}
+	if service != nil && !f.allowed.has(string(service.FullName())) {
+		return nil, protoregistry.NotFound
+	}
+	if !f.allowed.fileVisible(d.ParentFile()) {
+		return nil, protoregistry.NotFound
+	}
+	return d, nil
+}
+
+// checkReflectionToken проверяет метаданные authorization вида "Bearer <token>"
+func checkReflectionToken(ctx context.Context, token string) error {
+	for _, value := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		scheme, raw, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") &&
+			subtle.ConstantTimeCompare([]byte(strings.TrimSpace(raw)), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "reflection token required")
+}
+
+// tokenReflectionV1 требует токен для reflection API v1
+type tokenReflectionV1 struct {
+	reflectionv1.ServerReflectionServer
+	token string
+}
+
+// ServerReflectionInfo реализует reflectionv1.ServerReflectionServer
+func (r *tokenReflectionV1) ServerReflectionInfo(stream grpc.BidiStreamingServer[reflectionv1.ServerReflectionRequest, reflectionv1.ServerReflectionResponse]) error {
+	if err := checkReflectionToken(stream.Context(), r.token); err != nil {
+		return err
+	}
+	return r.ServerReflectionServer.ServerReflectionInfo(stream)
+}
+
+// tokenReflectionV1Alpha требует токен для reflection API v1alpha
+type tokenReflectionV1Alpha struct {
+	reflectionv1alpha.ServerReflectionServer
+	token string
+}
+
+// ServerReflectionInfo реализует reflectionv1alpha.ServerReflectionServer
+func (r *tokenReflectionV1Alpha) ServerReflectionInfo(stream grpc.BidiStreamingServer[reflectionv1alpha.ServerReflectionRequest, reflectionv1alpha.ServerReflectionResponse]) error {
+	if err := checkReflectionToken(stream.Context(), r.token); err != nil {
+		return err
+	}
+	return r.ServerReflectionServer.ServerReflectionInfo(stream)
+}
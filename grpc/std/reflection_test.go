@@ -0,0 +1,164 @@
+package std
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	channelzservice "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startReflectionServer serves health and channelz services over bufconn with the given config.
+func startReflectionServer(t *testing.T, c Config) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := NewWithListener(lis, c, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+		channelzservice.RegisterChannelzServiceToServer(srv)
+	})
+	go func() { _ = s.Start() }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// reflect sends a single reflection request.
+func reflect(ctx context.Context, conn *grpc.ClientConn, req *reflectionv1.ServerReflectionRequest) (*reflectionv1.ServerReflectionResponse, error) {
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	_ = stream.CloseSend()
+	return resp, nil
+}
+
+func listServices(ctx context.Context, conn *grpc.ClientConn) ([]string, error) {
+	resp, err := reflect(ctx, conn, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		names = append(names, service.GetName())
+	}
+	return names, nil
+}
+
+func fileContainingSymbol(ctx context.Context, conn *grpc.ClientConn, symbol string) (*reflectionv1.ServerReflectionResponse, error) {
+	return reflect(ctx, conn, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+}
+
+// TestReflection_AllServices tests that without an allowlist every registered service is exposed.
+func TestReflection_AllServices(t *testing.T) {
+	t.Parallel()
+	conn := startReflectionServer(t, Config{EnableReflect: true})
+
+	services, err := listServices(context.Background(), conn)
+	require.NoError(t, err)
+	assert.Contains(t, services, "grpc.health.v1.Health")
+	assert.Contains(t, services, "grpc.channelz.v1.Channelz")
+}
+
+// TestReflection_Allowlist tests that reflection exposes only allowlisted services.
+func TestReflection_Allowlist(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn := startReflectionServer(t, Config{
+		EnableReflect:      true,
+		ReflectionServices: []string{"grpc.health.v1.Health"},
+	})
+
+	services, err := listServices(ctx, conn)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"grpc.health.v1.Health",
+		reflectionServiceV1,
+		reflectionServiceV1Alpha,
+	}, services)
+
+	resp, err := fileContainingSymbol(ctx, conn, "grpc.health.v1.Health")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetFileDescriptorResponse().GetFileDescriptorProto())
+
+	for _, symbol := range []string{"grpc.channelz.v1.Channelz", "grpc.channelz.v1.Channelz.GetServers", "grpc.channelz.v1.GetServersRequest"} {
+		resp, err = fileContainingSymbol(ctx, conn, symbol)
+		require.NoError(t, err)
+		assert.Equal(t, int32(codes.NotFound), resp.GetErrorResponse().GetErrorCode(), symbol)
+	}
+}
+
+// TestReflection_Token tests that reflection calls require the configured bearer token.
+func TestReflection_Token(t *testing.T) {
+	t.Parallel()
+	conn := startReflectionServer(t, Config{EnableReflect: true, ReflectionToken: "secret"})
+
+	_, err := listServices(context.Background(), conn)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = listServices(ctx, conn)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	services, err := listServices(ctx, conn)
+	require.NoError(t, err)
+	assert.Contains(t, services, "grpc.health.v1.Health")
+}
+
+// TestReflection_Environment tests that reflection is registered only in allowed environments.
+func TestReflection_Environment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config Config
+		want   bool
+	}{
+		{"no environment", Config{EnableReflect: true}, true},
+		{"disabled", Config{EnableReflect: false, Environment: "dev"}, false},
+		{"default dev environment", Config{EnableReflect: true, Environment: "DEV"}, true},
+		{"default production", Config{EnableReflect: true, Environment: "production"}, false},
+		{"custom environments", Config{EnableReflect: true, Environment: "staging", ReflectionEnvironments: []string{"staging"}}, true},
+		{"custom environments exclude dev", Config{EnableReflect: true, Environment: "dev", ReflectionEnvironments: []string{"staging"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, reflectionEnabled(tt.config))
+		})
+	}
+
+	s := New(Config{EnableReflect: true, Environment: "production"}, func(*grpc.Server) {})
+	_, registered := s.server.GetServiceInfo()[reflectionServiceV1]
+	assert.False(t, registered)
+}
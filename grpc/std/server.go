@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/reflection"
 
 	adaptergrpc "github.com/pure-golang/adapters/grpc"
 	"github.com/pure-golang/adapters/grpc/middleware"
@@ -23,12 +25,103 @@ const ShutdownTimeout = 15 * time.Second
 
 var _ adaptergrpc.RunableProvider = (*Server)(nil)
 
+// Сетевые протоколы listener'ов
+const (
+	NetworkTCP  = "tcp"
+	NetworkUnix = "unix"
+)
+
 type Config struct {
-	Host          string `envconfig:"GRPC_HOST"`
-	Port          int    `envconfig:"GRPC_PORT" required:"true"`
-	TLSCertPath   string `envconfig:"GRPC_TLS_CERT_PATH"`
-	TLSKeyPath    string `envconfig:"GRPC_TLS_KEY_PATH"`
-	EnableReflect bool   `envconfig:"GRPC_ENABLE_REFLECTION" default:"true"`
+	// Network — протокол основного listener'а: "tcp" (по умолчанию) или "unix"
+	Network string `envconfig:"GRPC_NETWORK" default:"tcp"`
+	Host    string `envconfig:"GRPC_HOST"`
+	// Port обязателен для сети "tcp"
+	Port int `envconfig:"GRPC_PORT"`
+	// SocketPath — путь к unix сокету, обязателен для сети "unix"
+	SocketPath string `envconfig:"GRPC_SOCKET_PATH"`
+	// AdminNetwork и AdminAddress задают дополнительный listener (например, unix сокет для sidecar).
+	// Дополнительный listener не создаётся, если AdminAddress пуст.
+	AdminNetwork string `envconfig:"GRPC_ADMIN_NETWORK" default:"unix"`
+	AdminAddress string `envconfig:"GRPC_ADMIN_ADDRESS"`
+	// AdminPort — порт admin HTTP сервера с pprof, channelz, runtime метриками и информацией о сборке.
+	// Сервер слушает Host:AdminPort и не запускается, если AdminPort равен 0.
+	AdminPort   int    `envconfig:"GRPC_ADMIN_PORT"`
+	TLSCertPath string `envconfig:"GRPC_TLS_CERT_PATH"`
+	TLSKeyPath  string `envconfig:"GRPC_TLS_KEY_PATH"`
+	// TLSClientCAPath — PEM файл с сертификатами CA клиентов: включает mTLS, клиент без сертификата,
+	// подписанного одним из них, не устанавливает соединение. Используется вместе с TLSCertPath и TLSKeyPath
+	TLSClientCAPath string `envconfig:"GRPC_TLS_CLIENT_CA_PATH"`
+	// TrustedProxies — подсети (CIDR) или адреса L7 балансировщиков и прокси, которым доверяются
+	// x-forwarded-for и x-real-ip: IP клиента за ними доступен через middleware.ClientIPFromContext
+	// и добавляется в span и поля лога (см. middleware.ForwardedPeerIPExtractor); пусто — адрес соединения
+	TrustedProxies []string `envconfig:"GRPC_TRUSTED_PROXIES"`
+	// PeerInfo сохраняет адрес клиента, ALPN и сертификат клиента (mTLS) в контексте запроса
+	// (middleware.PeerInfoFromContext), атрибутах span'а и полях лога (см. middleware.PeerInfoUnaryInterceptor)
+	PeerInfo      bool `envconfig:"GRPC_PEER_INFO" default:"false"`
+	EnableReflect bool `envconfig:"GRPC_ENABLE_REFLECTION" default:"true"`
+	// ReflectionServices ограничивает reflection перечисленными сервисами (полные имена);
+	// пусто — доступны все зарегистрированные сервисы
+	ReflectionServices []string `envconfig:"GRPC_REFLECTION_SERVICES"`
+	// ReflectionToken, если задан, требуется в метаданных "authorization: Bearer <token>" для reflection
+	ReflectionToken string `envconfig:"GRPC_REFLECTION_TOKEN"`
+	// Environment — имя окружения приложения. Если задано, reflection регистрируется
+	// только в окружениях из ReflectionEnvironments.
+	Environment            string   `envconfig:"APP_ENV"`
+	ReflectionEnvironments []string `envconfig:"GRPC_REFLECTION_ENVIRONMENTS" default:"local,dev,development,test"`
+	// DescriptorExport отдаёт FileDescriptorSet сервисов на /debug/grpc/descriptors admin HTTP сервера,
+	// в том числе при выключенном reflection; сервисы ограничиваются ReflectionServices. Требует AdminPort
+	DescriptorExport bool `envconfig:"GRPC_DESCRIPTOR_EXPORT"`
+	// OpenAPIExport отдаёт OpenAPI спецификацию методов с аннотациями google.api.http
+	// на /debug/grpc/openapi.json admin HTTP сервера. Требует AdminPort
+	OpenAPIExport bool `envconfig:"GRPC_OPENAPI_EXPORT"`
+	// Compression — предпочтительный компрессор ответов ("gzip" или "zstd"), если клиент его поддерживает;
+	// пусто — ответы сжимаются так же, как запрос
+	Compression string `envconfig:"GRPC_COMPRESSION"`
+	// CompressionThreshold — несжатые входящие сообщения больше этого размера в байтах
+	// отклоняются с RESOURCE_EXHAUSTED; 0 — без ограничения
+	CompressionThreshold int `envconfig:"GRPC_COMPRESSION_THRESHOLD"`
+	// MaxConcurrentRequests — максимум одновременно обрабатываемых запросов, сверх него
+	// запросы отклоняются с RESOURCE_EXHAUSTED; 0 — без общего лимита
+	MaxConcurrentRequests int `envconfig:"GRPC_MAX_CONCURRENT_REQUESTS"`
+	// MethodConcurrencyLimits — лимиты отдельных методов: "/pkg.Service/Method:10,/pkg.Service/Other:5"
+	MethodConcurrencyLimits map[string]int `envconfig:"GRPC_METHOD_CONCURRENCY_LIMITS"`
+	// ConcurrencyQueueTimeout — ожидание свободного места при насыщении; 0 — отказ сразу
+	ConcurrencyQueueTimeout time.Duration `envconfig:"GRPC_CONCURRENCY_QUEUE_TIMEOUT"`
+	// ConcurrencyRetryDelay — задержка повтора, которую отклонённый запрос получает в google.rpc.RetryInfo
+	// и трейлере grpc-retry-pushback-ms; 0 — без подсказки повтора
+	ConcurrencyRetryDelay time.Duration `envconfig:"GRPC_CONCURRENCY_RETRY_DELAY" default:"1s"`
+	// DeadlineMargin — запас на отправку ответа: дедлайн обработчика и исходящих вызовов
+	// сокращается на это время; 0 — бюджет дедлайна не применяется
+	DeadlineMargin time.Duration `envconfig:"GRPC_DEADLINE_MARGIN"`
+	// DefaultDeadline — дедлайн запросов, пришедших без дедлайна; используется вместе с DeadlineMargin
+	DefaultDeadline time.Duration `envconfig:"GRPC_DEFAULT_DEADLINE"`
+	// MaxRecvMsgSize — максимальный размер запроса в байтах; больший запрос отклоняется с INVALID_ARGUMENT
+	// и деталями errdetails.ErrorInfo. 0 — лимит gRPC по умолчанию (4 МБ) с исходной ошибкой RESOURCE_EXHAUSTED
+	MaxRecvMsgSize int `envconfig:"GRPC_MAX_RECV_MSG_SIZE"`
+	// MaxSendMsgSize — максимальный размер ответа в байтах; больший ответ заменяется ошибкой RESOURCE_EXHAUSTED.
+	// 0 — без ограничения
+	MaxSendMsgSize int `envconfig:"GRPC_MAX_SEND_MSG_SIZE"`
+	// EnableGRPCWeb запускает HTTP сервер gRPC-Web на Host:GRPCWebPort для браузерных клиентов
+	// без отдельного Envoy; запросы обслуживает тот же gRPC сервер с той же цепочкой интерцепторов
+	EnableGRPCWeb bool `envconfig:"GRPC_ENABLE_WEB" default:"false"`
+	// GRPCWebPort — порт сервера gRPC-Web, обязателен при EnableGRPCWeb
+	GRPCWebPort int `envconfig:"GRPC_WEB_PORT"`
+	// GRPCWebAllowedOrigins — origins, которым разрешены запросы gRPC-Web (CORS); "*" — любые.
+	// Запросы с Origin не из списка отклоняются с 403, запросы без Origin (не из браузера) принимаются
+	GRPCWebAllowedOrigins []string `envconfig:"GRPC_WEB_ALLOWED_ORIGINS" default:"*"`
+	// GRPCWebAllowedHeaders — заголовки запроса (метаданные), разрешённые в CORS помимо нужных gRPC-Web;
+	// "*" — любые запрошенные
+	GRPCWebAllowedHeaders []string `envconfig:"GRPC_WEB_ALLOWED_HEADERS"`
+	// GRPCWebExposedHeaders — заголовки ответа (метаданные), доступные браузерному клиенту помимо grpc-status,
+	// grpc-message и grpc-status-details-bin
+	GRPCWebExposedHeaders []string `envconfig:"GRPC_WEB_EXPOSED_HEADERS"`
+	// GRPCWebAllowCredentials разрешает запросы с cookie и HTTP аутентификацией (Access-Control-Allow-Credentials)
+	GRPCWebAllowCredentials bool `envconfig:"GRPC_WEB_ALLOW_CREDENTIALS" default:"false"`
+	// GRPCWebCORSMaxAge — время кэширования ответа на preflight в браузере; 0 — не кэшируется
+	GRPCWebCORSMaxAge time.Duration `envconfig:"GRPC_WEB_CORS_MAX_AGE" default:"10m"`
+	// SkipMethods — glob-шаблоны методов без трассировки, метрик и логов запросов, дополняющие
+	// MonitoringOptions.SkipMethods: "/grpc.health.v1.Health/*,/grpc.reflection.*/*" (см. middleware.MatchMethod)
+	SkipMethods []string `envconfig:"GRPC_SKIP_METHODS"`
 }
 
 type ServerOption func(*Server)
@@ -38,11 +131,19 @@ type Server struct {
 	server             *grpc.Server
 	config             Config
 	listener           net.Listener
+	adminListener      net.Listener
+	adminHTTPListener  net.Listener
+	adminHTTPServer    *http.Server
+	grpcWebListener    net.Listener
+	grpcWebServer      *http.Server
 	listenerMu         sync.RWMutex
 	interceptors       []grpc.UnaryServerInterceptor
 	streamInterceptors []grpc.StreamServerInterceptor
 	serverOpts         []grpc.ServerOption
 	monitoringOpts     *middleware.MonitoringOptions
+	chainInterceptors  []middleware.ChainInterceptor
+	handoffCommand     func() *exec.Cmd
+	configErr          error
 }
 
 func WithUnaryInterceptor(interceptor grpc.UnaryServerInterceptor) ServerOption {
@@ -71,6 +172,15 @@ func WithMonitoringOptions(opts *middleware.MonitoringOptions) ServerOption {
 	}
 }
 
+// WithChainInterceptor встраивает интерцептор в именованную позицию цепочки мониторинга.
+// В отличие от WithUnaryInterceptor/WithStreamInterceptor, которые добавляют интерцепторы
+// в конец цепочки, позволяет разместить его, например, до трассировки или в слоте аутентификации.
+func WithChainInterceptor(interceptor middleware.ChainInterceptor) ServerOption {
+	return func(s *Server) {
+		s.chainInterceptors = append(s.chainInterceptors, interceptor)
+	}
+}
+
 func NewDefault(c Config, registrationFunc func(*grpc.Server)) *Server {
 	s := New(c, registrationFunc)
 	return s
@@ -89,11 +199,58 @@ func New(c Config, registrationFunc func(*grpc.Server), opts ...ServerOption) *S
 		opt(s)
 	}
 
+	// Проверяем конфигурацию: New не возвращает ошибку, поэтому Start и Serve отказываются запускать сервер
+	if err := c.Validate(); err != nil {
+		s.configErr = errors.Wrap(err, "invalid gRPC server config")
+		s.logger.With("error", err).Error("invalid gRPC server config")
+	}
+
+	// Настраиваем сжатие
+	compressionInterceptors, compressionOpts := compressionOptions(c, s.logger)
+	s.chainInterceptors = append(s.chainInterceptors, compressionInterceptors...)
+	s.serverOpts = append(s.serverOpts, compressionOpts...)
+
+	// Настраиваем лимиты размера сообщений; опции из WithServerOption применяются позже и имеют приоритет
+	messageSizeInterceptors, messageSizeOpts := messageSizeOptions(c)
+	s.chainInterceptors = append(s.chainInterceptors, messageSizeInterceptors...)
+	s.serverOpts = append(messageSizeOpts, s.serverOpts...)
+
+	// Настраиваем бюджет дедлайна
+	if c.DeadlineMargin > 0 {
+		s.chainInterceptors = append(s.chainInterceptors, middleware.ChainInterceptor{
+			Position: middleware.PositionAfterLogging,
+			Unary:    middleware.DeadlineBudgetUnaryServerInterceptor(c.DeadlineMargin, middleware.WithDefaultDeadline(c.DefaultDeadline)),
+			Stream:   middleware.DeadlineBudgetStreamServerInterceptor(c.DeadlineMargin, middleware.WithDefaultDeadline(c.DefaultDeadline)),
+		})
+	}
+
+	// Настраиваем ограничение одновременных запросов
+	if limiter := concurrencyLimiter(c); limiter != nil {
+		s.chainInterceptors = append(s.chainInterceptors, middleware.ChainInterceptor{
+			Position: middleware.PositionAfterLogging,
+			Unary:    limiter.UnaryServerInterceptor(),
+			Stream:   limiter.StreamServerInterceptor(),
+		})
+	}
+
 	// Настраиваем мониторинг
 	monitoringOptions := s.monitoringOpts
 	if monitoringOptions == nil {
 		monitoringOptions = middleware.DefaultMonitoringOptions(s.logger)
 	}
+	peerIPExtractor := trustedProxiesExtractor(c)
+	if len(s.chainInterceptors) > 0 || len(c.SkipMethods) > 0 || c.PeerInfo && !monitoringOptions.EnablePeerInfo ||
+		peerIPExtractor != nil && monitoringOptions.PeerIPExtractor == nil {
+		// Копируем настройки, чтобы не изменять переданную структуру
+		optsCopy := *monitoringOptions
+		optsCopy.Interceptors = append(slices.Clone(monitoringOptions.Interceptors), s.chainInterceptors...)
+		optsCopy.SkipMethods = append(slices.Clone(monitoringOptions.SkipMethods), c.SkipMethods...)
+		optsCopy.EnablePeerInfo = monitoringOptions.EnablePeerInfo || c.PeerInfo
+		if optsCopy.PeerIPExtractor == nil {
+			optsCopy.PeerIPExtractor = peerIPExtractor
+		}
+		monitoringOptions = &optsCopy
+	}
 	unaryInterceptors, streamInterceptors, monitoringOpts := middleware.SetupMonitoring(
 		context.Background(),
 		monitoringOptions,
@@ -117,8 +274,8 @@ func New(c Config, registrationFunc func(*grpc.Server), opts ...ServerOption) *S
 	}))
 
 	// Настройка TLS если необходимо
-	if c.TLSCertPath != "" && c.TLSKeyPath != "" {
-		creds, err := credentials.NewServerTLSFromFile(c.TLSCertPath, c.TLSKeyPath)
+	if s.configErr == nil && c.TLSCertPath != "" && c.TLSKeyPath != "" {
+		creds, err := serverCredentials(c)
 		if err != nil {
 			s.logger.With("error", err).Error("failed to create TLS credentials")
 		} else {
@@ -133,28 +290,89 @@ func New(c Config, registrationFunc func(*grpc.Server), opts ...ServerOption) *S
 	registrationFunc(s.server)
 
 	// Добавляем reflection API если нужно
-	if c.EnableReflect {
-		reflection.Register(s.server)
+	if reflectionEnabled(c) {
+		registerReflection(s.server, c)
+	} else if c.EnableReflect {
+		s.logger.Info("gRPC reflection disabled for environment", "environment", c.Environment)
 	}
 
 	return s
 }
 
+// NewWithListener создаёт сервер, который обслуживает переданный listener вместо открытия
+// Network/Host/Port из конфигурации: bufconn в тестах, systemd socket activation, встраивание.
+// Start обслуживает lis; Close закрывает его.
+func NewWithListener(lis net.Listener, c Config, registrationFunc func(*grpc.Server), opts ...ServerOption) *Server {
+	s := New(c, registrationFunc, opts...)
+	s.listener = lis
+	return s
+}
+
+// Start открывает основной listener по конфигурации (или использует переданный в NewWithListener
+// либо унаследованный от процесса, вызвавшего Handoff, или от systemd) и обслуживает его до Close
 func (s *Server) Start() error {
-	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
+	if s.configErr != nil {
+		return s.configErr
+	}
+
+	s.listenerMu.RLock()
+	lis := s.listener
+	s.listenerMu.RUnlock()
+	if lis != nil {
+		return s.Serve(lis)
+	}
+
+	lis, err := inheritedListener(fdNameMain)
+	if err != nil {
+		return err
+	}
+	if lis != nil {
+		s.logger.Info("using inherited gRPC listener")
+		return s.Serve(lis)
+	}
+
+	network, addr, err := s.mainAddress()
+	if err != nil {
+		return err
+	}
 
-	lis, err := net.Listen("tcp", addr)
+	lis, err = listen(network, addr)
 	if err != nil {
-		return errors.Wrapf(err, "failed to listen on %s", addr)
+		return err
+	}
+
+	return s.Serve(lis)
+}
+
+// Serve обслуживает переданный listener до Close. Дополнительный listener и admin HTTP сервер
+// запускаются так же, как в Start. Listener закрывается при Close или при ошибке запуска.
+func (s *Server) Serve(lis net.Listener) error {
+	if s.configErr != nil {
+		if closeErr := closeListener(lis); closeErr != nil {
+			s.logger.With("error", closeErr).Warn("failed to close main listener")
+		}
+		return s.configErr
 	}
 
 	s.listenerMu.Lock()
 	s.listener = lis
 	s.listenerMu.Unlock()
 
-	s.logger.Info("gRPC server starting", "addr", addr)
+	if err := s.startAuxiliary(); err != nil {
+		closeErr := closeListener(lis)
+		if closeErr != nil {
+			s.logger.With("error", closeErr).Warn("failed to close main listener")
+		}
+		return err
+	}
 
-	err = s.server.Serve(lis)
+	if err := notifyHandoffReady(); err != nil {
+		s.logger.With("error", err).Warn("failed to notify previous process")
+	}
+
+	s.logger.Info("gRPC server starting", "network", lis.Addr().Network(), "addr", lis.Addr().String())
+
+	err := s.server.Serve(lis)
 	if err != nil && !errors.Is(err, net.ErrClosed) {
 		return errors.Wrap(err, "failed to serve gRPC")
 	}
@@ -162,7 +380,149 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// startAuxiliary запускает дополнительный gRPC listener, admin HTTP сервер и сервер gRPC-Web, если они настроены
+func (s *Server) startAuxiliary() error {
+	if s.config.AdminAddress != "" {
+		if err := s.startAdmin(); err != nil {
+			return err
+		}
+	}
+
+	if s.config.AdminPort != 0 {
+		if err := s.startAdminHTTP(); err != nil {
+			if closeErr := s.CloseAdmin(); closeErr != nil {
+				s.logger.With("error", closeErr).Warn("failed to close admin listener")
+			}
+			return err
+		}
+	}
+
+	if s.config.EnableGRPCWeb {
+		if err := s.startGRPCWeb(); err != nil {
+			if closeErr := s.CloseAdmin(); closeErr != nil {
+				s.logger.With("error", closeErr).Warn("failed to close admin listener")
+			}
+			if closeErr := s.closeAdminHTTP(context.Background()); closeErr != nil {
+				s.logger.With("error", closeErr).Warn("failed to close admin http server")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mainAddress возвращает сеть и адрес основного listener'а
+func (s *Server) mainAddress() (string, string, error) {
+	network := s.config.Network
+	if network == "" {
+		network = NetworkTCP
+	}
+
+	switch network {
+	case NetworkTCP:
+		if s.config.Port == 0 {
+			return "", "", errors.New("port is required for tcp network")
+		}
+		return network, fmt.Sprintf("%s:%d", s.config.Host, s.config.Port), nil
+	case NetworkUnix:
+		if s.config.SocketPath == "" {
+			return "", "", errors.New("socket path is required for unix network")
+		}
+		return network, s.config.SocketPath, nil
+	default:
+		return "", "", errors.Errorf("unsupported network %q", network)
+	}
+}
+
+// startAdmin открывает дополнительный listener и обслуживает его в отдельной горутине
+func (s *Server) startAdmin() error {
+	network := s.config.AdminNetwork
+	if network == "" {
+		network = NetworkUnix
+	}
+	if network != NetworkTCP && network != NetworkUnix {
+		return errors.Errorf("unsupported admin network %q", network)
+	}
+
+	lis, err := inheritedListener(fdNameAdmin)
+	if err != nil {
+		return err
+	}
+	if lis == nil {
+		lis, err = listen(network, s.config.AdminAddress)
+		if err != nil {
+			return errors.Wrap(err, "failed to start admin listener")
+		}
+	}
+
+	s.listenerMu.Lock()
+	s.adminListener = lis
+	s.listenerMu.Unlock()
+
+	s.logger.Info("gRPC admin listener starting", "network", network, "addr", s.config.AdminAddress)
+
+	go func() {
+		err := s.server.Serve(lis)
+		if err != nil && !errors.Is(err, net.ErrClosed) {
+			s.logger.With("error", err).Error("gRPC admin listener crashed")
+		}
+	}()
+
+	return nil
+}
+
+// listen открывает listener; для unix сокета предварительно удаляет оставшийся файл сокета
+func listen(network, addr string) (net.Listener, error) {
+	if network == NetworkUnix {
+		if err := removeStaleSocket(addr); err != nil {
+			return nil, err
+		}
+	}
+
+	lis, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen on %s %s", network, addr)
+	}
+	return lis, nil
+}
+
+// removeStaleSocket удаляет файл unix сокета, оставшийся от предыдущего запуска
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat socket %s", path)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return errors.Errorf("failed to listen on %s: file exists and is not a socket", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return errors.Wrapf(err, "failed to remove stale socket %s", path)
+	}
+	return nil
+}
+
+// closeListener закрывает listener, игнорируя ошибку повторного закрытия
+func closeListener(lis net.Listener) error {
+	if lis == nil {
+		return nil
+	}
+	err := lis.Close()
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
 func (s *Server) Close() error {
+	// Сервер gRPC-Web останавливается первым: его запросы дорабатывают, пока gRPC сервер принимает их
+	webCtx, webCancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer webCancel()
+	grpcWebErr := s.closeGRPCWeb(webCtx)
+
 	stopped := make(chan struct{})
 
 	go func() {
@@ -185,13 +545,38 @@ func (s *Server) Close() error {
 	listener := s.listener
 	s.listenerMu.RUnlock()
 
-	if listener != nil {
-		err := listener.Close()
-		if err != nil {
-			return errors.Wrap(err, "failed to close listener")
-		}
+	adminErr := s.CloseAdmin()
+
+	// Отдельный таймаут: ctx мог истечь при принудительной остановке gRPC сервера
+	httpCtx, httpCancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer httpCancel()
+	adminHTTPErr := s.closeAdminHTTP(httpCtx)
+
+	err := closeListener(listener)
+	if err != nil {
+		return errors.Wrap(err, "failed to close listener")
+	}
+
+	if adminErr != nil {
+		return adminErr
+	}
+	if adminHTTPErr != nil {
+		return adminHTTPErr
 	}
+	return grpcWebErr
+}
+
+// CloseAdmin закрывает только дополнительный listener; основной продолжает обслуживать запросы.
+// Безопасен для повторного вызова и при отсутствии дополнительного listener'а.
+func (s *Server) CloseAdmin() error {
+	s.listenerMu.RLock()
+	listener := s.adminListener
+	s.listenerMu.RUnlock()
 
+	err := closeListener(listener)
+	if err != nil {
+		return errors.Wrap(err, "failed to close admin listener")
+	}
 	return nil
 }
 
@@ -211,3 +596,10 @@ func (s *Server) GetListener() net.Listener {
 	defer s.listenerMu.RUnlock()
 	return s.listener
 }
+
+// GetAdminListener возвращает дополнительный listener или nil, если он не запущен
+func (s *Server) GetAdminListener() net.Listener {
+	s.listenerMu.RLock()
+	defer s.listenerMu.RUnlock()
+	return s.adminListener
+}
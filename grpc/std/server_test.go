@@ -4,14 +4,24 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/kelseyhightower/envconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/pure-golang/adapters/grpc/middleware"
 	"github.com/pure-golang/adapters/logger"
@@ -196,6 +206,31 @@ func TestNew_WithMonitoringOptions(t *testing.T) {
 	assert.Same(t, customMonitoringOpts, s.monitoringOpts, "should use custom monitoring options")
 }
 
+func TestNew_WithChainInterceptor(t *testing.T) {
+	t.Parallel()
+	c := Config{
+		Port: 9096,
+	}
+
+	testLogger := slog.New(noop.NewNoop().Handler())
+	customMonitoringOpts := &middleware.MonitoringOptions{
+		Logger:        testLogger,
+		EnableLogging: true,
+	}
+	mockInterceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(ctx, req)
+	}
+
+	s := New(c, func(s *grpc.Server) {},
+		WithMonitoringOptions(customMonitoringOpts),
+		WithChainInterceptor(middleware.UnaryAt(middleware.PositionFirst, mockInterceptor)),
+	)
+
+	require.NotNil(t, s)
+	assert.Len(t, s.chainInterceptors, 1, "should have one chain interceptor")
+	assert.Empty(t, customMonitoringOpts.Interceptors, "caller's monitoring options must not be modified")
+}
+
 func TestNew_WithNilMonitoringOptions(t *testing.T) {
 	t.Parallel()
 	c := Config{
@@ -337,6 +372,14 @@ func TestServer_Start_Errors(t *testing.T) {
 				Host: "invalid.host.with.bad.chars!@#",
 				Port: 9999,
 			},
+			expectError: "invalid gRPC server config: GRPC_HOST",
+		},
+		{
+			name: "address not available",
+			config: Config{
+				Host: "192.0.2.1", // TEST-NET-1, не назначен локальным интерфейсам
+				Port: 9999,
+			},
 			expectError: "failed to listen",
 		},
 	}
@@ -349,7 +392,7 @@ func TestServer_Start_Errors(t *testing.T) {
 
 			err := s.Start()
 			assert.Error(t, err)
-			assert.Contains(t, err.Error(), "failed to listen")
+			assert.Contains(t, err.Error(), tt.expectError)
 		})
 	}
 }
@@ -444,6 +487,14 @@ func TestConfig_DefaultValues(t *testing.T) {
 	assert.Equal(t, false, c.EnableReflect)
 }
 
+// TestConfig_PeerInfoOptIn tests that PeerInfo stays disabled unless GRPC_PEER_INFO is set.
+func TestConfig_PeerInfoOptIn(t *testing.T) {
+	t.Parallel()
+	var c Config
+	require.NoError(t, envconfig.Process("", &c))
+	assert.False(t, c.PeerInfo)
+}
+
 func TestNew_WithEmptyHost(t *testing.T) {
 	t.Parallel()
 	c := Config{
@@ -818,3 +869,234 @@ func BenchmarkNew_WithOptions(b *testing.B) {
 		_ = s
 	}
 }
+
+func TestServer_MainAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		config      Config
+		network     string
+		addr        string
+		expectError string
+	}{
+		{
+			name:    "default network is tcp",
+			config:  Config{Host: "localhost", Port: 9130},
+			network: NetworkTCP,
+			addr:    "localhost:9130",
+		},
+		{
+			name:    "unix socket",
+			config:  Config{Network: NetworkUnix, SocketPath: "/tmp/grpc.sock"},
+			network: NetworkUnix,
+			addr:    "/tmp/grpc.sock",
+		},
+		{
+			name:        "tcp without port",
+			config:      Config{Network: NetworkTCP},
+			expectError: "port is required",
+		},
+		{
+			name:        "unix without socket path",
+			config:      Config{Network: NetworkUnix},
+			expectError: "socket path is required",
+		},
+		{
+			name:        "unsupported network",
+			config:      Config{Network: "udp", Port: 9131},
+			expectError: "unsupported network",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(tt.config, func(srv *grpc.Server) {})
+
+			network, addr, err := s.mainAddress()
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.network, network)
+			assert.Equal(t, tt.addr, addr)
+		})
+	}
+}
+
+func TestServer_Start_UnsupportedAdminNetwork(t *testing.T) {
+	t.Parallel()
+	c := Config{
+		Network:      NetworkUnix,
+		SocketPath:   filepath.Join(t.TempDir(), "main.sock"),
+		AdminNetwork: "udp",
+		AdminAddress: "localhost:9132",
+	}
+
+	s := New(c, func(srv *grpc.Server) {})
+
+	err := s.Start()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `GRPC_ADMIN_NETWORK: unsupported network "udp"`)
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, removeStaleSocket(filepath.Join(dir, "missing.sock")))
+	})
+
+	t.Run("regular file is not removed", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(dir, "regular.sock")
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+
+		err := removeStaleSocket(path)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a socket")
+		assert.FileExists(t, path)
+	})
+}
+
+func TestServer_CloseAdmin_WithoutAdminListener(t *testing.T) {
+	t.Parallel()
+	s := New(Config{Port: 9133}, func(srv *grpc.Server) {})
+
+	assert.Nil(t, s.GetAdminListener())
+	assert.NoError(t, s.CloseAdmin())
+}
+
+// TestNewWithListener_ServesBufconn tests that the server serves a supplied in-memory listener
+func TestNewWithListener_ServesBufconn(t *testing.T) {
+	t.Parallel()
+	lis := bufconn.Listen(1 << 20)
+	s := NewWithListener(lis, Config{}, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- s.Start()
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	assert.Equal(t, lis, s.GetListener())
+
+	require.NoError(t, s.Close())
+	assert.NoError(t, <-startErr)
+}
+
+// TestServer_Serve_TCPListener tests serving a listener bound by the caller
+func TestServer_Serve_TCPListener(t *testing.T) {
+	t.Parallel()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := New(Config{}, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- s.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Close())
+	assert.NoError(t, <-startErr)
+}
+
+// TestCompressionOptions tests interceptors and server options built from compression settings
+func TestCompressionOptions(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.DiscardHandler)
+
+	interceptors, opts := compressionOptions(Config{}, logger)
+	assert.Empty(t, interceptors)
+	assert.Empty(t, opts)
+
+	interceptors, opts = compressionOptions(Config{Compression: "zstd", CompressionThreshold: 1024}, logger)
+	require.Len(t, interceptors, 2)
+	assert.Equal(t, middleware.PositionFirst, interceptors[0].Position)
+	assert.Equal(t, middleware.PositionAfterLogging, interceptors[1].Position)
+	assert.Len(t, opts, 1, "compression stats handler")
+
+	interceptors, _ = compressionOptions(Config{Compression: "brotli"}, logger)
+	assert.Empty(t, interceptors, "unknown compressor is ignored")
+}
+
+// TestConcurrencyLimiter tests that the limiter is created only when limits are configured
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, concurrencyLimiter(Config{}))
+	assert.NotNil(t, concurrencyLimiter(Config{MaxConcurrentRequests: 100}))
+	assert.NotNil(t, concurrencyLimiter(Config{MethodConcurrencyLimits: map[string]int{"/svc.Reports/Build": 2}}))
+}
+
+// TestNew_DeadlineMargin tests that the deadline budget interceptor is added only when a margin is configured.
+func TestNew_DeadlineMargin(t *testing.T) {
+	t.Parallel()
+
+	s := New(Config{Port: 9098}, func(*grpc.Server) {})
+	assert.Empty(t, s.chainInterceptors)
+
+	s = New(Config{Port: 9098, DeadlineMargin: 50 * time.Millisecond}, func(*grpc.Server) {})
+	require.Len(t, s.chainInterceptors, 1)
+	assert.Equal(t, middleware.PositionAfterLogging, s.chainInterceptors[0].Position)
+}
+
+// TestNew_MessageSizeLimits tests that oversized requests are rejected with INVALID_ARGUMENT.
+func TestNew_MessageSizeLimits(t *testing.T) {
+	t.Parallel()
+
+	interceptors, opts := messageSizeOptions(Config{})
+	assert.Empty(t, interceptors)
+	assert.Empty(t, opts)
+
+	lis := bufconn.Listen(1 << 20)
+	s := NewWithListener(lis, Config{MaxRecvMsgSize: 1024, MaxSendMsgSize: 1024}, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+	go func() { _ = s.Start() }()
+	defer s.Close()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 1500)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "exceeds the limit of 1024 bytes")
+}
@@ -1,13 +1,19 @@
 package std_test
 
 import (
+	"context"
 	"net"
+	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/pure-golang/adapters/grpc/std"
 )
@@ -136,3 +142,104 @@ func TestServer_Close_Timeout(t *testing.T) {
 		t.Skip("port was taken")
 	}
 }
+
+func TestServer_UnixSocketAndAdminListener(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	// Find an available port for the admin TCP listener
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	adminAddr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	socketPath := filepath.Join(t.TempDir(), "grpc.sock")
+	c := std.Config{
+		Network:      std.NetworkUnix,
+		SocketPath:   socketPath,
+		AdminNetwork: std.NetworkTCP,
+		AdminAddress: adminAddr,
+	}
+
+	s := std.New(c, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+
+	startDone := make(chan error, 1)
+	go func() {
+		startDone <- s.Start()
+	}()
+
+	require.Eventually(t, func() bool {
+		return s.GetListener() != nil && s.GetAdminListener() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "unix", s.GetListener().Addr().Network())
+	assert.Equal(t, "tcp", s.GetAdminListener().Addr().Network())
+
+	check := func(target string) error {
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+
+	assert.NoError(t, check("unix://"+socketPath))
+	assert.NoError(t, check(adminAddr))
+
+	// Closing the admin listener keeps the main listener serving
+	require.NoError(t, s.CloseAdmin())
+	assert.NoError(t, check("unix://"+socketPath))
+
+	require.NoError(t, s.Close())
+	assert.NoError(t, <-startDone)
+	assert.NoFileExists(t, socketPath)
+}
+
+func TestServer_AdminHTTP(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	// Find an available port for the admin HTTP server
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	adminPort := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	c := std.Config{
+		Network:    std.NetworkUnix,
+		SocketPath: filepath.Join(t.TempDir(), "grpc.sock"),
+		Host:       "127.0.0.1",
+		AdminPort:  adminPort,
+	}
+	s := std.New(c, func(srv *grpc.Server) {})
+
+	startDone := make(chan error, 1)
+	go func() {
+		startDone <- s.Start()
+	}()
+
+	require.Eventually(t, func() bool {
+		return s.GetListener() != nil && s.GetAdminHTTPListener() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	baseURL := "http://" + s.GetAdminHTTPListener().Addr().String()
+	for _, path := range []string{"/debug/pprof/", "/debug/runtime", "/debug/buildinfo", "/debug/grpc/channelz/servers"} {
+		resp, err := http.Get(baseURL + path)
+		require.NoError(t, err, path)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	require.NoError(t, s.Close())
+	assert.NoError(t, <-startDone)
+
+	_, err = http.Get(baseURL + "/debug/runtime")
+	assert.Error(t, err, "admin http server must be stopped with the gRPC server")
+}
@@ -0,0 +1,46 @@
+package std
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// serverCredentials создаёт TLS credentials сервера; с TLSClientCAPath клиент обязан предъявить
+// сертификат, подписанный одним из CA файла (mTLS)
+func serverCredentials(c Config) (credentials.TransportCredentials, error) {
+	if c.TLSClientCAPath == "" {
+		return credentials.NewServerTLSFromFile(c.TLSCertPath, c.TLSKeyPath)
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertPath, c.TLSKeyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load TLS key pair")
+	}
+	clientCAs, err := loadCertPool(c.TLSClientCAPath)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// loadCertPool читает PEM файл с сертификатами CA
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read client CA file")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
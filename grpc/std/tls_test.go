@@ -0,0 +1,101 @@
+package std
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+// TestServer_MutualTLS tests that clients must present a certificate signed by the client CA
+// and that its identity reaches handlers as middleware.PeerInfo.
+func TestServer_MutualTLS(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	serverCert, serverKey := writeTestKeyPair(t, dir, "server")
+	clientCert, clientKey := writeTestKeyPair(t, dir, "client")
+
+	peers := make(chan middleware.PeerInfo, 1)
+	lis := bufconn.Listen(1 << 20)
+	s := NewWithListener(lis, Config{
+		TLSCertPath:     serverCert,
+		TLSKeyPath:      serverKey,
+		TLSClientCAPath: clientCert,
+		PeerInfo:        true,
+	}, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	}, WithUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		info, _ := middleware.PeerInfoFromContext(ctx)
+		peers <- info
+		return handler(ctx, req)
+	}))
+	go func() { _ = s.Start() }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	serverPEM, err := os.ReadFile(serverCert)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(serverPEM))
+
+	dial := func(certs ...tls.Certificate) healthpb.HealthClient {
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+				RootCAs:      roots,
+				ServerName:   "localhost",
+				Certificates: certs,
+				MinVersion:   tls.VersionTLS12,
+			})),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+		return healthpb.NewHealthClient(conn)
+	}
+
+	pair, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	require.NoError(t, err)
+	_, err = dial(pair).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	info := <-peers
+	assert.True(t, info.TLS)
+	assert.Equal(t, "h2", info.ALPN)
+	assert.True(t, info.HasClientCert())
+	assert.Equal(t, "localhost", info.CommonName)
+
+	// Без сертификата клиента соединение не устанавливается
+	_, err = dial().Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Error(t, err)
+}
+
+// TestConfig_Validate_ClientCA tests validation of the client CA file.
+func TestConfig_Validate_ClientCA(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	certPath, keyPath := writeTestKeyPair(t, dir, "server")
+
+	require.NoError(t, Config{TLSCertPath: certPath, TLSKeyPath: keyPath, TLSClientCAPath: certPath}.Validate())
+
+	err := Config{TLSClientCAPath: certPath}.Validate()
+	assert.EqualError(t, err, "GRPC_TLS_CLIENT_CA_PATH: requires GRPC_TLS_CERT_PATH and GRPC_TLS_KEY_PATH")
+
+	garbage := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(garbage, []byte("not a certificate"), 0o600))
+	err = Config{TLSCertPath: certPath, TLSKeyPath: keyPath, TLSClientCAPath: garbage}.Validate()
+	assert.EqualError(t, err, "GRPC_TLS_CLIENT_CA_PATH: invalid client CA file: no PEM certificates found in "+garbage)
+}
@@ -0,0 +1,182 @@
+package std
+
+import (
+	"crypto/tls"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/pure-golang/adapters/env"
+	"github.com/pure-golang/adapters/grpc/middleware"
+)
+
+var _ env.Validatable = Config{}
+
+// Validate проверяет значения конфигурации: сети, диапазоны портов, синтаксис хоста, наличие
+// и соответствие TLS сертификата и ключа, CA клиентов, доверенные прокси, компрессор, неотрицательность лимитов и шаблоны SkipMethods.
+// Ошибки всех полей возвращаются разом как *env.ValidationError.
+//
+// Наличие адреса основного listener'а (Port или SocketPath) не проверяется: сервер может
+// обслуживать listener из NewWithListener или унаследованный, адрес проверяет Start.
+func (c Config) Validate() error {
+	verr := &env.ValidationError{}
+
+	validateNetwork(verr, "GRPC_NETWORK", c.Network)
+	validateNetwork(verr, "GRPC_ADMIN_NETWORK", c.AdminNetwork)
+	validatePort(verr, "GRPC_PORT", c.Port)
+	validatePort(verr, "GRPC_ADMIN_PORT", c.AdminPort)
+	if c.Host != "" && !validHost(c.Host) {
+		verr.Add("GRPC_HOST", "invalid host %q: expected an IP address or a DNS name", c.Host)
+	}
+	if c.AdminPort != 0 && c.AdminPort == c.Port && (c.Network == "" || c.Network == NetworkTCP) {
+		verr.Add("GRPC_ADMIN_PORT", "must differ from GRPC_PORT %d", c.Port)
+	}
+	if c.DescriptorExport && c.AdminPort == 0 {
+		verr.Add("GRPC_DESCRIPTOR_EXPORT", "requires GRPC_ADMIN_PORT")
+	}
+	if c.OpenAPIExport && c.AdminPort == 0 {
+		verr.Add("GRPC_OPENAPI_EXPORT", "requires GRPC_ADMIN_PORT")
+	}
+	validatePort(verr, "GRPC_WEB_PORT", c.GRPCWebPort)
+	if c.EnableGRPCWeb {
+		switch {
+		case c.GRPCWebPort == 0:
+			verr.Add("GRPC_WEB_PORT", "required when GRPC_ENABLE_WEB is set")
+		case c.GRPCWebPort == c.Port && (c.Network == "" || c.Network == NetworkTCP):
+			verr.Add("GRPC_WEB_PORT", "must differ from GRPC_PORT %d", c.Port)
+		case c.GRPCWebPort == c.AdminPort:
+			verr.Add("GRPC_WEB_PORT", "must differ from GRPC_ADMIN_PORT %d", c.AdminPort)
+		}
+	}
+	if _, err := middleware.ParseTrustedProxies(c.TrustedProxies); err != nil {
+		verr.Add("GRPC_TRUSTED_PROXIES", "%v", err)
+	}
+	validateTLS(verr, c.TLSCertPath, c.TLSKeyPath)
+	validateClientCA(verr, c)
+
+	if c.Compression != "" && encoding.GetCompressor(c.Compression) == nil {
+		verr.Add("GRPC_COMPRESSION", "unknown compressor %q", c.Compression)
+	}
+	for _, f := range []struct {
+		name  string
+		value int64
+	}{
+		{"GRPC_COMPRESSION_THRESHOLD", int64(c.CompressionThreshold)},
+		{"GRPC_MAX_CONCURRENT_REQUESTS", int64(c.MaxConcurrentRequests)},
+		{"GRPC_CONCURRENCY_QUEUE_TIMEOUT", int64(c.ConcurrencyQueueTimeout)},
+		{"GRPC_CONCURRENCY_RETRY_DELAY", int64(c.ConcurrencyRetryDelay)},
+		{"GRPC_DEADLINE_MARGIN", int64(c.DeadlineMargin)},
+		{"GRPC_DEFAULT_DEADLINE", int64(c.DefaultDeadline)},
+		{"GRPC_MAX_RECV_MSG_SIZE", int64(c.MaxRecvMsgSize)},
+		{"GRPC_MAX_SEND_MSG_SIZE", int64(c.MaxSendMsgSize)},
+		{"GRPC_WEB_CORS_MAX_AGE", int64(c.GRPCWebCORSMaxAge)},
+	} {
+		if f.value < 0 {
+			verr.Add(f.name, "must not be negative")
+		}
+	}
+	for _, method := range slices.Sorted(maps.Keys(c.MethodConcurrencyLimits)) {
+		if limit := c.MethodConcurrencyLimits[method]; limit <= 0 {
+			verr.Add("GRPC_METHOD_CONCURRENCY_LIMITS", "limit of %s must be positive, got %d", method, limit)
+		}
+	}
+	for _, pattern := range c.SkipMethods {
+		if _, err := middleware.MatchMethod([]string{pattern}, ""); err != nil {
+			verr.Add("GRPC_SKIP_METHODS", "invalid method pattern %q", pattern)
+		}
+	}
+
+	return verr.Err()
+}
+
+func validateNetwork(verr *env.ValidationError, field, network string) {
+	if network != "" && network != NetworkTCP && network != NetworkUnix {
+		verr.Add(field, "unsupported network %q: expected %q or %q", network, NetworkTCP, NetworkUnix)
+	}
+}
+
+func validatePort(verr *env.ValidationError, field string, port int) {
+	if port < 0 || port > 65535 {
+		verr.Add(field, "port %d out of range [1, 65535]", port)
+	}
+}
+
+// validHost сообщает, что host — IP адрес или DNS имя (RFC 1123)
+func validHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	if len(host) > 253 {
+		return false
+	}
+	for label := range strings.SplitSeq(strings.TrimSuffix(host, "."), ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validateTLS проверяет, что сертификат и ключ заданы вместе, существуют и образуют пару
+func validateTLS(verr *env.ValidationError, certPath, keyPath string) {
+	switch {
+	case certPath == "" && keyPath == "":
+		return
+	case certPath == "":
+		verr.Add("GRPC_TLS_CERT_PATH", "required when GRPC_TLS_KEY_PATH is set")
+		return
+	case keyPath == "":
+		verr.Add("GRPC_TLS_KEY_PATH", "required when GRPC_TLS_CERT_PATH is set")
+		return
+	}
+
+	certErr := validateFile(verr, "GRPC_TLS_CERT_PATH", certPath)
+	keyErr := validateFile(verr, "GRPC_TLS_KEY_PATH", keyPath)
+	if certErr != nil || keyErr != nil {
+		return
+	}
+	if _, err := tls.LoadX509KeyPair(certPath, keyPath); err != nil {
+		verr.Add("GRPC_TLS_KEY_PATH", "certificate and key do not match or are malformed: %v", err)
+	}
+}
+
+// validateClientCA проверяет, что CA клиентов задан вместе с сертификатом сервера и содержит сертификаты
+func validateClientCA(verr *env.ValidationError, c Config) {
+	if c.TLSClientCAPath == "" {
+		return
+	}
+	if c.TLSCertPath == "" || c.TLSKeyPath == "" {
+		verr.Add("GRPC_TLS_CLIENT_CA_PATH", "requires GRPC_TLS_CERT_PATH and GRPC_TLS_KEY_PATH")
+		return
+	}
+	if validateFile(verr, "GRPC_TLS_CLIENT_CA_PATH", c.TLSClientCAPath) != nil {
+		return
+	}
+	if _, err := loadCertPool(c.TLSClientCAPath); err != nil {
+		verr.Add("GRPC_TLS_CLIENT_CA_PATH", "invalid client CA file: %v", err)
+	}
+}
+
+// validateFile проверяет, что файл существует и доступен
+func validateFile(verr *env.ValidationError, field, path string) error {
+	_, err := os.Stat(path)
+	if err != nil {
+		reason := err
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			reason = pathErr.Err
+		}
+		verr.Add(field, "cannot read %s: %v", path, reason)
+	}
+	return err
+}
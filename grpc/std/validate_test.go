@@ -0,0 +1,119 @@
+package std
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/pure-golang/adapters/env"
+)
+
+// writeTestKeyPair writes a self-signed certificate and its key to dir and returns their paths.
+func writeTestKeyPair(t *testing.T, dir, name string) (certPath, keyPath string) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+
+	certPath = filepath.Join(dir, name+".crt")
+	keyPath = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+// TestConfig_Validate tests that all invalid fields are reported at once.
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	certPath, keyPath := writeTestKeyPair(t, dir, "server")
+	_, otherKeyPath := writeTestKeyPair(t, dir, "other")
+
+	require.NoError(t, Config{Host: "grpc.example.com", Port: 9000, TLSCertPath: certPath, TLSKeyPath: keyPath}.Validate())
+	require.NoError(t, Config{Host: "::1", Port: 9000, AdminPort: 9001}.Validate())
+	require.NoError(t, Config{}.Validate(), "the address is checked by Start")
+
+	err := Config{
+		Network:                 "udp",
+		Host:                    "bad host!",
+		Port:                    70000,
+		Compression:             "brotli",
+		MaxConcurrentRequests:   -1,
+		ConcurrencyRetryDelay:   -time.Second,
+		MethodConcurrencyLimits: map[string]int{"/svc.A/B": 0},
+		SkipMethods:             []string{"/grpc.health.v1.Health/*", "/svc.[/*"},
+		TLSCertPath:             certPath,
+		TLSKeyPath:              otherKeyPath,
+		OpenAPIExport:           true,
+		TrustedProxies:          []string{"10.0.0.0/8", "10.0.0.0/33"},
+	}.Validate()
+
+	var verr *env.ValidationError
+	require.ErrorAs(t, err, &verr)
+	var fields []string
+	for _, f := range verr.Fields {
+		fields = append(fields, f.Field)
+	}
+	assert.Equal(t, []string{
+		"GRPC_NETWORK",
+		"GRPC_PORT",
+		"GRPC_HOST",
+		"GRPC_OPENAPI_EXPORT",
+		"GRPC_TRUSTED_PROXIES",
+		"GRPC_TLS_KEY_PATH",
+		"GRPC_COMPRESSION",
+		"GRPC_MAX_CONCURRENT_REQUESTS",
+		"GRPC_CONCURRENCY_RETRY_DELAY",
+		"GRPC_METHOD_CONCURRENCY_LIMITS",
+		"GRPC_SKIP_METHODS",
+	}, fields)
+	assert.ErrorContains(t, err, "GRPC_PORT: port 70000 out of range [1, 65535]")
+	assert.ErrorContains(t, err, "certificate and key do not match")
+	assert.ErrorContains(t, err, `GRPC_TRUSTED_PROXIES: invalid trusted proxy "10.0.0.0/33"`)
+}
+
+// TestConfig_Validate_TLSFiles tests missing and incomplete TLS settings.
+func TestConfig_Validate_TLSFiles(t *testing.T) {
+	t.Parallel()
+	missing := filepath.Join(t.TempDir(), "missing.crt")
+
+	err := Config{TLSCertPath: missing, TLSKeyPath: missing}.Validate()
+	assert.EqualError(t, err, "GRPC_TLS_CERT_PATH: cannot read "+missing+": no such file or directory; "+
+		"GRPC_TLS_KEY_PATH: cannot read "+missing+": no such file or directory")
+
+	err = Config{TLSCertPath: missing}.Validate()
+	assert.EqualError(t, err, "GRPC_TLS_KEY_PATH: required when GRPC_TLS_CERT_PATH is set")
+}
+
+// TestServer_Start_InvalidConfig tests that a server with an invalid config refuses to start.
+func TestServer_Start_InvalidConfig(t *testing.T) {
+	t.Parallel()
+	s := New(Config{Port: 9140, AdminPort: 9140}, func(*grpc.Server) {})
+
+	err := s.Start()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid gRPC server config: GRPC_ADMIN_PORT: must differ from GRPC_PORT 9140")
+	require.NoError(t, s.Close())
+}
@@ -0,0 +1,37 @@
+// Package id выпускает упорядоченные по времени идентификаторы: UUIDv7, ULID и Snowflake.
+//
+// Все типы реализуют [ID] (String, Time), sql.Scanner и driver.Valuer (работают с db/pg/sqlx
+// и db/pg/pgx), encoding.TextMarshaler и TextUnmarshaler (JSON, envconfig):
+//   - [UUID] — UUID версии 7, колонка uuid; выбор по умолчанию
+//   - [ULID] — 26 символов Crockford's Base32, колонка text или uuid
+//   - [Snowflake] — int64 из времени, номера узла и счётчика, колонка bigint; в JSON — строка
+//
+// Генераторы ([Generator]) безопасны для конкурентного использования, идентификаторы одного
+// генератора строго возрастают.
+//
+// Использование:
+//
+//	requestID := id.NewUUID()
+//	messageID := id.NewULID()
+//
+//	gen, err := id.NewSnowflakeGenerator(nodeID)
+//	orderID := gen.NewSnowflake()
+//
+//	// тип идентификаторов из конфигурации
+//	var cfg id.Config
+//	if err := env.InitConfig(&cfg); err != nil {
+//	    return err
+//	}
+//	gen, err := id.NewGenerator(cfg)
+//	key := gen.New().String()
+//
+// Ключи объектного хранилища:
+//   - [ShardedKey] — prefix/<2 hex символа хэша>/<id>: распределяет последовательные идентификаторы
+//     по префиксам, чтобы запись не упиралась в один раздел S3
+//   - [DateKey] — prefix/yyyy/mm/dd/<id> по времени создания идентификатора
+//
+// Конфигурация через переменные окружения:
+//
+//	ID_KIND    — uuidv7, ulid или snowflake (default: uuidv7)
+//	ID_NODE_ID — номер узла Snowflake, 0-1023 (default: 0)
+package id
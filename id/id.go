@@ -0,0 +1,68 @@
+package id
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Kind — тип генерируемых идентификаторов
+type Kind string
+
+const (
+	KindUUIDv7    Kind = "uuidv7"
+	KindULID      Kind = "ulid"
+	KindSnowflake Kind = "snowflake"
+)
+
+// ID — упорядоченный по времени идентификатор: [UUID], [ULID] или [Snowflake].
+// Идентификаторы одного типа, выпущенные позже, сравниваются как большие и в текстовом виде.
+type ID interface {
+	// String возвращает текстовое представление идентификатора
+	String() string
+	// Time возвращает время создания идентификатора с точностью до миллисекунды
+	Time() time.Time
+}
+
+// Generator выпускает идентификаторы одного типа. Реализации безопасны для конкурентного использования.
+type Generator interface {
+	New() ID
+}
+
+// Config задаёт генератор для NewGenerator
+type Config struct {
+	Kind   Kind  `envconfig:"ID_KIND" default:"uuidv7"`
+	NodeID int64 `envconfig:"ID_NODE_ID" default:"0"` // номер узла для KindSnowflake, 0-1023
+}
+
+// NewGenerator создаёт генератор типа cfg.Kind
+func NewGenerator(cfg Config) (Generator, error) {
+	switch cfg.Kind {
+	case KindUUIDv7, "":
+		return UUIDGenerator{}, nil
+	case KindULID:
+		return NewULIDGenerator(), nil
+	case KindSnowflake:
+		return NewSnowflakeGenerator(cfg.NodeID)
+	default:
+		return nil, errors.Errorf("unknown id kind %q", cfg.Kind)
+	}
+}
+
+// ShardedKey возвращает ключ объекта prefix/<2 hex символа sha256 от id>/<id>.
+// Упорядоченные по времени идентификаторы с общим префиксом попадают в один раздел
+// объектного хранилища; хэш распределяет их по 256 префиксам.
+func ShardedKey(prefix string, id ID) string {
+	s := id.String()
+	sum := sha256.Sum256([]byte(s))
+	return path.Join(prefix, hex.EncodeToString(sum[:1]), s)
+}
+
+// DateKey возвращает ключ объекта prefix/yyyy/mm/dd/<id> по времени создания id (UTC).
+// Подходит для правил lifecycle и выборки объектов за период через List с префиксом.
+func DateKey(prefix string, id ID) string {
+	return path.Join(prefix, id.Time().UTC().Format("2006/01/02"), id.String())
+}
@@ -0,0 +1,170 @@
+package id
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerators_Ordered tests that every generator issues strictly increasing IDs under concurrency.
+func TestGenerators_Ordered(t *testing.T) {
+	t.Parallel()
+	for _, kind := range []Kind{KindUUIDv7, KindULID, KindSnowflake} {
+		t.Run(string(kind), func(t *testing.T) {
+			t.Parallel()
+			gen, err := NewGenerator(Config{Kind: kind, NodeID: 7})
+			require.NoError(t, err)
+
+			const workers, perWorker = 4, 2000
+			ids := make([][]ID, workers)
+			var wg sync.WaitGroup
+			for w := range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perWorker {
+						ids[w] = append(ids[w], gen.New())
+					}
+				}()
+			}
+			wg.Wait()
+
+			seen := make(map[string]struct{}, workers*perWorker)
+			for _, list := range ids {
+				for i, v := range list {
+					seen[v.String()] = struct{}{}
+					if i > 0 {
+						assert.Less(t, compareIDs(list[i-1], v), 0, "ids of one goroutine must increase")
+					}
+					assert.WithinDuration(t, time.Now(), v.Time(), time.Minute)
+				}
+			}
+			assert.Len(t, seen, workers*perWorker, "ids must be unique")
+		})
+	}
+}
+
+// compareIDs compares IDs of one kind in issue order
+func compareIDs(a, b ID) int {
+	if sa, ok := a.(Snowflake); ok {
+		return int(sa - b.(Snowflake))
+	}
+	return strings.Compare(a.String(), b.String())
+}
+
+// TestNewGenerator_Invalid tests configuration errors.
+func TestNewGenerator_Invalid(t *testing.T) {
+	t.Parallel()
+	_, err := NewGenerator(Config{Kind: "cuid"})
+	assert.EqualError(t, err, `unknown id kind "cuid"`)
+	_, err = NewGenerator(Config{Kind: KindSnowflake, NodeID: 1024})
+	assert.EqualError(t, err, "snowflake node 1024 out of range [0, 1023]")
+}
+
+// TestULID_Encoding tests ULID text encoding against the spec example.
+func TestULID_Encoding(t *testing.T) {
+	t.Parallel()
+	u, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	require.NoError(t, err)
+	assert.Equal(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV", u.String())
+	assert.Equal(t, int64(1469922850259), u.Time().UnixMilli())
+
+	lower, err := ParseULID("01arz3ndektsv4rrffq69g5fav")
+	require.NoError(t, err)
+	assert.Equal(t, u, lower)
+
+	max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	require.NoError(t, err)
+	assert.Equal(t, ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, max)
+
+	_, err = ParseULID("8ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	assert.ErrorContains(t, err, "overflows 128 bits")
+	_, err = ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAU")
+	assert.ErrorContains(t, err, `unexpected character 'U'`)
+	_, err = ParseULID("01ARZ3")
+	assert.ErrorContains(t, err, "expected 26 characters")
+}
+
+// TestULID_Scan tests scanning ULIDs from text, uuid and bytea columns.
+func TestULID_Scan(t *testing.T) {
+	t.Parallel()
+	u := NewULID()
+
+	var fromText, fromUUID, fromBytes, fromNull ULID
+	require.NoError(t, fromText.Scan(u.String()))
+	require.NoError(t, fromUUID.Scan([]byte(u.UUID().String())))
+	require.NoError(t, fromBytes.Scan(u[:]))
+	require.NoError(t, fromNull.Scan(nil))
+	assert.Equal(t, u, fromText)
+	assert.Equal(t, u, fromUUID)
+	assert.Equal(t, u, fromBytes)
+	assert.True(t, fromNull.IsZero())
+
+	v, err := u.Value()
+	require.NoError(t, err)
+	assert.Equal(t, u.String(), v)
+	assert.Error(t, fromText.Scan(42))
+}
+
+// TestUUID tests UUIDv7 time, database and JSON round trips.
+func TestUUID(t *testing.T) {
+	t.Parallel()
+	u := NewUUID()
+	assert.WithinDuration(t, time.Now(), u.Time(), time.Second)
+
+	v, err := u.Value()
+	require.NoError(t, err)
+	var scanned UUID
+	require.NoError(t, scanned.Scan(v))
+	assert.Equal(t, u, scanned)
+
+	v, err = UUID{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v4, err := ParseUUID("6ba7b810-9dad-41d1-80b4-00c04fd430c8")
+	require.NoError(t, err)
+	assert.True(t, v4.Time().IsZero(), "only v7 carries a timestamp")
+}
+
+// TestSnowflake tests Snowflake layout and encodings.
+func TestSnowflake(t *testing.T) {
+	t.Parallel()
+	gen, err := NewSnowflakeGenerator(MaxSnowflakeNode)
+	require.NoError(t, err)
+	s := gen.NewSnowflake()
+
+	assert.Equal(t, int64(MaxSnowflakeNode), s.Node())
+	assert.WithinDuration(t, time.Now(), s.Time(), time.Second)
+
+	data, err := json.Marshal(map[string]Snowflake{"id": s})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"`+s.String()+`"}`, string(data))
+
+	var scanned Snowflake
+	require.NoError(t, scanned.Scan(int64(s)))
+	assert.Equal(t, s, scanned)
+	require.NoError(t, scanned.Scan([]byte(s.String())))
+	assert.Equal(t, s, scanned)
+	_, err = ParseSnowflake("-1")
+	assert.Error(t, err)
+}
+
+// TestKeys tests storage key helpers.
+func TestKeys(t *testing.T) {
+	t.Parallel()
+	u, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	require.NoError(t, err)
+
+	assert.Equal(t, "reports/2016/07/30/01ARZ3NDEKTSV4RRFFQ69G5FAV", DateKey("reports", u))
+	assert.Equal(t, "2016/07/30/01ARZ3NDEKTSV4RRFFQ69G5FAV", DateKey("", u))
+
+	key := ShardedKey("uploads", u)
+	assert.Regexp(t, `^uploads/[0-9a-f]{2}/01ARZ3NDEKTSV4RRFFQ69G5FAV$`, key)
+	assert.Equal(t, key, ShardedKey("uploads", u), "shard is deterministic")
+}
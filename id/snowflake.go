@@ -0,0 +1,134 @@
+package id
+
+import (
+	"database/sql/driver"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Разметка Snowflake: 41 бит миллисекунд от SnowflakeEpoch, 10 бит узла, 12 бит счётчика
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+
+	// MaxSnowflakeNode — наибольший номер узла Snowflake
+	MaxSnowflakeNode = 1<<snowflakeNodeBits - 1
+
+	maxSnowflakeSequence = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeEpoch — начало отсчёта времени Snowflake; 41 бит миллисекунд хватает до 2089 года
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake — 63-битный идентификатор в стиле Twitter Snowflake: время, номер узла и счётчик
+// в пределах миллисекунды. Хранится в колонке bigint; в JSON передаётся строкой,
+// т.к. JavaScript не представляет целые числа больше 2^53 без потерь.
+type Snowflake int64
+
+// SnowflakeGenerator выпускает Snowflake для одного узла: до 4096 идентификаторов в миллисекунду.
+// Номера узлов должны быть уникальны среди процессов, выпускающих идентификаторы одного пространства.
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	lastMS   int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator создаёт генератор для узла node (0-MaxSnowflakeNode)
+func NewSnowflakeGenerator(node int64) (*SnowflakeGenerator, error) {
+	if node < 0 || node > MaxSnowflakeNode {
+		return nil, errors.Errorf("snowflake node %d out of range [0, %d]", node, MaxSnowflakeNode)
+	}
+	return &SnowflakeGenerator{node: node}, nil
+}
+
+// New реализует Generator
+func (g *SnowflakeGenerator) New() ID {
+	return g.NewSnowflake()
+}
+
+// NewSnowflake возвращает идентификатор больше всех ранее выпущенных генератором.
+// Если счётчик миллисекунды исчерпан или часы сдвинулись назад, ожидает следующую миллисекунду.
+func (g *SnowflakeGenerator) NewSnowflake() Snowflake {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for {
+		ms := time.Since(SnowflakeEpoch).Milliseconds()
+		switch {
+		case ms > g.lastMS:
+			g.lastMS = ms
+			g.sequence = 0
+		case g.sequence < maxSnowflakeSequence:
+			g.sequence++
+		default:
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		return Snowflake(g.lastMS<<(snowflakeNodeBits+snowflakeSequenceBits) | g.node<<snowflakeSequenceBits | g.sequence)
+	}
+}
+
+// ParseSnowflake разбирает десятичное представление Snowflake
+func ParseSnowflake(s string) (Snowflake, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return 0, errors.Errorf("invalid snowflake %q", s)
+	}
+	return Snowflake(v), nil
+}
+
+// String возвращает десятичное представление
+func (s Snowflake) String() string {
+	return strconv.FormatInt(int64(s), 10)
+}
+
+// Time возвращает время создания
+func (s Snowflake) Time() time.Time {
+	return SnowflakeEpoch.Add(time.Duration(int64(s)>>(snowflakeNodeBits+snowflakeSequenceBits)) * time.Millisecond)
+}
+
+// Node возвращает номер узла, выпустившего идентификатор
+func (s Snowflake) Node() int64 {
+	return int64(s) >> snowflakeSequenceBits & MaxSnowflakeNode
+}
+
+// MarshalText реализует encoding.TextMarshaler
+func (s Snowflake) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText реализует encoding.TextUnmarshaler
+func (s *Snowflake) UnmarshalText(data []byte) error {
+	parsed, err := ParseSnowflake(string(data))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer
+func (s Snowflake) Value() (driver.Value, error) {
+	return int64(s), nil
+}
+
+// Scan реализует sql.Scanner; NULL читается как 0
+func (s *Snowflake) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*s = 0
+	case int64:
+		*s = Snowflake(v)
+	case string:
+		return s.UnmarshalText([]byte(v))
+	case []byte:
+		return s.UnmarshalText(v)
+	default:
+		return errors.Errorf("failed to scan snowflake: unsupported source type %T", src)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package id
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// crockford — алфавит Crockford's Base32, используемый ULID
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLen — длина текстового представления ULID
+const ulidLen = 26
+
+// ULID — 128-битный идентификатор: 48 бит времени в миллисекундах и 80 случайных бит,
+// в тексте — 26 символов Crockford's Base32 (github.com/ulid/spec). Хранится в колонке text
+// или uuid: Scan принимает оба представления.
+type ULID [16]byte
+
+// ULIDGenerator выпускает монотонные ULID: в одну миллисекунду случайная часть увеличивается на 1,
+// поэтому идентификаторы процесса строго возрастают.
+type ULIDGenerator struct {
+	mu   sync.Mutex
+	last ULID
+}
+
+// NewULIDGenerator создаёт генератор монотонных ULID
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{}
+}
+
+// defaultULIDGenerator используется NewULID
+var defaultULIDGenerator = NewULIDGenerator()
+
+// NewULID возвращает новый монотонный ULID. Паникует, если не удалось прочитать случайные байты.
+func NewULID() ULID {
+	return defaultULIDGenerator.NewULID()
+}
+
+// New реализует Generator
+func (g *ULIDGenerator) New() ID {
+	return g.NewULID()
+}
+
+// NewULID возвращает ULID больше всех ранее выпущенных генератором.
+// При переполнении случайной части ожидает следующую миллисекунду.
+func (g *ULIDGenerator) NewULID() ULID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for {
+		ms := uint64(time.Now().UnixMilli())
+		lastMS := g.last.milliseconds()
+		if ms > lastMS {
+			var u ULID
+			u.setMilliseconds(ms)
+			if _, err := rand.Read(u[6:]); err != nil {
+				panic(errors.Wrap(err, "failed to read random bytes for ulid"))
+			}
+			g.last = u
+			return u
+		}
+		// та же миллисекунда или часы сдвинулись назад: увеличиваем случайную часть последнего ULID
+		if next, ok := g.last.increment(); ok {
+			g.last = next
+			return next
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// ParseULID разбирает ULID из 26 символов Crockford's Base32 без учёта регистра
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != ulidLen {
+		return u, errors.Errorf("invalid ulid %q: expected %d characters", s, ulidLen)
+	}
+	if s[0] > '7' {
+		return u, errors.Errorf("invalid ulid %q: value overflows 128 bits", s)
+	}
+
+	upper := strings.ToUpper(s)
+	// 26 символов по 5 бит = 130 бит, первые 2 бита — нулевое выравнивание
+	for i := range ulidLen {
+		v := strings.IndexByte(crockford, upper[i])
+		if v < 0 {
+			return ULID{}, errors.Errorf("invalid ulid %q: unexpected character %q", s, s[i])
+		}
+		for b := range 5 {
+			bit := i*5 + b - 2
+			if bit >= 0 && v&(1<<(4-b)) != 0 {
+				u[bit/8] |= 1 << (7 - bit%8)
+			}
+		}
+	}
+	return u, nil
+}
+
+// String возвращает ULID из 26 символов Crockford's Base32 в верхнем регистре
+func (u ULID) String() string {
+	var buf [ulidLen]byte
+	for i := range ulidLen {
+		v := 0
+		for b := range 5 {
+			v <<= 1
+			if bit := i*5 + b - 2; bit >= 0 && u[bit/8]&(1<<(7-bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		buf[i] = crockford[v]
+	}
+	return string(buf[:])
+}
+
+// Time возвращает время создания ULID
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(int64(u.milliseconds()))
+}
+
+// IsZero сообщает, что ULID не задан
+func (u ULID) IsZero() bool {
+	return u == ULID{}
+}
+
+// MarshalText реализует encoding.TextMarshaler
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText реализует encoding.TextUnmarshaler
+func (u *ULID) UnmarshalText(data []byte) error {
+	parsed, err := ParseULID(string(data))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer: ULID записывается текстом, нулевой ULID — как NULL.
+// Для колонки uuid передавайте u.UUID().
+func (u ULID) Value() (driver.Value, error) {
+	if u.IsZero() {
+		return nil, nil
+	}
+	return u.String(), nil
+}
+
+// Scan реализует sql.Scanner: принимает ULID из колонки text, UUID из колонки uuid
+// и 16 байт из колонки bytea; NULL читается как нулевой ULID
+func (u *ULID) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*u = ULID{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		if len(v) == len(u) {
+			copy(u[:], v)
+			return nil
+		}
+		s = string(v)
+	default:
+		return errors.Errorf("failed to scan ulid: unsupported source type %T", src)
+	}
+
+	if len(s) == ulidLen {
+		parsed, err := ParseULID(s)
+		if err != nil {
+			return errors.Wrap(err, "failed to scan ulid")
+		}
+		*u = parsed
+		return nil
+	}
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return errors.Wrapf(err, "failed to scan ulid %q", s)
+	}
+	*u = ULID(parsed)
+	return nil
+}
+
+// UUID возвращает те же 128 бит в виде UUID для хранения в колонке uuid
+func (u ULID) UUID() uuid.UUID {
+	return uuid.UUID(u)
+}
+
+func (u ULID) milliseconds() uint64 {
+	var buf [8]byte
+	copy(buf[2:], u[:6])
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+func (u *ULID) setMilliseconds(ms uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ms)
+	copy(u[:6], buf[2:])
+}
+
+// increment увеличивает случайную часть на 1; ok == false при переполнении
+func (u ULID) increment() (ULID, bool) {
+	for i := len(u) - 1; i >= 6; i-- {
+		u[i]++
+		if u[i] != 0 {
+			return u, true
+		}
+	}
+	return u, false
+}
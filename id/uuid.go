@@ -0,0 +1,88 @@
+package id
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UUID — UUID версии 7 (RFC 9562): 48 бит времени в миллисекундах и 74 случайных бита.
+// Хранится в колонке uuid; в отличие от UUIDv4 вставки идут в конец индекса.
+type UUID uuid.UUID
+
+// UUIDGenerator выпускает UUIDv7. В пределах процесса идентификаторы строго возрастают.
+type UUIDGenerator struct{}
+
+// New реализует Generator
+func (UUIDGenerator) New() ID {
+	return NewUUID()
+}
+
+// NewUUID возвращает новый UUIDv7. Паникует, если не удалось прочитать случайные байты.
+func NewUUID() UUID {
+	return UUID(uuid.Must(uuid.NewV7()))
+}
+
+// ParseUUID разбирает UUID в любом формате, принимаемом uuid.Parse
+func ParseUUID(s string) (UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return UUID{}, errors.Wrapf(err, "invalid uuid %q", s)
+	}
+	return UUID(u), nil
+}
+
+// String возвращает UUID в виде xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func (u UUID) String() string {
+	return uuid.UUID(u).String()
+}
+
+// Time возвращает время создания UUIDv7; для других версий — нулевое время
+func (u UUID) Time() time.Time {
+	if uuid.UUID(u).Version() != 7 {
+		return time.Time{}
+	}
+	ms := binary.BigEndian.Uint64(u[:8]) >> 16
+	return time.UnixMilli(int64(ms))
+}
+
+// IsZero сообщает, что UUID не задан
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+// MarshalText реализует encoding.TextMarshaler
+func (u UUID) MarshalText() ([]byte, error) {
+	return uuid.UUID(u).MarshalText()
+}
+
+// UnmarshalText реализует encoding.TextUnmarshaler
+func (u *UUID) UnmarshalText(data []byte) error {
+	parsed, err := ParseUUID(string(data))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer; нулевой UUID записывается как NULL
+func (u UUID) Value() (driver.Value, error) {
+	if u.IsZero() {
+		return nil, nil
+	}
+	return u.String(), nil
+}
+
+// Scan реализует sql.Scanner; NULL читается как нулевой UUID
+func (u *UUID) Scan(src any) error {
+	var parsed uuid.UUID
+	if err := parsed.Scan(src); err != nil {
+		return errors.Wrap(err, "failed to scan uuid")
+	}
+	*u = UUID(parsed)
+	return nil
+}
@@ -3,8 +3,13 @@ package kv
 import (
 	"context"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
+// ErrKeyNotFound возвращается реализациями Get/HGet/LPop/RPop, когда ключ не найден
+var ErrKeyNotFound = errors.New("key not found")
+
 // Store определяет интерфейс key-value хранилища
 type Store interface {
 	// Базовые операции
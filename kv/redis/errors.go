@@ -2,10 +2,12 @@ package redis
 
 import (
 	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/kv"
 )
 
-// ErrKeyNotFound возвращается когда ключ не найден в Redis
-var ErrKeyNotFound = errors.New("key not found")
+// ErrKeyNotFound возвращается когда ключ не найден в Redis; совпадает с kv.ErrKeyNotFound
+var ErrKeyNotFound = kv.ErrKeyNotFound
 
 // ErrTypeMismatch возвращается когда тип значения не соответствует ожидаемому
 var ErrTypeMismatch = errors.New("type mismatch")
@@ -1,6 +1,7 @@
 package devslog
 
 import (
+	"io"
 	"log/slog"
 	"os"
 
@@ -8,6 +9,11 @@ import (
 )
 
 func NewDefault(level slog.Level) *slog.Logger {
+	return New(os.Stdout, level)
+}
+
+// New creates a human-readable logger writing to w.
+func New(w io.Writer, level slog.Level) *slog.Logger {
 	opts := &devslog.Options{
 		HandlerOptions: &slog.HandlerOptions{
 			AddSource: true,
@@ -22,5 +28,5 @@ func NewDefault(level slog.Level) *slog.Logger {
 		StringerFormatter:  true,
 	}
 
-	return slog.New(devslog.NewHandler(os.Stdout, opts))
+	return slog.New(devslog.NewHandler(w, opts))
 }
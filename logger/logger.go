@@ -2,18 +2,24 @@ package logger
 
 import (
 	"context"
+	"io"
 	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 
 	"github.com/pure-golang/adapters/logger/devslog"
 	"github.com/pure-golang/adapters/logger/noop"
+	"github.com/pure-golang/adapters/logger/rotate"
 	"github.com/pure-golang/adapters/logger/stdjson"
 )
 
 type Level string
 type Provider string
+type Output string
 type contextKeyT string
 
 var contextKey = contextKeyT("github.com/pure-golang/adapters/logger")
@@ -27,25 +33,149 @@ const (
 	ProviderDevSlog Provider = "dev"      // for dev
 	ProviderStdJson Provider = "std_json" // for production
 	ProviderNoop    Provider = "noop"     // for unit tests
+
+	OutputStdout Output = "stdout"
+	OutputStderr Output = "stderr"
+	OutputFile   Output = "file" // rotated file, see Config.File* fields
 )
 
 type Config struct {
 	Provider Provider `envconfig:"LOG_PROVIDER" default:"std_json"`
 	Level    Level    `envconfig:"LOG_LEVEL" default:"info"`
+	Output   Output   `envconfig:"LOG_OUTPUT" default:"stdout"`
+
+	// File output settings, used when Output is "file".
+	FilePath        string        `envconfig:"LOG_FILE_PATH"`
+	FileMaxSizeMB   int           `envconfig:"LOG_FILE_MAX_SIZE_MB" default:"100"` // 0 disables size-based rotation
+	FileRotateEvery time.Duration `envconfig:"LOG_FILE_ROTATE_EVERY" default:"0"`  // 0 disables time-based rotation
+	FileMaxBackups  int           `envconfig:"LOG_FILE_MAX_BACKUPS" default:"7"`   // 0 keeps all backups
+	FileMaxAge      time.Duration `envconfig:"LOG_FILE_MAX_AGE" default:"0"`       // 0 keeps backups of any age
+	FileCompress    bool          `envconfig:"LOG_FILE_COMPRESS" default:"true"`
+
+	// Outputs fans records out to several destinations, overriding Provider, Level and Output.
+	// Each entry is "output[:provider[:level]]", missing parts default to Provider and Level,
+	// e.g. LOG_OUTPUTS=stdout:dev:debug,file:std_json:warn. A file output uses the File* fields
+	// and may be listed once: two writers of one file would rotate and remove each other's backups.
+	Outputs []string `envconfig:"LOG_OUTPUTS"`
+}
+
+// OutputConfig is a log destination with its own format and level filter.
+type OutputConfig struct {
+	Output   Output
+	Provider Provider
+	Level    Level
+}
+
+// ParseOutputs parses Config.Outputs entries of the form "output[:provider[:level]]".
+// The file output may be listed only once.
+func (c Config) ParseOutputs() ([]OutputConfig, error) {
+	outputs := make([]OutputConfig, 0, len(c.Outputs))
+	hasFile := false
+	for _, entry := range c.Outputs {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) > 3 || parts[0] == "" {
+			return nil, errors.Errorf("invalid log output %q, expected output[:provider[:level]]", entry)
+		}
+		if Output(parts[0]) == OutputFile {
+			if hasFile {
+				return nil, errors.Errorf("duplicate log output %q, file output may be listed once", entry)
+			}
+			hasFile = true
+		}
+		o := OutputConfig{Output: Output(parts[0]), Provider: c.Provider, Level: c.Level}
+		if len(parts) > 1 && parts[1] != "" {
+			o.Provider = Provider(parts[1])
+		}
+		if len(parts) > 2 && parts[2] != "" {
+			o.Level = Level(parts[2])
+		}
+		outputs = append(outputs, o)
+	}
+	return outputs, nil
 }
 
 // NewDefault creates a new instance of slog.Logger by default using Config.
+// If the configured output cannot be opened, the logger writes to stdout and reports the error.
 func NewDefault(c Config) *slog.Logger {
 	level := convertLevel(c.Level)
-	switch c.Provider {
-	case ProviderDevSlog:
-		return devslog.NewDefault(level)
-	case ProviderNoop:
+	if c.Provider == ProviderNoop {
 		return noop.NewNoop()
+	}
+	if len(c.Outputs) > 0 {
+		return newMultiLogger(c)
+	}
+
+	w, err := openOutput(c)
+	if err != nil {
+		l := newLogger(c.Provider, os.Stdout, level)
+		l.Error("failed to open log output, falling back to stdout", "output", c.Output, "error", err.Error())
+		return l
+	}
+
+	return newLogger(c.Provider, w, level)
+}
+
+// newMultiLogger creates a logger writing to every output of c.Outputs.
+// Outputs that cannot be opened are skipped and reported; if none can, the logger writes to stdout.
+func newMultiLogger(c Config) *slog.Logger {
+	outputs, err := c.ParseOutputs()
+	if err != nil {
+		l := newLogger(c.Provider, os.Stdout, convertLevel(c.Level))
+		l.Error("invalid log outputs, falling back to stdout", "error", err.Error())
+		return l
+	}
+
+	var handlers []slog.Handler
+	var failed []error
+	for _, o := range outputs {
+		oc := c
+		oc.Output = o.Output
+		w, err := openOutput(oc)
+		if err != nil {
+			failed = append(failed, errors.Wrapf(err, "output %s", o.Output))
+			continue
+		}
+		handlers = append(handlers, newLogger(o.Provider, w, convertLevel(o.Level)).Handler())
+	}
+	if len(handlers) == 0 {
+		handlers = append(handlers, newLogger(c.Provider, os.Stdout, convertLevel(c.Level)).Handler())
+	}
+
+	l := slog.New(NewMultiHandler(handlers...))
+	for _, err := range failed {
+		l.Error("failed to open log output, skipping it", "error", err.Error())
+	}
+	return l
+}
+
+func newLogger(provider Provider, w io.Writer, level slog.Level) *slog.Logger {
+	switch provider {
+	case ProviderDevSlog:
+		return devslog.New(w, level)
 	case ProviderStdJson:
 		fallthrough
 	default:
-		return stdjson.NewDefault(level)
+		return stdjson.New(w, level)
+	}
+}
+
+func openOutput(c Config) (io.Writer, error) {
+	switch c.Output {
+	case OutputStderr:
+		return os.Stderr, nil
+	case OutputFile:
+		return rotate.New(rotate.Config{
+			Filename:    c.FilePath,
+			MaxSizeMB:   c.FileMaxSizeMB,
+			RotateEvery: c.FileRotateEvery,
+			MaxBackups:  c.FileMaxBackups,
+			MaxAge:      c.FileMaxAge,
+			Compress:    c.FileCompress,
+		})
+	case OutputStdout:
+		fallthrough
+	default:
+		return os.Stdout, nil
 	}
 }
 
@@ -2,11 +2,15 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/pure-golang/adapters/logger/noop"
 )
@@ -78,6 +82,41 @@ func TestNewDefault_ProviderEmpty(t *testing.T) {
 	assert.IsType(t, &slog.Logger{}, l)
 }
 
+func TestNewDefault_OutputFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "app.log")
+	c := Config{
+		Provider: ProviderStdJson,
+		Level:    INFO,
+		Output:   OutputFile,
+		FilePath: path,
+	}
+
+	l := NewDefault(c)
+	l.Info("written to file", "key", "value")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "written to file", entry["msg"])
+	assert.Equal(t, "value", entry["key"])
+}
+
+func TestNewDefault_OutputFileWithoutPath(t *testing.T) {
+	t.Parallel()
+	// Missing file path should fall back to stdout instead of failing
+	c := Config{
+		Provider: ProviderStdJson,
+		Level:    INFO,
+		Output:   OutputFile,
+	}
+
+	l := NewDefault(c)
+
+	assert.NotNil(t, l)
+}
+
 func TestInitDefault_SetsGlobalLogger(t *testing.T) {
 	// Save original default handler to restore later
 	original := slog.Default()
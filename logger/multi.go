@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MultiHandler is a slog.Handler that fans records out to several handlers,
+// e.g. console text, a JSON file and an OTLP bridge.
+// Each handler filters records by its own level. A handler that fails or panics
+// does not prevent the others from receiving the record.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+var _ slog.Handler = (*MultiHandler)(nil)
+
+// NewMultiHandler creates a MultiHandler writing to handlers. Nil handlers are skipped.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	hs := make([]slog.Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+	return &MultiHandler{handlers: hs}
+}
+
+// Enabled reports whether any handler accepts records of level.
+func (m *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a copy of r to every handler that accepts its level.
+// Errors of all handlers are combined into one.
+func (m *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []string
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handleSafely(ctx, h, r.Clone()); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("failed to handle log record: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// WithAttrs returns a MultiHandler whose handlers have attrs added.
+func (m *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		hs[i] = h.WithAttrs(attrs)
+	}
+	return &MultiHandler{handlers: hs}
+}
+
+// WithGroup returns a MultiHandler whose handlers have the group opened.
+func (m *MultiHandler) WithGroup(name string) slog.Handler {
+	hs := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		hs[i] = h.WithGroup(name)
+	}
+	return &MultiHandler{handlers: hs}
+}
+
+// handleSafely calls h.Handle and converts a panic into an error.
+func handleSafely(ctx context.Context, h slog.Handler, r slog.Record) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("log handler panicked: %v", p)
+		}
+	}()
+	return h.Handle(ctx, r)
+}
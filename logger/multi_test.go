@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingHandler struct {
+	slog.Handler
+	panics bool
+}
+
+func (h failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h failingHandler) Handle(context.Context, slog.Record) error {
+	if h.panics {
+		panic("broken handler")
+	}
+	return errors.New("disk full")
+}
+
+func TestMultiHandler_LevelPerHandler(t *testing.T) {
+	t.Parallel()
+	var debug, warn bytes.Buffer
+	l := slog.New(NewMultiHandler(
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewJSONHandler(&warn, &slog.HandlerOptions{Level: slog.LevelWarn}),
+		nil,
+	))
+
+	l.Debug("debug message")
+	l.Warn("warn message")
+
+	assert.Contains(t, debug.String(), "debug message")
+	assert.Contains(t, debug.String(), "warn message")
+	assert.NotContains(t, warn.String(), "debug message")
+	assert.Contains(t, warn.String(), `"msg":"warn message"`)
+}
+
+func TestMultiHandler_Enabled(t *testing.T) {
+	t.Parallel()
+	h := NewMultiHandler(
+		slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}),
+		slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelError}),
+	)
+
+	assert.False(t, h.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelWarn))
+	assert.False(t, NewMultiHandler().Enabled(context.Background(), slog.LevelError))
+}
+
+func TestMultiHandler_FailureIsolation(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	h := NewMultiHandler(
+		failingHandler{},
+		failingHandler{panics: true},
+		slog.NewTextHandler(&buf, nil),
+	)
+
+	err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "still written", 0))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.Contains(t, err.Error(), "log handler panicked: broken handler")
+	assert.Contains(t, buf.String(), "still written")
+}
+
+func TestMultiHandler_WithAttrsAndGroup(t *testing.T) {
+	t.Parallel()
+	var first, second bytes.Buffer
+	l := slog.New(NewMultiHandler(slog.NewJSONHandler(&first, nil), slog.NewJSONHandler(&second, nil)))
+
+	l.With("service", "billing").WithGroup("request").Info("handled", "id", 42)
+
+	for _, buf := range []*bytes.Buffer{&first, &second} {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "billing", entry["service"])
+		assert.Equal(t, map[string]any{"id": float64(42)}, entry["request"])
+	}
+}
+
+func TestConfig_ParseOutputs(t *testing.T) {
+	t.Parallel()
+	c := Config{Provider: ProviderStdJson, Level: INFO, Outputs: []string{"stdout:dev:debug", "file", " stderr::error"}}
+
+	outputs, err := c.ParseOutputs()
+
+	require.NoError(t, err)
+	assert.Equal(t, []OutputConfig{
+		{Output: OutputStdout, Provider: ProviderDevSlog, Level: DEBUG},
+		{Output: OutputFile, Provider: ProviderStdJson, Level: INFO},
+		{Output: OutputStderr, Provider: ProviderStdJson, Level: ERROR},
+	}, outputs)
+
+	_, err = Config{Outputs: []string{"stdout:dev:debug:extra"}}.ParseOutputs()
+	assert.Error(t, err)
+	_, err = Config{Outputs: []string{":dev"}}.ParseOutputs()
+	assert.Error(t, err)
+	_, err = Config{Outputs: []string{"file:std_json:info", "stdout", "file:dev:debug"}}.ParseOutputs()
+	assert.ErrorContains(t, err, "duplicate log output")
+}
+
+func TestNewDefault_Outputs(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "app.log")
+	c := Config{
+		Provider: ProviderStdJson,
+		Level:    INFO,
+		Outputs:  []string{"stderr:dev:error", "file:std_json:warn"},
+		FilePath: path,
+	}
+
+	l := NewDefault(c)
+	l.Info("skipped by both outputs")
+	l.Warn("written to file")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "written to file")
+}
+
+func TestNewDefault_OutputsInvalid(t *testing.T) {
+	t.Parallel()
+	// Invalid outputs should fall back to stdout instead of failing
+	l := NewDefault(Config{Provider: ProviderStdJson, Level: INFO, Outputs: []string{"a:b:c:d"}})
+
+	assert.NotNil(t, l)
+}
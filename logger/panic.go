@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EventKind is the kind of a panic or fatal event.
+type EventKind string
+
+const (
+	EventPanic EventKind = "panic" // a recovered panic, see HandlePanic
+	EventFatal EventKind = "fatal" // a fatal error terminating the process, see Fatal
+)
+
+// fatalHookTimeout bounds hooks and notifiers run by Fatal, so that a stuck exporter does not keep
+// the process alive.
+const fatalHookTimeout = 5 * time.Second
+
+// Event describes a recovered panic or a fatal error.
+type Event struct {
+	Kind    EventKind
+	Message string   // log message
+	Value   any      // recovered value for panics, the error for fatal events
+	Stack   []string // stack trace lines of the goroutine that reported the event
+	Attrs   []any    // attributes passed by the caller, e.g. the gRPC method
+	Time    time.Time
+}
+
+// Err returns Value as an error.
+func (e *Event) Err() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", e.Value)
+}
+
+// Hook is called for every panic or fatal event, e.g. to flush telemetry exporters.
+type Hook func(ctx context.Context, e *Event)
+
+// Notifier forwards panics and fatal errors to an external sink such as Sentry.
+type Notifier interface {
+	Notify(ctx context.Context, e *Event) error
+}
+
+// NotifierFunc adapts a function to Notifier.
+type NotifierFunc func(ctx context.Context, e *Event) error
+
+// Notify implements Notifier.
+func (f NotifierFunc) Notify(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// registry holds the registered hooks and notifiers.
+type registry struct {
+	mu        sync.RWMutex
+	nextID    int
+	panic     map[int]Hook
+	fatal     map[int]Hook
+	notifiers map[int]Notifier
+}
+
+var hooks = &registry{
+	panic:     make(map[int]Hook),
+	fatal:     make(map[int]Hook),
+	notifiers: make(map[int]Notifier),
+}
+
+// exit terminates the process after a fatal event; replaced in tests.
+var exit = os.Exit
+
+// OnPanic registers a hook called by HandlePanic and returns a function removing it.
+func OnPanic(hook Hook) (remove func()) {
+	return hooks.add(hooks.panic, hook)
+}
+
+// OnFatal registers a hook called by Fatal before the process exits and returns a function removing it.
+// Use it to flush exporters: the process exits right after the hooks return.
+func OnFatal(hook Hook) (remove func()) {
+	return hooks.add(hooks.fatal, hook)
+}
+
+// AddNotifier registers a notifier receiving every panic and fatal event and returns a function removing it.
+func AddNotifier(n Notifier) (remove func()) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	id := hooks.nextID
+	hooks.nextID++
+	hooks.notifiers[id] = n
+	return func() {
+		hooks.mu.Lock()
+		defer hooks.mu.Unlock()
+		delete(hooks.notifiers, id)
+	}
+}
+
+func (r *registry) add(m map[int]Hook, hook Hook) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextID
+	r.nextID++
+	m[id] = hook
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(m, id)
+	}
+}
+
+// snapshot returns the hooks of kind and the notifiers ordered by registration.
+func (r *registry) snapshot(kind EventKind) ([]Hook, []Notifier) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m := r.panic
+	if kind == EventFatal {
+		m = r.fatal
+	}
+	var result []Hook
+	var notifiers []Notifier
+	for id := range r.nextID {
+		if hook, ok := m[id]; ok {
+			result = append(result, hook)
+		}
+		if n, ok := r.notifiers[id]; ok {
+			notifiers = append(notifiers, n)
+		}
+	}
+	return result, notifiers
+}
+
+// HandlePanic reports a recovered panic: logs it on ERROR level with the stack trace using l
+// (FromContext(ctx) if nil), runs OnPanic hooks and notifiers. Call it from a deferred recover:
+//
+//	defer func() {
+//	    if r := recover(); r != nil {
+//	        logger.HandlePanic(ctx, nil, r, "panic recovered in worker", "job", job.ID)
+//	    }
+//	}()
+func HandlePanic(ctx context.Context, l *slog.Logger, recovered any, msg string, attrs ...any) {
+	e := newEvent(EventPanic, msg, recovered, attrs)
+	if l == nil {
+		l = FromContext(ctx)
+	}
+	l.ErrorContext(ctx, msg, append([]any{slog.Any("panic", recovered), slog.Any("stack", e.Stack)}, attrs...)...)
+	dispatch(ctx, e)
+}
+
+// Fatal reports a fatal error and terminates the process with exit code 1: logs err on ERROR level
+// with the stack trace (the one carried by err, if any), runs OnFatal hooks and notifiers
+// for at most 5 seconds, then exits.
+// Unlike log.Fatal, it lets hooks flush buffered telemetry before the process exits.
+func Fatal(ctx context.Context, msg string, err error, attrs ...any) {
+	e := newEvent(EventFatal, msg, err, attrs)
+	l := FromContext(ctx)
+	var stackTracer interface {
+		StackTrace() errors.StackTrace
+	}
+	if !errors.As(err, &stackTracer) {
+		l = l.With("stack", e.Stack)
+	}
+	if err != nil {
+		l = appendErr(l, err)
+	}
+	l.ErrorContext(ctx, msg, attrs...)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fatalHookTimeout)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatch(ctx, e)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		FromContext(ctx).Error("fatal hooks timed out")
+	}
+	cancel()
+	exit(1)
+}
+
+func newEvent(kind EventKind, msg string, value any, attrs []any) *Event {
+	return &Event{
+		Kind:    kind,
+		Message: msg,
+		Value:   value,
+		Stack:   stackLines(),
+		Attrs:   attrs,
+		Time:    time.Now(),
+	}
+}
+
+// dispatch runs hooks and notifiers of e; a panicking hook or a notifier error is logged and skipped.
+func dispatch(ctx context.Context, e *Event) {
+	hookList, notifiers := hooks.snapshot(e.Kind)
+	for _, hook := range hookList {
+		safeCall(ctx, func() error {
+			hook(ctx, e)
+			return nil
+		})
+	}
+	for _, n := range notifiers {
+		safeCall(ctx, func() error {
+			return errors.Wrap(n.Notify(ctx, e), "failed to notify")
+		})
+	}
+}
+
+func safeCall(ctx context.Context, fn func() error) {
+	defer func() {
+		if p := recover(); p != nil {
+			FromContext(ctx).Error("panic hook panicked", "panic", p)
+		}
+	}()
+	if err := fn(); err != nil {
+		FromContext(ctx).Error(err.Error())
+	}
+}
+
+// stackLines returns the current goroutine stack trace as non-empty lines without tabs.
+func stackLines() []string {
+	var stack []string
+	for line := range strings.SplitSeq(strings.ReplaceAll(string(debug.Stack()), "\t", ""), "\n") {
+		if line != "" {
+			stack = append(stack, line)
+		}
+	}
+	return stack
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlePanic_LogsStackAndRunsHooks(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	var got *Event
+	removeHook := OnPanic(func(_ context.Context, e *Event) { got = e })
+	defer removeHook()
+	var notified []EventKind
+	removeNotifier := AddNotifier(NotifierFunc(func(_ context.Context, e *Event) error {
+		notified = append(notified, e.Kind)
+		return nil
+	}))
+	defer removeNotifier()
+
+	HandlePanic(context.Background(), l, "boom", "panic recovered", "job", "42")
+
+	require.NotNil(t, got)
+	assert.Equal(t, EventPanic, got.Kind)
+	assert.Equal(t, "boom", got.Value)
+	assert.Equal(t, "panic recovered", got.Message)
+	assert.Equal(t, []any{"job", "42"}, got.Attrs)
+	assert.NotEmpty(t, got.Stack)
+	assert.EqualError(t, got.Err(), "boom")
+	assert.Equal(t, []EventKind{EventPanic}, notified)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "panic recovered", record["msg"])
+	assert.Equal(t, "boom", record["panic"])
+	assert.Equal(t, "42", record["job"])
+	assert.NotEmpty(t, record["stack"])
+}
+
+func TestHandlePanic_RemovedHookNotCalled(t *testing.T) {
+	calls := 0
+	remove := OnPanic(func(context.Context, *Event) { calls++ })
+	remove()
+
+	HandlePanic(context.Background(), slog.New(slog.DiscardHandler), "boom", "panic recovered")
+
+	assert.Zero(t, calls)
+}
+
+func TestHandlePanic_HookFailuresIsolated(t *testing.T) {
+	var order []string
+	defer OnPanic(func(context.Context, *Event) {
+		order = append(order, "first")
+		panic("hook panic")
+	})()
+	defer AddNotifier(NotifierFunc(func(context.Context, *Event) error {
+		order = append(order, "failing notifier")
+		return errors.New("sink unavailable")
+	}))()
+	defer OnPanic(func(context.Context, *Event) { order = append(order, "second") })()
+	defer AddNotifier(NotifierFunc(func(context.Context, *Event) error {
+		order = append(order, "notifier")
+		return nil
+	}))()
+
+	assert.NotPanics(t, func() {
+		HandlePanic(context.Background(), slog.New(slog.DiscardHandler), "boom", "panic recovered")
+	})
+	assert.Equal(t, []string{"first", "second", "failing notifier", "notifier"}, order)
+}
+
+func TestHandlePanic_NilLoggerUsesContext(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := NewContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	HandlePanic(ctx, nil, "boom", "panic recovered")
+
+	assert.Contains(t, buf.String(), "panic recovered")
+}
+
+func TestFatal_RunsFatalHooksAndExits(t *testing.T) {
+	code := -1
+	prevExit := exit
+	exit = func(c int) { code = c }
+	defer func() { exit = prevExit }()
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithCancel(NewContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil))))
+	cancel()
+
+	var kinds []EventKind
+	var hookCtxErr error
+	defer OnFatal(func(ctx context.Context, e *Event) {
+		kinds = append(kinds, e.Kind)
+		hookCtxErr = ctx.Err()
+	})()
+	panicCalls := 0
+	defer OnPanic(func(context.Context, *Event) { panicCalls++ })()
+	defer AddNotifier(NotifierFunc(func(_ context.Context, e *Event) error {
+		kinds = append(kinds, e.Kind)
+		return nil
+	}))()
+
+	Fatal(ctx, "failed to start", stderrors.New("port in use"), "port", 8080)
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, []EventKind{EventFatal, EventFatal}, kinds)
+	assert.NoError(t, hookCtxErr, "hooks must not inherit caller cancellation")
+	assert.Zero(t, panicCalls)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "failed to start", record["msg"])
+	assert.Equal(t, "port in use", record["error"])
+	assert.NotEmpty(t, record["stack"])
+}
@@ -0,0 +1,340 @@
+package rotate
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	megabyte         = 1024 * 1024
+)
+
+// Config contains rotation settings.
+type Config struct {
+	Filename    string        // Path to the active log file
+	MaxSizeMB   int           // Rotate when the file exceeds this size; 0 disables size-based rotation
+	RotateEvery time.Duration // Rotate when the file is older than this; 0 disables time-based rotation
+	MaxBackups  int           // Number of rotated files to keep; 0 keeps all
+	MaxAge      time.Duration // Remove rotated files older than this; 0 keeps all
+	Compress    bool          // Gzip rotated files
+}
+
+// Writer is an io.WriteCloser that writes to Config.Filename and rotates it by size and age.
+//
+// Rotated files are renamed to "<name>-<timestamp><ext>" next to the active file.
+// Compression and removal of old backups run in a background goroutine.
+type Writer struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	millCh   chan struct{}
+	millDone chan struct{}
+	closed   bool
+}
+
+// New opens (or creates) Config.Filename for appending.
+func New(cfg Config) (*Writer, error) {
+	return newWriter(cfg, time.Now)
+}
+
+func newWriter(cfg Config, now func() time.Time) (*Writer, error) {
+	if cfg.Filename == "" {
+		return nil, errors.New("log filename is empty")
+	}
+
+	w := &Writer{
+		cfg:      cfg,
+		now:      now,
+		millCh:   make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	go w.millLoop()
+
+	return w, nil
+}
+
+// Write writes p to the active file, rotating it first if a limit is reached.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errors.New("log writer is closed")
+	}
+
+	// A failed rotation keeps the active file open: the entry is still written to it
+	// and rotation is retried on the next write
+	var rotateErr error
+	if w.shouldRotate(int64(len(p))) {
+		rotateErr = w.rotate()
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if err != nil {
+		return n, errors.Wrap(err, "failed to write log file")
+	}
+	return n, rotateErr
+}
+
+// Rotate renames the active file to a backup, opens a new one and closes the previous one.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return errors.New("log writer is closed")
+	}
+	return w.rotate()
+}
+
+// Close closes the active file and waits for pending compression and cleanup.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	close(w.millCh)
+	w.mu.Unlock()
+
+	<-w.millDone
+
+	if err != nil {
+		return errors.Wrap(err, "failed to close log file")
+	}
+	return nil
+}
+
+func (w *Writer) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.cfg.MaxSizeMB > 0 && w.size+n > int64(w.cfg.MaxSizeMB)*megabyte {
+		return true
+	}
+	return w.cfg.RotateEvery > 0 && w.now().Sub(w.openedAt) >= w.cfg.RotateEvery
+}
+
+func (w *Writer) open() error {
+	f, size, err := w.openFile()
+	if err != nil {
+		return err
+	}
+	w.file = f
+	w.size = size
+	w.openedAt = w.now()
+	return nil
+}
+
+// openFile opens Config.Filename for appending and returns it with its current size.
+func (w *Writer) openFile() (*os.File, int64, error) {
+	if err := os.MkdirAll(filepath.Dir(w.cfg.Filename), 0o755); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to create log directory")
+	}
+
+	f, err := os.OpenFile(w.cfg.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to open log file")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			return nil, 0, errors.Wrapf(err, "failed to stat log file (close: %v)", closeErr)
+		}
+		return nil, 0, errors.Wrap(err, "failed to stat log file")
+	}
+	return f, info.Size(), nil
+}
+
+// rotate renames the active file to a backup and switches to a new file.
+// The active file is closed only after the new one is open: on failure it stays
+// the active file under its original name.
+func (w *Writer) rotate() error {
+	backup := w.backupName(w.now())
+	if err := os.Rename(w.cfg.Filename, backup); err != nil {
+		return errors.Wrap(err, "failed to rename log file")
+	}
+
+	f, size, err := w.openFile()
+	if err != nil {
+		if renameErr := os.Rename(backup, w.cfg.Filename); renameErr != nil {
+			return errors.Wrapf(err, "failed to restore log file name (%v)", renameErr)
+		}
+		return err
+	}
+
+	old := w.file
+	w.file = f
+	w.size = size
+	w.openedAt = w.now()
+
+	closeErr := old.Close()
+	select {
+	case w.millCh <- struct{}{}:
+	default:
+	}
+	if closeErr != nil {
+		return errors.Wrap(closeErr, "failed to close rotated log file")
+	}
+	return nil
+}
+
+// backupName returns "<dir>/<name>-<timestamp><ext>" for the active file name.
+func (w *Writer) backupName(t time.Time) string {
+	dir := filepath.Dir(w.cfg.Filename)
+	ext := filepath.Ext(w.cfg.Filename)
+	prefix := strings.TrimSuffix(filepath.Base(w.cfg.Filename), ext)
+	return filepath.Join(dir, prefix+"-"+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+func (w *Writer) millLoop() {
+	defer close(w.millDone)
+	for range w.millCh {
+		_ = w.mill()
+	}
+}
+
+// backupFile is a rotated log file.
+type backupFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// mill compresses rotated files and removes those exceeding MaxBackups or MaxAge.
+func (w *Writer) mill() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var remove []backupFile
+	if w.cfg.MaxBackups > 0 && len(backups) > w.cfg.MaxBackups {
+		remove = append(remove, backups[w.cfg.MaxBackups:]...)
+		backups = backups[:w.cfg.MaxBackups]
+	}
+	if w.cfg.MaxAge > 0 {
+		cutoff := w.now().Add(-w.cfg.MaxAge)
+		kept := backups[:0]
+		for _, b := range backups {
+			if b.timestamp.Before(cutoff) {
+				remove = append(remove, b)
+			} else {
+				kept = append(kept, b)
+			}
+		}
+		backups = kept
+	}
+
+	var errs []error
+	for _, b := range remove {
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	if w.cfg.Compress {
+		for _, b := range backups {
+			if strings.HasSuffix(b.path, compressSuffix) {
+				continue
+			}
+			if err := compressFile(b.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// backups returns rotated files sorted from newest to oldest.
+func (w *Writer) backups() ([]backupFile, error) {
+	dir := filepath.Dir(w.cfg.Filename)
+	ext := filepath.Ext(w.cfg.Filename)
+	prefix := strings.TrimSuffix(filepath.Base(w.cfg.Filename), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read log directory")
+	}
+
+	var backups []backupFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		ts, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		ts = strings.TrimSuffix(strings.TrimSuffix(ts, compressSuffix), ext)
+		t, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), timestamp: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+	return backups, nil
+}
+
+// compressFile gzips path into path+".gz" and removes the original.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log backup")
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "failed to create compressed log backup")
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(path + compressSuffix)
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return errors.Wrap(err, "failed to compress log backup")
+	}
+	if err = gz.Close(); err != nil {
+		_ = dst.Close()
+		return errors.Wrap(err, "failed to compress log backup")
+	}
+	if err = dst.Close(); err != nil {
+		return errors.Wrap(err, "failed to close compressed log backup")
+	}
+
+	return os.Remove(path)
+}
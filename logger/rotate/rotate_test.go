@@ -0,0 +1,215 @@
+package rotate
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestNew_EmptyFilename(t *testing.T) {
+	t.Parallel()
+
+	w, err := New(Config{})
+
+	assert.Nil(t, w)
+	assert.Error(t, err)
+}
+
+func TestWriter_AppendsToExistingFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	w, err := New(Config{Filename: path})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "old\nnew\n", readFile(t, path))
+}
+
+func TestWriter_RotatesBySize(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := newFakeClock()
+	w, err := newWriter(Config{Filename: path, MaxSizeMB: 1}, clock.Now)
+	require.NoError(t, err)
+
+	line := []byte(strings.Repeat("x", 600*1024))
+	_, err = w.Write(line)
+	require.NoError(t, err)
+	clock.Advance(time.Second)
+	_, err = w.Write(line)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2024-01-02T03-04-06.000.log", "app.log"}, listDir(t, dir))
+	assert.Len(t, readFile(t, path), len(line))
+}
+
+func TestWriter_RotatesByTime(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := newFakeClock()
+	w, err := newWriter(Config{Filename: path, RotateEvery: time.Hour}, clock.Now)
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	clock.Advance(30 * time.Minute)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	clock.Advance(30 * time.Minute)
+	_, err = w.Write([]byte("third\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "first\nsecond\n", readFile(t, filepath.Join(dir, "app-2024-01-02T04-04-05.000.log")))
+	assert.Equal(t, "third\n", readFile(t, path))
+}
+
+func TestWriter_RotateFailureKeepsActiveFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := newFakeClock()
+	w, err := newWriter(Config{Filename: path, RotateEvery: time.Hour}, clock.Now)
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	// A directory in place of the backup makes the rename fail
+	clock.Advance(time.Hour)
+	backup := filepath.Join(dir, "app-2024-01-02T04-04-05.000.log")
+	require.NoError(t, os.MkdirAll(filepath.Join(backup, "blocker"), 0o755))
+	n, err := w.Write([]byte("second\n"))
+	assert.Error(t, err)
+	assert.Equal(t, 7, n, "the entry is written to the active file")
+
+	require.NoError(t, os.RemoveAll(backup))
+	_, err = w.Write([]byte("third\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "first\nsecond\n", readFile(t, backup))
+	assert.Equal(t, "third\n", readFile(t, path))
+}
+
+func TestWriter_MaxBackupsAndCompression(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := newFakeClock()
+	w, err := newWriter(Config{Filename: path, MaxBackups: 2, Compress: true}, clock.Now)
+	require.NoError(t, err)
+
+	for i := range 4 {
+		_, err = w.Write([]byte{byte('a' + i), '\n'})
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+		require.NoError(t, w.Rotate())
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2024-01-02T03-04-08.000.log.gz",
+		"app-2024-01-02T03-04-09.000.log.gz",
+		"app.log",
+	}, listDir(t, dir))
+
+	f, err := os.Open(filepath.Join(dir, "app-2024-01-02T03-04-09.000.log.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "d\n", string(data))
+}
+
+func TestWriter_MaxAge(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := newFakeClock()
+	w, err := newWriter(Config{Filename: path, MaxAge: 24 * time.Hour}, clock.Now)
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("old\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Rotate())
+	clock.Advance(48 * time.Hour)
+	_, err = w.Write([]byte("recent\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Rotate())
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2024-01-04T03-04-05.000.log", "app.log"}, listDir(t, dir))
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+	w, err := New(Config{Filename: filepath.Join(t.TempDir(), "app.log")})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close(), "close is idempotent")
+
+	_, err = w.Write([]byte("x"))
+	assert.Error(t, err)
+	assert.Error(t, w.Rotate())
+}
@@ -1,10 +1,16 @@
 package stdjson
 
 import (
+	"io"
 	"log/slog"
 	"os"
 )
 
 func NewDefault(level slog.Level) *slog.Logger {
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	return New(os.Stdout, level)
+}
+
+// New creates a JSON logger writing to w.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
 }
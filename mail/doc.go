@@ -4,6 +4,10 @@
 // Реализации находятся в дочерних пакетах:
 //   - [mail/smtp] — SMTP клиент для отправки писем
 //   - [mail/noop] — заглушка для тестирования
+//   - [mail/preview] — сохранение писем в .eml (каталог или storage) и их просмотр по HTTP вместо отправки
+//   - [mail/parse] — разбор входящих писем (RFC 5322) в [Email]
+//   - [mail/mailtest] — MailHog в testcontainers и проверка полученных писем
+//   - [mail/events] — webhook'и о доставке (SES, SendGrid): bounce, complaint, delivered, open
 //
 // Использование:
 //
@@ -16,10 +20,33 @@
 //	})
 //	defer sender.Close()
 //
+// Фильтрация получателей перед отправкой:
+//   - [SuppressionList] — список подавления (hard bounce, жалобы, отписки);
+//     пополняется из webhook'ов обработчиком events.Suppress
+//   - [SyntaxFilter] — проверка синтаксиса адреса
+//   - [MXFilter] — проверка наличия MX-записей домена
+//
+// Песочница для staging ([Sandbox]): все получатели заменяются безопасным адресом RedirectTo
+// (исходные сохраняются в заголовках X-Original-To, X-Original-Cc, X-Original-Bcc) или письма
+// доставляются только в домены AllowedDomains. Применяется отправителями, например smtp.WithSandbox.
+//
+// Ограничения для пользовательского контента ([Limits]): максимальный размер письма, число вложений,
+// разрешённые типы и запрещённые расширения вложений. [AttachmentScanner] (например, антивирус)
+// проверяет каждое вложение перед отправкой ([ScanAttachments]). Отклонённое письмо не отправляется
+// никому, отправитель возвращает [RejectionError] с причиной ([RejectionReason]) и именем вложения;
+// errors.Is(err, [ErrRejected]) отличает отказ от ошибок доставки. Применяется отправителями,
+// например smtp.WithLimits и smtp.WithAttachmentScanner.
+//
 // Интерфейсы:
 //   - [Sender] — отправка email сообщений
+//   - [RecipientFilter] — фильтр получателей
+//   - [AttachmentScanner] — проверка вложений перед отправкой
 //
 // Типы:
 //   - [Email] — структура email сообщения
 //   - [Address] — email адрес с опциональным именем
+//   - [Attachment] — вложение (файл или inline-ресурс)
+//   - [SendResult] — результат отправки, передаётся в [AfterSendHook]
+//
+// [BuildMessage] рендерит письмо в формат RFC 5322 — так, как его отправляет mail/smtp.
 package mail
@@ -0,0 +1,38 @@
+// Package events принимает webhook'и о доставке писем (Amazon SES через SNS, SendGrid Event Webhook)
+// и приводит их к единому типу [DeliveryEvent]: bounce, complaint, delivered, open.
+//
+// События передаются обработчикам, зарегистрированным в [Dispatcher]. Обработчик [Suppress]
+// добавляет адреса с hard bounce и жалобами в [mail.SuppressionList], так что отправитель,
+// использующий список как [mail.RecipientFilter], перестаёт писать на них.
+//
+// Использование:
+//
+//	suppressed := mail.NewSuppressionList()
+//	dispatcher := events.NewDispatcher()
+//	dispatcher.Handle(events.Suppress(suppressed))
+//	dispatcher.Handle(events.HandlerFunc(func(ctx context.Context, e events.DeliveryEvent) error {
+//	    return repo.MarkBounced(ctx, e.Recipient)
+//	}), events.TypeBounce)
+//
+//	key, err := events.ParseSendGridPublicKey(cfg.SendGridWebhookKey)
+//	if err != nil {
+//	    return err
+//	}
+//	mux.Handle("POST /webhooks/ses", events.NewSESHandler(dispatcher, &events.SESOptions{
+//	    TopicARNs:   []string{cfg.SESTopicARN},
+//	    AutoConfirm: true,
+//	}))
+//	mux.Handle("POST /webhooks/sendgrid", events.NewSendGridHandler(dispatcher, &events.SendGridOptions{
+//	    PublicKey: key,
+//	}))
+//
+// Особенности:
+//   - Подпись SNS (SignatureVersion 1 и 2) проверяется по сертификату AWS; сертификаты кешируются,
+//     принимаются только https URL на хостах sns.*.amazonaws.com
+//   - Подтверждение подписки SNS выполняется автоматически при AutoConfirm, иначе только логируется
+//   - Подпись SendGrid (ECDSA) проверяется при заданном PublicKey; запросы с подписанным временем
+//     дальше Tolerance (по умолчанию 5 минут) от текущего отклоняются как повторы
+//   - Одно уведомление SES с несколькими получателями даёт событие на каждого получателя
+//   - Ответы: 200 — обработано, 400 — некорректный payload, 403 — неверная подпись или топик,
+//     500 — ошибка обработчика (провайдер повторит доставку, поэтому обработчики должны быть идемпотентны)
+package events
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+// DefaultMaxBodySize is the default limit of a webhook request body.
+const DefaultMaxBodySize = 1 << 20
+
+// Type is the kind of a delivery event.
+type Type string
+
+// Delivery event types.
+const (
+	TypeBounce    Type = "bounce"
+	TypeComplaint Type = "complaint"
+	TypeDelivered Type = "delivered"
+	TypeOpen      Type = "open"
+)
+
+// BounceType tells whether a bounce is permanent.
+type BounceType string
+
+// Bounce types.
+const (
+	BouncePermanent    BounceType = "permanent"    // Hard bounce: the address does not exist or rejects mail
+	BounceTransient    BounceType = "transient"    // Soft bounce: mailbox full, blocked, temporary failure
+	BounceUndetermined BounceType = "undetermined" // The provider could not classify the bounce
+)
+
+// Providers reported in DeliveryEvent.Provider.
+const (
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+)
+
+// DeliveryEvent is a delivery notification of a single recipient, normalized across providers.
+type DeliveryEvent struct {
+	Type       Type
+	Provider   string     // ProviderSES or ProviderSendGrid
+	Recipient  string     // Recipient email address
+	MessageID  string     // Provider message ID
+	Time       time.Time  // When the event happened
+	BounceType BounceType // Set for TypeBounce
+	Reason     string     // Bounce diagnostic or complaint feedback type, if reported
+}
+
+// Suppressible reports whether the recipient should no longer receive email:
+// a permanent bounce or a complaint.
+func (e DeliveryEvent) Suppressible() bool {
+	return e.Type == TypeComplaint || (e.Type == TypeBounce && e.BounceType == BouncePermanent)
+}
+
+// Handler processes delivery events.
+type Handler interface {
+	// HandleEvent processes the event. An error makes the webhook respond with 500,
+	// so the provider retries the whole notification.
+	HandleEvent(ctx context.Context, event DeliveryEvent) error
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, event DeliveryEvent) error
+
+// HandleEvent implements Handler.
+func (f HandlerFunc) HandleEvent(ctx context.Context, event DeliveryEvent) error {
+	return f(ctx, event)
+}
+
+// Dispatcher passes events to the handlers registered for their type.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers []registration
+}
+
+// registration is a handler with the event types it accepts; no types means all.
+type registration struct {
+	handler Handler
+	types   []Type
+}
+
+// NewDispatcher creates an empty dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// Handle registers the handler for events of the given types, or for all events if none are given.
+func (d *Dispatcher) Handle(handler Handler, types ...Type) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, registration{handler: handler, types: types})
+}
+
+// Dispatch passes the event to every matching handler in registration order.
+// All handlers are invoked; their errors are combined.
+func (d *Dispatcher) Dispatch(ctx context.Context, event DeliveryEvent) error {
+	d.mu.RLock()
+	handlers := d.handlers
+	d.mu.RUnlock()
+
+	var errs []string
+	for _, r := range handlers {
+		if !r.accepts(event.Type) {
+			continue
+		}
+		if err := r.handler.HandleEvent(ctx, event); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("failed to handle %s event for %s: %s", event.Type, event.Recipient, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (r registration) accepts(t Type) bool {
+	if len(r.types) == 0 {
+		return true
+	}
+	for _, accepted := range r.types {
+		if accepted == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Suppress returns a handler that adds recipients of permanent bounces and complaints
+// to the suppression list, so senders using it as a RecipientFilter skip them.
+func Suppress(list *mail.SuppressionList) Handler {
+	return HandlerFunc(func(_ context.Context, event DeliveryEvent) error {
+		if event.Suppressible() && event.Recipient != "" {
+			list.Add(event.Recipient)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+// recorder collects dispatched events.
+type recorder struct {
+	events []DeliveryEvent
+}
+
+func (r *recorder) HandleEvent(_ context.Context, event DeliveryEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+// TestDispatcher tests that events reach only handlers registered for their type.
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+	d := NewDispatcher()
+	all, bounces := &recorder{}, &recorder{}
+	d.Handle(all)
+	d.Handle(bounces, TypeBounce, TypeComplaint)
+
+	ctx := context.Background()
+	require.NoError(t, d.Dispatch(ctx, DeliveryEvent{Type: TypeBounce, Recipient: "a@example.com"}))
+	require.NoError(t, d.Dispatch(ctx, DeliveryEvent{Type: TypeOpen, Recipient: "b@example.com"}))
+
+	assert.Len(t, all.events, 2)
+	require.Len(t, bounces.events, 1)
+	assert.Equal(t, "a@example.com", bounces.events[0].Recipient)
+}
+
+// TestDispatcher_Error tests that a failing handler does not stop the others and its error is returned.
+func TestDispatcher_Error(t *testing.T) {
+	t.Parallel()
+	d := NewDispatcher()
+	rec := &recorder{}
+	d.Handle(HandlerFunc(func(context.Context, DeliveryEvent) error { return errors.New("boom") }))
+	d.Handle(rec)
+
+	err := d.Dispatch(context.Background(), DeliveryEvent{Type: TypeDelivered, Recipient: "a@example.com"})
+	assert.ErrorContains(t, err, "boom")
+	assert.Len(t, rec.events, 1)
+}
+
+// TestSuppress tests that only permanent bounces and complaints are suppressed.
+func TestSuppress(t *testing.T) {
+	t.Parallel()
+	list := mail.NewSuppressionList()
+	h := Suppress(list)
+	ctx := context.Background()
+
+	for _, event := range []DeliveryEvent{
+		{Type: TypeBounce, BounceType: BouncePermanent, Recipient: "hard@example.com"},
+		{Type: TypeBounce, BounceType: BounceTransient, Recipient: "soft@example.com"},
+		{Type: TypeComplaint, Recipient: "spam@example.com"},
+		{Type: TypeDelivered, Recipient: "ok@example.com"},
+	} {
+		require.NoError(t, h.HandleEvent(ctx, event))
+	}
+
+	assert.True(t, list.Contains("hard@example.com"))
+	assert.True(t, list.Contains("spam@example.com"))
+	assert.False(t, list.Contains("soft@example.com"))
+	assert.False(t, list.Contains("ok@example.com"))
+}
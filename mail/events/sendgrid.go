@@ -0,0 +1,208 @@
+package events
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// SendGrid signed event webhook headers.
+const (
+	SendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	SendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+
+	// DefaultSendGridTolerance is the default maximum difference between the signed timestamp and the current time.
+	DefaultSendGridTolerance = 5 * time.Minute
+)
+
+// SendGridOptions configures SendGridHandler.
+type SendGridOptions struct {
+	// PublicKey verifies signed event webhooks, see ParseSendGridPublicKey.
+	// Nil disables verification; then the endpoint must be protected otherwise.
+	PublicKey *ecdsa.PublicKey
+
+	// Tolerance is the maximum difference between the signed timestamp and the current time
+	// (default DefaultSendGridTolerance). Older requests are rejected, so a captured webhook
+	// cannot be replayed later.
+	Tolerance time.Duration
+
+	MaxBodySize int64 // Maximum request body size in bytes (default DefaultMaxBodySize)
+}
+
+// SendGridHandler is an HTTP handler of the SendGrid event webhook.
+// It dispatches bounce, spamreport, delivered and open events as DeliveryEvent;
+// other event types are ignored.
+type SendGridHandler struct {
+	dispatcher  *Dispatcher
+	publicKey   *ecdsa.PublicKey
+	tolerance   time.Duration
+	maxBodySize int64
+	now         func() time.Time
+}
+
+// sendGridEvent is a single record of the SendGrid event webhook.
+type sendGridEvent struct {
+	Email       string `json:"email"`
+	Timestamp   int64  `json:"timestamp"`
+	Event       string `json:"event"`
+	SGMessageID string `json:"sg_message_id"`
+	Type        string `json:"type"`   // bounce or blocked for bounce events
+	Reason      string `json:"reason"` // bounce diagnostic
+}
+
+// NewSendGridHandler creates a handler dispatching SendGrid events to dispatcher.
+func NewSendGridHandler(dispatcher *Dispatcher, opts *SendGridOptions) *SendGridHandler {
+	if opts == nil {
+		opts = &SendGridOptions{}
+	}
+	h := &SendGridHandler{
+		dispatcher:  dispatcher,
+		publicKey:   opts.PublicKey,
+		tolerance:   opts.Tolerance,
+		maxBodySize: opts.MaxBodySize,
+		now:         time.Now,
+	}
+	if h.tolerance <= 0 {
+		h.tolerance = DefaultSendGridTolerance
+	}
+	if h.maxBodySize <= 0 {
+		h.maxBodySize = DefaultMaxBodySize
+	}
+	return h
+}
+
+// ParseSendGridPublicKey parses the base64 encoded verification key shown in the SendGrid
+// signed event webhook settings.
+func ParseSendGridPublicKey(key string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode sendgrid public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse sendgrid public key")
+	}
+	ecdsaKey, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("sendgrid public key is %T, not ecdsa", pub)
+	}
+	return ecdsaKey, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *SendGridHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if h.publicKey != nil {
+		if err := h.verify(r.Header, body); err != nil {
+			log.With("error", err).Warn("sendgrid webhook signature rejected")
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+	}
+
+	events, err := ParseSendGridEvents(body)
+	if err != nil {
+		log.With("error", err).Warn("invalid sendgrid webhook payload")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	for _, event := range events {
+		if err := h.dispatcher.Dispatch(ctx, event); err != nil {
+			log.With("error", err).Error("failed to handle sendgrid event", "message_id", event.MessageID)
+			http.Error(w, "failed to handle event", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ParseSendGridEvents converts a SendGrid event webhook payload into delivery events.
+// Event types other than bounce, spamreport, delivered and open are skipped.
+func ParseSendGridEvents(data []byte) ([]DeliveryEvent, error) {
+	var records []sendGridEvent
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, errors.Wrap(err, "failed to decode sendgrid events")
+	}
+
+	events := make([]DeliveryEvent, 0, len(records))
+	for _, rec := range records {
+		event := DeliveryEvent{
+			Provider:  ProviderSendGrid,
+			Recipient: rec.Email,
+			MessageID: rec.SGMessageID,
+			Time:      time.Unix(rec.Timestamp, 0).UTC(),
+		}
+		switch rec.Event {
+		case "bounce":
+			event.Type = TypeBounce
+			event.Reason = rec.Reason
+			// blocked is a rejection by the receiving server, usually temporary
+			if rec.Type == "blocked" {
+				event.BounceType = BounceTransient
+			} else {
+				event.BounceType = BouncePermanent
+			}
+		case "spamreport":
+			event.Type = TypeComplaint
+		case "delivered":
+			event.Type = TypeDelivered
+		case "open":
+			event.Type = TypeOpen
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// verify checks the ECDSA signature of timestamp + body and the age of the timestamp.
+func (h *SendGridHandler) verify(header http.Header, body []byte) error {
+	signature := header.Get(SendGridSignatureHeader)
+	timestamp := header.Get(SendGridTimestampHeader)
+	if signature == "" || timestamp == "" {
+		return errors.New("missing signature headers")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode signature")
+	}
+
+	digest := sha256.Sum256(bytes.Join([][]byte{[]byte(timestamp), body}, nil))
+	if !ecdsa.VerifyASN1(h.publicKey, digest[:], sig) {
+		return errors.New("signature mismatch")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse timestamp")
+	}
+	if age := h.now().Sub(time.Unix(unix, 0)).Abs(); age > h.tolerance {
+		return errors.Errorf("timestamp is %s away from current time", age.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+const sendGridPayload = `[
+  {"email":"gone@example.com","timestamp":1769526000,"event":"bounce","type":"bounce","reason":"550 5.1.1 user unknown","sg_message_id":"sg-1"},
+  {"email":"blocked@example.com","timestamp":1769526000,"event":"bounce","type":"blocked","reason":"421 try later","sg_message_id":"sg-2"},
+  {"email":"spam@example.com","timestamp":1769526000,"event":"spamreport","sg_message_id":"sg-3"},
+  {"email":"ok@example.com","timestamp":1769526000,"event":"delivered","sg_message_id":"sg-4"},
+  {"email":"ok@example.com","timestamp":1769526000,"event":"click","sg_message_id":"sg-4"}
+]`
+
+func signSendGrid(t *testing.T, key *ecdsa.PrivateKey, timestamp string, body []byte) http.Header {
+	t.Helper()
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	header := http.Header{}
+	header.Set(SendGridSignatureHeader, base64.StdEncoding.EncodeToString(sig))
+	header.Set(SendGridTimestampHeader, timestamp)
+	return header
+}
+
+func post(h http.Handler, body []byte, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestSendGridHandler tests that signed events are normalized, dispatched and feed the suppression list.
+func TestSendGridHandler(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pub, err := ParseSendGridPublicKey(base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+
+	list := mail.NewSuppressionList()
+	rec := &recorder{}
+	d := NewDispatcher()
+	d.Handle(Suppress(list))
+	d.Handle(rec)
+	h := NewSendGridHandler(d, &SendGridOptions{PublicKey: pub})
+	h.now = func() time.Time { return time.Unix(1769526060, 0) }
+
+	body := []byte(sendGridPayload)
+	resp := post(h, body, signSendGrid(t, key, "1769526001", body))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	require.Len(t, rec.events, 4)
+	assert.Equal(t, DeliveryEvent{
+		Type:       TypeBounce,
+		Provider:   ProviderSendGrid,
+		Recipient:  "gone@example.com",
+		MessageID:  "sg-1",
+		Time:       time.Unix(1769526000, 0).UTC(),
+		BounceType: BouncePermanent,
+		Reason:     "550 5.1.1 user unknown",
+	}, rec.events[0])
+	assert.Equal(t, BounceTransient, rec.events[1].BounceType)
+	assert.Equal(t, TypeComplaint, rec.events[2].Type)
+	assert.Equal(t, TypeDelivered, rec.events[3].Type)
+
+	assert.True(t, list.Contains("gone@example.com"))
+	assert.True(t, list.Contains("spam@example.com"))
+	assert.False(t, list.Contains("blocked@example.com"))
+}
+
+// TestSendGridHandler_InvalidSignature tests that unsigned and tampered payloads are rejected.
+func TestSendGridHandler_InvalidSignature(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rec := &recorder{}
+	d := NewDispatcher()
+	d.Handle(rec)
+	h := NewSendGridHandler(d, &SendGridOptions{PublicKey: &key.PublicKey})
+	h.now = func() time.Time { return time.Unix(1769526060, 0) }
+
+	body := []byte(sendGridPayload)
+	assert.Equal(t, http.StatusForbidden, post(h, body, nil).Code)
+
+	header := signSendGrid(t, key, "1769526001", body)
+	header.Set(SendGridTimestampHeader, "1769526002")
+	assert.Equal(t, http.StatusForbidden, post(h, body, header).Code)
+
+	// replayed and future-dated webhooks are outside the tolerance
+	assert.Equal(t, http.StatusForbidden, post(h, body, signSendGrid(t, key, "1769525700", body)).Code)
+	assert.Equal(t, http.StatusForbidden, post(h, body, signSendGrid(t, key, "1769526420", body)).Code)
+
+	assert.Empty(t, rec.events)
+}
+
+// TestSendGridHandler_InvalidPayload tests that a malformed payload is answered with 400.
+func TestSendGridHandler_InvalidPayload(t *testing.T) {
+	t.Parallel()
+	h := NewSendGridHandler(NewDispatcher(), nil)
+	assert.Equal(t, http.StatusBadRequest, post(h, []byte(`{"event":"bounce"}`), nil).Code)
+}
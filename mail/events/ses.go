@@ -0,0 +1,446 @@
+package events
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // SNS SignatureVersion 1 is defined over SHA1
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// SNS message types.
+const (
+	snsNotification             = "Notification"
+	snsSubscriptionConfirmation = "SubscriptionConfirmation"
+	snsUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// SESOptions configures SESHandler.
+type SESOptions struct {
+	// TopicARNs restricts accepted SNS topics. Empty accepts any topic with a valid signature.
+	TopicARNs []string
+
+	// AutoConfirm confirms SNS subscriptions by requesting SubscribeURL.
+	// Otherwise the confirmation is only logged and must be done manually.
+	AutoConfirm bool
+
+	HTTPClient  *http.Client // Client for signing certificates and subscription confirmation (default http.DefaultClient)
+	MaxBodySize int64        // Maximum request body size in bytes (default DefaultMaxBodySize)
+}
+
+// SESHandler is an HTTP handler of Amazon SES notifications delivered by SNS.
+// It verifies the SNS message signature and dispatches bounce, complaint, delivery and open
+// notifications as DeliveryEvent, one per recipient.
+type SESHandler struct {
+	dispatcher  *Dispatcher
+	topics      map[string]struct{}
+	autoConfirm bool
+	client      *http.Client
+	maxBodySize int64
+
+	certMu sync.RWMutex
+	certs  map[string]*x509.Certificate
+}
+
+// snsMessage is the SNS HTTP(S) delivery payload.
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicARN         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// sesNotification is an SES notification or event publishing record.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Mail             struct {
+		MessageID   string    `json:"messageId"`
+		Timestamp   time.Time `json:"timestamp"`
+		Destination []string  `json:"destination"`
+	} `json:"mail"`
+	Bounce *struct {
+		BounceType        string    `json:"bounceType"`
+		BounceSubType     string    `json:"bounceSubType"`
+		Timestamp         time.Time `json:"timestamp"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplaintFeedbackType string    `json:"complaintFeedbackType"`
+		Timestamp             time.Time `json:"timestamp"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+	Delivery *struct {
+		Timestamp  time.Time `json:"timestamp"`
+		Recipients []string  `json:"recipients"`
+	} `json:"delivery"`
+	Open *struct {
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"open"`
+}
+
+// NewSESHandler creates a handler dispatching SES notifications to dispatcher.
+func NewSESHandler(dispatcher *Dispatcher, opts *SESOptions) *SESHandler {
+	if opts == nil {
+		opts = &SESOptions{}
+	}
+	h := &SESHandler{
+		dispatcher:  dispatcher,
+		autoConfirm: opts.AutoConfirm,
+		client:      opts.HTTPClient,
+		maxBodySize: opts.MaxBodySize,
+		certs:       make(map[string]*x509.Certificate),
+	}
+	if h.client == nil {
+		h.client = http.DefaultClient
+	}
+	if h.maxBodySize <= 0 {
+		h.maxBodySize = DefaultMaxBodySize
+	}
+	if len(opts.TopicARNs) > 0 {
+		h.topics = make(map[string]struct{}, len(opts.TopicARNs))
+		for _, arn := range opts.TopicARNs {
+			h.topics[arn] = struct{}{}
+		}
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *SESHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg snsMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodySize)).Decode(&msg); err != nil {
+		http.Error(w, "invalid sns message", http.StatusBadRequest)
+		return
+	}
+	if h.topics != nil {
+		if _, ok := h.topics[msg.TopicARN]; !ok {
+			log.Warn("sns message from unexpected topic rejected", "topic", msg.TopicARN)
+			http.Error(w, "unexpected topic", http.StatusForbidden)
+			return
+		}
+	}
+	if err := h.verify(ctx, &msg); err != nil {
+		log.With("error", err).Warn("sns message signature rejected", "topic", msg.TopicARN)
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	switch msg.Type {
+	case snsSubscriptionConfirmation:
+		if !h.autoConfirm {
+			log.Info("sns subscription confirmation received", "topic", msg.TopicARN, "subscribe_url", msg.SubscribeURL)
+			break
+		}
+		if err := h.confirm(ctx, msg.SubscribeURL); err != nil {
+			log.With("error", err).Error("failed to confirm sns subscription", "topic", msg.TopicARN)
+			http.Error(w, "failed to confirm subscription", http.StatusInternalServerError)
+			return
+		}
+		log.Info("sns subscription confirmed", "topic", msg.TopicARN)
+	case snsUnsubscribeConfirmation:
+		log.Info("sns subscription removed", "topic", msg.TopicARN)
+	case snsNotification:
+		events, err := ParseSESNotification([]byte(msg.Message))
+		if err != nil {
+			log.With("error", err).Warn("invalid ses notification", "topic", msg.TopicARN)
+			http.Error(w, "invalid ses notification", http.StatusBadRequest)
+			return
+		}
+		for _, event := range events {
+			if err := h.dispatcher.Dispatch(ctx, event); err != nil {
+				log.With("error", err).Error("failed to handle ses event", "message_id", event.MessageID)
+				http.Error(w, "failed to handle event", http.StatusInternalServerError)
+				return
+			}
+		}
+	default:
+		http.Error(w, "unsupported sns message type", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ParseSESNotification converts an SES notification (the SNS Message field) into delivery events,
+// one per recipient. Notification types other than bounce, complaint, delivery and open yield no events.
+func ParseSESNotification(data []byte) ([]DeliveryEvent, error) {
+	var n sesNotification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, errors.Wrap(err, "failed to decode ses notification")
+	}
+	kind := n.EventType
+	if kind == "" {
+		kind = n.NotificationType
+	}
+
+	base := DeliveryEvent{Provider: ProviderSES, MessageID: n.Mail.MessageID, Time: n.Mail.Timestamp}
+	var events []DeliveryEvent
+	switch kind {
+	case "Bounce":
+		if n.Bounce == nil {
+			return nil, errors.New("ses bounce notification without bounce details")
+		}
+		base.Type = TypeBounce
+		base.BounceType = sesBounceType(n.Bounce.BounceType)
+		base.Time = eventTime(n.Bounce.Timestamp, base.Time)
+		for _, rcpt := range n.Bounce.BouncedRecipients {
+			event := base
+			event.Recipient = rcpt.EmailAddress
+			event.Reason = rcpt.DiagnosticCode
+			if event.Reason == "" {
+				event.Reason = n.Bounce.BounceSubType
+			}
+			events = append(events, event)
+		}
+	case "Complaint":
+		if n.Complaint == nil {
+			return nil, errors.New("ses complaint notification without complaint details")
+		}
+		base.Type = TypeComplaint
+		base.Reason = n.Complaint.ComplaintFeedbackType
+		base.Time = eventTime(n.Complaint.Timestamp, base.Time)
+		for _, rcpt := range n.Complaint.ComplainedRecipients {
+			event := base
+			event.Recipient = rcpt.EmailAddress
+			events = append(events, event)
+		}
+	case "Delivery":
+		base.Type = TypeDelivered
+		recipients := n.Mail.Destination
+		if n.Delivery != nil {
+			base.Time = eventTime(n.Delivery.Timestamp, base.Time)
+			if len(n.Delivery.Recipients) > 0 {
+				recipients = n.Delivery.Recipients
+			}
+		}
+		events = eventsFor(base, recipients)
+	case "Open":
+		base.Type = TypeOpen
+		if n.Open != nil {
+			base.Time = eventTime(n.Open.Timestamp, base.Time)
+		}
+		events = eventsFor(base, n.Mail.Destination)
+	}
+	return events, nil
+}
+
+// sesBounceType maps the SES bounceType to BounceType.
+func sesBounceType(t string) BounceType {
+	switch t {
+	case "Permanent":
+		return BouncePermanent
+	case "Transient":
+		return BounceTransient
+	default:
+		return BounceUndetermined
+	}
+}
+
+// eventTime returns t, or fallback if t is not set.
+func eventTime(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+	return t
+}
+
+// eventsFor copies base for every recipient.
+func eventsFor(base DeliveryEvent, recipients []string) []DeliveryEvent {
+	events := make([]DeliveryEvent, 0, len(recipients))
+	for _, rcpt := range recipients {
+		event := base
+		event.Recipient = rcpt
+		events = append(events, event)
+	}
+	return events
+}
+
+// verify checks the SNS message signature against the AWS signing certificate.
+func (h *SESHandler) verify(ctx context.Context, msg *snsMessage) error {
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return errors.Errorf("unsupported signature version %q", msg.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode signature")
+	}
+	cert, err := h.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.Errorf("unexpected signing key type %T", cert.PublicKey)
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum(snsStringToSign(msg)) //nolint:gosec // required by SignatureVersion 1
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256(snsStringToSign(msg))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return errors.Wrap(err, "signature mismatch")
+	}
+	return nil
+}
+
+// snsStringToSign builds the canonical string signed by SNS for the message type.
+func snsStringToSign(msg *snsMessage) []byte {
+	var fields [][2]string
+	if msg.Type == snsNotification {
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageID},
+			{"Subject", msg.Subject},
+			{"Timestamp", msg.Timestamp},
+			{"TopicArn", msg.TopicARN},
+			{"Type", msg.Type},
+		}
+	} else {
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageID},
+			{"SubscribeURL", msg.SubscribeURL},
+			{"Timestamp", msg.Timestamp},
+			{"Token", msg.Token},
+			{"TopicArn", msg.TopicARN},
+			{"Type", msg.Type},
+		}
+	}
+
+	var b strings.Builder
+	for _, f := range fields {
+		// Subject is signed only when present
+		if f[0] == "Subject" && f[1] == "" {
+			continue
+		}
+		b.WriteString(f[0])
+		b.WriteByte('\n')
+		b.WriteString(f[1])
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// certificate returns the signing certificate, downloading it on first use.
+func (h *SESHandler) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	if err := checkAWSURL(certURL); err != nil {
+		return nil, errors.Wrap(err, "invalid signing certificate url")
+	}
+
+	h.certMu.RLock()
+	cert, ok := h.certs[certURL]
+	h.certMu.RUnlock()
+	if ok {
+		return cert, nil
+	}
+
+	data, err := h.get(ctx, certURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download signing certificate")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing certificate is not pem encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing certificate")
+	}
+
+	h.certMu.Lock()
+	h.certs[certURL] = cert
+	h.certMu.Unlock()
+	return cert, nil
+}
+
+// confirm confirms the SNS subscription.
+func (h *SESHandler) confirm(ctx context.Context, subscribeURL string) error {
+	if err := checkAWSURL(subscribeURL); err != nil {
+		return errors.Wrap(err, "invalid subscribe url")
+	}
+	_, err := h.get(ctx, subscribeURL)
+	return err
+}
+
+// get performs a GET request and returns the response body.
+func (h *SESHandler) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request %s", req.URL.Host)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL.Host)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, DefaultMaxBodySize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response from %s", req.URL.Host)
+	}
+	return data, nil
+}
+
+// checkAWSURL makes sure the URL points to SNS over https, so a forged message
+// cannot make the handler trust an arbitrary certificate or request an arbitrary host.
+func checkAWSURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return errors.Errorf("scheme %q is not https", u.Scheme)
+	}
+	host := u.Hostname()
+	if !strings.HasPrefix(host, "sns.") || !(strings.HasSuffix(host, ".amazonaws.com") || strings.HasSuffix(host, ".amazonaws.com.cn")) {
+		return errors.Errorf("host %q is not an sns endpoint", host)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package events
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+const (
+	testTopicARN = "arn:aws:sns:eu-west-1:123456789012:ses-events"
+	testCertURL  = "https://sns.eu-west-1.amazonaws.com/SimpleNotificationService-test.pem"
+)
+
+// roundTripFunc serves client requests in-process.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// snsSigner signs SNS messages with a self-signed certificate served at testCertURL.
+type snsSigner struct {
+	key       *rsa.PrivateKey
+	certPEM   []byte
+	confirmed atomic.Int32
+}
+
+func newSNSSigner(t *testing.T) *snsSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &snsSigner{key: key, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (s *snsSigner) client() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := s.certPEM
+		if strings.Contains(r.URL.RawQuery, "ConfirmSubscription") {
+			s.confirmed.Add(1)
+			body = []byte("<ConfirmSubscriptionResponse/>")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Request: r}, nil
+	})}
+}
+
+func (s *snsSigner) sign(t *testing.T, msg *snsMessage) {
+	t.Helper()
+	msg.SignatureVersion = "2"
+	msg.SigningCertURL = testCertURL
+	digest := sha256.Sum256(snsStringToSign(msg))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	msg.Signature = base64.StdEncoding.EncodeToString(sig)
+}
+
+func postJSON(t *testing.T, h http.Handler, v any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(v)
+	require.NoError(t, err)
+	return post(h, body, nil)
+}
+
+const sesBounce = `{
+  "notificationType": "Bounce",
+  "mail": {"messageId": "msg-1", "timestamp": "2026-01-27T14:59:38.237Z", "destination": ["gone@example.com", "full@example.com"]},
+  "bounce": {
+    "bounceType": "Permanent",
+    "bounceSubType": "General",
+    "timestamp": "2026-01-27T14:59:40.000Z",
+    "bouncedRecipients": [{"emailAddress": "gone@example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"}]
+  }
+}`
+
+// TestSESHandler tests that a signed bounce notification is dispatched and suppresses the recipient.
+func TestSESHandler(t *testing.T) {
+	t.Parallel()
+	signer := newSNSSigner(t)
+	list := mail.NewSuppressionList()
+	rec := &recorder{}
+	d := NewDispatcher()
+	d.Handle(Suppress(list))
+	d.Handle(rec)
+	h := NewSESHandler(d, &SESOptions{TopicARNs: []string{testTopicARN}, HTTPClient: signer.client()})
+
+	msg := &snsMessage{Type: snsNotification, MessageID: "sns-1", TopicARN: testTopicARN, Message: sesBounce, Timestamp: "2026-01-27T14:59:41.000Z"}
+	signer.sign(t, msg)
+	resp := postJSON(t, h, msg)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	require.Len(t, rec.events, 1)
+	event := rec.events[0]
+	assert.Equal(t, TypeBounce, event.Type)
+	assert.Equal(t, BouncePermanent, event.BounceType)
+	assert.Equal(t, ProviderSES, event.Provider)
+	assert.Equal(t, "gone@example.com", event.Recipient)
+	assert.Equal(t, "msg-1", event.MessageID)
+	assert.Equal(t, "smtp; 550 5.1.1 user unknown", event.Reason)
+	assert.Equal(t, time.Date(2026, 1, 27, 14, 59, 40, 0, time.UTC), event.Time)
+	assert.True(t, list.Contains("gone@example.com"))
+	assert.False(t, list.Contains("full@example.com"))
+}
+
+// TestSESHandler_Rejected tests that tampered messages and unexpected topics are rejected.
+func TestSESHandler_Rejected(t *testing.T) {
+	t.Parallel()
+	signer := newSNSSigner(t)
+	rec := &recorder{}
+	d := NewDispatcher()
+	d.Handle(rec)
+	h := NewSESHandler(d, &SESOptions{TopicARNs: []string{testTopicARN}, HTTPClient: signer.client()})
+
+	tampered := &snsMessage{Type: snsNotification, MessageID: "sns-1", TopicARN: testTopicARN, Message: sesBounce, Timestamp: "2026-01-27T14:59:41.000Z"}
+	signer.sign(t, tampered)
+	tampered.Message = strings.ReplaceAll(tampered.Message, "gone@", "victim@")
+	assert.Equal(t, http.StatusForbidden, postJSON(t, h, tampered).Code)
+
+	otherTopic := &snsMessage{Type: snsNotification, MessageID: "sns-2", TopicARN: "arn:aws:sns:eu-west-1:1:other", Message: sesBounce}
+	signer.sign(t, otherTopic)
+	assert.Equal(t, http.StatusForbidden, postJSON(t, h, otherTopic).Code)
+
+	foreignCert := &snsMessage{Type: snsNotification, MessageID: "sns-3", TopicARN: testTopicARN, Message: sesBounce}
+	signer.sign(t, foreignCert)
+	foreignCert.SigningCertURL = "https://attacker.example.com/cert.pem"
+	assert.Equal(t, http.StatusForbidden, postJSON(t, h, foreignCert).Code)
+
+	assert.Empty(t, rec.events)
+}
+
+// TestSESHandler_SubscriptionConfirmation tests that AutoConfirm requests the SubscribeURL.
+func TestSESHandler_SubscriptionConfirmation(t *testing.T) {
+	t.Parallel()
+	signer := newSNSSigner(t)
+	h := NewSESHandler(NewDispatcher(), &SESOptions{AutoConfirm: true, HTTPClient: signer.client()})
+
+	msg := &snsMessage{
+		Type:         snsSubscriptionConfirmation,
+		MessageID:    "sns-1",
+		Token:        "token",
+		TopicARN:     testTopicARN,
+		Message:      "You have chosen to subscribe",
+		SubscribeURL: "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=token",
+		Timestamp:    "2026-01-27T14:59:41.000Z",
+	}
+	signer.sign(t, msg)
+	resp := postJSON(t, h, msg)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, int32(1), signer.confirmed.Load())
+}
+
+// TestParseSESNotification tests mapping of SES notification types to delivery events.
+func TestParseSESNotification(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		data       string
+		wantType   Type
+		recipients []string
+	}{
+		{
+			name:       "complaint",
+			data:       `{"notificationType":"Complaint","mail":{"messageId":"m"},"complaint":{"complaintFeedbackType":"abuse","complainedRecipients":[{"emailAddress":"a@example.com"}]}}`,
+			wantType:   TypeComplaint,
+			recipients: []string{"a@example.com"},
+		},
+		{
+			name:       "delivery",
+			data:       `{"notificationType":"Delivery","mail":{"messageId":"m","destination":["a@example.com","b@example.com"]},"delivery":{"recipients":["a@example.com"]}}`,
+			wantType:   TypeDelivered,
+			recipients: []string{"a@example.com"},
+		},
+		{
+			name:       "event publishing open",
+			data:       `{"eventType":"Open","mail":{"messageId":"m","destination":["a@example.com","b@example.com"]},"open":{"timestamp":"2026-01-27T15:00:00Z"}}`,
+			wantType:   TypeOpen,
+			recipients: []string{"a@example.com", "b@example.com"},
+		},
+		{
+			name: "ignored type",
+			data: `{"eventType":"Send","mail":{"messageId":"m","destination":["a@example.com"]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			events, err := ParseSESNotification([]byte(tt.data))
+			require.NoError(t, err)
+			require.Len(t, events, len(tt.recipients))
+			for i, event := range events {
+				assert.Equal(t, tt.wantType, event.Type)
+				assert.Equal(t, tt.recipients[i], event.Recipient)
+				assert.Equal(t, "m", event.MessageID)
+			}
+		})
+	}
+}
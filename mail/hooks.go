@@ -0,0 +1,171 @@
+package mail
+
+import (
+	"context"
+	"net"
+	netmail "net/mail"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrAllRecipientsFiltered is returned when RecipientFilter dropped every recipient of an email.
+var ErrAllRecipientsFiltered = errors.New("all recipients were filtered out")
+
+// RecipientFilter decides whether an email may be sent to a recipient.
+// It is invoked by senders before delivery for every To, Cc and Bcc address.
+type RecipientFilter interface {
+	// AllowRecipient returns false to drop the recipient.
+	// A non-nil error aborts sending of the whole email.
+	AllowRecipient(ctx context.Context, addr Address) (bool, error)
+}
+
+// RecipientFilterFunc adapts a function to RecipientFilter.
+type RecipientFilterFunc func(ctx context.Context, addr Address) (bool, error)
+
+// AllowRecipient implements RecipientFilter.
+func (f RecipientFilterFunc) AllowRecipient(ctx context.Context, addr Address) (bool, error) {
+	return f(ctx, addr)
+}
+
+// SendResult describes the outcome of sending a single email.
+type SendResult struct {
+	Email     Email     // Email with filtered recipients
+	MessageID string    // Message-ID header value
+	Dropped   []Address // Recipients dropped by RecipientFilter
+	Err       error     // nil if the email was accepted by the server
+}
+
+// AfterSendHook is invoked after every email, successful or not.
+// Use it to record message IDs for bounce tracking.
+type AfterSendHook func(ctx context.Context, result SendResult)
+
+// FilterRecipients applies filters to all recipients of the email and returns
+// the filtered copy and the dropped addresses.
+func FilterRecipients(ctx context.Context, email Email, filters ...RecipientFilter) (Email, []Address, error) {
+	if len(filters) == 0 {
+		return email, nil, nil
+	}
+
+	var dropped []Address
+	filter := func(addrs []Address) ([]Address, error) {
+		if addrs == nil {
+			return nil, nil
+		}
+		kept := make([]Address, 0, len(addrs))
+	next:
+		for _, addr := range addrs {
+			for _, f := range filters {
+				ok, err := f.AllowRecipient(ctx, addr)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to check recipient %s", addr.Address)
+				}
+				if !ok {
+					dropped = append(dropped, addr)
+					continue next
+				}
+			}
+			kept = append(kept, addr)
+		}
+		return kept, nil
+	}
+
+	filtered := email
+	var err error
+	if filtered.To, err = filter(email.To); err != nil {
+		return email, nil, err
+	}
+	if filtered.Cc, err = filter(email.Cc); err != nil {
+		return email, nil, err
+	}
+	if filtered.Bcc, err = filter(email.Bcc); err != nil {
+		return email, nil, err
+	}
+
+	if len(dropped) > 0 && len(filtered.To)+len(filtered.Cc)+len(filtered.Bcc) == 0 {
+		return filtered, dropped, ErrAllRecipientsFiltered
+	}
+	return filtered, dropped, nil
+}
+
+// SuppressionList is an in-memory RecipientFilter that drops suppressed addresses
+// (hard bounces, complaints, unsubscribes). Addresses are compared case-insensitively.
+type SuppressionList struct {
+	mu        sync.RWMutex
+	addresses map[string]struct{}
+}
+
+// NewSuppressionList creates a suppression list with the given addresses.
+func NewSuppressionList(addresses ...string) *SuppressionList {
+	l := &SuppressionList{addresses: make(map[string]struct{}, len(addresses))}
+	l.Add(addresses...)
+	return l
+}
+
+// Add suppresses the addresses.
+func (l *SuppressionList) Add(addresses ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, addr := range addresses {
+		l.addresses[normalizeAddress(addr)] = struct{}{}
+	}
+}
+
+// Remove removes the addresses from the list.
+func (l *SuppressionList) Remove(addresses ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, addr := range addresses {
+		delete(l.addresses, normalizeAddress(addr))
+	}
+}
+
+// Contains reports whether the address is suppressed.
+func (l *SuppressionList) Contains(address string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.addresses[normalizeAddress(address)]
+	return ok
+}
+
+// AllowRecipient implements RecipientFilter.
+func (l *SuppressionList) AllowRecipient(_ context.Context, addr Address) (bool, error) {
+	return !l.Contains(addr.Address), nil
+}
+
+// SyntaxFilter drops recipients whose address is not a valid RFC 5322 addr-spec.
+func SyntaxFilter() RecipientFilter {
+	return RecipientFilterFunc(func(_ context.Context, addr Address) (bool, error) {
+		parsed, err := netmail.ParseAddress(addr.Address)
+		return err == nil && parsed.Address == addr.Address, nil
+	})
+}
+
+// MXFilter drops recipients whose domain has no MX records.
+// Temporary DNS errors abort sending; nil resolver means net.DefaultResolver.
+func MXFilter(resolver *net.Resolver) RecipientFilter {
+	r := resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	return RecipientFilterFunc(func(ctx context.Context, addr Address) (bool, error) {
+		_, domain, ok := strings.Cut(addr.Address, "@")
+		if !ok || domain == "" {
+			return false, nil
+		}
+		records, err := r.LookupMX(ctx, domain)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+				return false, nil
+			}
+			return false, errors.Wrapf(err, "failed to lookup mx for %s", domain)
+		}
+		return len(records) > 0, nil
+	})
+}
+
+func normalizeAddress(addr string) string {
+	return strings.ToLower(strings.TrimSpace(addr))
+}
@@ -0,0 +1,123 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addresses(addrs ...string) []Address {
+	result := make([]Address, 0, len(addrs))
+	for _, a := range addrs {
+		result = append(result, Address{Address: a})
+	}
+	return result
+}
+
+func TestSuppressionList(t *testing.T) {
+	t.Parallel()
+	list := NewSuppressionList("Bounced@Example.com")
+
+	assert.True(t, list.Contains("bounced@example.com"))
+	assert.False(t, list.Contains("ok@example.com"))
+
+	allowed, err := list.AllowRecipient(context.Background(), Address{Address: " BOUNCED@example.com "})
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	list.Remove("bounced@example.com")
+	assert.False(t, list.Contains("bounced@example.com"))
+
+	list.Add("new@example.com")
+	assert.True(t, list.Contains("NEW@example.com"))
+}
+
+func TestFilterRecipients(t *testing.T) {
+	t.Parallel()
+	email := Email{
+		To:  addresses("a@example.com", "suppressed@example.com"),
+		Cc:  addresses("not an address"),
+		Bcc: addresses("b@example.com"),
+	}
+
+	filtered, dropped, err := FilterRecipients(context.Background(), email,
+		NewSuppressionList("suppressed@example.com"),
+		SyntaxFilter(),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, addresses("a@example.com"), filtered.To)
+	assert.Empty(t, filtered.Cc)
+	assert.Equal(t, addresses("b@example.com"), filtered.Bcc)
+	assert.Equal(t, addresses("suppressed@example.com", "not an address"), dropped)
+	assert.Len(t, email.To, 2, "the original email must not be modified")
+}
+
+func TestFilterRecipients_AllFiltered(t *testing.T) {
+	t.Parallel()
+	email := Email{To: addresses("suppressed@example.com")}
+
+	_, dropped, err := FilterRecipients(context.Background(), email, NewSuppressionList("suppressed@example.com"))
+
+	assert.ErrorIs(t, err, ErrAllRecipientsFiltered)
+	assert.Len(t, dropped, 1)
+}
+
+func TestFilterRecipients_FilterError(t *testing.T) {
+	t.Parallel()
+	failing := RecipientFilterFunc(func(context.Context, Address) (bool, error) {
+		return false, errors.New("dns timeout")
+	})
+
+	_, _, err := FilterRecipients(context.Background(), Email{To: addresses("a@example.com")}, failing)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dns timeout")
+}
+
+func TestFilterRecipients_NoFilters(t *testing.T) {
+	t.Parallel()
+	email := Email{To: addresses("a@example.com")}
+
+	filtered, dropped, err := FilterRecipients(context.Background(), email)
+
+	require.NoError(t, err)
+	assert.Equal(t, email, filtered)
+	assert.Nil(t, dropped)
+}
+
+func TestSyntaxFilter(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "user@example.com", allowed: true},
+		{address: "user.name+tag@sub.example.com", allowed: true},
+		{address: "user@", allowed: false},
+		{address: "Name <user@example.com>", allowed: false},
+		{address: "", allowed: false},
+	}
+
+	filter := SyntaxFilter()
+	for _, tc := range testCases {
+		t.Run(tc.address, func(t *testing.T) {
+			t.Parallel()
+			allowed, err := filter.AllowRecipient(context.Background(), Address{Address: tc.address})
+			require.NoError(t, err)
+			assert.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
+
+func TestMXFilter_InvalidAddress(t *testing.T) {
+	t.Parallel()
+
+	allowed, err := MXFilter(nil).AllowRecipient(context.Background(), Address{Address: "no-domain"})
+
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
@@ -0,0 +1,158 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRejected is matched by RejectionError with errors.Is.
+var ErrRejected = errors.New("email rejected")
+
+// RejectionReason explains why an email was rejected before sending.
+type RejectionReason string
+
+const (
+	RejectMessageTooLarge    RejectionReason = "message_too_large"
+	RejectTooManyAttachments RejectionReason = "too_many_attachments"
+	RejectAttachmentType     RejectionReason = "attachment_type_not_allowed"
+	RejectAttachmentInfected RejectionReason = "attachment_infected"
+)
+
+// RejectionError reports an email rejected by Limits or an AttachmentScanner.
+// The email is not sent to any recipient.
+type RejectionError struct {
+	Reason     RejectionReason
+	Attachment string // Filename of the rejected attachment, empty if the whole email is rejected
+	Detail     string // Human-readable details, e.g. the limit or the threat name
+}
+
+func (e *RejectionError) Error() string {
+	msg := "email rejected: " + string(e.Reason)
+	if e.Attachment != "" {
+		msg += " (attachment " + e.Attachment + ")"
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Is reports whether target is ErrRejected.
+func (e *RejectionError) Is(target error) bool {
+	return target == ErrRejected
+}
+
+// Limits restricts the size and attachments of outgoing emails, e.g. with user-generated content.
+// The zero value is unlimited.
+type Limits struct {
+	MaxMessageSize         int      // Max size of the rendered message in bytes, attachments included (0 = unlimited)
+	MaxAttachments         int      // Max number of attachments, inline ones included (0 = unlimited)
+	AllowedAttachmentTypes []string // Allowed content types, "image/*" matches any subtype (empty = any)
+	BlockedExtensions      []string // Rejected filename extensions, e.g. ".exe", compared case-insensitively
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	return l.MaxMessageSize > 0 || l.MaxAttachments > 0 || len(l.AllowedAttachmentTypes) > 0 || len(l.BlockedExtensions) > 0
+}
+
+// Check returns a *RejectionError if the email exceeds the limits.
+// Attachment types are checked against the declared ContentType (application/octet-stream if empty);
+// use an AttachmentScanner to inspect the content.
+func (l Limits) Check(email Email) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	if l.MaxAttachments > 0 && len(email.Attachments) > l.MaxAttachments {
+		return &RejectionError{
+			Reason: RejectTooManyAttachments,
+			Detail: fmt.Sprintf("%d attachments exceed the limit of %d", len(email.Attachments), l.MaxAttachments),
+		}
+	}
+
+	for _, attachment := range email.Attachments {
+		ext := strings.ToLower(path.Ext(attachment.Filename))
+		for _, blocked := range l.BlockedExtensions {
+			if ext != "" && ext == strings.ToLower(strings.TrimSpace(blocked)) {
+				return &RejectionError{Reason: RejectAttachmentType, Attachment: attachment.Filename, Detail: "extension " + ext + " is blocked"}
+			}
+		}
+		if len(l.AllowedAttachmentTypes) > 0 && !l.typeAllowed(attachment.ContentType) {
+			return &RejectionError{Reason: RejectAttachmentType, Attachment: attachment.Filename, Detail: "content type " + contentType(attachment) + " is not allowed"}
+		}
+	}
+
+	if l.MaxMessageSize > 0 {
+		if size := len(BuildMessage(&email)); size > l.MaxMessageSize {
+			return &RejectionError{
+				Reason: RejectMessageTooLarge,
+				Detail: fmt.Sprintf("message size %d bytes exceeds the limit of %d bytes", size, l.MaxMessageSize),
+			}
+		}
+	}
+	return nil
+}
+
+// typeAllowed reports whether the media type matches AllowedAttachmentTypes.
+func (l Limits) typeAllowed(contentTypeValue string) bool {
+	mediaType, _, _ := strings.Cut(contentTypeValue, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	for _, allowed := range l.AllowedAttachmentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// contentType returns the content type the attachment is sent with.
+func contentType(attachment Attachment) string {
+	if attachment.ContentType == "" {
+		return "application/octet-stream"
+	}
+	return attachment.ContentType
+}
+
+// AttachmentScanner inspects attachments before sending, e.g. with an antivirus.
+type AttachmentScanner interface {
+	// ScanAttachment returns the name of the detected threat, or "" if the attachment is clean.
+	// A non-nil error aborts sending of the whole email.
+	ScanAttachment(ctx context.Context, attachment Attachment) (threat string, err error)
+}
+
+// AttachmentScannerFunc adapts a function to AttachmentScanner.
+type AttachmentScannerFunc func(ctx context.Context, attachment Attachment) (string, error)
+
+// ScanAttachment implements AttachmentScanner.
+func (f AttachmentScannerFunc) ScanAttachment(ctx context.Context, attachment Attachment) (string, error) {
+	return f(ctx, attachment)
+}
+
+// ScanAttachments runs the scanners on every attachment of the email and returns
+// a *RejectionError with RejectAttachmentInfected for the first detected threat.
+func ScanAttachments(ctx context.Context, email Email, scanners ...AttachmentScanner) error {
+	for _, attachment := range email.Attachments {
+		for _, scanner := range scanners {
+			threat, err := scanner.ScanAttachment(ctx, attachment)
+			if err != nil {
+				return errors.Wrapf(err, "failed to scan attachment %s", attachment.Filename)
+			}
+			if threat != "" {
+				return &RejectionError{Reason: RejectAttachmentInfected, Attachment: attachment.Filename, Detail: threat}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLimits_Check tests rejection of emails exceeding size, attachment count and type limits.
+func TestLimits_Check(t *testing.T) {
+	t.Parallel()
+	email := Email{
+		Body: "Report attached",
+		Attachments: []Attachment{
+			{Filename: "report.pdf", ContentType: "application/pdf", Data: []byte("%PDF")},
+			{Filename: "logo.png", ContentType: "image/png", Inline: true, Data: []byte("png")},
+		},
+	}
+	tests := []struct {
+		name       string
+		limits     Limits
+		email      Email
+		reason     RejectionReason
+		attachment string
+	}{
+		{name: "unlimited", limits: Limits{}, email: email},
+		{
+			name:   "within limits",
+			limits: Limits{MaxMessageSize: 10000, MaxAttachments: 2, AllowedAttachmentTypes: []string{"application/pdf", "image/*"}, BlockedExtensions: []string{".exe"}},
+			email:  email,
+		},
+		{name: "message too large", limits: Limits{MaxMessageSize: 100}, email: email, reason: RejectMessageTooLarge},
+		{name: "too many attachments", limits: Limits{MaxAttachments: 1}, email: email, reason: RejectTooManyAttachments},
+		{
+			name:       "type not allowed",
+			limits:     Limits{AllowedAttachmentTypes: []string{"application/pdf"}},
+			email:      email,
+			reason:     RejectAttachmentType,
+			attachment: "logo.png",
+		},
+		{
+			name:       "default type not allowed",
+			limits:     Limits{AllowedAttachmentTypes: []string{"image/*"}},
+			email:      Email{Attachments: []Attachment{{Filename: "data.bin"}}},
+			reason:     RejectAttachmentType,
+			attachment: "data.bin",
+		},
+		{
+			name:       "blocked extension",
+			limits:     Limits{BlockedExtensions: []string{".exe"}},
+			email:      Email{Attachments: []Attachment{{Filename: "Setup.EXE", ContentType: "application/pdf"}}},
+			reason:     RejectAttachmentType,
+			attachment: "Setup.EXE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.limits.Check(tt.email)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var rejection *RejectionError
+			require.ErrorAs(t, err, &rejection)
+			assert.ErrorIs(t, err, ErrRejected)
+			assert.Equal(t, tt.reason, rejection.Reason)
+			assert.Equal(t, tt.attachment, rejection.Attachment)
+		})
+	}
+}
+
+// TestScanAttachments tests rejection of infected attachments and scanner failures.
+func TestScanAttachments(t *testing.T) {
+	t.Parallel()
+	email := Email{Attachments: []Attachment{
+		{Filename: "notes.txt", Data: []byte("hello")},
+		{Filename: "eicar.com", Data: []byte("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR")},
+	}}
+	var scanned []string
+	scanner := AttachmentScannerFunc(func(_ context.Context, a Attachment) (string, error) {
+		scanned = append(scanned, a.Filename)
+		if strings.Contains(string(a.Data), "EICAR") {
+			return "Eicar-Test-Signature", nil
+		}
+		return "", nil
+	})
+
+	err := ScanAttachments(context.Background(), email, scanner)
+	var rejection *RejectionError
+	require.ErrorAs(t, err, &rejection)
+	assert.Equal(t, RejectAttachmentInfected, rejection.Reason)
+	assert.Equal(t, "eicar.com", rejection.Attachment)
+	assert.EqualError(t, err, "email rejected: attachment_infected (attachment eicar.com): Eicar-Test-Signature")
+	assert.Equal(t, []string{"notes.txt", "eicar.com"}, scanned)
+
+	failing := AttachmentScannerFunc(func(context.Context, Attachment) (string, error) {
+		return "", errors.New("clamd unavailable")
+	})
+	err = ScanAttachments(context.Background(), email, failing)
+	assert.EqualError(t, err, "failed to scan attachment notes.txt: clamd unavailable")
+	assert.NotErrorIs(t, err, ErrRejected)
+}
@@ -26,6 +26,18 @@ type Email struct {
 	// Body
 	Body string // Plain text body
 	HTML string // HTML body (optional)
+
+	// Attachments
+	Attachments []Attachment
+}
+
+// Attachment represents a file attached to an email.
+type Attachment struct {
+	Filename    string // "report.pdf"
+	ContentType string // "application/pdf" (optional, application/octet-stream by default)
+	ContentID   string // Content-ID for inline attachments referenced from HTML (optional)
+	Inline      bool   // Content-Disposition: inline instead of attachment
+	Data        []byte // Decoded content
 }
 
 // Address represents an email address.
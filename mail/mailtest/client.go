@@ -0,0 +1,140 @@
+package mailtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/mail"
+	"github.com/pure-golang/adapters/mail/parse"
+)
+
+// pollInterval is the delay between message list requests in WaitFor.
+const pollInterval = 100 * time.Millisecond
+
+// Message is a message received by the mail server.
+type Message struct {
+	ID         string
+	From       string      // Envelope sender (MAIL FROM)
+	Recipients []string    // Envelope recipients (RCPT TO), including Bcc
+	Raw        []byte      // Message as received, RFC 5322
+	Email      *mail.Email // Parsed message: headers, bodies and attachments
+	Created    time.Time
+}
+
+// Client reads messages from the MailHog HTTP API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the MailHog API at baseURL, e.g. "http://localhost:8025".
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// mailhogMessages is the response of GET /api/v2/messages.
+type mailhogMessages struct {
+	Items []struct {
+		ID      string    `json:"ID"`
+		Created time.Time `json:"Created"`
+		Raw     struct {
+			From string   `json:"From"`
+			To   []string `json:"To"`
+			Data string   `json:"Data"`
+		} `json:"Raw"`
+	} `json:"items"`
+}
+
+// Messages returns all received messages, oldest first.
+func (c *Client) Messages(ctx context.Context) ([]Message, error) {
+	var resp mailhogMessages
+	if err := c.do(ctx, http.MethodGet, "/api/v2/messages?limit=1000", &resp); err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		raw := []byte(item.Raw.Data)
+		email, err := parse.Parse(bytes.NewReader(raw))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse message %s", item.ID)
+		}
+		messages = append(messages, Message{
+			ID:         item.ID,
+			From:       item.Raw.From,
+			Recipients: item.Raw.To,
+			Raw:        raw,
+			Email:      email,
+			Created:    item.Created,
+		})
+	}
+	// MailHog returns the newest message first
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// DeleteAll deletes all received messages.
+func (c *Client) DeleteAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/messages", nil)
+}
+
+// WaitFor polls the server until a message matching match arrives or ctx is done.
+func (c *Client) WaitFor(ctx context.Context, match func(Message) bool) (Message, error) {
+	for {
+		messages, err := c.Messages(ctx)
+		if err != nil {
+			return Message{}, err
+		}
+		for _, m := range messages {
+			if match(m) {
+				return m, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return Message{}, errors.Wrap(ctx.Err(), "message not received")
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// WaitForSubject waits for a message with the given subject.
+func (c *Client) WaitForSubject(ctx context.Context, subject string) (Message, error) {
+	return c.WaitFor(ctx, func(m Message) bool {
+		return m.Email.Subject == subject
+	})
+}
+
+func (c *Client) do(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s %s", method, path)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s for %s %s", resp.Status, method, path)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrap(err, "failed to decode response")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package mailtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rawMessage = "From: Sender <sender@example.com>\r\n" +
+	"To: to@example.com\r\n" +
+	"Subject: =?UTF-8?B?0J/RgNC40LLQtdGC?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/alternative; boundary=b\r\n" +
+	"\r\n" +
+	"--b\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"plain body\r\n" +
+	"--b\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>html body</p>\r\n" +
+	"--b--\r\n"
+
+// fakeMailHog serves the MailHog API with messages added by add.
+type fakeMailHog struct {
+	mu    sync.Mutex
+	items []map[string]any
+}
+
+func (f *fakeMailHog) add(id, subject string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data := rawMessage
+	if subject != "" {
+		data = "Subject: " + subject + "\r\n\r\nbody"
+	}
+	// newest first, like MailHog
+	f.items = append([]map[string]any{{
+		"ID":      id,
+		"Created": time.Now().Format(time.RFC3339Nano),
+		"Raw": map[string]any{
+			"From": "sender@example.com",
+			"To":   []string{"to@example.com", "bcc@example.com"},
+			"Data": data,
+		},
+	}}, f.items...)
+}
+
+func (f *fakeMailHog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/messages":
+		writeJSON(w, map[string]any{"total": len(f.items), "count": len(f.items), "items": f.items})
+	case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/messages":
+		f.items = nil
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeClient(t *testing.T) (*Client, *fakeMailHog) {
+	fake := &fakeMailHog{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL + "/"), fake
+}
+
+func TestClient_Messages(t *testing.T) {
+	t.Parallel()
+	client, fake := newFakeClient(t)
+	fake.add("1", "")
+	fake.add("2", "Second")
+
+	messages, err := client.Messages(context.Background())
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	first := messages[0]
+	assert.Equal(t, "1", first.ID)
+	assert.Equal(t, "sender@example.com", first.From)
+	assert.Equal(t, []string{"to@example.com", "bcc@example.com"}, first.Recipients)
+	assert.Equal(t, "Привет", first.Email.Subject)
+	assert.Equal(t, "Sender", first.Email.From.Name)
+	assert.Equal(t, "plain body", first.Email.Body)
+	assert.Equal(t, "<p>html body</p>", first.Email.HTML)
+	assert.Equal(t, rawMessage, string(first.Raw))
+
+	assert.Equal(t, "Second", messages[1].Email.Subject)
+}
+
+func TestClient_DeleteAll(t *testing.T) {
+	t.Parallel()
+	client, fake := newFakeClient(t)
+	fake.add("1", "First")
+
+	require.NoError(t, client.DeleteAll(context.Background()))
+
+	messages, err := client.Messages(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestClient_WaitForSubject(t *testing.T) {
+	t.Parallel()
+	client, fake := newFakeClient(t)
+	fake.add("1", "Other")
+	go func() {
+		time.Sleep(3 * pollInterval)
+		fake.add("2", "Expected")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m, err := client.WaitForSubject(ctx, "Expected")
+	require.NoError(t, err)
+	assert.Equal(t, "2", m.ID)
+}
+
+func TestClient_WaitFor_Timeout(t *testing.T) {
+	t.Parallel()
+	client, _ := newFakeClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*pollInterval)
+	defer cancel()
+	_, err := client.WaitForSubject(ctx, "Missing")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	_, err := NewClient(server.URL).Messages(context.Background())
+	assert.ErrorContains(t, err, "unexpected status 404")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
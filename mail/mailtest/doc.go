@@ -0,0 +1,27 @@
+// Package mailtest запускает MailHog в testcontainers и читает полученные письма через его HTTP API,
+// чтобы интеграционные тесты проверяли содержимое писем, а не только отсутствие ошибки Send.
+//
+// Использование:
+//
+//	server := mailtest.StartMailServer(t) // пропускает тест в режиме -short
+//	sender := smtp.NewSender(server.Config())
+//	defer sender.Close()
+//
+//	err := sender.Send(ctx, email)
+//	require.NoError(t, err)
+//
+//	m, err := server.WaitForSubject(ctx, email.Subject)
+//	require.NoError(t, err)
+//	assert.ElementsMatch(t, []string{"to@example.com", "bcc@example.com"}, m.Recipients)
+//	assert.Equal(t, "Hello!", m.Email.Body)
+//
+// Один контейнер на пакет — в TestMain через [Start] и [Server.Terminate].
+//
+// [Message] содержит:
+//   - From и Recipients — адреса SMTP-конверта (MAIL FROM, RCPT TO), включая Bcc
+//   - Raw — письмо в исходном виде
+//   - Email — письмо, разобранное mail/parse: заголовки, Body, HTML, вложения
+//
+// [Client] работает с любым MailHog по адресу API ([NewClient]):
+// [Client.Messages], [Client.WaitFor], [Client.WaitForSubject], [Client.DeleteAll].
+package mailtest
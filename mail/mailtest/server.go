@@ -0,0 +1,118 @@
+package mailtest
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/pure-golang/adapters/mail/smtp"
+)
+
+// DefaultImage is the MailHog image started by Start.
+const DefaultImage = "mailhog/mailhog:latest"
+
+// startupTimeout bounds container start and readiness checks.
+const startupTimeout = time.Minute
+
+// Server is a running MailHog container.
+type Server struct {
+	*Client
+
+	container testcontainers.Container
+	host      string
+	smtpPort  int
+}
+
+// StartMailServer starts MailHog for the test and terminates it when the test finishes.
+// The test is skipped in -short mode.
+func StartMailServer(t testing.TB) *Server {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s, err := Start(context.Background())
+	if err != nil {
+		t.Fatalf("failed to start mail server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := s.Terminate(context.Background()); err != nil {
+			t.Logf("failed to terminate mail server: %v", err)
+		}
+	})
+	return s
+}
+
+// Start starts MailHog and waits until both SMTP and the HTTP API accept connections.
+// Use it in TestMain; stop the server with Terminate.
+func Start(ctx context.Context) (*Server, error) {
+	startCtx, cancel := context.WithTimeout(ctx, startupTimeout)
+	defer cancel()
+
+	container, err := testcontainers.GenericContainer(startCtx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        DefaultImage,
+			ExposedPorts: []string{"1025/tcp", "8025/tcp"},
+			WaitingFor: wait.ForAll(
+				wait.ForListeningPort("1025/tcp"),
+				wait.ForHTTP("/api/v2/messages").WithPort("8025/tcp"),
+			).WithDeadline(startupTimeout),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start mailhog container")
+	}
+
+	s := &Server{container: container}
+	if err := s.init(startCtx); err != nil {
+		_ = container.Terminate(context.Background())
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) init(ctx context.Context) error {
+	host, err := s.container.Host(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get container host")
+	}
+	smtpPort, err := s.container.MappedPort(ctx, "1025")
+	if err != nil {
+		return errors.Wrap(err, "failed to get smtp port")
+	}
+	apiPort, err := s.container.MappedPort(ctx, "8025")
+	if err != nil {
+		return errors.Wrap(err, "failed to get api port")
+	}
+
+	s.host = host
+	s.smtpPort, err = strconv.Atoi(smtpPort.Port())
+	if err != nil {
+		return errors.Wrap(err, "failed to parse smtp port")
+	}
+	s.Client = NewClient("http://" + net.JoinHostPort(host, apiPort.Port()))
+	return nil
+}
+
+// Config returns the SMTP adapter configuration for the server: no TLS, no authentication.
+func (s *Server) Config() smtp.Config {
+	return smtp.Config{
+		Host: s.host,
+		Port: s.smtpPort,
+	}
+}
+
+// Terminate stops the container.
+func (s *Server) Terminate(ctx context.Context) error {
+	if err := s.container.Terminate(ctx); err != nil {
+		return errors.Wrap(err, "failed to terminate mailhog container")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// BuildMessage renders the email as a raw RFC 5322 message, as it is sent over SMTP:
+// multipart/alternative for HTML bodies and multipart/mixed with base64 parts for attachments.
+// Bcc recipients are not included in the headers.
+func BuildMessage(email *Email) []byte {
+	var msg strings.Builder
+
+	// Headers
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", email.From.String()))
+
+	if len(email.To) > 0 {
+		msg.WriteString(fmt.Sprintf("To: %s\r\n", FormatAddressList(email.To)))
+	}
+
+	if len(email.Cc) > 0 {
+		msg.WriteString(fmt.Sprintf("Cc: %s\r\n", FormatAddressList(email.Cc)))
+	}
+
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+
+	// Add custom headers
+	for k, v := range email.Headers {
+		msg.WriteString(fmt.Sprintf("%s: %s\r\n", k, v))
+	}
+
+	// Build body
+	if len(email.Attachments) == 0 {
+		writeBody(&msg, email)
+		return []byte(msg.String())
+	}
+
+	mixedBoundary := fmt.Sprintf("mixed_%d", time.Now().UnixNano())
+	msg.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n", mixedBoundary))
+	msg.WriteString("\r\n")
+
+	msg.WriteString(fmt.Sprintf("--%s\r\n", mixedBoundary))
+	writeBody(&msg, email)
+
+	for _, attachment := range email.Attachments {
+		msg.WriteString(fmt.Sprintf("--%s\r\n", mixedBoundary))
+		writeAttachment(&msg, attachment)
+	}
+	msg.WriteString(fmt.Sprintf("--%s--\r\n", mixedBoundary))
+
+	return []byte(msg.String())
+}
+
+// writeBody writes the Content-Type header and the text/HTML body.
+func writeBody(msg *strings.Builder, email *Email) {
+	if email.HTML != "" {
+		boundary := fmt.Sprintf("boundary_%d", time.Now().UnixNano())
+		msg.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%s\r\n", boundary))
+		msg.WriteString("\r\n")
+
+		// Plain text part
+		msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(email.Body)
+		msg.WriteString("\r\n")
+
+		// HTML part
+		msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+		msg.WriteString(email.HTML)
+		msg.WriteString("\r\n")
+
+		msg.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+		return
+	}
+
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(email.Body)
+	msg.WriteString("\r\n")
+}
+
+// writeAttachment writes a base64-encoded attachment part.
+func writeAttachment(msg *strings.Builder, attachment Attachment) {
+	disposition := "attachment"
+	if attachment.Inline {
+		disposition = "inline"
+	}
+
+	msg.WriteString(fmt.Sprintf("Content-Type: %s\r\n", mime.FormatMediaType(contentType(attachment), map[string]string{"name": attachment.Filename})))
+	msg.WriteString(fmt.Sprintf("Content-Disposition: %s\r\n", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename})))
+	if attachment.ContentID != "" {
+		msg.WriteString(fmt.Sprintf("Content-ID: <%s>\r\n", attachment.ContentID))
+	}
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	// RFC 2045: lines of base64 data must not exceed 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76])
+		msg.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded)
+	msg.WriteString("\r\n")
+}
+
+// String formats the address for a message header: "Name <address>" or the bare address.
+func (a Address) String() string {
+	if a.Name != "" {
+		// Escape quotes in name
+		escapedName := strings.ReplaceAll(a.Name, "\"", "\\\"")
+		return fmt.Sprintf("%s <%s>", escapedName, a.Address)
+	}
+	return a.Address
+}
+
+// FormatAddressList formats a list of addresses for a message header.
+func FormatAddressList(addrs []Address) string {
+	formatted := make([]string, len(addrs))
+	for i, addr := range addrs {
+		formatted[i] = addr.String()
+	}
+	return strings.Join(formatted, ", ")
+}
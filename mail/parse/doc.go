@@ -0,0 +1,22 @@
+// Package parse разбирает входящие письма в формате RFC 5322 в [mail.Email].
+//
+// Предназначен для обработки входящей почты через webhook'и (SES, SendGrid Inbound Parse
+// и т.п.), чтобы входящие и исходящие письма описывались одними и теми же типами.
+//
+// Использование:
+//
+//	email, err := parse.Parse(r.Body)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, attachment := range email.Attachments {
+//	    // attachment.Filename, attachment.ContentType, attachment.Data
+//	}
+//
+// Особенности:
+//   - Subject, имена в адресах и имена файлов декодируются из RFC 2047 / RFC 2231
+//   - Тела частей декодируются из base64 и quoted-printable
+//   - Текст перекодируется в UTF-8 из указанной в charset кодировки (windows-1251, koi8-r и др.)
+//   - Первая часть text/plain — Body, первая часть text/html — HTML, остальные — Attachments
+//   - Вложенность multipart ограничена 10 уровнями
+package parse
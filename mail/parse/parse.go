@@ -0,0 +1,222 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+// maxDepth ограничивает вложенность multipart-частей
+const maxDepth = 10
+
+// structuralHeaders не попадают в [mail.Email.Headers]: они разбираются в отдельные поля
+// или описывают MIME-структуру исходного письма
+var structuralHeaders = map[string]struct{}{
+	"From":                      {},
+	"To":                        {},
+	"Cc":                        {},
+	"Bcc":                       {},
+	"Subject":                   {},
+	"Mime-Version":              {},
+	"Content-Type":              {},
+	"Content-Transfer-Encoding": {},
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse разбирает письмо в формате RFC 5322 в [mail.Email].
+//
+// Заголовки From/To/Cc/Bcc и Subject декодируются (RFC 2047) в соответствующие поля,
+// остальные заголовки попадают в Headers (первое значение каждого заголовка).
+// Первая текстовая часть text/plain становится Body, первая text/html — HTML;
+// остальные части, а также части с Content-Disposition: attachment — Attachments.
+// Тела частей декодируются из base64/quoted-printable и перекодируются в UTF-8.
+func Parse(r io.Reader) (*mail.Email, error) {
+	msg, err := netmail.ReadMessage(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read message")
+	}
+
+	email := &mail.Email{
+		Subject: decodeHeader(msg.Header.Get("Subject")),
+	}
+
+	if email.From, err = parseAddress(msg.Header, "From"); err != nil {
+		return nil, err
+	}
+	if email.To, err = parseAddressList(msg.Header, "To"); err != nil {
+		return nil, err
+	}
+	if email.Cc, err = parseAddressList(msg.Header, "Cc"); err != nil {
+		return nil, err
+	}
+	if email.Bcc, err = parseAddressList(msg.Header, "Bcc"); err != nil {
+		return nil, err
+	}
+
+	for key, values := range msg.Header {
+		if _, ok := structuralHeaders[key]; ok || len(values) == 0 {
+			continue
+		}
+		if email.Headers == nil {
+			email.Headers = make(map[string]string)
+		}
+		email.Headers[key] = decodeHeader(values[0])
+	}
+
+	if err := walkPart(email, textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return nil, err
+	}
+
+	return email, nil
+}
+
+// walkPart рекурсивно обходит MIME-части и раскладывает их по полям email
+func walkPart(email *mail.Email, header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxDepth {
+		return errors.Errorf("mime nesting exceeds %d levels", maxDepth)
+	}
+
+	mediaType, params := "text/plain", map[string]string{}
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		var err error
+		mediaType, params, err = mime.ParseMediaType(contentType)
+		// ErrInvalidMediaParameter: тип распознан, некорректные параметры пропускаем
+		if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+			return errors.Wrapf(err, "failed to parse content type %q", contentType)
+		}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return errors.Wrap(err, "failed to read multipart part")
+			}
+			if err := walkPart(email, part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode %s part", mediaType)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	filename = decodeHeader(filename)
+
+	isBody := disposition != "attachment" && filename == ""
+	switch {
+	case isBody && mediaType == "text/plain" && email.Body == "":
+		email.Body = decodeCharset(params["charset"], data)
+		return nil
+	case isBody && mediaType == "text/html" && email.HTML == "":
+		email.HTML = decodeCharset(params["charset"], data)
+		return nil
+	}
+
+	email.Attachments = append(email.Attachments, mail.Attachment{
+		Filename:    filename,
+		ContentType: mediaType,
+		ContentID:   strings.Trim(header.Get("Content-Id"), "<>"),
+		Inline:      disposition == "inline",
+		Data:        data,
+	})
+	return nil
+}
+
+// decodeTransfer оборачивает body декодером Content-Transfer-Encoding.
+// multipart.Reader сам декодирует quoted-printable и удаляет заголовок,
+// поэтому здесь это нужно только для тела письма без multipart.
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// decodeCharset перекодирует текст в UTF-8; при неизвестной кодировке возвращает данные как есть
+func decodeCharset(charset string, data []byte) string {
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+		return string(data)
+	}
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return string(data)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+// charsetReader возвращает reader, перекодирующий input из charset в UTF-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unsupported charset %q", charset)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// decodeHeader декодирует encoded-words (RFC 2047); при ошибке возвращает значение как есть
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func parseAddress(header netmail.Header, key string) (mail.Address, error) {
+	list, err := parseAddressList(header, key)
+	if err != nil || len(list) == 0 {
+		return mail.Address{}, err
+	}
+	return list[0], nil
+}
+
+func parseAddressList(header netmail.Header, key string) ([]mail.Address, error) {
+	value := header.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parser := netmail.AddressParser{WordDecoder: wordDecoder}
+	parsed, err := parser.ParseList(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s header", key)
+	}
+
+	addresses := make([]mail.Address, 0, len(parsed))
+	for _, addr := range parsed {
+		addresses = append(addresses, mail.Address{Name: addr.Name, Address: addr.Address})
+	}
+	return addresses, nil
+}
@@ -0,0 +1,187 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+func crlf(s string) string {
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+func TestParse_PlainText(t *testing.T) {
+	t.Parallel()
+	raw := crlf(`From: "John Doe" <john@example.com>
+To: alice@example.com, Bob <bob@example.com>
+Cc: carol@example.com
+Subject: Hello
+Message-ID: <123@example.com>
+X-Custom: value
+
+Hello, world!
+`)
+
+	email, err := Parse(strings.NewReader(raw))
+
+	require.NoError(t, err)
+	assert.Equal(t, mail.Address{Name: "John Doe", Address: "john@example.com"}, email.From)
+	assert.Equal(t, []mail.Address{
+		{Address: "alice@example.com"},
+		{Name: "Bob", Address: "bob@example.com"},
+	}, email.To)
+	assert.Equal(t, []mail.Address{{Address: "carol@example.com"}}, email.Cc)
+	assert.Empty(t, email.Bcc)
+	assert.Equal(t, "Hello", email.Subject)
+	assert.Equal(t, "Hello, world!\r\n", email.Body)
+	assert.Empty(t, email.HTML)
+	assert.Equal(t, "value", email.Headers["X-Custom"])
+	assert.Equal(t, "<123@example.com>", email.Headers["Message-Id"])
+	assert.NotContains(t, email.Headers, "Subject")
+	assert.NotContains(t, email.Headers, "From")
+}
+
+func TestParse_EncodedHeaders(t *testing.T) {
+	t.Parallel()
+	raw := crlf(`From: =?UTF-8?B?0JjQstCw0L0=?= <ivan@example.com>
+To: user@example.com
+Subject: =?UTF-8?B?0J/RgNC40LLQtdGC?=
+Content-Type: text/plain; charset=koi8-r
+Content-Transfer-Encoding: base64
+
+8NLJ18XU
+`)
+
+	email, err := Parse(strings.NewReader(raw))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Иван", email.From.Name)
+	assert.Equal(t, "Привет", email.Subject)
+	assert.Equal(t, "Привет", email.Body)
+}
+
+func TestParse_MultipartWithAttachments(t *testing.T) {
+	t.Parallel()
+	raw := crlf(`From: sender@example.com
+To: recipient@example.com
+Subject: Report
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="mixed"
+
+--mixed
+Content-Type: multipart/alternative; boundary="alt"
+
+--alt
+Content-Type: text/plain; charset=UTF-8
+Content-Transfer-Encoding: quoted-printable
+
+See attached =E2=80=94 report
+--alt
+Content-Type: text/html; charset=UTF-8
+
+<p>See attached</p>
+--alt--
+--mixed
+Content-Type: text/csv; name="report.csv"
+Content-Disposition: attachment; filename="report.csv"
+Content-Transfer-Encoding: base64
+
+aWQsbmFtZQoxLHRlc3QK
+--mixed
+Content-Type: image/png
+Content-Disposition: inline; filename*=UTF-8''%D0%BB%D0%BE%D0%B3%D0%BE.png
+Content-ID: <logo>
+Content-Transfer-Encoding: base64
+
+iVBORw==
+--mixed--
+`)
+
+	email, err := Parse(strings.NewReader(raw))
+
+	require.NoError(t, err)
+	assert.Equal(t, "See attached — report", email.Body)
+	assert.Equal(t, "<p>See attached</p>", email.HTML)
+	require.Len(t, email.Attachments, 2)
+
+	csv := email.Attachments[0]
+	assert.Equal(t, "report.csv", csv.Filename)
+	assert.Equal(t, "text/csv", csv.ContentType)
+	assert.False(t, csv.Inline)
+	assert.Equal(t, "id,name\n1,test\n", string(csv.Data))
+
+	logo := email.Attachments[1]
+	assert.Equal(t, "лого.png", logo.Filename)
+	assert.Equal(t, "image/png", logo.ContentType)
+	assert.Equal(t, "logo", logo.ContentID)
+	assert.True(t, logo.Inline)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, logo.Data)
+}
+
+func TestParse_TextAttachment(t *testing.T) {
+	t.Parallel()
+	raw := crlf(`From: sender@example.com
+Content-Type: multipart/mixed; boundary=b
+
+--b
+Content-Type: text/plain
+
+Body
+--b
+Content-Type: text/plain
+Content-Disposition: attachment; filename=notes.txt
+
+Notes
+--b--
+`)
+
+	email, err := Parse(strings.NewReader(raw))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Body", email.Body)
+	require.Len(t, email.Attachments, 1)
+	assert.Equal(t, "notes.txt", email.Attachments[0].Filename)
+	assert.Equal(t, "Notes", string(email.Attachments[0].Data))
+}
+
+func TestParse_InvalidAddress(t *testing.T) {
+	t.Parallel()
+	raw := crlf(`From: not an address
+Subject: Test
+
+Body
+`)
+
+	_, err := Parse(strings.NewReader(raw))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "From")
+}
+
+func TestParse_InvalidMessage(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse(strings.NewReader("not a message"))
+
+	require.Error(t, err)
+}
+
+func TestParse_NestingLimit(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	sb.WriteString("From: sender@example.com\r\n")
+	for i := 0; i <= maxDepth+1; i++ {
+		fmt.Fprintf(&sb, "Content-Type: multipart/mixed; boundary=b%d\r\n\r\n--b%d\r\n", i, i)
+	}
+	sb.WriteString("\r\nBody\r\n")
+
+	_, err := Parse(strings.NewReader(sb.String()))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nesting")
+}
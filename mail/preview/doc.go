@@ -0,0 +1,28 @@
+// Package preview реализует [mail.Sender], который вместо отправки сохраняет письма как .eml файлы —
+// для staging-окружений и проверки шаблонов.
+//
+// Письмо рендерится [mail.BuildMessage] так же, как его отправил бы mail/smtp, и сохраняется в [Store]:
+//   - [DirStore] — файлы в каталоге
+//   - [StorageStore] — объекты в bucket [storage.Storage] (MinIO, S3)
+//
+// [NewHandler] возвращает HTTP обработчик для просмотра писем: список последних писем,
+// страница письма с заголовками, HTML (в sandbox iframe), текстом и вложениями, скачивание .eml.
+//
+// Использование:
+//
+//	store := preview.NewStorageStore(minioStorage, "mail-preview", "staging/")
+//	var sender mail.Sender = preview.NewSender(store,
+//	    preview.WithRecipientFilter(mail.SyntaxFilter()),
+//	)
+//	defer sender.Close()
+//
+//	mux.Handle("/debug/mail/", http.StripPrefix("/debug/mail", preview.NewHandler(store, nil)))
+//
+// Особенности:
+//   - Имя письма — время записи (UTC, с наносекундами) и случайный суффикс: "20260102T150405.000000000Z-1a2b3c4d.eml";
+//     имена сортируются в порядке записи, открываются только имена этого формата
+//   - DirStore пишет во временный файл и переименовывает его, список не содержит недописанных писем
+//   - Если у письма нет заголовка Message-ID, он генерируется; Bcc в файл не попадает
+//   - Фильтры получателей и хуки [WithAfterSend] работают как в mail/smtp
+//   - Обработчик не проверяет доступ: публикуйте его только в staging и dev окружениях
+package preview
@@ -0,0 +1,210 @@
+package preview
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/logger"
+	"github.com/pure-golang/adapters/mail"
+	"github.com/pure-golang/adapters/mail/parse"
+)
+
+// DefaultIndexLimit is the default number of the newest messages shown on the index page.
+const DefaultIndexLimit = 100
+
+// maxMessageSize limits the size of a message parsed for the index and view pages.
+const maxMessageSize = 32 << 20
+
+// HandlerOptions configures NewHandler.
+type HandlerOptions struct {
+	// IndexLimit is the number of the newest messages on the index page, DefaultIndexLimit if zero.
+	// Every listed message is read to show its headers.
+	IndexLimit int
+}
+
+// NewHandler returns a handler to browse messages of the store:
+//
+//	GET /             index of the newest messages: time, From, To, Subject
+//	GET /view/{name}  headers, text and HTML bodies and attachments of a message
+//	GET /raw/{name}   raw .eml message to open in a mail client
+//
+// Mount it under a prefix with http.StripPrefix, e.g. mux.Handle("/mail/", http.StripPrefix("/mail", h)).
+// The handler has no authentication: expose it only in staging and development environments.
+func NewHandler(store Store, opts *HandlerOptions) http.Handler {
+	limit := DefaultIndexLimit
+	if opts != nil && opts.IndexLimit > 0 {
+		limit = opts.IndexLimit
+	}
+	h := &handler{store: store, limit: limit}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.index)
+	mux.HandleFunc("GET /view/{name}", h.view)
+	mux.HandleFunc("GET /raw/{name}", h.raw)
+	return mux
+}
+
+type handler struct {
+	store Store
+	limit int
+}
+
+// indexEntry is a row of the index page.
+type indexEntry struct {
+	Name    string
+	Time    time.Time
+	From    string
+	To      string
+	Subject string
+	Err     string
+}
+
+func (h *handler) index(w http.ResponseWriter, r *http.Request) {
+	names, err := h.store.List(r.Context())
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	slices.Sort(names)
+	slices.Reverse(names)
+	total := len(names)
+	names = names[:min(total, h.limit)]
+
+	entries := make([]indexEntry, 0, len(names))
+	for _, name := range names {
+		entry := indexEntry{Name: name}
+		entry.Time, _ = MessageTime(name)
+		email, err := h.load(r, name)
+		if err != nil {
+			entry.Err = err.Error()
+		} else {
+			entry.From = email.From.String()
+			entry.To = mail.FormatAddressList(slices.Concat(email.To, email.Cc))
+			entry.Subject = email.Subject
+		}
+		entries = append(entries, entry)
+	}
+
+	h.render(w, r, indexTemplate, map[string]any{
+		"Entries": entries,
+		"Total":   total,
+	})
+}
+
+func (h *handler) view(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	email, err := h.load(r, name)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	t, _ := MessageTime(name)
+	h.render(w, r, viewTemplate, map[string]any{
+		"Name":  name,
+		"Time":  t,
+		"Email": email,
+		"To":    mail.FormatAddressList(email.To),
+		"Cc":    mail.FormatAddressList(email.Cc),
+	})
+}
+
+func (h *handler) raw(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	body, err := h.store.Open(r.Context(), name)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	w.Header().Set("Content-Type", "message/rfc822")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	if _, err := io.Copy(w, body); err != nil {
+		logger.FromContext(r.Context()).Warn("failed to write preview message", slog.String("name", name), slog.Any("error", err))
+	}
+}
+
+// load reads and parses a message.
+func (h *handler) load(r *http.Request, name string) (*mail.Email, error) {
+	body, err := h.store.Open(r.Context(), name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	email, err := parse.Parse(io.LimitReader(body, maxMessageSize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse message %s", name)
+	}
+	return email, nil
+}
+
+// render executes the page template into a buffer, so a template error does not produce a partial page.
+func (h *handler) render(w http.ResponseWriter, r *http.Request, tmpl *template.Template, data any) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		h.fail(w, r, errors.Wrap(err, "failed to render page"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+}
+
+// fail responds with 404 for ErrNotFound and 500 otherwise.
+func (h *handler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "message not found", http.StatusNotFound)
+		return
+	}
+	logger.FromContext(r.Context()).Error("mail preview request failed", slog.String("path", r.URL.Path), slog.Any("error", err))
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+const pageStyle = `<style>
+body { font-family: sans-serif; margin: 1.5em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .3em .6em; border-bottom: 1px solid #ddd; vertical-align: top; }
+pre { white-space: pre-wrap; background: #f6f6f6; padding: 1em; }
+iframe { width: 100%; height: 70vh; border: 1px solid #ddd; }
+.error { color: #b00; }
+</style>`
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Mail preview</title>` + pageStyle + `</head><body>
+<h1>Mail preview</h1>
+<p>{{len .Entries}} of {{.Total}} messages, newest first.</p>
+<table>
+<tr><th>Time (UTC)</th><th>From</th><th>To</th><th>Subject</th><th></th></tr>
+{{range .Entries}}<tr>
+<td>{{.Time.Format "2006-01-02 15:04:05"}}</td>
+{{if .Err}}<td colspan="3" class="error">{{.Err}}</td>{{else}}<td>{{.From}}</td><td>{{.To}}</td><td><a href="view/{{.Name}}">{{or .Subject "(no subject)"}}</a></td>{{end}}
+<td><a href="raw/{{.Name}}">.eml</a></td>
+</tr>{{end}}
+</table>
+</body></html>`))
+
+var viewTemplate = template.Must(template.New("view").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Email.Subject}}</title>` + pageStyle + `</head><body>
+<p><a href="../">&larr; All messages</a> &middot; <a href="../raw/{{.Name}}">Download .eml</a></p>
+<table>
+<tr><th>Time (UTC)</th><td>{{.Time.Format "2006-01-02 15:04:05"}}</td></tr>
+<tr><th>From</th><td>{{.Email.From.String}}</td></tr>
+<tr><th>To</th><td>{{.To}}</td></tr>
+{{if .Cc}}<tr><th>Cc</th><td>{{.Cc}}</td></tr>{{end}}
+<tr><th>Subject</th><td>{{.Email.Subject}}</td></tr>
+{{range $k, $v := .Email.Headers}}<tr><th>{{$k}}</th><td>{{$v}}</td></tr>{{end}}
+</table>
+{{if .Email.HTML}}<h2>HTML</h2>
+<iframe sandbox srcdoc="{{.Email.HTML}}"></iframe>{{end}}
+{{if .Email.Body}}<h2>Text</h2>
+<pre>{{.Email.Body}}</pre>{{end}}
+{{if .Email.Attachments}}<h2>Attachments</h2>
+<ul>{{range .Email.Attachments}}<li>{{.Filename}} ({{.ContentType}}, {{len .Data}} bytes{{if .Inline}}, inline{{end}})</li>{{end}}</ul>{{end}}
+</body></html>`))
@@ -0,0 +1,67 @@
+package preview
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+// TestHandler tests the index, view and raw pages.
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	store := NewDirStore(t.TempDir())
+	sender := NewSender(store)
+	first := testEmail()
+	first.Subject = "First <script>"
+	first.Attachments = []mail.Attachment{{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF")}}
+	require.NoError(t, sender.Send(context.Background(), first))
+	second := testEmail()
+	second.Subject = "Second"
+	require.NoError(t, sender.Send(context.Background(), second))
+	names, err := store.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+
+	h := http.StripPrefix("/mail", NewHandler(store, &HandlerOptions{IndexLimit: 1}))
+
+	rec := get(t, h, "/mail/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "1 of 2 messages")
+	assert.Contains(t, rec.Body.String(), "Second", "the newest message is listed first")
+	assert.NotContains(t, rec.Body.String(), "First")
+
+	firstName := slices.Min(names)
+	rec = get(t, h, "/mail/view/"+firstName)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "First &lt;script&gt;")
+	assert.Contains(t, body, `srcdoc="&lt;p&gt;Thanks!&lt;/p&gt;"`)
+	assert.Contains(t, body, "invoice.pdf (application/pdf, 4 bytes)")
+	assert.Contains(t, body, "Shop &lt;noreply@shop.example&gt;")
+
+	rec = get(t, h, "/mail/raw/"+firstName)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "message/rfc822", rec.Header().Get("Content-Type"))
+	raw, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "Subject: First <script>\r\n")
+
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/mail/view/missing.eml").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/mail/raw/..%2Fsecret").Code)
+}
@@ -0,0 +1,150 @@
+package preview
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+var _ mail.Sender = (*Sender)(nil)
+
+// Sender implements mail.Sender by writing rendered messages to a Store instead of sending them.
+type Sender struct {
+	mu     sync.Mutex
+	store  Store
+	closed bool
+
+	filters    []mail.RecipientFilter
+	afterSend  []mail.AfterSendHook
+	domain     string
+	randSuffix func() string
+}
+
+// Option configures Sender.
+type Option func(*Sender)
+
+// WithRecipientFilter adds a filter applied to To, Cc and Bcc before the message is written,
+// as smtp.WithRecipientFilter does, so staging drops the same recipients as production.
+func WithRecipientFilter(filter mail.RecipientFilter) Option {
+	return func(s *Sender) {
+		s.filters = append(s.filters, filter)
+	}
+}
+
+// WithAfterSend adds a hook invoked after every email with its Message-ID and the write error.
+func WithAfterSend(hook mail.AfterSendHook) Option {
+	return func(s *Sender) {
+		s.afterSend = append(s.afterSend, hook)
+	}
+}
+
+// WithMessageIDDomain sets the domain of generated Message-ID headers
+// (default: the domain of the From address, or "preview.local").
+func WithMessageIDDomain(domain string) Option {
+	return func(s *Sender) {
+		s.domain = domain
+	}
+}
+
+// NewSender creates a Sender writing messages to store.
+func NewSender(store Store, opts ...Option) *Sender {
+	s := &Sender{
+		store:      store,
+		randSuffix: func() string { return randomHex(4) },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Send renders every email with mail.BuildMessage and writes it to the store as a separate .eml message.
+// Sending stops at the first error.
+func (s *Sender) Send(ctx context.Context, emails ...mail.Email) error {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return errors.New("sender is closed")
+	}
+
+	for i := range emails {
+		if err := s.send(ctx, emails[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send filters recipients, writes a single email and runs the after-send hooks.
+func (s *Sender) send(ctx context.Context, email mail.Email) error {
+	filtered, dropped, err := mail.FilterRecipients(ctx, email, s.filters...)
+	if err == nil && len(dropped) > 0 && len(filtered.To)+len(filtered.Cc)+len(filtered.Bcc) == 0 {
+		err = mail.ErrAllRecipientsFiltered
+	}
+
+	messageID := ""
+	if err == nil {
+		messageID = s.ensureMessageID(&filtered)
+		name := MessageName(time.Now(), s.randSuffix())
+		if saveErr := s.store.Save(ctx, name, mail.BuildMessage(&filtered)); saveErr != nil {
+			err = errors.Wrapf(saveErr, "failed to save message %s", name)
+		}
+	}
+
+	for _, hook := range s.afterSend {
+		hook(ctx, mail.SendResult{Email: filtered, MessageID: messageID, Dropped: dropped, Err: err})
+	}
+	return err
+}
+
+// ensureMessageID returns the Message-ID header of the email, generating one if it is missing.
+// The caller's Headers map is not modified.
+func (s *Sender) ensureMessageID(email *mail.Email) string {
+	for k, v := range email.Headers {
+		if strings.EqualFold(k, "Message-ID") {
+			return v
+		}
+	}
+
+	domain := s.domain
+	if domain == "" {
+		domain = "preview.local"
+		if _, d, ok := strings.Cut(email.From.Address, "@"); ok && d != "" {
+			domain = d
+		}
+	}
+	id := fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), randomHex(8), domain)
+
+	headers := maps.Clone(email.Headers)
+	if headers == nil {
+		headers = make(map[string]string, 1)
+	}
+	headers["Message-ID"] = id
+	email.Headers = headers
+	return id
+}
+
+// Close marks the sender as closed; the store is not closed.
+func (s *Sender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,130 @@
+package preview
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+	"github.com/pure-golang/adapters/mail/parse"
+	"github.com/pure-golang/adapters/storage/storagetest"
+)
+
+func testEmail() mail.Email {
+	return mail.Email{
+		From:    mail.Address{Name: "Shop", Address: "noreply@shop.example"},
+		To:      []mail.Address{{Address: "user@example.com"}},
+		Bcc:     []mail.Address{{Address: "audit@shop.example"}},
+		Subject: "Order confirmed",
+		Body:    "Thanks!",
+		HTML:    "<p>Thanks!</p>",
+	}
+}
+
+// readMessages parses all messages of the store.
+func readMessages(t *testing.T, store Store) []*mail.Email {
+	t.Helper()
+	names, err := store.List(context.Background())
+	require.NoError(t, err)
+	emails := make([]*mail.Email, 0, len(names))
+	for _, name := range names {
+		body, err := store.Open(context.Background(), name)
+		require.NoError(t, err)
+		email, err := parse.Parse(body)
+		require.NoError(t, body.Close())
+		require.NoError(t, err)
+		emails = append(emails, email)
+	}
+	return emails
+}
+
+// TestSender_DirStore tests that sent emails are written as .eml files.
+func TestSender_DirStore(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "outbox")
+	var results []mail.SendResult
+	sender := NewSender(NewDirStore(dir), WithAfterSend(func(_ context.Context, r mail.SendResult) {
+		results = append(results, r)
+	}))
+
+	require.NoError(t, sender.Send(context.Background(), testEmail(), testEmail()))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "temporary files must be removed")
+	for _, entry := range entries {
+		assert.Regexp(t, `^\d{8}T\d{6}\.\d{9}Z-[0-9a-f]{8}\.eml$`, entry.Name())
+	}
+
+	emails := readMessages(t, sender.store)
+	require.Len(t, emails, 2)
+	assert.Equal(t, "Order confirmed", emails[0].Subject)
+	assert.Equal(t, "Shop", emails[0].From.Name)
+	assert.Equal(t, "<p>Thanks!</p>", emails[0].HTML)
+	assert.Empty(t, emails[0].Bcc, "Bcc must not be rendered")
+
+	require.Len(t, results, 2)
+	assert.Regexp(t, `^<\d+\.[0-9a-f]+@shop\.example>$`, results[0].MessageID)
+	assert.Equal(t, results[0].MessageID, emails[0].Headers["Message-Id"])
+	assert.NoError(t, results[0].Err)
+}
+
+// TestSender_StorageStore tests that sent emails are written as objects under the prefix.
+func TestSender_StorageStore(t *testing.T) {
+	t.Parallel()
+	st := storagetest.NewMemoryStorage()
+	store := NewStorageStore(st, "mail", "staging/")
+	sender := NewSender(store)
+
+	require.NoError(t, sender.Send(context.Background(), testEmail()))
+
+	names, err := store.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+	_, info, ok := st.Object("mail", "staging/"+names[0])
+	require.True(t, ok)
+	assert.Equal(t, "message/rfc822", info.ContentType)
+	assert.Len(t, readMessages(t, store), 1)
+
+	_, err = store.Open(context.Background(), MessageName(time.Now(), "00"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestSender_Filters tests recipient filters and the closed state.
+func TestSender_Filters(t *testing.T) {
+	t.Parallel()
+	store := NewDirStore(t.TempDir())
+	sender := NewSender(store, WithRecipientFilter(mail.NewSuppressionList("user@example.com", "audit@shop.example")))
+
+	err := sender.Send(context.Background(), testEmail())
+	assert.ErrorIs(t, err, mail.ErrAllRecipientsFiltered)
+	assert.Empty(t, readMessages(t, store))
+
+	require.NoError(t, sender.Close())
+	assert.EqualError(t, sender.Send(context.Background(), testEmail()), "sender is closed")
+}
+
+// TestDirStore_Open tests that only generated names are opened.
+func TestDirStore_Open(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o600))
+	store := NewDirStore(dir)
+
+	for _, name := range []string{"secret.txt", "../secret.txt", MessageName(time.Now(), "abc")} {
+		_, err := store.Open(context.Background(), name)
+		assert.ErrorIs(t, err, ErrNotFound, name)
+	}
+	names, err := store.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	names, err = NewDirStore(filepath.Join(dir, "missing")).List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
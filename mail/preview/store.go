@@ -0,0 +1,195 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// Extension is the file extension of stored messages.
+const Extension = ".eml"
+
+// nameTimeLayout is the time layout of message names; names sort in the order messages were written.
+const nameTimeLayout = "20060102T150405.000000000Z"
+
+// ErrNotFound is returned by Store.Open for unknown or invalid message names.
+var ErrNotFound = errors.New("message not found")
+
+// namePattern matches names generated by MessageName.
+var namePattern = regexp.MustCompile(`^\d{8}T\d{6}\.\d{9}Z-[0-9a-f]+\.eml$`)
+
+// Store keeps rendered messages.
+type Store interface {
+	// Save writes the raw message under name.
+	Save(ctx context.Context, name string, msg []byte) error
+	// List returns the names of stored messages in any order.
+	List(ctx context.Context) ([]string, error)
+	// Open returns the raw message, or ErrNotFound.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// MessageName returns the name of a message written at t, e.g. "20260102T150405.000000000Z-1a2b3c4d.eml".
+func MessageName(t time.Time, suffix string) string {
+	return t.UTC().Format(nameTimeLayout) + "-" + suffix + Extension
+}
+
+// MessageTime returns the time encoded in a message name.
+func MessageTime(name string) (time.Time, bool) {
+	stamp, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(nameTimeLayout, stamp)
+	return t, err == nil
+}
+
+// validName reports whether name was generated by MessageName; other names,
+// including paths with separators, are never opened.
+func validName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// DirStore keeps messages as .eml files in a directory, which is created on the first Save.
+type DirStore struct {
+	dir string
+}
+
+var _ Store = (*DirStore)(nil)
+
+// NewDirStore creates a Store writing files to dir.
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+// Save writes the message to a temporary file and renames it, so List never returns partial messages.
+func (d *DirStore) Save(_ context.Context, name string, msg []byte) error {
+	if !validName(name) {
+		return errors.Errorf("invalid message name %q", name)
+	}
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return errors.Wrap(err, "failed to create preview directory")
+	}
+
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(msg); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write message")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write message")
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return errors.Wrap(err, "failed to set message permissions")
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(d.dir, name)); err != nil {
+		return errors.Wrap(err, "failed to rename message")
+	}
+	return nil
+}
+
+// List returns the names of .eml files in the directory; a missing directory has no messages.
+func (d *DirStore) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read preview directory")
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && validName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Open opens the message file.
+func (d *DirStore) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	if !validName(name) {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(filepath.Join(d.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open message")
+	}
+	return f, nil
+}
+
+// StorageStore keeps messages as objects "<prefix><name>" in a storage.Storage bucket.
+type StorageStore struct {
+	storage storage.Storage
+	bucket  string
+	prefix  string
+}
+
+var _ Store = (*StorageStore)(nil)
+
+// NewStorageStore creates a Store writing objects to bucket with the key prefix, e.g. "mail/staging/".
+func NewStorageStore(s storage.Storage, bucket, prefix string) *StorageStore {
+	return &StorageStore{storage: s, bucket: bucket, prefix: prefix}
+}
+
+// Save puts the message with Content-Type message/rfc822.
+func (s *StorageStore) Save(ctx context.Context, name string, msg []byte) error {
+	if !validName(name) {
+		return errors.Errorf("invalid message name %q", name)
+	}
+	err := s.storage.Put(ctx, s.bucket, s.prefix+name, bytes.NewReader(msg), &storage.PutOptions{
+		ContentType: "message/rfc822",
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to put message")
+	}
+	return nil
+}
+
+// List returns the names of objects under the prefix.
+func (s *StorageStore) List(ctx context.Context) ([]string, error) {
+	result, err := s.storage.List(ctx, s.bucket, &storage.ListOptions{Prefix: s.prefix})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list messages")
+	}
+
+	names := make([]string, 0, len(result.Objects))
+	for _, obj := range result.Objects {
+		if name := strings.TrimPrefix(obj.Key, s.prefix); validName(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Open gets the message object.
+func (s *StorageStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if !validName(name) {
+		return nil, ErrNotFound
+	}
+	body, _, err := s.storage.Get(ctx, s.bucket, s.prefix+name)
+	if storage.IsNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get message")
+	}
+	return body, nil
+}
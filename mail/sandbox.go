@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Headers set by Sandbox on redirected emails with the original recipients.
+const (
+	HeaderOriginalTo  = "X-Original-To"
+	HeaderOriginalCc  = "X-Original-Cc"
+	HeaderOriginalBcc = "X-Original-Bcc"
+)
+
+// Sandbox protects non-production environments from mailing real recipients.
+// The zero value is disabled.
+//
+// With RedirectTo, recipients are replaced with the safe address and the originals are kept
+// in X-Original-To, X-Original-Cc and X-Original-Bcc headers. With AllowedDomains, recipients
+// in the listed domains are delivered as is; the others are redirected if RedirectTo is set
+// and dropped otherwise.
+type Sandbox struct {
+	RedirectTo     string   // Safe address receiving all redirected emails, e.g. "qa@example.com"
+	AllowedDomains []string // Recipient domains delivered as is, compared case-insensitively; subdomains are not included
+}
+
+// Enabled reports whether the sandbox changes recipients.
+func (s Sandbox) Enabled() bool {
+	return s.RedirectTo != "" || len(s.AllowedDomains) > 0
+}
+
+// Apply returns a copy of the email with sandboxed recipients and the dropped addresses.
+// Redirected To, Cc and Bcc recipients are replaced with a single RedirectTo address in To.
+// The caller's Headers map is not modified.
+func (s Sandbox) Apply(email Email) (Email, []Address) {
+	if !s.Enabled() {
+		return email, nil
+	}
+
+	var redirected, dropped []Address
+	originals := make(map[string][]Address)
+	split := func(header string, addrs []Address) []Address {
+		if addrs == nil {
+			return nil
+		}
+		kept := make([]Address, 0, len(addrs))
+		for _, addr := range addrs {
+			switch {
+			case s.allowed(addr):
+				kept = append(kept, addr)
+			case s.RedirectTo != "":
+				redirected = append(redirected, addr)
+				originals[header] = append(originals[header], addr)
+			default:
+				dropped = append(dropped, addr)
+			}
+		}
+		return kept
+	}
+
+	sandboxed := email
+	sandboxed.To = split(HeaderOriginalTo, email.To)
+	sandboxed.Cc = split(HeaderOriginalCc, email.Cc)
+	sandboxed.Bcc = split(HeaderOriginalBcc, email.Bcc)
+
+	if len(redirected) > 0 {
+		if !slices.ContainsFunc(sandboxed.To, func(a Address) bool { return strings.EqualFold(a.Address, s.RedirectTo) }) {
+			sandboxed.To = append(sandboxed.To, Address{Address: s.RedirectTo})
+		}
+		sandboxed.Headers = maps.Clone(email.Headers)
+		if sandboxed.Headers == nil {
+			sandboxed.Headers = make(map[string]string, len(originals))
+		}
+		for header, addrs := range originals {
+			sandboxed.Headers[header] = FormatAddressList(addrs)
+		}
+	}
+	return sandboxed, dropped
+}
+
+// allowed reports whether the recipient domain is in AllowedDomains.
+func (s Sandbox) allowed(addr Address) bool {
+	_, domain, ok := strings.Cut(addr.Address, "@")
+	if !ok {
+		return false
+	}
+	for _, d := range s.AllowedDomains {
+		if strings.EqualFold(domain, strings.TrimSpace(d)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandbox_Apply(t *testing.T) {
+	t.Parallel()
+	email := Email{
+		To:      []Address{{Address: "dev@Example.com"}, {Name: "Ann", Address: "ann@gmail.com"}},
+		Cc:      []Address{{Address: "bob@yahoo.com"}},
+		Bcc:     []Address{{Address: "audit@example.com"}},
+		Headers: map[string]string{"X-Campaign": "spring"},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		got, dropped := Sandbox{}.Apply(email)
+		assert.Equal(t, email, got)
+		assert.Empty(t, dropped)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		t.Parallel()
+		got, dropped := Sandbox{RedirectTo: "qa@example.com"}.Apply(email)
+		assert.Empty(t, dropped)
+		assert.Equal(t, []Address{{Address: "qa@example.com"}}, got.To)
+		assert.Empty(t, got.Cc)
+		assert.Empty(t, got.Bcc)
+		assert.Equal(t, map[string]string{
+			"X-Campaign":     "spring",
+			"X-Original-To":  "dev@Example.com, Ann <ann@gmail.com>",
+			"X-Original-Cc":  "bob@yahoo.com",
+			"X-Original-Bcc": "audit@example.com",
+		}, got.Headers)
+		assert.Equal(t, map[string]string{"X-Campaign": "spring"}, email.Headers, "caller headers are not modified")
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		t.Parallel()
+		got, dropped := Sandbox{AllowedDomains: []string{"example.com"}}.Apply(email)
+		assert.Equal(t, []Address{{Address: "dev@Example.com"}}, got.To)
+		assert.Empty(t, got.Cc)
+		assert.Equal(t, []Address{{Address: "audit@example.com"}}, got.Bcc)
+		assert.Equal(t, []Address{{Name: "Ann", Address: "ann@gmail.com"}, {Address: "bob@yahoo.com"}}, dropped)
+		assert.Equal(t, email.Headers, got.Headers)
+	})
+}
@@ -0,0 +1,79 @@
+package smtp
+
+import (
+	"net/smtp"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// Supported authentication methods.
+const (
+	AuthPlain   = "plain"   // AUTH PLAIN with Username/Password
+	AuthXOAUTH2 = "xoauth2" // AUTH XOAUTH2 with an OAuth2 access token (Gmail, Office365)
+)
+
+// WithTokenSource sets the OAuth2 token source used for XOAUTH2 authentication.
+// Tokens are cached and refreshed by the source when they expire.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(s *Sender) {
+		s.tokenSource = oauth2.ReuseTokenSource(nil, ts)
+	}
+}
+
+// auth returns smtp.Auth for the configured authentication method.
+// Returns nil if authentication is not configured.
+func (s *Sender) auth() (smtp.Auth, error) {
+	switch s.cfg.AuthMethod {
+	case "", AuthPlain:
+		if s.cfg.Username == "" {
+			return nil, nil
+		}
+		return smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host), nil
+	case AuthXOAUTH2:
+		if s.tokenSource == nil {
+			return nil, errors.New("token source is required for xoauth2 auth")
+		}
+		token, err := s.tokenSource.Token()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get oauth2 token")
+		}
+		return &xoauth2Auth{username: s.cfg.Username, token: token.AccessToken, host: s.cfg.Host}, nil
+	default:
+		return nil, errors.Errorf("unsupported auth method: %s", s.cfg.AuthMethod)
+	}
+}
+
+// xoauth2Auth implements smtp.Auth for the XOAUTH2 SASL mechanism.
+type xoauth2Auth struct {
+	username string
+	token    string
+	host     string
+}
+
+// Start builds the XOAUTH2 initial response.
+// Like smtp.PlainAuth, it refuses to send credentials over an unencrypted connection
+// unless the server is on localhost.
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	resp := "user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01"
+	return "XOAUTH2", []byte(resp), nil
+}
+
+// Next handles the server challenge. On failure the server sends a JSON error
+// description; an empty response is required to receive the final error status.
+func (a *xoauth2Auth) Next(_ []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+	return nil, nil
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
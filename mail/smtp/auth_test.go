@@ -0,0 +1,137 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+// countingTokenSource returns a new token on every call
+type countingTokenSource struct {
+	calls int
+	err   error
+}
+
+func (ts *countingTokenSource) Token() (*oauth2.Token, error) {
+	if ts.err != nil {
+		return nil, ts.err
+	}
+	ts.calls++
+	return &oauth2.Token{AccessToken: "token", TokenType: "Bearer"}, nil
+}
+
+func TestSender_Auth_Plain(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(Config{Host: "smtp.example.com", Username: "user", Password: "secret"})
+
+	auth, err := sender.auth()
+
+	require.NoError(t, err)
+	mech, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com", TLS: true})
+	require.NoError(t, err)
+	assert.Equal(t, "PLAIN", mech)
+}
+
+func TestSender_Auth_NoUsername(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(Config{Host: "smtp.example.com"})
+
+	auth, err := sender.auth()
+
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+}
+
+func TestSender_Auth_XOAUTH2(t *testing.T) {
+	t.Parallel()
+	ts := &countingTokenSource{}
+	sender := NewSender(
+		Config{Host: "smtp.gmail.com", Username: "user@gmail.com", AuthMethod: AuthXOAUTH2},
+		WithTokenSource(ts),
+	)
+
+	auth, err := sender.auth()
+
+	require.NoError(t, err)
+	mech, resp, err := auth.Start(&smtp.ServerInfo{Name: "smtp.gmail.com", TLS: true})
+	require.NoError(t, err)
+	assert.Equal(t, "XOAUTH2", mech)
+	assert.Equal(t, "user=user@gmail.com\x01auth=Bearer token\x01\x01", string(resp))
+
+	// valid token is reused
+	_, err = sender.auth()
+	require.NoError(t, err)
+	assert.Equal(t, 1, ts.calls)
+}
+
+func TestSender_Auth_XOAUTH2_NoTokenSource(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(Config{Host: "smtp.gmail.com", Username: "user@gmail.com", AuthMethod: AuthXOAUTH2})
+
+	_, err := sender.auth()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token source is required")
+}
+
+func TestSender_Auth_UnsupportedMethod(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(Config{Host: "smtp.example.com", Username: "user", AuthMethod: "cram-md5"})
+
+	_, err := sender.auth()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported auth method")
+}
+
+func TestSender_Send_TokenSourceError(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(
+		Config{Host: "127.0.0.1", Port: 1, Username: "user", AuthMethod: AuthXOAUTH2, MaxRetries: 3},
+		WithTokenSource(&countingTokenSource{err: errors.New("invalid_grant")}),
+	)
+
+	err := sender.Send(context.Background(), mail.Email{
+		From: mail.Address{Address: "sender@example.com"},
+		To:   []mail.Address{{Address: "recipient@example.com"}},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_grant")
+}
+
+func TestXOAUTH2Auth_Start_Unencrypted(t *testing.T) {
+	t.Parallel()
+	auth := &xoauth2Auth{username: "user", token: "token", host: "smtp.gmail.com"}
+
+	_, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.gmail.com"})
+	require.Error(t, err)
+
+	_, _, err = auth.Start(&smtp.ServerInfo{Name: "localhost"})
+	require.Error(t, err, "host name must match")
+
+	local := &xoauth2Auth{username: "user", token: "token", host: "localhost"}
+	_, _, err = local.Start(&smtp.ServerInfo{Name: "localhost"})
+	require.NoError(t, err)
+}
+
+func TestXOAUTH2Auth_Next(t *testing.T) {
+	t.Parallel()
+	auth := &xoauth2Auth{}
+
+	resp, err := auth.Next([]byte(`{"status":"401"}`), true)
+	require.NoError(t, err)
+	assert.Empty(t, resp)
+	assert.NotNil(t, resp, "empty response must be sent to get the final error")
+
+	resp, err = auth.Next(nil, false)
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
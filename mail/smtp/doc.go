@@ -4,7 +4,10 @@
 //   - plaintext SMTP
 //   - STARTTLS
 //   - TLS
+//   - аутентификация PLAIN и XOAUTH2 (Gmail, Office365)
+//   - HTML-письма и вложения (multipart/alternative, multipart/mixed)
 //   - OpenTelemetry tracing
+//   - OpenTelemetry метрики и slog-события отправки
 //
 // Использование:
 //
@@ -18,6 +21,79 @@
 //	})
 //	err = sender.Send(ctx, mail.Message{...})
 //
+// XOAUTH2: токены берутся из [golang.org/x/oauth2.TokenSource] и обновляются по истечении срока:
+//
+//	sender := smtp.NewSender(smtp.Config{
+//	    Host:       "smtp.gmail.com",
+//	    Username:   "user@gmail.com",
+//	    AuthMethod: smtp.AuthXOAUTH2,
+//	}, smtp.WithTokenSource(oauthCfg.TokenSource(ctx, refreshToken)))
+//
+// Метрики (MeterProvider задаётся через [WithMeterProvider], по умолчанию глобальный):
+//   - mail.smtp.sent_total — количество отправленных писем
+//   - mail.smtp.failed_total — количество писем, которые не удалось отправить
+//   - mail.smtp.send_duration_ms — длительность отправки с учётом повторов
+//   - mail.smtp.recipients — количество получателей последнего письма
+//
+// После каждой отправки пишется событие "email sent" (Info) или "email send failed" (Error)
+// с message_id, доменами получателей и количеством попыток, а также trace_id, span_id и request_id
+// из контекста (observability/correlate). Логгер задаётся через [WithLogger],
+// по умолчанию берётся из контекста. Если у письма нет заголовка Message-ID, он генерируется.
+//
+// Фильтры получателей ([WithRecipientFilter]) применяются к To, Cc и Bcc до подключения к серверу;
+// отброшенные адреса не получают письмо. Если отброшены все получатели, Send возвращает
+// [mail.ErrAllRecipientsFiltered]. Хуки [WithAfterSend] вызываются после каждого письма
+// с Message-ID, отброшенными адресами и ошибкой — например, для учёта bounce:
+//
+//	suppressed := mail.NewSuppressionList("bounced@example.com")
+//	sender := smtp.NewSender(cfg,
+//	    smtp.WithRecipientFilter(suppressed),
+//	    smtp.WithRecipientFilter(mail.SyntaxFilter()),
+//	    smtp.WithAfterSend(func(ctx context.Context, r mail.SendResult) {
+//	        store.SaveMessageID(ctx, r.MessageID, r.Email.To)
+//	    }),
+//	)
+//
+// Ограничение скорости по доменам получателей (token bucket на домен) защищает от ответов 421
+// при массовых рассылках: письмо ждёт токен каждого домена своих получателей, ожидание дополняется
+// случайной задержкой до ThrottleJitter. Пачка писем в Send переупорядочивается так, чтобы письма
+// в ограниченный домен не задерживали остальные; порядок писем одним и тем же доменам сохраняется.
+//
+//	sender := smtp.NewSender(smtp.Config{
+//	    Host:             "smtp.example.com",
+//	    DomainRateLimits: map[string]float64{"gmail.com": 10, "yahoo.com": 5},
+//	    DomainRateLimit:  20, // остальные домены
+//	    ThrottleJitter:   200 * time.Millisecond,
+//	})
+//
+// Режим песочницы ([mail.Sandbox]) защищает от отправки писем реальным клиентам из staging:
+// получатели заменяются безопасным адресом (исходные — в заголовках X-Original-To, X-Original-Cc
+// и X-Original-Bcc) или письма отправляются только в разрешённые домены. Песочница применяется
+// после фильтров получателей; задаётся переменными SMTP_SANDBOX_* или [WithSandbox]:
+//
+//	sender := smtp.NewSender(smtp.Config{
+//	    Host:                  "smtp.example.com",
+//	    SandboxRedirectTo:     "qa@example.com",
+//	    SandboxAllowedDomains: []string{"example.com"}, // сотрудники получают письма как есть
+//	})
+//
+// Ограничения писем ([mail.Limits]) и проверка вложений ([mail.AttachmentScanner]) выполняются после
+// песочницы, до подключения к серверу: письмо больше SMTP_MAX_MESSAGE_SIZE, с лишними вложениями,
+// вложением запрещённого типа или угрозой, найденной сканером, не отправляется, Send возвращает
+// [mail.RejectionError]. Задаются переменными окружения или [WithLimits] и [WithAttachmentScanner]:
+//
+//	sender := smtp.NewSender(smtp.Config{
+//	    Host:                   "smtp.example.com",
+//	    MaxMessageSize:         10 << 20,
+//	    MaxAttachments:         5,
+//	    AllowedAttachmentTypes: []string{"application/pdf", "image/*"},
+//	}, smtp.WithAttachmentScanner(clamav))
+//
+//	var rejection *mail.RejectionError
+//	if errors.As(err, &rejection) {
+//	    // rejection.Reason, rejection.Attachment, rejection.Detail
+//	}
+//
 // Конфигурация через переменные окружения:
 //
 //	SMTP_HOST     — хост SMTP-сервера
@@ -25,4 +101,15 @@
 //	SMTP_USERNAME — имя пользователя
 //	SMTP_PASSWORD — пароль
 //	SMTP_FROM     — адрес отправителя
+//	SMTP_AUTH_METHOD — метод аутентификации: plain или xoauth2 (default: plain)
+//	SMTP_DOMAIN_RATE_LIMITS — писем в секунду по доменам: "gmail.com:10,yahoo.com:5"
+//	SMTP_DOMAIN_RATE_LIMIT  — писем в секунду для остальных доменов (default: 0 — без ограничения)
+//	SMTP_DOMAIN_RATE_BURST  — писем в домен без ожидания (default: 1)
+//	SMTP_THROTTLE_JITTER    — максимальная случайная добавка к ожиданию (default: 0)
+//	SMTP_SANDBOX_REDIRECT_TO     — безопасный адрес, на который перенаправляются письма остальным получателям
+//	SMTP_SANDBOX_ALLOWED_DOMAINS — домены получателей без перенаправления: "example.com,example.org"
+//	SMTP_MAX_MESSAGE_SIZE         — максимальный размер письма в байтах с вложениями (default: 0 — без ограничения)
+//	SMTP_MAX_ATTACHMENTS          — максимальное число вложений (default: 0 — без ограничения)
+//	SMTP_ALLOWED_ATTACHMENT_TYPES — разрешённые типы вложений: "application/pdf,image/*"; пусто — любые
+//	SMTP_BLOCKED_EXTENSIONS       — запрещённые расширения вложений: ".exe,.js"
 package smtp
@@ -0,0 +1,57 @@
+package smtp
+
+import (
+	"context"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+// WithRecipientFilter adds a filter invoked for every recipient before sending,
+// e.g. mail.SuppressionList, mail.SyntaxFilter or mail.MXFilter.
+// Dropped recipients are removed from the email; if none are left,
+// Send returns mail.ErrAllRecipientsFiltered.
+func WithRecipientFilter(filter mail.RecipientFilter) Option {
+	return func(s *Sender) {
+		s.filters = append(s.filters, filter)
+	}
+}
+
+// WithAfterSend adds a hook invoked after every email, successful or not,
+// e.g. to record message IDs for bounce tracking.
+func WithAfterSend(hook mail.AfterSendHook) Option {
+	return func(s *Sender) {
+		s.afterSend = append(s.afterSend, hook)
+	}
+}
+
+// WithSandbox overrides the sandbox configured by SMTP_SANDBOX_* variables.
+// It is applied after recipient filters; recipients it drops are reported in mail.SendResult.Dropped.
+func WithSandbox(sandbox mail.Sandbox) Option {
+	return func(s *Sender) {
+		s.sandbox = sandbox
+	}
+}
+
+// WithLimits overrides the limits configured by SMTP_MAX_* and attachment type variables.
+// Emails exceeding them are not sent; Send returns a *mail.RejectionError.
+func WithLimits(limits mail.Limits) Option {
+	return func(s *Sender) {
+		s.limits = limits
+	}
+}
+
+// WithAttachmentScanner adds a scanner invoked for every attachment before sending, e.g. an antivirus.
+// Scanners run after limits are checked; a detected threat makes Send return a *mail.RejectionError
+// with mail.RejectAttachmentInfected, a scanner error aborts sending.
+func WithAttachmentScanner(scanner mail.AttachmentScanner) Option {
+	return func(s *Sender) {
+		s.scanners = append(s.scanners, scanner)
+	}
+}
+
+// runAfterSend invokes AfterSend hooks in the order they were added.
+func (s *Sender) runAfterSend(ctx context.Context, result mail.SendResult) {
+	for _, hook := range s.afterSend {
+		hook(ctx, result)
+	}
+}
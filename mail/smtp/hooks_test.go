@@ -0,0 +1,161 @@
+package smtp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+func TestSender_RecipientFilterAndAfterSend(t *testing.T) {
+	t.Parallel()
+	server := startMiniSMTPServer(t, 12561)
+	defer server.close()
+
+	var results []mail.SendResult
+	sender := NewSender(
+		Config{Host: "127.0.0.1", Port: 12561},
+		WithRecipientFilter(mail.NewSuppressionList("bounced@example.com")),
+		WithAfterSend(func(_ context.Context, result mail.SendResult) {
+			results = append(results, result)
+		}),
+	)
+	defer sender.Close()
+
+	err := sender.Send(context.Background(), mail.Email{
+		From:    mail.Address{Address: "sender@example.com"},
+		To:      []mail.Address{{Address: "ok@example.com"}, {Address: "bounced@example.com"}},
+		Subject: "Hello",
+		Body:    "Body",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, server.messages, 1)
+	msg := string(server.messages[0])
+	assert.Contains(t, msg, "ok@example.com")
+	assert.NotContains(t, msg, "bounced@example.com")
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, []mail.Address{{Address: "bounced@example.com"}}, results[0].Dropped)
+	assert.Equal(t, []mail.Address{{Address: "ok@example.com"}}, results[0].Email.To)
+	assert.True(t, strings.HasPrefix(results[0].MessageID, "<"))
+}
+
+func TestSender_AllRecipientsFiltered(t *testing.T) {
+	t.Parallel()
+	var results []mail.SendResult
+	sender := NewSender(
+		// no server is listening: filtering must happen before connecting
+		Config{Host: "127.0.0.1", Port: 1},
+		WithRecipientFilter(mail.NewSuppressionList("bounced@example.com")),
+		WithAfterSend(func(_ context.Context, result mail.SendResult) {
+			results = append(results, result)
+		}),
+	)
+
+	err := sender.Send(context.Background(), mail.Email{
+		From: mail.Address{Address: "sender@example.com"},
+		To:   []mail.Address{{Address: "bounced@example.com"}},
+	})
+
+	require.ErrorIs(t, err, mail.ErrAllRecipientsFiltered)
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].Err, mail.ErrAllRecipientsFiltered)
+}
+
+func TestSender_Sandbox(t *testing.T) {
+	t.Parallel()
+	server := startMiniSMTPServer(t, 12562)
+	defer server.close()
+
+	var results []mail.SendResult
+	sender := NewSender(
+		Config{
+			Host:                  "127.0.0.1",
+			Port:                  12562,
+			SandboxRedirectTo:     "qa@example.com",
+			SandboxAllowedDomains: []string{"example.com"},
+		},
+		WithAfterSend(func(_ context.Context, result mail.SendResult) {
+			results = append(results, result)
+		}),
+	)
+	defer sender.Close()
+
+	err := sender.Send(context.Background(), mail.Email{
+		From:    mail.Address{Address: "sender@example.com"},
+		To:      []mail.Address{{Address: "dev@example.com"}, {Name: "Customer", Address: "customer@gmail.com"}},
+		Subject: "Hello",
+		Body:    "Body",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, server.messages, 1)
+	msg := string(server.messages[0])
+	assert.Contains(t, msg, "X-Original-To: Customer <customer@gmail.com>")
+	require.Len(t, results, 1)
+	assert.Equal(t, []mail.Address{{Address: "dev@example.com"}, {Address: "qa@example.com"}}, results[0].Email.To)
+}
+
+func TestSender_SandboxAllowlist(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(
+		// no server is listening: the sandbox must drop recipients before connecting
+		Config{Host: "127.0.0.1", Port: 1},
+		WithSandbox(mail.Sandbox{AllowedDomains: []string{"example.com"}}),
+	)
+
+	err := sender.Send(context.Background(), mail.Email{
+		From: mail.Address{Address: "sender@example.com"},
+		To:   []mail.Address{{Address: "customer@gmail.com"}},
+	})
+	require.ErrorIs(t, err, mail.ErrAllRecipientsFiltered)
+}
+
+func TestSender_LimitsAndAttachmentScanner(t *testing.T) {
+	t.Parallel()
+	var results []mail.SendResult
+	var scanned int
+	sender := NewSender(
+		// no server is listening: rejected emails must not be delivered
+		Config{Host: "127.0.0.1", Port: 1, MaxAttachments: 1, BlockedExtensions: []string{".exe"}},
+		WithAttachmentScanner(mail.AttachmentScannerFunc(func(context.Context, mail.Attachment) (string, error) {
+			scanned++
+			return "Eicar-Test-Signature", nil
+		})),
+		WithAfterSend(func(_ context.Context, result mail.SendResult) {
+			results = append(results, result)
+		}),
+	)
+	email := mail.Email{
+		From: mail.Address{Address: "sender@example.com"},
+		To:   []mail.Address{{Address: "user@example.com"}},
+	}
+
+	tooMany := email
+	tooMany.Attachments = []mail.Attachment{{Filename: "a.txt"}, {Filename: "b.txt"}}
+	err := sender.Send(context.Background(), tooMany)
+	var rejection *mail.RejectionError
+	require.ErrorAs(t, err, &rejection)
+	assert.Equal(t, mail.RejectTooManyAttachments, rejection.Reason)
+	assert.Zero(t, scanned, "scanners run after limits are checked")
+
+	infected := email
+	infected.Attachments = []mail.Attachment{{Filename: "invoice.pdf", ContentType: "application/pdf"}}
+	err = sender.Send(context.Background(), infected)
+	require.ErrorAs(t, err, &rejection)
+	assert.Equal(t, mail.RejectAttachmentInfected, rejection.Reason)
+	assert.Equal(t, "Eicar-Test-Signature", rejection.Detail)
+
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[1].Err, mail.ErrRejected)
+
+	sender = NewSender(Config{Host: "127.0.0.1", Port: 1, MaxAttachments: 1}, WithLimits(mail.Limits{}))
+	err = sender.Send(context.Background(), tooMany)
+	assert.NotErrorIs(t, err, mail.ErrRejected, "WithLimits overrides the configured limits")
+}
@@ -0,0 +1,188 @@
+package smtp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/pure-golang/adapters/mail"
+	"github.com/pure-golang/adapters/observability/correlate"
+)
+
+const meterName = "github.com/pure-golang/adapters/mail/smtp"
+
+// WithLogger sets the logger for send events.
+// By default the logger from the context is used.
+// Either way records get trace_id, span_id and request_id from the context.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Sender) {
+		s.logger = l
+	}
+}
+
+// WithMeterProvider sets the MeterProvider for send metrics.
+// By default the global MeterProvider is used.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(s *Sender) {
+		s.meterProvider = provider
+	}
+}
+
+// senderMetrics contains OTel instruments of the Sender.
+type senderMetrics struct {
+	sent       metric.Int64Counter
+	failed     metric.Int64Counter
+	duration   metric.Int64Histogram
+	recipients metric.Int64Gauge
+}
+
+// newSenderMetrics creates instruments from the provider.
+// Falls back to no-op instruments if any of them cannot be created.
+func newSenderMetrics(provider metric.MeterProvider) *senderMetrics {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	m, err := createSenderMetrics(provider.Meter(meterName))
+	if err != nil {
+		otel.Handle(err)
+		m, _ = createSenderMetrics(noop.NewMeterProvider().Meter(meterName))
+	}
+	return m
+}
+
+func createSenderMetrics(meter metric.Meter) (*senderMetrics, error) {
+	var (
+		m   senderMetrics
+		err error
+	)
+
+	m.sent, err = meter.Int64Counter(
+		"mail.smtp.sent_total",
+		metric.WithDescription("Total number of emails sent"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.failed, err = meter.Int64Counter(
+		"mail.smtp.failed_total",
+		metric.WithDescription("Total number of emails that failed to send"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.duration, err = meter.Int64Histogram(
+		"mail.smtp.send_duration_ms",
+		metric.WithDescription("Email send duration in milliseconds, including retries"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.recipients, err = meter.Int64Gauge(
+		"mail.smtp.recipients",
+		metric.WithDescription("Number of recipients of the last sent email"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// observe records metrics and a log event for a single send.
+func (s *Sender) observe(ctx context.Context, email *mail.Email, messageID string, attempts int, duration time.Duration, err error) {
+	recipients := len(email.To) + len(email.Cc) + len(email.Bcc)
+	attrs := metric.WithAttributes(attribute.String("smtp.host", s.cfg.Host))
+
+	s.metrics.duration.Record(ctx, duration.Milliseconds(), attrs)
+	s.metrics.recipients.Record(ctx, int64(recipients), attrs)
+	if err != nil {
+		s.metrics.failed.Add(ctx, 1, attrs)
+	} else {
+		s.metrics.sent.Add(ctx, 1, attrs)
+	}
+
+	log := correlate.FromContext(ctx)
+	if s.logger != nil {
+		log = correlate.Logger(ctx, s.logger)
+	}
+	logAttrs := []slog.Attr{
+		slog.String("message_id", messageID),
+		slog.Any("recipient_domains", recipientDomains(email)),
+		slog.Int("recipients", recipients),
+		slog.Int("attempts", attempts),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	}
+	log = log.WithGroup("smtp")
+	if err != nil {
+		logAttrs = append(logAttrs, slog.Any("error", err))
+		log.LogAttrs(ctx, slog.LevelError, "email send failed", logAttrs...)
+		return
+	}
+	log.LogAttrs(ctx, slog.LevelInfo, "email sent", logAttrs...)
+}
+
+// ensureMessageID returns the Message-ID header of the email, generating one if it is missing.
+// The caller's Headers map is not modified.
+func (s *Sender) ensureMessageID(email *mail.Email) string {
+	for k, v := range email.Headers {
+		if strings.EqualFold(k, "Message-ID") {
+			return v
+		}
+	}
+
+	from := email.From.Address
+	if from == "" {
+		from = s.cfg.From
+	}
+	domain := s.cfg.Host
+	if _, d, ok := strings.Cut(from, "@"); ok && d != "" {
+		domain = d
+	}
+
+	id := fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), randomHex(8), domain)
+
+	headers := maps.Clone(email.Headers)
+	if headers == nil {
+		headers = make(map[string]string, 1)
+	}
+	headers["Message-ID"] = id
+	email.Headers = headers
+
+	return id
+}
+
+// recipientDomains returns sorted unique domains of all recipients.
+// Full addresses are not logged to avoid leaking personal data.
+func recipientDomains(email *mail.Email) []string {
+	domains := make([]string, 0, len(email.To)+len(email.Cc)+len(email.Bcc))
+	for _, list := range [][]mail.Address{email.To, email.Cc, email.Bcc} {
+		for _, addr := range list {
+			if _, domain, ok := strings.Cut(addr.Address, "@"); ok {
+				domains = append(domains, strings.ToLower(domain))
+			}
+		}
+	}
+	slices.Sort(domains)
+	return slices.Compact(domains)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,156 @@
+package smtp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+// recordingHandler collects log records for assertions
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
+
+// collectMetrics returns metrics by name from the reader
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	result := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m.Data
+		}
+	}
+	return result
+}
+
+func counterValue(t *testing.T, data metricdata.Aggregation) int64 {
+	t.Helper()
+	sum, ok := data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	var total int64
+	for _, dp := range sum.DataPoints {
+		total += dp.Value
+	}
+	return total
+}
+
+func TestSender_Metrics_Success(t *testing.T) {
+	t.Parallel()
+	server := startMiniSMTPServer(t, 12560)
+	defer server.close()
+
+	reader := sdkmetric.NewManualReader()
+	h := &recordingHandler{}
+	sender := NewSender(
+		Config{Host: "127.0.0.1", Port: 12560},
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithLogger(slog.New(h)),
+	)
+	defer sender.Close()
+
+	err := sender.Send(context.Background(), mail.Email{
+		From: mail.Address{Address: "sender@example.com"},
+		To:   []mail.Address{{Address: "a@example.com"}, {Address: "b@Example.com"}},
+		Bcc:  []mail.Address{{Address: "c@other.org"}},
+	})
+	require.NoError(t, err)
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), counterValue(t, metrics["mail.smtp.sent_total"]))
+	assert.NotContains(t, metrics, "mail.smtp.failed_total")
+	assert.Contains(t, metrics, "mail.smtp.send_duration_ms")
+
+	gauge, ok := metrics["mail.smtp.recipients"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, int64(3), gauge.DataPoints[0].Value)
+
+	require.Len(t, h.records, 1)
+	r := h.records[0]
+	assert.Equal(t, slog.LevelInfo, r.Level)
+	assert.Equal(t, "email sent", r.Message)
+	attrs := recordAttrs(r)
+	assert.Contains(t, attrs["message_id"].String(), "@example.com>")
+	assert.Equal(t, []string{"example.com", "other.org"}, attrs["recipient_domains"].Any())
+	assert.Equal(t, int64(1), attrs["attempts"].Int64())
+
+	require.Len(t, server.messages, 1)
+	assert.Contains(t, string(server.messages[0]), "Message-ID: "+attrs["message_id"].String())
+}
+
+func TestSender_Metrics_Failure(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	h := &recordingHandler{}
+	sender := NewSender(
+		Config{Host: "127.0.0.1", Port: 1, MaxRetries: 1},
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithLogger(slog.New(h)),
+	)
+
+	err := sender.Send(context.Background(), mail.Email{
+		From:    mail.Address{Address: "sender@example.com"},
+		To:      []mail.Address{{Address: "user@example.com"}},
+		Headers: map[string]string{"Message-Id": "<custom@example.com>"},
+	})
+	require.Error(t, err)
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), counterValue(t, metrics["mail.smtp.failed_total"]))
+	assert.NotContains(t, metrics, "mail.smtp.sent_total")
+
+	require.Len(t, h.records, 1)
+	r := h.records[0]
+	assert.Equal(t, slog.LevelError, r.Level)
+	assert.Equal(t, "email send failed", r.Message)
+	attrs := recordAttrs(r)
+	assert.Equal(t, "<custom@example.com>", attrs["message_id"].String())
+	assert.Contains(t, attrs, "error")
+}
+
+func TestSender_EnsureMessageID_DoesNotModifyCallerHeaders(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(Config{Host: "smtp.example.com"})
+	headers := map[string]string{"X-Custom": "value"}
+	email := mail.Email{Headers: headers}
+
+	id := sender.ensureMessageID(&email)
+
+	assert.Contains(t, id, "@smtp.example.com>")
+	assert.Equal(t, id, email.Headers["Message-ID"])
+	assert.NotContains(t, headers, "Message-ID")
+}
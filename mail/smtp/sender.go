@@ -4,16 +4,18 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/smtp"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
 
 	"github.com/pure-golang/adapters/mail"
 )
@@ -22,9 +24,19 @@ var _ mail.Sender = (*Sender)(nil)
 
 // Sender implements mail.Sender using net/smtp.
 type Sender struct {
-	mx     sync.Mutex
-	cfg    Config
-	closed bool
+	mx            sync.Mutex
+	cfg           Config
+	closed        bool
+	tokenSource   oauth2.TokenSource
+	logger        *slog.Logger
+	meterProvider metric.MeterProvider
+	metrics       *senderMetrics
+	filters       []mail.RecipientFilter
+	afterSend     []mail.AfterSendHook
+	throttle      *domainThrottle
+	sandbox       mail.Sandbox
+	limits        mail.Limits
+	scanners      []mail.AttachmentScanner
 }
 
 // Option определяет функцию для настройки Sender
@@ -33,8 +45,10 @@ type Option func(*Sender)
 // NewSender creates a new SMTP Sender.
 func NewSender(cfg Config, opts ...Option) *Sender {
 	s := &Sender{
-		cfg:    cfg,
-		closed: false,
+		cfg:     cfg,
+		closed:  false,
+		sandbox: cfg.Sandbox(),
+		limits:  cfg.Limits(),
 	}
 
 	// Применяем опции
@@ -42,12 +56,16 @@ func NewSender(cfg Config, opts ...Option) *Sender {
 		opt(s)
 	}
 
+	s.metrics = newSenderMetrics(s.meterProvider)
+	s.throttle = newDomainThrottle(cfg)
+
 	return s
 }
 
 // Send sends one or more emails.
+// With per-domain rate limits a batch is reordered so that throttled domains do not hold back the others.
 func (s *Sender) Send(ctx context.Context, emails ...mail.Email) error {
-	for _, email := range emails {
+	for _, email := range s.throttle.schedule(emails) {
 		if err := s.send(ctx, &email); err != nil {
 			return err
 		}
@@ -55,8 +73,47 @@ func (s *Sender) Send(ctx context.Context, emails ...mail.Email) error {
 	return nil
 }
 
-// send sends a single email.
+// send filters and sandboxes recipients, checks limits and scans attachments, sends a single email,
+// records metrics and log events and invokes AfterSend hooks.
 func (s *Sender) send(ctx context.Context, email *mail.Email) error {
+	start := time.Now()
+	messageID := s.ensureMessageID(email)
+
+	filtered, dropped, err := mail.FilterRecipients(ctx, *email, s.filters...)
+	if err == nil && s.sandbox.Enabled() {
+		var sandboxed []mail.Address
+		filtered, sandboxed = s.sandbox.Apply(filtered)
+		dropped = append(dropped, sandboxed...)
+		if len(filtered.To)+len(filtered.Cc)+len(filtered.Bcc) == 0 {
+			err = mail.ErrAllRecipientsFiltered
+		}
+	}
+	if err == nil {
+		err = s.limits.Check(filtered)
+	}
+	if err == nil {
+		err = mail.ScanAttachments(ctx, filtered, s.scanners...)
+	}
+	if err == nil {
+		err = s.waitThrottle(ctx, &filtered)
+	}
+	attempts := 0
+	if err == nil {
+		attempts, err = s.deliver(ctx, &filtered)
+	}
+
+	s.observe(ctx, &filtered, messageID, attempts, time.Since(start), err)
+	s.runAfterSend(ctx, mail.SendResult{
+		Email:     filtered,
+		MessageID: messageID,
+		Dropped:   dropped,
+		Err:       err,
+	})
+	return err
+}
+
+// deliver sends a single email with retries and returns the number of attempts made.
+func (s *Sender) deliver(ctx context.Context, email *mail.Email) (int, error) {
 	ctx, span := tracer.Start(ctx, "SMTP.Send", trace.WithSpanKind(trace.SpanKindClient))
 	defer span.End()
 
@@ -77,7 +134,7 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 
 	if s.closed {
 		span.SetStatus(codes.Error, "sender is closed")
-		return errors.New("sender is closed")
+		return 0, errors.New("sender is closed")
 	}
 
 	// Build email content
@@ -86,7 +143,7 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 		from = s.cfg.From
 	}
 	if from == "" {
-		return errors.New("no from address specified")
+		return 0, errors.New("no from address specified")
 	}
 
 	toAddresses := s.getEmailAddresses(email.To)
@@ -94,7 +151,7 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 	bccAddresses := s.getEmailAddresses(email.Bcc)
 
 	if len(toAddresses) == 0 && len(ccAddresses) == 0 && len(bccAddresses) == 0 {
-		return errors.New("no recipients specified")
+		return 0, errors.New("no recipients specified")
 	}
 
 	// Build message
@@ -103,11 +160,6 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 	// SMTP server address
 	addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
 
-	var auth smtp.Auth
-	if s.cfg.Username != "" {
-		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
-	}
-
 	allTo := make([]string, 0, len(toAddresses)+len(ccAddresses))
 	allTo = append(allTo, toAddresses...)
 	allTo = append(allTo, ccAddresses...)
@@ -120,6 +172,7 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 	span.SetAttributes(attribute.Int("smtp.max_retries", maxRetries))
 
 	var err error
+	attempts := 0
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
 			backoff := calcBackoff(attempt)
@@ -133,11 +186,21 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 			case <-ctx.Done():
 				span.RecordError(ctx.Err())
 				span.SetStatus(codes.Error, "context canceled during retry backoff")
-				return errors.Wrap(ctx.Err(), "context canceled during retry backoff")
+				return attempts, errors.Wrap(ctx.Err(), "context canceled during retry backoff")
 			case <-time.After(backoff):
 			}
 		}
 
+		// Auth is built per attempt so that expired OAuth2 tokens are refreshed between retries
+		var auth smtp.Auth
+		auth, err = s.auth()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return attempts, errors.Wrap(err, "failed to authenticate")
+		}
+
+		attempts++
 		if s.cfg.TLS {
 			err = s.sendMailWithTLS(ctx, addr, auth, from, allTo, bccAddresses, msg)
 		} else {
@@ -156,11 +219,11 @@ func (s *Sender) send(ctx context.Context, email *mail.Email) error {
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return errors.Wrap(err, "failed to send email")
+		return attempts, errors.Wrap(err, "failed to send email")
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return nil
+	return attempts, nil
 }
 
 // sendMail sends email without TLS (plain connection).
@@ -366,73 +429,17 @@ func (s *Sender) sendMailWithTLS(ctx context.Context, addr string, auth smtp.Aut
 
 // buildMessage builds the raw email message.
 func (s *Sender) buildMessage(email *mail.Email) []byte {
-	var msg strings.Builder
-
-	// Headers
-	msg.WriteString(fmt.Sprintf("From: %s\r\n", s.formatAddress(email.From)))
-
-	if len(email.To) > 0 {
-		msg.WriteString(fmt.Sprintf("To: %s\r\n", s.formatAddressList(email.To)))
-	}
-
-	if len(email.Cc) > 0 {
-		msg.WriteString(fmt.Sprintf("Cc: %s\r\n", s.formatAddressList(email.Cc)))
-	}
-
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
-	msg.WriteString("MIME-Version: 1.0\r\n")
-	msg.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
-
-	// Add custom headers
-	for k, v := range email.Headers {
-		msg.WriteString(fmt.Sprintf("%s: %s\r\n", k, v))
-	}
-
-	// Build body
-	if email.HTML != "" {
-		boundary := fmt.Sprintf("boundary_%d", time.Now().UnixNano())
-		msg.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%s\r\n", boundary))
-		msg.WriteString("\r\n")
-
-		// Plain text part
-		msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
-		msg.WriteString(email.Body)
-		msg.WriteString("\r\n")
-
-		// HTML part
-		msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-		msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
-		msg.WriteString(email.HTML)
-		msg.WriteString("\r\n")
-
-		msg.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
-	} else {
-		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
-		msg.WriteString(email.Body)
-		msg.WriteString("\r\n")
-	}
-
-	return []byte(msg.String())
+	return mail.BuildMessage(email)
 }
 
 // formatAddress formats a single address.
 func (s *Sender) formatAddress(addr mail.Address) string {
-	if addr.Name != "" {
-		// Escape quotes in name
-		escapedName := strings.ReplaceAll(addr.Name, "\"", "\\\"")
-		return fmt.Sprintf("%s <%s>", escapedName, addr.Address)
-	}
-	return addr.Address
+	return addr.String()
 }
 
 // formatAddressList formats a list of addresses.
 func (s *Sender) formatAddressList(addrs []mail.Address) string {
-	formatted := make([]string, len(addrs))
-	for i, addr := range addrs {
-		formatted[i] = s.formatAddress(addr)
-	}
-	return strings.Join(formatted, ", ")
+	return mail.FormatAddressList(addrs)
 }
 
 // getEmailAddresses extracts email addresses from mail.Address slice.
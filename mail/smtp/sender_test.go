@@ -153,6 +153,35 @@ func TestSender_BuildMessageWithHTML(t *testing.T) {
 	assert.Contains(t, msgStr, "boundary_")
 }
 
+func TestSender_BuildMessageWithAttachments(t *testing.T) {
+	t.Parallel()
+	cfg := Config{Host: "localhost"}
+	sender := NewSender(cfg)
+
+	email := mail.Email{
+		From:    mail.Address{Address: "sender@example.com"},
+		To:      []mail.Address{{Address: "recipient@example.com"}},
+		Subject: "Report",
+		Body:    "See attached",
+		HTML:    "<p>See attached</p>",
+		Attachments: []mail.Attachment{
+			{Filename: "report.csv", ContentType: "text/csv", Data: []byte("id,name\n1,test\n")},
+			{Filename: "logo.png", ContentID: "logo", Inline: true, Data: []byte{0x89, 'P', 'N', 'G'}},
+		},
+	}
+
+	msg := sender.buildMessage(&email)
+
+	msgStr := string(msg)
+	assert.Contains(t, msgStr, "multipart/mixed")
+	assert.Contains(t, msgStr, "multipart/alternative")
+	assert.Contains(t, msgStr, "Content-Disposition: attachment; filename=report.csv")
+	assert.Contains(t, msgStr, "Content-Disposition: inline; filename=logo.png")
+	assert.Contains(t, msgStr, "Content-Type: application/octet-stream; name=logo.png")
+	assert.Contains(t, msgStr, "Content-ID: <logo>")
+	assert.Contains(t, msgStr, "aWQsbmFtZQoxLHRlc3QK")
+}
+
 func TestSender_BuildMessageWithCcAndBcc(t *testing.T) {
 	t.Parallel()
 	cfg := Config{Host: "localhost"}
@@ -1,6 +1,10 @@
 package smtp
 
-import "time"
+import (
+	"time"
+
+	"github.com/pure-golang/adapters/mail"
+)
 
 // Backoff defaults for retry logic.
 const (
@@ -11,12 +15,44 @@ const (
 
 // Config contains SMTP connection parameters.
 type Config struct {
-	Host       string `envconfig:"SMTP_HOST" required:"true"`     // smtp.gmail.com
-	Port       int    `envconfig:"SMTP_PORT" default:"587"`       // 587 for STARTTLS, 465 for TLS
-	Username   string `envconfig:"SMTP_USER" required:"true"`     // username or email
-	Password   string `envconfig:"SMTP_PASSWORD" required:"true"` // password or app password
-	From       string `envconfig:"SMTP_FROM"`                     // default from address (optional)
-	TLS        bool   `envconfig:"SMTP_TLS" default:"true"`       // enable STARTTLS
-	Insecure   bool   `envconfig:"SMTP_INSECURE" default:"false"` // skip certificate verification
-	MaxRetries int    `envconfig:"SMTP_MAX_RETRIES" default:"3"`  // max send attempts (0 or 1 = no retry)
+	Host       string `envconfig:"SMTP_HOST" required:"true"`        // smtp.gmail.com
+	Port       int    `envconfig:"SMTP_PORT" default:"587"`          // 587 for STARTTLS, 465 for TLS
+	Username   string `envconfig:"SMTP_USER" required:"true"`        // username or email
+	Password   string `envconfig:"SMTP_PASSWORD"`                    // password or app password (plain auth)
+	From       string `envconfig:"SMTP_FROM"`                        // default from address (optional)
+	TLS        bool   `envconfig:"SMTP_TLS" default:"true"`          // enable STARTTLS
+	Insecure   bool   `envconfig:"SMTP_INSECURE" default:"false"`    // skip certificate verification
+	MaxRetries int    `envconfig:"SMTP_MAX_RETRIES" default:"3"`     // max send attempts (0 or 1 = no retry)
+	AuthMethod string `envconfig:"SMTP_AUTH_METHOD" default:"plain"` // plain or xoauth2 (requires WithTokenSource)
+
+	// Per-domain throttling: emails per second for each recipient domain, e.g. "gmail.com:10,yahoo.com:5"
+	DomainRateLimits map[string]float64 `envconfig:"SMTP_DOMAIN_RATE_LIMITS"`
+	DomainRateLimit  float64            `envconfig:"SMTP_DOMAIN_RATE_LIMIT"`             // emails per second for other domains (0 = unlimited)
+	DomainRateBurst  int                `envconfig:"SMTP_DOMAIN_RATE_BURST" default:"1"` // emails sent to a domain without waiting
+	ThrottleJitter   time.Duration      `envconfig:"SMTP_THROTTLE_JITTER"`               // max random delay added to throttled sends
+
+	// Sandbox mode for non-production environments, see mail.Sandbox
+	SandboxRedirectTo     string   `envconfig:"SMTP_SANDBOX_REDIRECT_TO"`     // safe address receiving all other recipients' emails
+	SandboxAllowedDomains []string `envconfig:"SMTP_SANDBOX_ALLOWED_DOMAINS"` // recipient domains delivered as is, e.g. "example.com"
+
+	// Limits for emails with user-generated content, see mail.Limits
+	MaxMessageSize         int      `envconfig:"SMTP_MAX_MESSAGE_SIZE"`         // max rendered message size in bytes (0 = unlimited)
+	MaxAttachments         int      `envconfig:"SMTP_MAX_ATTACHMENTS"`          // max attachments per email (0 = unlimited)
+	AllowedAttachmentTypes []string `envconfig:"SMTP_ALLOWED_ATTACHMENT_TYPES"` // allowed content types, e.g. "application/pdf,image/*"
+	BlockedExtensions      []string `envconfig:"SMTP_BLOCKED_EXTENSIONS"`       // rejected attachment extensions, e.g. ".exe,.js"
+}
+
+// Sandbox returns the sandbox configured by SMTP_SANDBOX_* variables.
+func (c Config) Sandbox() mail.Sandbox {
+	return mail.Sandbox{RedirectTo: c.SandboxRedirectTo, AllowedDomains: c.SandboxAllowedDomains}
+}
+
+// Limits returns the limits configured by SMTP_MAX_* and attachment type variables.
+func (c Config) Limits() mail.Limits {
+	return mail.Limits{
+		MaxMessageSize:         c.MaxMessageSize,
+		MaxAttachments:         c.MaxAttachments,
+		AllowedAttachmentTypes: c.AllowedAttachmentTypes,
+		BlockedExtensions:      c.BlockedExtensions,
+	}
 }
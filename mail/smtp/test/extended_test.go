@@ -12,76 +12,15 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 
 	"github.com/pure-golang/adapters/mail"
+	"github.com/pure-golang/adapters/mail/mailtest"
 	smtpadapter "github.com/pure-golang/adapters/mail/smtp"
 )
 
-func startSMTPContainer(t *testing.T) testcontainers.Container {
-	if testing.Short() {
-		t.Skip("integration test")
-	}
-
-	ctx := context.Background()
-
-	req := testcontainers.ContainerRequest{
-		Image:        "mailhog/mailhog:latest",
-		ExposedPorts: []string{"1025/tcp", "8025/tcp"},
-		WaitingFor:   wait.ForListeningPort("1025/tcp"),
-	}
-
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	require.NoError(t, err, "failed to start mailhog container")
-
-	t.Cleanup(func() {
-		if err := container.Terminate(ctx); err != nil {
-			t.Logf("failed to terminate container: %v", err)
-		}
-	})
-
-	return container
-}
-
-func getSMTPHostPort(t *testing.T, container testcontainers.Container) (string, int) {
-	ctx := context.Background()
-	host, err := container.Host(ctx)
-	require.NoError(t, err, "failed to get container host")
-
-	port, err := container.MappedPort(ctx, "1025")
-	require.NoError(t, err, "failed to get container port")
-
-	portNum, err := strconv.Atoi(port.Port())
-	require.NoError(t, err, "failed to parse port number")
-
-	return host, portNum
-}
-
-func waitForSMTP(t *testing.T, host string, port int) {
-	addr := net.JoinHostPort(host, strconv.Itoa(port))
-	deadline := time.Now().Add(30 * time.Second)
-
-	for time.Now().Before(deadline) {
-		conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
-		if err == nil {
-			conn.Close()
-			time.Sleep(500 * time.Millisecond)
-			return
-		}
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	t.Fatalf("SMTP server at %s did not become ready", addr)
-}
-
 func TestSender_Extended_NoTLS(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -105,9 +44,8 @@ func TestSender_Extended_NoTLS(t *testing.T) {
 }
 
 func TestSender_Extended_WithDefaultFrom(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -131,9 +69,8 @@ func TestSender_Extended_WithDefaultFrom(t *testing.T) {
 }
 
 func TestSender_Extended_ExplicitFromOverridesDefault(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -158,9 +95,8 @@ func TestSender_Extended_ExplicitFromOverridesDefault(t *testing.T) {
 }
 
 func TestSender_Extended_MultipleEmails(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -198,9 +134,8 @@ func TestSender_Extended_MultipleEmails(t *testing.T) {
 }
 
 func TestSender_Extended_MultipleRecipients(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -236,9 +171,8 @@ func TestSender_Extended_MultipleRecipients(t *testing.T) {
 }
 
 func TestSender_Extended_OnlyCcRecipients(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -262,9 +196,8 @@ func TestSender_Extended_OnlyCcRecipients(t *testing.T) {
 }
 
 func TestSender_Extended_OnlyBccRecipients(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -288,9 +221,8 @@ func TestSender_Extended_OnlyBccRecipients(t *testing.T) {
 }
 
 func TestSender_Extended_HTMLWithMultipart(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -315,9 +247,8 @@ func TestSender_Extended_HTMLWithMultipart(t *testing.T) {
 }
 
 func TestSender_Extended_MultipleCustomHeaders(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -348,9 +279,8 @@ func TestSender_Extended_MultipleCustomHeaders(t *testing.T) {
 }
 
 func TestSender_Extended_EmptySubject(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -374,9 +304,8 @@ func TestSender_Extended_EmptySubject(t *testing.T) {
 }
 
 func TestSender_Extended_LongSubject(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -402,9 +331,8 @@ func TestSender_Extended_LongSubject(t *testing.T) {
 }
 
 func TestSender_Extended_SpecialCharactersInBody(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -428,9 +356,8 @@ func TestSender_Extended_SpecialCharactersInBody(t *testing.T) {
 }
 
 func TestSender_Extended_UnicodeContent(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -454,9 +381,8 @@ func TestSender_Extended_UnicodeContent(t *testing.T) {
 }
 
 func TestSender_Extended_SenderConcurrency(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -581,9 +507,8 @@ func TestSender_Extended_ContextTimeout(t *testing.T) {
 }
 
 func TestSender_Extended_SMTPDirect(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	addr := net.JoinHostPort(host, strconv.Itoa(port))
 
@@ -611,9 +536,8 @@ func TestSender_Extended_SMTPDirect(t *testing.T) {
 }
 
 func TestSender_Extended_CloseIdempotent(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -633,9 +557,8 @@ func TestSender_Extended_CloseIdempotent(t *testing.T) {
 }
 
 func TestSender_Extended_BodyOnlyNoHTML(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
@@ -659,9 +582,8 @@ func TestSender_Extended_BodyOnlyNoHTML(t *testing.T) {
 }
 
 func TestSender_Extended_HTMLNoBody(t *testing.T) {
-	container := startSMTPContainer(t)
-	host, port := getSMTPHostPort(t, container)
-	waitForSMTP(t, host, port)
+	server := mailtest.StartMailServer(t)
+	host, port := server.Config().Host, server.Config().Port
 
 	cfg := smtpadapter.Config{
 		Host: host,
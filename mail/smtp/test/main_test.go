@@ -5,18 +5,22 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 
 	"github.com/pure-golang/adapters/mail"
+	"github.com/pure-golang/adapters/mail/mailtest"
 	"github.com/pure-golang/adapters/mail/smtp"
 )
 
-var testSender *smtp.Sender
+var (
+	testSender *smtp.Sender
+	testServer *mailtest.Server
+)
 
 func TestMain(m *testing.M) {
 	flag.Parse()
@@ -28,59 +32,34 @@ func TestMain(m *testing.M) {
 
 	ctx := context.Background()
 
-	req := testcontainers.ContainerRequest{
-		Image:        "mailhog/mailhog:latest",
-		ExposedPorts: []string{"1025/tcp"},
-		WaitingFor:   wait.ForListeningPort("1025/tcp"),
-	}
-
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	if err != nil {
-		panic(fmt.Sprintf("could not start mailhog container: %s", err))
-	}
-
-	host, err := container.Host(ctx)
+	server, err := mailtest.Start(ctx)
 	if err != nil {
-		_ = container.Terminate(ctx)
-		panic(fmt.Sprintf("could not get container host: %s", err))
+		panic(fmt.Sprintf("could not start mail server: %s", err))
 	}
-
-	port, err := container.MappedPort(ctx, "1025")
-	if err != nil {
-		_ = container.Terminate(ctx)
-		panic(fmt.Sprintf("could not get container port: %s", err))
-	}
-
-	portNum, err := strconv.Atoi(port.Port())
-	if err != nil {
-		_ = container.Terminate(ctx)
-		panic(fmt.Sprintf("could not parse port number: %s", err))
-	}
-
-	cfg := smtp.Config{
-		Host: host,
-		Port: portNum,
-		TLS:  false,
-	}
-
-	testSender = smtp.NewSender(cfg)
+	testServer = server
+	testSender = smtp.NewSender(server.Config())
 
 	code := m.Run()
 
-	if testSender != nil {
-		testSender.Close()
-	}
-
-	if err := container.Terminate(ctx); err != nil {
+	testSender.Close()
+	if err := server.Terminate(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to terminate container: %v\n", err)
 	}
 
 	os.Exit(code)
 }
 
+// waitForSubject returns the received message with the given subject.
+func waitForSubject(t *testing.T, subject string) mailtest.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	m, err := testServer.WaitForSubject(ctx, subject)
+	require.NoError(t, err)
+	return m
+}
+
 func TestSender_Integration_Send_Success(t *testing.T) {
 	if testing.Short() {
 		t.Skip("integration test")
@@ -96,6 +75,12 @@ func TestSender_Integration_Send_Success(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	m := waitForSubject(t, email.Subject)
+	assert.Equal(t, "test@example.com", m.From)
+	assert.Equal(t, []string{"recipient@example.com"}, m.Recipients)
+	assert.Equal(t, email.From, m.Email.From)
+	assert.Equal(t, email.Body, strings.TrimSpace(m.Email.Body))
 }
 
 func TestSender_Integration_Send_WithName(t *testing.T) {
@@ -116,6 +101,10 @@ func TestSender_Integration_Send_WithName(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	m := waitForSubject(t, email.Subject)
+	assert.Equal(t, email.From, m.Email.From)
+	assert.Equal(t, email.To, m.Email.To)
 }
 
 func TestSender_Integration_Send_WithHTML(t *testing.T) {
@@ -134,6 +123,10 @@ func TestSender_Integration_Send_WithHTML(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	m := waitForSubject(t, email.Subject)
+	assert.Equal(t, email.Body, strings.TrimSpace(m.Email.Body))
+	assert.Equal(t, email.HTML, strings.TrimSpace(m.Email.HTML))
 }
 
 func TestSender_Integration_Send_WithCcAndBcc(t *testing.T) {
@@ -153,6 +146,12 @@ func TestSender_Integration_Send_WithCcAndBcc(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	m := waitForSubject(t, email.Subject)
+	assert.ElementsMatch(t, []string{"to@example.com", "cc@example.com", "bcc@example.com"}, m.Recipients)
+	assert.Equal(t, email.Cc, m.Email.Cc)
+	assert.Empty(t, m.Email.Bcc, "Bcc must not be written to headers")
+	assert.NotContains(t, string(m.Raw), "bcc@example.com")
 }
 
 func TestSender_Integration_Send_WithCustomHeaders(t *testing.T) {
@@ -174,6 +173,10 @@ func TestSender_Integration_Send_WithCustomHeaders(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	m := waitForSubject(t, email.Subject)
+	assert.Equal(t, "1", m.Email.Headers["X-Priority"])
+	assert.Equal(t, "custom-value", m.Email.Headers["X-Custom"])
 }
 
 func TestSender_Integration_Send_MultipleEmails(t *testing.T) {
@@ -199,6 +202,12 @@ func TestSender_Integration_Send_MultipleEmails(t *testing.T) {
 
 	err := testSender.Send(ctx, emails...)
 	require.NoError(t, err)
+
+	for _, email := range emails {
+		m := waitForSubject(t, email.Subject)
+		assert.Equal(t, email.From.Address, m.From)
+		assert.Equal(t, email.Body, strings.TrimSpace(m.Email.Body))
+	}
 }
 
 func TestSender_Integration_Send_WithSpecialCharacters(t *testing.T) {
@@ -216,6 +225,11 @@ func TestSender_Integration_Send_WithSpecialCharacters(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	m := waitForSubject(t, email.Subject)
+	assert.Equal(t, email.From, m.Email.From)
+	assert.Equal(t, email.To, m.Email.To)
+	assert.Equal(t, email.Body, strings.TrimSpace(m.Email.Body))
 }
 
 func TestSender_Integration_Send_LongSubject(t *testing.T) {
@@ -237,4 +251,6 @@ func TestSender_Integration_Send_LongSubject(t *testing.T) {
 
 	err := testSender.Send(ctx, email)
 	require.NoError(t, err)
+
+	waitForSubject(t, longSubject)
 }
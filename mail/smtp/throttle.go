@@ -0,0 +1,203 @@
+package smtp
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/pure-golang/adapters/logger"
+	"github.com/pure-golang/adapters/mail"
+)
+
+// domainThrottle limits the send rate per recipient domain with token buckets,
+// so that bulk sends do not trigger provider throttling (421 responses).
+// A nil *domainThrottle does not limit anything.
+type domainThrottle struct {
+	mu           sync.Mutex
+	limits       map[string]rate.Limit
+	defaultLimit rate.Limit
+	burst        int
+	jitter       time.Duration
+	limiters     map[string]*rate.Limiter
+}
+
+// newDomainThrottle returns a throttle for the config or nil if no rate limits are set.
+func newDomainThrottle(cfg Config) *domainThrottle {
+	if cfg.DomainRateLimit <= 0 && len(cfg.DomainRateLimits) == 0 {
+		return nil
+	}
+	t := &domainThrottle{
+		limits:       make(map[string]rate.Limit, len(cfg.DomainRateLimits)),
+		defaultLimit: rate.Limit(cfg.DomainRateLimit),
+		burst:        max(cfg.DomainRateBurst, 1),
+		jitter:       cfg.ThrottleJitter,
+		limiters:     make(map[string]*rate.Limiter),
+	}
+	for domain, limit := range cfg.DomainRateLimits {
+		t.limits[strings.ToLower(domain)] = rate.Limit(limit)
+	}
+	return t
+}
+
+// limiter returns the token bucket of the domain or nil if the domain is not limited.
+func (t *domainThrottle) limiter(domain string) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if l, ok := t.limiters[domain]; ok {
+		return l
+	}
+	limit, ok := t.limits[domain]
+	if !ok {
+		limit = t.defaultLimit
+	}
+	if limit <= 0 {
+		t.limiters[domain] = nil
+		return nil
+	}
+	l := rate.NewLimiter(limit, t.burst)
+	t.limiters[domain] = l
+	return l
+}
+
+// wait takes a token from every recipient domain of the email and waits until all of them are available.
+// Throttled sends are delayed by a random jitter so that senders released together do not burst.
+// It returns the time spent waiting.
+func (t *domainThrottle) wait(ctx context.Context, domains []string) (time.Duration, error) {
+	if t == nil {
+		return 0, nil
+	}
+
+	var delay time.Duration
+	reservations := make([]*rate.Reservation, 0, len(domains))
+	for _, domain := range domains {
+		l := t.limiter(domain)
+		if l == nil {
+			continue
+		}
+		r := l.Reserve()
+		reservations = append(reservations, r)
+		delay = max(delay, r.Delay())
+	}
+	if delay == 0 {
+		return 0, nil
+	}
+	if t.jitter > 0 {
+		delay += rand.N(t.jitter)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		for _, r := range reservations {
+			r.Cancel()
+		}
+		return 0, ctx.Err()
+	}
+}
+
+// waitThrottle waits for the rate limits of the recipient domains of the email.
+func (s *Sender) waitThrottle(ctx context.Context, email *mail.Email) error {
+	domains := recipientDomains(email)
+	waited, err := s.throttle.wait(ctx, domains)
+	if err != nil {
+		return errors.Wrap(err, "context canceled while waiting for domain rate limit")
+	}
+	if waited > 0 {
+		log := s.logger
+		if log == nil {
+			log = logger.FromContext(ctx)
+		}
+		log.WithGroup("smtp").DebugContext(ctx, "email send throttled",
+			"recipient_domains", domains, "wait_ms", waited.Milliseconds())
+	}
+	return nil
+}
+
+// bucket is a simulated token bucket used to plan a batch.
+type bucket struct {
+	tokens float64
+	limit  float64
+	burst  float64
+}
+
+// schedule orders a batch so that emails to throttled domains do not hold back the others:
+// each next email is the first one of the recipient domains that can be sent soonest.
+// Emails to the same recipient domains keep their relative order.
+func (t *domainThrottle) schedule(emails []mail.Email) []mail.Email {
+	if t == nil || len(emails) < 2 {
+		return emails
+	}
+
+	type group struct {
+		domains []string
+		emails  []mail.Email
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	for _, email := range emails {
+		domains := recipientDomains(&email)
+		key := strings.Join(domains, ",")
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{domains: domains}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.emails = append(g.emails, email)
+	}
+	if len(groups) == 1 {
+		return emails
+	}
+
+	// Buckets start from the current state and are drained as if the emails were sent in the chosen order
+	now := time.Now()
+	buckets := make(map[string]*bucket)
+	for _, g := range groups {
+		for _, domain := range g.domains {
+			if l := t.limiter(domain); l != nil {
+				buckets[domain] = &bucket{tokens: l.TokensAt(now), limit: float64(l.Limit()), burst: float64(l.Burst())}
+			}
+		}
+	}
+
+	scheduled := make([]mail.Email, 0, len(emails))
+	for len(scheduled) < len(emails) {
+		var next *group
+		var nextDelay float64
+		for _, g := range groups {
+			if len(g.emails) == 0 {
+				continue
+			}
+			var delay float64
+			for _, domain := range g.domains {
+				if b := buckets[domain]; b != nil && b.tokens < 1 {
+					delay = max(delay, (1-b.tokens)/b.limit)
+				}
+			}
+			if next == nil || delay < nextDelay {
+				next, nextDelay = g, delay
+			}
+		}
+
+		for _, b := range buckets {
+			b.tokens = min(b.burst, b.tokens+nextDelay*b.limit)
+		}
+		for _, domain := range next.domains {
+			if b := buckets[domain]; b != nil {
+				b.tokens--
+			}
+		}
+		scheduled = append(scheduled, next.emails[0])
+		next.emails = next.emails[1:]
+	}
+	return scheduled
+}
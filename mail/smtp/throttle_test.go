@@ -0,0 +1,113 @@
+package smtp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/mail"
+)
+
+func emailTo(addr string) mail.Email {
+	return mail.Email{To: []mail.Address{{Address: addr}}, Subject: addr}
+}
+
+func TestNewDomainThrottle_Disabled(t *testing.T) {
+	t.Parallel()
+	throttle := newDomainThrottle(Config{})
+	assert.Nil(t, throttle)
+
+	waited, err := throttle.wait(context.Background(), []string{"gmail.com"})
+	require.NoError(t, err)
+	assert.Zero(t, waited)
+
+	emails := []mail.Email{emailTo("a@gmail.com"), emailTo("b@gmail.com")}
+	assert.Equal(t, emails, throttle.schedule(emails))
+}
+
+func TestDomainThrottle_Wait(t *testing.T) {
+	t.Parallel()
+	throttle := newDomainThrottle(Config{
+		DomainRateLimits: map[string]float64{"Gmail.com": 20},
+		ThrottleJitter:   10 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	waited, err := throttle.wait(ctx, []string{"gmail.com", "example.com"})
+	require.NoError(t, err)
+	assert.Zero(t, waited, "first email uses the burst")
+
+	start := time.Now()
+	waited, err = throttle.wait(ctx, []string{"gmail.com"})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, waited, 40*time.Millisecond)
+	assert.Less(t, waited, 70*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	// unlimited domains are not throttled
+	for range 5 {
+		waited, err = throttle.wait(ctx, []string{"example.com"})
+		require.NoError(t, err)
+		assert.Zero(t, waited)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = throttle.wait(canceled, []string{"gmail.com"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDomainThrottle_DefaultLimit(t *testing.T) {
+	t.Parallel()
+	throttle := newDomainThrottle(Config{DomainRateLimit: 1, DomainRateBurst: 2})
+	ctx := context.Background()
+
+	for range 2 {
+		waited, err := throttle.wait(ctx, []string{"example.com"})
+		require.NoError(t, err)
+		assert.Zero(t, waited)
+	}
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := throttle.wait(timeout, []string{"example.com"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the canceled reservation returns its token
+	assert.Greater(t, throttle.limiter("example.com").Tokens(), -0.5)
+}
+
+func TestDomainThrottle_Schedule(t *testing.T) {
+	t.Parallel()
+	throttle := newDomainThrottle(Config{DomainRateLimits: map[string]float64{"gmail.com": 1}})
+
+	scheduled := throttle.schedule([]mail.Email{
+		emailTo("1@gmail.com"),
+		emailTo("2@gmail.com"),
+		emailTo("3@gmail.com"),
+		emailTo("1@yahoo.com"),
+		emailTo("2@yahoo.com"),
+	})
+
+	subjects := make([]string, 0, len(scheduled))
+	for _, email := range scheduled {
+		subjects = append(subjects, email.Subject)
+	}
+	assert.Equal(t, []string{"1@gmail.com", "1@yahoo.com", "2@yahoo.com", "2@gmail.com", "3@gmail.com"}, subjects)
+}
+
+func TestSender_Send_ThrottleCanceled(t *testing.T) {
+	t.Parallel()
+	sender := NewSender(Config{Host: "localhost", Port: 1, DomainRateLimits: map[string]float64{"example.com": 0.001}})
+	sender.throttle.limiter("example.com").AllowN(time.Now(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := sender.Send(ctx, mail.Email{
+		From: mail.Address{Address: "sender@example.com"},
+		To:   []mail.Address{{Address: "user@example.com"}},
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,92 @@
+package correlate
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Ключи атрибутов лога, общие для всех адаптеров
+const (
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+	RequestIDKey = "request_id"
+)
+
+// requestIDKey — ключ контекста для идентификатора запроса
+type requestIDKey struct{}
+
+// boundKey — ключ контекста, отмечающий, что логгер в контексте уже содержит атрибуты корреляции
+type boundKey struct{}
+
+// WithRequestID возвращает контекст с идентификатором запроса; пустой id не сохраняется
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Attrs возвращает атрибуты корреляции из контекста: trace_id и span_id текущего span'а,
+// если он валиден, и request_id, если задан через WithRequestID
+func Attrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		attrs = append(attrs,
+			slog.String(TraceIDKey, sc.TraceID().String()),
+			slog.String(SpanIDKey, sc.SpanID().String()),
+		)
+	}
+	if id := RequestID(ctx); id != "" {
+		attrs = append(attrs, slog.String(RequestIDKey, id))
+	}
+	return attrs
+}
+
+// Args возвращает атрибуты корреляции в виде аргументов для slog.Logger.With и методов логирования
+func Args(ctx context.Context) []any {
+	attrs := Attrs(ctx)
+	if len(attrs) == 0 {
+		return nil
+	}
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return args
+}
+
+// Logger возвращает l с атрибутами корреляции из контекста.
+// Вызывается до WithGroup, чтобы атрибуты оставались на верхнем уровне записи.
+func Logger(ctx context.Context, l *slog.Logger) *slog.Logger {
+	args := Args(ctx)
+	if len(args) == 0 {
+		return l
+	}
+	return l.With(args...)
+}
+
+// NewContext сохраняет в контекст логгер из logger.FromContext с атрибутами корреляции.
+// Последующие FromContext возвращают его без повторного добавления атрибутов.
+func NewContext(ctx context.Context) context.Context {
+	ctx = logger.NewContext(ctx, Logger(ctx, logger.FromContext(ctx)))
+	return context.WithValue(ctx, boundKey{}, true)
+}
+
+// FromContext возвращает логгер из logger.FromContext с атрибутами корреляции.
+// Если логгер сохранён через NewContext, он возвращается как есть.
+func FromContext(ctx context.Context) *slog.Logger {
+	if bound, _ := ctx.Value(boundKey{}).(bool); bound {
+		return logger.FromContext(ctx)
+	}
+	return Logger(ctx, logger.FromContext(ctx))
+}
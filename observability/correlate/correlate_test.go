@@ -0,0 +1,97 @@
+package correlate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// spanContext returns a context with a valid remote span.
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+}
+
+// decode parses JSON log lines.
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestAttrs tests the attributes taken from the span and the request ID.
+func TestAttrs(t *testing.T) {
+	t.Parallel()
+	assert.Empty(t, Attrs(context.Background()))
+	assert.Nil(t, Args(context.Background()))
+
+	ctx := WithRequestID(spanContext(t), "req-1")
+	assert.Equal(t, []slog.Attr{
+		slog.String(TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+		slog.String(SpanIDKey, "00f067aa0ba902b7"),
+		slog.String(RequestIDKey, "req-1"),
+	}, Attrs(ctx))
+	assert.Len(t, Args(ctx), 3)
+
+	ctx = WithRequestID(context.Background(), "")
+	assert.Empty(t, RequestID(ctx))
+	assert.Empty(t, Attrs(ctx))
+}
+
+// TestLogger tests that correlation attributes stay at the top level of grouped records.
+func TestLogger(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&buf, nil))
+	ctx := WithRequestID(spanContext(t), "req-1")
+
+	Logger(ctx, base).WithGroup("storage").Info("object stored", "key", "a.txt")
+	assert.Same(t, base, Logger(context.Background(), base))
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", records[0][TraceIDKey])
+	assert.Equal(t, "00f067aa0ba902b7", records[0][SpanIDKey])
+	assert.Equal(t, "req-1", records[0][RequestIDKey])
+	assert.Equal(t, map[string]any{"key": "a.txt"}, records[0]["storage"])
+}
+
+// TestFromContext tests that a logger bound by NewContext is not enriched twice.
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	ctx := logger.NewContext(spanContext(t), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	FromContext(ctx).Info("unbound")
+	FromContext(NewContext(ctx)).Info("bound")
+
+	records := decode(t, &buf)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record[TraceIDKey])
+	}
+	assert.Equal(t, 1, strings.Count(strings.Split(buf.String(), "\n")[1], TraceIDKey))
+}
@@ -0,0 +1,29 @@
+// Package correlate добавляет в записи slog идентификаторы трассировки и запроса из контекста,
+// чтобы логи всех адаптеров (storage, db, mail, grpc) связывались с трейсами
+// по одинаковым ключам.
+//
+// Атрибуты:
+//   - trace_id, span_id — текущий span OpenTelemetry, если он валиден
+//   - request_id — идентификатор запроса, сохранённый через [WithRequestID]
+//
+// Использование:
+//
+//	import "github.com/pure-golang/adapters/observability/correlate"
+//
+//	ctx = correlate.WithRequestID(ctx, r.Header.Get("X-Request-Id"))
+//	ctx = correlate.NewContext(ctx) // logger.FromContext(ctx) получает trace_id, span_id и request_id
+//
+//	// логгер адаптера
+//	correlate.Logger(ctx, s.logger).Info("object stored", "key", key)
+//
+//	// логгер из контекста
+//	correlate.FromContext(ctx).WithGroup("postgres").LogAttrs(ctx, slog.LevelDebug, "query executed", attrs...)
+//
+// [Logger] вызывается до WithGroup: атрибуты, добавленные после группы, попадают внутрь неё.
+//
+// [NewContext] сохраняет логгер с атрибутами корреляции в контекст (logger.NewContext);
+// [FromContext] для такого контекста не дублирует атрибуты.
+//
+// gRPC интерцепторы логирования (grpc/middleware) сохраняют в контекст идентификатор запроса
+// из метаданных x-request-id; без него записи содержат только trace_id и span_id.
+package correlate
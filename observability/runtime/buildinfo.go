@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"fmt"
+	stdruntime "runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Сведения о сборке, задаются при компиляции через -ldflags (см. LDFlags):
+//
+//	go build -ldflags "-X github.com/pure-golang/adapters/observability/runtime.Version=v1.2.3 ..."
+//
+// Пустые значения заполняются из debug.ReadBuildInfo (версия модуля, vcs.revision, vcs.time).
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// importPath — путь пакета для флагов -X
+const importPath = "github.com/pure-golang/adapters/observability/runtime"
+
+// unknown — значение атрибута, если сведения о сборке недоступны
+const unknown = "unknown"
+
+// BuildInfo — сведения о сборке приложения
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// ReadBuildInfo возвращает сведения о сборке: значения из -ldflags, иначе из debug.ReadBuildInfo.
+// Недоступные значения равны "unknown".
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: stdruntime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	for _, field := range []*string{&info.Version, &info.Commit, &info.BuildTime} {
+		if *field == "" {
+			*field = unknown
+		}
+	}
+	return info
+}
+
+// LDFlags возвращает значение -ldflags, задающее Version, Commit и BuildTime;
+// пустые значения пропускаются. Для скриптов сборки на Go; в Makefile те же флаги:
+//
+//	go build -ldflags "-X '$(PKG).Version=$(VERSION)' -X '$(PKG).Commit=$(shell git rev-parse HEAD)'" ./cmd/app
+func LDFlags(version, commit, buildTime string) string {
+	values := []struct{ name, value string }{
+		{name: "Version", value: version},
+		{name: "Commit", value: commit},
+		{name: "BuildTime", value: buildTime},
+	}
+	var flags []string
+	for _, v := range values {
+		if v.value != "" {
+			flags = append(flags, fmt.Sprintf("-X '%s.%s=%s'", importPath, v.name, v.value))
+		}
+	}
+	return strings.Join(flags, " ")
+}
@@ -0,0 +1,39 @@
+// Package runtime регистрирует метрики Go runtime, метрики процесса и gauge build_info
+// на общем MeterProvider.
+//
+// Метрики:
+//   - Go runtime (go.opentelemetry.io/contrib/instrumentation/runtime): память, GC, горутины
+//   - process.uptime, process.cpu.time, process.open_file_descriptors (только Linux)
+//   - build_info — значение 1 с атрибутами version, commit, build_time, go_version
+//
+// Использование:
+//
+//	import obsruntime "github.com/pure-golang/adapters/observability/runtime"
+//
+//	// MeterProvider настраивается до Start (например, metrics.InitDefault)
+//	rt := obsruntime.New(obsruntime.Config{
+//	    MemStatsInterval: 15 * time.Second,
+//	    DisableGoMetrics: true, // уже зарегистрированы metrics.InitPrometheus
+//	})
+//	if err := rt.Start(); err != nil {
+//	    return err
+//	}
+//	defer rt.Close()
+//
+// metrics.InitPrometheus уже регистрирует метрики Go runtime на глобальном MeterProvider,
+// поэтому вместе с ним задаётся DisableGoMetrics.
+//
+// Версия, коммит и время сборки задаются через ldflags; LDFlags формирует строку флагов:
+//
+//	go build -ldflags "-X 'github.com/pure-golang/adapters/observability/runtime.Version=v1.2.3' \
+//	    -X 'github.com/pure-golang/adapters/observability/runtime.Commit=$(git rev-parse HEAD)'"
+//
+// Без ldflags значения берутся из debug.ReadBuildInfo (версия модуля, vcs.revision, vcs.time),
+// недоступные — "unknown".
+//
+// Конфигурация через переменные окружения:
+//
+//	RUNTIME_METRICS_MEMSTATS_INTERVAL — минимальный интервал чтения статистики памяти (default: 15s)
+//	RUNTIME_METRICS_DISABLE_PROCESS   — отключить метрики процесса
+//	RUNTIME_METRICS_DISABLE_GO        — отключить метрики Go runtime
+package runtime
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"runtime/metrics"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Метрики runtime/metrics для оценки процессорного времени процесса
+const (
+	cpuTotal = "/cpu/classes/total:cpu-seconds"
+	cpuIdle  = "/cpu/classes/idle:cpu-seconds"
+)
+
+// registerProcessMetrics регистрирует метрики процесса:
+//   - process.uptime — время с запуска, секунды
+//   - process.cpu.time — процессорное время по оценке Go runtime (обновляется при сборке мусора), секунды
+//   - process.open_file_descriptors — открытые дескрипторы (только Linux, через /proc/self/fd)
+func registerProcessMetrics(m metric.Meter, start time.Time) (metric.Registration, error) {
+	uptime, err := m.Float64ObservableGauge(
+		"process.uptime",
+		metric.WithUnit("s"),
+		metric.WithDescription("The time the process has been running."),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create process uptime gauge")
+	}
+	cpuTime, err := m.Float64ObservableCounter(
+		"process.cpu.time",
+		metric.WithUnit("s"),
+		metric.WithDescription("CPU time used by the process as estimated by the Go runtime."),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create process cpu time counter")
+	}
+	openFDs, err := m.Int64ObservableUpDownCounter(
+		"process.open_file_descriptors",
+		metric.WithUnit("{file_descriptor}"),
+		metric.WithDescription("Number of file descriptors in use by the process."),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create open file descriptors counter")
+	}
+
+	samples := []metrics.Sample{{Name: cpuTotal}, {Name: cpuIdle}}
+	registration, err := m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveFloat64(uptime, time.Since(start).Seconds())
+
+		metrics.Read(samples)
+		if samples[0].Value.Kind() == metrics.KindFloat64 && samples[1].Value.Kind() == metrics.KindFloat64 {
+			o.ObserveFloat64(cpuTime, samples[0].Value.Float64()-samples[1].Value.Float64())
+		}
+
+		if fds, err := os.ReadDir("/proc/self/fd"); err == nil {
+			o.ObserveInt64(openFDs, int64(len(fds)))
+		}
+		return nil
+	}, uptime, cpuTime, openFDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register process metrics callback")
+	}
+	return registration, nil
+}
+
+// registerBuildInfo регистрирует gauge build_info со значением 1
+// и атрибутами version, commit, build_time, go_version
+func registerBuildInfo(m metric.Meter, info BuildInfo) (metric.Registration, error) {
+	gauge, err := m.Int64ObservableGauge(
+		"build_info",
+		metric.WithDescription("Build information of the application, the value is always 1."),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create build info gauge")
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String("version", info.Version),
+		attribute.String("commit", info.Commit),
+		attribute.String("build_time", info.BuildTime),
+		attribute.String("go_version", info.GoVersion),
+	)
+	registration, err := m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(gauge, 1, attrs)
+		return nil
+	}, gauge)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register build info callback")
+	}
+	return registration, nil
+}
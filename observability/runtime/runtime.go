@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	otelruntime "go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName — имя Meter для метрик процесса и build_info
+const meterName = "github.com/pure-golang/adapters/observability/runtime"
+
+// Config — настройки метрик runtime
+type Config struct {
+	// MemStatsInterval — минимальный интервал чтения статистики памяти Go runtime
+	MemStatsInterval time.Duration `envconfig:"RUNTIME_METRICS_MEMSTATS_INTERVAL" default:"15s"`
+	// DisableProcessMetrics отключает метрики процесса (uptime, CPU, дескрипторы)
+	DisableProcessMetrics bool `envconfig:"RUNTIME_METRICS_DISABLE_PROCESS"`
+	// DisableGoMetrics отключает метрики Go runtime, если их уже зарегистрировал
+	// metrics.InitPrometheus на том же MeterProvider
+	DisableGoMetrics bool `envconfig:"RUNTIME_METRICS_DISABLE_GO"`
+}
+
+// Option настраивает Runtime
+type Option func(*Runtime)
+
+// WithMeterProvider задаёт MeterProvider вместо глобального otel.GetMeterProvider()
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(r *Runtime) {
+		r.provider = provider
+	}
+}
+
+// WithBuildInfo задаёт сведения о сборке вместо ReadBuildInfo()
+func WithBuildInfo(info BuildInfo) Option {
+	return func(r *Runtime) {
+		r.buildInfo = &info
+	}
+}
+
+// Runtime регистрирует метрики Go runtime (память, GC, горутины, планировщик),
+// метрики процесса и gauge build_info на MeterProvider
+type Runtime struct {
+	cfg           Config
+	provider      metric.MeterProvider
+	buildInfo     *BuildInfo
+	start         time.Time
+	mu            sync.Mutex
+	registrations []metric.Registration
+}
+
+// New создаёт Runtime; метрики регистрируются в Start
+func New(cfg Config, opts ...Option) *Runtime {
+	r := &Runtime{
+		cfg:   cfg,
+		start: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start регистрирует метрики. MeterProvider должен быть настроен до вызова
+// (например, metrics.InitPrometheus или WithMeterProvider).
+func (r *Runtime) Start() error {
+	provider := r.provider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	if !r.cfg.DisableGoMetrics {
+		err := otelruntime.Start(
+			otelruntime.WithMeterProvider(provider),
+			otelruntime.WithMinimumReadMemStatsInterval(r.cfg.MemStatsInterval),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to start go runtime metrics")
+		}
+	}
+
+	m := provider.Meter(meterName)
+	info := ReadBuildInfo()
+	if r.buildInfo != nil {
+		info = *r.buildInfo
+	}
+	buildInfo, err := registerBuildInfo(m, info)
+	if err != nil {
+		return err
+	}
+	registrations := []metric.Registration{buildInfo}
+
+	if !r.cfg.DisableProcessMetrics {
+		process, err := registerProcessMetrics(m, r.start)
+		if err != nil {
+			if unregisterErr := buildInfo.Unregister(); unregisterErr != nil {
+				otel.Handle(unregisterErr)
+			}
+			return err
+		}
+		registrations = append(registrations, process)
+	}
+
+	r.mu.Lock()
+	r.registrations = append(r.registrations, registrations...)
+	r.mu.Unlock()
+	return nil
+}
+
+// Close отменяет регистрацию метрик процесса и build_info.
+// Метрики Go runtime остаются зарегистрированными до остановки MeterProvider.
+func (r *Runtime) Close() error {
+	r.mu.Lock()
+	registrations := r.registrations
+	r.registrations = nil
+	r.mu.Unlock()
+
+	for _, registration := range registrations {
+		if err := registration.Unregister(); err != nil {
+			return errors.Wrap(err, "failed to unregister runtime metrics")
+		}
+	}
+	return nil
+}
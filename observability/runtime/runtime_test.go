@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+	stdruntime "runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect returns the collected metrics by name.
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m
+		}
+	}
+	return result
+}
+
+// TestRuntime_Start tests that runtime, process and build info metrics are registered.
+func TestRuntime_Start(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	r := New(Config{}, WithMeterProvider(provider), WithBuildInfo(BuildInfo{
+		Version:   "v1.2.3",
+		Commit:    "abc123",
+		BuildTime: "2024-01-01T00:00:00Z",
+		GoVersion: "go1.25.0",
+	}))
+	require.NoError(t, r.Start())
+
+	metrics := collect(t, reader)
+	for _, name := range []string{"process.runtime.go.goroutines", "process.runtime.go.gc.count", "process.uptime", "process.cpu.time"} {
+		assert.Contains(t, metrics, name)
+	}
+
+	buildInfo := metrics["build_info"].Data.(metricdata.Gauge[int64])
+	require.Len(t, buildInfo.DataPoints, 1)
+	point := buildInfo.DataPoints[0]
+	assert.Equal(t, int64(1), point.Value)
+	version, ok := point.Attributes.Value("version")
+	require.True(t, ok)
+	assert.Equal(t, "v1.2.3", version.AsString())
+	goVersion, ok := point.Attributes.Value("go_version")
+	require.True(t, ok)
+	assert.Equal(t, "go1.25.0", goVersion.AsString())
+
+	require.NoError(t, r.Close())
+	metrics = collect(t, reader)
+	assert.NotContains(t, metrics, "build_info")
+	assert.NotContains(t, metrics, "process.uptime")
+	assert.Contains(t, metrics, "process.runtime.go.goroutines")
+}
+
+// TestRuntime_Disable tests that process and Go runtime metrics can be disabled.
+func TestRuntime_Disable(t *testing.T) {
+	t.Parallel()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	r := New(Config{DisableProcessMetrics: true, DisableGoMetrics: true}, WithMeterProvider(provider))
+	require.NoError(t, r.Start())
+	defer func() { _ = r.Close() }()
+
+	metrics := collect(t, reader)
+	assert.Contains(t, metrics, "build_info")
+	assert.NotContains(t, metrics, "process.uptime")
+	assert.NotContains(t, metrics, "process.runtime.go.goroutines")
+}
+
+// TestReadBuildInfo tests that ldflags values take precedence and missing values are "unknown".
+func TestReadBuildInfo(t *testing.T) {
+	info := ReadBuildInfo()
+	assert.Equal(t, stdruntime.Version(), info.GoVersion)
+	assert.NotEmpty(t, info.Version)
+	assert.NotEmpty(t, info.Commit)
+
+	Version, Commit = "v9.9.9", "deadbeef"
+	defer func() { Version, Commit = "", "" }()
+	info = ReadBuildInfo()
+	assert.Equal(t, "v9.9.9", info.Version)
+	assert.Equal(t, "deadbeef", info.Commit)
+}
+
+// TestLDFlags tests building -ldflags for build variables.
+func TestLDFlags(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t,
+		"-X 'github.com/pure-golang/adapters/observability/runtime.Version=v1.0.0' "+
+			"-X 'github.com/pure-golang/adapters/observability/runtime.Commit=abc'",
+		LDFlags("v1.0.0", "abc", ""))
+	assert.Empty(t, LDFlags("", "", ""))
+}
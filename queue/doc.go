@@ -5,6 +5,8 @@
 //   - [queue/rabbitmq] — RabbitMQ адаптер
 //   - [queue/kafka] — Kafka адаптер
 //
+// Пакет [queue/inbox] делает обработчики идемпотентными при повторных доставках (transactional inbox).
+//
 // Интерфейсы:
 //   - [Publisher] — отправка сообщений в очередь
 //   - [Subscriber] — получение сообщений из очереди
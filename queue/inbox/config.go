@@ -0,0 +1,36 @@
+package inbox
+
+import "time"
+
+// Значения конфигурации по умолчанию
+const (
+	DefaultTable           = "inbox_messages"
+	DefaultTTL             = 7 * 24 * time.Hour
+	DefaultCleanupInterval = time.Hour
+)
+
+// Config содержит параметры inbox
+type Config struct {
+	// Table — таблица обработанных сообщений, может включать схему ("events.inbox");
+	// имя проверяется pg.ParseTableName
+	Table string `envconfig:"INBOX_TABLE" default:"inbox_messages"`
+	// Consumer — имя потребителя (например, consumer group); одинаковые ID сообщений
+	// разных потребителей не пересекаются
+	Consumer string `envconfig:"INBOX_CONSUMER" required:"true"`
+	// TTL — срок хранения записей; повтор сообщения после TTL будет обработан снова.
+	// Должен превышать максимальную задержку повторной доставки брокером.
+	TTL time.Duration `envconfig:"INBOX_TTL" default:"168h"`
+	// CleanupInterval — период удаления устаревших записей после Start; 0 — очистка не запускается
+	CleanupInterval time.Duration `envconfig:"INBOX_CLEANUP_INTERVAL" default:"1h"`
+}
+
+// withDefaults заполняет незаданные поля значениями по умолчанию
+func (c Config) withDefaults() Config {
+	if c.Table == "" {
+		c.Table = DefaultTable
+	}
+	if c.TTL <= 0 {
+		c.TTL = DefaultTTL
+	}
+	return c
+}
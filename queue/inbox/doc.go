@@ -0,0 +1,32 @@
+// Package inbox реализует transactional inbox — идемпотентную обработку сообщений очередей.
+//
+// ID обработанных сообщений хранятся в таблице PostgreSQL. Запись ID и изменения обработчика
+// выполняются в одной транзакции (db/pg/sqlx), поэтому повторная доставка уже обработанного
+// сообщения подтверждается без вызова обработчика, а сбой обработки откатывает и запись inbox.
+//
+// Использование:
+//
+//	ib := inbox.New(conn, inbox.Config{Consumer: "billing"})
+//	if err := ib.CreateTable(ctx); err != nil { // или миграция из ib.Schema()
+//	    return err
+//	}
+//	if err := ib.Start(); err != nil { // периодическая очистка записей старше TTL
+//	    return err
+//	}
+//	defer ib.Close()
+//
+//	sub.Listen(ib.Wrap(func(ctx context.Context, d queue.Delivery) (bool, error) {
+//	    // conn.Exec(ctx, ...) выполняется в транзакции inbox
+//	    return false, handle(ctx, d)
+//	}))
+//
+// ID сообщения читается из заголовка [MessageIDHeader] ("message-id"); другой источник
+// задаётся [WithMessageID]. Сообщения без ID обрабатываются без дедупликации.
+//
+// Конфигурация через переменные окружения:
+//
+//	INBOX_TABLE            — таблица inbox, может включать схему (default: inbox_messages)
+//	INBOX_CONSUMER         — имя потребителя (required)
+//	INBOX_TTL              — срок хранения записей (default: 168h)
+//	INBOX_CLEANUP_INTERVAL — период очистки после Start; 0 — не запускается (default: 1h)
+package inbox
@@ -0,0 +1,104 @@
+package inbox
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/pure-golang/adapters/db/pg/sqlx"
+	"github.com/pure-golang/adapters/logger"
+	"github.com/pure-golang/adapters/queue"
+)
+
+// MessageIDHeader — заголовок сообщения с его уникальным ID по умолчанию
+const MessageIDHeader = "message-id"
+
+// Option настраивает Wrap
+type Option func(*handlerConfig)
+
+// WithMessageID задаёт функцию извлечения ID сообщения, например из ключа или тела события.
+// Пустой ID означает, что сообщение обрабатывается без дедупликации.
+func WithMessageID(fn func(d queue.Delivery) string) Option {
+	return func(c *handlerConfig) {
+		c.messageID = fn
+	}
+}
+
+// WithTxOptions задаёт опции транзакции, в которой выполняется обработчик
+func WithTxOptions(opts *sqlx.TxOptions) Option {
+	return func(c *handlerConfig) {
+		c.txOptions = opts
+	}
+}
+
+type handlerConfig struct {
+	messageID func(d queue.Delivery) string
+	txOptions *sqlx.TxOptions
+}
+
+// headerMessageID возвращает ID сообщения из заголовка MessageIDHeader
+func headerMessageID(d queue.Delivery) string {
+	return d.Headers[MessageIDHeader]
+}
+
+// Wrap делает обработчик идемпотентным: ID сообщения записывается в inbox в одной транзакции
+// с обработкой, и повторная доставка уже обработанного сообщения подтверждается без вызова next.
+//
+// Транзакция передаётся в next через контекст (sqlx.WithTx): запросы обработчика через
+// sqlx.Connection с этим контекстом фиксируются или откатываются вместе с записью inbox.
+// При ошибке next запись откатывается, и повтор будет обработан снова; флаг retry возвращается
+// от next. Ошибки inbox и фиксации транзакции считаются временными (retry = true).
+//
+// Подходит для подписчиков queue/kafka и queue/rabbitmq:
+//
+//	sub.Listen(ib.Wrap(handler))
+func (i *Inbox) Wrap(next queue.Handler, opts ...Option) queue.Handler {
+	cfg := &handlerConfig{messageID: headerMessageID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx context.Context, d queue.Delivery) (bool, error) {
+		messageID := cfg.messageID(d)
+		if messageID == "" {
+			logger.FromContext(ctx).Warn("message without id is processed without deduplication", "consumer", i.cfg.Consumer)
+			return next(ctx, d)
+		}
+
+		ctx, span := startSpan(ctx, "Handle",
+			attribute.String("messaging.message.id", messageID),
+			attribute.String("messaging.consumer.group.name", i.cfg.Consumer),
+		)
+		defer span.End()
+
+		var retry, duplicate, handlerFailed bool
+		err := i.db.RunTx(ctx, cfg.txOptions, func(ctx context.Context, _ *sqlx.Tx) error {
+			first, err := i.MarkProcessed(ctx, messageID)
+			if err != nil {
+				return err
+			}
+			if !first {
+				duplicate = true
+				return nil
+			}
+			var handlerErr error
+			retry, handlerErr = next(ctx, d)
+			handlerFailed = handlerErr != nil
+			return handlerErr
+		})
+		span.SetAttributes(attribute.Bool("inbox.duplicate", duplicate))
+		if err != nil {
+			recordError(span, err)
+			if !handlerFailed {
+				// Ошибка самого inbox или фиксации транзакции — сообщение стоит повторить
+				retry = true
+			}
+			return retry, err
+		}
+
+		if duplicate {
+			logger.FromContext(ctx).Debug("duplicate message skipped", "message_id", messageID, "consumer", i.cfg.Consumer)
+		}
+		return false, nil
+	}
+}
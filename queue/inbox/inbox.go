@@ -0,0 +1,209 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/db/pg/sqlx"
+	"github.com/pure-golang/adapters/logger"
+)
+
+// DB — операции PostgreSQL, необходимые inbox; реализуется *sqlx.Connection.
+// Exec должен выполняться в транзакции из контекста (sqlx.WithTx), как методы sqlx.Connection.
+type DB interface {
+	Exec(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Get(ctx context.Context, dst any, query string, args ...any) error
+	RunTx(ctx context.Context, opts *sqlx.TxOptions, fn sqlx.TxFunc) error
+}
+
+var _ DB = (*sqlx.Connection)(nil)
+
+// Inbox хранит ID обработанных сообщений для идемпотентной обработки повторных доставок
+type Inbox struct {
+	db  DB
+	cfg Config
+	// table — проверенное имя таблицы; tableErr — ошибка проверки Config.Table,
+	// возвращаемая всеми методами, обращающимися к таблице
+	table    string
+	tableErr error
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New создаёт inbox поверх подключения к PostgreSQL.
+// Таблица создаётся CreateTable или миграцией из Schema.
+// Имя таблицы проверяется pg.ParseTableName; ошибка проверки возвращается методами inbox.
+func New(db DB, cfg Config) *Inbox {
+	cfg = cfg.withDefaults()
+	i := &Inbox{db: db, cfg: cfg}
+	table, err := pg.ParseTableName(cfg.Table)
+	if err != nil {
+		i.tableErr = errors.Wrap(err, "invalid inbox table")
+		return i
+	}
+	i.table = table.String()
+	return i
+}
+
+// Schema возвращает DDL таблицы inbox для миграций
+func (i *Inbox) Schema() (string, error) {
+	if i.tableErr != nil {
+		return "", i.tableErr
+	}
+	index := pg.QuoteIdentifier(strings.ReplaceAll(i.cfg.Table, ".", "_") + "_processed_at_idx")
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	consumer     text NOT NULL,
+	message_id   text NOT NULL,
+	processed_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (consumer, message_id)
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (processed_at)`, i.table, index), nil
+}
+
+// CreateTable создаёт таблицу inbox, если она не существует
+func (i *Inbox) CreateTable(ctx context.Context) error {
+	schema, err := i.Schema()
+	if err != nil {
+		return err
+	}
+	if _, err := i.db.Exec(ctx, schema); err != nil {
+		return errors.Wrap(err, "failed to create inbox table")
+	}
+	return nil
+}
+
+// MarkProcessed записывает сообщение как обработанное.
+// Возвращает false, если сообщение уже было записано, — его следует пропустить.
+// Запись выполняется в транзакции из контекста, поэтому откатывается вместе с ней;
+// конкурентная доставка того же сообщения ждёт завершения этой транзакции.
+func (i *Inbox) MarkProcessed(ctx context.Context, messageID string) (bool, error) {
+	if i.tableErr != nil {
+		return false, i.tableErr
+	}
+	query := "INSERT INTO " + i.table + " (consumer, message_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	result, err := i.db.Exec(ctx, query, i.cfg.Consumer, messageID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark message as processed")
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get affected rows")
+	}
+	return affected == 1, nil
+}
+
+// IsProcessed проверяет, было ли сообщение обработано
+func (i *Inbox) IsProcessed(ctx context.Context, messageID string) (bool, error) {
+	if i.tableErr != nil {
+		return false, i.tableErr
+	}
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM " + i.table + " WHERE consumer = $1 AND message_id = $2)"
+	if err := i.db.Get(ctx, &exists, query, i.cfg.Consumer, messageID); err != nil {
+		return false, errors.Wrap(err, "failed to check processed message")
+	}
+	return exists, nil
+}
+
+// Cleanup удаляет записи старше TTL всех потребителей таблицы и возвращает их количество
+func (i *Inbox) Cleanup(ctx context.Context) (int64, error) {
+	if i.tableErr != nil {
+		return 0, i.tableErr
+	}
+	ctx, span := startSpan(ctx, "Cleanup")
+	defer span.End()
+
+	query := "DELETE FROM " + i.table + " WHERE processed_at < now() - make_interval(secs => $1)"
+	result, err := i.db.Exec(ctx, query, i.cfg.TTL.Seconds())
+	if err != nil {
+		recordError(span, err)
+		return 0, errors.Wrap(err, "failed to delete expired inbox records")
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return 0, errors.Wrap(err, "failed to get affected rows")
+	}
+	return deleted, nil
+}
+
+// Start запускает периодическую очистку устаревших записей с интервалом CleanupInterval.
+// Ошибки очистки логируются; повторный вызов Start не запускает вторую очистку.
+// Недопустимое имя таблицы — ошибка Start.
+func (i *Inbox) Start() error {
+	if i.tableErr != nil {
+		return i.tableErr
+	}
+	if i.cfg.CleanupInterval <= 0 {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.stop != nil {
+		return nil
+	}
+	i.stop = make(chan struct{})
+	i.done = make(chan struct{})
+	go i.cleanupLoop(i.stop, i.done)
+	return nil
+}
+
+// Close останавливает периодическую очистку и ждёт завершения текущей
+func (i *Inbox) Close() error {
+	i.mu.Lock()
+	stop, done := i.stop, i.done
+	i.stop, i.done = nil, nil
+	i.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	return nil
+}
+
+// cleanupLoop удаляет устаревшие записи до закрытия stop
+func (i *Inbox) cleanupLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(i.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := i.Cleanup(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.FromContext(ctx).With("error", err).Error("failed to clean up inbox", "table", i.cfg.Table)
+				}
+				continue
+			}
+			if deleted > 0 {
+				logger.FromContext(ctx).Debug("inbox cleaned up", "table", i.cfg.Table, "deleted", deleted)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/db/pg/sqlx"
+	"github.com/pure-golang/adapters/queue"
+)
+
+type pendingKey struct{}
+
+// fakeDB emulates the inbox table with transactional inserts
+type fakeDB struct {
+	mu        sync.Mutex
+	processed map[string]bool
+	cleanups  int
+	execErr   error
+	commitErr error
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{processed: make(map[string]bool)}
+}
+
+func (f *fakeDB) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.execErr != nil {
+		return nil, f.execErr
+	}
+	switch {
+	case strings.HasPrefix(query, "INSERT"):
+		key := args[0].(string) + "/" + args[1].(string)
+		pending, _ := ctx.Value(pendingKey{}).(map[string]bool)
+		if f.processed[key] || pending[key] {
+			return driver.RowsAffected(0), nil
+		}
+		if pending != nil {
+			pending[key] = true
+		} else {
+			f.processed[key] = true
+		}
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "DELETE"):
+		f.cleanups++
+		return driver.RowsAffected(0), nil
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (f *fakeDB) Get(_ context.Context, dst any, _ string, args ...any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	*dst.(*bool) = f.processed[args[0].(string)+"/"+args[1].(string)]
+	return nil
+}
+
+func (f *fakeDB) RunTx(ctx context.Context, _ *sqlx.TxOptions, fn sqlx.TxFunc) error {
+	pending := make(map[string]bool)
+	if err := fn(context.WithValue(ctx, pendingKey{}, pending), nil); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.commitErr != nil {
+		return f.commitErr
+	}
+	for key := range pending {
+		f.processed[key] = true
+	}
+	return nil
+}
+
+func (f *fakeDB) cleanupCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cleanups
+}
+
+func delivery(id string) queue.Delivery {
+	return queue.Delivery{Headers: map[string]string{MessageIDHeader: id}, Body: []byte("body")}
+}
+
+// TestInbox_Wrap tests that redelivered messages are handled once.
+func TestInbox_Wrap(t *testing.T) {
+	t.Parallel()
+	db := newFakeDB()
+	ib := New(db, Config{Consumer: "billing"})
+	ctx := context.Background()
+
+	var calls int
+	var fail bool
+	handler := ib.Wrap(func(_ context.Context, _ queue.Delivery) (bool, error) {
+		calls++
+		if fail {
+			return true, errors.New("temporary failure")
+		}
+		return false, nil
+	})
+
+	retry, err := handler(ctx, delivery("m1"))
+	require.NoError(t, err)
+	assert.False(t, retry)
+	assert.Equal(t, 1, calls)
+
+	// redelivery is acknowledged without calling the handler
+	retry, err = handler(ctx, delivery("m1"))
+	require.NoError(t, err)
+	assert.False(t, retry)
+	assert.Equal(t, 1, calls)
+
+	processed, err := ib.IsProcessed(ctx, "m1")
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	// failed handling is rolled back and retried
+	fail = true
+	retry, err = handler(ctx, delivery("m2"))
+	require.Error(t, err)
+	assert.True(t, retry)
+	assert.Equal(t, 2, calls)
+
+	fail = false
+	_, err = handler(ctx, delivery("m2"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	// the same id of another consumer is not a duplicate
+	other := New(db, Config{Consumer: "shipping"}).Wrap(func(context.Context, queue.Delivery) (bool, error) {
+		calls++
+		return false, nil
+	})
+	_, err = other(ctx, delivery("m1"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+// TestInbox_Wrap_WithoutID tests that messages without id are handled every time.
+func TestInbox_Wrap_WithoutID(t *testing.T) {
+	t.Parallel()
+	ib := New(newFakeDB(), Config{Consumer: "billing"})
+
+	var calls int
+	handler := ib.Wrap(func(context.Context, queue.Delivery) (bool, error) {
+		calls++
+		return false, nil
+	})
+	for range 2 {
+		_, err := handler(context.Background(), queue.Delivery{Body: []byte("body")})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+// TestInbox_Wrap_WithMessageID tests a custom message id extractor.
+func TestInbox_Wrap_WithMessageID(t *testing.T) {
+	t.Parallel()
+	ib := New(newFakeDB(), Config{Consumer: "billing"})
+
+	var calls int
+	handler := ib.Wrap(func(context.Context, queue.Delivery) (bool, error) {
+		calls++
+		return false, nil
+	}, WithMessageID(func(d queue.Delivery) string { return string(d.Body) }))
+
+	for range 2 {
+		_, err := handler(context.Background(), queue.Delivery{Body: []byte("event-1")})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+// TestInbox_Wrap_InfraErrors tests that inbox and commit errors are retryable.
+func TestInbox_Wrap_InfraErrors(t *testing.T) {
+	t.Parallel()
+	next := func(context.Context, queue.Delivery) (bool, error) { return false, nil }
+
+	db := newFakeDB()
+	db.execErr = errors.New("connection refused")
+	retry, err := New(db, Config{Consumer: "billing"}).Wrap(next)(context.Background(), delivery("m1"))
+	require.ErrorContains(t, err, "failed to mark message as processed")
+	assert.True(t, retry)
+
+	db = newFakeDB()
+	db.commitErr = errors.New("serialization failure")
+	retry, err = New(db, Config{Consumer: "billing"}).Wrap(next)(context.Background(), delivery("m1"))
+	require.Error(t, err)
+	assert.True(t, retry)
+}
+
+// TestInbox_Schema tests that table and index names are quoted.
+func TestInbox_Schema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := New(newFakeDB(), Config{Table: "events.inbox"}).Schema()
+	require.NoError(t, err)
+	assert.Contains(t, schema, `CREATE TABLE IF NOT EXISTS "events"."inbox"`)
+	assert.Contains(t, schema, `CREATE INDEX IF NOT EXISTS "events_inbox_processed_at_idx" ON "events"."inbox" (processed_at)`)
+
+	schema, err = New(newFakeDB(), Config{}).Schema()
+	require.NoError(t, err)
+	assert.Contains(t, schema, `"inbox_messages"`)
+}
+
+// TestInbox_InvalidTable tests that an invalid table name fails every table operation without a query.
+func TestInbox_InvalidTable(t *testing.T) {
+	t.Parallel()
+	ib := New(newFakeDB(), Config{Consumer: "billing", Table: `inbox"; DROP TABLE users; --`})
+
+	_, err := ib.Schema()
+	require.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	assert.ErrorContains(t, err, "invalid inbox table")
+	require.ErrorIs(t, ib.CreateTable(context.Background()), pg.ErrInvalidIdentifier)
+	_, err = ib.MarkProcessed(context.Background(), "m1")
+	require.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	_, err = ib.IsProcessed(context.Background(), "m1")
+	require.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	_, err = ib.Cleanup(context.Background())
+	require.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	require.ErrorIs(t, ib.Start(), pg.ErrInvalidIdentifier)
+}
+
+// TestInbox_StartClose tests periodic cleanup.
+func TestInbox_StartClose(t *testing.T) {
+	t.Parallel()
+	db := newFakeDB()
+	ib := New(db, Config{Consumer: "billing", CleanupInterval: 10 * time.Millisecond})
+
+	require.NoError(t, ib.Start())
+	require.NoError(t, ib.Start())
+	assert.Eventually(t, func() bool { return db.cleanupCount() >= 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, ib.Close())
+
+	count := db.cleanupCount()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, count, db.cleanupCount())
+	require.NoError(t, ib.Close())
+}
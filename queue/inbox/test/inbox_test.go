@@ -0,0 +1,75 @@
+package inbox_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/db/pg/pgtest"
+	"github.com/pure-golang/adapters/queue"
+	"github.com/pure-golang/adapters/queue/inbox"
+)
+
+// TestInbox tests deduplication and transactional handling against PostgreSQL.
+func TestInbox(t *testing.T) {
+	pg := pgtest.StartPostgres(t, &pgtest.Options{
+		Setup: func(ctx context.Context, db *sql.DB) error {
+			_, err := db.ExecContext(ctx, `CREATE TABLE payments (message_id text PRIMARY KEY)`)
+			return err
+		},
+	})
+	conn := pg.Connect(t)
+	ctx := context.Background()
+
+	ib := inbox.New(conn, inbox.Config{Consumer: "billing", TTL: time.Hour})
+	require.NoError(t, ib.CreateTable(ctx))
+	require.NoError(t, ib.CreateTable(ctx), "schema is idempotent")
+
+	var fail bool
+	handler := ib.Wrap(func(ctx context.Context, d queue.Delivery) (bool, error) {
+		// the write joins the inbox transaction through the context
+		if _, err := conn.Exec(ctx, `INSERT INTO payments (message_id) VALUES ($1)`, d.Headers[inbox.MessageIDHeader]); err != nil {
+			return true, err
+		}
+		if fail {
+			return true, errors.New("temporary failure")
+		}
+		return false, nil
+	})
+	delivery := queue.Delivery{Headers: map[string]string{inbox.MessageIDHeader: "m1"}}
+
+	fail = true
+	retry, err := handler(ctx, delivery)
+	require.Error(t, err)
+	assert.True(t, retry)
+
+	var payments int
+	require.NoError(t, conn.Get(ctx, &payments, `SELECT count(*) FROM payments`))
+	assert.Zero(t, payments, "handler writes are rolled back with the inbox record")
+	processed, err := ib.IsProcessed(ctx, "m1")
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	fail = false
+	for range 3 {
+		_, err = handler(ctx, delivery)
+		require.NoError(t, err)
+	}
+	require.NoError(t, conn.Get(ctx, &payments, `SELECT count(*) FROM payments`))
+	assert.Equal(t, 1, payments)
+
+	deleted, err := ib.Cleanup(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	_, err = conn.Exec(ctx, `UPDATE inbox_messages SET processed_at = now() - interval '2 hours'`)
+	require.NoError(t, err)
+	deleted, err = ib.Cleanup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
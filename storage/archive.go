@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat is the archive format for ArchivePrefix.
+type ArchiveFormat string
+
+const (
+	ArchiveTar ArchiveFormat = "tar"
+	ArchiveZip ArchiveFormat = "zip"
+)
+
+// Archive defaults.
+const (
+	DefaultArchiveConcurrency = 4
+	DefaultManifestName       = "manifest.json"
+)
+
+// ArchiveOptions contains optional parameters for ArchivePrefix.
+type ArchiveOptions struct {
+	Concurrency  int    // Max concurrent GETs (default 4)
+	Manifest     bool   // Append a JSON manifest describing archived objects
+	ManifestName string // Manifest entry name (default "manifest.json")
+}
+
+// ManifestEntry describes an archived object in the manifest.
+type ManifestEntry struct {
+	Name         string    `json:"name"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// archiveWriter abstracts tar and zip writers.
+type archiveWriter interface {
+	WriteEntry(name string, size int64, modified time.Time, r io.Reader) error
+	Close() error
+}
+
+// ArchivePrefix streams all objects under prefix into a tar or zip archive written to w.
+//
+// Objects are not staged on disk: up to Concurrency GET requests are opened ahead
+// while bodies are copied into the archive one by one in key order.
+// Entry names are object keys relative to the prefix directory
+// (for prefix "reports/2024/" the key "reports/2024/jan.csv" becomes "jan.csv").
+func ArchivePrefix(ctx context.Context, s Storage, bucket, prefix string, w io.Writer, format ArchiveFormat, opts *ArchiveOptions) error {
+	if opts == nil {
+		opts = &ArchiveOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultArchiveConcurrency
+	}
+	manifestName := opts.ManifestName
+	if manifestName == "" {
+		manifestName = DefaultManifestName
+	}
+
+	var aw archiveWriter
+	switch format {
+	case ArchiveTar:
+		aw = &tarArchive{w: tar.NewWriter(w)}
+	case ArchiveZip:
+		aw = &zipArchive{w: zip.NewWriter(w)}
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+
+	list, err := s.List(ctx, bucket, &ListOptions{Prefix: prefix, Recursive: true})
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	manifest, err := writeObjects(ctx, s, bucket, prefix, list.Objects, aw, concurrency)
+	if err != nil {
+		return err
+	}
+
+	if opts.Manifest {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		err = aw.WriteEntry(manifestName, int64(len(data)), time.Now(), bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	if err := aw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return nil
+}
+
+// fetchedObject is an opened object body.
+type fetchedObject struct {
+	body     io.ReadCloser
+	info     *ObjectInfo
+	err      error
+	acquired bool // the fetch holds a concurrency slot
+}
+
+// writeObjects opens objects concurrently and writes them into the archive in order.
+func writeObjects(ctx context.Context, s Storage, bucket, prefix string, objects []ObjectInfo, aw archiveWriter, concurrency int) ([]ManifestEntry, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan fetchedObject, len(objects))
+	for i := range results {
+		results[i] = make(chan fetchedObject, 1)
+	}
+	slots := make(chan struct{}, concurrency)
+
+	go func() {
+		for i, obj := range objects {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] <- fetchedObject{err: ctx.Err()}
+				continue
+			}
+			go func() {
+				body, info, err := s.Get(ctx, bucket, obj.Key)
+				results[i] <- fetchedObject{body: body, info: info, err: err, acquired: true}
+			}()
+		}
+	}()
+
+	// release closes the body and frees the slot
+	release := func(f fetchedObject) {
+		if f.body != nil {
+			_ = f.body.Close()
+		}
+		if f.acquired {
+			<-slots
+		}
+	}
+
+	// abort cancels pending fetches and releases already opened bodies
+	abort := func(from int, err error) ([]ManifestEntry, error) {
+		cancel()
+		for _, ch := range results[from:] {
+			release(<-ch)
+		}
+		return nil, err
+	}
+
+	dir := prefix[:strings.LastIndex(prefix, "/")+1]
+	manifest := make([]ManifestEntry, 0, len(objects))
+
+	for i, obj := range objects {
+		f := <-results[i]
+		if f.err != nil {
+			release(f)
+			return abort(i+1, fmt.Errorf("failed to get object %s: %w", obj.Key, f.err))
+		}
+
+		entry := ManifestEntry{
+			Name:         archiveEntryName(dir, obj.Key),
+			Key:          obj.Key,
+			Size:         obj.Size,
+			ETag:         obj.ETag,
+			ContentType:  obj.ContentType,
+			LastModified: obj.LastModified,
+		}
+		if f.info != nil {
+			entry.Size = f.info.Size
+			if f.info.ContentType != "" {
+				entry.ContentType = f.info.ContentType
+			}
+		}
+
+		err := aw.WriteEntry(entry.Name, entry.Size, entry.LastModified, f.body)
+		release(f)
+		if err != nil {
+			return abort(i+1, fmt.Errorf("failed to write object %s: %w", obj.Key, err))
+		}
+		manifest = append(manifest, entry)
+	}
+
+	return manifest, nil
+}
+
+// archiveEntryName returns the key relative to dir; keys equal to dir fall back to the base name.
+func archiveEntryName(dir, key string) string {
+	name := strings.TrimPrefix(key, dir)
+	if name == "" {
+		return path.Base(key)
+	}
+	return name
+}
+
+type tarArchive struct {
+	w *tar.Writer
+}
+
+func (a *tarArchive) WriteEntry(name string, size int64, modified time.Time, r io.Reader) error {
+	err := a.w.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: modified,
+		Format:  tar.FormatPAX,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(a.w, r)
+	return err
+}
+
+func (a *tarArchive) Close() error {
+	return a.w.Close()
+}
+
+type zipArchive struct {
+	w *zip.Writer
+}
+
+func (a *zipArchive) WriteEntry(name string, _ int64, modified time.Time, r io.Reader) error {
+	fw, err := a.w.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+func (a *zipArchive) Close() error {
+	return a.w.Close()
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveStorage is an in-memory Storage serving Get and List for archive tests
+type archiveStorage struct {
+	Storage
+
+	objects map[string]string
+	getErr  map[string]error
+
+	mu      sync.Mutex
+	open    int
+	maxOpen int
+	closed  atomic.Int32
+}
+
+func (s *archiveStorage) List(_ context.Context, _ string, opts *ListOptions) (*ListResult, error) {
+	var objects []ObjectInfo
+	for key, data := range s.objects {
+		if strings.HasPrefix(key, opts.Prefix) {
+			objects = append(objects, ObjectInfo{
+				Key:          key,
+				Size:         int64(len(data)),
+				ETag:         "etag-" + key,
+				LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return &ListResult{Objects: objects}, nil
+}
+
+func (s *archiveStorage) Get(_ context.Context, _, key string) (io.ReadCloser, *ObjectInfo, error) {
+	if err := s.getErr[key]; err != nil {
+		return nil, nil, err
+	}
+	s.mu.Lock()
+	s.open++
+	s.maxOpen = max(s.maxOpen, s.open)
+	s.mu.Unlock()
+
+	data := s.objects[key]
+	return &trackedBody{Reader: strings.NewReader(data), s: s}, &ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+type trackedBody struct {
+	io.Reader
+	s *archiveStorage
+}
+
+func (b *trackedBody) Close() error {
+	b.s.mu.Lock()
+	b.s.open--
+	b.s.mu.Unlock()
+	b.s.closed.Add(1)
+	return nil
+}
+
+func newArchiveStorage() *archiveStorage {
+	return &archiveStorage{
+		objects: map[string]string{
+			"reports/2024/jan.csv":       "a,b\n1,2\n",
+			"reports/2024/feb.csv":       "a,b\n3,4\n",
+			"reports/2024/q1/total.json": `{"total":10}`,
+			"reports/2025/jan.csv":       "other",
+		},
+	}
+}
+
+func readTar(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(body)
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(body)
+	}
+	return files
+}
+
+func TestArchivePrefix_Tar(t *testing.T) {
+	t.Parallel()
+	s := newArchiveStorage()
+	var buf bytes.Buffer
+
+	err := ArchivePrefix(context.Background(), s, "bucket", "reports/2024/", &buf, ArchiveTar, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"jan.csv":       "a,b\n1,2\n",
+		"feb.csv":       "a,b\n3,4\n",
+		"q1/total.json": `{"total":10}`,
+	}, readTar(t, buf.Bytes()))
+	assert.Equal(t, int32(3), s.closed.Load(), "all bodies must be closed")
+}
+
+func TestArchivePrefix_ZipWithManifest(t *testing.T) {
+	t.Parallel()
+	s := newArchiveStorage()
+	var buf bytes.Buffer
+
+	err := ArchivePrefix(context.Background(), s, "bucket", "reports/2024/", &buf, ArchiveZip, &ArchiveOptions{
+		Manifest: true,
+	})
+
+	require.NoError(t, err)
+	files := readZip(t, buf.Bytes())
+	require.Contains(t, files, DefaultManifestName)
+	assert.Equal(t, `{"total":10}`, files["q1/total.json"])
+
+	var manifest []ManifestEntry
+	require.NoError(t, json.Unmarshal([]byte(files[DefaultManifestName]), &manifest))
+	require.Len(t, manifest, 3)
+	assert.Equal(t, "feb.csv", manifest[0].Name)
+	assert.Equal(t, "reports/2024/feb.csv", manifest[0].Key)
+	assert.Equal(t, int64(8), manifest[0].Size)
+	assert.Equal(t, "etag-reports/2024/feb.csv", manifest[0].ETag)
+}
+
+func TestArchivePrefix_PartialPrefixName(t *testing.T) {
+	t.Parallel()
+	s := newArchiveStorage()
+	var buf bytes.Buffer
+
+	err := ArchivePrefix(context.Background(), s, "bucket", "reports/2024/j", &buf, ArchiveTar, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"jan.csv": "a,b\n1,2\n"}, readTar(t, buf.Bytes()),
+		"names are relative to the prefix directory")
+}
+
+func TestArchivePrefix_LimitsConcurrency(t *testing.T) {
+	t.Parallel()
+	s := &archiveStorage{objects: make(map[string]string)}
+	for i := range 20 {
+		s.objects["data/"+string(rune('a'+i))] = strings.Repeat("x", i)
+	}
+
+	err := ArchivePrefix(context.Background(), s, "bucket", "data/", io.Discard, ArchiveTar, &ArchiveOptions{
+		Concurrency: 2,
+	})
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, s.maxOpen, 2)
+	assert.Equal(t, int32(20), s.closed.Load())
+}
+
+func TestArchivePrefix_GetError(t *testing.T) {
+	t.Parallel()
+	s := newArchiveStorage()
+	s.getErr = map[string]error{"reports/2024/jan.csv": ErrAccessDenied}
+
+	err := ArchivePrefix(context.Background(), s, "bucket", "reports/2024/", io.Discard, ArchiveZip, nil)
+
+	require.Error(t, err)
+	assert.True(t, IsAccessDenied(err))
+	assert.Contains(t, err.Error(), "reports/2024/jan.csv")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Zero(t, s.open, "opened bodies must be closed on error")
+}
+
+func TestArchivePrefix_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	err := ArchivePrefix(context.Background(), newArchiveStorage(), "bucket", "", io.Discard, "rar", nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported archive format")
+}
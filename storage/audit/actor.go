@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/pure-golang/adapters/crypto/token"
+)
+
+type actorKey struct{}
+
+// WithActor возвращает контекст с инициатором операций для журнала аудита
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает инициатора, сохранённого WithActor, иначе Subject токена
+// из token.ClaimsFromContext; пустая строка — инициатор неизвестен
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	if claims, ok := token.ClaimsFromContext(ctx); ok {
+		return claims.Subject
+	}
+	return ""
+}
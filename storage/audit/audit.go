@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+)
+
+// Action — тип изменяющей операции
+type Action string
+
+const (
+	ActionPut                     Action = "put"
+	ActionDelete                  Action = "delete"
+	ActionCompleteMultipartUpload Action = "complete_multipart_upload"
+)
+
+// Record — запись журнала аудита об изменяющей операции.
+// Записи только добавляются: журнал восстанавливает историю изменений объектов.
+type Record struct {
+	Time        time.Time `json:"time"`
+	Action      Action    `json:"action"`
+	Bucket      string    `json:"bucket"`
+	Key         string    `json:"key"`
+	Actor       string    `json:"actor,omitempty"`        // инициатор операции, см. ActorFromContext
+	Size        int64     `json:"size,omitempty"`         // размер записанного объекта
+	ETag        string    `json:"etag,omitempty"`         // известен только для CompleteMultipartUpload
+	ContentType string    `json:"content_type,omitempty"` // тип содержимого из PutOptions или ObjectInfo
+	UploadID    string    `json:"upload_id,omitempty"`    // ID multipart загрузки
+	Error       string    `json:"error,omitempty"`        // ошибка операции; пусто — операция выполнена
+}
+
+// Options содержит настройки Storage
+type Options struct {
+	// Sink принимает записи аудита; по умолчанию LogSink(Logger)
+	Sink Sink
+	// Actor извлекает инициатора операции из контекста; по умолчанию ActorFromContext
+	Actor func(ctx context.Context) string
+	// SkipFailed отключает запись неудавшихся операций; по умолчанию они записываются с Error
+	SkipFailed bool
+	// Logger для ошибок записи в Sink и LogSink по умолчанию; по умолчанию slog.Default()
+	Logger *slog.Logger
+}
+
+// Storage — декоратор storage.Storage, записывающий каждую изменяющую операцию
+// (Put, Delete, CompleteMultipartUpload) в журнал аудита.
+// Операции, не изменяющие данные, передаются в исходное хранилище без изменений.
+type Storage struct {
+	storage.Storage
+
+	sink       Sink
+	actor      func(ctx context.Context) string
+	skipFailed bool
+	logger     *slog.Logger
+}
+
+// New создаёт Storage поверх inner
+func New(inner storage.Storage, opts *Options) *Storage {
+	if opts == nil {
+		opts = &Options{}
+	}
+	actor := opts.Actor
+	if actor == nil {
+		actor = ActorFromContext
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+	sink := opts.Sink
+	if sink == nil {
+		sink = LogSink(log)
+	}
+
+	return &Storage{
+		Storage:    inner,
+		sink:       sink,
+		actor:      actor,
+		skipFailed: opts.SkipFailed,
+		logger:     log.WithGroup("storage").With("decorator", "audit"),
+	}
+}
+
+// Put сохраняет объект и записывает размер записанных данных
+func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
+	counter := &countingReader{reader: reader}
+	err := s.Storage.Put(ctx, bucket, key, counter, opts)
+
+	record := Record{Action: ActionPut, Bucket: bucket, Key: key, Size: counter.n}
+	if opts != nil {
+		record.ContentType = opts.ContentType
+	}
+	s.record(ctx, record, err)
+	return err
+}
+
+// Delete удаляет объект
+func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
+	err := s.Storage.Delete(ctx, bucket, key)
+	s.record(ctx, Record{Action: ActionDelete, Bucket: bucket, Key: key}, err)
+	return err
+}
+
+// CompleteMultipartUpload завершает multipart загрузку
+func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, opts *storage.CompleteMultipartUploadOptions) (*storage.ObjectInfo, error) {
+	info, err := s.Storage.CompleteMultipartUpload(ctx, bucket, key, uploadID, opts)
+
+	record := Record{Action: ActionCompleteMultipartUpload, Bucket: bucket, Key: key, UploadID: uploadID}
+	if info != nil {
+		record.Size = info.Size
+		record.ETag = info.ETag
+		record.ContentType = info.ContentType
+	}
+	s.record(ctx, record, err)
+	return info, err
+}
+
+// GetWithOptions передаёт запрос исходному хранилищу, если оно реализует storage.OptionsGetter
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
+	getter, ok := s.Storage.(storage.OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	return getter.GetWithOptions(ctx, bucket, key, opts)
+}
+
+// record дополняет запись временем, инициатором и ошибкой и передаёт её в Sink.
+// Ошибка Sink логируется и не влияет на результат операции, которая уже выполнена.
+func (s *Storage) record(ctx context.Context, record Record, opErr error) {
+	if opErr != nil {
+		if s.skipFailed {
+			return
+		}
+		record.Error = opErr.Error()
+	}
+	record.Time = time.Now().UTC()
+	record.Actor = s.actor(ctx)
+
+	if err := s.sink.Write(ctx, record); err != nil {
+		s.logger.With("error", err).Error("failed to write audit record",
+			"action", record.Action, "bucket", record.Bucket, "key", record.Key, "actor", record.Actor)
+	}
+}
+
+// countingReader считает прочитанные байты
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
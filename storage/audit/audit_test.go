@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/crypto/token"
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/queue"
+	"github.com/pure-golang/adapters/storage"
+)
+
+// fakeStorage accepts writes and fails operations on keys from failKeys
+type fakeStorage struct {
+	storage.Storage
+	failKeys map[string]bool
+}
+
+func (f *fakeStorage) Put(_ context.Context, _, key string, reader io.Reader, _ *storage.PutOptions) error {
+	if f.failKeys[key] {
+		return storage.ErrAccessDenied
+	}
+	_, err := io.Copy(io.Discard, reader)
+	return err
+}
+
+func (f *fakeStorage) Delete(_ context.Context, _, key string) error {
+	if f.failKeys[key] {
+		return storage.ErrAccessDenied
+	}
+	return nil
+}
+
+func (f *fakeStorage) CompleteMultipartUpload(_ context.Context, _, key, _ string, _ *storage.CompleteMultipartUploadOptions) (*storage.ObjectInfo, error) {
+	return &storage.ObjectInfo{Key: key, Size: 42, ETag: "etag", ContentType: "video/mp4"}, nil
+}
+
+// memorySink collects records
+type memorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (s *memorySink) Write(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// TestStorage_RecordsMutations tests that mutating operations are recorded with the actor.
+func TestStorage_RecordsMutations(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	s := New(&fakeStorage{failKeys: map[string]bool{"locked.txt": true}}, &Options{Sink: sink})
+	ctx := WithActor(context.Background(), "user-1")
+
+	require.NoError(t, s.Put(ctx, "docs", "a.txt", strings.NewReader("hello"), &storage.PutOptions{ContentType: "text/plain"}))
+	require.NoError(t, s.Delete(ctx, "docs", "a.txt"))
+	_, err := s.CompleteMultipartUpload(ctx, "docs", "movie.mp4", "upload-1", nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, s.Delete(ctx, "docs", "locked.txt"), storage.ErrAccessDenied)
+
+	require.Len(t, sink.records, 4)
+	for _, record := range sink.records {
+		assert.Equal(t, "user-1", record.Actor)
+		assert.False(t, record.Time.IsZero())
+		assert.Equal(t, "docs", record.Bucket)
+	}
+
+	put := sink.records[0]
+	assert.Equal(t, ActionPut, put.Action)
+	assert.Equal(t, int64(5), put.Size)
+	assert.Equal(t, "text/plain", put.ContentType)
+	assert.Empty(t, put.Error)
+
+	assert.Equal(t, ActionDelete, sink.records[1].Action)
+
+	complete := sink.records[2]
+	assert.Equal(t, ActionCompleteMultipartUpload, complete.Action)
+	assert.Equal(t, "upload-1", complete.UploadID)
+	assert.Equal(t, "etag", complete.ETag)
+	assert.Equal(t, int64(42), complete.Size)
+
+	assert.Equal(t, "locked.txt", sink.records[3].Key)
+	assert.Equal(t, storage.ErrAccessDenied.Error(), sink.records[3].Error)
+}
+
+// TestStorage_SkipFailed tests that failed operations can be excluded.
+func TestStorage_SkipFailed(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	s := New(&fakeStorage{failKeys: map[string]bool{"locked.txt": true}}, &Options{Sink: sink, SkipFailed: true})
+
+	require.Error(t, s.Put(context.Background(), "docs", "locked.txt", strings.NewReader("x"), nil))
+	assert.Empty(t, sink.records)
+}
+
+// TestStorage_SinkError tests that sink errors are logged and do not fail the operation.
+func TestStorage_SinkError(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	sink := SinkFunc(func(context.Context, Record) error { return errors.New("sink unavailable") })
+	s := New(&fakeStorage{}, &Options{Sink: sink, Logger: slog.New(slog.NewTextHandler(&buf, nil))})
+
+	require.NoError(t, s.Delete(context.Background(), "docs", "a.txt"))
+	assert.Contains(t, buf.String(), "failed to write audit record")
+	assert.Contains(t, buf.String(), "sink unavailable")
+}
+
+// TestActorFromContext tests actor extraction.
+func TestActorFromContext(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert.Empty(t, ActorFromContext(ctx))
+
+	ctx = token.ContextWithClaims(ctx, &token.Claims{Subject: "user-2"})
+	assert.Equal(t, "user-2", ActorFromContext(ctx))
+
+	assert.Equal(t, "service-a", ActorFromContext(WithActor(ctx, "service-a")))
+}
+
+// TestLogSink tests the slog sink.
+func TestLogSink(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	sink := LogSink(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	require.NoError(t, sink.Write(context.Background(), Record{Action: ActionDelete, Bucket: "docs", Key: "a.txt", Actor: "user-1"}))
+	assert.Contains(t, buf.String(), `msg="storage audit" `)
+	assert.Contains(t, buf.String(), "action=delete bucket=docs key=a.txt actor=user-1")
+}
+
+type publisherFunc func(ctx context.Context, msgs ...queue.Message) error
+
+func (f publisherFunc) Publish(ctx context.Context, msgs ...queue.Message) error {
+	return f(ctx, msgs...)
+}
+
+// TestPublisherSink tests publishing records to a topic.
+func TestPublisherSink(t *testing.T) {
+	t.Parallel()
+	var published []queue.Message
+	sink := PublisherSink(publisherFunc(func(_ context.Context, msgs ...queue.Message) error {
+		published = append(published, msgs...)
+		return nil
+	}), "storage.audit")
+
+	record := Record{Action: ActionPut, Bucket: "docs", Key: "a.txt"}
+	require.NoError(t, sink.Write(context.Background(), record))
+	require.Len(t, published, 1)
+	assert.Equal(t, "storage.audit", published[0].Topic)
+	assert.Equal(t, "put", published[0].Headers[ActionHeader])
+	assert.Equal(t, record, published[0].Body)
+}
+
+type execFunc func(ctx context.Context, query string, args ...any) (sql.Result, error)
+
+func (f execFunc) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f(ctx, query, args...)
+}
+
+// TestPostgresSink tests the insert query and schema.
+func TestPostgresSink(t *testing.T) {
+	t.Parallel()
+	var gotQuery string
+	var gotArgs []any
+	sink := NewPostgresSink(execFunc(func(_ context.Context, query string, args ...any) (sql.Result, error) {
+		gotQuery, gotArgs = query, args
+		return driver.RowsAffected(1), nil
+	}), "audit.objects")
+
+	require.NoError(t, sink.Write(context.Background(), Record{Action: ActionPut, Bucket: "docs", Key: "a.txt", Actor: "user-1", Size: 5}))
+	assert.Contains(t, gotQuery, `INSERT INTO "audit"."objects"`)
+	assert.Equal(t, "put", gotArgs[1])
+	assert.Equal(t, "user-1", gotArgs[4])
+	assert.Equal(t, int64(5), gotArgs[5])
+
+	schema, err := sink.Schema()
+	require.NoError(t, err)
+	assert.Contains(t, schema, `CREATE TABLE IF NOT EXISTS "audit"."objects"`)
+	schema, err = NewPostgresSink(nil, "").Schema()
+	require.NoError(t, err)
+	assert.Contains(t, schema, `"storage_audit"`)
+}
+
+// TestPostgresSink_InvalidTable tests that an invalid table name fails Schema and Write without a query.
+func TestPostgresSink_InvalidTable(t *testing.T) {
+	t.Parallel()
+	sink := NewPostgresSink(execFunc(func(context.Context, string, ...any) (sql.Result, error) {
+		t.Fatal("query must not run")
+		return nil, nil
+	}), `audit"; DROP TABLE users; --`)
+
+	_, err := sink.Schema()
+	require.ErrorIs(t, err, pg.ErrInvalidIdentifier)
+	assert.ErrorContains(t, err, "invalid audit table")
+	require.ErrorIs(t, sink.Write(context.Background(), Record{Action: ActionPut}), pg.ErrInvalidIdentifier)
+}
+
+// TestMultiSink tests that all sinks receive the record and errors are combined.
+func TestMultiSink(t *testing.T) {
+	t.Parallel()
+	first, second := &memorySink{}, &memorySink{}
+	failing := SinkFunc(func(context.Context, Record) error { return errors.New("broker down") })
+
+	err := MultiSink(first, failing, second).Write(context.Background(), Record{Action: ActionDelete})
+	require.ErrorContains(t, err, "broker down")
+	assert.Len(t, first.records, 1)
+	assert.Len(t, second.records, 1)
+}
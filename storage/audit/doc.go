@@ -0,0 +1,36 @@
+// Package audit реализует декоратор [storage.Storage], ведущий журнал аудита изменений.
+//
+// Каждая изменяющая операция (Put, Delete, CompleteMultipartUpload) записывается как [Record]
+// с инициатором, временем, размером и ошибкой в подключаемый [Sink] — для хранилищ,
+// где требуется история изменений объектов (compliance).
+//
+// Использование:
+//
+//	inner, err := minio.NewDefault(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	sink := audit.NewPostgresSink(db, "storage_audit") // таблица из sink.Schema()
+//	s := audit.New(inner, &audit.Options{
+//	    Sink: audit.MultiSink(sink, audit.PublisherSink(publisher, "storage.audit")),
+//	})
+//
+//	ctx = audit.WithActor(ctx, userID)
+//	err = s.Put(ctx, "docs", "contracts/42.pdf", r, nil)
+//
+// Sink'и:
+//   - [LogSink] — запись в slog сообщением "storage audit" (по умолчанию)
+//   - [PostgresSink] — таблица PostgreSQL; в транзакции из контекста (sqlx.WithTx) запись
+//     фиксируется вместе с ней
+//   - [PublisherSink] — топик брокера сообщений ([queue.Publisher])
+//   - [MultiSink], [SinkFunc] — комбинация и собственные реализации
+//
+// Особенности:
+//   - Инициатор берётся из [WithActor], иначе из Subject токена ([token.ClaimsFromContext]);
+//     Options.Actor задаёт собственное извлечение
+//   - Неудавшиеся операции записываются с Error; SkipFailed отключает их запись
+//   - Запись выполняется после операции; ошибка Sink логируется и не возвращается,
+//     т.к. операция уже выполнена
+//   - ETag известен только для CompleteMultipartUpload: Put его не возвращает
+package audit
@@ -0,0 +1,166 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/db/pg"
+	"github.com/pure-golang/adapters/queue"
+)
+
+// DefaultTable — таблица журнала аудита по умолчанию
+const DefaultTable = "storage_audit"
+
+// ActionHeader — заголовок сообщения PublisherSink с типом операции
+const ActionHeader = "audit-action"
+
+// Sink принимает записи аудита
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// SinkFunc — функция, реализующая Sink
+type SinkFunc func(ctx context.Context, record Record) error
+
+// Write вызывает f
+func (f SinkFunc) Write(ctx context.Context, record Record) error {
+	return f(ctx, record)
+}
+
+// MultiSink передаёт запись во все sinks; ошибки объединяются
+func MultiSink(sinks ...Sink) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) error {
+		var errs []string
+		for _, sink := range sinks {
+			if err := sink.Write(ctx, record); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			return errors.Errorf("failed to write audit record: %s", strings.Join(errs, "; "))
+		}
+		return nil
+	})
+}
+
+// LogSink пишет записи в логгер на уровне Info сообщением "storage audit"
+func LogSink(logger *slog.Logger) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) error {
+		attrs := []slog.Attr{
+			slog.Time("time", record.Time),
+			slog.String("action", string(record.Action)),
+			slog.String("bucket", record.Bucket),
+			slog.String("key", record.Key),
+			slog.String("actor", record.Actor),
+			slog.Int64("size", record.Size),
+		}
+		if record.ETag != "" {
+			attrs = append(attrs, slog.String("etag", record.ETag))
+		}
+		if record.ContentType != "" {
+			attrs = append(attrs, slog.String("content_type", record.ContentType))
+		}
+		if record.UploadID != "" {
+			attrs = append(attrs, slog.String("upload_id", record.UploadID))
+		}
+		if record.Error != "" {
+			attrs = append(attrs, slog.String("error", record.Error))
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "storage audit", attrs...)
+		return nil
+	})
+}
+
+// PublisherSink публикует записи в топик брокера сообщений; тело сообщения — Record,
+// кодируется энкодером Publisher (обычно JSON)
+func PublisherSink(publisher queue.Publisher, topic string) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) error {
+		msg := queue.Message{
+			Topic:   topic,
+			Headers: map[string]string{ActionHeader: string(record.Action)},
+			Body:    record,
+		}
+		if err := publisher.Publish(ctx, msg); err != nil {
+			return errors.Wrap(err, "failed to publish audit record")
+		}
+		return nil
+	})
+}
+
+// Execer выполняет запрос; реализуется *sqlx.Connection.
+// Если Exec выполняет запрос в транзакции из контекста (sqlx.WithTx), запись аудита
+// фиксируется вместе с ней.
+type Execer interface {
+	Exec(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// PostgresSink записывает журнал аудита в таблицу PostgreSQL
+type PostgresSink struct {
+	db     Execer
+	table  string
+	quoted string
+	query  string
+	// err — ошибка проверки имени таблицы, возвращаемая Schema и Write
+	err error
+}
+
+// NewPostgresSink создаёт Sink, записывающий в table (по умолчанию DefaultTable).
+// Таблица создаётся миграцией из Schema. Имя проверяется pg.ParseTableName;
+// ошибка проверки возвращается Schema и Write.
+func NewPostgresSink(db Execer, table string) *PostgresSink {
+	if table == "" {
+		table = DefaultTable
+	}
+	s := &PostgresSink{db: db, table: table}
+	name, err := pg.ParseTableName(table)
+	if err != nil {
+		s.err = errors.Wrap(err, "invalid audit table")
+		return s
+	}
+	s.quoted = name.String()
+	s.query = fmt.Sprintf(`INSERT INTO %s (time, action, bucket, key, actor, size, etag, content_type, upload_id, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, s.quoted)
+	return s
+}
+
+// Schema возвращает DDL таблицы журнала аудита для миграций
+func (s *PostgresSink) Schema() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	index := pg.QuoteIdentifier(strings.ReplaceAll(s.table, ".", "_") + "_object_idx")
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id           bigserial PRIMARY KEY,
+	time         timestamptz NOT NULL,
+	action       text NOT NULL,
+	bucket       text NOT NULL,
+	key          text NOT NULL,
+	actor        text NOT NULL DEFAULT '',
+	size         bigint NOT NULL DEFAULT 0,
+	etag         text NOT NULL DEFAULT '',
+	content_type text NOT NULL DEFAULT '',
+	upload_id    text NOT NULL DEFAULT '',
+	error        text NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (bucket, key, time)`, s.quoted, index), nil
+}
+
+// Write добавляет запись в таблицу
+func (s *PostgresSink) Write(ctx context.Context, record Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	_, err := s.db.Exec(ctx, s.query,
+		record.Time, string(record.Action), record.Bucket, record.Key, record.Actor,
+		record.Size, record.ETag, record.ContentType, record.UploadID, record.Error,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert audit record")
+	}
+	return nil
+}
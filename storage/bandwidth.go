@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// BandwidthLimiter limits the throughput of readers to a number of bytes per second with a token bucket.
+// A single limiter may wrap many readers at once, e.g. to cap the total upload bandwidth of a process;
+// the readers then share its rate. A nil limiter does not limit.
+type BandwidthLimiter struct {
+	limiter *rate.Limiter
+	burst   int
+}
+
+// NewBandwidthLimiter returns a limiter allowing bytesPerSec bytes per second on average
+// and bursts of up to one second of traffic, but no more than 1 MiB. It returns nil, an unlimited limiter, if bytesPerSec is not positive.
+func NewBandwidthLimiter(bytesPerSec int64) *BandwidthLimiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	burst := int(min(bytesPerSec, int64(maxBandwidthBurst)))
+	return &BandwidthLimiter{
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSec), burst),
+		burst:   burst,
+	}
+}
+
+// maxBandwidthBurst bounds a single read, so that readers sharing a fast limiter interleave.
+const maxBandwidthBurst = 1 << 20
+
+// Reader returns r reading no faster than the limiter allows.
+// Reads wait for tokens and fail once ctx is done or its deadline is too close to wait for them.
+func (l *BandwidthLimiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, limiter: l}
+}
+
+// limitedReader is an io.Reader throttled by a BandwidthLimiter.
+type limitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *BandwidthLimiter
+}
+
+// Read implements io.Reader. A single read is capped at the bucket size, so it never waits
+// for more tokens than the bucket can hold.
+func (r *limitedReader) Read(b []byte) (int, error) {
+	if len(b) > r.limiter.burst {
+		b = b[:r.limiter.burst]
+	}
+	n, err := r.r.Read(b)
+	if n > 0 {
+		if waitErr := r.limiter.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewBandwidthLimiter tests that a non-positive rate disables limiting
+func TestNewBandwidthLimiter(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, NewBandwidthLimiter(0))
+	assert.Nil(t, NewBandwidthLimiter(-1))
+
+	var l *BandwidthLimiter
+	r := strings.NewReader("data")
+	assert.Same(t, r, l.Reader(context.Background(), r))
+}
+
+// TestBandwidthLimiter_Reader tests that reads are throttled to the limiter rate
+func TestBandwidthLimiter_Reader(t *testing.T) {
+	t.Parallel()
+	const rate = 256 << 10
+	l := NewBandwidthLimiter(rate)
+	data := bytes.Repeat([]byte("x"), rate*3/2)
+
+	start := time.Now()
+	got, err := io.ReadAll(l.Reader(context.Background(), bytes.NewReader(data)))
+	require.NoError(t, err)
+	elapsed := time.Since(start)
+
+	assert.Equal(t, data, got)
+	// The first second of traffic is a burst, the remaining half takes about 500ms
+	assert.GreaterOrEqual(t, elapsed, 400*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+// TestBandwidthLimiter_Shared tests that readers sharing a limiter share its rate
+func TestBandwidthLimiter_Shared(t *testing.T) {
+	t.Parallel()
+	const rate = 256 << 10
+	l := NewBandwidthLimiter(rate)
+	ctx := context.Background()
+
+	start := time.Now()
+	_, err := io.ReadAll(l.Reader(ctx, bytes.NewReader(make([]byte, rate))))
+	require.NoError(t, err)
+	_, err = io.ReadAll(l.Reader(ctx, bytes.NewReader(make([]byte, rate/2))))
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+// TestBandwidthLimiter_Canceled tests that a waiting read fails with a canceled context
+func TestBandwidthLimiter_Canceled(t *testing.T) {
+	t.Parallel()
+	l := NewBandwidthLimiter(1 << 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	r := l.Reader(ctx, bytes.NewReader(make([]byte, 4<<10)))
+
+	buf := make([]byte, 4<<10)
+	n, err := r.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 1<<10, n, "a read is capped at the bucket size")
+
+	cancel()
+	_, err = r.Read(buf)
+	assert.ErrorIs(t, err, context.Canceled)
+}
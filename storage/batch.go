@@ -0,0 +1,309 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Batch defaults.
+const (
+	DefaultBatchConcurrency  = 4
+	DefaultBatchRetries      = 2
+	DefaultBatchManifestName = "batch.json"
+)
+
+// ErrBatchNotCommitted is returned by ReadBatch when the batch has no committed manifest.
+var ErrBatchNotCommitted = errors.New("batch is not committed")
+
+// BatchItem is an object uploaded by PutBatch.
+type BatchItem struct {
+	Name    string      // Name within the batch, e.g. "index.html" or "assets/app.js"
+	Reader  io.Reader   // Content; a failed Put is retried only if Reader implements io.Seeker
+	Options *PutOptions // Optional put options
+}
+
+// BatchOptions contains optional parameters for PutBatch.
+type BatchOptions struct {
+	Concurrency  int               // Max concurrent PUTs (default 4)
+	Retries      int               // Retries of a failed PUT (default 2), negative disables retries
+	Metadata     map[string]string // Stored in the manifest, e.g. the artifact version
+	ManifestName string            // Manifest object name under the prefix (default "batch.json")
+	// KeepPrevious keeps the objects of the previously committed batch under the prefix.
+	// By default they are deleted after the commit, so readers holding the old manifest
+	// may fail to open its objects.
+	KeepPrevious bool
+}
+
+// BatchManifest describes a committed batch.
+type BatchManifest struct {
+	ID          string            `json:"id"`
+	Bucket      string            `json:"bucket"`
+	Prefix      string            `json:"prefix"`
+	CommittedAt time.Time         `json:"committed_at"`
+	Objects     []BatchObject     `json:"objects"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// BatchObject describes an object of a committed batch.
+type BatchObject struct {
+	Name        string `json:"name"`
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	ETag        string `json:"etag,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// Object returns the batch object with the given name.
+func (m *BatchManifest) Object(name string) (BatchObject, bool) {
+	for _, obj := range m.Objects {
+		if obj.Name == name {
+			return obj, true
+		}
+	}
+	return BatchObject{}, false
+}
+
+// Open gets the batch object with the given name, or returns an error with CodeNotFound
+// if the batch has no such object.
+func (m *BatchManifest) Open(ctx context.Context, s Storage, name string) (io.ReadCloser, *ObjectInfo, error) {
+	obj, ok := m.Object(name)
+	if !ok {
+		return nil, nil, &StorageError{Code: CodeNotFound, Message: "object is not part of the batch", Bucket: m.Bucket, Key: name}
+	}
+	return s.Get(ctx, m.Bucket, obj.Key)
+}
+
+// PutBatch uploads a set of related objects and publishes them together by writing
+// a manifest under prefix last, as the commit marker.
+//
+// Objects are uploaded under "<prefix><batch id>/<name>", so a new batch never overwrites
+// the objects of the committed one. Readers find objects through the manifest only (ReadBatch),
+// so until the manifest is written the new objects are not visible, and after it is written
+// all of them are. A single PUT of the manifest replaces the previous one atomically.
+//
+// If an upload fails after retries, or the manifest cannot be written, the objects uploaded
+// so far are deleted and the previous batch stays committed. Objects of a process that crashed
+// mid-upload are left unreferenced under their batch id.
+func PutBatch(ctx context.Context, s Storage, bucket, prefix string, items []BatchItem, opts *BatchOptions) (*BatchManifest, error) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	if err := validateBatchItems(items); err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	retries := opts.Retries
+	if retries == 0 {
+		retries = DefaultBatchRetries
+	}
+	manifestKey := prefix + batchManifestName(opts)
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	manifest := &BatchManifest{
+		ID:       now.Format("20060102T150405Z") + "-" + suffix,
+		Bucket:   bucket,
+		Prefix:   prefix,
+		Objects:  make([]BatchObject, len(items)),
+		Metadata: opts.Metadata,
+	}
+
+	previous, err := readBatchManifest(ctx, s, bucket, manifestKey)
+	if err != nil && !errors.Is(err, ErrBatchNotCommitted) {
+		return nil, err
+	}
+
+	uploaded, err := putBatchObjects(ctx, s, manifest, items, concurrency, retries)
+	if err != nil {
+		deleteBatchKeys(ctx, s, bucket, uploaded)
+		return nil, err
+	}
+
+	manifest.CommittedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		deleteBatchKeys(ctx, s, bucket, uploaded)
+		return nil, fmt.Errorf("failed to marshal batch manifest: %w", err)
+	}
+	err = s.Put(ctx, bucket, manifestKey, bytes.NewReader(data), &PutOptions{
+		ContentType:  "application/json",
+		CacheControl: "no-cache",
+	})
+	if err != nil {
+		deleteBatchKeys(ctx, s, bucket, uploaded)
+		return nil, fmt.Errorf("failed to commit batch manifest: %w", err)
+	}
+
+	if previous != nil && !opts.KeepPrevious {
+		keys := make([]string, 0, len(previous.Objects))
+		for _, obj := range previous.Objects {
+			keys = append(keys, obj.Key)
+		}
+		deleteBatchKeys(ctx, s, bucket, keys)
+	}
+	return manifest, nil
+}
+
+// ReadBatch returns the manifest of the batch committed under prefix,
+// or ErrBatchNotCommitted if PutBatch has not committed one.
+func ReadBatch(ctx context.Context, s Storage, bucket, prefix string, opts *BatchOptions) (*BatchManifest, error) {
+	return readBatchManifest(ctx, s, bucket, prefix+batchManifestName(opts))
+}
+
+// batchManifestName returns the manifest object name.
+func batchManifestName(opts *BatchOptions) string {
+	if opts == nil || opts.ManifestName == "" {
+		return DefaultBatchManifestName
+	}
+	return opts.ManifestName
+}
+
+// readBatchManifest gets and decodes the manifest.
+func readBatchManifest(ctx context.Context, s Storage, bucket, key string) (*BatchManifest, error) {
+	body, _, err := s.Get(ctx, bucket, key)
+	if IsNotFound(err) {
+		return nil, ErrBatchNotCommitted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch manifest: %w", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	var manifest BatchManifest
+	if err := json.NewDecoder(body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode batch manifest %s: %w", key, err)
+	}
+	return &manifest, nil
+}
+
+// validateBatchItems rejects empty batches, missing readers and unsafe or duplicate names.
+func validateBatchItems(items []BatchItem) error {
+	if len(items) == 0 {
+		return errors.New("batch has no items")
+	}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Name == "" || strings.HasPrefix(item.Name, "/") || path.Clean(item.Name) != item.Name ||
+			item.Name == ".." || strings.HasPrefix(item.Name, "../") {
+			return fmt.Errorf("invalid batch item name %q", item.Name)
+		}
+		if item.Reader == nil {
+			return fmt.Errorf("batch item %q has no reader", item.Name)
+		}
+		if seen[item.Name] {
+			return fmt.Errorf("duplicate batch item name %q", item.Name)
+		}
+		seen[item.Name] = true
+	}
+	return nil
+}
+
+// putBatchObjects uploads the items concurrently and fills manifest.Objects.
+// It returns the keys of uploaded objects, including on error, so they can be deleted.
+func putBatchObjects(ctx context.Context, s Storage, manifest *BatchManifest, items []BatchItem, concurrency, retries int) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		uploaded []string
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	slots := make(chan struct{}, concurrency)
+
+	for i, item := range items {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			key := manifest.Prefix + manifest.ID + "/" + item.Name
+			err := putBatchObject(ctx, s, manifest.Bucket, key, item, retries)
+			var info *ObjectInfo
+			if err == nil {
+				// the object is written: delete it on abort even if Stat fails
+				mu.Lock()
+				uploaded = append(uploaded, key)
+				mu.Unlock()
+				info, err = s.Stat(ctx, manifest.Bucket, key)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to put batch item %s: %w", item.Name, err)
+					cancel()
+				}
+				return
+			}
+			manifest.Objects[i] = BatchObject{
+				Name:        item.Name,
+				Key:         key,
+				Size:        info.Size,
+				ETag:        info.ETag,
+				ContentType: info.ContentType,
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	return uploaded, firstErr
+}
+
+// putBatchObject puts an item, retrying failures of seekable readers.
+func putBatchObject(ctx context.Context, s Storage, bucket, key string, item BatchItem, retries int) error {
+	seeker, seekable := item.Reader.(io.Seeker)
+	var start int64
+	if seekable {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seekable = false
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		err := s.Put(ctx, bucket, key, item.Reader, item.Options)
+		if err == nil || !seekable || attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+		if _, seekErr := seeker.Seek(start, io.SeekStart); seekErr != nil {
+			return err
+		}
+	}
+}
+
+// deleteBatchKeys deletes objects best-effort, also after ctx is cancelled.
+func deleteBatchKeys(ctx context.Context, s Storage, bucket string, keys []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		_ = s.Delete(ctx, bucket, key)
+	}
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchStorage is an in-memory Storage failing Puts of keys with a given suffix.
+type batchStorage struct {
+	*presignedStorage
+
+	mu       sync.Mutex
+	failKey  string // suffix of keys whose Put fails
+	failures int    // number of failures left, negative fails forever
+	puts     map[string]int
+}
+
+func newBatchStorage() *batchStorage {
+	return &batchStorage{presignedStorage: newPresignedStorage(), puts: make(map[string]int)}
+}
+
+func (s *batchStorage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *PutOptions) error {
+	s.mu.Lock()
+	s.puts[key]++
+	fail := s.failKey != "" && strings.HasSuffix(key, s.failKey) && s.failures != 0
+	if fail && s.failures > 0 {
+		s.failures--
+	}
+	s.mu.Unlock()
+	if fail {
+		_, _ = io.Copy(io.Discard, reader)
+		return errors.New("connection reset")
+	}
+	if opts == nil {
+		opts = &PutOptions{}
+	}
+	return s.presignedStorage.Put(ctx, bucket, key, reader, opts)
+}
+
+func (s *batchStorage) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.presignedStorage.mu.Lock()
+	defer s.presignedStorage.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// putsOf returns the number of Put calls for keys with the suffix.
+func (s *batchStorage) putsOf(suffix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for k, puts := range s.puts {
+		if strings.HasSuffix(k, suffix) {
+			n += puts
+		}
+	}
+	return n
+}
+
+func batchItems(contents ...string) []BatchItem {
+	items := make([]BatchItem, 0, len(contents))
+	for _, c := range contents {
+		name, body, _ := strings.Cut(c, "=")
+		items = append(items, BatchItem{
+			Name:    name,
+			Reader:  strings.NewReader(body),
+			Options: &PutOptions{ContentType: "text/plain"},
+		})
+	}
+	return items
+}
+
+func readBatchObject(t *testing.T, s Storage, m *BatchManifest, name string) string {
+	t.Helper()
+	body, _, err := m.Open(context.Background(), s, name)
+	require.NoError(t, err)
+	defer func() { _ = body.Close() }()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestPutBatch tests that a batch is readable only after commit and replaces the previous batch.
+func TestPutBatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := newBatchStorage()
+
+	_, err := ReadBatch(ctx, s, "artifacts", "site/", nil)
+	require.ErrorIs(t, err, ErrBatchNotCommitted)
+
+	first, err := PutBatch(ctx, s, "artifacts", "site/", batchItems("index.html=v1", "assets/app.js=js1"),
+		&BatchOptions{Metadata: map[string]string{"version": "1"}})
+	require.NoError(t, err)
+	assert.Len(t, first.Objects, 2)
+	assert.Equal(t, "site/"+first.ID+"/assets/app.js", first.Objects[1].Key)
+	assert.Equal(t, int64(3), first.Objects[1].Size)
+	assert.Equal(t, "text/plain", first.Objects[1].ContentType)
+
+	read, err := ReadBatch(ctx, s, "artifacts", "site/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, read.ID)
+	assert.Equal(t, "1", read.Metadata["version"])
+	assert.Equal(t, "v1", readBatchObject(t, s, read, "index.html"))
+	_, _, err = read.Open(ctx, s, "missing.html")
+	assert.True(t, IsNotFound(err))
+
+	second, err := PutBatch(ctx, s, "artifacts", "site/", batchItems("index.html=v2"), nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+	read, err = ReadBatch(ctx, s, "artifacts", "site/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", readBatchObject(t, s, read, "index.html"))
+
+	// objects of the first batch are deleted after the second commit
+	assert.ElementsMatch(t, []string{"site/batch.json", "site/" + second.ID + "/index.html"}, s.keys())
+}
+
+// TestPutBatch_Abort tests that a failed upload deletes uploaded objects and keeps the committed batch.
+func TestPutBatch_Abort(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := newBatchStorage()
+	committed, err := PutBatch(ctx, s, "artifacts", "site/", batchItems("index.html=v1"), nil)
+	require.NoError(t, err)
+
+	s.failKey, s.failures = "/broken.js", -1
+	_, err = PutBatch(ctx, s, "artifacts", "site/", batchItems("index.html=v2", "a.js=a", "broken.js=b"), &BatchOptions{Concurrency: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to put batch item broken.js")
+
+	read, err := ReadBatch(ctx, s, "artifacts", "site/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, committed.ID, read.ID)
+	assert.ElementsMatch(t, []string{"site/batch.json", committed.Objects[0].Key}, s.keys())
+	assert.Equal(t, 1+DefaultBatchRetries, s.putsOf("/broken.js"), "failed PUTs are retried")
+}
+
+// TestPutBatch_Retry tests that failed PUTs of seekable readers are retried from the start.
+func TestPutBatch_Retry(t *testing.T) {
+	t.Parallel()
+	s := newBatchStorage()
+	s.failKey, s.failures = "/index.html", 2
+
+	m, err := PutBatch(context.Background(), s, "artifacts", "site/", batchItems("index.html=hello"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", readBatchObject(t, s, m, "index.html"))
+
+	// a non-seekable reader is not retried
+	s.failKey, s.failures = "/stream.bin", 1
+	_, err = PutBatch(context.Background(), s, "artifacts", "site/",
+		[]BatchItem{{Name: "stream.bin", Reader: io.MultiReader(bytes.NewReader([]byte("data")))}}, nil)
+	require.Error(t, err)
+}
+
+// TestPutBatch_InvalidItems tests validation of batch items.
+func TestPutBatch_InvalidItems(t *testing.T) {
+	t.Parallel()
+	s := newBatchStorage()
+	for _, items := range [][]BatchItem{
+		nil,
+		batchItems("../escape=x"),
+		batchItems("/abs=x"),
+		batchItems("a//b=x"),
+		batchItems("a=x", "a=y"),
+		{{Name: "nil-reader"}},
+	} {
+		_, err := PutBatch(context.Background(), s, "artifacts", "site/", items, nil)
+		assert.Error(t, err)
+	}
+	assert.Empty(t, s.keys())
+}
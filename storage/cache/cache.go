@@ -0,0 +1,233 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/pure-golang/adapters/kv"
+	"github.com/pure-golang/adapters/storage"
+)
+
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+)
+
+const (
+	// DefaultMaxObjectSize — максимальный размер кэшируемого объекта по умолчанию
+	DefaultMaxObjectSize = 1 << 20
+	// DefaultTTL — время жизни записи кэша по умолчанию
+	DefaultTTL = 5 * time.Minute
+	// DefaultKeyPrefix — префикс ключей кэша по умолчанию
+	DefaultKeyPrefix = "storage:cache:"
+)
+
+// meterName — имя Meter для метрик кэша
+const meterName = "github.com/pure-golang/adapters/storage/cache"
+
+// Options содержит настройки Storage
+type Options struct {
+	// MaxObjectSize — объекты больше этого размера в байтах не кэшируются; по умолчанию DefaultMaxObjectSize
+	MaxObjectSize int64
+	// TTL — время жизни записи кэша; по умолчанию DefaultTTL
+	TTL time.Duration
+	// KeyPrefix — префикс ключей в kv.Store; по умолчанию DefaultKeyPrefix
+	KeyPrefix string
+	// MeterProvider для метрик попаданий и промахов; по умолчанию otel.GetMeterProvider()
+	MeterProvider metric.MeterProvider
+	// Logger для ошибок кэша; по умолчанию slog.Default()
+	Logger *slog.Logger
+}
+
+// Storage — декоратор storage.Storage, кэширующий небольшие объекты в kv.Store.
+//
+// Get запрашивает метаданные объекта (Stat) и ищет в кэше запись по bucket, ключу и ETag:
+// изменённый объект получает новый ETag, поэтому устаревшая запись не возвращается
+// и удаляется по TTL. Ошибки кэша логируются, объект читается из исходного хранилища.
+//
+// Метрики storage.cache.hits_total и storage.cache.misses_total (атрибут bucket).
+type Storage struct {
+	storage.Storage
+
+	store         kv.Store
+	maxObjectSize int64
+	ttl           time.Duration
+	keyPrefix     string
+	hits          metric.Int64Counter
+	misses        metric.Int64Counter
+	logger        *slog.Logger
+}
+
+// New создаёт Storage поверх inner с кэшем в store (например, kv/redis).
+// Ошибка создания метрик передаётся в otel.Handle, кэш работает без них.
+func New(inner storage.Storage, store kv.Store, opts *Options) *Storage {
+	if opts == nil {
+		opts = &Options{}
+	}
+	maxObjectSize := opts.MaxObjectSize
+	if maxObjectSize <= 0 {
+		maxObjectSize = DefaultMaxObjectSize
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	keyPrefix := opts.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+	provider := opts.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	s := &Storage{
+		Storage:       inner,
+		store:         store,
+		maxObjectSize: maxObjectSize,
+		ttl:           ttl,
+		keyPrefix:     keyPrefix,
+		logger:        log.WithGroup("storage").With("decorator", "cache"),
+	}
+
+	m := provider.Meter(meterName)
+	hits, err := m.Int64Counter(
+		"storage.cache.hits_total",
+		metric.WithDescription("Total number of objects served from the cache"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create cache hits counter"))
+	} else {
+		s.hits = hits
+	}
+	misses, err := m.Int64Counter(
+		"storage.cache.misses_total",
+		metric.WithDescription("Total number of objects read from the underlying storage"),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create cache misses counter"))
+	} else {
+		s.misses = misses
+	}
+	return s
+}
+
+// Get возвращает объект из кэша, если ETag записи совпадает с текущим ETag объекта,
+// иначе читает его из исходного хранилища и кэширует, если размер не больше MaxObjectSize
+func (s *Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	current, err := s.Stat(ctx, bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if current.ETag != "" && current.Size <= s.maxObjectSize {
+		if data, info, ok := s.lookup(ctx, s.cacheKey(bucket, key, current.ETag)); ok {
+			s.count(ctx, s.hits, bucket)
+			return io.NopCloser(bytes.NewReader(data)), info, nil
+		}
+	}
+	s.count(ctx, s.misses, bucket)
+
+	rc, info, err := s.Storage.Get(ctx, bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info == nil || info.ETag == "" || info.Size > s.maxObjectSize {
+		return rc, info, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(rc, s.maxObjectSize+1))
+	if err != nil {
+		if closeErr := rc.Close(); closeErr != nil {
+			s.logger.With("error", closeErr).Error("failed to close object after read error")
+		}
+		return nil, nil, errors.Wrap(err, "failed to read object")
+	}
+	if int64(len(data)) > s.maxObjectSize {
+		// Размер в метаданных меньше фактического: объект отдаётся без кэширования
+		return &prefixedReadCloser{Reader: io.MultiReader(bytes.NewReader(data), rc), Closer: rc}, info, nil
+	}
+	if err := rc.Close(); err != nil {
+		s.logger.With("error", err).Error("failed to close object")
+	}
+
+	s.save(ctx, s.cacheKey(bucket, key, info.ETag), data, info)
+	return io.NopCloser(bytes.NewReader(data)), info, nil
+}
+
+// GetWithOptions передаёт запрос исходному хранилищу без кэширования:
+// заголовки запроса могут изменить ответ
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
+	getter, ok := s.Storage.(storage.OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	return getter.GetWithOptions(ctx, bucket, key, opts)
+}
+
+// entry — запись кэша
+type entry struct {
+	Info storage.ObjectInfo `json:"info"`
+	Data []byte             `json:"data"`
+}
+
+// lookup возвращает содержимое и метаданные объекта из кэша
+func (s *Storage) lookup(ctx context.Context, cacheKey string) ([]byte, *storage.ObjectInfo, bool) {
+	value, err := s.store.Get(ctx, cacheKey)
+	if errors.Is(err, kv.ErrKeyNotFound) || (err == nil && value == "") {
+		return nil, nil, false
+	}
+	if err != nil {
+		s.logger.With("error", err).Warn("failed to get cached object", "cache_key", cacheKey)
+		return nil, nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal([]byte(value), &e); err != nil {
+		s.logger.With("error", err).Warn("failed to decode cached object", "cache_key", cacheKey)
+		return nil, nil, false
+	}
+	return e.Data, &e.Info, true
+}
+
+// save сохраняет объект в кэш; ошибка логируется, т.к. объект уже прочитан
+func (s *Storage) save(ctx context.Context, cacheKey string, data []byte, info *storage.ObjectInfo) {
+	value, err := json.Marshal(entry{Info: *info, Data: data})
+	if err != nil {
+		s.logger.With("error", err).Warn("failed to encode object for cache", "cache_key", cacheKey)
+		return
+	}
+	if err := s.store.Set(ctx, cacheKey, string(value), s.ttl); err != nil {
+		s.logger.With("error", err).Warn("failed to cache object", "cache_key", cacheKey)
+	}
+}
+
+func (s *Storage) cacheKey(bucket, key, etag string) string {
+	return s.keyPrefix + bucket + "/" + key + "#" + etag
+}
+
+func (s *Storage) count(ctx context.Context, counter metric.Int64Counter, bucket string) {
+	if counter == nil {
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("bucket", bucket)))
+}
+
+// prefixedReadCloser дочитывает объект после уже прочитанного начала
+type prefixedReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/pure-golang/adapters/kv"
+	kvnoop "github.com/pure-golang/adapters/kv/noop"
+	"github.com/pure-golang/adapters/storage"
+	"github.com/pure-golang/adapters/storage/storagetest"
+)
+
+// put stores a text object in inner and returns its ETag
+func put(t *testing.T, inner *storagetest.MemoryStorage, key, data string) string {
+	t.Helper()
+	require.NoError(t, inner.Put(context.Background(), "bucket", key, strings.NewReader(data), &storage.PutOptions{ContentType: "text/plain"}))
+	_, info, ok := inner.Object("bucket", key)
+	require.True(t, ok)
+	return info.ETag
+}
+
+// memoryStore is a kv.Store keeping strings in memory
+type memoryStore struct {
+	kvnoop.Store
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memoryStore) Get(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return "", kv.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (m *memoryStore) Set(_ context.Context, key string, value any, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value.(string)
+	m.ttls[key] = expiration
+	return nil
+}
+
+// failingStore fails every operation
+type failingStore struct {
+	kvnoop.Store
+}
+
+func (*failingStore) Get(context.Context, string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func (*failingStore) Set(context.Context, string, any, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func read(t *testing.T, s *Storage, key string) (string, *storage.ObjectInfo) {
+	t.Helper()
+	rc, info, err := s.Get(context.Background(), "bucket", key)
+	require.NoError(t, err)
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(data), info
+}
+
+// counters returns the cache counters by metric name.
+func counters(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				result[m.Name] += point.Value
+			}
+		}
+	}
+	return result
+}
+
+// TestStorage_Get tests that repeated reads are served from the cache with hit and miss metrics.
+func TestStorage_Get(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	etag := put(t, inner, "tpl.html", "<h1>hi</h1>")
+	store := newMemoryStore()
+	reader := sdkmetric.NewManualReader()
+	s := New(inner, store, &Options{
+		TTL:           time.Minute,
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+
+	for range 3 {
+		data, info := read(t, s, "tpl.html")
+		assert.Equal(t, "<h1>hi</h1>", data)
+		assert.Equal(t, etag, info.ETag)
+		assert.Equal(t, "text/plain", info.ContentType)
+	}
+
+	assert.Equal(t, 1, inner.Calls("Get"))
+	assert.Equal(t, 3, inner.Calls("Stat"))
+	assert.Equal(t, time.Minute, store.ttls[DefaultKeyPrefix+"bucket/tpl.html#"+etag])
+	assert.Equal(t, map[string]int64{"storage.cache.hits_total": 2, "storage.cache.misses_total": 1}, counters(t, reader))
+}
+
+// TestStorage_Get_ETagChanged tests that a changed object is read again.
+func TestStorage_Get_ETagChanged(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	put(t, inner, "config.json", "v1")
+	s := New(inner, newMemoryStore(), nil)
+
+	data, _ := read(t, s, "config.json")
+	assert.Equal(t, "v1", data)
+
+	etag := put(t, inner, "config.json", "v2")
+	data, info := read(t, s, "config.json")
+	assert.Equal(t, "v2", data)
+	assert.Equal(t, etag, info.ETag)
+	assert.Equal(t, 2, inner.Calls("Get"))
+}
+
+// TestStorage_Get_LargeObject tests that objects over MaxObjectSize are not cached.
+func TestStorage_Get_LargeObject(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	put(t, inner, "big.bin", strings.Repeat("x", 100))
+	store := newMemoryStore()
+	s := New(inner, store, &Options{MaxObjectSize: 10})
+
+	for range 2 {
+		data, _ := read(t, s, "big.bin")
+		assert.Len(t, data, 100)
+	}
+	assert.Equal(t, 2, inner.Calls("Get"))
+	assert.Empty(t, store.values)
+}
+
+// TestStorage_Get_NotFound tests that a missing object is reported by Stat without reading it.
+func TestStorage_Get_NotFound(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, newMemoryStore(), nil)
+
+	_, _, err := s.Get(context.Background(), "bucket", "missing.txt")
+	require.Error(t, err)
+	assert.True(t, storage.IsNotFound(err))
+	assert.Equal(t, 1, inner.Calls("Stat"))
+	assert.Zero(t, inner.Calls("Get"))
+}
+
+// TestStorage_Get_StoreErrors tests that cache failures fall back to the underlying storage.
+func TestStorage_Get_StoreErrors(t *testing.T) {
+	t.Parallel()
+	for name, store := range map[string]kv.Store{"failing": &failingStore{}, "noop": kvnoop.New()} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			inner := storagetest.NewMemoryStorage()
+			put(t, inner, "a.txt", "data")
+			s := New(inner, store, nil)
+
+			for range 2 {
+				data, _ := read(t, s, "a.txt")
+				assert.Equal(t, "data", data)
+			}
+			assert.Equal(t, 2, inner.Calls("Get"))
+		})
+	}
+}
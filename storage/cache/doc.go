@@ -0,0 +1,28 @@
+// Package cache реализует кэширующий декоратор [storage.Storage] для небольших объектов.
+//
+// Get небольших, часто читаемых объектов (шаблоны, конфигурации) отдаётся из [kv.Store]
+// (например, kv/redis) вместо повторного скачивания из объектного хранилища.
+//
+// Использование:
+//
+//	inner, err := minio.NewDefault(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	s := cache.New(inner, redisClient, &cache.Options{
+//	    MaxObjectSize: 256 << 10,
+//	    TTL:           10 * time.Minute,
+//	})
+//
+//	rc, info, err := s.Get(ctx, "templates", "email/welcome.html")
+//
+// Особенности:
+//   - Запись кэша хранит содержимое и [storage.ObjectInfo] под ключом bucket/key#ETag
+//   - Актуальность проверяется каждым Get через Stat исходного хранилища:
+//     изменённый объект получает новый ETag и читается заново, старая запись удаляется по TTL
+//   - Объекты больше MaxObjectSize и без ETag не кэшируются
+//   - GetWithOptions не кэшируется: заголовки запроса могут изменить ответ
+//   - Ошибки kv.Store логируются, объект читается из исходного хранилища
+//   - Метрики storage.cache.hits_total и storage.cache.misses_total с атрибутом bucket
+package cache
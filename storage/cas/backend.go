@@ -0,0 +1,58 @@
+package cas
+
+import (
+	"context"
+	"sync"
+)
+
+// Backend хранит счётчики ссылок на объекты.
+// Реализация должна атомарно применять Add, чтобы конкурентные ссылки не терялись.
+type Backend interface {
+	// Get возвращает число ссылок на объект; для неизвестного объекта — 0
+	Get(ctx context.Context, bucket, key string) (int64, error)
+	// Add прибавляет delta (может быть отрицательной) и возвращает новое число ссылок
+	Add(ctx context.Context, bucket, key string, delta int64) (int64, error)
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+// MemoryBackend хранит счётчики ссылок в памяти процесса.
+// Подходит для тестов и single-instance сервисов; данные теряются при перезапуске.
+type MemoryBackend struct {
+	mu   sync.Mutex
+	refs map[objectRef]int64
+}
+
+// objectRef — объект в bucket
+type objectRef struct {
+	bucket string
+	key    string
+}
+
+// NewMemoryBackend создаёт пустой MemoryBackend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		refs: make(map[objectRef]int64),
+	}
+}
+
+// Get возвращает число ссылок на объект
+func (b *MemoryBackend) Get(_ context.Context, bucket, key string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.refs[objectRef{bucket: bucket, key: key}], nil
+}
+
+// Add прибавляет delta к числу ссылок на объект; нулевые счётчики удаляются
+func (b *MemoryBackend) Add(_ context.Context, bucket, key string, delta int64) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ref := objectRef{bucket: bucket, key: key}
+	refs := b.refs[ref] + delta
+	if refs == 0 {
+		delete(b.refs, ref)
+	} else {
+		b.refs[ref] = refs
+	}
+	return refs, nil
+}
@@ -0,0 +1,304 @@
+package cas
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+)
+
+// DefaultPrefix — префикс ключей content-addressable объектов по умолчанию
+const DefaultPrefix = "sha256/"
+
+// Options содержит настройки Storage
+type Options struct {
+	// Backend хранит счётчики ссылок; по умолчанию NewMemoryBackend()
+	Backend Backend
+	// Prefix — префикс ключей объектов, ключ объекта — Prefix + hex(sha256); по умолчанию DefaultPrefix
+	Prefix string
+	// TempDir — каталог временных файлов, в которые буферизуется содержимое до вычисления хэша;
+	// по умолчанию os.TempDir()
+	TempDir string
+	// Logger для ошибок отката счётчиков; по умолчанию slog.Default()
+	Logger *slog.Logger
+}
+
+// Result — результат PutCAS
+type Result struct {
+	Key          string // Ключ объекта: Prefix + Checksum
+	Checksum     string // hex(sha256) содержимого
+	Size         int64  // Размер содержимого в байтах
+	Refs         int64  // Число ссылок после записи
+	Deduplicated bool   // Объект с таким содержимым уже существовал и не загружался повторно
+}
+
+// Storage — декоратор storage.Storage с content-addressable записью.
+//
+// PutCAS сохраняет содержимое под ключом, производным от его sha256, и не загружает
+// одинаковое содержимое повторно, увеличивая счётчик ссылок. Delete ключа под Prefix
+// уменьшает счётчик и удаляет объект, только когда ссылок не осталось.
+// Остальные ключи и операции передаются в исходное хранилище без изменений.
+type Storage struct {
+	storage.Storage
+
+	backend Backend
+	prefix  string
+	tempDir string
+	logger  *slog.Logger
+	locks   keyLocks
+}
+
+// New создаёт Storage поверх inner
+func New(inner storage.Storage, opts *Options) *Storage {
+	if opts == nil {
+		opts = &Options{}
+	}
+	backend := opts.Backend
+	if backend == nil {
+		backend = NewMemoryBackend()
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Storage{
+		Storage: inner,
+		backend: backend,
+		prefix:  prefix,
+		tempDir: opts.TempDir,
+		logger:  log.WithGroup("storage").With("decorator", "cas"),
+		locks:   keyLocks{locks: make(map[string]*keyLock)},
+	}
+}
+
+// Key возвращает ключ объекта с контрольной суммой checksum (hex sha256)
+func (s *Storage) Key(checksum string) string {
+	return s.prefix + strings.ToLower(checksum)
+}
+
+// PutCAS сохраняет содержимое reader под ключом Prefix + hex(sha256) и добавляет ссылку на него.
+// Если объект с таким содержимым уже есть, он не загружается повторно, а opts не применяются.
+// Содержимое буферизуется во временный файл, так как ключ известен только после чтения.
+func (s *Storage) PutCAS(ctx context.Context, bucket string, reader io.Reader, opts *storage.PutOptions) (*Result, error) {
+	tmp, err := os.CreateTemp(s.tempDir, "cas-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp file")
+	}
+	defer func() {
+		_ = tmp.Close()
+		if err := os.Remove(tmp.Name()); err != nil {
+			s.logger.With("error", err).Warn("failed to remove temp file", "path", tmp.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to buffer content")
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	key := s.Key(checksum)
+	result := &Result{Key: key, Checksum: checksum, Size: size}
+
+	unlock := s.locks.lock(bucket, key)
+	defer unlock()
+
+	refs, err := s.backend.Add(ctx, bucket, key, 1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add reference to %s", key)
+	}
+	result.Refs = refs
+	if refs > 1 {
+		result.Deduplicated = true
+		return result, nil
+	}
+
+	// Первая ссылка: объект мог остаться от записи без учёта ссылок
+	exists, err := s.Storage.Exists(ctx, bucket, key)
+	if err == nil && exists {
+		result.Deduplicated = true
+		return result, nil
+	}
+	if err == nil {
+		if _, err = tmp.Seek(0, io.SeekStart); err == nil {
+			err = s.Storage.Put(ctx, bucket, key, tmp, opts)
+		}
+	}
+	if err != nil {
+		s.release(ctx, bucket, key)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Find возвращает метаданные объекта с контрольной суммой checksum (hex sha256)
+// или ошибку с CodeNotFound, если такого содержимого нет
+func (s *Storage) Find(ctx context.Context, bucket, checksum string) (*storage.ObjectInfo, error) {
+	if err := checkChecksum(checksum); err != nil {
+		return nil, &storage.StorageError{
+			Code:    storage.CodeInvalidKey,
+			Message: err.Error(),
+			Err:     storage.ErrInvalidKey,
+			Bucket:  bucket,
+			Key:     checksum,
+		}
+	}
+	return s.Storage.Stat(ctx, bucket, s.Key(checksum))
+}
+
+// Refs возвращает число ссылок на объект с ключом key
+func (s *Storage) Refs(ctx context.Context, bucket, key string) (int64, error) {
+	refs, err := s.backend.Get(ctx, bucket, key)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get references to %s", key)
+	}
+	return refs, nil
+}
+
+// Put сохраняет объект; ключи под Prefix управляются PutCAS и отклоняются с CodeInvalidKey
+func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
+	if err := s.checkWritable(bucket, key); err != nil {
+		return err
+	}
+	return s.Storage.Put(ctx, bucket, key, reader, opts)
+}
+
+// CreateMultipartUpload начинает multipart загрузку; ключи под Prefix отклоняются с CodeInvalidKey
+func (s *Storage) CreateMultipartUpload(ctx context.Context, bucket, key string, opts *storage.PutOptions) (*storage.MultipartUpload, error) {
+	if err := s.checkWritable(bucket, key); err != nil {
+		return nil, err
+	}
+	return s.Storage.CreateMultipartUpload(ctx, bucket, key, opts)
+}
+
+// Delete удаляет ссылку на объект под Prefix; объект удаляется, когда ссылок не осталось.
+// Остальные объекты удаляются сразу.
+func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
+	if !strings.HasPrefix(key, s.prefix) {
+		return s.Storage.Delete(ctx, bucket, key)
+	}
+
+	unlock := s.locks.lock(bucket, key)
+	defer unlock()
+
+	refs, err := s.backend.Add(ctx, bucket, key, -1)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove reference to %s", key)
+	}
+	if refs > 0 {
+		return nil
+	}
+	if refs < 0 {
+		// Ссылки не учитывались (объект записан до подключения Backend): удаляется как обычный
+		if _, err := s.backend.Add(ctx, bucket, key, -refs); err != nil {
+			s.logger.With("error", err).Error("failed to reset reference count", "bucket", bucket, "key", key)
+		}
+	}
+
+	if err := s.Storage.Delete(ctx, bucket, key); err != nil {
+		if refs < 0 {
+			return err
+		}
+		// Ссылка возвращается, чтобы повторный Delete снова удалил объект
+		if _, addErr := s.backend.Add(ctx, bucket, key, 1); addErr != nil {
+			s.logger.With("error", addErr).Error("failed to restore reference", "bucket", bucket, "key", key)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetWithOptions передаёт запрос исходному хранилищу, если оно реализует storage.OptionsGetter
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
+	getter, ok := s.Storage.(storage.OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	return getter.GetWithOptions(ctx, bucket, key, opts)
+}
+
+// release откатывает ссылку, добавленную PutCAS
+func (s *Storage) release(ctx context.Context, bucket, key string) {
+	if _, err := s.backend.Add(ctx, bucket, key, -1); err != nil {
+		s.logger.With("error", err).Error("failed to roll back reference", "bucket", bucket, "key", key)
+	}
+}
+
+// checkWritable запрещает запись в обход PutCAS под Prefix
+func (s *Storage) checkWritable(bucket, key string) error {
+	if !strings.HasPrefix(key, s.prefix) {
+		return nil
+	}
+	return &storage.StorageError{
+		Code:    storage.CodeInvalidKey,
+		Message: "key is reserved for content-addressable objects, use PutCAS",
+		Err:     storage.ErrInvalidKey,
+		Bucket:  bucket,
+		Key:     key,
+	}
+}
+
+// checkChecksum проверяет, что checksum — hex sha256
+func checkChecksum(checksum string) error {
+	if len(checksum) != sha256.Size*2 {
+		return errors.Errorf("checksum must be %d hex characters", sha256.Size*2)
+	}
+	if _, err := hex.DecodeString(checksum); err != nil {
+		return errors.New("checksum is not hex encoded")
+	}
+	return nil
+}
+
+// keyLocks сериализует изменение счётчика и объекта одного ключа внутри процесса
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock — блокировка ключа и число её ожидающих
+type keyLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+// lock блокирует ключ и возвращает функцию разблокировки
+func (l *keyLocks) lock(bucket, key string) func() {
+	name := bucket + "/" + key
+	l.mu.Lock()
+	kl, ok := l.locks[name]
+	if !ok {
+		kl = &keyLock{}
+		l.locks[name] = kl
+	}
+	kl.waiters++
+	l.mu.Unlock()
+
+	kl.mu.Lock()
+	return func() {
+		kl.mu.Unlock()
+		l.mu.Lock()
+		kl.waiters--
+		if kl.waiters == 0 {
+			delete(l.locks, name)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,159 @@
+package cas
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+	"github.com/pure-golang/adapters/storage/storagetest"
+)
+
+func checksumOf(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestStorage_PutCAS tests that identical content is stored once and referenced twice.
+func TestStorage_PutCAS(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, &Options{TempDir: t.TempDir()})
+	ctx := context.Background()
+
+	first, err := s.PutCAS(ctx, "bucket", strings.NewReader("hello"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256/"+checksumOf("hello"), first.Key)
+	assert.Equal(t, checksumOf("hello"), first.Checksum)
+	assert.Equal(t, int64(5), first.Size)
+	assert.Equal(t, int64(1), first.Refs)
+	assert.False(t, first.Deduplicated)
+
+	second, err := s.PutCAS(ctx, "bucket", strings.NewReader("hello"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.Key, second.Key)
+	assert.Equal(t, int64(2), second.Refs)
+	assert.True(t, second.Deduplicated)
+
+	assert.Equal(t, 1, inner.Calls("Put"))
+	data, _, ok := inner.Object("bucket", first.Key)
+	require.True(t, ok)
+	assert.Equal(t, "hello", string(data))
+}
+
+// TestStorage_Delete tests that a shared object is removed only with its last reference.
+func TestStorage_Delete(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, &Options{TempDir: t.TempDir()})
+	ctx := context.Background()
+
+	res, err := s.PutCAS(ctx, "bucket", strings.NewReader("shared"), nil)
+	require.NoError(t, err)
+	_, err = s.PutCAS(ctx, "bucket", strings.NewReader("shared"), nil)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Delete(ctx, "bucket", res.Key))
+	assert.Contains(t, inner.Keys("bucket"), res.Key)
+	refs, err := s.Refs(ctx, "bucket", res.Key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), refs)
+
+	require.NoError(t, s.Delete(ctx, "bucket", res.Key))
+	assert.NotContains(t, inner.Keys("bucket"), res.Key)
+	refs, err = s.Refs(ctx, "bucket", res.Key)
+	require.NoError(t, err)
+	assert.Zero(t, refs)
+
+	// untracked object under the prefix and regular keys are deleted at once
+	require.NoError(t, inner.Put(ctx, "bucket", "sha256/untracked", strings.NewReader("x"), nil))
+	require.NoError(t, inner.Put(ctx, "bucket", "plain.txt", strings.NewReader("y"), nil))
+	require.NoError(t, s.Delete(ctx, "bucket", "sha256/untracked"))
+	require.NoError(t, s.Delete(ctx, "bucket", "plain.txt"))
+	assert.Empty(t, inner.Keys("bucket"))
+	refs, err = s.Refs(ctx, "bucket", "sha256/untracked")
+	require.NoError(t, err)
+	assert.Zero(t, refs)
+}
+
+// TestStorage_PutCAS_Error tests that a failed upload does not leave a reference behind.
+func TestStorage_PutCAS_Error(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	inner.SetError(errors.New("backend unavailable"), "Put")
+	s := New(inner, &Options{TempDir: t.TempDir()})
+	ctx := context.Background()
+
+	_, err := s.PutCAS(ctx, "bucket", strings.NewReader("data"), nil)
+	require.ErrorContains(t, err, "backend unavailable")
+
+	refs, err := s.Refs(ctx, "bucket", s.Key(checksumOf("data")))
+	require.NoError(t, err)
+	assert.Zero(t, refs)
+}
+
+// TestStorage_Find tests lookup by checksum.
+func TestStorage_Find(t *testing.T) {
+	t.Parallel()
+	s := New(storagetest.NewMemoryStorage(), &Options{TempDir: t.TempDir()})
+	ctx := context.Background()
+
+	_, err := s.PutCAS(ctx, "bucket", strings.NewReader("content"), nil)
+	require.NoError(t, err)
+
+	info, err := s.Find(ctx, "bucket", strings.ToUpper(checksumOf("content")))
+	require.NoError(t, err)
+	assert.Equal(t, s.Key(checksumOf("content")), info.Key)
+	assert.Equal(t, int64(7), info.Size)
+
+	_, err = s.Find(ctx, "bucket", checksumOf("missing"))
+	assert.True(t, storage.IsNotFound(err))
+
+	_, err = s.Find(ctx, "bucket", "not-a-checksum")
+	assert.True(t, storage.IsInvalidKey(err))
+}
+
+// TestStorage_ReservedPrefix tests that writes under the prefix must go through PutCAS.
+func TestStorage_ReservedPrefix(t *testing.T) {
+	t.Parallel()
+	s := New(storagetest.NewMemoryStorage(), nil)
+	ctx := context.Background()
+
+	err := s.Put(ctx, "bucket", "sha256/"+checksumOf("x"), strings.NewReader("y"), nil)
+	assert.True(t, storage.IsInvalidKey(err))
+	_, err = s.CreateMultipartUpload(ctx, "bucket", "sha256/abc", nil)
+	assert.True(t, storage.IsInvalidKey(err))
+
+	require.NoError(t, s.Put(ctx, "bucket", "files/a.txt", strings.NewReader("a"), nil))
+}
+
+// TestStorage_ConcurrentPutCAS tests that concurrent writes of the same content upload it once.
+func TestStorage_ConcurrentPutCAS(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, &Options{TempDir: t.TempDir()})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.PutCAS(ctx, "bucket", strings.NewReader("same"), nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, inner.Calls("Put"))
+	refs, err := s.Refs(ctx, "bucket", s.Key(checksumOf("same")))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), refs)
+}
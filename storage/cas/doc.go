@@ -0,0 +1,40 @@
+// Package cas реализует декоратор [storage.Storage] с content-addressable записью.
+//
+// Объект сохраняется под ключом, производным от sha256 содержимого (sha256/<hex>), поэтому
+// одинаковые файлы хранятся один раз. Счётчики ссылок позволяют безопасно удалять объекты,
+// на которые ссылаются несколько записей, например вложения, загруженные разными пользователями.
+//
+// Использование:
+//
+//	inner, err := minio.NewDefault(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	s := cas.New(inner, &cas.Options{
+//	    Backend: backend, // по умолчанию cas.NewMemoryBackend()
+//	})
+//
+//	res, err := s.PutCAS(ctx, "attachments", r, &storage.PutOptions{ContentType: "application/pdf"})
+//	if err != nil {
+//	    return err
+//	}
+//	// res.Key = "sha256/<hex>", res.Deduplicated — содержимое уже было в хранилище
+//
+//	info, err := s.Find(ctx, "attachments", checksum) // поиск по контрольной сумме
+//
+//	err = s.Delete(ctx, "attachments", res.Key) // объект удаляется после последней ссылки
+//
+// Особенности:
+//   - Содержимое буферизуется во временный файл (Options.TempDir), так как ключ известен
+//     только после чтения всего потока
+//   - При повторной записи того же содержимого объект не загружается, opts не применяются
+//   - Put и CreateMultipartUpload под Prefix отклоняются с CodeInvalidKey, чтобы запись
+//     не обходила учёт ссылок; остальные ключи работают как в исходном хранилище
+//   - Объект под Prefix без учтённых ссылок (записан до подключения Backend) удаляется первым Delete
+//   - Счётчики хранятся в [Backend]; [MemoryBackend] подходит для тестов и single-instance
+//     сервисов, для нескольких экземпляров нужна общая реализация (например, в БД)
+//   - Изменение счётчика и объекта одного ключа сериализуется только внутри процесса:
+//     при нескольких экземплярах конкурентные PutCAS и последний Delete того же содержимого
+//     могут удалить объект, на который только что добавлена ссылка
+package cas
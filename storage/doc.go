@@ -3,17 +3,102 @@
 // Пакет предоставляет базовые типы ошибок для S3-совместимых хранилищ.
 // Реализации находятся в дочерних пакетах:
 //   - [storage/minio] — MinIO/S3 адаптер
+//   - [storage/quota] — декоратор учёта использования и квот
+//   - [storage/audit] — декоратор журнала аудита изменяющих операций
+//   - [storage/cache] — кэширующий декоратор Get для небольших объектов с проверкой ETag
+//   - [storage/replica] — декоратор асинхронной репликации записи во вторичные хранилища с чтением при отказе основного
+//   - [storage/cas] — content-addressable декоратор: ключи по sha256, дедупликация и счётчики ссылок
+//   - [storage/trash] — декоратор мягкого удаления: корзина, восстановление и очистка по сроку хранения
+//
+// Пакет [storage/storagetest] содержит контрактные тесты интерфейса [Storage],
+// хранилище в памяти для модульных тестов и контейнер MinIO для интеграционных тестов новых адаптеров.
 //
 // Типы ошибок:
 //   - [ErrNotFound] — объект не найден
 //   - [ErrAccessDenied] — доступ запрещён
 //   - [ErrBucketNotFound] — bucket не существует
+//   - [ErrQuotaExceeded] — превышена квота
+//   - [ErrInvalidKey] — недопустимый ключ объекта
+//   - [ErrObjectLockNotEnabled] — для bucket'а не включена блокировка объектов
+//   - [ErrQueryNotSupported] — backend не поддерживает S3 Select
+//   - [ErrReaderClosed] — чтение из закрытого [ObjectReader]
 //   - [StorageError] — детальная ошибка с кодом и контекстом
 //
 // Хелперы для проверки ошибок:
 //   - [IsNotFound] — проверка ErrNotFound
 //   - [IsAccessDenied] — проверка ErrAccessDenied
 //   - [IsBucketNotFound] — проверка ErrBucketNotFound
+//   - [IsQuotaExceeded] — проверка ErrQuotaExceeded
+//   - [IsInvalidKey] — проверка ErrInvalidKey
+//   - [IsObjectLockNotEnabled] — проверка ErrObjectLockNotEnabled
+//   - [IsQueryNotSupported] — проверка ErrQueryNotSupported
+//
+// Метаданные объекта:
+//   - [Storage].Stat — метаданные без тела объекта; ошибка с кодом [CodeNotFound], если объекта нет
+//   - [ObjectInfo].VersionID — версия объекта в bucket'е с версионированием
+//   - [ObjectInfo].Checksums — дополнительные контрольные суммы ([Checksums]: CRC32, CRC32C, CRC64NVME, SHA1, SHA256)
+//
+// Политика ключей:
+//   - [KeyPolicy] — длина, запрещённые символы, только ASCII, запрет path traversal ("../", ведущий "/")
+//   - [NormalizeKeyNFC] — приведение ключа к Unicode NFC
+//   - [KeyValidator], [KeyNormalizer], [CheckKey] — проверка перед каждой операцией адаптера;
+//     ошибка возвращается с кодом [CodeInvalidKey]
+//
+// Заголовки запросов:
+//   - [PutOptions].CacheControl, ContentDisposition, ContentEncoding — сохраняются с объектом
+//     и возвращаются в [ObjectInfo] из Get и List
+//   - [PutOptions].Headers и [GetOptions].Headers — дополнительные HTTP-заголовки (Cache-Control, Content-Disposition и т.п.)
+//   - RequestPayer — запросы к requester-pays bucket'ам
+//   - [OptionsGetter] — хранилища, поддерживающие GetWithOptions
+//
+// Фильтрация List:
+//   - [ListOptions] — ModifiedAfter/ModifiedBefore, MinSize/MaxSize, Metadata, сортировка SortBy/SortDesc
+//   - StatMetadata — запрос метаданных отдельным stat, если листинг их не возвращает (AWS S3)
+//   - [ListOptions.Match], [SortObjects], [MetadataValue] — для реализации фильтров в адаптерах
+//
+// Прогресс передачи:
+//   - [PutOptions].Progress и [GetOptions].Progress — [ProgressFunc] вызывается после каждого чтения
+//   - [NewProgressReader], [NewProgressReadCloser], [NewProgressWriter] — обёртки со счётчиком байт
+//
+// Архивы:
+//   - [ArchivePrefix] — потоковая упаковка всех объектов под префиксом в tar или zip
+//     без промежуточных файлов, с ограничением параллельных GET и опциональным manifest.json
+//
+// Пакетная публикация:
+//   - [PutBatch] — загрузка набора связанных объектов под "<prefix><batch id>/" и запись манифеста
+//     последним (commit marker); при ошибке загруженные объекты удаляются, прежний пакет остаётся опубликованным
+//   - [ReadBatch] — манифест опубликованного пакета или [ErrBatchNotCommitted]; [BatchManifest].Open
+//     читает объекты только из опубликованного набора
+//
+// Загрузки из браузера:
+//   - [UploadHandler] — http.Handler для multipart/form-data и возобновляемых загрузок по протоколу tus 1.0,
+//     потоково пишет в хранилище через multipart API с ограничением размера и типов содержимого
+//     и частями до [MaxUploadParts]: размер части растёт для больших файлов ([UploadPartSize]);
+//     скорость ограничивается на загрузку (BandwidthLimit) и общим [BandwidthLimiter] (token bucket);
+//     память возобновляемых загрузок ограничена MaxBuffer, размер объекта — MaxSize (по умолчанию 5 GiB)
+//   - [PresignedUploader] — presigned PUT во временный префикс с подписанным токеном; VerifyUpload
+//     проверяет размер, тип содержимого и sha256 загруженного объекта и переносит его под итоговый ключ
+//   - [PartPresigner] — presigned PUT для частей multipart-загрузки: клиент загружает части напрямую,
+//     сервер создаёт и завершает загрузку (CreateMultipartUpload, CompleteMultipartUpload)
+//
+// Ограничение доступа:
+//   - [NewReadOnlyStorage] — только чтение; запись, удаление и multipart-загрузки отклоняются с [CodeAccessDenied]
+//   - [NewWriteOnlyStorage] — только запись новых объектов; чтение содержимого, List и Delete отклоняются
+//
+// Неизменяемые объекты (WORM):
+//   - [ObjectLocker] — S3 object lock: срок хранения ([Retention], режимы GOVERNANCE и COMPLIANCE)
+//     и legal hold для версии объекта; bucket должен быть создан с включённым object lock
+//   - [PutOptions].Retention и LegalHold — блокировка объекта при загрузке, [ObjectInfo] возвращает их из Get и Stat
+//
+// Чтение с произвольной позиции:
+//   - [SeekableOpener] — OpenSeekable возвращает [ObjectReader] (io.ReadSeekCloser): Seek только
+//     перемещает позицию, следующий Read запрашивает диапазон с этой позиции. Подходит для
+//     http.ServeContent и range-запросов к видео и аудио без загрузки файла целиком
+//
+// Фильтрация на стороне хранилища:
+//   - [Querier] — S3 Select: SQL-выражение выполняется над CSV, JSON или Parquet объектом на сервере,
+//     клиент получает потоком только подходящие записи ([QueryInput], [QueryOutput]);
+//     backend без S3 Select возвращает ошибку с [CodeQueryNotSupported]
 //
 // Использование:
 //
@@ -27,4 +112,8 @@
 //   - [CodeAccessDenied] — доступ запрещён
 //   - [CodeBucketNotFound] — bucket не существует
 //   - [CodeInternalError] — внутренняя ошибка
+//   - [CodeQuotaExceeded] — превышена квота
+//   - [CodeInvalidKey] — недопустимый ключ объекта
+//   - [CodeObjectLockNotEnabled] — для bucket'а не включена блокировка объектов
+//   - [CodeQueryNotSupported] — backend не поддерживает S3 Select
 package storage
@@ -10,6 +10,11 @@ var (
 	ErrNotFound       = errors.New("object not found")
 	ErrAccessDenied   = errors.New("access denied")
 	ErrBucketNotFound = errors.New("bucket not found")
+	ErrQuotaExceeded  = errors.New("quota exceeded")
+	ErrInvalidKey     = errors.New("invalid object key")
+
+	ErrObjectLockNotEnabled = errors.New("object lock not enabled")
+	ErrQueryNotSupported    = errors.New("query not supported")
 )
 
 // ErrorCode represents a storage error code.
@@ -20,6 +25,11 @@ const (
 	CodeAccessDenied   ErrorCode = "AccessDenied"
 	CodeBucketNotFound ErrorCode = "BucketNotFound"
 	CodeInternalError  ErrorCode = "InternalError"
+	CodeQuotaExceeded  ErrorCode = "QuotaExceeded"
+	CodeInvalidKey     ErrorCode = "InvalidKey"
+
+	CodeObjectLockNotEnabled ErrorCode = "ObjectLockNotEnabled"
+	CodeQueryNotSupported    ErrorCode = "QueryNotSupported"
 )
 
 // StorageError wraps storage operation errors.
@@ -68,3 +78,40 @@ func IsBucketNotFound(err error) bool {
 	}
 	return errors.Is(err, ErrBucketNotFound)
 }
+
+// IsQuotaExceeded checks if error is a "quota exceeded" error.
+func IsQuotaExceeded(err error) bool {
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) {
+		return storageErr.Code == CodeQuotaExceeded
+	}
+	return errors.Is(err, ErrQuotaExceeded)
+}
+
+// IsInvalidKey checks if error is an "invalid object key" error.
+func IsInvalidKey(err error) bool {
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) {
+		return storageErr.Code == CodeInvalidKey
+	}
+	return errors.Is(err, ErrInvalidKey)
+}
+
+// IsObjectLockNotEnabled checks if error is returned for an object lock operation
+// on a bucket without object lock.
+func IsObjectLockNotEnabled(err error) bool {
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) {
+		return storageErr.Code == CodeObjectLockNotEnabled
+	}
+	return errors.Is(err, ErrObjectLockNotEnabled)
+}
+
+// IsQueryNotSupported checks if error is returned for a Query on a backend without S3 Select.
+func IsQueryNotSupported(err error) bool {
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) {
+		return storageErr.Code == CodeQueryNotSupported
+	}
+	return errors.Is(err, ErrQueryNotSupported)
+}
@@ -209,6 +209,34 @@ func TestIsBucketNotFound(t *testing.T) {
 	})
 }
 
+// TestIsQuotaExceeded tests the IsQuotaExceeded helper function.
+func TestIsQuotaExceeded(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsQuotaExceeded(&StorageError{Code: CodeQuotaExceeded}))
+	assert.False(t, IsQuotaExceeded(&StorageError{Code: CodeNotFound}))
+	assert.True(t, IsQuotaExceeded(fmt.Errorf("wrapped: %w", ErrQuotaExceeded)))
+	assert.False(t, IsQuotaExceeded(errors.New("some other error")))
+	assert.False(t, IsQuotaExceeded(nil))
+}
+
+// TestIsInvalidKey tests the IsInvalidKey helper function.
+func TestIsInvalidKey(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsInvalidKey(&StorageError{Code: CodeInvalidKey}))
+	assert.False(t, IsInvalidKey(&StorageError{Code: CodeNotFound}))
+	assert.True(t, IsInvalidKey(fmt.Errorf("wrapped: %w", ErrInvalidKey)))
+	assert.False(t, IsInvalidKey(nil))
+}
+
+// TestIsQueryNotSupported tests the IsQueryNotSupported helper function.
+func TestIsQueryNotSupported(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsQueryNotSupported(&StorageError{Code: CodeQueryNotSupported}))
+	assert.False(t, IsQueryNotSupported(&StorageError{Code: CodeNotFound}))
+	assert.True(t, IsQueryNotSupported(fmt.Errorf("wrapped: %w", ErrQueryNotSupported)))
+	assert.False(t, IsQueryNotSupported(nil))
+}
+
 // TestErrorCode_values tests that ErrorCode constants have expected values.
 func TestErrorCode_values(t *testing.T) {
 	t.Parallel()
@@ -216,6 +244,8 @@ func TestErrorCode_values(t *testing.T) {
 	assert.Equal(t, ErrorCode("AccessDenied"), CodeAccessDenied)
 	assert.Equal(t, ErrorCode("BucketNotFound"), CodeBucketNotFound)
 	assert.Equal(t, ErrorCode("InternalError"), CodeInternalError)
+	assert.Equal(t, ErrorCode("QuotaExceeded"), CodeQuotaExceeded)
+	assert.Equal(t, ErrorCode("InvalidKey"), CodeInvalidKey)
 }
 
 // TestNewStorageError tests creating StorageError instances.
@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	_ Storage        = (*guardedStorage)(nil)
+	_ OptionsGetter  = (*guardedStorage)(nil)
+	_ PartPresigner  = (*guardedStorage)(nil)
+	_ ObjectLocker   = (*guardedStorage)(nil)
+	_ Querier        = (*guardedStorage)(nil)
+	_ SeekableOpener = (*guardedStorage)(nil)
+)
+
+// guardMode is the set of operations a guarded storage allows.
+type guardMode string
+
+const (
+	guardReadOnly  guardMode = "read-only"
+	guardWriteOnly guardMode = "write-only"
+)
+
+// guardedStorage rejects operations not allowed by its mode.
+type guardedStorage struct {
+	inner Storage
+	mode  guardMode
+}
+
+// NewReadOnlyStorage wraps s so that only reads are allowed: Get, GetWithOptions, GetFileHeader,
+// OpenSeekable, Exists, Stat, List, ListMultipartUploads, Query, GET presigned URLs and reading
+// object lock settings. Every other operation fails with CodeAccessDenied without reaching s.
+//
+// Close is forwarded to s: close the handle only if the subsystem owns the storage.
+func NewReadOnlyStorage(s Storage) Storage {
+	return &guardedStorage{inner: s, mode: guardReadOnly}
+}
+
+// NewWriteOnlyStorage wraps s so that only new objects can be written: Put, multipart uploads
+// (including AbortMultipartUpload and presigned parts) and PUT presigned URLs. Exists, Stat and
+// ListMultipartUploads are allowed too, to check the written objects and resume uploads.
+// Reading contents (including OpenSeekable), listing, Query, Delete and changing object lock settings
+// fail with CodeAccessDenied without reaching s.
+//
+// Close is forwarded to s: close the handle only if the subsystem owns the storage.
+func NewWriteOnlyStorage(s Storage) Storage {
+	return &guardedStorage{inner: s, mode: guardWriteOnly}
+}
+
+// deny returns the access denied error for op.
+func (g *guardedStorage) deny(op, bucket, key string) error {
+	return &StorageError{
+		Code:    CodeAccessDenied,
+		Message: op + " is not allowed on " + string(g.mode) + " storage",
+		Err:     ErrAccessDenied,
+		Bucket:  bucket,
+		Key:     key,
+	}
+}
+
+func (g *guardedStorage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *PutOptions) error {
+	if g.mode != guardWriteOnly {
+		return g.deny("put", bucket, key)
+	}
+	return g.inner.Put(ctx, bucket, key, reader, opts)
+}
+
+func (g *guardedStorage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error) {
+	if g.mode != guardReadOnly {
+		return nil, nil, g.deny("get", bucket, key)
+	}
+	return g.inner.Get(ctx, bucket, key)
+}
+
+// GetWithOptions forwards to the wrapped storage if it implements OptionsGetter.
+func (g *guardedStorage) GetWithOptions(ctx context.Context, bucket, key string, opts *GetOptions) (io.ReadCloser, *ObjectInfo, error) {
+	if g.mode != guardReadOnly {
+		return nil, nil, g.deny("get", bucket, key)
+	}
+	getter, ok := g.inner.(OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	return getter.GetWithOptions(ctx, bucket, key, opts)
+}
+
+// OpenSeekable forwards to the wrapped storage if it implements SeekableOpener.
+func (g *guardedStorage) OpenSeekable(ctx context.Context, bucket, key string) (*ObjectReader, error) {
+	if g.mode != guardReadOnly {
+		return nil, g.deny("get", bucket, key)
+	}
+	opener, ok := g.inner.(SeekableOpener)
+	if !ok {
+		return nil, errors.New("underlying storage does not support seekable reads")
+	}
+	return opener.OpenSeekable(ctx, bucket, key)
+}
+
+// Delete is denied in both modes.
+func (g *guardedStorage) Delete(_ context.Context, bucket, key string) error {
+	return g.deny("delete", bucket, key)
+}
+
+func (g *guardedStorage) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	return g.inner.Exists(ctx, bucket, key)
+}
+
+func (g *guardedStorage) Stat(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	return g.inner.Stat(ctx, bucket, key)
+}
+
+func (g *guardedStorage) List(ctx context.Context, bucket string, opts *ListOptions) (*ListResult, error) {
+	if g.mode != guardReadOnly {
+		return nil, g.deny("list", bucket, "")
+	}
+	return g.inner.List(ctx, bucket, opts)
+}
+
+// GetPresignedURL allows GET URLs on read-only and PUT URLs on write-only storage.
+func (g *guardedStorage) GetPresignedURL(ctx context.Context, bucket, key string, opts *PresignedURLOptions) (string, error) {
+	method := http.MethodGet
+	if opts != nil && opts.Method != "" {
+		method = strings.ToUpper(opts.Method)
+	}
+	allowed := (g.mode == guardReadOnly && method == http.MethodGet) ||
+		(g.mode == guardWriteOnly && method == http.MethodPut)
+	if !allowed {
+		return "", g.deny("presigned "+method, bucket, key)
+	}
+	return g.inner.GetPresignedURL(ctx, bucket, key, opts)
+}
+
+func (g *guardedStorage) GetFileHeader(ctx context.Context, bucket, key string) ([]byte, error) {
+	if g.mode != guardReadOnly {
+		return nil, g.deny("get", bucket, key)
+	}
+	return g.inner.GetFileHeader(ctx, bucket, key)
+}
+
+func (g *guardedStorage) CreateMultipartUpload(ctx context.Context, bucket, key string, opts *PutOptions) (*MultipartUpload, error) {
+	if g.mode != guardWriteOnly {
+		return nil, g.deny("multipart upload", bucket, key)
+	}
+	return g.inner.CreateMultipartUpload(ctx, bucket, key, opts)
+}
+
+func (g *guardedStorage) UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, reader io.Reader) (*UploadedPart, error) {
+	if g.mode != guardWriteOnly {
+		return nil, g.deny("multipart upload", bucket, key)
+	}
+	return g.inner.UploadPart(ctx, bucket, key, uploadID, partNumber, reader)
+}
+
+func (g *guardedStorage) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, opts *CompleteMultipartUploadOptions) (*ObjectInfo, error) {
+	if g.mode != guardWriteOnly {
+		return nil, g.deny("multipart upload", bucket, key)
+	}
+	return g.inner.CompleteMultipartUpload(ctx, bucket, key, uploadID, opts)
+}
+
+func (g *guardedStorage) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	if g.mode != guardWriteOnly {
+		return g.deny("multipart upload", bucket, key)
+	}
+	return g.inner.AbortMultipartUpload(ctx, bucket, key, uploadID)
+}
+
+func (g *guardedStorage) ListMultipartUploads(ctx context.Context, bucket string) ([]MultipartUpload, error) {
+	return g.inner.ListMultipartUploads(ctx, bucket)
+}
+
+// GetPresignedUploadPartURL forwards to the wrapped storage if it implements PartPresigner.
+func (g *guardedStorage) GetPresignedUploadPartURL(ctx context.Context, bucket, key, uploadID string, partNumber int32, expiry time.Duration) (string, error) {
+	if g.mode != guardWriteOnly {
+		return "", g.deny("multipart upload", bucket, key)
+	}
+	presigner, ok := g.inner.(PartPresigner)
+	if !ok {
+		return "", errors.New("underlying storage does not support presigned parts")
+	}
+	return presigner.GetPresignedUploadPartURL(ctx, bucket, key, uploadID, partNumber, expiry)
+}
+
+// PutObjectRetention is denied in both modes.
+func (g *guardedStorage) PutObjectRetention(_ context.Context, bucket, key string, _ *Retention, _ *RetentionOptions) error {
+	return g.deny("object retention change", bucket, key)
+}
+
+// GetObjectRetention forwards to the wrapped storage if it implements ObjectLocker.
+func (g *guardedStorage) GetObjectRetention(ctx context.Context, bucket, key string) (*Retention, error) {
+	if g.mode != guardReadOnly {
+		return nil, g.deny("object retention read", bucket, key)
+	}
+	locker, ok := g.inner.(ObjectLocker)
+	if !ok {
+		return nil, errors.New("underlying storage does not support object lock")
+	}
+	return locker.GetObjectRetention(ctx, bucket, key)
+}
+
+// PutObjectLegalHold is denied in both modes.
+func (g *guardedStorage) PutObjectLegalHold(_ context.Context, bucket, key string, _ bool) error {
+	return g.deny("legal hold change", bucket, key)
+}
+
+// GetObjectLegalHold forwards to the wrapped storage if it implements ObjectLocker.
+func (g *guardedStorage) GetObjectLegalHold(ctx context.Context, bucket, key string) (bool, error) {
+	if g.mode != guardReadOnly {
+		return false, g.deny("legal hold read", bucket, key)
+	}
+	locker, ok := g.inner.(ObjectLocker)
+	if !ok {
+		return false, errors.New("underlying storage does not support object lock")
+	}
+	return locker.GetObjectLegalHold(ctx, bucket, key)
+}
+
+// Query forwards to the wrapped storage if it implements Querier.
+func (g *guardedStorage) Query(ctx context.Context, bucket, key, expression string, input QueryInput, output QueryOutput) (io.ReadCloser, error) {
+	if g.mode != guardReadOnly {
+		return nil, g.deny("query", bucket, key)
+	}
+	querier, ok := g.inner.(Querier)
+	if !ok {
+		return nil, &StorageError{
+			Code:    CodeQueryNotSupported,
+			Message: "underlying storage does not support queries",
+			Err:     ErrQueryNotSupported,
+			Bucket:  bucket,
+			Key:     key,
+		}
+	}
+	return querier.Query(ctx, bucket, key, expression, input, output)
+}
+
+func (g *guardedStorage) Close() error {
+	return g.inner.Close()
+}
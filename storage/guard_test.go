@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guardStorage records the operations that reach it; other methods panic on the nil Storage.
+type guardStorage struct {
+	Storage
+	calls []string
+}
+
+func (s *guardStorage) Put(context.Context, string, string, io.Reader, *PutOptions) error {
+	s.calls = append(s.calls, "put")
+	return nil
+}
+
+func (s *guardStorage) Get(context.Context, string, string) (io.ReadCloser, *ObjectInfo, error) {
+	s.calls = append(s.calls, "get")
+	return io.NopCloser(strings.NewReader("data")), &ObjectInfo{}, nil
+}
+
+func (s *guardStorage) Stat(context.Context, string, string) (*ObjectInfo, error) {
+	s.calls = append(s.calls, "stat")
+	return &ObjectInfo{}, nil
+}
+
+func (s *guardStorage) List(context.Context, string, *ListOptions) (*ListResult, error) {
+	s.calls = append(s.calls, "list")
+	return &ListResult{}, nil
+}
+
+func (s *guardStorage) GetPresignedURL(context.Context, string, string, *PresignedURLOptions) (string, error) {
+	s.calls = append(s.calls, "presign")
+	return "https://example.com", nil
+}
+
+func (s *guardStorage) CreateMultipartUpload(context.Context, string, string, *PutOptions) (*MultipartUpload, error) {
+	s.calls = append(s.calls, "multipart")
+	return &MultipartUpload{UploadID: "1"}, nil
+}
+
+// TestNewReadOnlyStorage tests that a read-only storage forwards reads and denies writes.
+func TestNewReadOnlyStorage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := &guardStorage{}
+	s := NewReadOnlyStorage(inner)
+
+	_, _, err := s.Get(ctx, "b", "k")
+	require.NoError(t, err)
+	_, err = s.Stat(ctx, "b", "k")
+	require.NoError(t, err)
+	_, err = s.List(ctx, "b", nil)
+	require.NoError(t, err)
+	_, err = s.GetPresignedURL(ctx, "b", "k", nil)
+	require.NoError(t, err)
+
+	denied := []error{
+		s.Put(ctx, "b", "k", strings.NewReader("x"), nil),
+		s.Delete(ctx, "b", "k"),
+		s.AbortMultipartUpload(ctx, "b", "k", "1"),
+		s.(ObjectLocker).PutObjectLegalHold(ctx, "b", "k", false),
+	}
+	_, err = s.CreateMultipartUpload(ctx, "b", "k", nil)
+	denied = append(denied, err)
+	_, err = s.GetPresignedURL(ctx, "b", "k", &PresignedURLOptions{Method: "put"})
+	denied = append(denied, err)
+	for _, err := range denied {
+		assert.True(t, IsAccessDenied(err), "%v", err)
+	}
+	assert.Equal(t, []string{"get", "stat", "list", "presign"}, inner.calls)
+
+	_, err = s.(Querier).Query(ctx, "b", "k", "SELECT * FROM S3Object", QueryInput{}, QueryOutput{})
+	assert.True(t, IsQueryNotSupported(err), "%v", err)
+	_, err = s.(SeekableOpener).OpenSeekable(ctx, "b", "k")
+	assert.EqualError(t, err, "underlying storage does not support seekable reads")
+}
+
+// TestNewWriteOnlyStorage tests that a write-only storage forwards writes and denies reads and deletes.
+func TestNewWriteOnlyStorage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := &guardStorage{}
+	s := NewWriteOnlyStorage(inner)
+
+	require.NoError(t, s.Put(ctx, "b", "k", strings.NewReader("x"), nil))
+	_, err := s.CreateMultipartUpload(ctx, "b", "k", nil)
+	require.NoError(t, err)
+	_, err = s.Stat(ctx, "b", "k")
+	require.NoError(t, err)
+	_, err = s.GetPresignedURL(ctx, "b", "k", &PresignedURLOptions{Method: "PUT"})
+	require.NoError(t, err)
+
+	_, _, err = s.Get(ctx, "b", "k")
+	assert.True(t, IsAccessDenied(err))
+	_, err = s.GetFileHeader(ctx, "b", "k")
+	assert.True(t, IsAccessDenied(err))
+	_, err = s.List(ctx, "b", nil)
+	assert.True(t, IsAccessDenied(err))
+	_, err = s.GetPresignedURL(ctx, "b", "k", nil)
+	assert.True(t, IsAccessDenied(err))
+	err = s.Delete(ctx, "b", "k")
+	assert.True(t, IsAccessDenied(err))
+	assert.EqualError(t, err, "storage.AccessDenied: delete is not allowed on write-only storage (bucket=b, key=k): access denied")
+	_, err = s.(Querier).Query(ctx, "b", "k", "SELECT * FROM S3Object", QueryInput{}, QueryOutput{})
+	assert.True(t, IsAccessDenied(err))
+	_, err = s.(SeekableOpener).OpenSeekable(ctx, "b", "k")
+	assert.True(t, IsAccessDenied(err))
+
+	assert.Equal(t, []string{"put", "multipart", "stat", "presign"}, inner.calls)
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMaxKeyLength is the maximum object key length in bytes allowed by S3.
+const DefaultMaxKeyLength = 1024
+
+// KeyValidator checks an object key before a storage operation.
+// It returns an error describing why the key is not allowed.
+type KeyValidator func(key string) error
+
+// KeyNormalizer rewrites an object key before validation, e.g. to a canonical Unicode form.
+type KeyNormalizer func(key string) string
+
+// KeyPolicy describes which object keys are allowed.
+// The zero value rejects empty keys, control characters, invalid UTF-8,
+// keys longer than DefaultMaxKeyLength and path traversal.
+type KeyPolicy struct {
+	MaxLength      int    // Maximum key length in bytes, 0 means DefaultMaxKeyLength
+	ForbiddenChars string // Characters not allowed in keys, e.g. `\{}^%`
+	ASCIIOnly      bool   // Reject non-ASCII characters such as emoji or Cyrillic
+	// AllowTraversal allows "." and ".." path segments, empty segments
+	// and a leading "/". Such keys are dangerous when mirrored to a file system.
+	AllowTraversal bool
+}
+
+// Validate checks key against the policy. It can be used as a KeyValidator.
+func (p KeyPolicy) Validate(key string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key is empty", ErrInvalidKey)
+	}
+	maxLength := p.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxKeyLength
+	}
+	if len(key) > maxLength {
+		return fmt.Errorf("%w: key is longer than %d bytes", ErrInvalidKey, maxLength)
+	}
+	if !utf8.ValidString(key) {
+		return fmt.Errorf("%w: key is not valid UTF-8", ErrInvalidKey)
+	}
+
+	for _, r := range key {
+		switch {
+		case unicode.IsControl(r):
+			return fmt.Errorf("%w: key contains control character %U", ErrInvalidKey, r)
+		case p.ASCIIOnly && r > unicode.MaxASCII:
+			return fmt.Errorf("%w: key contains non-ASCII character %q", ErrInvalidKey, r)
+		case strings.ContainsRune(p.ForbiddenChars, r):
+			return fmt.Errorf("%w: key contains forbidden character %q", ErrInvalidKey, r)
+		}
+	}
+
+	if !p.AllowTraversal {
+		if strings.HasPrefix(key, "/") {
+			return fmt.Errorf("%w: key starts with /", ErrInvalidKey)
+		}
+		// A trailing "/" marks a directory and is allowed.
+		for _, segment := range strings.Split(strings.TrimSuffix(key, "/"), "/") {
+			switch segment {
+			case ".", "..":
+				return fmt.Errorf("%w: key contains path segment %q", ErrInvalidKey, segment)
+			case "":
+				return fmt.Errorf("%w: key contains an empty path segment", ErrInvalidKey)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeKeyNFC converts key to Unicode Normalization Form C, so that visually
+// identical keys typed on different systems (e.g. "й" on macOS and Linux) match.
+func NormalizeKeyNFC(key string) string {
+	return norm.NFC.String(key)
+}
+
+// CheckKey normalizes key and validates the result. Either function may be nil.
+// Validation errors are returned as StorageError with CodeInvalidKey.
+func CheckKey(bucket, key string, normalize KeyNormalizer, validate KeyValidator) (string, error) {
+	normalized := key
+	if normalize != nil {
+		normalized = normalize(key)
+	}
+	if validate == nil {
+		return normalized, nil
+	}
+	if err := validate(normalized); err != nil {
+		return "", &StorageError{
+			Code:    CodeInvalidKey,
+			Message: "invalid object key",
+			Err:     err,
+			Bucket:  bucket,
+			Key:     key,
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeyPolicy_Validate tests the default and configured key policies.
+func TestKeyPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  KeyPolicy
+		key     string
+		wantErr string
+	}{
+		{name: "plain key", key: "reports/2024/q1.pdf"},
+		{name: "unicode key", key: "папка/файл.txt"},
+		{name: "emoji key", key: "photos/🎉.jpg"},
+		{name: "directory marker", key: "photos/"},
+		{name: "empty", key: "", wantErr: "key is empty"},
+		{name: "too long", key: strings.Repeat("a", DefaultMaxKeyLength+1), wantErr: "longer than 1024 bytes"},
+		{name: "custom max length", policy: KeyPolicy{MaxLength: 8}, key: "123456789", wantErr: "longer than 8 bytes"},
+		{name: "invalid utf-8", key: "file\xff.txt", wantErr: "not valid UTF-8"},
+		{name: "control character", key: "file\n.txt", wantErr: "control character U+000A"},
+		{name: "parent traversal", key: "../etc/passwd", wantErr: `path segment ".."`},
+		{name: "nested traversal", key: "a/../../b", wantErr: `path segment ".."`},
+		{name: "current directory", key: "a/./b", wantErr: `path segment "."`},
+		{name: "leading slash", key: "/etc/passwd", wantErr: "starts with /"},
+		{name: "empty segment", key: "a//b", wantErr: "empty path segment"},
+		{name: "traversal allowed", policy: KeyPolicy{AllowTraversal: true}, key: "../a//b"},
+		{name: "ascii only", policy: KeyPolicy{ASCIIOnly: true}, key: "photos/🎉.jpg", wantErr: `non-ASCII character '🎉'`},
+		{name: "forbidden character", policy: KeyPolicy{ForbiddenChars: `\{}`}, key: `a\b`, wantErr: `forbidden character '\\'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.Validate(tt.key)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidKey)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestCheckKey tests key normalization and the resulting error code.
+func TestCheckKey(t *testing.T) {
+	t.Parallel()
+
+	// "й" as "и" + combining breve (NFD, as produced by macOS)
+	decomposed := "\u0438\u0306.txt"
+	key, err := CheckKey("bucket", decomposed, NormalizeKeyNFC, KeyPolicy{}.Validate)
+	require.NoError(t, err)
+	assert.Equal(t, "\u0439.txt", key)
+
+	key, err = CheckKey("bucket", "../secret", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "../secret", key)
+
+	_, err = CheckKey("bucket", "../secret", nil, KeyPolicy{}.Validate)
+	require.Error(t, err)
+	assert.True(t, IsInvalidKey(err))
+	assert.True(t, errors.Is(err, ErrInvalidKey))
+
+	var storageErr *StorageError
+	require.ErrorAs(t, err, &storageErr)
+	assert.Equal(t, CodeInvalidKey, storageErr.Code)
+	assert.Equal(t, "bucket", storageErr.Bucket)
+	assert.Equal(t, "../secret", storageErr.Key)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+)
+
+// ListSort selects the order of ListResult.Objects.
+type ListSort string
+
+const (
+	SortNone         ListSort = ""              // Backend order (usually lexicographic by key)
+	SortKey          ListSort = "key"           // By key
+	SortLastModified ListSort = "last_modified" // By modification time
+	SortSize         ListSort = "size"          // By size
+)
+
+// HasFilters reports whether opts filter objects beyond the prefix.
+func (o *ListOptions) HasFilters() bool {
+	return !o.ModifiedAfter.IsZero() || !o.ModifiedBefore.IsZero() ||
+		o.MinSize > 0 || o.MaxSize > 0 || len(o.Metadata) > 0
+}
+
+// Match reports whether the object passes the filters of opts.
+// Metadata keys are compared case-insensitively, with or without the x-amz-meta- prefix.
+func (o *ListOptions) Match(info ObjectInfo) bool {
+	if !o.ModifiedAfter.IsZero() && !info.LastModified.After(o.ModifiedAfter) {
+		return false
+	}
+	if !o.ModifiedBefore.IsZero() && !info.LastModified.Before(o.ModifiedBefore) {
+		return false
+	}
+	if o.MinSize > 0 && info.Size < o.MinSize {
+		return false
+	}
+	if o.MaxSize > 0 && info.Size > o.MaxSize {
+		return false
+	}
+	for key, value := range o.Metadata {
+		if v, ok := MetadataValue(info.Metadata, key); !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// MetadataValue looks up a user metadata value. Backends return keys in different forms
+// ("owner", "Owner", "X-Amz-Meta-Owner"), so the lookup ignores case and the x-amz-meta- prefix.
+func MetadataValue(metadata map[string]string, key string) (string, bool) {
+	want := normalizeMetadataKey(key)
+	for k, v := range metadata {
+		if normalizeMetadataKey(k) == want {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func normalizeMetadataKey(key string) string {
+	lower := strings.ToLower(key)
+	return strings.TrimPrefix(lower, "x-amz-meta-")
+}
+
+// SortObjects sorts objects in place by the given field; ties are ordered by key.
+func SortObjects(objects []ObjectInfo, by ListSort, desc bool) {
+	if by == SortNone {
+		return
+	}
+
+	less := func(a, b ObjectInfo) bool {
+		switch by {
+		case SortLastModified:
+			if !a.LastModified.Equal(b.LastModified) {
+				return a.LastModified.Before(b.LastModified)
+			}
+		case SortSize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		}
+		return a.Key < b.Key
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		if desc {
+			return less(objects[j], objects[i])
+		}
+		return less(objects[i], objects[j])
+	})
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListOptions_Match tests object filtering by time, size and metadata.
+func TestListOptions_Match(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	info := ObjectInfo{
+		Key:          "a.txt",
+		Size:         100,
+		LastModified: now,
+		Metadata:     map[string]string{"X-Amz-Meta-Owner": "alice"},
+	}
+
+	testCases := []struct {
+		name  string
+		opts  ListOptions
+		match bool
+	}{
+		{name: "no filters", opts: ListOptions{}, match: true},
+		{name: "modified after", opts: ListOptions{ModifiedAfter: now.Add(-time.Hour)}, match: true},
+		{name: "modified after excludes", opts: ListOptions{ModifiedAfter: now}, match: false},
+		{name: "modified before", opts: ListOptions{ModifiedBefore: now.Add(time.Hour)}, match: true},
+		{name: "modified before excludes", opts: ListOptions{ModifiedBefore: now}, match: false},
+		{name: "min size", opts: ListOptions{MinSize: 100}, match: true},
+		{name: "min size excludes", opts: ListOptions{MinSize: 101}, match: false},
+		{name: "max size", opts: ListOptions{MaxSize: 100}, match: true},
+		{name: "max size excludes", opts: ListOptions{MaxSize: 99}, match: false},
+		{name: "metadata", opts: ListOptions{Metadata: map[string]string{"owner": "alice"}}, match: true},
+		{name: "metadata value differs", opts: ListOptions{Metadata: map[string]string{"owner": "bob"}}, match: false},
+		{name: "metadata key missing", opts: ListOptions{Metadata: map[string]string{"team": "core"}}, match: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.match, tc.opts.Match(info))
+			assert.Equal(t, tc.name != "no filters", tc.opts.HasFilters())
+		})
+	}
+}
+
+// TestMetadataValue tests metadata lookup across backend key forms.
+func TestMetadataValue(t *testing.T) {
+	t.Parallel()
+	for _, key := range []string{"owner", "Owner", "X-Amz-Meta-Owner", "x-amz-meta-owner"} {
+		value, ok := MetadataValue(map[string]string{key: "alice"}, "owner")
+		assert.True(t, ok, key)
+		assert.Equal(t, "alice", value, key)
+	}
+
+	_, ok := MetadataValue(nil, "owner")
+	assert.False(t, ok)
+}
+
+// TestSortObjects tests sorting by each field in both directions.
+func TestSortObjects(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	objects := func() []ObjectInfo {
+		return []ObjectInfo{
+			{Key: "b", Size: 2, LastModified: now.Add(-time.Hour)},
+			{Key: "c", Size: 1, LastModified: now},
+			{Key: "a", Size: 2, LastModified: now.Add(-2 * time.Hour)},
+		}
+	}
+	keys := func(objs []ObjectInfo) []string {
+		result := make([]string, 0, len(objs))
+		for _, o := range objs {
+			result = append(result, o.Key)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		by       ListSort
+		desc     bool
+		expected []string
+	}{
+		{by: SortNone, expected: []string{"b", "c", "a"}},
+		{by: SortKey, expected: []string{"a", "b", "c"}},
+		{by: SortKey, desc: true, expected: []string{"c", "b", "a"}},
+		{by: SortLastModified, expected: []string{"a", "b", "c"}},
+		{by: SortLastModified, desc: true, expected: []string{"c", "b", "a"}},
+		{by: SortSize, expected: []string{"c", "a", "b"}},
+		{by: SortSize, desc: true, expected: []string{"b", "a", "c"}},
+	}
+
+	for _, tc := range testCases {
+		objs := objects()
+		SortObjects(objs, tc.by, tc.desc)
+		assert.Equal(t, tc.expected, keys(objs), "sort by %q desc=%v", tc.by, tc.desc)
+	}
+}
@@ -67,10 +67,16 @@ func NewClient(cfg Config, opts ...Option) (*Client, error) {
 		secure = false
 	}
 
+	transport, err := newTransport(secure)
+	if err != nil {
+		return nil, err
+	}
+
 	minioOpts := &minio.Options{
-		Creds:  creds,
-		Region: cfg.Region,
-		Secure: secure,
+		Creds:     creds,
+		Region:    cfg.Region,
+		Secure:    secure,
+		Transport: transport,
 	}
 
 	client, err := minio.New(endpoint, minioOpts)
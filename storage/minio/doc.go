@@ -4,6 +4,7 @@
 //   - загрузку и скачивание объектов
 //   - мультичастную загрузку
 //   - presigned URL для временного доступа
+//   - дополнительные заголовки запросов и requester-pays bucket'ы ([storage.PutOptions], [Storage.GetWithOptions])
 //   - OpenTelemetry tracing
 //
 // Использование:
@@ -13,6 +14,11 @@
 //	client, err := minio.Connect(ctx, minio.ConfigFromEnv())
 //	err = client.Upload(ctx, bucket, key, reader, size)
 //
+// Стандартные заголовки PutOptions.Headers (Cache-Control, Content-Disposition, Content-Encoding,
+// Content-Language, Expires, x-amz-storage-class) передаются через типизированные поля minio-go,
+// x-amz-meta-*, x-amz-acl и x-amz-grant-* — как метаданные. Остальные заголовки и RequestPayer
+// добавляются транспортом клиента ко всем запросам операции, включая части multipart загрузки.
+//
 // Конфигурация через переменные окружения:
 //
 //	MINIO_ENDPOINT   — адрес сервера (default: localhost:9000)
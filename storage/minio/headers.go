@@ -0,0 +1,221 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+const (
+	requestPayerHeader    = "X-Amz-Request-Payer"
+	requestPayerRequester = "requester"
+
+	// Object lock headers returned by Stat and Get
+	retentionModeHeader = "X-Amz-Object-Lock-Mode"
+	retainUntilHeader   = "X-Amz-Object-Lock-Retain-Until-Date"
+	legalHoldHeader     = "X-Amz-Object-Lock-Legal-Hold"
+)
+
+// extraHeadersKey is the context key for headers added by headerTransport.
+type extraHeadersKey struct{}
+
+// withExtraHeaders attaches headers that minio.PutObjectOptions cannot express.
+// headerTransport adds them to every request made with the returned context.
+func withExtraHeaders(ctx context.Context, headers http.Header) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, extraHeadersKey{}, headers)
+}
+
+// headerTransport adds headers stored by withExtraHeaders to outgoing requests.
+// Headers already set by minio-go are not overridden.
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers, _ := req.Context().Value(extraHeadersKey{}).(http.Header)
+	if len(headers) == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTripper must not modify the original request
+	r := req.Clone(req.Context())
+	for name, values := range headers {
+		if r.Header.Get(name) == "" {
+			r.Header[name] = values
+		}
+	}
+	return t.base.RoundTrip(r)
+}
+
+// newTransport returns the minio default transport wrapped with headerTransport.
+func newTransport(secure bool) (http.RoundTripper, error) {
+	base, err := minio.DefaultTransport(secure)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create S3 transport")
+	}
+	return &headerTransport{base: base}, nil
+}
+
+// putObjectOptions converts storage.PutOptions to minio.PutObjectOptions.
+// Standard headers are mapped to typed fields, metadata and ACL headers are sent
+// as user metadata, the rest are returned as extra headers for withExtraHeaders.
+func putObjectOptions(opts *storage.PutOptions) (minio.PutObjectOptions, http.Header, error) {
+	result := minio.PutObjectOptions{
+		ContentType:  opts.ContentType,
+		UserMetadata: opts.Metadata,
+	}
+	extra := make(http.Header)
+	var metadata map[string]string
+
+	for name, value := range opts.Headers {
+		key := http.CanonicalHeaderKey(name)
+		lower := strings.ToLower(key)
+		switch {
+		case key == "Content-Type":
+			if result.ContentType == "" {
+				result.ContentType = value
+			}
+		case key == "Cache-Control":
+			result.CacheControl = value
+		case key == "Content-Disposition":
+			result.ContentDisposition = value
+		case key == "Content-Encoding":
+			result.ContentEncoding = value
+		case key == "Content-Language":
+			result.ContentLanguage = value
+		case key == "Expires":
+			expires, err := http.ParseTime(value)
+			if err != nil {
+				return result, nil, errors.Wrapf(err, "invalid Expires header %q", value)
+			}
+			result.Expires = expires
+		case key == "X-Amz-Storage-Class":
+			result.StorageClass = value
+		case key == "X-Amz-Website-Redirect-Location":
+			result.WebsiteRedirectLocation = value
+		case strings.HasPrefix(lower, "x-amz-meta-") || strings.HasPrefix(lower, "x-amz-grant-") || lower == "x-amz-acl":
+			// minio-go sends these user metadata keys as is
+			if metadata == nil {
+				metadata = make(map[string]string, len(opts.Metadata)+len(opts.Headers))
+				for k, v := range opts.Metadata {
+					metadata[k] = v
+				}
+			}
+			metadata[key] = value
+		default:
+			extra.Set(key, value)
+		}
+	}
+
+	if metadata != nil {
+		result.UserMetadata = metadata
+	}
+	if opts.CacheControl != "" {
+		result.CacheControl = opts.CacheControl
+	}
+	if opts.ContentDisposition != "" {
+		result.ContentDisposition = opts.ContentDisposition
+	}
+	if opts.ContentEncoding != "" {
+		result.ContentEncoding = opts.ContentEncoding
+	}
+	if opts.RequestPayer {
+		extra.Set(requestPayerHeader, requestPayerRequester)
+	}
+	if opts.Retention != nil {
+		if err := opts.Retention.Validate(); err != nil {
+			return result, nil, errors.Wrap(err, "invalid retention")
+		}
+		result.Mode = minio.RetentionMode(opts.Retention.Mode)
+		result.RetainUntilDate = opts.Retention.RetainUntil
+	}
+	if opts.LegalHold {
+		result.LegalHold = minio.LegalHoldEnabled
+	}
+	return result, extra, nil
+}
+
+// getObjectOptions converts storage.GetOptions to minio.GetObjectOptions.
+func getObjectOptions(opts *storage.GetOptions) minio.GetObjectOptions {
+	result := minio.GetObjectOptions{}
+	for name, value := range opts.Headers {
+		result.Set(name, value)
+	}
+	if opts.RequestPayer {
+		result.Set(requestPayerHeader, requestPayerRequester)
+	}
+	return result
+}
+
+// toObjectInfo converts minio.ObjectInfo to storage.ObjectInfo.
+func toObjectInfo(key string, info minio.ObjectInfo) storage.ObjectInfo {
+	return storage.ObjectInfo{
+		Key:                key,
+		Size:               info.Size,
+		LastModified:       info.LastModified,
+		ETag:               info.ETag,
+		ContentType:        info.ContentType,
+		Metadata:           info.UserMetadata,
+		CacheControl:       objectHeader(info, "Cache-Control"),
+		ContentDisposition: objectHeader(info, "Content-Disposition"),
+		ContentEncoding:    objectHeader(info, "Content-Encoding"),
+		VersionID:          info.VersionID,
+		Checksums: storage.Checksums{
+			CRC32:     info.ChecksumCRC32,
+			CRC32C:    info.ChecksumCRC32C,
+			CRC64NVME: info.ChecksumCRC64NVME,
+			SHA1:      info.ChecksumSHA1,
+			SHA256:    info.ChecksumSHA256,
+		},
+		Retention: objectRetention(info),
+		LegalHold: strings.EqualFold(info.Metadata.Get(legalHoldHeader), string(minio.LegalHoldEnabled)),
+	}
+}
+
+// objectRetention returns the retention of the object from its object lock headers.
+func objectRetention(info minio.ObjectInfo) *storage.Retention {
+	mode := info.Metadata.Get(retentionModeHeader)
+	if mode == "" {
+		return nil
+	}
+	retainUntil, err := time.Parse(time.RFC3339, info.Metadata.Get(retainUntilHeader))
+	if err != nil {
+		return nil
+	}
+	return &storage.Retention{Mode: storage.RetentionMode(strings.ToUpper(mode)), RetainUntil: retainUntil}
+}
+
+// objectHeader returns a standard header of the object.
+// Stat and Get return it in Metadata, MinIO listings with metadata in UserMetadata.
+func objectHeader(info minio.ObjectInfo, name string) string {
+	if value := info.Metadata.Get(name); value != "" {
+		return value
+	}
+	for k, v := range info.UserMetadata {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// missingMetadata reports whether the listing lacks any of the metadata keys,
+// e.g. because the backend does not return metadata in listings.
+func missingMetadata(info storage.ObjectInfo, keys map[string]string) bool {
+	for key := range keys {
+		if _, ok := storage.MetadataValue(info.Metadata, key); !ok {
+			return true
+		}
+	}
+	return false
+}
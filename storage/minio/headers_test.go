@@ -0,0 +1,314 @@
+package minio
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// fakeS3 records request headers and serves a fixed object.
+// Uploads of unknown size go through the multipart API.
+type fakeS3 struct {
+	mu      sync.Mutex
+	headers map[string]http.Header // operation -> last request headers
+	counts  map[string]int         // operation -> number of requests
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.Copy(io.Discard, r.Body)
+	query := r.URL.Query()
+
+	operation := r.Method
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		operation = "InitiateMultipartUpload"
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		operation = "UploadPart"
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		operation = "CompleteMultipartUpload"
+	case r.Method == http.MethodGet && query.Get("list-type") == "2":
+		operation = "ListObjectsV2"
+	}
+	f.mu.Lock()
+	f.headers[operation] = r.Header.Clone()
+	f.counts[operation]++
+	f.mu.Unlock()
+
+	w.Header().Set("ETag", `"etag"`)
+	switch operation {
+	case "InitiateMultipartUpload":
+		_, _ = io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>key</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case "CompleteMultipartUpload":
+		_, _ = io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>key</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`)
+	case "ListObjectsV2":
+		// no user metadata, like AWS S3
+		_, _ = io.WriteString(w, `<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`+
+			`<Contents><Key>small.txt</Key><Size>5</Size><LastModified>2024-01-01T00:00:00.000Z</LastModified><ETag>"a"</ETag></Contents>`+
+			`<Contents><Key>large.txt</Key><Size>50</Size><LastModified>2024-02-01T00:00:00.000Z</LastModified><ETag>"b"</ETag></Contents>`+
+			`<Contents><Key>owned.txt</Key><Size>20</Size><LastModified>2024-03-01T00:00:00.000Z</LastModified><ETag>"c"</ETag></Contents>`+
+			`</ListBucketResult>`)
+	case http.MethodGet, http.MethodHead:
+		if strings.HasSuffix(r.URL.Path, "/owned.txt") {
+			w.Header().Set("X-Amz-Meta-Owner", "alice")
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Content-Disposition", "inline")
+		w.Header().Set("Content-Encoding", "identity")
+		w.Header().Set("Content-Length", "5")
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, "hello")
+		}
+	}
+}
+
+func (f *fakeS3) requestCount(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[operation]
+}
+
+func (f *fakeS3) requestHeaders(operation string) http.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.headers[operation]
+}
+
+// newFakeS3Storage creates a Storage backed by fakeS3.
+func newFakeS3Storage(t *testing.T) (*Storage, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{headers: make(map[string]http.Header), counts: make(map[string]int)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	mc, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:     credentials.NewStaticV4("access", "secret", ""),
+		Region:    "us-east-1",
+		Transport: &headerTransport{base: http.DefaultTransport},
+	})
+	require.NoError(t, err)
+
+	client := &Client{client: mc, logger: slog.Default()}
+	return NewStorage(client, nil), fake
+}
+
+// TestStorage_Put_Headers tests that PutOptions headers and request payer reach the server.
+func TestStorage_Put_Headers(t *testing.T) {
+	t.Parallel()
+	stor, fake := newFakeS3Storage(t)
+
+	err := stor.Put(context.Background(), "bucket", "key", strings.NewReader("hello"), &storage.PutOptions{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"owner": "alice"},
+		Headers: map[string]string{
+			"cache-control":       "public, max-age=3600",
+			"Content-Disposition": `attachment; filename="a.txt"`,
+			"X-Amz-Acl":           "public-read",
+			"X-Custom-Header":     "custom",
+		},
+		RequestPayer: true,
+	})
+	require.NoError(t, err)
+
+	headers := fake.requestHeaders("InitiateMultipartUpload")
+	require.NotNil(t, headers)
+	assert.Equal(t, "text/plain", headers.Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", headers.Get("Cache-Control"))
+	assert.Equal(t, `attachment; filename="a.txt"`, headers.Get("Content-Disposition"))
+	assert.Equal(t, "alice", headers.Get("X-Amz-Meta-Owner"))
+	assert.Equal(t, "public-read", headers.Get("X-Amz-Acl"))
+	assert.Equal(t, "custom", headers.Get("X-Custom-Header"))
+	assert.Equal(t, "requester", headers.Get("X-Amz-Request-Payer"))
+
+	// request payer applies to every request of the upload
+	assert.Equal(t, "requester", fake.requestHeaders("UploadPart").Get("X-Amz-Request-Payer"))
+	assert.Equal(t, "requester", fake.requestHeaders("CompleteMultipartUpload").Get("X-Amz-Request-Payer"))
+}
+
+// TestStorage_GetWithOptions tests that GetOptions headers and request payer reach the server.
+func TestStorage_GetWithOptions(t *testing.T) {
+	t.Parallel()
+	stor, fake := newFakeS3Storage(t)
+
+	reader, info, err := stor.GetWithOptions(context.Background(), "bucket", "key", &storage.GetOptions{
+		Headers:      map[string]string{"X-Custom-Header": "custom"},
+		RequestPayer: true,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, "no-cache", info.CacheControl)
+	assert.Equal(t, "inline", info.ContentDisposition)
+	assert.Equal(t, "identity", info.ContentEncoding)
+
+	headers := fake.requestHeaders(http.MethodGet)
+	require.NotNil(t, headers)
+	assert.Equal(t, "custom", headers.Get("X-Custom-Header"))
+	assert.Equal(t, "requester", headers.Get("X-Amz-Request-Payer"))
+}
+
+// TestPutObjectOptions tests conversion of storage.PutOptions to minio.PutObjectOptions.
+func TestPutObjectOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps standard headers to typed fields", func(t *testing.T) {
+		t.Parallel()
+		metadata := map[string]string{"owner": "alice"}
+		opts, extra, err := putObjectOptions(&storage.PutOptions{
+			Metadata: metadata,
+			Headers: map[string]string{
+				"Content-Type":        "image/png",
+				"Content-Encoding":    "gzip",
+				"Content-Language":    "ru",
+				"Expires":             "Wed, 21 Oct 2015 07:28:00 GMT",
+				"X-Amz-Storage-Class": "STANDARD_IA",
+				"X-Amz-Meta-Source":   "upload",
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "image/png", opts.ContentType)
+		assert.Equal(t, "gzip", opts.ContentEncoding)
+		assert.Equal(t, "ru", opts.ContentLanguage)
+		assert.Equal(t, 2015, opts.Expires.Year())
+		assert.Equal(t, "STANDARD_IA", opts.StorageClass)
+		assert.Equal(t, map[string]string{"owner": "alice", "X-Amz-Meta-Source": "upload"}, opts.UserMetadata)
+		assert.Equal(t, map[string]string{"owner": "alice"}, metadata, "caller metadata must not be modified")
+		assert.Empty(t, extra)
+	})
+
+	t.Run("content type option wins over header", func(t *testing.T) {
+		t.Parallel()
+		opts, _, err := putObjectOptions(&storage.PutOptions{
+			ContentType: "text/plain",
+			Headers:     map[string]string{"Content-Type": "image/png"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", opts.ContentType)
+	})
+
+	t.Run("typed fields win over headers", func(t *testing.T) {
+		t.Parallel()
+		opts, _, err := putObjectOptions(&storage.PutOptions{
+			Headers: map[string]string{
+				"Cache-Control":       "no-store",
+				"Content-Disposition": "inline",
+			},
+			CacheControl:       "public, max-age=31536000, immutable",
+			ContentDisposition: `attachment; filename="a.txt"`,
+			ContentEncoding:    "br",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "public, max-age=31536000, immutable", opts.CacheControl)
+		assert.Equal(t, `attachment; filename="a.txt"`, opts.ContentDisposition)
+		assert.Equal(t, "br", opts.ContentEncoding)
+	})
+
+	t.Run("object lock", func(t *testing.T) {
+		t.Parallel()
+		retainUntil := time.Now().Add(time.Hour)
+		opts, _, err := putObjectOptions(&storage.PutOptions{
+			Retention: &storage.Retention{Mode: storage.RetentionGovernance, RetainUntil: retainUntil},
+			LegalHold: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, minio.Governance, opts.Mode)
+		assert.Equal(t, retainUntil, opts.RetainUntilDate)
+		assert.Equal(t, minio.LegalHoldEnabled, opts.LegalHold)
+
+		_, _, err = putObjectOptions(&storage.PutOptions{
+			Retention: &storage.Retention{Mode: "WORM", RetainUntil: retainUntil},
+		})
+		assert.ErrorContains(t, err, "invalid retention")
+	})
+
+	t.Run("invalid expires", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := putObjectOptions(&storage.PutOptions{
+			Headers: map[string]string{"Expires": "tomorrow"},
+		})
+		assert.ErrorContains(t, err, "invalid Expires header")
+	})
+}
+
+// TestToObjectInfo tests that standard headers are read from stat and listing metadata.
+func TestToObjectInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stat metadata", func(t *testing.T) {
+		t.Parallel()
+		info := toObjectInfo("key", minio.ObjectInfo{
+			Size: 5,
+			Metadata: http.Header{
+				"Cache-Control":       {"max-age=60"},
+				"Content-Disposition": {"inline"},
+				"Content-Encoding":    {"gzip"},
+			},
+		})
+
+		assert.Equal(t, "key", info.Key)
+		assert.Equal(t, int64(5), info.Size)
+		assert.Equal(t, "max-age=60", info.CacheControl)
+		assert.Equal(t, "inline", info.ContentDisposition)
+		assert.Equal(t, "gzip", info.ContentEncoding)
+	})
+
+	t.Run("object lock headers", func(t *testing.T) {
+		t.Parallel()
+		info := toObjectInfo("key", minio.ObjectInfo{
+			Metadata: http.Header{
+				"X-Amz-Object-Lock-Mode":              {"COMPLIANCE"},
+				"X-Amz-Object-Lock-Retain-Until-Date": {"2030-01-02T03:04:05Z"},
+				"X-Amz-Object-Lock-Legal-Hold":        {"ON"},
+			},
+		})
+
+		require.NotNil(t, info.Retention)
+		assert.Equal(t, storage.RetentionCompliance, info.Retention.Mode)
+		assert.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), info.Retention.RetainUntil)
+		assert.True(t, info.LegalHold)
+	})
+
+	t.Run("version and checksums", func(t *testing.T) {
+		t.Parallel()
+		info := toObjectInfo("key", minio.ObjectInfo{
+			VersionID:      "v1",
+			ChecksumCRC32C: "yZRlqg==",
+			ChecksumSHA256: "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=",
+		})
+
+		assert.Equal(t, "v1", info.VersionID)
+		assert.Equal(t, "yZRlqg==", info.Checksums.CRC32C)
+		assert.Equal(t, "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=", info.Checksums.SHA256)
+		assert.Empty(t, info.Checksums.CRC32)
+		assert.False(t, info.Checksums.IsZero())
+	})
+
+	t.Run("listing user metadata", func(t *testing.T) {
+		t.Parallel()
+		info := toObjectInfo("key", minio.ObjectInfo{
+			UserMetadata: minio.StringMap{"cache-control": "no-cache"},
+		})
+
+		assert.Equal(t, "no-cache", info.CacheControl)
+		assert.Empty(t, info.ContentDisposition)
+	})
+}
@@ -0,0 +1,61 @@
+package minio
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// TestStorage_KeyValidator tests that invalid keys are rejected before any request.
+func TestStorage_KeyValidator(t *testing.T) {
+	t.Parallel()
+	client := &Client{
+		cfg:    Config{DefaultBucket: "bucket"},
+		logger: slog.Default(),
+	}
+	stor := NewStorage(client, &StorageOptions{
+		KeyNormalizer: storage.NormalizeKeyNFC,
+		KeyValidator:  storage.KeyPolicy{ASCIIOnly: true}.Validate,
+	})
+	ctx := context.Background()
+
+	for _, key := range []string{"../etc/passwd", "photos/🎉.jpg"} {
+		err := stor.Put(ctx, "", key, strings.NewReader("test"), nil)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		_, _, err = stor.Get(ctx, "", key)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		assert.True(t, storage.IsInvalidKey(stor.Delete(ctx, "", key)))
+
+		_, err = stor.Exists(ctx, "", key)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		_, err = stor.GetFileHeader(ctx, "", key)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		_, err = stor.GetPresignedURL(ctx, "", key, nil)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		_, err = stor.CreateMultipartUpload(ctx, "", key, nil)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		_, err = stor.UploadPart(ctx, "", key, "upload-id", 1, strings.NewReader("test"))
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		_, err = stor.CompleteMultipartUpload(ctx, "", key, "upload-id", nil)
+		assert.True(t, storage.IsInvalidKey(err), err)
+
+		assert.True(t, storage.IsInvalidKey(stor.AbortMultipartUpload(ctx, "", key, "upload-id")))
+	}
+
+	// valid keys reach the client
+	err := stor.Put(ctx, "", "reports/q1.pdf", strings.NewReader("test"), nil)
+	assert.False(t, storage.IsInvalidKey(err))
+	assert.Contains(t, err.Error(), "not initialized")
+}
@@ -0,0 +1,62 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+func listKeys(result *storage.ListResult) []string {
+	keys := make([]string, 0, len(result.Objects))
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	return keys
+}
+
+// TestStorage_List_Filters tests client-side filtering and sorting of listed objects.
+func TestStorage_List_Filters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("size and time", func(t *testing.T) {
+		t.Parallel()
+		stor, _ := newFakeS3Storage(t)
+		result, err := stor.List(context.Background(), "bucket", &storage.ListOptions{
+			MinSize:       10,
+			ModifiedAfter: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			SortBy:        storage.SortSize,
+			SortDesc:      true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"large.txt", "owned.txt"}, listKeys(result))
+	})
+
+	t.Run("metadata without stat", func(t *testing.T) {
+		t.Parallel()
+		stor, fake := newFakeS3Storage(t)
+		result, err := stor.List(context.Background(), "bucket", &storage.ListOptions{
+			Metadata: map[string]string{"owner": "alice"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result.Objects, "listing has no metadata")
+		assert.Equal(t, 0, fake.requestCount(http.MethodHead))
+	})
+
+	t.Run("metadata with stat", func(t *testing.T) {
+		t.Parallel()
+		stor, fake := newFakeS3Storage(t)
+		result, err := stor.List(context.Background(), "bucket", &storage.ListOptions{
+			Metadata:     map[string]string{"owner": "alice"},
+			StatMetadata: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"owned.txt"}, listKeys(result))
+		assert.Equal(t, 3, fake.requestCount(http.MethodHead))
+	})
+}
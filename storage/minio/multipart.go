@@ -29,6 +29,13 @@ func (s *Storage) CreateMultipartUpload(ctx context.Context, bucket, key string,
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	if opts == nil {
 		opts = &storage.PutOptions{}
 	}
@@ -46,12 +53,14 @@ func (s *Storage) CreateMultipartUpload(ctx context.Context, bucket, key string,
 	}
 
 	// Create multipart upload
-	minioOpts := minio.PutObjectOptions{
-		ContentType:  opts.ContentType,
-		UserMetadata: opts.Metadata,
+	minioOpts, extraHeaders, err := putObjectOptions(opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
 
-	uploadID, err := s.core().NewMultipartUpload(ctx, bucket, key, minioOpts)
+	uploadID, err := s.core().NewMultipartUpload(withExtraHeaders(ctx, extraHeaders), bucket, key, minioOpts)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -70,7 +79,7 @@ func (s *Storage) CreateMultipartUpload(ctx context.Context, bucket, key string,
 	)
 	span.SetStatus(codes.Ok, "")
 
-	s.logger.Debug("Multipart upload created", "bucket", bucket, "key", key, "upload_id", uploadID)
+	s.log(ctx).Debug("Multipart upload created", "bucket", bucket, "key", key, "upload_id", uploadID)
 	return result, nil
 }
 
@@ -83,6 +92,13 @@ func (s *Storage) UploadPart(ctx context.Context, bucket, key, uploadID string,
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -137,7 +153,7 @@ func (s *Storage) UploadPart(ctx context.Context, bucket, key, uploadID string,
 	)
 	span.SetStatus(codes.Ok, "")
 
-	s.logger.Debug("Part uploaded", "bucket", bucket, "key", key, "part_number", partNumber, "size", info.Size)
+	s.log(ctx).Debug("Part uploaded", "bucket", bucket, "key", key, "part_number", partNumber, "size", info.Size)
 	return result, nil
 }
 
@@ -150,6 +166,13 @@ func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uplo
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -209,7 +232,7 @@ func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uplo
 			attribute.String("etag", info.ETag),
 		)
 		span.SetStatus(codes.Ok, "")
-		s.logger.Info("Multipart upload completed", "bucket", bucket, "key", key, "size", totalSize)
+		s.log(ctx).Info("Multipart upload completed", "bucket", bucket, "key", key, "size", totalSize)
 		return result, nil
 	}
 
@@ -228,18 +251,11 @@ func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uplo
 			attribute.String("etag", info.ETag),
 		)
 		span.SetStatus(codes.Ok, "")
-		s.logger.Info("Multipart upload completed", "bucket", bucket, "key", key, "size", totalSize)
+		s.log(ctx).Info("Multipart upload completed", "bucket", bucket, "key", key, "size", totalSize)
 		return result, nil
 	}
 
-	result := &storage.ObjectInfo{
-		Key:          key,
-		Size:         stat.Size,
-		ETag:         stat.ETag,
-		ContentType:  stat.ContentType,
-		LastModified: stat.LastModified,
-		Metadata:     stat.UserMetadata,
-	}
+	result := toObjectInfo(key, stat)
 
 	span.SetAttributes(
 		attribute.Int64("size", stat.Size),
@@ -247,8 +263,8 @@ func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uplo
 	)
 	span.SetStatus(codes.Ok, "")
 
-	s.logger.Info("Multipart upload completed", "bucket", bucket, "key", key, "size", stat.Size)
-	return result, nil
+	s.log(ctx).Info("Multipart upload completed", "bucket", bucket, "key", key, "size", stat.Size)
+	return &result, nil
 }
 
 // AbortMultipartUpload aborts a multipart upload.
@@ -260,6 +276,13 @@ func (s *Storage) AbortMultipartUpload(ctx context.Context, bucket, key, uploadI
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -273,7 +296,7 @@ func (s *Storage) AbortMultipartUpload(ctx context.Context, bucket, key, uploadI
 		return err
 	}
 
-	err := s.core().AbortMultipartUpload(ctx, bucket, key, uploadID)
+	err = s.core().AbortMultipartUpload(ctx, bucket, key, uploadID)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -281,7 +304,7 @@ func (s *Storage) AbortMultipartUpload(ctx context.Context, bucket, key, uploadI
 	}
 
 	span.SetStatus(codes.Ok, "")
-	s.logger.Debug("Multipart upload aborted", "bucket", bucket, "key", key, "upload_id", uploadID)
+	s.log(ctx).Debug("Multipart upload aborted", "bucket", bucket, "key", key, "upload_id", uploadID)
 	return nil
 }
 
@@ -318,7 +341,7 @@ func (s *Storage) ListMultipartUploads(ctx context.Context, bucket string) ([]st
 		})
 	}
 
-	s.logger.Debug("ListMultipartUploads result", "bucket", bucket, "upload_count", len(uploads))
+	s.log(ctx).Debug("ListMultipartUploads result", "bucket", bucket, "upload_count", len(uploads))
 
 	span.SetAttributes(
 		attribute.Int("upload_count", len(uploads)),
@@ -2,7 +2,9 @@ package minio
 
 import (
 	"context"
+	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -22,6 +24,13 @@ func (s *Storage) GetPresignedURL(ctx context.Context, bucket, key string, opts
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
 	if opts == nil {
 		opts = &storage.PresignedURLOptions{
 			Method: "GET",
@@ -47,7 +56,6 @@ func (s *Storage) GetPresignedURL(ctx context.Context, bucket, key string, opts
 	}
 
 	var presignedURL *url.URL
-	var err error
 
 	// Validate method first (before client validation)
 	if opts.Method != "GET" && opts.Method != "PUT" {
@@ -79,7 +87,77 @@ func (s *Storage) GetPresignedURL(ctx context.Context, bucket, key string, opts
 	}
 
 	span.SetStatus(codes.Ok, "")
-	s.logger.Debug("Presigned URL generated", "bucket", bucket, "key", key, "method", opts.Method, "expiry", opts.Expiry)
+	s.log(ctx).Debug("Presigned URL generated", "bucket", bucket, "key", key, "method", opts.Method, "expiry", opts.Expiry)
+
+	return presignedURL.String(), nil
+}
+
+// maxPartNumber is the largest part number accepted by S3-compatible backends.
+const maxPartNumber = 10000
+
+// GetPresignedUploadPartURL generates a presigned PUT URL for a part of a multipart upload
+// created with CreateMultipartUpload. Browser clients need the bucket CORS configuration
+// to expose the ETag header to read the part ETag.
+func (s *Storage) GetPresignedUploadPartURL(ctx context.Context, bucket, key, uploadID string, partNumber int32, expiry time.Duration) (string, error) {
+	ctx, span := tracer.Start(ctx, "S3.GetPresignedUploadPartURL", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	if bucket == "" {
+		bucket = s.cfg.DefaultBucket
+	}
+
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	// Set default expiry
+	if expiry == 0 {
+		expiry = 15 * time.Minute
+	}
+
+	span.SetAttributes(
+		attribute.String("bucket", bucket),
+		attribute.String("key", key),
+		attribute.String("upload_id", uploadID),
+		attribute.Int("part_number", int(partNumber)),
+		attribute.Int("expiry_seconds", int(expiry.Seconds())),
+	)
+
+	if uploadID == "" {
+		err = errors.New("upload ID is required")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+	if partNumber < 1 || partNumber > maxPartNumber {
+		err = errors.Errorf("part number %d out of range [1, %d]", partNumber, maxPartNumber)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	client, err := s.getClient()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("partNumber", strconv.Itoa(int(partNumber)))
+	params.Set("uploadId", uploadID)
+	presignedURL, err := client.Presign(ctx, http.MethodPut, bucket, key, expiry, params)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errors.Wrapf(err, "failed to generate presigned URL for part %d of %s/%s", partNumber, bucket, key)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	s.log(ctx).Debug("Presigned part URL generated", "bucket", bucket, "key", key, "upload_id", uploadID, "part_number", partNumber, "expiry", expiry)
 
 	return presignedURL.String(), nil
 }
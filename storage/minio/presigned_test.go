@@ -3,11 +3,13 @@ package minio
 import (
 	"context"
 	"log/slog"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/pure-golang/adapters/storage"
 )
@@ -519,3 +521,39 @@ func TestPresignedURL_StringCases(t *testing.T) {
 		})
 	}
 }
+
+// TestGetPresignedUploadPartURL tests that the part URL is signed for the upload and part number.
+func TestGetPresignedUploadPartURL(t *testing.T) {
+	t.Parallel()
+	stor, _ := newFakeS3Storage(t)
+
+	rawURL, err := stor.GetPresignedUploadPartURL(context.Background(), "bucket", "video.mp4", "upload-1", 3, time.Hour)
+	require.NoError(t, err)
+
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/bucket/video.mp4", u.Path)
+	assert.Equal(t, "3", u.Query().Get("partNumber"))
+	assert.Equal(t, "upload-1", u.Query().Get("uploadId"))
+	assert.Equal(t, "3600", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+}
+
+// TestGetPresignedUploadPartURL_Invalid tests validation of the upload ID and part number.
+func TestGetPresignedUploadPartURL_Invalid(t *testing.T) {
+	t.Parallel()
+	stor, _ := newFakeS3Storage(t)
+	ctx := context.Background()
+
+	_, err := stor.GetPresignedUploadPartURL(ctx, "bucket", "key", "", 1, time.Minute)
+	assert.ErrorContains(t, err, "upload ID is required")
+
+	for _, partNumber := range []int32{0, -1, 10001} {
+		_, err = stor.GetPresignedUploadPartURL(ctx, "bucket", "key", "upload-1", partNumber, time.Minute)
+		assert.ErrorContains(t, err, "out of range")
+	}
+
+	nilClient := NewStorage(&Client{logger: slog.Default()}, nil)
+	_, err = nilClient.GetPresignedUploadPartURL(ctx, "bucket", "key", "upload-1", 1, time.Minute)
+	assert.ErrorContains(t, err, "not initialized")
+}
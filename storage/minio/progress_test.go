@@ -0,0 +1,72 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// TestStorage_Put_Progress tests that Put reports bytes read from the reader
+func TestStorage_Put_Progress(t *testing.T) {
+	t.Parallel()
+	stor, _ := newFakeS3Storage(t)
+
+	var transferred, total atomic.Int64
+	err := stor.Put(context.Background(), "bucket", "key", strings.NewReader("hello"), &storage.PutOptions{
+		Progress: func(n, size int64) {
+			transferred.Store(n)
+			total.Store(size)
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), transferred.Load())
+	assert.Equal(t, int64(5), total.Load())
+}
+
+// TestStorage_GetWithOptions_Progress tests that the returned body reports bytes read
+func TestStorage_GetWithOptions_Progress(t *testing.T) {
+	t.Parallel()
+	stor, _ := newFakeS3Storage(t)
+
+	var transferred, total int64
+	reader, _, err := stor.GetWithOptions(context.Background(), "bucket", "key", &storage.GetOptions{
+		Progress: func(n, size int64) {
+			transferred, total = n, size
+		},
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	_, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), transferred)
+	assert.Equal(t, int64(5), total)
+}
+
+// TestReaderSize tests size detection for readers of known size
+func TestReaderSize(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.WriteFile(path, []byte("0123456789"), 0o600))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Seek(4, io.SeekStart)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), readerSize(strings.NewReader("abc")))
+	assert.Equal(t, int64(2), readerSize(bytes.NewReader([]byte("ab"))))
+	assert.Equal(t, int64(6), readerSize(f))
+	assert.Equal(t, int64(-1), readerSize(io.MultiReader(strings.NewReader("abc"))))
+}
@@ -0,0 +1,146 @@
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var _ storage.Querier = (*Storage)(nil)
+
+// S3 error codes of backends without S3 Select.
+const (
+	notImplemented   = "NotImplemented"
+	methodNotAllowed = "MethodNotAllowed"
+)
+
+// Query runs an S3 Select SQL expression against the object and streams the matching records.
+func (s *Storage) Query(ctx context.Context, bucket, key, expression string, input storage.QueryInput, output storage.QueryOutput) (io.ReadCloser, error) {
+	ctx, span := tracer.Start(ctx, "S3.Query", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	if err := storage.ValidateQuery(expression, input, output); err != nil {
+		err = errors.Wrap(err, "invalid query")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if bucket == "" {
+		bucket = s.cfg.DefaultBucket
+	}
+
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("bucket", bucket),
+		attribute.String("key", key),
+		attribute.String("input_format", string(input.Format)),
+		attribute.String("output_format", string(output.Format)),
+	)
+
+	client, err := s.getClient()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	results, err := client.SelectObjectContent(ctx, bucket, key, selectObjectOptions(expression, input, output))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, queryError(err, bucket, key)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return results, nil
+}
+
+// selectObjectOptions converts the query to S3 Select request options.
+func selectObjectOptions(expression string, input storage.QueryInput, output storage.QueryOutput) minio.SelectObjectOptions {
+	opts := minio.SelectObjectOptions{
+		Expression:     expression,
+		ExpressionType: minio.QueryExpressionTypeSQL,
+	}
+
+	switch input.Format {
+	case storage.QueryFormatCSV:
+		csv := &minio.CSVInputOptions{}
+		header := input.CSVHeader
+		if header == "" {
+			header = storage.CSVHeaderNone
+		}
+		csv.SetFileHeaderInfo(minio.CSVFileHeaderInfo(header))
+		if input.FieldDelimiter != "" {
+			csv.SetFieldDelimiter(input.FieldDelimiter)
+		}
+		if input.RecordDelimiter != "" {
+			csv.SetRecordDelimiter(input.RecordDelimiter)
+		}
+		opts.InputSerialization.CSV = csv
+	case storage.QueryFormatJSON:
+		json := &minio.JSONInputOptions{}
+		if input.JSONLines {
+			json.SetType(minio.JSONLinesType)
+		} else {
+			json.SetType(minio.JSONDocumentType)
+		}
+		opts.InputSerialization.JSON = json
+	case storage.QueryFormatParquet:
+		opts.InputSerialization.Parquet = &minio.ParquetInputOptions{}
+	}
+	if input.Format != storage.QueryFormatParquet {
+		opts.InputSerialization.CompressionType = minio.SelectCompressionNONE
+		if input.Compression != storage.QueryCompressionNone {
+			opts.InputSerialization.CompressionType = minio.SelectCompressionType(input.Compression)
+		}
+	}
+
+	switch output.Format {
+	case storage.QueryFormatCSV:
+		csv := &minio.CSVOutputOptions{}
+		if output.FieldDelimiter != "" {
+			csv.SetFieldDelimiter(output.FieldDelimiter)
+		}
+		if output.RecordDelimiter != "" {
+			csv.SetRecordDelimiter(output.RecordDelimiter)
+		}
+		opts.OutputSerialization.CSV = csv
+	case storage.QueryFormatJSON:
+		json := &minio.JSONOutputOptions{}
+		if output.RecordDelimiter != "" {
+			json.SetRecordDelimiter(output.RecordDelimiter)
+		}
+		opts.OutputSerialization.JSON = json
+	}
+	return opts
+}
+
+// queryError converts a Query error; backends without S3 Select get CodeQueryNotSupported.
+func queryError(err error, bucket, key string) error {
+	resp := minio.ToErrorResponse(err)
+	if resp.Code == notImplemented || resp.Code == methodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		return &storage.StorageError{
+			Code:    storage.CodeQueryNotSupported,
+			Message: "S3 Select not supported by backend",
+			Err:     err,
+			Bucket:  bucket,
+			Key:     key,
+		}
+	}
+	return toStorageError(err, bucket, key)
+}
@@ -0,0 +1,103 @@
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// TestSelectObjectOptions tests conversion of query formats to the S3 Select request.
+func TestSelectObjectOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		input  storage.QueryInput
+		output storage.QueryOutput
+		want   []string
+	}{
+		{
+			name:   "csv to json",
+			input:  storage.QueryInput{Format: storage.QueryFormatCSV, CSVHeader: storage.CSVHeaderUse, FieldDelimiter: ";", Compression: storage.QueryCompressionGZIP},
+			output: storage.QueryOutput{Format: storage.QueryFormatJSON},
+			want: []string{
+				"<CompressionType>GZIP</CompressionType>",
+				"<CSV><FileHeaderInfo>USE</FileHeaderInfo><FieldDelimiter>;</FieldDelimiter></CSV>",
+				"<OutputSerialization><JSON></JSON></OutputSerialization>",
+			},
+		},
+		{
+			name:   "json lines to csv",
+			input:  storage.QueryInput{Format: storage.QueryFormatJSON, JSONLines: true},
+			output: storage.QueryOutput{Format: storage.QueryFormatCSV, RecordDelimiter: "\r\n"},
+			want: []string{
+				"<CompressionType>NONE</CompressionType>",
+				"<JSON><Type>LINES</Type></JSON>",
+				"<OutputSerialization><CSV><RecordDelimiter>&#xD;&#xA;</RecordDelimiter></CSV></OutputSerialization>",
+			},
+		},
+		{
+			name:   "parquet",
+			input:  storage.QueryInput{Format: storage.QueryFormatParquet},
+			output: storage.QueryOutput{Format: storage.QueryFormatJSON},
+			want:   []string{"<InputSerialization><Parquet></Parquet></InputSerialization>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			body, err := xml.Marshal(selectObjectOptions("SELECT * FROM S3Object s", tt.input, tt.output))
+			require.NoError(t, err)
+			assert.Contains(t, string(body), "<Expression>SELECT * FROM S3Object s</Expression><ExpressionType>SQL</ExpressionType>")
+			for _, want := range tt.want {
+				assert.Contains(t, string(body), want)
+			}
+		})
+	}
+}
+
+// TestStorage_Query_NotSupported tests the error of backends answering S3 Select with 501 Not Implemented.
+func TestStorage_Query_NotSupported(t *testing.T) {
+	t.Parallel()
+	stor := newFakeObjectLockStorage(t, &fakeObjectLock{})
+	csv := storage.QueryInput{Format: storage.QueryFormatCSV}
+	json := storage.QueryOutput{Format: storage.QueryFormatJSON}
+
+	_, err := stor.Query(context.Background(), "", "events.csv", "SELECT * FROM S3Object", csv, json)
+	assert.True(t, storage.IsQueryNotSupported(err), "got %v", err)
+}
+
+// TestStorage_Query_Invalid tests that an invalid query is rejected before any request.
+func TestStorage_Query_Invalid(t *testing.T) {
+	t.Parallel()
+	stor := NewStorage(&Client{logger: slog.Default()}, nil)
+
+	_, err := stor.Query(context.Background(), "bucket", "events.csv", "", storage.QueryInput{Format: storage.QueryFormatCSV},
+		storage.QueryOutput{Format: storage.QueryFormatJSON})
+	assert.EqualError(t, err, "invalid query: query expression is empty")
+
+	body, err := stor.Query(context.Background(), "bucket", "events.csv", "SELECT 1", storage.QueryInput{Format: storage.QueryFormatCSV},
+		storage.QueryOutput{Format: storage.QueryFormatJSON})
+	assert.Nil(t, body)
+	assert.ErrorContains(t, err, "minio client is not initialized")
+}
+
+// TestQueryError tests conversion of S3 Select errors.
+func TestQueryError(t *testing.T) {
+	t.Parallel()
+	err := queryError(minio.ErrorResponse{Code: "NotImplemented", StatusCode: http.StatusNotImplemented}, "bucket", "key")
+	assert.True(t, storage.IsQueryNotSupported(err))
+
+	err = queryError(minio.ErrorResponse{Code: "MethodNotAllowed", StatusCode: http.StatusMethodNotAllowed}, "bucket", "key")
+	assert.True(t, storage.IsQueryNotSupported(err))
+
+	err = queryError(minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}, "bucket", "key")
+	assert.True(t, storage.IsNotFound(err))
+}
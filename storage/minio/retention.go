@@ -0,0 +1,193 @@
+package minio
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// S3 error codes of object lock operations.
+const (
+	noSuchObjectLockConfiguration = "NoSuchObjectLockConfiguration"
+	objectLockConfigNotFound      = "ObjectLockConfigurationNotFoundError"
+	invalidRequest                = "InvalidRequest"
+)
+
+// PutObjectRetention sets the retention of the current object version.
+func (s *Storage) PutObjectRetention(ctx context.Context, bucket, key string, retention *storage.Retention, opts *storage.RetentionOptions) error {
+	ctx, span := tracer.Start(ctx, "S3.PutObjectRetention", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	if retention != nil {
+		if err := retention.Validate(); err != nil {
+			err = errors.Wrap(err, "invalid retention")
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+
+	bucket, key, client, err := s.objectLockTarget(span, bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if opts == nil {
+		opts = &storage.RetentionOptions{}
+	}
+	minioOpts := minio.PutObjectRetentionOptions{GovernanceBypass: opts.BypassGovernance}
+	if retention != nil {
+		mode := minio.RetentionMode(retention.Mode)
+		minioOpts.Mode = &mode
+		minioOpts.RetainUntilDate = &retention.RetainUntil
+		span.SetAttributes(
+			attribute.String("retention_mode", string(retention.Mode)),
+			attribute.String("retain_until", retention.RetainUntil.UTC().Format(time.RFC3339)),
+		)
+	}
+	span.SetAttributes(attribute.Bool("bypass_governance", opts.BypassGovernance))
+
+	if err := client.PutObjectRetention(ctx, bucket, key, minioOpts); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return objectLockError(err, bucket, key)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	s.log(ctx).Info("Object retention set", "bucket", bucket, "key", key, "retention", retention != nil, "bypass_governance", opts.BypassGovernance)
+	return nil
+}
+
+// GetObjectRetention returns the retention of the current object version, or nil if it has none.
+func (s *Storage) GetObjectRetention(ctx context.Context, bucket, key string) (*storage.Retention, error) {
+	ctx, span := tracer.Start(ctx, "S3.GetObjectRetention", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	bucket, key, client, err := s.objectLockTarget(span, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, retainUntil, err := client.GetObjectRetention(ctx, bucket, key, "")
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == noSuchObjectLockConfiguration {
+			span.SetStatus(codes.Ok, "")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, objectLockError(err, bucket, key)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	if mode == nil || *mode == "" || retainUntil == nil {
+		return nil, nil
+	}
+	return &storage.Retention{Mode: storage.RetentionMode(*mode), RetainUntil: *retainUntil}, nil
+}
+
+// PutObjectLegalHold places or removes a legal hold on the current object version.
+func (s *Storage) PutObjectLegalHold(ctx context.Context, bucket, key string, hold bool) error {
+	ctx, span := tracer.Start(ctx, "S3.PutObjectLegalHold", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	bucket, key, client, err := s.objectLockTarget(span, bucket, key)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Bool("legal_hold", hold))
+
+	status := minio.LegalHoldDisabled
+	if hold {
+		status = minio.LegalHoldEnabled
+	}
+	if err := client.PutObjectLegalHold(ctx, bucket, key, minio.PutObjectLegalHoldOptions{Status: &status}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return objectLockError(err, bucket, key)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	s.log(ctx).Info("Object legal hold set", "bucket", bucket, "key", key, "legal_hold", hold)
+	return nil
+}
+
+// GetObjectLegalHold reports whether the current object version is under a legal hold.
+func (s *Storage) GetObjectLegalHold(ctx context.Context, bucket, key string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "S3.GetObjectLegalHold", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	bucket, key, client, err := s.objectLockTarget(span, bucket, key)
+	if err != nil {
+		return false, err
+	}
+
+	status, err := client.GetObjectLegalHold(ctx, bucket, key, minio.GetObjectLegalHoldOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == noSuchObjectLockConfiguration {
+			span.SetStatus(codes.Ok, "")
+			return false, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return false, objectLockError(err, bucket, key)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return status != nil && *status == minio.LegalHoldEnabled, nil
+}
+
+// objectLockTarget resolves the bucket, checks the key and returns the client for an object lock operation.
+func (s *Storage) objectLockTarget(span trace.Span, bucket, key string) (string, string, *minio.Client, error) {
+	if bucket == "" {
+		bucket = s.cfg.DefaultBucket
+	}
+
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", "", nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("bucket", bucket),
+		attribute.String("key", key),
+	)
+
+	client, err := s.getClient()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", "", nil, err
+	}
+	return bucket, key, client, nil
+}
+
+// objectLockError converts an object lock error; buckets without object lock get CodeObjectLockNotEnabled.
+func objectLockError(err error, bucket, key string) error {
+	resp := minio.ToErrorResponse(err)
+	if resp.Code == objectLockConfigNotFound || (resp.Code == invalidRequest && isObjectLockDisabledMessage(resp.Message)) {
+		return &storage.StorageError{
+			Code:    storage.CodeObjectLockNotEnabled,
+			Message: "object lock not enabled for bucket",
+			Err:     err,
+			Bucket:  bucket,
+			Key:     key,
+		}
+	}
+	return toStorageError(err, bucket, key)
+}
+
+// isObjectLockDisabledMessage reports the InvalidRequest message S3 returns for buckets without object lock.
+func isObjectLockDisabledMessage(message string) bool {
+	return strings.Contains(strings.ToLower(message), "object lock")
+}
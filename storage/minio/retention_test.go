@@ -0,0 +1,170 @@
+package minio
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// fakeObjectLock serves object retention and legal hold of a single object.
+// With lockDisabled it answers like a bucket created without object lock.
+type fakeObjectLock struct {
+	mu           sync.Mutex
+	lockDisabled bool
+	retention    string // Retention XML, empty if not set
+	legalHold    string // LegalHold XML, empty if not set
+	bypass       string // last x-amz-bypass-governance-retention header
+}
+
+func (f *fakeObjectLock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	query := r.URL.Query()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.lockDisabled {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<Error><Code>InvalidRequest</Code><Message>Bucket is missing Object Lock Configuration</Message></Error>`)
+		return
+	}
+
+	var stored *string
+	var missing string
+	switch {
+	case query.Has("retention"):
+		stored, missing = &f.retention, "No retention configuration found"
+	case query.Has("legal-hold"):
+		stored, missing = &f.legalHold, "No legal hold configuration found"
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		*stored = string(body)
+		f.bypass = r.Header.Get("X-Amz-Bypass-Governance-Retention")
+		return
+	}
+	if *stored == "" {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `<Error><Code>NoSuchObjectLockConfiguration</Code><Message>`+missing+`</Message></Error>`)
+		return
+	}
+	_, _ = io.WriteString(w, *stored)
+}
+
+// newFakeObjectLockStorage creates a Storage backed by fakeObjectLock.
+func newFakeObjectLockStorage(t *testing.T, fake *fakeObjectLock) *Storage {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	mc, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	client := &Client{client: mc, cfg: Config{DefaultBucket: "bucket"}, logger: slog.Default()}
+	return NewStorage(client, nil)
+}
+
+// TestStorage_ObjectRetention tests setting and reading object retention.
+func TestStorage_ObjectRetention(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	fake := &fakeObjectLock{}
+	stor := newFakeObjectLockStorage(t, fake)
+
+	retention, err := stor.GetObjectRetention(ctx, "", "report.pdf")
+	require.NoError(t, err)
+	assert.Nil(t, retention, "object without retention")
+
+	retainUntil := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	err = stor.PutObjectRetention(ctx, "", "report.pdf", &storage.Retention{
+		Mode:        storage.RetentionCompliance,
+		RetainUntil: retainUntil,
+	}, &storage.RetentionOptions{BypassGovernance: true})
+	require.NoError(t, err)
+	assert.Equal(t, "true", fake.bypass)
+
+	retention, err = stor.GetObjectRetention(ctx, "", "report.pdf")
+	require.NoError(t, err)
+	require.NotNil(t, retention)
+	assert.Equal(t, storage.RetentionCompliance, retention.Mode)
+	assert.True(t, retainUntil.Equal(retention.RetainUntil))
+}
+
+// TestStorage_ObjectLegalHold tests placing and removing a legal hold.
+func TestStorage_ObjectLegalHold(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	stor := newFakeObjectLockStorage(t, &fakeObjectLock{})
+
+	hold, err := stor.GetObjectLegalHold(ctx, "", "report.pdf")
+	require.NoError(t, err)
+	assert.False(t, hold, "object without legal hold")
+
+	require.NoError(t, stor.PutObjectLegalHold(ctx, "", "report.pdf", true))
+	hold, err = stor.GetObjectLegalHold(ctx, "", "report.pdf")
+	require.NoError(t, err)
+	assert.True(t, hold)
+
+	require.NoError(t, stor.PutObjectLegalHold(ctx, "", "report.pdf", false))
+	hold, err = stor.GetObjectLegalHold(ctx, "", "report.pdf")
+	require.NoError(t, err)
+	assert.False(t, hold)
+}
+
+// TestStorage_ObjectLock_NotEnabled tests errors for buckets without object lock.
+func TestStorage_ObjectLock_NotEnabled(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	stor := newFakeObjectLockStorage(t, &fakeObjectLock{lockDisabled: true})
+
+	err := stor.PutObjectLegalHold(ctx, "", "report.pdf", true)
+	assert.True(t, storage.IsObjectLockNotEnabled(err), "got %v", err)
+
+	_, err = stor.GetObjectRetention(ctx, "", "report.pdf")
+	assert.True(t, storage.IsObjectLockNotEnabled(err), "got %v", err)
+}
+
+// TestStorage_PutObjectRetention_Invalid tests that invalid retention is rejected before any request.
+func TestStorage_PutObjectRetention_Invalid(t *testing.T) {
+	t.Parallel()
+	stor := NewStorage(&Client{logger: slog.Default()}, nil)
+
+	err := stor.PutObjectRetention(context.Background(), "bucket", "key", &storage.Retention{
+		Mode:        storage.RetentionGovernance,
+		RetainUntil: time.Now().Add(-time.Hour),
+	}, nil)
+	assert.ErrorContains(t, err, "invalid retention: retain until date must be in the future")
+
+	err = stor.PutObjectLegalHold(context.Background(), "bucket", "key", true)
+	assert.ErrorContains(t, err, "minio client is not initialized")
+}
+
+// TestObjectLockError tests conversion of object lock errors.
+func TestObjectLockError(t *testing.T) {
+	t.Parallel()
+	err := objectLockError(minio.ErrorResponse{Code: "ObjectLockConfigurationNotFoundError", StatusCode: http.StatusNotFound}, "bucket", "key")
+	assert.True(t, storage.IsObjectLockNotEnabled(err))
+	assert.False(t, storage.IsNotFound(err))
+
+	err = objectLockError(minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}, "bucket", "key")
+	assert.True(t, storage.IsNotFound(err))
+}
@@ -0,0 +1,105 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// fakeRangeServer serves a single object with ranged and conditional GETs.
+type fakeRangeServer struct {
+	mu     sync.Mutex
+	data   []byte
+	etag   string
+	ranges []string
+}
+
+func (f *fakeRangeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasSuffix(r.URL.Path, "/bucket/video.mp4") {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+		return
+	}
+	if r.Method == http.MethodGet {
+		f.ranges = append(f.ranges, r.Header.Get("Range"))
+	}
+	w.Header().Set("ETag", f.etag)
+	w.Header().Set("Content-Type", "video/mp4")
+	http.ServeContent(w, r, "", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), bytes.NewReader(f.data))
+}
+
+func newFakeRangeStorage(t *testing.T, fake *fakeRangeServer) *Storage {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	mc, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	return NewStorage(&Client{client: mc, cfg: Config{DefaultBucket: "bucket"}, logger: slog.Default()}, nil)
+}
+
+// TestStorage_OpenSeekable tests ranged reads after Seek and serving range requests with http.ServeContent.
+func TestStorage_OpenSeekable(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	fake := &fakeRangeServer{data: []byte("0123456789abcdefghij"), etag: `"v1"`}
+	stor := newFakeRangeStorage(t, fake)
+
+	r, err := stor.OpenSeekable(ctx, "", "video.mp4")
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, int64(20), r.Info().Size)
+	assert.Equal(t, "video/mp4", r.Info().ContentType)
+
+	_, err = r.Seek(10, io.SeekStart)
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", string(buf))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/video.mp4", nil)
+	req.Header.Set("Range", "bytes=15-")
+	http.ServeContent(rec, req, "video.mp4", r.Info().LastModified, r)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "fghij", rec.Body.String())
+
+	fake.mu.Lock()
+	assert.Equal(t, []string{"bytes=10-", "bytes=15-"}, fake.ranges, "seeks must not download skipped bytes")
+	fake.etag = `"v2"`
+	fake.mu.Unlock()
+
+	_, err = r.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	assert.Error(t, err, "reads after overwrite must fail")
+}
+
+// TestStorage_OpenSeekable_NotFound tests opening a missing object.
+func TestStorage_OpenSeekable_NotFound(t *testing.T) {
+	t.Parallel()
+	stor := newFakeRangeStorage(t, &fakeRangeServer{})
+
+	_, err := stor.OpenSeekable(context.Background(), "", "missing.mp4")
+	assert.True(t, storage.IsNotFound(err), "got %v", err)
+}
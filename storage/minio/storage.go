@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"os"
 	"strings"
 
 	"github.com/minio/minio-go/v7"
@@ -13,24 +14,39 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/pure-golang/adapters/observability/correlate"
 	"github.com/pure-golang/adapters/storage"
 )
 
-var _ storage.Storage = (*Storage)(nil)
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+	_ storage.PartPresigner = (*Storage)(nil)
+	_ storage.ObjectLocker  = (*Storage)(nil)
+)
 
 var tracer = otel.Tracer("github.com/pure-golang/adapters/storage/s3")
 
 // Storage implements storage.Storage interface for S3-compatible storage.
 // Supports MinIO, Yandex Cloud Storage, AWS S3, and other S3-compatible providers.
 type Storage struct {
-	client *Client
-	cfg    Config
-	logger *slog.Logger
+	client        *Client
+	cfg           Config
+	logger        *slog.Logger
+	keyValidator  storage.KeyValidator
+	keyNormalizer storage.KeyNormalizer
 }
 
 // StorageOptions contains options for Storage creation.
 type StorageOptions struct {
 	Logger *slog.Logger
+
+	// KeyNormalizer rewrites object keys before every operation, e.g. storage.NormalizeKeyNFC.
+	// List prefixes are normalized too.
+	KeyNormalizer storage.KeyNormalizer
+	// KeyValidator rejects object keys with storage.CodeInvalidKey before any request
+	// is sent, e.g. storage.KeyPolicy{}.Validate. Nil passes keys to the backend as is.
+	KeyValidator storage.KeyValidator
 }
 
 // NewStorage creates a new S3 Storage instance.
@@ -43,12 +59,20 @@ func NewStorage(client *Client, opts *StorageOptions) *Storage {
 	}
 
 	return &Storage{
-		client: client,
-		cfg:    client.cfg,
-		logger: opts.Logger.WithGroup("storage").With("backend", "s3"),
+		client:        client,
+		cfg:           client.cfg,
+		logger:        opts.Logger,
+		keyValidator:  opts.KeyValidator,
+		keyNormalizer: opts.KeyNormalizer,
 	}
 }
 
+// log returns the storage logger with trace_id, span_id and request_id from ctx.
+// Correlation attributes are added before the group to stay at the top level of the record.
+func (s *Storage) log(ctx context.Context) *slog.Logger {
+	return correlate.Logger(ctx, s.logger).WithGroup("storage").With("backend", "s3")
+}
+
 // NewDefault creates a Storage with a new client.
 func NewDefault(cfg Config) (*Storage, error) {
 	client, err := NewDefaultClient(cfg)
@@ -69,6 +93,11 @@ func (s *Storage) getClient() (*minio.Client, error) {
 	return s.client.client, nil
 }
 
+// checkKey normalizes and validates an object key according to StorageOptions.
+func (s *Storage) checkKey(bucket, key string) (string, error) {
+	return storage.CheckKey(bucket, key, s.keyNormalizer, s.keyValidator)
+}
+
 // Put stores an object in S3-compatible storage.
 func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
 	ctx, span := tracer.Start(ctx, "S3.Put", trace.WithSpanKind(trace.SpanKindClient))
@@ -83,6 +112,13 @@ func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader,
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -90,9 +126,11 @@ func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader,
 	)
 
 	// Convert storage.PutOptions to minio.PutObjectOptions
-	minioOpts := minio.PutObjectOptions{
-		ContentType:  opts.ContentType,
-		UserMetadata: opts.Metadata,
+	minioOpts, extraHeaders, err := putObjectOptions(opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
 
 	// Get the minio client
@@ -103,8 +141,13 @@ func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader,
 		return err
 	}
 
+	body := reader
+	if opts.Progress != nil {
+		body = storage.NewProgressReader(reader, readerSize(reader), opts.Progress)
+	}
+
 	// Upload the object
-	info, err := client.PutObject(ctx, bucket, key, reader, -1, minioOpts)
+	info, err := client.PutObject(withExtraHeaders(ctx, extraHeaders), bucket, key, body, -1, minioOpts)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -117,19 +160,35 @@ func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader,
 	)
 	span.SetStatus(codes.Ok, "")
 
-	s.logger.Debug("Object stored", "bucket", bucket, "key", key, "size", info.Size)
+	s.log(ctx).Debug("Object stored", "bucket", bucket, "key", key, "size", info.Size)
 	return nil
 }
 
 // Get retrieves an object from S3-compatible storage.
 func (s *Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	return s.GetWithOptions(ctx, bucket, key, nil)
+}
+
+// GetWithOptions retrieves an object from S3-compatible storage with extra request headers.
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
 	ctx, span := tracer.Start(ctx, "S3.Get", trace.WithSpanKind(trace.SpanKindClient))
 	defer span.End()
 
+	if opts == nil {
+		opts = &storage.GetOptions{}
+	}
+
 	if bucket == "" {
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -144,7 +203,7 @@ func (s *Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *
 	}
 
 	// Get the object
-	obj, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	obj, err := client.GetObject(ctx, bucket, key, getObjectOptions(opts))
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -156,21 +215,14 @@ func (s *Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *
 	if err != nil {
 		closeErr := obj.Close()
 		if closeErr != nil {
-			s.logger.With("error", closeErr).Error("failed to close object after stat error")
+			s.log(ctx).With("error", closeErr).Error("failed to close object after stat error")
 		}
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, nil, toStorageError(err, bucket, key)
 	}
 
-	info := &storage.ObjectInfo{
-		Key:          key,
-		Size:         stat.Size,
-		LastModified: stat.LastModified,
-		ETag:         stat.ETag,
-		ContentType:  stat.ContentType,
-		Metadata:     stat.UserMetadata,
-	}
+	info := toObjectInfo(key, stat)
 
 	span.SetAttributes(
 		attribute.Int64("size", stat.Size),
@@ -178,7 +230,10 @@ func (s *Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *
 	)
 	span.SetStatus(codes.Ok, "")
 
-	return obj, info, nil
+	if opts.Progress != nil {
+		return storage.NewProgressReadCloser(obj, stat.Size, opts.Progress), &info, nil
+	}
+	return obj, &info, nil
 }
 
 // Delete removes an object from S3-compatible storage.
@@ -190,6 +245,13 @@ func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -211,7 +273,7 @@ func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
 	}
 
 	span.SetStatus(codes.Ok, "")
-	s.logger.Debug("Object deleted", "bucket", bucket, "key", key)
+	s.log(ctx).Debug("Object deleted", "bucket", bucket, "key", key)
 	return nil
 }
 
@@ -224,6 +286,13 @@ func (s *Storage) Exists(ctx context.Context, bucket, key string) (bool, error)
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return false, err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -252,6 +321,53 @@ func (s *Storage) Exists(ctx context.Context, bucket, key string) (bool, error)
 	return true, nil
 }
 
+// Stat retrieves object metadata from S3-compatible storage without the body.
+func (s *Storage) Stat(ctx context.Context, bucket, key string) (*storage.ObjectInfo, error) {
+	ctx, span := tracer.Start(ctx, "S3.Stat", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	if bucket == "" {
+		bucket = s.cfg.DefaultBucket
+	}
+
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("bucket", bucket),
+		attribute.String("key", key),
+	)
+
+	// Get the minio client
+	client, err := s.getClient()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// Checksum mode makes S3 return the x-amz-checksum-* headers
+	stat, err := client.StatObject(ctx, bucket, key, minio.StatObjectOptions{Checksum: true})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, toStorageError(err, bucket, key)
+	}
+
+	info := toObjectInfo(key, stat)
+
+	span.SetAttributes(
+		attribute.Int64("size", stat.Size),
+		attribute.String("etag", stat.ETag),
+	)
+	span.SetStatus(codes.Ok, "")
+	return &info, nil
+}
+
 // List lists objects in the specified bucket.
 func (s *Storage) List(ctx context.Context, bucket string, opts *storage.ListOptions) (*storage.ListResult, error) {
 	ctx, span := tracer.Start(ctx, "S3.List", trace.WithSpanKind(trace.SpanKindClient))
@@ -269,11 +385,17 @@ func (s *Storage) List(ctx context.Context, bucket string, opts *storage.ListOpt
 		attribute.String("bucket", bucket),
 		attribute.String("prefix", opts.Prefix),
 		attribute.Bool("recursive", opts.Recursive),
+		attribute.Bool("filtered", opts.HasFilters()),
 	)
 
+	prefix := opts.Prefix
+	if s.keyNormalizer != nil && prefix != "" {
+		prefix = s.keyNormalizer(prefix)
+	}
+
 	// Convert storage.ListOptions to minio.ListObjectsOptions
 	minioOpts := minio.ListObjectsOptions{
-		Prefix:       opts.Prefix,
+		Prefix:       prefix,
 		Recursive:    opts.Recursive,
 		MaxKeys:      opts.MaxKeys,
 		WithMetadata: true,
@@ -287,8 +409,10 @@ func (s *Storage) List(ctx context.Context, bucket string, opts *storage.ListOpt
 		return nil, err
 	}
 
-	// List objects
-	objectCh := client.ListObjects(ctx, bucket, minioOpts)
+	// List objects; cancel stops the listing goroutine if we return early
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	objectCh := client.ListObjects(listCtx, bucket, minioOpts)
 
 	var objects []storage.ObjectInfo
 
@@ -304,16 +428,24 @@ func (s *Storage) List(ctx context.Context, bucket string, opts *storage.ListOpt
 			continue
 		}
 
-		objects = append(objects, storage.ObjectInfo{
-			Key:          object.Key,
-			Size:         object.Size,
-			LastModified: object.LastModified,
-			ETag:         object.ETag,
-			ContentType:  object.ContentType,
-			Metadata:     object.UserMetadata,
-		})
+		info := toObjectInfo(object.Key, object)
+		if opts.StatMetadata && missingMetadata(info, opts.Metadata) {
+			stat, err := client.StatObject(ctx, bucket, object.Key, minio.StatObjectOptions{})
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return nil, errors.Wrapf(err, "failed to stat object %s", object.Key)
+			}
+			info = toObjectInfo(object.Key, stat)
+		}
+
+		if opts.Match(info) {
+			objects = append(objects, info)
+		}
 	}
 
+	storage.SortObjects(objects, opts.SortBy, opts.SortDesc)
+
 	result := &storage.ListResult{
 		Objects:     objects,
 		IsTruncated: false, // minio-go v7 doesn't provide this info directly
@@ -336,6 +468,13 @@ func (s *Storage) GetFileHeader(ctx context.Context, bucket, key string) ([]byte
 		bucket = s.cfg.DefaultBucket
 	}
 
+	key, err := s.checkKey(bucket, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	span.SetAttributes(
 		attribute.String("bucket", bucket),
 		attribute.String("key", key),
@@ -382,6 +521,26 @@ func (s *Storage) GetFileHeader(ctx context.Context, bucket, key string) ([]byte
 	return head[:n], nil
 }
 
+// readerSize returns the number of bytes left in readers of known size or -1.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }: // bytes.Reader, bytes.Buffer, strings.Reader
+		return int64(v.Len())
+	case *os.File:
+		stat, err := v.Stat()
+		if err != nil || !stat.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return stat.Size() - offset
+	default:
+		return -1
+	}
+}
+
 // Close closes the storage connection.
 func (s *Storage) Close() error {
 	return s.client.Close()
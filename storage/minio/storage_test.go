@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pure-golang/adapters/observability/correlate"
 	"github.com/pure-golang/adapters/storage"
 )
 
@@ -92,6 +93,16 @@ func TestStorage_NewDefault(t *testing.T) {
 	})
 }
 
+// TestStorage_Log tests that storage records carry the request ID outside the storage group.
+func TestStorage_Log(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	stor := NewStorage(&Client{logger: slog.Default()}, &StorageOptions{Logger: slog.New(slog.NewJSONHandler(&buf, nil))})
+
+	stor.log(correlate.WithRequestID(context.Background(), "req-1")).Info("object stored", "key", "a.txt")
+	assert.Contains(t, buf.String(), `"request_id":"req-1","storage":{"backend":"s3","key":"a.txt"}`)
+}
+
 // TestStorage_Put_DefaultBucket tests Put with default bucket.
 func TestStorage_Put_DefaultBucket(t *testing.T) {
 	t.Parallel()
@@ -293,6 +304,21 @@ func TestStorage_Exists_KeyVariations(t *testing.T) {
 	}
 }
 
+// TestStorage_Stat_DefaultBucket tests Stat with default bucket.
+func TestStorage_Stat_DefaultBucket(t *testing.T) {
+	t.Parallel()
+	client := &Client{
+		cfg:    Config{DefaultBucket: "default-bucket"},
+		logger: slog.Default(),
+	}
+	stor := NewStorage(client, nil)
+
+	info, err := stor.Stat(context.Background(), "", "key.txt")
+	assert.Error(t, err)
+	assert.Nil(t, info)
+	assert.Contains(t, err.Error(), "not initialized")
+}
+
 // TestStorage_List_DefaultBucket tests List with default bucket.
 func TestStorage_List_DefaultBucket(t *testing.T) {
 	t.Parallel()
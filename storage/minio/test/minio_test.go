@@ -16,8 +16,17 @@ import (
 
 	"github.com/pure-golang/adapters/storage"
 	"github.com/pure-golang/adapters/storage/minio"
+	"github.com/pure-golang/adapters/storage/storagetest"
 )
 
+// TestStorageContract tests that the minio adapter conforms to the storage.Storage contract.
+func TestStorageContract(t *testing.T) {
+	m := storagetest.StartMinIO(t)
+	stor, bucket := m.NewStorage(t)
+
+	storagetest.RunStorageTests(t, stor, bucket)
+}
+
 func TestIntegrationWithTestcontainers(t *testing.T) {
 	if testing.Short() {
 		t.Skip("integration test")
@@ -0,0 +1,307 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Presigned upload defaults.
+const (
+	DefaultPresignedUploadExpiry        = 15 * time.Minute
+	DefaultPresignedUploadVerifyWindow  = time.Hour
+	DefaultPresignedUploadStagingPrefix = "staging/"
+)
+
+var (
+	// ErrInvalidUploadToken is returned by VerifyUpload for malformed, forged or expired tokens.
+	ErrInvalidUploadToken = errors.New("invalid upload token")
+
+	// ErrUploadRejected is returned by VerifyUpload when the uploaded object violates the constraints
+	// of the token. The staging object is deleted.
+	ErrUploadRejected = errors.New("upload rejected")
+)
+
+// PresignedUploadOptions configures PresignedUploader.
+type PresignedUploadOptions struct {
+	Secret []byte // HMAC-SHA256 key signing upload tokens, required
+
+	// StagingPrefix is the prefix of keys clients upload to; VerifyUpload moves verified objects
+	// to their final keys. Default "staging/". A lifecycle rule on the prefix removes abandoned uploads.
+	StagingPrefix string
+
+	Expiry time.Duration // Presigned URL lifetime (default 15m)
+
+	// VerifyWindow is how long after the URL expires VerifyUpload still accepts the token (default 1h).
+	VerifyWindow time.Duration
+
+	MaxSize int64 // Maximum object size in bytes, 0 means no limit
+
+	// AllowedContentTypes restricts content types, e.g. "image/png" or "image/*". Empty allows any type.
+	AllowedContentTypes []string
+}
+
+// PresignedPutRequest describes an upload a server allows a client to perform.
+type PresignedPutRequest struct {
+	Bucket      string // Target bucket
+	Key         string // Final object key, set by VerifyUpload
+	Size        int64  // Exact expected size in bytes, 0 means any size up to MaxSize
+	ContentType string // Expected content type, empty means any allowed type
+	SHA256      string // Expected hex SHA-256 of the content, empty disables the check
+}
+
+// PresignedPut is a presigned PUT URL with the token that finalizes the upload.
+type PresignedPut struct {
+	URL        string    // Presigned PUT URL for the client
+	Token      string    // Opaque token passed back to VerifyUpload once the client reports completion
+	StagingKey string    // Key the client uploads to
+	ExpiresAt  time.Time // URL expiration time
+}
+
+// uploadToken is the signed payload of a PresignedPut token.
+type uploadToken struct {
+	Bucket      string `json:"b"`
+	StagingKey  string `json:"s"`
+	Key         string `json:"k"`
+	Size        int64  `json:"n,omitempty"`
+	ContentType string `json:"ct,omitempty"`
+	SHA256      string `json:"h,omitempty"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+// PresignedUploader issues presigned PUT URLs for server-initiated uploads and verifies
+// the uploads clients report as complete.
+//
+// GeneratePresignedPut returns a URL for a random key under StagingPrefix and a signed token
+// carrying the final key and the constraints. VerifyUpload checks that the object exists,
+// verifies size, content type and checksum, and moves it to the final key, so that objects
+// clients never uploaded or uploaded in violation of the limits never reach the final keys.
+// Presigned PUT URLs do not bind the size or content type of the body, which is why they are
+// verified after the upload.
+type PresignedUploader struct {
+	storage Storage
+	opts    PresignedUploadOptions
+	now     func() time.Time
+}
+
+// NewPresignedUploader returns an uploader issuing presigned URLs for s.
+func NewPresignedUploader(s Storage, opts PresignedUploadOptions) *PresignedUploader {
+	if opts.StagingPrefix == "" {
+		opts.StagingPrefix = DefaultPresignedUploadStagingPrefix
+	}
+	if opts.Expiry <= 0 {
+		opts.Expiry = DefaultPresignedUploadExpiry
+	}
+	if opts.VerifyWindow <= 0 {
+		opts.VerifyWindow = DefaultPresignedUploadVerifyWindow
+	}
+	return &PresignedUploader{storage: s, opts: opts, now: time.Now}
+}
+
+// GeneratePresignedPut returns a presigned PUT URL for a staging key and the token finalizing the upload.
+func (u *PresignedUploader) GeneratePresignedPut(ctx context.Context, req PresignedPutRequest) (*PresignedPut, error) {
+	if len(u.opts.Secret) == 0 {
+		return nil, errors.New("presigned upload secret is not configured")
+	}
+	if req.Key == "" {
+		return nil, errors.New("upload key is required")
+	}
+	if u.opts.MaxSize > 0 && req.Size > u.opts.MaxSize {
+		return nil, fmt.Errorf("%w: size %d exceeds maximum %d", errUploadTooLarge, req.Size, u.opts.MaxSize)
+	}
+	if req.ContentType != "" && !contentTypeAllowed(u.opts.AllowedContentTypes, req.ContentType) {
+		return nil, fmt.Errorf("%w: %s", errContentTypeNotAllowed, req.ContentType)
+	}
+	if req.SHA256 != "" {
+		if sum, err := hex.DecodeString(req.SHA256); err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("invalid sha256 checksum %q", req.SHA256)
+		}
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	stagingKey := u.opts.StagingPrefix + id + safeExt(req.Key)
+
+	url, err := u.storage.GetPresignedURL(ctx, req.Bucket, stagingKey, &PresignedURLOptions{
+		Method: "PUT",
+		Expiry: u.opts.Expiry,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := u.now().Add(u.opts.Expiry)
+	token, err := u.sign(uploadToken{
+		Bucket:      req.Bucket,
+		StagingKey:  stagingKey,
+		Key:         req.Key,
+		Size:        req.Size,
+		ContentType: req.ContentType,
+		SHA256:      strings.ToLower(req.SHA256),
+		ExpiresAt:   expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresignedPut{URL: url, Token: token, StagingKey: stagingKey, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyUpload verifies the upload of token and moves the object from the staging key to the final key.
+//
+// Errors:
+//   - ErrInvalidUploadToken — the token is malformed, forged or expired (beyond VerifyWindow)
+//   - CodeNotFound — the client has not uploaded the object
+//   - ErrUploadRejected — size, content type or checksum violate the constraints; the object is deleted
+//
+// VerifyUpload is idempotent: once the object is finalized, a repeated call returns the final object.
+func (u *PresignedUploader) VerifyUpload(ctx context.Context, token string) (*ObjectInfo, error) {
+	t, err := u.parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err := u.storage.Stat(ctx, t.Bucket, t.StagingKey)
+	if IsNotFound(err) {
+		if final, finalErr := u.storage.Stat(ctx, t.Bucket, t.Key); finalErr == nil {
+			return final, nil
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if reason := u.violation(t, staged); reason != "" {
+		u.discard(ctx, t.Bucket, t.StagingKey)
+		return nil, fmt.Errorf("%w: %s", ErrUploadRejected, reason)
+	}
+
+	if err := u.finalize(ctx, t, staged); err != nil {
+		return nil, err
+	}
+	return u.storage.Stat(ctx, t.Bucket, t.Key)
+}
+
+// violation returns why staged violates the constraints of t, or "" if it does not.
+func (u *PresignedUploader) violation(t *uploadToken, staged *ObjectInfo) string {
+	switch {
+	case t.Size > 0 && staged.Size != t.Size:
+		return fmt.Sprintf("size %d does not match expected %d", staged.Size, t.Size)
+	case u.opts.MaxSize > 0 && staged.Size > u.opts.MaxSize:
+		return fmt.Sprintf("size %d exceeds maximum %d", staged.Size, u.opts.MaxSize)
+	case t.ContentType != "" && !sameMediaType(staged.ContentType, t.ContentType):
+		return fmt.Sprintf("content type %q does not match expected %q", staged.ContentType, t.ContentType)
+	case !contentTypeAllowed(u.opts.AllowedContentTypes, staged.ContentType):
+		return fmt.Sprintf("content type %q is not allowed", staged.ContentType)
+	}
+	return ""
+}
+
+// finalize copies the staging object to the final key, verifying the checksum on the way,
+// and deletes the staging object.
+func (u *PresignedUploader) finalize(ctx context.Context, t *uploadToken, staged *ObjectInfo) error {
+	body, _, err := u.storage.Get(ctx, t.Bucket, t.StagingKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	var h hash.Hash
+	reader := io.Reader(body)
+	if t.SHA256 != "" {
+		h = sha256.New()
+		reader = io.TeeReader(body, h)
+	}
+
+	err = u.storage.Put(ctx, t.Bucket, t.Key, reader, &PutOptions{
+		ContentType:        staged.ContentType,
+		Metadata:           staged.Metadata,
+		CacheControl:       staged.CacheControl,
+		ContentDisposition: staged.ContentDisposition,
+		ContentEncoding:    staged.ContentEncoding,
+	})
+	if err != nil {
+		return err
+	}
+
+	if h != nil {
+		if sum := hex.EncodeToString(h.Sum(nil)); sum != t.SHA256 {
+			u.discard(ctx, t.Bucket, t.Key)
+			u.discard(ctx, t.Bucket, t.StagingKey)
+			return fmt.Errorf("%w: sha256 %s does not match expected %s", ErrUploadRejected, sum, t.SHA256)
+		}
+	}
+
+	u.discard(ctx, t.Bucket, t.StagingKey)
+	return nil
+}
+
+// discard deletes an object; a failure is logged, the staging prefix lifecycle rule removes leftovers.
+func (u *PresignedUploader) discard(ctx context.Context, bucket, key string) {
+	if err := u.storage.Delete(ctx, bucket, key); err != nil {
+		logger.FromContext(ctx).With("error", err).Warn("failed to delete upload", "bucket", bucket, "key", key)
+	}
+}
+
+// sign encodes t as base64url(JSON) + "." + base64url(HMAC-SHA256).
+func (u *PresignedUploader) sign(t uploadToken) (string, error) {
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode upload token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(u.mac(encoded)), nil
+}
+
+// parse verifies the signature and expiration of token.
+func (u *PresignedUploader) parse(token string) (*uploadToken, error) {
+	if len(u.opts.Secret) == 0 {
+		return nil, errors.New("presigned upload secret is not configured")
+	}
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidUploadToken)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, u.mac(encoded)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidUploadToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidUploadToken)
+	}
+	var t uploadToken
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidUploadToken)
+	}
+	if u.now().After(time.Unix(t.ExpiresAt, 0).Add(u.opts.VerifyWindow)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidUploadToken)
+	}
+	return &t, nil
+}
+
+func (u *PresignedUploader) mac(payload string) []byte {
+	m := hmac.New(sha256.New, u.opts.Secret)
+	_, _ = m.Write([]byte(payload))
+	return m.Sum(nil)
+}
+
+// sameMediaType reports whether two content types have the same media type, ignoring parameters.
+func sameMediaType(a, b string) bool {
+	m := mediaType(a)
+	return m != "" && m == mediaType(b)
+}
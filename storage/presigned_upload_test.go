@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// presignedStorage is an in-memory Storage serving presigned URLs, Get, Stat, Put and Delete
+type presignedStorage struct {
+	Storage
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newPresignedStorage() *presignedStorage {
+	return &presignedStorage{objects: make(map[string][]byte), types: make(map[string]string)}
+}
+
+func (s *presignedStorage) GetPresignedURL(_ context.Context, bucket, key string, opts *PresignedURLOptions) (string, error) {
+	return "https://s3.example.com/" + bucket + "/" + key + "?method=" + opts.Method, nil
+}
+
+func (s *presignedStorage) Put(_ context.Context, _, key string, reader io.Reader, opts *PutOptions) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	s.types[key] = opts.ContentType
+	return nil
+}
+
+func (s *presignedStorage) Get(_ context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, nil, &StorageError{Code: CodeNotFound, Message: "object not found", Err: ErrNotFound, Bucket: bucket, Key: key}
+	}
+	return io.NopCloser(bytes.NewReader(data)), &ObjectInfo{Key: key, Size: int64(len(data)), ContentType: s.types[key]}, nil
+}
+
+func (s *presignedStorage) Stat(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	_, info, err := s.Get(ctx, bucket, key)
+	return info, err
+}
+
+func (s *presignedStorage) Delete(_ context.Context, _, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *presignedStorage) has(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.objects[key]
+	return ok
+}
+
+// clientUpload simulates the client PUT to the presigned URL
+func (s *presignedStorage) clientUpload(t *testing.T, put *PresignedPut, data, contentType string) {
+	t.Helper()
+	require.NoError(t, s.Put(context.Background(), "media", put.StagingKey, strings.NewReader(data), &PutOptions{ContentType: contentType}))
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestPresignedUploader_VerifyUpload tests that a verified upload is moved to the final key
+func TestPresignedUploader_VerifyUpload(t *testing.T) {
+	t.Parallel()
+	s := newPresignedStorage()
+	u := NewPresignedUploader(s, PresignedUploadOptions{Secret: []byte("secret"), AllowedContentTypes: []string{"image/*"}})
+	ctx := context.Background()
+
+	put, err := u.GeneratePresignedPut(ctx, PresignedPutRequest{
+		Bucket:      "media",
+		Key:         "avatars/42.png",
+		Size:        5,
+		ContentType: "image/png",
+		SHA256:      sha256Hex("image"),
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(put.StagingKey, DefaultPresignedUploadStagingPrefix))
+	assert.True(t, strings.HasSuffix(put.StagingKey, ".png"))
+	assert.Contains(t, put.URL, put.StagingKey)
+	assert.Contains(t, put.URL, "method=PUT")
+
+	s.clientUpload(t, put, "image", "image/png")
+
+	info, err := u.VerifyUpload(ctx, put.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "avatars/42.png", info.Key)
+	assert.Equal(t, int64(5), info.Size)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.False(t, s.has(put.StagingKey), "staging object must be deleted")
+
+	// repeated verification returns the final object
+	info, err = u.VerifyUpload(ctx, put.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "avatars/42.png", info.Key)
+}
+
+// TestPresignedUploader_NotUploaded tests that uploads the client never performed are reported as not found
+func TestPresignedUploader_NotUploaded(t *testing.T) {
+	t.Parallel()
+	u := NewPresignedUploader(newPresignedStorage(), PresignedUploadOptions{Secret: []byte("secret")})
+
+	put, err := u.GeneratePresignedPut(context.Background(), PresignedPutRequest{Bucket: "media", Key: "doc.pdf"})
+	require.NoError(t, err)
+
+	_, err = u.VerifyUpload(context.Background(), put.Token)
+	assert.True(t, IsNotFound(err))
+}
+
+// TestPresignedUploader_Rejected tests that uploads violating constraints are rejected and deleted
+func TestPresignedUploader_Rejected(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		req         PresignedPutRequest
+		data        string
+		contentType string
+	}{
+		{name: "size mismatch", req: PresignedPutRequest{Size: 3}, data: "toolong", contentType: "image/png"},
+		{name: "max size", data: strings.Repeat("x", 11), contentType: "image/png"},
+		{name: "content type mismatch", req: PresignedPutRequest{ContentType: "image/png"}, data: "x", contentType: "image/jpeg"},
+		{name: "content type not allowed", data: "x", contentType: "text/html"},
+		{name: "checksum mismatch", req: PresignedPutRequest{SHA256: sha256Hex("expected")}, data: "actual", contentType: "image/png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := newPresignedStorage()
+			u := NewPresignedUploader(s, PresignedUploadOptions{
+				Secret:              []byte("secret"),
+				MaxSize:             10,
+				AllowedContentTypes: []string{"image/*"},
+			})
+			tt.req.Bucket, tt.req.Key = "media", "final.png"
+			put, err := u.GeneratePresignedPut(context.Background(), tt.req)
+			require.NoError(t, err)
+			s.clientUpload(t, put, tt.data, tt.contentType)
+
+			_, err = u.VerifyUpload(context.Background(), put.Token)
+			require.ErrorIs(t, err, ErrUploadRejected)
+			assert.False(t, s.has(put.StagingKey))
+			assert.False(t, s.has("final.png"))
+		})
+	}
+}
+
+// TestPresignedUploader_InvalidToken tests that forged and expired tokens are rejected
+func TestPresignedUploader_InvalidToken(t *testing.T) {
+	t.Parallel()
+	s := newPresignedStorage()
+	u := NewPresignedUploader(s, PresignedUploadOptions{Secret: []byte("secret"), Expiry: time.Minute, VerifyWindow: time.Minute})
+	now := time.Now()
+	u.now = func() time.Time { return now }
+
+	put, err := u.GeneratePresignedPut(context.Background(), PresignedPutRequest{Bucket: "media", Key: "a.txt"})
+	require.NoError(t, err)
+	s.clientUpload(t, put, "data", "text/plain")
+
+	other := NewPresignedUploader(s, PresignedUploadOptions{Secret: []byte("other")})
+	_, err = other.VerifyUpload(context.Background(), put.Token)
+	require.ErrorIs(t, err, ErrInvalidUploadToken)
+
+	payload, signature, _ := strings.Cut(put.Token, ".")
+	_, err = u.VerifyUpload(context.Background(), payload+"x."+signature)
+	require.ErrorIs(t, err, ErrInvalidUploadToken)
+
+	_, err = u.VerifyUpload(context.Background(), "garbage")
+	require.ErrorIs(t, err, ErrInvalidUploadToken)
+
+	now = now.Add(3 * time.Minute)
+	_, err = u.VerifyUpload(context.Background(), put.Token)
+	require.ErrorIs(t, err, ErrInvalidUploadToken)
+}
+
+// TestPresignedUploader_GenerateValidation tests that requests violating the options are refused
+func TestPresignedUploader_GenerateValidation(t *testing.T) {
+	t.Parallel()
+	u := NewPresignedUploader(newPresignedStorage(), PresignedUploadOptions{
+		Secret:              []byte("secret"),
+		MaxSize:             10,
+		AllowedContentTypes: []string{"image/*"},
+	})
+	ctx := context.Background()
+
+	for _, req := range []PresignedPutRequest{
+		{Bucket: "media"},
+		{Bucket: "media", Key: "a.png", Size: 11},
+		{Bucket: "media", Key: "a.png", ContentType: "text/html"},
+		{Bucket: "media", Key: "a.png", SHA256: "not-hex"},
+	} {
+		_, err := u.GeneratePresignedPut(ctx, req)
+		assert.Error(t, err, "%+v", req)
+	}
+
+	_, err := NewPresignedUploader(newPresignedStorage(), PresignedUploadOptions{}).
+		GeneratePresignedPut(ctx, PresignedPutRequest{Bucket: "media", Key: "a.png"})
+	assert.Error(t, err, "secret is required")
+}
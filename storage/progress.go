@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// ProgressFunc reports transfer progress: bytes transferred so far and the total size.
+// total is -1 when the size is unknown. It is called from the goroutine doing the transfer
+// after every Read or Write, so it must be fast.
+type ProgressFunc func(transferred, total int64)
+
+// ProgressReader wraps an io.Reader and reports the number of bytes read.
+type ProgressReader struct {
+	r        io.Reader
+	total    int64
+	progress ProgressFunc
+	read     atomic.Int64
+}
+
+// NewProgressReader returns a reader that calls progress after every Read.
+// Use a negative total when the size is unknown.
+func NewProgressReader(r io.Reader, total int64, progress ProgressFunc) *ProgressReader {
+	if total < 0 {
+		total = -1
+	}
+	return &ProgressReader{r: r, total: total, progress: progress}
+}
+
+// Read implements io.Reader.
+func (p *ProgressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.progress(p.read.Add(int64(n)), p.total)
+	}
+	return n, err
+}
+
+// Transferred returns the number of bytes read so far.
+func (p *ProgressReader) Transferred() int64 {
+	return p.read.Load()
+}
+
+// progressReadCloser is a ProgressReader that closes the underlying reader.
+type progressReadCloser struct {
+	*ProgressReader
+	io.Closer
+}
+
+// NewProgressReadCloser is like NewProgressReader for readers that must be closed,
+// e.g. object bodies returned by Get.
+func NewProgressReadCloser(rc io.ReadCloser, total int64, progress ProgressFunc) io.ReadCloser {
+	return &progressReadCloser{
+		ProgressReader: NewProgressReader(rc, total, progress),
+		Closer:         rc,
+	}
+}
+
+// ProgressWriter wraps an io.Writer and reports the number of bytes written.
+type ProgressWriter struct {
+	w        io.Writer
+	total    int64
+	progress ProgressFunc
+	written  atomic.Int64
+}
+
+// NewProgressWriter returns a writer that calls progress after every Write.
+// Use a negative total when the size is unknown.
+func NewProgressWriter(w io.Writer, total int64, progress ProgressFunc) *ProgressWriter {
+	if total < 0 {
+		total = -1
+	}
+	return &ProgressWriter{w: w, total: total, progress: progress}
+}
+
+// Write implements io.Writer.
+func (p *ProgressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.progress(p.written.Add(int64(n)), p.total)
+	}
+	return n, err
+}
+
+// Transferred returns the number of bytes written so far.
+func (p *ProgressWriter) Transferred() int64 {
+	return p.written.Load()
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressRecorder collects ProgressFunc calls
+type progressRecorder struct {
+	transferred []int64
+	totals      []int64
+}
+
+func (r *progressRecorder) record(transferred, total int64) {
+	r.transferred = append(r.transferred, transferred)
+	r.totals = append(r.totals, total)
+}
+
+// TestProgressReader tests that progress is reported after every read
+func TestProgressReader(t *testing.T) {
+	t.Parallel()
+	var rec progressRecorder
+	r := NewProgressReader(strings.NewReader("hello world"), 11, rec.record)
+
+	buf := make([]byte, 5)
+	for {
+		if _, err := r.Read(buf); err == io.EOF {
+			break
+		}
+	}
+
+	assert.Equal(t, []int64{5, 10, 11}, rec.transferred)
+	assert.Equal(t, []int64{11, 11, 11}, rec.totals)
+	assert.Equal(t, int64(11), r.Transferred())
+}
+
+// TestProgressReader_UnknownTotal tests that a negative total is reported as -1
+func TestProgressReader_UnknownTotal(t *testing.T) {
+	t.Parallel()
+	var rec progressRecorder
+
+	_, err := io.ReadAll(NewProgressReader(strings.NewReader("abc"), -42, rec.record))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rec.transferred[len(rec.transferred)-1])
+	assert.Equal(t, int64(-1), rec.totals[0])
+}
+
+// closeRecorder is a ReadCloser recording Close calls
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestProgressReadCloser tests that Close reaches the underlying reader
+func TestProgressReadCloser(t *testing.T) {
+	t.Parallel()
+	var rec progressRecorder
+	body := &closeRecorder{Reader: strings.NewReader("data")}
+	rc := NewProgressReadCloser(body, 4, rec.record)
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+
+	assert.Equal(t, "data", string(data))
+	assert.True(t, body.closed)
+	assert.Equal(t, int64(4), rec.transferred[len(rec.transferred)-1])
+}
+
+// TestProgressWriter tests that progress is reported after every write
+func TestProgressWriter(t *testing.T) {
+	t.Parallel()
+	var rec progressRecorder
+	var buf bytes.Buffer
+	w := NewProgressWriter(&buf, 6, rec.record)
+
+	_, err := w.Write([]byte("abc"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("def"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "abcdef", buf.String())
+	assert.Equal(t, []int64{3, 6}, rec.transferred)
+	assert.Equal(t, int64(6), w.Transferred())
+}
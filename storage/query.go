@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// QueryFormat is the serialization format of the object and of the records returned by Query.
+type QueryFormat string
+
+const (
+	QueryFormatCSV     QueryFormat = "CSV"
+	QueryFormatJSON    QueryFormat = "JSON"
+	QueryFormatParquet QueryFormat = "Parquet" // Input only
+)
+
+// CSVHeader specifies how the first line of a CSV object is treated.
+type CSVHeader string
+
+const (
+	CSVHeaderNone   CSVHeader = "NONE"   // The first line is a record; columns are referenced as _1, _2, ...
+	CSVHeaderIgnore CSVHeader = "IGNORE" // The first line is skipped; columns are referenced as _1, _2, ...
+	CSVHeaderUse    CSVHeader = "USE"    // The first line names the columns, e.g. s.status
+)
+
+// QueryCompression is the compression of the queried object.
+type QueryCompression string
+
+const (
+	QueryCompressionNone  QueryCompression = ""
+	QueryCompressionGZIP  QueryCompression = "GZIP"
+	QueryCompressionBZIP2 QueryCompression = "BZIP2"
+)
+
+// QueryInput describes how the queried object is serialized.
+type QueryInput struct {
+	Format      QueryFormat      // Object format
+	Compression QueryCompression // Object compression, CSV and JSON only
+
+	// CSV input
+	CSVHeader       CSVHeader // How the first line is treated, CSVHeaderNone if empty
+	FieldDelimiter  string    // Field delimiter, "," if empty
+	RecordDelimiter string    // Record delimiter, "\n" if empty
+
+	// JSON input
+	JSONLines bool // One JSON object per line; false for a single JSON document
+}
+
+// QueryOutput describes how the matching records are serialized.
+type QueryOutput struct {
+	Format          QueryFormat // QueryFormatCSV or QueryFormatJSON
+	FieldDelimiter  string      // CSV field delimiter, "," if empty
+	RecordDelimiter string      // Record delimiter, "\n" if empty
+}
+
+// Querier is implemented by storages that filter object contents on the server (S3 Select):
+// only the records matching an SQL expression are sent back, which saves bandwidth for
+// analytics-style reads of large CSV, JSON and Parquet objects.
+// Backends without S3 Select return an error with CodeQueryNotSupported.
+type Querier interface {
+	// Query runs the SQL expression, e.g. `SELECT s.id, s.total FROM S3Object s WHERE s.status = 'failed'`,
+	// against the object and streams the matching records serialized as output.
+	// The caller must close the returned reader; errors found while scanning the object
+	// are returned by Read.
+	Query(ctx context.Context, bucket, key, expression string, input QueryInput, output QueryOutput) (io.ReadCloser, error)
+}
+
+// ValidateQuery checks the expression and the input and output formats of a Query call.
+func ValidateQuery(expression string, input QueryInput, output QueryOutput) error {
+	if expression == "" {
+		return errors.New("query expression is empty")
+	}
+	switch input.Format {
+	case QueryFormatCSV, QueryFormatJSON:
+	case QueryFormatParquet:
+		if input.Compression != QueryCompressionNone {
+			return errors.New("compression is not supported for Parquet input")
+		}
+	default:
+		return errors.New("invalid query input format " + string(input.Format))
+	}
+	switch input.Compression {
+	case QueryCompressionNone, QueryCompressionGZIP, QueryCompressionBZIP2:
+	default:
+		return errors.New("invalid query input compression " + string(input.Compression))
+	}
+	switch input.CSVHeader {
+	case "", CSVHeaderNone, CSVHeaderIgnore, CSVHeaderUse:
+	default:
+		return errors.New("invalid CSV header mode " + string(input.CSVHeader))
+	}
+	if output.Format != QueryFormatCSV && output.Format != QueryFormatJSON {
+		return errors.New("invalid query output format " + string(output.Format))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateQuery tests validation of the query expression and formats.
+func TestValidateQuery(t *testing.T) {
+	t.Parallel()
+	csv := QueryInput{Format: QueryFormatCSV, CSVHeader: CSVHeaderUse, Compression: QueryCompressionGZIP}
+	json := QueryOutput{Format: QueryFormatJSON}
+
+	assert.NoError(t, ValidateQuery("SELECT * FROM S3Object", csv, json))
+	assert.NoError(t, ValidateQuery("SELECT * FROM S3Object", QueryInput{Format: QueryFormatParquet}, json))
+
+	tests := []struct {
+		name       string
+		expression string
+		input      QueryInput
+		output     QueryOutput
+		want       string
+	}{
+		{"empty expression", "", csv, json, "query expression is empty"},
+		{"unknown input format", "SELECT 1", QueryInput{Format: "XML"}, json, "invalid query input format XML"},
+		{"compressed parquet", "SELECT 1", QueryInput{Format: QueryFormatParquet, Compression: QueryCompressionGZIP}, json,
+			"compression is not supported for Parquet input"},
+		{"unknown compression", "SELECT 1", QueryInput{Format: QueryFormatJSON, Compression: "ZIP"}, json, "invalid query input compression ZIP"},
+		{"unknown CSV header", "SELECT 1", QueryInput{Format: QueryFormatCSV, CSVHeader: "FIRST"}, json, "invalid CSV header mode FIRST"},
+		{"parquet output", "SELECT 1", csv, QueryOutput{Format: QueryFormatParquet}, "invalid query output format Parquet"},
+	}
+	for _, tt := range tests {
+		assert.EqualError(t, ValidateQuery(tt.expression, tt.input, tt.output), tt.want, tt.name)
+	}
+}
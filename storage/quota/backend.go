@@ -0,0 +1,49 @@
+package quota
+
+import (
+	"context"
+	"sync"
+)
+
+// Backend хранит счётчики использования по областям.
+// Реализация должна атомарно применять Add, чтобы конкурентные записи не терялись.
+type Backend interface {
+	// Get возвращает текущее использование области; для неизвестной области — нулевое
+	Get(ctx context.Context, scope Scope) (Usage, error)
+	// Add прибавляет delta (значения могут быть отрицательными) и возвращает новое использование
+	Add(ctx context.Context, scope Scope, delta Usage) (Usage, error)
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+// MemoryBackend хранит счётчики в памяти процесса.
+// Подходит для тестов и single-instance сервисов; данные теряются при перезапуске.
+type MemoryBackend struct {
+	mu    sync.Mutex
+	usage map[Scope]Usage
+}
+
+// NewMemoryBackend создаёт пустой MemoryBackend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		usage: make(map[Scope]Usage),
+	}
+}
+
+// Get возвращает текущее использование области
+func (b *MemoryBackend) Get(_ context.Context, scope Scope) (Usage, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.usage[scope], nil
+}
+
+// Add прибавляет delta к использованию области
+func (b *MemoryBackend) Add(_ context.Context, scope Scope, delta Usage) (Usage, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	usage := b.usage[scope]
+	usage.Bytes += delta.Bytes
+	usage.Objects += delta.Objects
+	b.usage[scope] = usage
+	return usage, nil
+}
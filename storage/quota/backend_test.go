@@ -0,0 +1,48 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	b := NewMemoryBackend()
+	scope := Scope{Bucket: "b", Prefix: "p/"}
+
+	u, err := b.Get(ctx, scope)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{}, u)
+
+	u, err = b.Add(ctx, scope, Usage{Bytes: 10, Objects: 1})
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Bytes: 10, Objects: 1}, u)
+
+	u, err = b.Add(ctx, scope, Usage{Bytes: -4, Objects: -1})
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Bytes: 6}, u)
+}
+
+func TestMemoryBackend_Concurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	b := NewMemoryBackend()
+	scope := Scope{Bucket: "b"}
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			_, _ = b.Add(ctx, scope, Usage{Bytes: 1, Objects: 1})
+		})
+	}
+	wg.Wait()
+
+	u, err := b.Get(ctx, scope)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Bytes: 100, Objects: 100}, u)
+}
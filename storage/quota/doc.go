@@ -0,0 +1,39 @@
+// Package quota реализует декоратор [storage.Storage] с учётом использования и квотами.
+//
+// Декоратор учитывает занятый объём и количество объектов по bucket'ам и префиксам
+// и применяет квоты, что нужно multi-tenant сервисам загрузки поверх storage/minio.
+//
+// Использование:
+//
+//	inner, err := minio.NewDefault(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	s := quota.New(inner, &quota.Options{
+//	    Backend: backend, // по умолчанию quota.NewMemoryBackend()
+//	    Limits: []quota.Limit{{
+//	        Scope: quota.Scope{Bucket: "uploads", Prefix: "tenant-1/"},
+//	        Soft:  quota.Usage{Bytes: 8 << 30},
+//	        Hard:  quota.Usage{Bytes: 10 << 30, Objects: 100_000},
+//	    }},
+//	})
+//
+//	err = s.Put(ctx, "uploads", "tenant-1/file.bin", r, nil)
+//	if storage.IsQuotaExceeded(err) {
+//	    // Жёсткая квота превышена
+//	}
+//
+// Особенности:
+//   - Использование bucket'ов учитывается всегда, префиксов — только указанных в Limits
+//   - Жёсткая квота проверяется до записи; поток данных прерывается, как только объём
+//     превышает остаток квоты, и операция завершается ошибкой с кодом CodeQuotaExceeded
+//   - Мягкая квота не блокирует запись: пишется предупреждение и вызывается OnSoftLimit
+//   - При перезаписи и удалении размер объекта определяется через Stat
+//   - Части multipart загрузки учитываются в остатке квоты до завершения загрузки
+//   - Счётчики хранятся в [Backend]; [MemoryBackend] подходит для тестов и single-instance
+//     сервисов, для нескольких экземпляров нужна общая реализация (например, в БД)
+//   - Ошибки обновления счётчиков после успешной записи логируются и не возвращаются
+//   - Проверка квоты и запись не атомарны: при конкурентных записях квота может быть
+//     превышена на размер одновременно записываемых объектов
+package quota
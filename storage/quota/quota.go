@@ -0,0 +1,397 @@
+package quota
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+)
+
+// unlimited — отсутствие ограничения на оставшийся объём
+const unlimited = -1
+
+// Scope — область учёта: bucket целиком (пустой Prefix) или префикс ключей внутри bucket
+type Scope struct {
+	Bucket string
+	Prefix string
+}
+
+// contains проверяет, относится ли объект к области
+func (s Scope) contains(bucket, key string) bool {
+	return s.Bucket == bucket && strings.HasPrefix(key, s.Prefix)
+}
+
+// Usage — занятый объём в байтах и количество объектов
+type Usage struct {
+	Bytes   int64
+	Objects int64
+}
+
+// Limit задаёт квоты для области. Нулевое значение поля означает отсутствие ограничения.
+//   - Soft — при превышении запись выполняется, но пишется предупреждение и вызывается OnSoftLimit
+//   - Hard — запись, которая привела бы к превышению, отклоняется с CodeQuotaExceeded;
+//     квота проверяется до записи, поэтому конкурентные записи могут её превысить
+type Limit struct {
+	Scope Scope
+	Soft  Usage
+	Hard  Usage
+}
+
+// Options содержит настройки Storage
+type Options struct {
+	// Backend хранит счётчики использования; по умолчанию NewMemoryBackend()
+	Backend Backend
+	// Limits — квоты по областям. Использование bucket'ов учитывается всегда,
+	// использование префиксов — только для префиксов из Limits.
+	Limits []Limit
+	// Logger для предупреждений о превышении мягких квот и ошибок учёта; по умолчанию slog.Default()
+	Logger *slog.Logger
+	// OnSoftLimit вызывается после записи, превысившей мягкую квоту
+	OnSoftLimit func(ctx context.Context, scope Scope, usage Usage, soft Usage)
+}
+
+// Storage — декоратор storage.Storage, учитывающий занятый объём и количество объектов
+// по bucket'ам и префиксам и применяющий мягкие и жёсткие квоты.
+// Операции, не изменяющие данные, передаются в исходное хранилище без изменений.
+type Storage struct {
+	storage.Storage
+
+	backend     Backend
+	limits      []Limit
+	logger      *slog.Logger
+	onSoftLimit func(ctx context.Context, scope Scope, usage Usage, soft Usage)
+
+	mu      sync.Mutex
+	pending map[string]*pendingUpload
+}
+
+// pendingUpload — состояние незавершённой multipart загрузки
+type pendingUpload struct {
+	prevSize int64 // размер перезаписываемого объекта
+	existed  bool  // объект существовал до загрузки
+	bytes    int64 // суммарный размер загруженных частей
+}
+
+// New создаёт Storage поверх inner
+func New(inner storage.Storage, opts *Options) *Storage {
+	if opts == nil {
+		opts = &Options{}
+	}
+	backend := opts.Backend
+	if backend == nil {
+		backend = NewMemoryBackend()
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Storage{
+		Storage:     inner,
+		backend:     backend,
+		limits:      opts.Limits,
+		logger:      log.WithGroup("storage").With("decorator", "quota"),
+		onSoftLimit: opts.OnSoftLimit,
+		pending:     make(map[string]*pendingUpload),
+	}
+}
+
+// Usage возвращает текущее использование области
+func (s *Storage) Usage(ctx context.Context, scope Scope) (Usage, error) {
+	usage, err := s.backend.Get(ctx, scope)
+	if err != nil {
+		return Usage{}, errors.Wrap(err, "failed to get usage")
+	}
+	return usage, nil
+}
+
+// Put сохраняет объект, если это не нарушает жёстких квот.
+// При перезаписи объекта учитывается разница размеров.
+func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
+	prevSize, existed, err := s.stat(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+
+	scopes := s.scopesFor(bucket, key)
+	remaining, err := s.checkHard(ctx, bucket, key, prevSize, objectsDelta(existed))
+	if err != nil {
+		return err
+	}
+
+	counter := &limitedReader{reader: reader, remaining: remaining, bucket: bucket, key: key}
+	if err := s.Storage.Put(ctx, bucket, key, counter, opts); err != nil {
+		if counter.exceeded {
+			return quotaError(bucket, key)
+		}
+		return err
+	}
+
+	s.add(ctx, scopes, Usage{Bytes: counter.n - prevSize, Objects: objectsDelta(existed)})
+	return nil
+}
+
+// Delete удаляет объект и уменьшает использование
+func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
+	size, existed, err := s.stat(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Storage.Delete(ctx, bucket, key); err != nil {
+		return err
+	}
+
+	if existed {
+		s.add(ctx, s.scopesFor(bucket, key), Usage{Bytes: -size, Objects: -1})
+	}
+	return nil
+}
+
+// GetWithOptions передаёт запрос исходному хранилищу, если оно реализует storage.OptionsGetter
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
+	getter, ok := s.Storage.(storage.OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	return getter.GetWithOptions(ctx, bucket, key, opts)
+}
+
+// CreateMultipartUpload начинает multipart загрузку, если квота на количество объектов не исчерпана
+func (s *Storage) CreateMultipartUpload(ctx context.Context, bucket, key string, opts *storage.PutOptions) (*storage.MultipartUpload, error) {
+	prevSize, existed, err := s.stat(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.checkHard(ctx, bucket, key, prevSize, objectsDelta(existed)); err != nil {
+		return nil, err
+	}
+
+	upload, err := s.Storage.CreateMultipartUpload(ctx, bucket, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.pending[upload.UploadID] = &pendingUpload{prevSize: prevSize, existed: existed}
+	s.mu.Unlock()
+
+	return upload, nil
+}
+
+// UploadPart загружает часть с учётом уже загруженных частей той же загрузки
+func (s *Storage) UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, reader io.Reader) (*storage.UploadedPart, error) {
+	s.mu.Lock()
+	upload := s.pending[uploadID]
+	var prevSize, uploaded int64
+	if upload != nil {
+		prevSize, uploaded = upload.prevSize, upload.bytes
+	}
+	s.mu.Unlock()
+
+	remaining, err := s.checkHard(ctx, bucket, key, prevSize-uploaded, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := &limitedReader{reader: reader, remaining: remaining, bucket: bucket, key: key}
+	part, err := s.Storage.UploadPart(ctx, bucket, key, uploadID, partNumber, counter)
+	if err != nil {
+		if counter.exceeded {
+			return nil, quotaError(bucket, key)
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if upload != nil {
+		upload.bytes += counter.n
+	}
+	s.mu.Unlock()
+
+	return part, nil
+}
+
+// CompleteMultipartUpload завершает загрузку и учитывает итоговый размер объекта
+func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, opts *storage.CompleteMultipartUploadOptions) (*storage.ObjectInfo, error) {
+	info, err := s.Storage.CompleteMultipartUpload(ctx, bucket, key, uploadID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	upload := s.pending[uploadID]
+	delete(s.pending, uploadID)
+	s.mu.Unlock()
+
+	var delta Usage
+	switch {
+	case upload != nil:
+		size := upload.bytes
+		if info != nil {
+			size = info.Size
+		}
+		delta = Usage{Bytes: size - upload.prevSize, Objects: objectsDelta(upload.existed)}
+	case info != nil:
+		// Загрузка начата до создания декоратора: предыдущий размер неизвестен
+		delta = Usage{Bytes: info.Size, Objects: 1}
+	}
+
+	s.add(ctx, s.scopesFor(bucket, key), delta)
+	return info, nil
+}
+
+// AbortMultipartUpload отменяет загрузку
+func (s *Storage) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	if err := s.Storage.AbortMultipartUpload(ctx, bucket, key, uploadID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.pending, uploadID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// scopesFor возвращает области, к которым относится объект: bucket и подходящие префиксы из Limits
+func (s *Storage) scopesFor(bucket, key string) []Scope {
+	scopes := []Scope{{Bucket: bucket}}
+	for _, limit := range s.limits {
+		if limit.Scope.Prefix == "" || !limit.Scope.contains(bucket, key) || slices.Contains(scopes, limit.Scope) {
+			continue
+		}
+		scopes = append(scopes, limit.Scope)
+	}
+	return scopes
+}
+
+// checkHard проверяет жёсткие квоты для записи объекта, заменяющего freed байт
+// и добавляющего objects объектов. Возвращает оставшийся объём для записи или unlimited.
+// Проверка выполняется до записи и не резервирует объём: конкурентные записи видят одно
+// и то же использование, и вместе могут превысить квоту.
+func (s *Storage) checkHard(ctx context.Context, bucket, key string, freed, objects int64) (int64, error) {
+	remaining := int64(unlimited)
+	for _, limit := range s.limits {
+		if limit.Hard == (Usage{}) || !limit.Scope.contains(bucket, key) {
+			continue
+		}
+
+		usage, err := s.backend.Get(ctx, limit.Scope)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get usage")
+		}
+
+		if limit.Hard.Objects > 0 && usage.Objects+objects > limit.Hard.Objects {
+			return 0, quotaError(bucket, key)
+		}
+		if limit.Hard.Bytes > 0 {
+			left := max(limit.Hard.Bytes-usage.Bytes+freed, 0)
+			if remaining == unlimited || left < remaining {
+				remaining = left
+			}
+		}
+	}
+	return remaining, nil
+}
+
+// add применяет изменение использования и проверяет мягкие квоты.
+// Объект уже записан, поэтому ошибки учёта только логируются.
+func (s *Storage) add(ctx context.Context, scopes []Scope, delta Usage) {
+	if delta == (Usage{}) {
+		return
+	}
+
+	for _, scope := range scopes {
+		usage, err := s.backend.Add(ctx, scope, delta)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to update usage",
+				"bucket", scope.Bucket, "prefix", scope.Prefix, "error", err)
+			continue
+		}
+		s.checkSoft(ctx, scope, usage)
+	}
+}
+
+// checkSoft сообщает о превышении мягкой квоты области
+func (s *Storage) checkSoft(ctx context.Context, scope Scope, usage Usage) {
+	for _, limit := range s.limits {
+		if limit.Scope != scope || limit.Soft == (Usage{}) {
+			continue
+		}
+		bytesExceeded := limit.Soft.Bytes > 0 && usage.Bytes > limit.Soft.Bytes
+		objectsExceeded := limit.Soft.Objects > 0 && usage.Objects > limit.Soft.Objects
+		if !bytesExceeded && !objectsExceeded {
+			continue
+		}
+
+		s.logger.WarnContext(ctx, "soft quota exceeded",
+			"bucket", scope.Bucket, "prefix", scope.Prefix,
+			"bytes", usage.Bytes, "objects", usage.Objects,
+			"soft_bytes", limit.Soft.Bytes, "soft_objects", limit.Soft.Objects)
+		if s.onSoftLimit != nil {
+			s.onSoftLimit(ctx, scope, usage, limit.Soft)
+		}
+	}
+}
+
+// stat возвращает размер объекта и признак его существования
+func (s *Storage) stat(ctx context.Context, bucket, key string) (int64, bool, error) {
+	info, err := s.Storage.Stat(ctx, bucket, key)
+	if storage.IsNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return info.Size, true, nil
+}
+
+func objectsDelta(existed bool) int64 {
+	if existed {
+		return 0
+	}
+	return 1
+}
+
+func quotaError(bucket, key string) error {
+	return &storage.StorageError{
+		Code:    storage.CodeQuotaExceeded,
+		Message: "quota exceeded",
+		Err:     storage.ErrQuotaExceeded,
+		Bucket:  bucket,
+		Key:     key,
+	}
+}
+
+// limitedReader считает прочитанные байты и прерывает чтение при превышении remaining
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	n         int64
+	exceeded  bool
+	bucket    string
+	key       string
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	if r.remaining != unlimited && r.n > r.remaining {
+		r.exceeded = true
+		return n, quotaError(r.bucket, r.key)
+	}
+	return n, err
+}
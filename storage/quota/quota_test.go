@@ -0,0 +1,176 @@
+package quota
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+	"github.com/pure-golang/adapters/storage/storagetest"
+)
+
+func put(t *testing.T, s *Storage, bucket, key, data string) error {
+	t.Helper()
+	return s.Put(context.Background(), bucket, key, strings.NewReader(data), nil)
+}
+
+func usage(t *testing.T, s *Storage, scope Scope) Usage {
+	t.Helper()
+	u, err := s.Usage(context.Background(), scope)
+	require.NoError(t, err)
+	return u
+}
+
+func TestStorage_TracksUsage(t *testing.T) {
+	t.Parallel()
+	tenant := Scope{Bucket: "uploads", Prefix: "tenant-1/"}
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, &Options{Limits: []Limit{{Scope: tenant}}})
+
+	require.NoError(t, put(t, s, "uploads", "tenant-1/a.txt", "hello"))
+	require.NoError(t, put(t, s, "uploads", "tenant-1/b.txt", "world!"))
+	require.NoError(t, put(t, s, "uploads", "tenant-2/c.txt", "abc"))
+
+	assert.Equal(t, Usage{Bytes: 14, Objects: 3}, usage(t, s, Scope{Bucket: "uploads"}))
+	assert.Equal(t, Usage{Bytes: 11, Objects: 2}, usage(t, s, tenant))
+	assert.Equal(t, Usage{}, usage(t, s, Scope{Bucket: "uploads", Prefix: "tenant-2/"}), "prefixes without limits are not tracked")
+
+	// Overwrite counts the size difference
+	require.NoError(t, put(t, s, "uploads", "tenant-1/a.txt", "hi"))
+	assert.Equal(t, Usage{Bytes: 8, Objects: 2}, usage(t, s, tenant))
+
+	require.NoError(t, s.Delete(context.Background(), "uploads", "tenant-1/b.txt"))
+	assert.Equal(t, Usage{Bytes: 2, Objects: 1}, usage(t, s, tenant))
+
+	// Deleting a missing object does not change usage
+	require.NoError(t, s.Delete(context.Background(), "uploads", "tenant-1/missing.txt"))
+	assert.Equal(t, Usage{Bytes: 2, Objects: 1}, usage(t, s, tenant))
+
+	// Object sizes come from Stat, not from listing the key prefix
+	assert.Zero(t, inner.Calls("List"))
+}
+
+func TestStorage_HardBytesLimit(t *testing.T) {
+	t.Parallel()
+	inner := storagetest.NewMemoryStorage()
+	tenant := Scope{Bucket: "uploads", Prefix: "tenant-1/"}
+	s := New(inner, &Options{Limits: []Limit{{Scope: tenant, Hard: Usage{Bytes: 10}}}})
+
+	require.NoError(t, put(t, s, "uploads", "tenant-1/a.txt", "12345678"))
+
+	err := put(t, s, "uploads", "tenant-1/b.txt", "123")
+	require.Error(t, err)
+	assert.True(t, storage.IsQuotaExceeded(err))
+	assert.NotContains(t, inner.Keys("uploads"), "tenant-1/b.txt")
+	assert.Equal(t, Usage{Bytes: 8, Objects: 1}, usage(t, s, tenant))
+
+	// Overwriting frees the previous size
+	require.NoError(t, put(t, s, "uploads", "tenant-1/a.txt", "1234567890"))
+
+	// Other prefixes are not limited
+	require.NoError(t, put(t, s, "uploads", "tenant-2/a.txt", "12345678901234567890"))
+}
+
+func TestStorage_HardObjectsLimit(t *testing.T) {
+	t.Parallel()
+	s := New(storagetest.NewMemoryStorage(), &Options{Limits: []Limit{{Scope: Scope{Bucket: "uploads"}, Hard: Usage{Objects: 1}}}})
+
+	require.NoError(t, put(t, s, "uploads", "a.txt", "a"))
+	require.NoError(t, put(t, s, "uploads", "a.txt", "aa"), "overwrite does not add objects")
+
+	err := put(t, s, "uploads", "b.txt", "b")
+	assert.True(t, storage.IsQuotaExceeded(err))
+
+	_, err = s.CreateMultipartUpload(context.Background(), "uploads", "c.bin", nil)
+	assert.True(t, storage.IsQuotaExceeded(err))
+}
+
+func TestStorage_SoftLimit(t *testing.T) {
+	t.Parallel()
+	scope := Scope{Bucket: "uploads"}
+	var notified []Usage
+	var logs bytes.Buffer
+	s := New(storagetest.NewMemoryStorage(), &Options{
+		Limits: []Limit{{Scope: scope, Soft: Usage{Bytes: 4}}},
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		OnSoftLimit: func(_ context.Context, got Scope, usage Usage, soft Usage) {
+			assert.Equal(t, scope, got)
+			assert.Equal(t, Usage{Bytes: 4}, soft)
+			notified = append(notified, usage)
+		},
+	})
+
+	require.NoError(t, put(t, s, "uploads", "a.txt", "abc"))
+	assert.Empty(t, notified)
+
+	require.NoError(t, put(t, s, "uploads", "b.txt", "abc"), "soft limit does not block writes")
+	assert.Equal(t, []Usage{{Bytes: 6, Objects: 2}}, notified)
+	assert.Contains(t, logs.String(), "soft quota exceeded")
+}
+
+func TestStorage_MultipartUpload(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	scope := Scope{Bucket: "uploads"}
+	s := New(storagetest.NewMemoryStorage(), &Options{Limits: []Limit{{Scope: scope, Hard: Usage{Bytes: 10}}}})
+
+	upload, err := s.CreateMultipartUpload(ctx, "uploads", "big.bin", nil)
+	require.NoError(t, err)
+
+	part1, err := s.UploadPart(ctx, "uploads", "big.bin", upload.UploadID, 1, strings.NewReader("123456"))
+	require.NoError(t, err)
+
+	// Uploaded parts count against the remaining quota
+	_, err = s.UploadPart(ctx, "uploads", "big.bin", upload.UploadID, 2, strings.NewReader("123456"))
+	assert.True(t, storage.IsQuotaExceeded(err))
+
+	part2, err := s.UploadPart(ctx, "uploads", "big.bin", upload.UploadID, 2, strings.NewReader("1234"))
+	require.NoError(t, err)
+
+	info, err := s.CompleteMultipartUpload(ctx, "uploads", "big.bin", upload.UploadID, &storage.CompleteMultipartUploadOptions{
+		Parts: []storage.UploadedPart{*part1, *part2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), info.Size)
+	assert.Equal(t, Usage{Bytes: 10, Objects: 1}, usage(t, s, scope))
+}
+
+func TestStorage_AbortMultipartUpload(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	scope := Scope{Bucket: "uploads"}
+	s := New(storagetest.NewMemoryStorage(), &Options{Limits: []Limit{{Scope: scope, Hard: Usage{Bytes: 10}}}})
+
+	upload, err := s.CreateMultipartUpload(ctx, "uploads", "big.bin", nil)
+	require.NoError(t, err)
+	_, err = s.UploadPart(ctx, "uploads", "big.bin", upload.UploadID, 1, strings.NewReader("123456"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.AbortMultipartUpload(ctx, "uploads", "big.bin", upload.UploadID))
+
+	assert.Empty(t, s.pending)
+	assert.Equal(t, Usage{}, usage(t, s, scope))
+}
+
+func TestNew_Defaults(t *testing.T) {
+	t.Parallel()
+	s := New(storagetest.NewMemoryStorage(), nil)
+
+	assert.NotNil(t, s.backend)
+	assert.NotNil(t, s.logger)
+	require.NoError(t, put(t, s, "b", "k", "data"))
+}
+
+func TestStorage_GetWithOptions(t *testing.T) {
+	t.Parallel()
+	s := New(storagetest.NewMemoryStorage(), nil)
+
+	_, _, err := s.GetWithOptions(context.Background(), "b", "k", &storage.GetOptions{RequestPayer: true})
+
+	assert.ErrorContains(t, err, "does not support get options")
+}
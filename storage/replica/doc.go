@@ -0,0 +1,44 @@
+// Package replica реализует декоратор [storage.Storage] с асинхронной репликацией записи
+// во вторичные хранилища.
+//
+// Используется для двойной записи на время миграции (например, MinIO → S3): основное
+// хранилище остаётся источником истины, реплики догоняют его в фоне.
+//
+// Использование:
+//
+//	primary, err := minio.NewDefault(minioCfg)
+//	if err != nil {
+//	    return err
+//	}
+//	s3, err := minio.NewDefault(s3Cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	s := replica.New(primary, []replica.Replica{{Name: "s3", Storage: s3}}, &replica.Options{
+//	    QueueSize:  5000,
+//	    MaxRetries: 5,
+//	})
+//	if err := s.Start(); err != nil {
+//	    return err
+//	}
+//	defer s.Close()
+//
+// Особенности:
+//   - Put, Delete и CompleteMultipartUpload возвращают результат основного хранилища;
+//     ошибки репликации не видны вызывающему коду
+//   - У каждой реплики своя очередь размером QueueSize и один обработчик: медленная реплика
+//     не задерживает остальные, операции над одним ключом применяются по порядку
+//   - Реплика получает актуальное содержимое и метаданные объекта из основного хранилища;
+//     если объект уже удалён, он удаляется и из реплики
+//   - Неудачная репликация повторяется MaxRetries раз с паузой RetryBackoff, удваивающейся
+//     с каждым повтором
+//   - Multipart загрузка выполняется только в основном хранилище, реплика получает собранный объект
+//   - Get, GetWithOptions, Exists, List, GetFileHeader и Stat при ошибке основного хранилища
+//     (кроме отсутствия объекта) выполняются в репликах по порядку; реплика может отставать
+//   - GetPresignedURL и остальные операции выполняются только в основном хранилище
+//   - Close ждёт репликации очереди не дольше DrainTimeout и закрывает все хранилища
+//   - Расхождение реплик видно по метрикам storage.replica.failed_total и
+//     storage.replica.dropped_total (атрибуты replica и operation); также
+//     storage.replica.replicated_total, storage.replica.failovers_total и storage.replica.queue_length
+package replica
@@ -0,0 +1,538 @@
+package replica
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+)
+
+const (
+	// DefaultQueueSize — размер очереди репликации одной реплики по умолчанию
+	DefaultQueueSize = 1000
+	// DefaultMaxRetries — число повторов репликации объекта по умолчанию
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff — пауза перед первым повтором по умолчанию; удваивается с каждым повтором
+	DefaultRetryBackoff = time.Second
+	// DefaultDrainTimeout — время ожидания репликации очереди в Close по умолчанию
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+// meterName — имя Meter для метрик репликации
+const meterName = "github.com/pure-golang/adapters/storage/replica"
+
+// Операции репликации (атрибут operation метрик)
+const (
+	operationPut    = "put"
+	operationDelete = "delete"
+)
+
+// Replica — вторичное хранилище
+type Replica struct {
+	// Name — имя реплики в логах и атрибуте replica метрик, например "s3"
+	Name    string
+	Storage storage.Storage
+}
+
+// Options содержит настройки Storage
+type Options struct {
+	// QueueSize — размер очереди каждой реплики; при переполнении операция не реплицируется.
+	// По умолчанию DefaultQueueSize.
+	QueueSize int
+	// MaxRetries — число повторов неудачной репликации; по умолчанию DefaultMaxRetries
+	MaxRetries int
+	// RetryBackoff — пауза перед первым повтором; по умолчанию DefaultRetryBackoff
+	RetryBackoff time.Duration
+	// DrainTimeout — сколько Close ждёт репликации оставшихся операций; по умолчанию DefaultDrainTimeout
+	DrainTimeout time.Duration
+	// MeterProvider для метрик репликации; по умолчанию otel.GetMeterProvider()
+	MeterProvider metric.MeterProvider
+	// Logger для ошибок репликации; по умолчанию slog.Default()
+	Logger *slog.Logger
+}
+
+// Storage — декоратор storage.Storage, записывающий в основное хранилище и асинхронно
+// реплицирующий изменения во вторичные.
+//
+// Put, Delete и CompleteMultipartUpload выполняются в основном хранилище; после успеха
+// операция ставится в очередь каждой реплики. Реплика получает актуальное состояние объекта:
+// содержимое читается из основного хранилища в момент репликации. Чтение выполняется
+// из основного хранилища, при ошибке (кроме отсутствия объекта) — из реплик по порядку.
+//
+// Метрики (атрибуты replica и operation):
+//   - storage.replica.replicated_total — реплицированные операции
+//   - storage.replica.failed_total — операции, не реплицированные после всех повторов
+//   - storage.replica.dropped_total — операции, не поставленные в переполненную очередь
+//   - storage.replica.failovers_total — чтения, выполненные репликой
+//   - storage.replica.queue_length — длина очереди (без атрибута operation)
+//
+// failed_total и dropped_total означают расхождение реплики с основным хранилищем.
+type Storage struct {
+	storage.Storage
+
+	replicas     []*replica
+	maxRetries   int
+	retryBackoff time.Duration
+	drainTimeout time.Duration
+	logger       *slog.Logger
+
+	replicated metric.Int64Counter
+	failed     metric.Int64Counter
+	dropped    metric.Int64Counter
+	failovers  metric.Int64Counter
+
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// replica — реплика с очередью операций
+type replica struct {
+	name    string
+	storage storage.Storage
+	queue   chan task
+}
+
+// task — операция, ожидающая репликации
+type task struct {
+	operation string
+	bucket    string
+	key       string
+}
+
+// New создаёт Storage с основным хранилищем primary и репликами replicas.
+// Репликация начинается после Start; операции до Start накапливаются в очереди.
+// Ошибка создания метрик передаётся в otel.Handle, репликация работает без них.
+func New(primary storage.Storage, replicas []Replica, opts *Options) *Storage {
+	if opts == nil {
+		opts = &Options{}
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	retryBackoff := opts.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = DefaultRetryBackoff
+	}
+	drainTimeout := opts.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+	provider := opts.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	s := &Storage{
+		Storage:      primary,
+		maxRetries:   maxRetries,
+		retryBackoff: retryBackoff,
+		drainTimeout: drainTimeout,
+		logger:       log.WithGroup("storage").With("decorator", "replica"),
+	}
+	for _, r := range replicas {
+		s.replicas = append(s.replicas, &replica{
+			name:    r.Name,
+			storage: r.Storage,
+			queue:   make(chan task, queueSize),
+		})
+	}
+
+	m := provider.Meter(meterName)
+	s.replicated = s.counter(m, "storage.replica.replicated_total", "Total number of operations replicated to a replica")
+	s.failed = s.counter(m, "storage.replica.failed_total", "Total number of operations not replicated after all retries")
+	s.dropped = s.counter(m, "storage.replica.dropped_total", "Total number of operations dropped because the replication queue was full")
+	s.failovers = s.counter(m, "storage.replica.failovers_total", "Total number of reads served by a replica")
+
+	_, err := m.Int64ObservableGauge(
+		"storage.replica.queue_length",
+		metric.WithDescription("Number of operations waiting for replication"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for _, r := range s.replicas {
+				o.Observe(int64(len(r.queue)), metric.WithAttributes(attribute.String("replica", r.name)))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		otel.Handle(errors.Wrap(err, "failed to create replication queue length gauge"))
+	}
+	return s
+}
+
+// Start запускает репликацию; повторный вызов ничего не делает
+func (s *Storage) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.closed {
+		return nil
+	}
+	s.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, r := range s.replicas {
+		s.wg.Add(1)
+		go s.run(ctx, r)
+	}
+	return nil
+}
+
+// Close перестаёт принимать операции в очередь, ждёт их репликации не дольше DrainTimeout
+// и закрывает основное хранилище и реплики. Не реплицированные операции учитываются
+// в storage.replica.dropped_total.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	started := s.started
+	for _, r := range s.replicas {
+		close(r.queue)
+	}
+	s.mu.Unlock()
+
+	if started {
+		done := make(chan struct{})
+		go func() {
+			s.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(s.drainTimeout):
+			s.logger.Warn("replication queue was not drained before timeout", "timeout", s.drainTimeout)
+			s.cancel()
+			<-done
+		}
+		s.cancel()
+	}
+	for _, r := range s.replicas {
+		s.discard(r)
+	}
+
+	var errs []string
+	if err := s.Storage.Close(); err != nil {
+		errs = append(errs, "primary: "+err.Error())
+	}
+	for _, r := range s.replicas {
+		if err := r.storage.Close(); err != nil {
+			errs = append(errs, r.name+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("failed to close storage: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Put сохраняет объект в основном хранилище и ставит его в очередь репликации
+func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
+	if err := s.Storage.Put(ctx, bucket, key, reader, opts); err != nil {
+		return err
+	}
+	s.enqueue(ctx, task{operation: operationPut, bucket: bucket, key: key})
+	return nil
+}
+
+// Delete удаляет объект из основного хранилища и ставит удаление в очередь репликации
+func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
+	if err := s.Storage.Delete(ctx, bucket, key); err != nil {
+		return err
+	}
+	s.enqueue(ctx, task{operation: operationDelete, bucket: bucket, key: key})
+	return nil
+}
+
+// CompleteMultipartUpload завершает multipart загрузку в основном хранилище и ставит
+// собранный объект в очередь репликации. Части в реплики не передаются.
+func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, opts *storage.CompleteMultipartUploadOptions) (*storage.ObjectInfo, error) {
+	info, err := s.Storage.CompleteMultipartUpload(ctx, bucket, key, uploadID, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.enqueue(ctx, task{operation: operationPut, bucket: bucket, key: key})
+	return info, nil
+}
+
+// Get читает объект из основного хранилища, при его ошибке — из реплик
+func (s *Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	rc, info, err := s.Storage.Get(ctx, bucket, key)
+	if !s.shouldFailover(ctx, err) {
+		return rc, info, err
+	}
+	for _, r := range s.replicas {
+		rc, info, replicaErr := r.storage.Get(ctx, bucket, key)
+		if replicaErr == nil {
+			s.failover(ctx, r, "get", err)
+			return rc, info, nil
+		}
+	}
+	return nil, nil, err
+}
+
+// GetWithOptions читает объект с параметрами запроса из основного хранилища, при его ошибке —
+// из реплик, реализующих storage.OptionsGetter
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
+	getter, ok := s.Storage.(storage.OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	rc, info, err := getter.GetWithOptions(ctx, bucket, key, opts)
+	if !s.shouldFailover(ctx, err) {
+		return rc, info, err
+	}
+	for _, r := range s.replicas {
+		getter, ok := r.storage.(storage.OptionsGetter)
+		if !ok {
+			continue
+		}
+		rc, info, replicaErr := getter.GetWithOptions(ctx, bucket, key, opts)
+		if replicaErr == nil {
+			s.failover(ctx, r, "get", err)
+			return rc, info, nil
+		}
+	}
+	return nil, nil, err
+}
+
+// Exists проверяет наличие объекта в основном хранилище, при его ошибке — в репликах
+func (s *Storage) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	exists, err := s.Storage.Exists(ctx, bucket, key)
+	if !s.shouldFailover(ctx, err) {
+		return exists, err
+	}
+	for _, r := range s.replicas {
+		exists, replicaErr := r.storage.Exists(ctx, bucket, key)
+		if replicaErr == nil {
+			s.failover(ctx, r, "exists", err)
+			return exists, nil
+		}
+	}
+	return false, err
+}
+
+// List возвращает объекты основного хранилища, при его ошибке — реплики
+func (s *Storage) List(ctx context.Context, bucket string, opts *storage.ListOptions) (*storage.ListResult, error) {
+	result, err := s.Storage.List(ctx, bucket, opts)
+	if !s.shouldFailover(ctx, err) {
+		return result, err
+	}
+	for _, r := range s.replicas {
+		result, replicaErr := r.storage.List(ctx, bucket, opts)
+		if replicaErr == nil {
+			s.failover(ctx, r, "list", err)
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
+// GetFileHeader читает начало объекта из основного хранилища, при его ошибке — из реплик
+func (s *Storage) GetFileHeader(ctx context.Context, bucket, key string) ([]byte, error) {
+	header, err := s.Storage.GetFileHeader(ctx, bucket, key)
+	if !s.shouldFailover(ctx, err) {
+		return header, err
+	}
+	for _, r := range s.replicas {
+		header, replicaErr := r.storage.GetFileHeader(ctx, bucket, key)
+		if replicaErr == nil {
+			s.failover(ctx, r, "get_file_header", err)
+			return header, nil
+		}
+	}
+	return nil, err
+}
+
+// Stat возвращает метаданные объекта из основного хранилища, при его ошибке — из реплик
+func (s *Storage) Stat(ctx context.Context, bucket, key string) (*storage.ObjectInfo, error) {
+	info, err := s.Storage.Stat(ctx, bucket, key)
+	if !s.shouldFailover(ctx, err) {
+		return info, err
+	}
+	for _, r := range s.replicas {
+		info, replicaErr := r.storage.Stat(ctx, bucket, key)
+		if replicaErr == nil {
+			s.failover(ctx, r, "stat", err)
+			return info, nil
+		}
+	}
+	return nil, err
+}
+
+// shouldFailover сообщает, что чтение нужно повторить в репликах: отсутствие объекта
+// и отмена контекста — не отказ основного хранилища
+func (s *Storage) shouldFailover(ctx context.Context, err error) bool {
+	return err != nil && len(s.replicas) > 0 && !storage.IsNotFound(err) && ctx.Err() == nil
+}
+
+func (s *Storage) failover(ctx context.Context, r *replica, operation string, primaryErr error) {
+	s.logger.With("error", primaryErr).WarnContext(ctx, "primary storage failed, read served by replica",
+		"replica", r.name, "operation", operation)
+	s.count(ctx, s.failovers, r.name, operation)
+}
+
+// enqueue ставит операцию в очередь каждой реплики; при переполнении очереди
+// операция для реплики отбрасывается
+func (s *Storage) enqueue(ctx context.Context, t task) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.replicas {
+		if s.closed {
+			s.drop(ctx, r, t, "storage is closed")
+			continue
+		}
+		select {
+		case r.queue <- t:
+		default:
+			s.drop(ctx, r, t, "replication queue is full")
+		}
+	}
+}
+
+func (s *Storage) drop(ctx context.Context, r *replica, t task, reason string) {
+	s.logger.ErrorContext(ctx, "operation dropped from replication: "+reason,
+		"replica", r.name, "operation", t.operation, "bucket", t.bucket, "key", t.key)
+	s.count(ctx, s.dropped, r.name, t.operation)
+}
+
+// discard учитывает операции, оставшиеся в очереди после остановки репликации
+func (s *Storage) discard(r *replica) {
+	for t := range r.queue {
+		s.drop(context.Background(), r, t, "replication stopped")
+	}
+}
+
+// run реплицирует операции из очереди реплики до её закрытия или отмены ctx
+func (s *Storage) run(ctx context.Context, r *replica) {
+	defer s.wg.Done()
+	for {
+		select {
+		case t, ok := <-r.queue:
+			if !ok {
+				return
+			}
+			s.replicate(ctx, r, t)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// replicate выполняет операцию в реплике с повторами
+func (s *Storage) replicate(ctx context.Context, r *replica, t task) {
+	var err error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(s.retryBackoff << (attempt - 1)):
+			case <-ctx.Done():
+				s.drop(ctx, r, t, "replication stopped")
+				return
+			}
+		}
+		if err = s.apply(ctx, r, t); err == nil {
+			s.count(ctx, s.replicated, r.name, t.operation)
+			return
+		}
+		if ctx.Err() != nil {
+			s.drop(ctx, r, t, "replication stopped")
+			return
+		}
+	}
+	s.logger.With("error", err).ErrorContext(ctx, "failed to replicate operation",
+		"replica", r.name, "operation", t.operation, "bucket", t.bucket, "key", t.key, "attempts", s.maxRetries+1)
+	s.count(ctx, s.failed, r.name, t.operation)
+}
+
+// apply переносит в реплику текущее состояние объекта: копирует его из основного
+// хранилища или удаляет, если объекта там уже нет
+func (s *Storage) apply(ctx context.Context, r *replica, t task) error {
+	if t.operation == operationDelete {
+		return s.deleteReplica(ctx, r, t)
+	}
+
+	rc, info, err := s.Storage.Get(ctx, t.bucket, t.key)
+	if storage.IsNotFound(err) {
+		// объект удалён после записи, удаление уже в очереди
+		return s.deleteReplica(ctx, r, t)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read object from primary storage")
+	}
+	defer func() { _ = rc.Close() }()
+
+	if err := r.storage.Put(ctx, t.bucket, t.key, rc, putOptions(info)); err != nil {
+		return errors.Wrap(err, "failed to put object to replica")
+	}
+	return nil
+}
+
+func (s *Storage) deleteReplica(ctx context.Context, r *replica, t task) error {
+	err := r.storage.Delete(ctx, t.bucket, t.key)
+	if err != nil && !storage.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete object from replica")
+	}
+	return nil
+}
+
+// putOptions переносит метаданные объекта основного хранилища в параметры записи реплики
+func putOptions(info *storage.ObjectInfo) *storage.PutOptions {
+	if info == nil {
+		return nil
+	}
+	return &storage.PutOptions{
+		ContentType:        info.ContentType,
+		Metadata:           info.Metadata,
+		CacheControl:       info.CacheControl,
+		ContentDisposition: info.ContentDisposition,
+		ContentEncoding:    info.ContentEncoding,
+	}
+}
+
+func (s *Storage) counter(m metric.Meter, name, description string) metric.Int64Counter {
+	counter, err := m.Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		otel.Handle(errors.Wrapf(err, "failed to create %s counter", name))
+		return nil
+	}
+	return counter
+}
+
+func (s *Storage) count(ctx context.Context, counter metric.Int64Counter, replicaName, operation string) {
+	if counter == nil {
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("replica", replicaName),
+		attribute.String("operation", operation),
+	))
+}
@@ -0,0 +1,126 @@
+package replica
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/pure-golang/adapters/storage"
+	"github.com/pure-golang/adapters/storage/storagetest"
+)
+
+func counters(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					result[m.Name] += point.Value
+				}
+			}
+		}
+	}
+	return result
+}
+
+// TestStorage_Replication tests that writes reach the primary synchronously and replicas after Close drains the queue.
+func TestStorage_Replication(t *testing.T) {
+	t.Parallel()
+	primary, secondary := storagetest.NewMemoryStorage(), storagetest.NewMemoryStorage()
+	reader := sdkmetric.NewManualReader()
+	s := New(primary, []Replica{{Name: "s3", Storage: secondary}}, &Options{
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	require.NoError(t, s.Start())
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "bucket", "a.txt", strings.NewReader("a"), &storage.PutOptions{ContentType: "text/plain"}))
+	require.NoError(t, s.Put(ctx, "bucket", "b.txt", strings.NewReader("b"), nil))
+	require.NoError(t, s.Delete(ctx, "bucket", "b.txt"))
+	data, _, ok := primary.Object("bucket", "a.txt")
+	require.True(t, ok)
+	assert.Equal(t, "a", string(data))
+
+	require.NoError(t, s.Close())
+	data, info, ok := secondary.Object("bucket", "a.txt")
+	require.True(t, ok)
+	assert.Equal(t, "a", string(data))
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, []string{"a.txt"}, secondary.Keys("bucket"))
+	assert.True(t, primary.Closed())
+	assert.True(t, secondary.Closed())
+	assert.Equal(t, map[string]int64{"storage.replica.replicated_total": 3}, counters(t, reader))
+}
+
+// TestStorage_Failover tests that reads fall back to replicas on primary errors but not on missing objects.
+func TestStorage_Failover(t *testing.T) {
+	t.Parallel()
+	primary, secondary := storagetest.NewMemoryStorage(), storagetest.NewMemoryStorage()
+	ctx := context.Background()
+	require.NoError(t, secondary.Put(ctx, "bucket", "a.txt", strings.NewReader("replica"), nil))
+	reader := sdkmetric.NewManualReader()
+	s := New(primary, []Replica{{Name: "s3", Storage: secondary}}, &Options{
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+
+	_, _, err := s.Get(ctx, "bucket", "a.txt")
+	assert.True(t, storage.IsNotFound(err))
+
+	primary.SetError(errors.New("connection refused"))
+	rc, _, err := s.Get(ctx, "bucket", "a.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", string(data))
+
+	exists, err := s.Exists(ctx, "bucket", "a.txt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	secondary.SetError(errors.New("timeout"))
+	_, _, err = s.Get(ctx, "bucket", "a.txt")
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, map[string]int64{"storage.replica.failovers_total": 2}, counters(t, reader))
+}
+
+// TestStorage_Divergence tests that failed and dropped operations are counted as divergence.
+func TestStorage_Divergence(t *testing.T) {
+	t.Parallel()
+	primary, secondary := storagetest.NewMemoryStorage(), storagetest.NewMemoryStorage()
+	secondary.SetError(errors.New("access denied"))
+	reader := sdkmetric.NewManualReader()
+	s := New(primary, []Replica{{Name: "s3", Storage: secondary}}, &Options{
+		QueueSize:     1,
+		MaxRetries:    2,
+		RetryBackoff:  time.Millisecond,
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	ctx := context.Background()
+
+	// без Start вторая операция не помещается в очередь
+	require.NoError(t, s.Put(ctx, "bucket", "a.txt", strings.NewReader("a"), nil))
+	require.NoError(t, s.Put(ctx, "bucket", "b.txt", strings.NewReader("b"), nil))
+	require.NoError(t, s.Start())
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, 3, secondary.Calls("Put"))
+	assert.Equal(t, map[string]int64{
+		"storage.replica.dropped_total": 1,
+		"storage.replica.failed_total":  1,
+	}, counters(t, reader))
+
+	// после Close операции не реплицируются
+	require.NoError(t, s.Put(ctx, "bucket", "c.txt", strings.NewReader("c"), nil))
+	assert.Equal(t, int64(2), counters(t, reader)["storage.replica.dropped_total"])
+}
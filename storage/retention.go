@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetentionMode is the object lock retention mode.
+type RetentionMode string
+
+const (
+	// RetentionGovernance protects the object from deletion and overwrites, except by users
+	// allowed to bypass governance retention (RetentionOptions.BypassGovernance).
+	RetentionGovernance RetentionMode = "GOVERNANCE"
+	// RetentionCompliance protects the object until RetainUntil; nobody, including the root
+	// account, can shorten the period or delete the object version.
+	RetentionCompliance RetentionMode = "COMPLIANCE"
+)
+
+// Retention is a write-once-read-many (WORM) retention period of an object version.
+// The bucket must be created with object lock enabled.
+type Retention struct {
+	Mode        RetentionMode // Retention mode
+	RetainUntil time.Time     // The object version cannot be deleted or overwritten before this time
+}
+
+// Validate checks the mode and that RetainUntil is in the future.
+func (r *Retention) Validate() error {
+	if r.Mode != RetentionGovernance && r.Mode != RetentionCompliance {
+		return errors.New("invalid retention mode " + string(r.Mode))
+	}
+	if !r.RetainUntil.After(time.Now()) {
+		return errors.New("retain until date must be in the future")
+	}
+	return nil
+}
+
+// RetentionOptions contains optional parameters for PutObjectRetention.
+type RetentionOptions struct {
+	// BypassGovernance allows shortening or removing GOVERNANCE retention
+	// (requires the s3:BypassGovernanceRetention permission).
+	BypassGovernance bool
+}
+
+// ObjectLocker is implemented by storages supporting S3 object lock: retention periods
+// and legal holds that make object versions immutable for compliance workloads.
+// Operations on buckets without object lock return an error with CodeObjectLockNotEnabled.
+type ObjectLocker interface {
+	// PutObjectRetention sets the retention of the current object version. Retention can be
+	// extended at any time; shortening GOVERNANCE retention or removing it with a nil retention
+	// requires opts.BypassGovernance, COMPLIANCE retention cannot be shortened.
+	PutObjectRetention(ctx context.Context, bucket, key string, retention *Retention, opts *RetentionOptions) error
+
+	// GetObjectRetention returns the retention of the current object version, or nil if it has none.
+	GetObjectRetention(ctx context.Context, bucket, key string) (*Retention, error)
+
+	// PutObjectLegalHold places or removes a legal hold. A held object version cannot be deleted
+	// or overwritten regardless of retention until the hold is removed.
+	PutObjectLegalHold(ctx context.Context, bucket, key string, hold bool) error
+
+	// GetObjectLegalHold reports whether the current object version is under a legal hold.
+	GetObjectLegalHold(ctx context.Context, bucket, key string) (bool, error)
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRetention_Validate tests retention mode and date validation.
+func TestRetention_Validate(t *testing.T) {
+	t.Parallel()
+	future := time.Now().Add(time.Hour)
+
+	assert.NoError(t, (&Retention{Mode: RetentionGovernance, RetainUntil: future}).Validate())
+	assert.NoError(t, (&Retention{Mode: RetentionCompliance, RetainUntil: future}).Validate())
+	assert.EqualError(t, (&Retention{Mode: "governance", RetainUntil: future}).Validate(), "invalid retention mode governance")
+	assert.EqualError(t, (&Retention{Mode: RetentionCompliance}).Validate(), "retain until date must be in the future")
+}
+
+// TestIsObjectLockNotEnabled tests IsObjectLockNotEnabled with storage and sentinel errors.
+func TestIsObjectLockNotEnabled(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsObjectLockNotEnabled(&StorageError{Code: CodeObjectLockNotEnabled}))
+	assert.True(t, IsObjectLockNotEnabled(ErrObjectLockNotEnabled))
+	assert.False(t, IsObjectLockNotEnabled(&StorageError{Code: CodeNotFound}))
+	assert.False(t, IsObjectLockNotEnabled(nil))
+}
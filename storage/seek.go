@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrReaderClosed is returned by ObjectReader after Close.
+var ErrReaderClosed = errors.New("object reader is closed")
+
+// SeekableOpener is implemented by storages that open objects for random access,
+// e.g. to serve HTTP range requests for media with http.ServeContent.
+type SeekableOpener interface {
+	// OpenSeekable fetches the object metadata and returns a reader that fetches data lazily
+	// with ranged reads, so seeking does not download the skipped bytes.
+	// It returns an error with CodeNotFound if the object does not exist.
+	OpenSeekable(ctx context.Context, bucket, key string) (*ObjectReader, error)
+}
+
+// RangeOpenFunc opens the object body from offset to the end of the object.
+type RangeOpenFunc func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+// ObjectReader is an io.ReadSeekCloser over an object of known size.
+// Seek only moves the offset; the next Read opens the body at the offset with a ranged read,
+// and sequential reads reuse the open body. It is not safe for concurrent use.
+type ObjectReader struct {
+	ctx    context.Context
+	info   ObjectInfo
+	open   RangeOpenFunc
+	offset int64
+	body   io.ReadCloser
+	closed bool
+}
+
+var _ io.ReadSeekCloser = (*ObjectReader)(nil)
+
+// NewObjectReader creates a reader over the object described by info; open is called with ctx
+// on the first Read and on the first Read after a Seek to another offset.
+func NewObjectReader(ctx context.Context, info ObjectInfo, open RangeOpenFunc) *ObjectReader {
+	return &ObjectReader{ctx: ctx, info: info, open: open}
+}
+
+// Info returns the metadata of the object fetched on open.
+func (r *ObjectReader) Info() ObjectInfo {
+	return r.info
+}
+
+// Read reads from the current offset, opening the body with a ranged read if needed.
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, ErrReaderClosed
+	}
+	if r.offset >= r.info.Size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if r.body == nil {
+		body, err := r.open(r.ctx, r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if errors.Is(err, io.EOF) && r.offset < r.info.Size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Seek sets the offset for the next Read. Seeking past the end is allowed; Read then returns io.EOF.
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	if r.closed {
+		return 0, ErrReaderClosed
+	}
+
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.info.Size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if abs != r.offset {
+		r.closeBody()
+		r.offset = abs
+	}
+	return abs, nil
+}
+
+// Close closes the open body, if any.
+func (r *ObjectReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.closeBody()
+}
+
+func (r *ObjectReader) closeBody() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestObjectReader tests lazy ranged opens, Seek and Close of ObjectReader.
+func TestObjectReader(t *testing.T) {
+	t.Parallel()
+	data := []byte("0123456789")
+	var opens []int64
+	r := NewObjectReader(context.Background(), ObjectInfo{Key: "a.bin", Size: int64(len(data))}, func(_ context.Context, offset int64) (io.ReadCloser, error) {
+		opens = append(opens, offset)
+		return io.NopCloser(bytes.NewReader(data[offset:])), nil
+	})
+	assert.Empty(t, opens, "the body is opened on the first Read")
+
+	buf := make([]byte, 3)
+	_, err := io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "012", string(buf))
+
+	pos, err := r.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pos)
+
+	pos, err = r.Seek(-4, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), pos)
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "6789", string(rest))
+	assert.Equal(t, []int64{0, 6}, opens)
+
+	_, err = r.Seek(20, io.SeekStart)
+	require.NoError(t, err)
+	n, err := r.Read(buf)
+	assert.Zero(t, n)
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = r.Seek(-1, io.SeekStart)
+	assert.EqualError(t, err, "negative position")
+
+	require.NoError(t, r.Close())
+	_, err = r.Read(buf)
+	assert.ErrorIs(t, err, ErrReaderClosed)
+}
+
+// TestObjectReader_Errors tests errors of the range open function and truncated bodies.
+func TestObjectReader_Errors(t *testing.T) {
+	t.Parallel()
+	openErr := errors.New("precondition failed")
+	r := NewObjectReader(context.Background(), ObjectInfo{Size: 10}, func(context.Context, int64) (io.ReadCloser, error) {
+		return nil, openErr
+	})
+	_, err := r.Read(make([]byte, 4))
+	assert.ErrorIs(t, err, openErr)
+
+	r = NewObjectReader(context.Background(), ObjectInfo{Size: 10}, func(context.Context, int64) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("short"))), nil
+	})
+	_, err = io.ReadAll(r)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -14,12 +14,60 @@ type ObjectInfo struct {
 	ETag         string            // Entity tag for versioning
 	ContentType  string            // Content type
 	Metadata     map[string]string // User-defined metadata
+
+	CacheControl       string // Cache-Control header
+	ContentDisposition string // Content-Disposition header
+	ContentEncoding    string // Content-Encoding header
+
+	VersionID string    // Version ID, empty if the bucket is not versioned
+	Checksums Checksums // Additional checksums stored with the object
+
+	// Object lock state returned by Stat and Get of storages implementing ObjectLocker.
+	Retention *Retention // Retention of the object version, nil if none
+	LegalHold bool       // Whether the object version is under a legal hold
+}
+
+// Checksums contains the additional checksums of an object as base64-encoded values,
+// as returned by S3 in x-amz-checksum-* headers. Fields are empty for algorithms the
+// object was not uploaded with. Checksums of multipart objects are checksums of the
+// part checksums followed by "-<parts>".
+type Checksums struct {
+	CRC32     string // x-amz-checksum-crc32
+	CRC32C    string // x-amz-checksum-crc32c
+	CRC64NVME string // x-amz-checksum-crc64nvme
+	SHA1      string // x-amz-checksum-sha1
+	SHA256    string // x-amz-checksum-sha256
+}
+
+// IsZero reports whether no checksum is set.
+func (c Checksums) IsZero() bool {
+	return c == Checksums{}
 }
 
 // PutOptions contains optional parameters for Put operation.
 type PutOptions struct {
-	ContentType string            // MIME type
-	Metadata    map[string]string // User metadata
+	ContentType  string            // MIME type
+	Metadata     map[string]string // User metadata
+	Headers      map[string]string // Extra HTTP headers, e.g. Expires or x-amz-storage-class
+	RequestPayer bool              // Requester pays for the request (requester-pays buckets)
+
+	// Typed headers take precedence over the same keys in Headers.
+	CacheControl       string // Cache-Control, e.g. "public, max-age=31536000, immutable"
+	ContentDisposition string // Content-Disposition, e.g. `attachment; filename="report.pdf"`
+	ContentEncoding    string // Content-Encoding, e.g. "gzip"
+
+	Progress ProgressFunc // Reports bytes read from the reader by Put
+
+	// Object lock, see ObjectLocker. The bucket must have object lock enabled.
+	Retention *Retention // Retention of the new object version
+	LegalHold bool       // Place a legal hold on the new object version
+}
+
+// GetOptions contains optional parameters for GetWithOptions operation.
+type GetOptions struct {
+	Headers      map[string]string // Extra HTTP headers, e.g. If-None-Match
+	RequestPayer bool              // Requester pays for the request (requester-pays buckets)
+	Progress     ProgressFunc      // Reports bytes read from the returned body
 }
 
 // ListOptions contains optional parameters for List operation.
@@ -27,6 +75,19 @@ type ListOptions struct {
 	Prefix    string // Object key prefix
 	Recursive bool   // Whether to list recursively
 	MaxKeys   int    // Maximum number of keys to return
+
+	// Filters are applied by the adapter to listed objects, see Match.
+	ModifiedAfter  time.Time         // Only objects modified after this time
+	ModifiedBefore time.Time         // Only objects modified before this time
+	MinSize        int64             // Minimum object size in bytes, 0 means no limit
+	MaxSize        int64             // Maximum object size in bytes, 0 means no limit
+	Metadata       map[string]string // User metadata that objects must have
+	// StatMetadata fetches metadata with a stat call per object when the listing
+	// does not include it (e.g. AWS S3). Only used with a Metadata filter.
+	StatMetadata bool
+
+	SortBy   ListSort // Order of the result, SortNone keeps the backend order
+	SortDesc bool     // Descending order
 }
 
 // ListResult contains the result of a List operation.
@@ -75,6 +136,10 @@ type Storage interface {
 	// Exists checks if an object exists in the specified bucket.
 	Exists(ctx context.Context, bucket, key string) (bool, error)
 
+	// Stat returns metadata of an object without the body,
+	// or an error with CodeNotFound if it does not exist.
+	Stat(ctx context.Context, bucket, key string) (*ObjectInfo, error)
+
 	// List lists objects in the specified bucket with optional prefix.
 	List(ctx context.Context, bucket string, opts *ListOptions) (*ListResult, error)
 
@@ -101,3 +166,18 @@ type Storage interface {
 
 	io.Closer
 }
+
+// OptionsGetter is implemented by storages that accept GetOptions.
+type OptionsGetter interface {
+	// GetWithOptions retrieves an object like Get, applying opts to the request.
+	GetWithOptions(ctx context.Context, bucket, key string, opts *GetOptions) (io.ReadCloser, *ObjectInfo, error)
+}
+
+// PartPresigner is implemented by storages that presign multipart part uploads,
+// so clients upload parts directly while the server creates and completes the upload.
+type PartPresigner interface {
+	// GetPresignedUploadPartURL generates a presigned PUT URL for part partNumber (1-10000)
+	// of the multipart upload uploadID. The ETag of the part is returned in the ETag response
+	// header and must be passed back for CompleteMultipartUpload.
+	GetPresignedUploadPartURL(ctx context.Context, bucket, key, uploadID string, partNumber int32, expiry time.Duration) (string, error)
+}
@@ -0,0 +1,39 @@
+// Package storagetest содержит контрактные тесты интерфейса storage.Storage,
+// хранилище в памяти для модульных тестов и контейнер MinIO для интеграционных тестов.
+//
+// Новый адаптер (s3, gcs, azblob, fs) подтверждает соответствие контракту одним вызовом:
+//
+//	func TestContract(t *testing.T) {
+//	    s := newMyStorage(t)
+//	    storagetest.RunStorageTests(t, s, "bucket")
+//	}
+//
+// [MemoryStorage] — хранилище в памяти для модульных тестов декораторов и сервисов поверх storage;
+// проходит контрактные тесты, считает вызовы по методам ([MemoryStorage.Calls]) и возвращает
+// заданные ошибки ([MemoryStorage.SetError]):
+//
+//	inner := storagetest.NewMemoryStorage()
+//	inner.SetError(errors.New("backend unavailable"), "Put")
+//
+// Контейнер MinIO для тестов адаптеров и сервисов:
+//
+//	m := storagetest.StartMinIO(t) // пропускает тест в режиме -short
+//	stor, bucket := m.NewStorage(t)
+//
+// Проверяемый контракт:
+//   - Put/Get — содержимое, размер, ContentType, ETag, метаданные, пустые объекты, перезапись
+//   - Get и GetFileHeader несуществующего объекта — ошибка [storage.IsNotFound]
+//   - Exists, Delete (повторное удаление не является ошибкой)
+//   - Stat — метаданные без тела объекта
+//   - List — рекурсивный и нерекурсивный обход, фильтр по префиксу
+//   - List с фильтрами — размер, время изменения, метаданные (со StatMetadata), сортировка
+//   - GetFileHeader — первые 4096 байт объекта
+//   - Multipart — создание, загрузка частей, сборка, отмена, ListMultipartUploads
+//   - Presigned URL — загрузка и скачивание по ссылке, отказ для неподдерживаемых методов
+//   - Presigned URL частей multipart-загрузки, если адаптер реализует [storage.PartPresigner]
+//   - Чтение с произвольной позиции (OpenSeekable), если адаптер реализует [storage.SeekableOpener]
+//   - Query (S3 Select) по CSV-объекту, если адаптер реализует [storage.Querier] и backend его поддерживает
+//
+// Каждый запуск работает под собственным префиксом ключей, поэтому bucket можно переиспользовать.
+// Части multipart-загрузки, кроме последней, имеют размер [MinPartSize].
+package storagetest
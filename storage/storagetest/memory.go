@@ -0,0 +1,395 @@
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// MemoryStorage is an in-memory storage.Storage for unit tests of decorators and services
+// built on top of storage. It passes RunStorageTests.
+//
+// ETags are MD5 checksums of the content, so a rewrite with other content changes the ETag.
+// Calls counts calls per method and SetError fails chosen methods, which lets tests check
+// caching and failure handling. Presigned URLs point to an httptest server that is started
+// on first use and stopped by Close.
+type MemoryStorage struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+	uploads map[string]*memoryUpload
+	seq     int
+	calls   map[string]int
+	errs    map[string]error
+	err     error
+	closed  bool
+	server  *httptest.Server
+}
+
+type memoryObject struct {
+	data []byte
+	info storage.ObjectInfo
+}
+
+type memoryUpload struct {
+	upload storage.MultipartUpload
+	opts   storage.PutOptions
+	parts  map[int32][]byte
+}
+
+var _ storage.Storage = (*MemoryStorage)(nil)
+
+// NewMemoryStorage creates an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		objects: make(map[string]memoryObject),
+		uploads: make(map[string]*memoryUpload),
+		calls:   make(map[string]int),
+		errs:    make(map[string]error),
+	}
+}
+
+// SetError makes the given methods, e.g. "Put" or "Get", fail with err.
+// Without methods every method but Close fails. A nil err removes the failure.
+func (m *MemoryStorage) SetError(err error, methods ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(methods) == 0 {
+		m.err = err
+		clear(m.errs)
+		return
+	}
+	for _, method := range methods {
+		m.errs[method] = err
+	}
+}
+
+// Calls returns the number of calls of the method, including failed ones.
+func (m *MemoryStorage) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+// Object returns the content and metadata of a stored object without counting a call.
+func (m *MemoryStorage) Object(bucket, key string) ([]byte, storage.ObjectInfo, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[bucket+"/"+key]
+	if !ok {
+		return nil, storage.ObjectInfo{}, false
+	}
+	return slices.Clone(obj.data), cloneInfo(obj.info), true
+}
+
+// Keys returns the sorted keys of the bucket without counting a call.
+func (m *MemoryStorage) Keys(bucket string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := []string{}
+	for path := range m.objects {
+		if key, ok := strings.CutPrefix(path, bucket+"/"); ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Closed reports whether Close has been called.
+func (m *MemoryStorage) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// call counts a call of the method and returns the error set for it.
+func (m *MemoryStorage) call(method string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[method]++
+	if err, ok := m.errs[method]; ok {
+		return err
+	}
+	if method == "Close" {
+		return nil
+	}
+	return m.err
+}
+
+func (m *MemoryStorage) Put(_ context.Context, bucket, key string, reader io.Reader, opts *storage.PutOptions) error {
+	if err := m.call("Put"); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	m.store(bucket, key, data, opts)
+	return nil
+}
+
+func (m *MemoryStorage) store(bucket, key string, data []byte, opts *storage.PutOptions) storage.ObjectInfo {
+	if opts == nil {
+		opts = &storage.PutOptions{}
+	}
+	sum := md5.Sum(data)
+	info := storage.ObjectInfo{
+		Key:                key,
+		Size:               int64(len(data)),
+		LastModified:       time.Now(),
+		ETag:               hex.EncodeToString(sum[:]),
+		ContentType:        opts.ContentType,
+		Metadata:           maps.Clone(opts.Metadata),
+		CacheControl:       opts.CacheControl,
+		ContentDisposition: opts.ContentDisposition,
+		ContentEncoding:    opts.ContentEncoding,
+	}
+	m.mu.Lock()
+	m.objects[bucket+"/"+key] = memoryObject{data: data, info: info}
+	m.mu.Unlock()
+	return cloneInfo(info)
+}
+
+func (m *MemoryStorage) Get(_ context.Context, bucket, key string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	if err := m.call("Get"); err != nil {
+		return nil, nil, err
+	}
+	obj, err := m.object(bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), &obj.info, nil
+}
+
+// object returns a copy of the object or a not found error.
+func (m *MemoryStorage) object(bucket, key string) (memoryObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[bucket+"/"+key]
+	if !ok {
+		return memoryObject{}, &storage.StorageError{Code: storage.CodeNotFound, Message: "object not found", Bucket: bucket, Key: key, Err: storage.ErrNotFound}
+	}
+	return memoryObject{data: obj.data, info: cloneInfo(obj.info)}, nil
+}
+
+func (m *MemoryStorage) Delete(_ context.Context, bucket, key string) error {
+	if err := m.call("Delete"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	delete(m.objects, bucket+"/"+key)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStorage) Exists(_ context.Context, bucket, key string) (bool, error) {
+	if err := m.call("Exists"); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	_, ok := m.objects[bucket+"/"+key]
+	m.mu.Unlock()
+	return ok, nil
+}
+
+func (m *MemoryStorage) Stat(_ context.Context, bucket, key string) (*storage.ObjectInfo, error) {
+	if err := m.call("Stat"); err != nil {
+		return nil, err
+	}
+	obj, err := m.object(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return &obj.info, nil
+}
+
+func (m *MemoryStorage) List(_ context.Context, bucket string, opts *storage.ListOptions) (*storage.ListResult, error) {
+	if err := m.call("List"); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &storage.ListOptions{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := &storage.ListResult{}
+	for path, obj := range m.objects {
+		key, ok := strings.CutPrefix(path, bucket+"/")
+		if !ok || !strings.HasPrefix(key, opts.Prefix) {
+			continue
+		}
+		if !opts.Recursive && strings.Contains(strings.TrimPrefix(key, opts.Prefix), "/") {
+			continue
+		}
+		if opts.Match(obj.info) {
+			result.Objects = append(result.Objects, cloneInfo(obj.info))
+		}
+	}
+	storage.SortObjects(result.Objects, opts.SortBy, opts.SortDesc)
+	return result, nil
+}
+
+func (m *MemoryStorage) GetPresignedURL(_ context.Context, bucket, key string, opts *storage.PresignedURLOptions) (string, error) {
+	if err := m.call("GetPresignedURL"); err != nil {
+		return "", err
+	}
+	if opts.Method != http.MethodGet && opts.Method != http.MethodPut {
+		return "", fmt.Errorf("unsupported HTTP method: %s", opts.Method)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		m.server = httptest.NewServer(http.HandlerFunc(m.servePresigned))
+	}
+	return m.server.URL + "/" + bucket + "/" + key, nil
+}
+
+func (m *MemoryStorage) servePresigned(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.store(bucket, key, data, nil)
+	case http.MethodGet:
+		obj, err := m.object(bucket, key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(obj.data)
+	}
+}
+
+func (m *MemoryStorage) GetFileHeader(_ context.Context, bucket, key string) ([]byte, error) {
+	if err := m.call("GetFileHeader"); err != nil {
+		return nil, err
+	}
+	obj, err := m.object(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(obj.data[:min(len(obj.data), 4096)]), nil
+}
+
+func (m *MemoryStorage) CreateMultipartUpload(_ context.Context, bucket, key string, opts *storage.PutOptions) (*storage.MultipartUpload, error) {
+	if err := m.call("CreateMultipartUpload"); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	u := &memoryUpload{
+		upload: storage.MultipartUpload{UploadID: fmt.Sprint(m.seq), Key: key, Bucket: bucket, Initiated: time.Now()},
+		parts:  make(map[int32][]byte),
+	}
+	if opts != nil {
+		u.opts = *opts
+	}
+	m.uploads[u.upload.UploadID] = u
+	upload := u.upload
+	return &upload, nil
+}
+
+func (m *MemoryStorage) UploadPart(_ context.Context, bucket, key, uploadID string, partNumber int32, reader io.Reader) (*storage.UploadedPart, error) {
+	if err := m.call("UploadPart"); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.uploads[uploadID]
+	if !ok {
+		return nil, &storage.StorageError{Code: storage.CodeNotFound, Message: "upload not found", Bucket: bucket, Key: key, Err: storage.ErrNotFound}
+	}
+	u.parts[partNumber] = data
+	sum := md5.Sum(data)
+	return &storage.UploadedPart{PartNumber: partNumber, ETag: hex.EncodeToString(sum[:]), Size: int64(len(data))}, nil
+}
+
+func (m *MemoryStorage) CompleteMultipartUpload(_ context.Context, bucket, key, uploadID string, opts *storage.CompleteMultipartUploadOptions) (*storage.ObjectInfo, error) {
+	if err := m.call("CompleteMultipartUpload"); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	u, ok := m.uploads[uploadID]
+	delete(m.uploads, uploadID)
+	m.mu.Unlock()
+	if !ok {
+		return nil, &storage.StorageError{Code: storage.CodeNotFound, Message: "upload not found", Bucket: bucket, Key: key, Err: storage.ErrNotFound}
+	}
+
+	var data []byte
+	if opts != nil {
+		for _, part := range opts.Parts {
+			data = append(data, u.parts[part.PartNumber]...)
+		}
+	}
+	info := m.store(bucket, key, data, &u.opts)
+	return &info, nil
+}
+
+func (m *MemoryStorage) AbortMultipartUpload(_ context.Context, _, _, uploadID string) error {
+	if err := m.call("AbortMultipartUpload"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	delete(m.uploads, uploadID)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStorage) ListMultipartUploads(_ context.Context, bucket string) ([]storage.MultipartUpload, error) {
+	if err := m.call("ListMultipartUploads"); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var uploads []storage.MultipartUpload
+	for _, u := range m.uploads {
+		if u.upload.Bucket == bucket {
+			uploads = append(uploads, u.upload)
+		}
+	}
+	slices.SortFunc(uploads, func(a, b storage.MultipartUpload) int { return strings.Compare(a.UploadID, b.UploadID) })
+	return uploads, nil
+}
+
+// Close marks the storage closed and stops the presigned URL server.
+func (m *MemoryStorage) Close() error {
+	err := m.call("Close")
+	m.mu.Lock()
+	m.closed = true
+	server := m.server
+	m.server = nil
+	m.mu.Unlock()
+	// the server is closed without the lock: Close waits for handlers that take it
+	if server != nil {
+		server.Close()
+	}
+	return err
+}
+
+// cloneInfo copies info so that callers cannot change the stored metadata.
+func cloneInfo(info storage.ObjectInfo) storage.ObjectInfo {
+	info.Metadata = maps.Clone(info.Metadata)
+	return info
+}
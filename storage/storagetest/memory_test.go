@@ -0,0 +1,41 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryStorage_SetError tests failure injection, call counting and Close.
+func TestMemoryStorage_SetError(t *testing.T) {
+	t.Parallel()
+	s := NewMemoryStorage()
+	ctx := context.Background()
+	errDown := errors.New("backend unavailable")
+
+	s.SetError(errDown, "Put")
+	assert.ErrorIs(t, s.Put(ctx, "bucket", "a.txt", strings.NewReader("a"), nil), errDown)
+	_, err := s.Exists(ctx, "bucket", "a.txt")
+	require.NoError(t, err)
+
+	s.SetError(nil, "Put")
+	require.NoError(t, s.Put(ctx, "bucket", "a.txt", strings.NewReader("a"), nil))
+	assert.Equal(t, 2, s.Calls("Put"))
+	assert.Equal(t, []string{"a.txt"}, s.Keys("bucket"))
+
+	s.SetError(errDown)
+	_, _, err = s.Get(ctx, "bucket", "a.txt")
+	assert.ErrorIs(t, err, errDown)
+	require.NoError(t, s.Close())
+	assert.True(t, s.Closed())
+
+	s.SetError(nil)
+	data, info, ok := s.Object("bucket", "a.txt")
+	require.True(t, ok)
+	assert.Equal(t, "a", string(data))
+	assert.Equal(t, "0cc175b9c0f1b6a831c399e269772661", info.ETag)
+}
@@ -0,0 +1,94 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	miniogo "github.com/minio/minio-go/v7"
+	tcminio "github.com/testcontainers/testcontainers-go/modules/minio"
+
+	"github.com/pure-golang/adapters/storage/minio"
+)
+
+// Default MinIO container settings.
+const (
+	DefaultMinIOImage = "minio/minio:latest"
+	DefaultAccessKey  = "minioadmin"
+	DefaultSecretKey  = "minioadmin"
+)
+
+// MinIO is a running MinIO container.
+type MinIO struct {
+	cfg minio.Config
+	seq atomic.Int64
+}
+
+// StartMinIO starts a MinIO container and terminates it when the test finishes.
+// The test is skipped in -short mode.
+func StartMinIO(t testing.TB) *MinIO {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	ctx := context.Background()
+
+	container, err := tcminio.Run(ctx, DefaultMinIOImage,
+		tcminio.WithUsername(DefaultAccessKey),
+		tcminio.WithPassword(DefaultSecretKey),
+	)
+	if err != nil {
+		t.Fatalf("failed to start minio: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("failed to terminate minio: %v", err)
+		}
+	})
+
+	endpoint, err := container.Endpoint(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to get minio endpoint: %v", err)
+	}
+
+	return &MinIO{cfg: minio.Config{
+		Endpoint:  strings.TrimPrefix(endpoint, "http://"),
+		AccessKey: DefaultAccessKey,
+		SecretKey: DefaultSecretKey,
+		Region:    "us-east-1",
+	}}
+}
+
+// Config returns the adapter configuration for the container.
+func (m *MinIO) Config() minio.Config {
+	return m.cfg
+}
+
+// NewBucket creates a bucket with a unique name and returns the name.
+func (m *MinIO) NewBucket(t testing.TB) string {
+	t.Helper()
+	client, err := minio.NewClient(m.cfg)
+	if err != nil {
+		t.Fatalf("failed to create minio client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	bucket := fmt.Sprintf("storagetest-%d", m.seq.Add(1))
+	if err := client.GetMinioClient().MakeBucket(context.Background(), bucket, miniogo.MakeBucketOptions{Region: m.cfg.Region}); err != nil {
+		t.Fatalf("failed to create bucket %s: %v", bucket, err)
+	}
+	return bucket
+}
+
+// NewStorage connects the minio adapter to the container and creates a bucket for it.
+func (m *MinIO) NewStorage(t testing.TB) (*minio.Storage, string) {
+	t.Helper()
+	client, err := minio.NewClient(m.cfg)
+	if err != nil {
+		t.Fatalf("failed to create minio storage client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return minio.NewStorage(client, nil), m.NewBucket(t)
+}
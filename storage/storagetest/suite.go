@@ -0,0 +1,466 @@
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+// MinPartSize is the smallest part size accepted by S3-compatible backends for all parts but the last.
+const MinPartSize = 5 * 1024 * 1024
+
+// RunStorageTests runs the storage.Storage contract against s using an existing bucket.
+// Every run works under its own key prefix, so the bucket may be shared between runs.
+func RunStorageTests(t *testing.T, s storage.Storage, bucket string) {
+	t.Helper()
+	prefix := fmt.Sprintf("storagetest-%d/", time.Now().UnixNano())
+
+	t.Run("PutGet", func(t *testing.T) { testPutGet(t, s, bucket, prefix+"put-get/") })
+	t.Run("Overwrite", func(t *testing.T) { testOverwrite(t, s, bucket, prefix+"overwrite/") })
+	t.Run("GetNotFound", func(t *testing.T) { testGetNotFound(t, s, bucket, prefix+"not-found/") })
+	t.Run("Exists", func(t *testing.T) { testExists(t, s, bucket, prefix+"exists/") })
+	t.Run("Stat", func(t *testing.T) { testStat(t, s, bucket, prefix+"stat/") })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, s, bucket, prefix+"delete/") })
+	t.Run("List", func(t *testing.T) { testList(t, s, bucket, prefix+"list/") })
+	t.Run("ListFilters", func(t *testing.T) { testListFilters(t, s, bucket, prefix+"list-filters/") })
+	t.Run("GetFileHeader", func(t *testing.T) { testGetFileHeader(t, s, bucket, prefix+"file-header/") })
+	t.Run("Multipart", func(t *testing.T) { testMultipart(t, s, bucket, prefix+"multipart/") })
+	t.Run("AbortMultipart", func(t *testing.T) { testAbortMultipart(t, s, bucket, prefix+"abort/") })
+	t.Run("PresignedURL", func(t *testing.T) { testPresignedURL(t, s, bucket, prefix+"presigned/") })
+	t.Run("PresignedUploadPart", func(t *testing.T) { testPresignedUploadPart(t, s, bucket, prefix+"presigned-part/") })
+	t.Run("Query", func(t *testing.T) { testQuery(t, s, bucket, prefix+"query/") })
+	t.Run("OpenSeekable", func(t *testing.T) { testOpenSeekable(t, s, bucket, prefix+"seekable/") })
+}
+
+func testPutGet(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.txt"
+	content := []byte("hello, storage")
+
+	err := s.Put(ctx, bucket, key, bytes.NewReader(content), &storage.PutOptions{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"owner": "storagetest"},
+	})
+	require.NoError(t, err)
+
+	data, info := get(t, s, bucket, key)
+	assert.Equal(t, content, data)
+	assert.Equal(t, key, info.Key)
+	assert.Equal(t, int64(len(content)), info.Size)
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.NotEmpty(t, info.ETag)
+	assert.False(t, info.LastModified.IsZero())
+	owner, _ := storage.MetadataValue(info.Metadata, "owner")
+	assert.Equal(t, "storagetest", owner)
+
+	// Put without options and with an empty body
+	empty := prefix + "empty"
+	require.NoError(t, s.Put(ctx, bucket, empty, bytes.NewReader(nil), nil))
+	data, info = get(t, s, bucket, empty)
+	assert.Empty(t, data)
+	assert.Equal(t, int64(0), info.Size)
+}
+
+func testOverwrite(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.txt"
+
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("first version"), nil))
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("second"), nil))
+
+	data, info := get(t, s, bucket, key)
+	assert.Equal(t, "second", string(data))
+	assert.Equal(t, int64(len("second")), info.Size)
+}
+
+func testGetNotFound(t *testing.T, s storage.Storage, bucket, prefix string) {
+	rc, info, err := s.Get(context.Background(), bucket, prefix+"missing")
+	require.Error(t, err)
+	assert.True(t, storage.IsNotFound(err), "Get of a missing object must return a not found error, got %v", err)
+	assert.Nil(t, rc)
+	assert.Nil(t, info)
+}
+
+func testExists(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.txt"
+
+	exists, err := s.Exists(ctx, bucket, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("data"), nil))
+
+	exists, err = s.Exists(ctx, bucket, key)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func testStat(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.txt"
+
+	_, err := s.Stat(ctx, bucket, key)
+	require.Error(t, err)
+	assert.True(t, storage.IsNotFound(err), "Stat of a missing object must return a not found error, got %v", err)
+
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("data"), &storage.PutOptions{ContentType: "text/plain"}))
+
+	info, err := s.Stat(ctx, bucket, key)
+	require.NoError(t, err)
+	_, getInfo := get(t, s, bucket, key)
+	assert.Equal(t, key, info.Key)
+	assert.Equal(t, int64(4), info.Size)
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, getInfo.ETag, info.ETag)
+}
+
+func testDelete(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.txt"
+
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("data"), nil))
+	require.NoError(t, s.Delete(ctx, bucket, key))
+
+	exists, err := s.Exists(ctx, bucket, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Delete is idempotent
+	assert.NoError(t, s.Delete(ctx, bucket, key))
+}
+
+func testList(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	keys := []string{prefix + "a.txt", prefix + "b.txt", prefix + "nested/c.txt"}
+	for _, key := range keys {
+		require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader(key), nil))
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		result, err := s.List(ctx, bucket, &storage.ListOptions{Prefix: prefix, Recursive: true})
+		require.NoError(t, err)
+		assert.Equal(t, keys, objectKeys(result))
+		for _, obj := range result.Objects {
+			assert.Equal(t, int64(len(obj.Key)), obj.Size, "size of %s", obj.Key)
+		}
+	})
+
+	t.Run("non-recursive", func(t *testing.T) {
+		result, err := s.List(ctx, bucket, &storage.ListOptions{Prefix: prefix})
+		require.NoError(t, err)
+		assert.Equal(t, keys[:2], objectKeys(result), "nested objects must not be listed")
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		result, err := s.List(ctx, bucket, &storage.ListOptions{Prefix: prefix + "nested/", Recursive: true})
+		require.NoError(t, err)
+		assert.Equal(t, keys[2:], objectKeys(result))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := s.List(ctx, bucket, &storage.ListOptions{Prefix: prefix + "missing/", Recursive: true})
+		require.NoError(t, err)
+		assert.Empty(t, result.Objects)
+	})
+}
+
+func testListFilters(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	objects := []struct {
+		key   string
+		size  int
+		owner string
+	}{
+		{key: prefix + "small.txt", size: 10, owner: "alice"},
+		{key: prefix + "medium.txt", size: 100, owner: "bob"},
+		{key: prefix + "large.txt", size: 1000, owner: "alice"},
+	}
+	for _, obj := range objects {
+		err := s.Put(ctx, bucket, obj.key, bytes.NewReader(make([]byte, obj.size)), &storage.PutOptions{
+			Metadata: map[string]string{"owner": obj.owner},
+		})
+		require.NoError(t, err)
+	}
+
+	list := func(t *testing.T, opts storage.ListOptions) []string {
+		t.Helper()
+		opts.Prefix = prefix
+		opts.Recursive = true
+		result, err := s.List(ctx, bucket, &opts)
+		require.NoError(t, err)
+		keys := make([]string, 0, len(result.Objects))
+		for _, obj := range result.Objects {
+			keys = append(keys, obj.Key)
+		}
+		return keys
+	}
+
+	t.Run("size", func(t *testing.T) {
+		keys := list(t, storage.ListOptions{MinSize: 50, MaxSize: 500})
+		assert.Equal(t, []string{prefix + "medium.txt"}, keys)
+	})
+
+	t.Run("modified time", func(t *testing.T) {
+		past, future := time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour)
+		assert.Len(t, list(t, storage.ListOptions{ModifiedAfter: past, ModifiedBefore: future}), 3)
+		assert.Empty(t, list(t, storage.ListOptions{ModifiedAfter: future}))
+		assert.Empty(t, list(t, storage.ListOptions{ModifiedBefore: past}))
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		keys := list(t, storage.ListOptions{
+			Metadata:     map[string]string{"owner": "alice"},
+			StatMetadata: true,
+			SortBy:       storage.SortKey,
+		})
+		assert.Equal(t, []string{prefix + "large.txt", prefix + "small.txt"}, keys)
+	})
+
+	t.Run("sort", func(t *testing.T) {
+		keys := list(t, storage.ListOptions{SortBy: storage.SortSize, SortDesc: true})
+		assert.Equal(t, []string{prefix + "large.txt", prefix + "medium.txt", prefix + "small.txt"}, keys)
+
+		keys = list(t, storage.ListOptions{SortBy: storage.SortKey, SortDesc: true})
+		assert.Equal(t, []string{prefix + "small.txt", prefix + "medium.txt", prefix + "large.txt"}, keys)
+	})
+}
+
+func testGetFileHeader(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+
+	small := prefix + "small.txt"
+	require.NoError(t, s.Put(ctx, bucket, small, strings.NewReader("short"), nil))
+	header, err := s.GetFileHeader(ctx, bucket, small)
+	require.NoError(t, err)
+	assert.Equal(t, "short", string(header))
+
+	large := prefix + "large.bin"
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	require.NoError(t, s.Put(ctx, bucket, large, bytes.NewReader(content), nil))
+	header, err = s.GetFileHeader(ctx, bucket, large)
+	require.NoError(t, err)
+	assert.Equal(t, content[:4096], header)
+
+	_, err = s.GetFileHeader(ctx, bucket, prefix+"missing")
+	assert.True(t, storage.IsNotFound(err), "GetFileHeader of a missing object must return a not found error, got %v", err)
+}
+
+func testMultipart(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.bin"
+
+	upload, err := s.CreateMultipartUpload(ctx, bucket, key, &storage.PutOptions{ContentType: "application/octet-stream"})
+	require.NoError(t, err)
+	require.NotEmpty(t, upload.UploadID)
+	assert.Equal(t, key, upload.Key)
+
+	uploads, err := s.ListMultipartUploads(ctx, bucket)
+	require.NoError(t, err)
+	assert.Contains(t, uploadIDs(uploads), upload.UploadID)
+
+	part1 := bytes.Repeat([]byte("A"), MinPartSize)
+	part2 := []byte("tail")
+
+	uploaded1, err := s.UploadPart(ctx, bucket, key, upload.UploadID, 1, bytes.NewReader(part1))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), uploaded1.PartNumber)
+	assert.NotEmpty(t, uploaded1.ETag)
+
+	uploaded2, err := s.UploadPart(ctx, bucket, key, upload.UploadID, 2, bytes.NewReader(part2))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), uploaded2.PartNumber)
+
+	info, err := s.CompleteMultipartUpload(ctx, bucket, key, upload.UploadID, &storage.CompleteMultipartUploadOptions{
+		Parts: []storage.UploadedPart{*uploaded1, *uploaded2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(part1)+len(part2)), info.Size)
+
+	data, _ := get(t, s, bucket, key)
+	assert.Equal(t, append(part1, part2...), data)
+
+	uploads, err = s.ListMultipartUploads(ctx, bucket)
+	require.NoError(t, err)
+	assert.NotContains(t, uploadIDs(uploads), upload.UploadID, "completed upload must not be listed")
+}
+
+func testAbortMultipart(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.bin"
+
+	upload, err := s.CreateMultipartUpload(ctx, bucket, key, nil)
+	require.NoError(t, err)
+	_, err = s.UploadPart(ctx, bucket, key, upload.UploadID, 1, strings.NewReader("part"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.AbortMultipartUpload(ctx, bucket, key, upload.UploadID))
+
+	exists, err := s.Exists(ctx, bucket, key)
+	require.NoError(t, err)
+	assert.False(t, exists, "aborted upload must not create the object")
+
+	uploads, err := s.ListMultipartUploads(ctx, bucket)
+	require.NoError(t, err)
+	assert.NotContains(t, uploadIDs(uploads), upload.UploadID, "aborted upload must not be listed")
+}
+
+func testPresignedURL(t *testing.T, s storage.Storage, bucket, prefix string) {
+	ctx := context.Background()
+	key := prefix + "object.txt"
+
+	putURL, err := s.GetPresignedURL(ctx, bucket, key, &storage.PresignedURLOptions{Method: http.MethodPut, Expiry: time.Minute})
+	require.NoError(t, err)
+	resp := do(t, http.MethodPut, putURL, strings.NewReader("presigned"))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	getURL, err := s.GetPresignedURL(ctx, bucket, key, &storage.PresignedURLOptions{Method: http.MethodGet, Expiry: time.Minute})
+	require.NoError(t, err)
+	resp = do(t, http.MethodGet, getURL, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "presigned", string(body))
+
+	_, err = s.GetPresignedURL(ctx, bucket, key, &storage.PresignedURLOptions{Method: http.MethodPatch, Expiry: time.Minute})
+	assert.Error(t, err, "unsupported methods must be rejected")
+}
+
+func testPresignedUploadPart(t *testing.T, s storage.Storage, bucket, prefix string) {
+	presigner, ok := s.(storage.PartPresigner)
+	if !ok {
+		t.Skip("storage does not implement storage.PartPresigner")
+	}
+	ctx := context.Background()
+	key := prefix + "object.bin"
+
+	upload, err := s.CreateMultipartUpload(ctx, bucket, key, nil)
+	require.NoError(t, err)
+
+	parts := [][]byte{bytes.Repeat([]byte("A"), MinPartSize), []byte("tail")}
+	var uploaded []storage.UploadedPart
+	for i, part := range parts {
+		partNumber := int32(i + 1)
+		partURL, err := presigner.GetPresignedUploadPartURL(ctx, bucket, key, upload.UploadID, partNumber, time.Minute)
+		require.NoError(t, err)
+		resp := do(t, http.MethodPut, partURL, bytes.NewReader(part))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotEmpty(t, resp.Header.Get("ETag"), "part upload must return the part ETag")
+		uploaded = append(uploaded, storage.UploadedPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag"), Size: int64(len(part))})
+	}
+
+	_, err = s.CompleteMultipartUpload(ctx, bucket, key, upload.UploadID, &storage.CompleteMultipartUploadOptions{Parts: uploaded})
+	require.NoError(t, err)
+	data, _ := get(t, s, bucket, key)
+	assert.Equal(t, append(parts[0], parts[1]...), data)
+
+	_, err = presigner.GetPresignedUploadPartURL(ctx, bucket, key, upload.UploadID, 0, time.Minute)
+	assert.Error(t, err, "part numbers below 1 must be rejected")
+}
+
+func testQuery(t *testing.T, s storage.Storage, bucket, prefix string) {
+	querier, ok := s.(storage.Querier)
+	if !ok {
+		t.Skip("storage does not implement storage.Querier")
+	}
+	ctx := context.Background()
+	key := prefix + "events.csv"
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("id,status\n1,ok\n2,failed\n3,failed\n"), nil))
+
+	rc, err := querier.Query(ctx, bucket, key, `SELECT s.id FROM S3Object s WHERE s.status = 'failed'`,
+		storage.QueryInput{Format: storage.QueryFormatCSV, CSVHeader: storage.CSVHeaderUse},
+		storage.QueryOutput{Format: storage.QueryFormatCSV})
+	if storage.IsQueryNotSupported(err) {
+		t.Skip("backend does not support queries")
+	}
+	require.NoError(t, err)
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "2\n3\n", string(data))
+}
+
+func testOpenSeekable(t *testing.T, s storage.Storage, bucket, prefix string) {
+	opener, ok := s.(storage.SeekableOpener)
+	if !ok {
+		t.Skip("storage does not implement storage.SeekableOpener")
+	}
+	ctx := context.Background()
+	key := prefix + "media.bin"
+	require.NoError(t, s.Put(ctx, bucket, key, strings.NewReader("0123456789abcdef"), nil))
+
+	_, err := opener.OpenSeekable(ctx, bucket, prefix+"missing.bin")
+	assert.True(t, storage.IsNotFound(err), "OpenSeekable of a missing object: %v", err)
+
+	r, err := opener.OpenSeekable(ctx, bucket, key)
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, int64(16), r.Info().Size)
+
+	_, err = r.Seek(10, io.SeekStart)
+	require.NoError(t, err)
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(buf))
+
+	end, err := r.Seek(-2, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(14), end)
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "ef", string(rest))
+}
+
+// get reads the whole object and closes its body.
+func get(t *testing.T, s storage.Storage, bucket, key string) ([]byte, *storage.ObjectInfo) {
+	t.Helper()
+	rc, info, err := s.Get(context.Background(), bucket, key)
+	require.NoError(t, err)
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	return data, info
+}
+
+// do sends a request to a presigned URL; the response body is closed on test cleanup.
+func do(t *testing.T, method, url string, body io.Reader) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), method, url, body)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func objectKeys(result *storage.ListResult) []string {
+	keys := make([]string, 0, len(result.Objects))
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func uploadIDs(uploads []storage.MultipartUpload) []string {
+	ids := make([]string, 0, len(uploads))
+	for _, u := range uploads {
+		ids = append(ids, u.UploadID)
+	}
+	return ids
+}
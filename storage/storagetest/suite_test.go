@@ -0,0 +1,11 @@
+package storagetest
+
+import "testing"
+
+// TestRunStorageTests tests the contract suite against MemoryStorage.
+func TestRunStorageTests(t *testing.T) {
+	t.Parallel()
+	s := NewMemoryStorage()
+	t.Cleanup(func() { _ = s.Close() })
+	RunStorageTests(t, s, "bucket")
+}
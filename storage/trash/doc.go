@@ -0,0 +1,38 @@
+// Package trash реализует декоратор [storage.Storage] с мягким удалением.
+//
+// Delete не удаляет объект, а переносит его в корзину — под префикс .trash/ в том же bucket'е,
+// сохраняя исходный ключ и время удаления в метаданных (trash-original-key, trash-deleted-at).
+// Объект можно вернуть через Restore, пока PurgeTrash не удалит его по истечении срока хранения.
+//
+// Использование:
+//
+//	inner, err := minio.NewDefault(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	s := trash.New(inner, &trash.Options{
+//	    Retention: 7 * 24 * time.Hour, // по умолчанию 30 дней
+//	    HideTrash: true,               // List не возвращает объекты корзины
+//	})
+//
+//	err = s.Delete(ctx, "docs", "contracts/42.pdf")  // перенос в .trash/contracts/42.pdf
+//	err = s.Restore(ctx, "docs", "contracts/42.pdf") // возврат под исходным ключом
+//
+//	items, err := s.ListTrash(ctx, "docs")  // содержимое корзины с временем удаления
+//	purged, err := s.PurgeTrash(ctx, "docs") // периодически, например из cron
+//
+// Особенности:
+//   - Перенос выполняется через Get, Put и Delete: объект передаётся потоком и сохраняет
+//     тип содержимого, пользовательские метаданные и заголовки Cache-Control, Content-Disposition,
+//     Content-Encoding; retention и legal hold не переносятся
+//   - В корзине хранится последняя удалённая версия ключа: повторное удаление заменяет её
+//   - Delete ключа под префиксом корзины удаляет объект окончательно; удаление
+//     несуществующего объекта не является ошибкой, как в S3
+//   - Если после копирования не удалось удалить исходный объект, он остаётся под обоими
+//     ключами, а операция возвращает ошибку и может быть повторена
+//   - PurgeTrash определяет время удаления по метаданным, а если List их не возвращает
+//     (например, AWS S3) — по времени изменения копии в корзине
+//   - HideTrash фильтрует результат List после запроса, поэтому при MaxKeys объектов
+//     может вернуться меньше
+package trash
@@ -0,0 +1,248 @@
+package trash
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pure-golang/adapters/storage"
+)
+
+var (
+	_ storage.Storage       = (*Storage)(nil)
+	_ storage.OptionsGetter = (*Storage)(nil)
+)
+
+// DefaultPrefix — префикс ключей объектов в корзине по умолчанию
+const DefaultPrefix = ".trash/"
+
+// DefaultRetention — срок хранения объектов в корзине по умолчанию
+const DefaultRetention = 30 * 24 * time.Hour
+
+// Ключи метаданных объекта в корзине
+const (
+	MetadataOriginalKey = "trash-original-key" // исходный ключ объекта
+	MetadataDeletedAt   = "trash-deleted-at"   // время удаления в RFC 3339 (UTC)
+)
+
+// Options содержит настройки Storage
+type Options struct {
+	// Prefix — префикс ключей корзины, объект key переносится в Prefix + key; по умолчанию DefaultPrefix
+	Prefix string
+	// Retention — срок хранения в корзине, после которого PurgeTrash удаляет объект; по умолчанию DefaultRetention
+	Retention time.Duration
+	// HideTrash исключает объекты корзины из результатов List
+	HideTrash bool
+	// Logger для ошибок удаления при переносе; по умолчанию slog.Default()
+	Logger *slog.Logger
+}
+
+// Item — объект в корзине
+type Item struct {
+	Key       string    // Исходный ключ объекта
+	TrashKey  string    // Ключ объекта в корзине: Prefix + Key
+	Size      int64     // Размер объекта в байтах
+	DeletedAt time.Time // Время удаления
+}
+
+// Storage — декоратор storage.Storage с мягким удалением.
+//
+// Delete переносит объект в корзину под Prefix, сохраняя исходный ключ и время удаления
+// в метаданных; Restore возвращает его обратно, PurgeTrash окончательно удаляет объекты
+// старше Retention. Delete ключа под Prefix удаляет объект из корзины окончательно.
+// Остальные операции передаются в исходное хранилище без изменений.
+type Storage struct {
+	storage.Storage
+
+	prefix    string
+	retention time.Duration
+	hideTrash bool
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// New создаёт Storage поверх inner
+func New(inner storage.Storage, opts *Options) *Storage {
+	if opts == nil {
+		opts = &Options{}
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	retention := opts.Retention
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	log := opts.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Storage{
+		Storage:   inner,
+		prefix:    prefix,
+		retention: retention,
+		hideTrash: opts.HideTrash,
+		logger:    log.WithGroup("storage").With("decorator", "trash"),
+		now:       time.Now,
+	}
+}
+
+// TrashKey возвращает ключ объекта key в корзине
+func (s *Storage) TrashKey(key string) string {
+	return s.prefix + key
+}
+
+// Delete переносит объект в корзину. Повторное удаление того же ключа заменяет объект в корзине.
+// Ключ под Prefix удаляется окончательно; удаление несуществующего объекта не является ошибкой.
+func (s *Storage) Delete(ctx context.Context, bucket, key string) error {
+	if strings.HasPrefix(key, s.prefix) {
+		return s.Storage.Delete(ctx, bucket, key)
+	}
+
+	metadata := map[string]string{
+		MetadataOriginalKey: key,
+		MetadataDeletedAt:   s.now().UTC().Format(time.RFC3339),
+	}
+	err := s.move(ctx, bucket, key, s.TrashKey(key), metadata)
+	if storage.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to move %s to trash", key)
+}
+
+// Restore возвращает объект key из корзины под исходным ключом, заменяя объект с тем же ключом.
+// Возвращает ошибку с CodeNotFound, если объекта нет в корзине.
+func (s *Storage) Restore(ctx context.Context, bucket, key string) error {
+	return errors.Wrapf(s.move(ctx, bucket, s.TrashKey(key), key, nil), "failed to restore %s from trash", key)
+}
+
+// List передаёт запрос исходному хранилищу; с HideTrash объекты корзины исключаются из результата,
+// поэтому при MaxKeys объектов может вернуться меньше
+func (s *Storage) List(ctx context.Context, bucket string, opts *storage.ListOptions) (*storage.ListResult, error) {
+	result, err := s.Storage.List(ctx, bucket, opts)
+	if err != nil || !s.hideTrash {
+		return result, err
+	}
+
+	objects := make([]storage.ObjectInfo, 0, len(result.Objects))
+	for _, object := range result.Objects {
+		if !strings.HasPrefix(object.Key, s.prefix) {
+			objects = append(objects, object)
+		}
+	}
+	result.Objects = objects
+	return result, nil
+}
+
+// ListTrash возвращает объекты корзины
+func (s *Storage) ListTrash(ctx context.Context, bucket string) ([]Item, error) {
+	result, err := s.Storage.List(ctx, bucket, &storage.ListOptions{Prefix: s.prefix, Recursive: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list trash")
+	}
+
+	items := make([]Item, 0, len(result.Objects))
+	for _, object := range result.Objects {
+		items = append(items, s.item(object))
+	}
+	return items, nil
+}
+
+// PurgeTrash окончательно удаляет объекты корзины, удалённые раньше, чем Retention назад.
+// Возвращает число удалённых объектов; при ошибке удаление прекращается.
+func (s *Storage) PurgeTrash(ctx context.Context, bucket string) (int, error) {
+	items, err := s.ListTrash(ctx, bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := s.now().Add(-s.retention)
+	purged := 0
+	for _, item := range items {
+		if !item.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if err := s.Storage.Delete(ctx, bucket, item.TrashKey); err != nil {
+			return purged, errors.Wrapf(err, "failed to purge %s", item.TrashKey)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// GetWithOptions передаёт запрос исходному хранилищу, если оно реализует storage.OptionsGetter
+func (s *Storage) GetWithOptions(ctx context.Context, bucket, key string, opts *storage.GetOptions) (io.ReadCloser, *storage.ObjectInfo, error) {
+	getter, ok := s.Storage.(storage.OptionsGetter)
+	if !ok {
+		return nil, nil, errors.New("underlying storage does not support get options")
+	}
+	return getter.GetWithOptions(ctx, bucket, key, opts)
+}
+
+// move копирует объект from в to с его метаданными и заголовками и удаляет from.
+// Метаданные корзины исходного объекта заменяются metadata: при восстановлении (nil) они удаляются.
+func (s *Storage) move(ctx context.Context, bucket, from, to string, metadata map[string]string) error {
+	body, info, err := s.Storage.Get(ctx, bucket, from)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	opts := &storage.PutOptions{
+		ContentType:        info.ContentType,
+		Metadata:           withoutTrashMetadata(info.Metadata),
+		CacheControl:       info.CacheControl,
+		ContentDisposition: info.ContentDisposition,
+		ContentEncoding:    info.ContentEncoding,
+	}
+	maps.Copy(opts.Metadata, metadata)
+	if err := s.Storage.Put(ctx, bucket, to, body, opts); err != nil {
+		return err
+	}
+
+	if err := s.Storage.Delete(ctx, bucket, from); err != nil {
+		// Копия уже записана: объект остаётся под обоими ключами до повторной операции
+		s.logger.With("error", err).Error("failed to delete moved object", "bucket", bucket, "from", from, "to", to)
+		return err
+	}
+	return nil
+}
+
+// item описывает объект корзины; время удаления берётся из метаданных,
+// а если List их не возвращает — из времени изменения копии в корзине
+func (s *Storage) item(object storage.ObjectInfo) Item {
+	item := Item{
+		Key:       strings.TrimPrefix(object.Key, s.prefix),
+		TrashKey:  object.Key,
+		Size:      object.Size,
+		DeletedAt: object.LastModified,
+	}
+	if key, ok := storage.MetadataValue(object.Metadata, MetadataOriginalKey); ok {
+		item.Key = key
+	}
+	if value, ok := storage.MetadataValue(object.Metadata, MetadataDeletedAt); ok {
+		if deletedAt, err := time.Parse(time.RFC3339, value); err == nil {
+			item.DeletedAt = deletedAt
+		}
+	}
+	return item
+}
+
+// withoutTrashMetadata возвращает копию метаданных без ключей корзины
+func withoutTrashMetadata(metadata map[string]string) map[string]string {
+	result := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		name := strings.TrimPrefix(strings.ToLower(k), "x-amz-meta-")
+		if name != MetadataOriginalKey && name != MetadataDeletedAt {
+			result[k] = v
+		}
+	}
+	return result
+}
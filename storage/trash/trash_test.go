@@ -0,0 +1,120 @@
+package trash
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pure-golang/adapters/storage"
+	"github.com/pure-golang/adapters/storage/storagetest"
+)
+
+// TestStorage_DeleteRestore tests moving an object to the trash and back with its metadata.
+func TestStorage_DeleteRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, nil)
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return deletedAt }
+
+	require.NoError(t, s.Put(ctx, "docs", "reports/q1.pdf", strings.NewReader("pdf"), &storage.PutOptions{
+		ContentType:  "application/pdf",
+		Metadata:     map[string]string{"owner": "alice"},
+		CacheControl: "no-cache",
+	}))
+
+	require.NoError(t, s.Delete(ctx, "docs", "reports/q1.pdf"))
+	assert.Equal(t, []string{".trash/reports/q1.pdf"}, inner.Keys("docs"))
+
+	_, info, err := inner.Get(ctx, "docs", ".trash/reports/q1.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", info.ContentType)
+	assert.Equal(t, "no-cache", info.CacheControl)
+	assert.Equal(t, map[string]string{
+		"owner":             "alice",
+		MetadataOriginalKey: "reports/q1.pdf",
+		MetadataDeletedAt:   "2024-05-01T12:00:00Z",
+	}, info.Metadata)
+
+	items, err := s.ListTrash(ctx, "docs")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, Item{Key: "reports/q1.pdf", TrashKey: ".trash/reports/q1.pdf", Size: 3, DeletedAt: deletedAt}, items[0])
+
+	require.NoError(t, s.Restore(ctx, "docs", "reports/q1.pdf"))
+	assert.Equal(t, []string{"reports/q1.pdf"}, inner.Keys("docs"))
+
+	body, info, err := s.Get(ctx, "docs", "reports/q1.pdf")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", string(data))
+	assert.Equal(t, map[string]string{"owner": "alice"}, info.Metadata)
+}
+
+// TestStorage_Delete tests idempotent deletion and permanent deletion from the trash.
+func TestStorage_Delete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, &Options{Prefix: "bin/"})
+
+	require.NoError(t, s.Delete(ctx, "docs", "missing.txt"))
+
+	require.NoError(t, s.Put(ctx, "docs", "a.txt", strings.NewReader("a"), &storage.PutOptions{}))
+	require.NoError(t, s.Delete(ctx, "docs", "a.txt"))
+	require.NoError(t, s.Delete(ctx, "docs", "bin/a.txt"))
+	assert.Empty(t, inner.Keys("docs"))
+
+	err := s.Restore(ctx, "docs", "a.txt")
+	assert.True(t, storage.IsNotFound(err), "got %v", err)
+}
+
+// TestStorage_List tests hiding trashed objects from List.
+func TestStorage_List(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := storagetest.NewMemoryStorage()
+	require.NoError(t, inner.Put(ctx, "docs", "a.txt", strings.NewReader("a"), &storage.PutOptions{}))
+	require.NoError(t, inner.Put(ctx, "docs", "b.txt", strings.NewReader("b"), &storage.PutOptions{}))
+	require.NoError(t, New(inner, nil).Delete(ctx, "docs", "b.txt"))
+
+	result, err := New(inner, nil).List(ctx, "docs", &storage.ListOptions{Recursive: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Objects, 2)
+
+	result, err = New(inner, &Options{HideTrash: true}).List(ctx, "docs", &storage.ListOptions{Recursive: true})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, 1)
+	assert.Equal(t, "a.txt", result.Objects[0].Key)
+}
+
+// TestStorage_PurgeTrash tests that only objects older than the retention period are purged.
+func TestStorage_PurgeTrash(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := storagetest.NewMemoryStorage()
+	s := New(inner, &Options{Retention: 24 * time.Hour})
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	for key, deletedAt := range map[string]time.Time{
+		"old.txt":    now.Add(-48 * time.Hour),
+		"recent.txt": now.Add(-time.Hour),
+	} {
+		require.NoError(t, s.Put(ctx, "docs", key, strings.NewReader(key), &storage.PutOptions{}))
+		s.now = func() time.Time { return deletedAt }
+		require.NoError(t, s.Delete(ctx, "docs", key))
+	}
+	s.now = func() time.Time { return now }
+
+	purged, err := s.PurgeTrash(ctx, "docs")
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, []string{".trash/recent.txt"}, inner.Keys("docs"))
+}
@@ -0,0 +1,847 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pure-golang/adapters/logger"
+)
+
+// Upload handler defaults.
+const (
+	DefaultUploadPartSize   = 8 << 20
+	DefaultUploadFormField  = "file"
+	DefaultUploadSessionTTL = 24 * time.Hour
+	DefaultUploadMaxSize    = 5 << 30
+	DefaultUploadMaxBuffer  = 1 << 30
+
+	// MinUploadPartSize is the S3 minimum size of every part except the last one.
+	MinUploadPartSize = 5 << 20
+	// MaxUploadPartSize is the S3 maximum part size.
+	MaxUploadPartSize = 5 << 30
+	// MaxUploadParts is the S3 maximum number of parts in a multipart upload.
+	MaxUploadParts = 10000
+
+	// TusVersion is the supported version of the tus resumable upload protocol.
+	TusVersion = "1.0.0"
+)
+
+const (
+	tusContentType = "application/offset+octet-stream"
+	sniffLen       = 512
+
+	// partGrowthInterval is the number of parts after which the part size of an upload
+	// of unknown size is doubled.
+	partGrowthInterval = 1000
+)
+
+// errUploadTooLarge is returned when an upload exceeds UploadOptions.MaxSize.
+var errUploadTooLarge = errors.New("upload exceeds maximum size")
+
+// errUploadBufferFull is returned when a resumable upload would exceed UploadOptions.MaxBuffer.
+var errUploadBufferFull = errors.New("too many active uploads")
+
+// errContentTypeNotAllowed is returned when the upload content type is not in UploadOptions.AllowedContentTypes.
+var errContentTypeNotAllowed = errors.New("content type not allowed")
+
+// UploadOptions configures UploadHandler.
+type UploadOptions struct {
+	Bucket string // Target bucket
+	Prefix string // Prefix of generated keys, e.g. "uploads/"
+
+	// Key returns the object key for an upload. filename is the name sent by the client and may be empty.
+	// Default: Prefix + random hex + extension of filename.
+	Key func(r *http.Request, filename string) (string, error)
+
+	// Metadata returns user metadata stored with the object, e.g. the uploader ID.
+	Metadata func(r *http.Request) map[string]string
+
+	// MaxSize is the maximum object size in bytes (default 5 GiB), negative means no limit.
+	// Form uploads with a longer Content-Length are rejected before the body is read.
+	MaxSize int64
+
+	// MaxBuffer limits the memory held by resumable uploads (default 1 GiB): every active upload
+	// reserves a part of buffer, and new uploads are rejected with 503 while the reservations
+	// would exceed MaxBuffer, or with 413 when a single part does not fit into it.
+	MaxBuffer int64
+
+	// AllowedContentTypes restricts content types, e.g. "image/png" or "image/*". Empty allows any type.
+	// Form uploads without a declared type are sniffed with http.DetectContentType.
+	AllowedContentTypes []string
+
+	// PartSize is the multipart part size (default 8 MiB, at least MinUploadPartSize).
+	// It is grown for large uploads to stay within MaxUploadParts, see UploadPartSize.
+	PartSize int64
+
+	// BandwidthLimit limits every upload to this many bytes per second sent to the storage, 0 means no limit.
+	BandwidthLimit int64
+	// Bandwidth is a limiter shared by all uploads, e.g. NewBandwidthLimiter(50 << 20).
+	// Pass the same limiter to several handlers to cap their total bandwidth. Nil means no limit.
+	Bandwidth *BandwidthLimiter
+
+	FormField  string        // Form field with the file for multipart/form-data uploads (default "file")
+	SessionTTL time.Duration // Resumable uploads idle for longer are aborted on the next request (default 24h)
+}
+
+// UploadResult is the JSON response describing an uploaded object.
+type UploadResult struct {
+	Bucket      string `json:"bucket"`
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+}
+
+// UploadHandler is an http.Handler that streams browser uploads into a Storage.
+//
+// Two protocols are supported on the same endpoint:
+//
+//   - POST with multipart/form-data: the file from FormField is streamed into the storage
+//     in PartSize parts and the response is 201 with UploadResult.
+//   - tus 1.0 resumable uploads (creation and termination extensions): POST with Upload-Length
+//     creates an upload and returns its Location, PATCH appends chunks at Upload-Offset,
+//     HEAD reports the offset, DELETE aborts. The PATCH completing the upload returns 200 with UploadResult.
+//
+// Resumable upload state is kept in memory: chunks are buffered until a full part is collected,
+// so each active upload holds up to a part (PartSize, grown for large uploads), and all requests
+// of an upload must reach the same handler instance.
+//
+// Parts are sent to the storage no faster than BandwidthLimit per upload and the shared Bandwidth limiter allow.
+type UploadHandler struct {
+	storage Storage
+	opts    UploadOptions
+
+	mu       sync.Mutex
+	sessions map[string]*uploadSession
+	buffered int64 // buffer reserved by active resumable uploads
+}
+
+// uploadSession is the state of a resumable upload.
+type uploadSession struct {
+	mu sync.Mutex
+
+	key         string
+	uploadID    string // empty for empty objects stored with Put
+	filename    string
+	contentType string
+	metadata    map[string]string
+	length      int64
+	offset      int64 // bytes received: uploaded parts plus pending
+	partSize    int64
+	parts       []UploadedPart
+	pending     []byte            // received bytes not yet uploaded as a part
+	limiter     *BandwidthLimiter // per-upload limiter, nil without BandwidthLimit
+	reserved    int64             // part of UploadHandler.buffered held until the upload ends
+	updated     time.Time
+	result      *UploadResult // set when the upload is completed
+}
+
+// NewUploadHandler returns an upload handler storing objects in s.
+// Mount it with a trailing slash so that resumable upload URLs are routed to it:
+//
+//	mux.Handle("/uploads/", storage.NewUploadHandler(s, storage.UploadOptions{Bucket: "media"}))
+func NewUploadHandler(s Storage, opts UploadOptions) *UploadHandler {
+	if opts.PartSize <= 0 {
+		opts.PartSize = DefaultUploadPartSize
+	}
+	opts.PartSize = max(opts.PartSize, MinUploadPartSize)
+	if opts.FormField == "" {
+		opts.FormField = DefaultUploadFormField
+	}
+	if opts.SessionTTL <= 0 {
+		opts.SessionTTL = DefaultUploadSessionTTL
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = DefaultUploadMaxSize
+	}
+	if opts.MaxBuffer <= 0 {
+		opts.MaxBuffer = DefaultUploadMaxBuffer
+	}
+	return &UploadHandler{
+		storage:  s,
+		opts:     opts,
+		sessions: make(map[string]*uploadSession),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Expired uploads are aborted on every request, so an idle upload is never resumed past SessionTTL
+	h.sweep(r.Context())
+
+	switch r.Method {
+	case http.MethodPost:
+		if r.Header.Get("Upload-Length") != "" || r.Header.Get("Tus-Resumable") != "" {
+			h.create(w, r)
+			return
+		}
+		h.uploadForm(w, r)
+	case http.MethodPatch:
+		h.patch(w, r)
+	case http.MethodHead:
+		h.head(w, r)
+	case http.MethodDelete:
+		h.terminate(w, r)
+	case http.MethodOptions:
+		h.options(w)
+	default:
+		w.Header().Set("Allow", "POST, PATCH, HEAD, DELETE, OPTIONS")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// uploadForm streams the file field of a multipart/form-data request into the storage.
+func (h *UploadHandler) uploadForm(w http.ResponseWriter, r *http.Request) {
+	// The body holds the file and the form overhead, so a longer body cannot fit into MaxSize
+	if h.tooLarge(r.ContentLength) {
+		h.fail(w, r, errUploadTooLarge)
+		return
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "expected multipart/form-data or tus upload", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var part io.ReadCloser
+	var filename, declared string
+	for {
+		p, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			http.Error(w, fmt.Sprintf("missing form field %q", h.opts.FormField), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "invalid multipart body", http.StatusBadRequest)
+			return
+		}
+		if p.FormName() == h.opts.FormField {
+			part, filename, declared = p, p.FileName(), p.Header.Get("Content-Type")
+			break
+		}
+		_ = p.Close()
+	}
+	defer func() { _ = part.Close() }()
+
+	body := bufio.NewReaderSize(part, sniffLen)
+	contentType := declared
+	if contentType == "" || contentType == "application/octet-stream" {
+		head, _ := body.Peek(sniffLen)
+		contentType = http.DetectContentType(head)
+	}
+	if !h.allowed(contentType) {
+		h.fail(w, r, errContentTypeNotAllowed)
+		return
+	}
+
+	key, err := h.key(r, filename)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	// The request length bounds the file size; without it the part size grows as the upload goes
+	size := r.ContentLength
+	if size <= 0 {
+		size = h.opts.MaxSize
+	}
+	result, err := h.store(r.Context(), key, body, size, h.putOptions(r, contentType))
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	result.Filename = filename
+	writeUploadResult(w, http.StatusCreated, result)
+}
+
+// store streams r into key: small bodies with Put, larger ones with the multipart API.
+// size is the expected upper bound of the body size, 0 if unknown.
+func (h *UploadHandler) store(ctx context.Context, key string, r io.Reader, size int64, opts *PutOptions) (*UploadResult, error) {
+	if h.opts.MaxSize > 0 {
+		r = io.LimitReader(r, h.opts.MaxSize+1)
+	}
+	partSize := UploadPartSize(size, h.opts.PartSize)
+	// size comes from the client, so the buffer grows with the bytes actually read
+	// instead of being allocated for a whole part up front
+	var buf bytes.Buffer
+	limiter := NewBandwidthLimiter(h.opts.BandwidthLimit)
+
+	if err := readPart(r, &buf, partSize); err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(buf.Len()) < partSize {
+		if h.tooLarge(int64(buf.Len())) {
+			return nil, errUploadTooLarge
+		}
+		return h.put(ctx, key, buf.Bytes(), opts, limiter)
+	}
+
+	upload, err := h.storage.CreateMultipartUpload(ctx, h.opts.Bucket, key, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	var parts []UploadedPart
+	var uploaded int64
+	for buf.Len() > 0 {
+		uploaded += int64(buf.Len())
+		if h.tooLarge(uploaded) {
+			h.abort(ctx, key, upload.UploadID)
+			return nil, errUploadTooLarge
+		}
+		part, err := h.storage.UploadPart(ctx, h.opts.Bucket, key, upload.UploadID, int32(len(parts)+1), h.partReader(ctx, buf.Bytes(), limiter))
+		if err != nil {
+			h.abort(ctx, key, upload.UploadID)
+			return nil, fmt.Errorf("failed to upload part %d: %w", len(parts)+1, err)
+		}
+		parts = append(parts, *part)
+
+		// Parts may differ in size: with an unknown size, grow them so that
+		// MaxUploadParts parts hold the largest object S3 accepts
+		if size <= 0 && len(parts)%partGrowthInterval == 0 && partSize < MaxUploadPartSize {
+			partSize = min(partSize*2, MaxUploadPartSize)
+		}
+		if err := readPart(r, &buf, partSize); err != nil {
+			h.abort(ctx, key, upload.UploadID)
+			return nil, fmt.Errorf("failed to read upload: %w", err)
+		}
+	}
+
+	return h.complete(ctx, key, upload.UploadID, parts, uploaded, opts.ContentType)
+}
+
+// readPart replaces the contents of buf with the next partSize bytes of r, fewer at the end of r.
+func readPart(r io.Reader, buf *bytes.Buffer, partSize int64) error {
+	buf.Reset()
+	_, err := buf.ReadFrom(io.LimitReader(r, partSize))
+	return err
+}
+
+// partReader returns a reader of data limited by the per-upload limiter and the shared Bandwidth limiter.
+func (h *UploadHandler) partReader(ctx context.Context, data []byte, limiter *BandwidthLimiter) io.Reader {
+	return limiter.Reader(ctx, h.opts.Bandwidth.Reader(ctx, bytes.NewReader(data)))
+}
+
+// UploadPartSize returns the part size for a multipart upload of size bytes: partSize,
+// grown to a multiple of 1 MiB when size would otherwise take more than MaxUploadParts parts.
+// The result is at most MaxUploadPartSize; partSize is returned for an unknown (non-positive) size.
+func UploadPartSize(size, partSize int64) int64 {
+	if size <= 0 {
+		return partSize
+	}
+	minSize := (size + MaxUploadParts - 1) / MaxUploadParts
+	if minSize <= partSize {
+		return partSize
+	}
+	const mib = 1 << 20
+	return min((minSize+mib-1)/mib*mib, MaxUploadPartSize)
+}
+
+// put stores a body that fits into a single part.
+func (h *UploadHandler) put(ctx context.Context, key string, data []byte, opts *PutOptions, limiter *BandwidthLimiter) (*UploadResult, error) {
+	if err := h.storage.Put(ctx, h.opts.Bucket, key, h.partReader(ctx, data, limiter), opts); err != nil {
+		return nil, fmt.Errorf("failed to put object: %w", err)
+	}
+	return &UploadResult{
+		Bucket:      h.opts.Bucket,
+		Key:         key,
+		Size:        int64(len(data)),
+		ContentType: opts.ContentType,
+	}, nil
+}
+
+// complete completes a multipart upload, aborting it on failure.
+func (h *UploadHandler) complete(ctx context.Context, key, uploadID string, parts []UploadedPart, size int64, contentType string) (*UploadResult, error) {
+	info, err := h.storage.CompleteMultipartUpload(ctx, h.opts.Bucket, key, uploadID, &CompleteMultipartUploadOptions{Parts: parts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	result := &UploadResult{Bucket: h.opts.Bucket, Key: key, Size: size, ContentType: contentType}
+	if info != nil {
+		result.ETag = info.ETag
+	}
+	return result, nil
+}
+
+// abort aborts a multipart upload; the request context may already be canceled.
+func (h *UploadHandler) abort(ctx context.Context, key, uploadID string) {
+	if err := h.storage.AbortMultipartUpload(context.WithoutCancel(ctx), h.opts.Bucket, key, uploadID); err != nil {
+		logger.FromContext(ctx).With("error", err).Warn("failed to abort multipart upload", "key", key)
+	}
+}
+
+// create starts a resumable upload (tus creation extension).
+func (h *UploadHandler) create(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "invalid Upload-Length", http.StatusBadRequest)
+		return
+	}
+	if h.tooLarge(length) {
+		h.fail(w, r, errUploadTooLarge)
+		return
+	}
+
+	metadata := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	filename := firstNonEmpty(metadata["filename"], metadata["name"])
+	contentType := firstNonEmpty(metadata["filetype"], metadata["type"])
+	if contentType != "" && !h.allowed(contentType) {
+		h.fail(w, r, errContentTypeNotAllowed)
+		return
+	}
+
+	key, err := h.key(r, filename)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	s := &uploadSession{
+		key:         key,
+		filename:    filename,
+		contentType: contentType,
+		length:      length,
+		partSize:    UploadPartSize(length, h.opts.PartSize),
+		limiter:     NewBandwidthLimiter(h.opts.BandwidthLimit),
+		updated:     time.Now(),
+	}
+	if length > 0 {
+		// At most a part is buffered; the last part is not longer than the upload
+		s.reserved = min(s.partSize, length)
+	}
+	if s.reserved > h.opts.MaxBuffer {
+		h.fail(w, r, errUploadTooLarge)
+		return
+	}
+	if h.opts.Metadata != nil {
+		s.metadata = h.opts.Metadata(r)
+	}
+	if length == 0 {
+		result, err := h.put(r.Context(), key, nil, s.putOptions(""), nil)
+		if err != nil {
+			h.fail(w, r, err)
+			return
+		}
+		result.Filename = filename
+		s.result = result
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	h.mu.Lock()
+	if h.buffered+s.reserved > h.opts.MaxBuffer {
+		h.mu.Unlock()
+		h.fail(w, r, errUploadBufferFull)
+		return
+	}
+	h.buffered += s.reserved
+	h.sessions[id] = s
+	h.mu.Unlock()
+
+	w.Header().Set("Location", uploadLocation(r, id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// patch appends a chunk to a resumable upload.
+func (h *UploadHandler) patch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+	s, ok := h.session(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != tusContentType {
+		http.Error(w, "expected Content-Type "+tusContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid Upload-Offset", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updated = time.Now()
+
+	if s.result != nil || offset != s.offset {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(s.offset, 10))
+		http.Error(w, "upload offset mismatch", http.StatusConflict)
+		return
+	}
+
+	readErr := h.receive(r.Context(), s, r.Body)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(s.offset, 10))
+	if readErr != nil {
+		h.fail(w, r, readErr)
+		return
+	}
+
+	if s.offset < s.length {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result, err := h.finish(r.Context(), s)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	h.release(s)
+	writeUploadResult(w, http.StatusOK, result)
+}
+
+// receive reads a chunk into s, uploading every full part.
+// Bytes are counted in s.offset as soon as they are buffered, so a failed part upload
+// can be retried by the next PATCH without losing data.
+func (h *UploadHandler) receive(ctx context.Context, s *uploadSession, body io.Reader) error {
+	// Read one byte past the declared length to detect oversized chunks
+	body = io.LimitReader(body, s.length-s.offset+1)
+	chunk := make([]byte, 32<<10)
+	for {
+		n, err := body.Read(chunk)
+		if n > 0 {
+			if s.offset+int64(n) > s.length {
+				return errUploadTooLarge
+			}
+			if s.offset == 0 && s.contentType == "" {
+				s.contentType = http.DetectContentType(chunk[:min(n, sniffLen)])
+				if !h.allowed(s.contentType) {
+					return errContentTypeNotAllowed
+				}
+			}
+			s.pending = append(s.pending, chunk[:n]...)
+			s.offset += int64(n)
+			if int64(len(s.pending)) >= s.partSize {
+				if err := h.flush(ctx, s, s.partSize); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// The client went away: keep the received bytes for a resumed PATCH
+			return nil
+		}
+	}
+}
+
+// flush uploads the first size pending bytes of s as the next part.
+func (h *UploadHandler) flush(ctx context.Context, s *uploadSession, size int64) error {
+	if s.uploadID == "" {
+		upload, err := h.storage.CreateMultipartUpload(ctx, h.opts.Bucket, s.key, s.putOptions(s.contentType))
+		if err != nil {
+			return fmt.Errorf("failed to create multipart upload: %w", err)
+		}
+		s.uploadID = upload.UploadID
+	}
+
+	partNumber := int32(len(s.parts) + 1)
+	part, err := h.storage.UploadPart(ctx, h.opts.Bucket, s.key, s.uploadID, partNumber, h.partReader(ctx, s.pending[:size], s.limiter))
+	if err != nil {
+		return fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+	s.parts = append(s.parts, *part)
+	s.pending = append(s.pending[:0], s.pending[size:]...)
+	return nil
+}
+
+// finish uploads the last part and completes the upload.
+func (h *UploadHandler) finish(ctx context.Context, s *uploadSession) (*UploadResult, error) {
+	var result *UploadResult
+	var err error
+	if s.uploadID == "" {
+		// The whole upload fits into one part
+		result, err = h.put(ctx, s.key, s.pending, s.putOptions(s.contentType), s.limiter)
+	} else {
+		if len(s.pending) > 0 {
+			if err := h.flush(ctx, s, int64(len(s.pending))); err != nil {
+				return nil, err
+			}
+		}
+		result, err = h.complete(ctx, s.key, s.uploadID, s.parts, s.length, s.contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Filename = s.filename
+	s.result = result
+	s.pending = nil
+	return result, nil
+}
+
+// head reports the offset of a resumable upload.
+func (h *UploadHandler) head(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+	s, ok := h.session(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	s.mu.Lock()
+	offset, length := s.offset, s.length
+	s.mu.Unlock()
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(length, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// terminate aborts a resumable upload (tus termination extension).
+func (h *UploadHandler) terminate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+	id := path.Base(r.URL.Path)
+	h.mu.Lock()
+	s, ok := h.sessions[id]
+	delete(h.sessions, id)
+	h.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h.release(s)
+	if s.result == nil && s.uploadID != "" {
+		h.abort(r.Context(), s.key, s.uploadID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// options describes the supported tus protocol.
+func (h *UploadHandler) options(w http.ResponseWriter) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+	w.Header().Set("Tus-Version", TusVersion)
+	w.Header().Set("Tus-Extension", "creation,termination")
+	if h.opts.MaxSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.opts.MaxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// session returns the resumable upload addressed by the last path segment.
+func (h *UploadHandler) session(r *http.Request) (*uploadSession, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sessions[path.Base(r.URL.Path)]
+	return s, ok
+}
+
+// sweep aborts resumable uploads idle for longer than SessionTTL.
+func (h *UploadHandler) sweep(ctx context.Context) {
+	deadline := time.Now().Add(-h.opts.SessionTTL)
+	// Multipart uploads to abort, copied under the session lock
+	var expired []struct{ key, uploadID string }
+
+	h.mu.Lock()
+	for id, s := range h.sessions {
+		// Sessions locked by an active request are not idle
+		if !s.mu.TryLock() {
+			continue
+		}
+		if s.updated.Before(deadline) {
+			delete(h.sessions, id)
+			h.buffered -= s.reserved
+			s.reserved = 0
+			if s.result == nil && s.uploadID != "" {
+				expired = append(expired, struct{ key, uploadID string }{s.key, s.uploadID})
+			}
+		}
+		s.mu.Unlock()
+	}
+	h.mu.Unlock()
+
+	for _, u := range expired {
+		h.abort(ctx, u.key, u.uploadID)
+	}
+}
+
+// release returns the buffer reserved by s to the handler. s.mu must be held.
+func (h *UploadHandler) release(s *uploadSession) {
+	h.mu.Lock()
+	h.buffered -= s.reserved
+	h.mu.Unlock()
+	s.reserved = 0
+}
+
+// key returns the object key for an upload.
+func (h *UploadHandler) key(r *http.Request, filename string) (string, error) {
+	if h.opts.Key != nil {
+		return h.opts.Key(r, filename)
+	}
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	return h.opts.Prefix + id + safeExt(filename), nil
+}
+
+// putOptions returns options for objects stored from r.
+func (h *UploadHandler) putOptions(r *http.Request, contentType string) *PutOptions {
+	opts := &PutOptions{ContentType: contentType}
+	if h.opts.Metadata != nil {
+		opts.Metadata = h.opts.Metadata(r)
+	}
+	return opts
+}
+
+// putOptions returns options for the object of a resumable upload.
+func (s *uploadSession) putOptions(contentType string) *PutOptions {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &PutOptions{ContentType: contentType, Metadata: s.metadata}
+}
+
+// allowed reports whether contentType matches AllowedContentTypes.
+func (h *UploadHandler) allowed(contentType string) bool {
+	return contentTypeAllowed(h.opts.AllowedContentTypes, contentType)
+}
+
+// contentTypeAllowed reports whether contentType matches one of patterns, e.g. "image/png" or "image/*".
+// Empty patterns allow any type.
+func contentTypeAllowed(patterns []string, contentType string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	mediaType := mediaType(contentType)
+	if mediaType == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "*/*" || pattern == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType returns the lowercased media type of contentType without parameters, or "" if it is invalid.
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// tooLarge reports whether size exceeds MaxSize.
+func (h *UploadHandler) tooLarge(size int64) bool {
+	return h.opts.MaxSize > 0 && size > h.opts.MaxSize
+}
+
+// fail writes an error response; unexpected errors are logged and hidden from the client.
+func (h *UploadHandler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errUploadTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, errUploadBufferFull):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, errContentTypeNotAllowed):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case IsQuotaExceeded(err):
+		http.Error(w, "quota exceeded", http.StatusInsufficientStorage)
+	case IsAccessDenied(err):
+		http.Error(w, "access denied", http.StatusForbidden)
+	case IsInvalidKey(err):
+		http.Error(w, "invalid object key", http.StatusBadRequest)
+	default:
+		logger.FromContext(r.Context()).With("error", err).Error("failed to upload object")
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+	}
+}
+
+// writeUploadResult writes result as JSON.
+func writeUploadResult(w http.ResponseWriter, status int, result *UploadResult) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// uploadLocation returns the URL of a resumable upload relative to the original request path,
+// so that it stays correct behind http.StripPrefix.
+func uploadLocation(r *http.Request, id string) string {
+	base := r.URL.Path
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil && u.Path != "" {
+		base = u.Path
+	}
+	return strings.TrimSuffix(base, "/") + "/" + id
+}
+
+// parseTusMetadata decodes the Upload-Metadata header: comma-separated "key base64value" pairs.
+func parseTusMetadata(header string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			continue
+		}
+		result[key] = string(value)
+	}
+	return result
+}
+
+// safeExt returns the lowercased extension of filename if it is short and alphanumeric.
+func safeExt(filename string) string {
+	ext := strings.ToLower(path.Ext(strings.ReplaceAll(filename, `\`, "/")))
+	if len(ext) < 2 || len(ext) > 10 {
+		return ""
+	}
+	for _, c := range ext[1:] {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return ""
+		}
+	}
+	return ext
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate upload id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
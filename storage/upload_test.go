@@ -0,0 +1,577 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadStorage is an in-memory Storage serving Put and the multipart API for upload tests
+type uploadStorage struct {
+	Storage
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	opts    map[string]*PutOptions
+	uploads map[string]map[int32][]byte
+	aborted []string
+	putErr  error
+}
+
+func newUploadStorage() *uploadStorage {
+	return &uploadStorage{
+		objects: make(map[string][]byte),
+		opts:    make(map[string]*PutOptions),
+		uploads: make(map[string]map[int32][]byte),
+	}
+}
+
+func (s *uploadStorage) Put(_ context.Context, _, key string, reader io.Reader, opts *PutOptions) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	s.opts[key] = opts
+	return nil
+}
+
+func (s *uploadStorage) CreateMultipartUpload(_ context.Context, _, key string, opts *PutOptions) (*MultipartUpload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := fmt.Sprintf("upload-%d", len(s.uploads)+1)
+	s.uploads[id] = make(map[int32][]byte)
+	s.opts[key] = opts
+	return &MultipartUpload{Key: key, UploadID: id}, nil
+}
+
+func (s *uploadStorage) UploadPart(_ context.Context, _, _, uploadID string, partNumber int32, reader io.Reader) (*UploadedPart, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads[uploadID][partNumber] = data
+	return &UploadedPart{PartNumber: partNumber, ETag: fmt.Sprintf("part-%d", partNumber)}, nil
+}
+
+func (s *uploadStorage) CompleteMultipartUpload(_ context.Context, _, key, uploadID string, opts *CompleteMultipartUploadOptions) (*ObjectInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var data []byte
+	for _, part := range opts.Parts {
+		data = append(data, s.uploads[uploadID][part.PartNumber]...)
+	}
+	s.objects[key] = data
+	delete(s.uploads, uploadID)
+	return &ObjectInfo{Key: key, Size: int64(len(data)), ETag: "etag-multipart"}, nil
+}
+
+func (s *uploadStorage) AbortMultipartUpload(_ context.Context, _, _, uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploads, uploadID)
+	s.aborted = append(s.aborted, uploadID)
+	return nil
+}
+
+func (s *uploadStorage) object(key string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.objects[key]
+}
+
+// formRequest builds a multipart/form-data upload request.
+func formRequest(t *testing.T, field, filename, contentType string, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("title", "ignored"))
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filename))
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	part, err := mw.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/uploads/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func decodeUploadResult(t *testing.T, rec *httptest.ResponseRecorder) UploadResult {
+	t.Helper()
+	var result UploadResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	return result
+}
+
+// TestUploadHandler_Form tests multipart/form-data uploads.
+func TestUploadHandler_Form(t *testing.T) {
+	t.Parallel()
+
+	t.Run("small file is stored with put", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{
+			Bucket:   "media",
+			Prefix:   "avatars/",
+			Metadata: func(*http.Request) map[string]string { return map[string]string{"owner": "alice"} },
+		})
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "Photo.PNG", "image/png", []byte("png data")))
+
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		result := decodeUploadResult(t, rec)
+		assert.Equal(t, "media", result.Bucket)
+		assert.True(t, strings.HasPrefix(result.Key, "avatars/"), result.Key)
+		assert.True(t, strings.HasSuffix(result.Key, ".png"), result.Key)
+		assert.Equal(t, int64(8), result.Size)
+		assert.Equal(t, "image/png", result.ContentType)
+		assert.Equal(t, "Photo.PNG", result.Filename)
+		assert.Equal(t, []byte("png data"), s.object(result.Key))
+		assert.Equal(t, map[string]string{"owner": "alice"}, s.opts[result.Key].Metadata)
+	})
+
+	t.Run("large file is stored with multipart upload", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media"})
+		data := bytes.Repeat([]byte("0123456789"), (2*MinUploadPartSize+MinUploadPartSize/2)/10)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "data.bin", "application/x-custom", data))
+
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		result := decodeUploadResult(t, rec)
+		assert.Equal(t, int64(len(data)), result.Size)
+		assert.Equal(t, "etag-multipart", result.ETag)
+		assert.Equal(t, data, s.object(result.Key))
+	})
+
+	t.Run("content type is sniffed", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", AllowedContentTypes: []string{"image/*"}})
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "a.png", "", []byte("\x89PNG\r\n\x1a\n rest")))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		assert.Equal(t, "image/png", decodeUploadResult(t, rec).ContentType)
+
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "a.png", "application/octet-stream", []byte("plain text")))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", MaxSize: 4})
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "a.txt", "text/plain", []byte("12345")))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Empty(t, s.objects)
+	})
+
+	t.Run("declared length over max size is rejected before reading", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", MaxSize: 1 << 20})
+		req := formRequest(t, "file", "a.txt", "text/plain", []byte("data"))
+		req.ContentLength = 2 << 20
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Empty(t, s.objects)
+	})
+
+	t.Run("declared length does not size the buffer", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", MaxSize: -1})
+		req := formRequest(t, "file", "a.txt", "text/plain", []byte("data"))
+		// A whole part for this length would be MaxUploadPartSize
+		req.ContentLength = MaxUploadPartSize * MaxUploadParts
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		assert.Equal(t, []byte("data"), s.object(decodeUploadResult(t, rec).Key))
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		t.Parallel()
+		h := NewUploadHandler(newUploadStorage(), UploadOptions{Bucket: "media"})
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "other", "a.txt", "text/plain", []byte("data")))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("storage errors are mapped to status codes", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		s.putErr = ErrQuotaExceeded
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media"})
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "a.txt", "text/plain", []byte("data")))
+		assert.Equal(t, http.StatusInsufficientStorage, rec.Code)
+
+		s.putErr = &StorageError{Code: CodeInvalidKey, Err: ErrInvalidKey}
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "a.txt", "text/plain", []byte("data")))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+// tusClient sends tus requests to a handler.
+type tusClient struct {
+	t *testing.T
+	h http.Handler
+}
+
+func (c tusClient) do(method, target string, headers map[string]string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	req.Header.Set("Tus-Resumable", TusVersion)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	c.h.ServeHTTP(rec, req)
+	return rec
+}
+
+func (c tusClient) create(length int, metadata string) string {
+	rec := c.do(http.MethodPost, "/uploads/", map[string]string{
+		"Upload-Length":   strconv.Itoa(length),
+		"Upload-Metadata": metadata,
+	}, nil)
+	require.Equal(c.t, http.StatusCreated, rec.Code, rec.Body.String())
+	location := rec.Header().Get("Location")
+	require.True(c.t, strings.HasPrefix(location, "/uploads/"), location)
+	return location
+}
+
+func (c tusClient) patch(location string, offset int, chunk []byte) *httptest.ResponseRecorder {
+	return c.do(http.MethodPatch, location, map[string]string{
+		"Content-Type":  "application/offset+octet-stream",
+		"Upload-Offset": strconv.Itoa(offset),
+	}, chunk)
+}
+
+func tusMetadata(pairs ...string) string {
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+" "+base64.StdEncoding.EncodeToString([]byte(pairs[i+1])))
+	}
+	return strings.Join(parts, ",")
+}
+
+// TestUploadHandler_Tus tests resumable uploads.
+func TestUploadHandler_Tus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chunks are uploaded as parts", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		c := tusClient{t: t, h: NewUploadHandler(s, UploadOptions{Bucket: "media"})}
+		data := bytes.Repeat([]byte("abcdefgh"), (MinUploadPartSize*2+1000)/8)
+		location := c.create(len(data), tusMetadata("filename", "movie.mp4", "filetype", "video/mp4"))
+
+		// chunks smaller than a part are buffered
+		chunk := 3 << 20
+		offset := 0
+		var rec *httptest.ResponseRecorder
+		for offset < len(data) {
+			end := min(offset+chunk, len(data))
+			rec = c.patch(location, offset, data[offset:end])
+			offset = end
+			if offset < len(data) {
+				require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+				assert.Equal(t, strconv.Itoa(offset), rec.Header().Get("Upload-Offset"))
+			}
+		}
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		result := decodeUploadResult(t, rec)
+		assert.Equal(t, "movie.mp4", result.Filename)
+		assert.Equal(t, "video/mp4", result.ContentType)
+		assert.True(t, strings.HasSuffix(result.Key, ".mp4"), result.Key)
+		assert.Equal(t, int64(len(data)), result.Size)
+		assert.Equal(t, data, s.object(result.Key))
+
+		// the completed upload no longer accepts chunks
+		rec = c.patch(location, len(data), []byte("x"))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("small upload is stored with put", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		c := tusClient{t: t, h: NewUploadHandler(s, UploadOptions{Bucket: "media"})}
+		location := c.create(11, "")
+
+		rec := c.patch(location, 0, []byte("hello "))
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = c.do(http.MethodHead, location, nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "6", rec.Header().Get("Upload-Offset"))
+		assert.Equal(t, "11", rec.Header().Get("Upload-Length"))
+
+		rec = c.patch(location, 3, []byte("world"))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "6", rec.Header().Get("Upload-Offset"))
+
+		rec = c.patch(location, 6, []byte("world"))
+		require.Equal(t, http.StatusOK, rec.Code)
+		result := decodeUploadResult(t, rec)
+		assert.Equal(t, []byte("hello world"), s.object(result.Key))
+		assert.Equal(t, "text/plain; charset=utf-8", result.ContentType)
+		assert.Empty(t, s.uploads)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		t.Parallel()
+		c := tusClient{t: t, h: NewUploadHandler(newUploadStorage(), UploadOptions{
+			Bucket:              "media",
+			MaxSize:             10,
+			AllowedContentTypes: []string{"image/png"},
+		})}
+
+		rec := c.do(http.MethodPost, "/uploads/", map[string]string{"Upload-Length": "11"}, nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		rec = c.do(http.MethodPost, "/uploads/", map[string]string{
+			"Upload-Length":   "5",
+			"Upload-Metadata": tusMetadata("filetype", "text/html"),
+		}, nil)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+		// chunk longer than the declared length
+		location := c.create(3, tusMetadata("filetype", "image/png"))
+		rec = c.patch(location, 0, []byte("abcd"))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		// sniffed type of an upload without filetype
+		location = c.create(5, "")
+		rec = c.patch(location, 0, []byte("hello"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("terminate aborts multipart upload", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		c := tusClient{t: t, h: NewUploadHandler(s, UploadOptions{Bucket: "media", PartSize: MinUploadPartSize})}
+		data := make([]byte, MinUploadPartSize+1)
+		location := c.create(len(data), "")
+
+		rec := c.patch(location, 0, data[:MinUploadPartSize])
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		require.Len(t, s.uploads, 1)
+
+		rec = c.do(http.MethodDelete, location, nil, nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, []string{"upload-1"}, s.aborted)
+
+		rec = c.do(http.MethodHead, location, nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("expired upload is aborted on any request", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", PartSize: MinUploadPartSize, SessionTTL: time.Hour})
+		c := tusClient{t: t, h: h}
+		data := make([]byte, MinUploadPartSize+1)
+		location := c.create(len(data), "")
+		rec := c.patch(location, 0, data[:MinUploadPartSize])
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		session := h.sessions[path.Base(location)]
+		session.mu.Lock()
+		session.updated = time.Now().Add(-2 * time.Hour)
+		session.mu.Unlock()
+
+		rec = c.do(http.MethodHead, location, nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, []string{"upload-1"}, s.aborted)
+		assert.Empty(t, h.sessions)
+		assert.Zero(t, h.buffered)
+	})
+
+	t.Run("buffer limit", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", PartSize: MinUploadPartSize, MaxBuffer: 2 * MinUploadPartSize})
+		c := tusClient{t: t, h: h}
+
+		// the default MaxSize applies
+		rec := c.do(http.MethodPost, "/uploads/", map[string]string{"Upload-Length": strconv.Itoa(DefaultUploadMaxSize + 1)}, nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		first := c.create(MinUploadPartSize+1, "")
+		c.create(MinUploadPartSize, "")
+		rec = c.do(http.MethodPost, "/uploads/", map[string]string{"Upload-Length": "1"}, nil)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		// empty uploads are completed on creation and buffer nothing
+		c.create(0, "")
+
+		rec = c.do(http.MethodDelete, first, nil, nil)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		location := c.create(3, "")
+		rec = c.patch(location, 0, []byte("abc"))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, int64(MinUploadPartSize), h.buffered, "completed upload releases its buffer")
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+		c := tusClient{t: t, h: NewUploadHandler(newUploadStorage(), UploadOptions{Bucket: "media", MaxSize: 100})}
+
+		rec := c.do(http.MethodOptions, "/uploads/", nil, nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, TusVersion, rec.Header().Get("Tus-Version"))
+		assert.Equal(t, "creation,termination", rec.Header().Get("Tus-Extension"))
+		assert.Equal(t, "100", rec.Header().Get("Tus-Max-Size"))
+	})
+
+	t.Run("part size grows for large uploads", func(t *testing.T) {
+		t.Parallel()
+		h := NewUploadHandler(newUploadStorage(), UploadOptions{Bucket: "media", MaxSize: -1})
+		c := tusClient{t: t, h: h}
+		location := c.create(1<<40, "")
+
+		s, ok := h.sessions[path.Base(location)]
+		require.True(t, ok)
+		assert.Equal(t, int64(105<<20), s.partSize)
+	})
+}
+
+// TestUploadPartSize tests that the part size grows to keep large uploads within MaxUploadParts
+func TestUploadPartSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		size int64
+		want int64
+	}{
+		{name: "unknown size", size: 0, want: DefaultUploadPartSize},
+		{name: "small upload", size: 1 << 20, want: DefaultUploadPartSize},
+		{name: "fits into the part limit", size: MaxUploadParts * DefaultUploadPartSize, want: DefaultUploadPartSize},
+		{name: "one byte over the part limit", size: MaxUploadParts*DefaultUploadPartSize + 1, want: DefaultUploadPartSize + 1<<20},
+		{name: "1 TiB", size: 1 << 40, want: 105 << 20},
+		{name: "capped at the maximum part size", size: 100 << 40, want: MaxUploadPartSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := UploadPartSize(tt.size, DefaultUploadPartSize)
+			assert.Equal(t, tt.want, got)
+			if tt.size > 0 && got < MaxUploadPartSize {
+				assert.LessOrEqual(t, (tt.size+got-1)/got, int64(MaxUploadParts))
+			}
+		})
+	}
+}
+
+// TestUploadHandler_Bandwidth tests that uploads are throttled by the per-upload and shared limiters
+func TestUploadHandler_Bandwidth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("per-upload limit", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		h := NewUploadHandler(s, UploadOptions{Bucket: "media", BandwidthLimit: 256 << 10})
+		data := bytes.Repeat([]byte("z"), 384<<10)
+
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, formRequest(t, "file", "data.bin", "application/x-custom", data))
+
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+		assert.Equal(t, data, s.object(decodeUploadResult(t, rec).Key))
+	})
+
+	t.Run("shared limiter", func(t *testing.T) {
+		t.Parallel()
+		s := newUploadStorage()
+		shared := NewBandwidthLimiter(256 << 10)
+		c := tusClient{t: t, h: NewUploadHandler(s, UploadOptions{Bucket: "media", Bandwidth: shared})}
+		other := NewUploadHandler(s, UploadOptions{Bucket: "media", Bandwidth: shared})
+		data := bytes.Repeat([]byte("z"), 256<<10)
+
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		other.ServeHTTP(rec, formRequest(t, "file", "data.bin", "application/x-custom", data))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		location := c.create(128<<10, "")
+		rec = c.patch(location, 0, data[:128<<10])
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	})
+}
+
+// TestUploadHandler_Location tests that upload URLs keep the prefix stripped by http.StripPrefix.
+func TestUploadHandler_Location(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.Handle("/api/uploads/", http.StripPrefix("/api", NewUploadHandler(newUploadStorage(), UploadOptions{Bucket: "media"})))
+	c := tusClient{t: t, h: mux}
+
+	rec := c.do(http.MethodPost, "/api/uploads/", map[string]string{"Upload-Length": "5"}, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	location := rec.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, "/api/uploads/"), location)
+
+	rec = c.do(http.MethodHead, location, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// TestUploadHandler_allowed tests content type matching.
+func TestUploadHandler_allowed(t *testing.T) {
+	t.Parallel()
+	h := NewUploadHandler(nil, UploadOptions{AllowedContentTypes: []string{"image/*", "application/pdf"}})
+
+	assert.True(t, h.allowed("image/png"))
+	assert.True(t, h.allowed("IMAGE/JPEG"))
+	assert.True(t, h.allowed("application/pdf; charset=binary"))
+	assert.False(t, h.allowed("application/pdfx"))
+	assert.False(t, h.allowed("imagex/png"))
+	assert.False(t, h.allowed(""))
+	assert.True(t, NewUploadHandler(nil, UploadOptions{}).allowed(""))
+}